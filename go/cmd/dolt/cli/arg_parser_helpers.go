@@ -76,44 +76,85 @@ func ParseAuthor(authorStr string) (string, string, error) {
 }
 
 const (
-	AllowEmptyFlag   = "allow-empty"
-	DateParam        = "date"
-	MessageArg       = "message"
-	AuthorParam      = "author"
-	ForceFlag        = "force"
-	DryRunFlag       = "dry-run"
-	SetUpstreamFlag  = "set-upstream"
-	AllFlag          = "all"
-	UpperCaseAllFlag = "ALL"
-	HardResetParam   = "hard"
-	SoftResetParam   = "soft"
-	CheckoutCoBranch = "b"
-	NoFFParam        = "no-ff"
-	SquashParam      = "squash"
-	AbortParam       = "abort"
-	CopyFlag         = "copy"
-	MoveFlag         = "move"
-	DeleteFlag       = "delete"
-	DeleteForceFlag  = "D"
-	OutputOnlyFlag   = "output-only"
-	RemoteParam      = "remote"
-	BranchParam      = "branch"
-	TrackFlag        = "track"
-	AmendFlag        = "amend"
-	CommitFlag       = "commit"
-	NoCommitFlag     = "no-commit"
-	NoEditFlag       = "no-edit"
-	OursFlag         = "ours"
-	TheirsFlag       = "theirs"
-	NumberFlag       = "number"
-	NotFlag          = "not"
-	MergesFlag       = "merges"
-	ParentsFlag      = "parents"
-	MinParentsFlag   = "min-parents"
-	DecorateFlag     = "decorate"
-	OneLineFlag      = "oneline"
+	AllowEmptyFlag     = "allow-empty"
+	DateParam          = "date"
+	MessageArg         = "message"
+	AuthorParam        = "author"
+	ForceFlag          = "force"
+	DryRunFlag         = "dry-run"
+	SetUpstreamFlag    = "set-upstream"
+	AllFlag            = "all"
+	UpperCaseAllFlag   = "ALL"
+	HardResetParam     = "hard"
+	SoftResetParam     = "soft"
+	CheckoutCoBranch   = "b"
+	NoFFParam          = "no-ff"
+	SquashParam        = "squash"
+	AbortParam         = "abort"
+	CopyFlag           = "copy"
+	MoveFlag           = "move"
+	DeleteFlag         = "delete"
+	DeleteForceFlag    = "D"
+	OutputOnlyFlag     = "output-only"
+	RemoteParam        = "remote"
+	BranchParam        = "branch"
+	TrackFlag          = "track"
+	AmendFlag          = "amend"
+	CommitFlag         = "commit"
+	NoCommitFlag       = "no-commit"
+	NoEditFlag         = "no-edit"
+	OursFlag           = "ours"
+	TheirsFlag         = "theirs"
+	NumberFlag         = "number"
+	NotFlag            = "not"
+	MergesFlag         = "merges"
+	ParentsFlag        = "parents"
+	MinParentsFlag     = "min-parents"
+	NoMergesFlag       = "no-merges"
+	MaxParentsFlag     = "max-parents"
+	FetchParam         = "fetch"
+	DecorateFlag       = "decorate"
+	OneLineFlag        = "oneline"
+	StatFlag           = "stat"
+	ReverseFlag        = "reverse"
+	DatabaseParam      = "database"
+	GraphFlag          = "graph"
+	StartOrderParam    = "start-order"
+	EndOrderParam      = "end-order"
+	FormatParam        = "format"
+	ContainsParam      = "contains"
+	BoundaryFlag       = "boundary"
+	MergeBaseFlag      = "merge-base"
+	TrailerParam       = "trailer"
+	NoTrailerParam     = "no-trailer"
+	FirstParentFlag    = "first-parent"
+	ShortHashFlag      = "show-short-hash"
+	AbbrevParam        = "abbrev"
+	RemotesFlag        = "remotes"
+	BranchesFlag       = "branches"
+	SourceLimitParam   = "per-source-limit"
+	WithParam          = "with"
+	CheckPolicyParam   = "check-policy"
+	SinceParam         = "since"
+	UntilParam         = "until"
+	TablesParam        = "tables"
+	IgnoreFlag         = "ignore"
+	ReverifyFlag       = "reverify"
+	StrategyParam      = "strategy"
+	TableStrategyParam = "table-strategy"
+	InteractiveFlag    = "interactive"
+	ContinueFlag       = "continue"
+	MainlineParam      = "mainline"
 )
 
+const (
+	OursStrategy   = "ours"
+	TheirsStrategy = "theirs"
+	UnionStrategy  = "union"
+)
+
+var MergeStrategies = []string{OursStrategy, TheirsStrategy, UnionStrategy}
+
 const (
 	SyncBackupId        = "sync"
 	SyncBackupUrlId     = "sync-url"
@@ -149,6 +190,15 @@ func CreateConflictsResolveArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateConstraintViolationsResolveArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(DeleteFlag, "", "Delete the rows that violate constraints, and their violations.")
+	ap.SupportsFlag(IgnoreFlag, "", "Keep the rows that violate constraints, and clear their violations.")
+	ap.SupportsFlag(ReverifyFlag, "", "Verify the foreign keys of every row of the working set again, replacing the foreign key violations of the tables with those found.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table(s) to resolve constraint violations of, or . for every table with violations. --reverify defaults to every table."})
+	return ap
+}
+
 func CreateMergeArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(NoFFParam, "", "Create a merge commit even when the merge resolves as a fast-forward.")
@@ -163,6 +213,15 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	return ap
 }
 
+// CreateMergeWithStrategiesArgParser returns the arg parser of the dolt_merge procedure, which supports resolving
+// conflicts with merge strategies in addition to the arguments of dolt merge.
+func CreateMergeWithStrategiesArgParser() *argparser.ArgParser {
+	ap := CreateMergeArgParser()
+	ap.SupportsValidatedString(StrategyParam, "", "strategy", "Resolves the conflicts of every table by taking our rows, their rows, or the rows of either side. Valid options are ours, theirs, and union.", argparser.ValidatorFromStrList(StrategyParam, MergeStrategies))
+	ap.SupportsStringList(TableStrategyParam, "", "table=strategy", "Resolves the conflicts of the given table with the given strategy, overriding --strategy. May be given more than once.")
+	return ap
+}
+
 func CreatePushArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} and other commands.")
@@ -222,6 +281,9 @@ func CreateCheckoutArgParser() *argparser.ArgParser {
 
 func CreateCherryPickArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
+	ap.SupportsFlag(NoCommitFlag, "n", "Apply the changes of the cherry-picked commits to the working set and stage them, without creating any commits.")
+	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format. Defaults to the author of each cherry-picked commit.")
+	ap.SupportsFlag(AbortParam, "", "Abort the cherry-pick that stopped on conflicts, restoring the working set to its state before the cherry-pick.")
 	return ap
 }
 
@@ -231,11 +293,23 @@ func CreateFetchArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateRebaseArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(InteractiveFlag, "i", "Start an interactive rebase, which checks out a branch holding the rebase plan in the dolt_rebase_plan table so that it can be edited before the rebase is continued.")
+	ap.SupportsFlag(ContinueFlag, "", "Perform the plan of the interactive rebase in progress, and update the rebased branch.")
+	ap.SupportsFlag(AbortParam, "", "Abort the interactive rebase in progress, leaving the rebased branch as it was.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"upstream",
+		"The commit to rebase the commits of the current branch onto. The commits of the branch that aren't reachable from it are rebased."})
+	return ap
+}
+
 func CreateRevertArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsInt(MainlineParam, "m", "parent-number", "Revert merge commits relative to the given parent, numbered from 1, whose changes are kept.")
+	ap.SupportsFlag(SquashParam, "", "Revert all the commits given in a single commit, rather than in a commit each.")
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision",
-		"The commit revisions. If multiple revisions are given, they're applied in the order given."})
+		"The commit revisions. If multiple revisions are given, they're applied in the order given. A range of the form {{.EmphasisLeft}}A..B{{.EmphasisRight}} reverts the commits reachable from B that aren't reachable from A, newest first."})
 
 	return ap
 }
@@ -268,6 +342,7 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"ref", "A commit ref that the tag should point at."})
 	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the tag message.")
+	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit tagger using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
 	return ap
@@ -286,6 +361,14 @@ func CreateBackupArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateBackupScheduleArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "The name of the backup to sync on a schedule."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"schedule", "A cron expression of the times to sync the backup at, such as '0 2 * * *'."})
+	ap.SupportsFlag(DeleteFlag, "d", "Removes the schedule of the backup, so that it is no longer synced by sql-server.")
+	return ap
+}
+
 func CreateVerifyConstraintsArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(AllFlag, "a", "Verifies that all rows in the database do not violate constraints instead of just rows modified or inserted in the working set.")
@@ -302,7 +385,75 @@ func CreateLogArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(ParentsFlag, "", "Shows all parents of each commit in the log.")
 	ap.SupportsString(DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsFlag(OneLineFlag, "", "Shows logs in a compact format.")
-	ap.SupportsString(NotFlag, "", "revision", "Excludes commits from revision.")
+	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
+	return ap
+}
+
+// CreateLogTableFunctionArgParser returns the arg parser for the dolt_log table function, which supports every option
+// of the log command along with options that are only available from SQL.
+func CreateLogTableFunctionArgParser() *argparser.ArgParser {
+	ap := CreateLogArgParser()
+	ap.SupportsFlag(StatFlag, "", "Shows the number of tables changed, along with the number of rows added, modified, and deleted, for each commit.")
+	ap.SupportsFlag(ReverseFlag, "", "Outputs the commits in reverse order, so that every commit appears after its parents.")
+	ap.SupportsString(DatabaseParam, "", "database", "Reads the log of the given database, which may be revision qualified, rather than the current database.")
+	ap.SupportsFlag(GraphFlag, "", "Shows the position of each commit, the positions of its parents, and the lane it occupies in a drawing of the commit graph.")
+	ap.SupportsInt(StartOrderParam, "", "commit_order", "Only shows commits with a commit_order less than or equal to the given value.")
+	ap.SupportsInt(EndOrderParam, "", "commit_order", "Only shows commits with a commit_order greater than or equal to the given value.")
+	ap.SupportsString(FormatParam, "", "format", "The format of the parents and refs columns. Either text, which joins the values with commas, or json, which returns JSON arrays.")
+	ap.SupportsStringList(ContainsParam, "", "ref", "Adds a column that shows whether each commit is reachable from the given ref. May be given more than once, adding a column for each ref.")
+	ap.SupportsFlag(BoundaryFlag, "", "Adds an is_boundary column that shows whether a commit's parents are missing from the database, such as the oldest commit of a shallow clone.")
+	ap.SupportsFlag(MergeBaseFlag, "", "Appends the merge base of the revisions of a range as the final row. Requires a range such as a..b, a...b, or a revision with --not.")
+	ap.SupportsStringList(TrailerParam, "", "key[=value]", "Only shows commits whose message has a trailer with the given key, such as Signed-off-by, and the given value if one is given. Values containing % are matched as LIKE patterns. May be given more than once, in which case every trailer must be present.")
+	ap.SupportsStringList(NoTrailerParam, "", "key", "Only shows commits whose message has no trailer with the given key. May be given more than once.")
+	ap.SupportsFlag(FirstParentFlag, "", "Follows only the first parent of each commit, which shows the history of the branch that merges were made into. Commits excluded by a range or --not are still excluded along with all of their ancestors.")
+	ap.SupportsFlag(ShortHashFlag, "", "Adds a short_hash column with the shortest prefix of each commit hash, at least 7 characters long, that is unique among the commits in the log.")
+	ap.SupportsAttachedString(AbbrevParam, "", "length", "Abbreviates commit_hash to the given length, 8 by default, extending the prefix of any commit that shares it with another commit in the log, and adds a subject column with the first line of each message. The length may only be given as --abbrev=<length>.")
+	ap.SupportsInt(MaxParentsFlag, "", "parent_count", "The maximum number of parents a commit may have to be included in the log. A value of 0 limits the log to root commits.")
+	ap.SupportsFlag(NoMergesFlag, "", "Equivalent to max-parents == 1, this will limit the log to commits with at most 1 parent.")
+	ap.SupportsString(FetchParam, "", "remote", "Fetches from the given remote before the revisions are resolved, so that remote-tracking revisions such as origin/main include the remote's latest commits.")
+	ap.SupportsFlag(AllFlag, "", "Logs the commits reachable from every branch, remote-tracking branch, and tag, rather than from a revision, and adds a sources column with the refs that reach each commit.")
+	ap.SupportsFlag(BranchesFlag, "", "Logs the commits reachable from every branch, rather than from a revision, and adds a sources column with the branches that reach each commit.")
+	ap.SupportsInt(SourceLimitParam, "", "count", "Limits the log to the given number of commits from each of the refs of --all or --branches. A commit reached by several refs counts against each of them.")
+	ap.SupportsStringList(WithParam, "", "provider", "Adds the column computed by the given column provider, such as message_word_count. May be given more than once, adding a column for each provider.")
+	ap.SupportsString(CheckPolicyParam, "", "mapping_table", "Adds a policy_violation column that shows whether the committer of each commit would be denied write on the logged branch by the current branch control rules. Committers are mapped to accounts by their email through the given table, which has email, user, and host columns. Committers that aren't mapped are NULL.")
+	ap.SupportsString(SinceParam, "", "date", "Only shows commits made at or after the given date, such as 2023-01-01 or 2023-01-01T12:00:00Z. The walk stops once several commits in a row are older than the date.")
+	ap.SupportsString(UntilParam, "", "date", "Only shows commits made at or before the given date, such as 2023-02-01 or 2023-02-01T12:00:00Z.")
+	ap.SupportsString(TablesParam, "", "table_list", "Only shows commits that changed any of the given tables, separated by commas, compared with their first parent. A table is changed when it is created, dropped, or altered, or when any of its rows change.")
+	return ap
+}
+
+// CreateBranchStatusArgParser returns the arg parser for the dolt_branch_status table function.
+func CreateBranchStatusArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(RemotesFlag, "", "Includes remote tracking branches along with local branches.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"base_ref", "The ref that every branch is compared with."})
+	return ap
+}
+
+// CreateApproveMoveArgParser returns the arg parser for the DOLT_APPROVE_MOVE procedure.
+func CreateApproveMoveArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(ForceFlag, "f", "Moves the branch's head even when the proposed commit is not a fast-forward of it.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "The branch whose pending move is approved."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"expected", "The commit that the pending move is expected to move the branch to."})
+	return ap
+}
+
+// The subcommands of the stash command and the DOLT_STASH procedure. Without a subcommand, changes are pushed.
+const (
+	StashPushCmd  = "push"
+	StashPopCmd   = "pop"
+	StashApplyCmd = "apply"
+	StashDropCmd  = "drop"
+	StashClearCmd = "clear"
+	StashListCmd  = "list"
+)
+
+// CreateStashArgParser returns the arg parser for the stash command and the DOLT_STASH procedure.
+func CreateStashArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} to describe the stash.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"stash", "A stash, named stash@{n} or n, where n is the position of the stash in the list of stashes. Defaults to the most recent stash."})
 	return ap
 }
 
@@ -0,0 +1,52 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branchctrlcmds
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var Commands = cli.NewSubCommandHandler("branch-control", "Commands for managing branch control permissions.", []cli.Command{
+	ExportCmd{},
+	ImportCmd{},
+})
+
+// addFileFlags adds the flags that locate the branch control file, which match those of the sql and sql-server
+// commands.
+func addFileFlags(ap *argparser.ArgParser) {
+	ap.SupportsString(commands.CfgDirFlag, "", "directory", "Defines a directory that contains configuration files for dolt. Defaults to `.doltcfg`.")
+	ap.SupportsString(commands.BranchCtrlPathFlag, "", "branch control file", "Path to the file that branch control permissions are loaded from and stored in. Defaults to `$doltcfg-dir/branch_control.db`.")
+}
+
+// loadController loads the branch control file given by the parsed flags. A missing file is treated as empty.
+func loadController(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*branch_control.Controller, error) {
+	cfgDirPath := apr.GetValueOrDefault(commands.CfgDirFlag, commands.DefaultCfgDirName)
+	branchControlFilePath, ok := apr.GetValue(commands.BranchCtrlPathFlag)
+	if !ok {
+		branchControlFilePath = filepath.Join(cfgDirPath, commands.DefaultBranchCtrlName)
+	}
+	branchControlFilePath, err := dEnv.FS.Abs(branchControlFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return branch_control.LoadFile(ctx, branchControlFilePath, cfgDirPath)
+}
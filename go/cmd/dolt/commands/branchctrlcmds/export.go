@@ -0,0 +1,96 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branchctrlcmds
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var exportDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Exports branch control permissions as JSON",
+	LongDesc: `Writes every entry of the {{.EmphasisLeft}}dolt_branch_control{{.EmphasisRight}} and {{.EmphasisLeft}}dolt_branch_namespace_control{{.EmphasisRight}} tables, along with the members of every role, to the given file as JSON. If no file is given, the JSON is written to stdout.
+
+The output is the same as that of {{.EmphasisLeft}}CALL dolt_branch_control_export(){{.EmphasisRight}}, and may be loaded with {{.EmphasisLeft}}dolt branch-control import{{.EmphasisRight}}. The branch control file is read directly, so this should not be run while a server is using the file.`,
+	Synopsis: []string{
+		"[{{.LessThan}}file{{.GreaterThan}}]",
+	},
+}
+
+type ExportCmd struct{}
+
+// Name implements cli.Command.
+func (cmd ExportCmd) Name() string {
+	return "export"
+}
+
+// Description implements cli.Command.
+func (cmd ExportCmd) Description() string {
+	return exportDocs.ShortDesc
+}
+
+// RequiresRepo implements cli.Command.
+func (cmd ExportCmd) RequiresRepo() bool {
+	return false
+}
+
+// Docs implements cli.Command.
+func (cmd ExportCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(exportDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd ExportCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "File to write the permissions to."})
+	addFileFlags(ap)
+	return ap
+}
+
+// Exec implements cli.Command.
+func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, exportDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() > 1 {
+		verr := errhand.BuildDError("dolt branch-control export takes at most one argument").Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	controller, err := loadController(ctx, dEnv, apr)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to load branch control file").AddCause(err).Build(), usage)
+	}
+	data, err := controller.Export(ctx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	if apr.NArg() == 0 {
+		cli.Println(string(data))
+		return 0
+	}
+	if err = dEnv.FS.WriteFile(apr.Arg(0), data); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to write %s", apr.Arg(0)).AddCause(err).Build(), usage)
+	}
+	return 0
+}
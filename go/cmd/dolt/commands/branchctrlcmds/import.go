@@ -0,0 +1,100 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branchctrlcmds
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const modeFlag = "mode"
+
+var importDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Imports branch control permissions from JSON",
+	LongDesc: `Loads the permissions in the given JSON file, as written by {{.EmphasisLeft}}dolt branch-control export{{.EmphasisRight}} or {{.EmphasisLeft}}CALL dolt_branch_control_export(){{.EmphasisRight}}, into the branch control file.
+
+With {{.EmphasisLeft}}--mode merge{{.EmphasisRight}}, the default, existing entries are kept, and those that share their expressions with an imported entry are overwritten. With {{.EmphasisLeft}}--mode replace{{.EmphasisRight}}, every existing entry is removed before importing. The branch control file is written directly, so this should not be run while a server is using the file.`,
+	Synopsis: []string{
+		"[--mode {{.LessThan}}replace|merge{{.GreaterThan}}] {{.LessThan}}file{{.GreaterThan}}",
+	},
+}
+
+type ImportCmd struct{}
+
+// Name implements cli.Command.
+func (cmd ImportCmd) Name() string {
+	return "import"
+}
+
+// Description implements cli.Command.
+func (cmd ImportCmd) Description() string {
+	return importDocs.ShortDesc
+}
+
+// RequiresRepo implements cli.Command.
+func (cmd ImportCmd) RequiresRepo() bool {
+	return false
+}
+
+// Docs implements cli.Command.
+func (cmd ImportCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(importDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd ImportCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "File to read the permissions from."})
+	ap.SupportsString(modeFlag, "", "mode", "Either `replace` or `merge`. Defaults to `merge`.")
+	addFileFlags(ap)
+	return ap
+}
+
+// Exec implements cli.Command.
+func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, importDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		verr := errhand.BuildDError("dolt branch-control import takes exactly one argument").Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+	mode := branch_control.ImportMode(strings.ToLower(apr.GetValueOrDefault(modeFlag, string(branch_control.ImportMode_Merge))))
+
+	data, err := dEnv.FS.ReadFile(apr.Arg(0))
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to read %s", apr.Arg(0)).AddCause(err).Build(), usage)
+	}
+	controller, err := loadController(ctx, dEnv, apr)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to load branch control file").AddCause(err).Build(), usage)
+	}
+	if err = controller.Import(ctx, data, mode); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if err = controller.SaveFile(); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to write branch control file").AddCause(err).Build(), usage)
+	}
+	return 0
+}
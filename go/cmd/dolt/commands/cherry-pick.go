@@ -38,9 +38,13 @@ Applies the changes from an existing commit and creates a new commit from the cu
 Cherry-picking merge commits or commits with schema changes or rename or drop tables is not currently supported. Row data changes are allowed as long as the two table schemas are exactly identical.
 
 If applying the row data changes from the cherry-picked commit results in a data conflict, the cherry-pick operation is aborted and no changes are made to the working tree or committed.
+
+With {{.EmphasisLeft}}--no-commit{{.EmphasisRight}}, the changes are applied to the working set and staged, but not committed.
+
+The {{.EmphasisLeft}}dolt_cherry_pick(){{.EmphasisRight}} stored procedure also accepts commit ranges ({{.EmphasisLeft}}A..B{{.EmphasisRight}}), and leaves conflicts in the dolt_conflicts tables to be resolved instead of aborting.
 `,
 	Synopsis: []string{
-		`{{.LessThan}}commit{{.GreaterThan}}`,
+		`[-n] [--author {{.LessThan}}author{{.GreaterThan}}] {{.LessThan}}commit{{.GreaterThan}}`,
 	},
 }
 
@@ -102,12 +106,13 @@ func (cmd CherryPickCmd) Exec(ctx context.Context, commandStr string, args []str
 		authorStr = as
 	}
 
-	verr := cherryPick(ctx, dEnv, cherryStr, authorStr)
+	verr := cherryPick(ctx, dEnv, cherryStr, authorStr, apr.Contains(cli.NoCommitFlag))
 	return HandleVErrAndExitCode(verr, usage)
 }
 
-// cherryPick returns error if any step of cherry-picking fails. It receives cherry-picked commit and performs cherry-picking and commits.
-func cherryPick(ctx context.Context, dEnv *env.DoltEnv, cherryStr, authorStr string) errhand.VerboseError {
+// cherryPick returns error if any step of cherry-picking fails. It receives cherry-picked commit and performs cherry-picking
+// and commits, unless |noCommit| is true, in which case the changes are left staged.
+func cherryPick(ctx context.Context, dEnv *env.DoltEnv, cherryStr, authorStr string, noCommit bool) errhand.VerboseError {
 	// check for clean working state
 	headRoot, err := dEnv.HeadRoot(ctx)
 	if err != nil {
@@ -165,6 +170,9 @@ func cherryPick(ctx context.Context, dEnv *env.DoltEnv, cherryStr, authorStr str
 	if res != 0 {
 		return errhand.BuildDError("dolt add failed").AddCause(err).Build()
 	}
+	if noCommit {
+		return nil
+	}
 
 	// Pass in the final parameters for the author string.
 	commitParams := []string{"-m", commitMsg}
@@ -198,36 +206,14 @@ func getCherryPickedRootValue(ctx context.Context, dEnv *env.DoltEnv, workingRoo
 		return nil, "", err
 	}
 
-	if len(cherryCm.DatasParents()) > 1 {
-		return nil, "", errhand.BuildDError("cherry-picking a merge commit is not supported.").Build()
-	}
-	if len(cherryCm.DatasParents()) == 0 {
-		return nil, "", errhand.BuildDError("cherry-picking a commit without parents is not supported.").Build()
-	}
-
 	cherryCM, err := cherryCm.GetCommitMeta(ctx)
 	if err != nil {
 		return nil, "", err
 	}
 	commitMsg := cherryCM.Description
 
-	cherryRoot, err := cherryCm.GetRootValue(ctx)
-	if err != nil {
-		return nil, "", err
-	}
-
-	parentCm, err := dEnv.DoltDB.ResolveParent(ctx, cherryCm, 0)
-	if err != nil {
-		return nil, "", err
-	}
-	parentRoot, err := parentCm.GetRootValue(ctx)
-	if err != nil {
-		return nil, "", err
-	}
-
 	// use parent of cherry-pick as ancestor to merge
-	mo := merge.MergeOpts{IsCherryPick: true}
-	mergedRoot, mergeStats, err := merge.MergeRoots(ctx, workingRoot, cherryRoot, parentRoot, cherryCm, parentCm, opts, mo)
+	mergedRoot, mergeStats, err := merge.CherryPick(ctx, dEnv.DoltDB, workingRoot, cherryCm, opts)
 	if err != nil {
 		return nil, "", err
 	}
@@ -188,7 +188,12 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 
 	var mergeParentCommits []*doltdb.Commit
 	if ws.MergeActive() {
-		mergeParentCommits = []*doltdb.Commit{ws.MergeState().Commit()}
+		if ws.MergeState().HasSchemaConflicts() {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: cannot commit a merge with unresolved schema conflicts").AddDetails("Resolve them with dolt_conflicts_resolve, or abort the merge with dolt merge --abort.").Build(), usage)
+		}
+		if !ws.MergeState().IsCherryPick() {
+			mergeParentCommits = []*doltdb.Commit{ws.MergeState().Commit()}
+		}
 	} else if apr.Contains(cli.AmendFlag) && len(parentsHeadForAmend) > 1 {
 		mergeParentCommits = parentsHeadForAmend
 	}
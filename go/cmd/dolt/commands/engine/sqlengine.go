@@ -56,6 +56,7 @@ type SqlEngineConfig struct {
 	DoltCfgDirPath     string
 	PrivFilePath       string
 	BranchCtrlFilePath string
+	BranchCtrlBase     string
 	ServerUser         string
 	ServerPass         string
 	ServerHost         string
@@ -126,12 +127,12 @@ func NewSqlEngine(
 	}
 
 	// Load the branch control permissions, if they exist
-	if err = branch_control.LoadData(sql.NewEmptyContext(), config.BranchCtrlFilePath, config.DoltCfgDirPath); err != nil {
+	if err = branch_control.LoadData(sql.NewEmptyContext(), config.BranchCtrlFilePath, config.BranchCtrlBase, config.DoltCfgDirPath); err != nil {
 		return nil, err
 	}
 
 	// Set up engine
-	engine := gms.New(analyzer.NewBuilder(pro).WithParallelism(parallelism).Build(), &gms.Config{
+	engine := gms.New(dsqle.AddAnalyzerRules(analyzer.NewBuilder(pro).WithParallelism(parallelism)).Build(), &gms.Config{
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
@@ -260,7 +260,7 @@ func rebaseSqlEngine(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commit)
 	}
 
 	parallelism := runtime.GOMAXPROCS(0)
-	azr := analyzer.NewBuilder(pro).WithParallelism(parallelism).Build()
+	azr := dsqle.AddAnalyzerRules(analyzer.NewBuilder(pro).WithParallelism(parallelism)).Build()
 
 	head := dEnv.RepoStateReader().CWBHeadSpec()
 	headCommit, err := dEnv.DoltDB.Resolve(ctx, head, dEnv.RepoStateReader().CWBHeadRef())
@@ -0,0 +1,121 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const danglingFlag = "dangling"
+
+var fsckDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Find commits that are no longer reachable`,
+	LongDesc: `Checks the commits of the database. With {{.EmphasisLeft}}--dangling{{.EmphasisRight}}, every commit that cannot be reached from any branch, tag, remote-tracking branch, or stash is shown, most recent first, with its author, date, and age. Such commits are left behind by resets, forced branch updates, and deleted branches.
+
+Dangling commits are removed by {{.EmphasisLeft}}dolt gc{{.EmphasisRight}} once their moves have expired from the reflog. Until then, the work in a dangling commit can be recovered by creating a branch at it with {{.EmphasisLeft}}dolt branch <name> <commit>{{.EmphasisRight}}.
+
+Finding dangling commits reads every chunk of the database, so it can take some time for large databases. The dangling commits are also shown by the {{.EmphasisLeft}}dolt_dangling_commits{{.EmphasisRight}} system table.`,
+	Synopsis: []string{
+		`--dangling`,
+	},
+}
+
+type FsckCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd FsckCmd) Name() string {
+	return "fsck"
+}
+
+// Description returns a description of the command
+func (cmd FsckCmd) Description() string {
+	return fsckDocs.ShortDesc
+}
+
+func (cmd FsckCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(fsckDocs, ap)
+}
+
+func (cmd FsckCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(danglingFlag, "", "Show the commits that cannot be reached from any ref.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd FsckCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd FsckCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, fsckDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() > 0 || !apr.Contains(danglingFlag) {
+		usage()
+		return 1
+	}
+
+	dangling, err := dEnv.DoltDB.DanglingCommits(ctx)
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to find dangling commits").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	now := time.Now()
+	for _, dc := range dangling {
+		cli.Println(color.YellowString("dangling commit %s", dc.Hash.String()))
+		cli.Printf("Author: %s <%s>\n", dc.Meta.Name, dc.Meta.Email)
+		cli.Printf("Date:   %s (%s)\n", dc.Meta.FormatTS(), formatAge(now.Sub(dc.Meta.Time())))
+		cli.Println("\n\t" + strings.Replace(dc.Meta.Description, "\n", "\n\t", -1) + "\n")
+	}
+
+	return 0
+}
+
+// formatAge formats the given age in the largest whole unit, such as "3 days ago".
+func formatAge(age time.Duration) string {
+	units := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if n := int64(age / unit.d); n > 0 {
+			if n == 1 {
+				return fmt.Sprintf("1 %s ago", unit.name)
+			}
+			return fmt.Sprintf("%d %ss ago", n, unit.name)
+		}
+	}
+	return "less than a minute ago"
+}
@@ -162,6 +162,9 @@ func parseLogArgs(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 
 	excludingRef, ok := apr.GetValue(cli.NotFlag)
 	if ok {
+		if len(apr.GetValueList(cli.NotFlag)) > 1 {
+			return nil, fmt.Errorf("error: multiple values provided for `%s'", cli.NotFlag)
+		}
 		if opts.excludingCommitSpec != nil {
 			return nil, fmt.Errorf("cannot use --not argument with two dots or ref with ^")
 		}
@@ -0,0 +1,121 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+var reflogDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Show the history of named refs`,
+	LongDesc: `Shows every recorded move of the named ref, most recent first. Refs are moved by commits, merges, resets, and by creating, force updating, or deleting branches and tags. Moves of refs which have since been deleted are still shown, so the reflog can be used to find commits which are no longer reachable from any branch.
+
+The ref may be the full name of a ref, such as {{.EmphasisLeft}}refs/heads/main{{.EmphasisRight}}, or the name of a branch, tag, or remote-tracking branch. When no ref is given, the moves of every ref are shown.
+
+Only moves of refs are recorded. Checking out an existing branch does not move a ref, and so is not shown. Moves made before the reflog was introduced, or on another clone, are not shown.
+
+Commits in the reflog are kept by {{.EmphasisLeft}}dolt gc{{.EmphasisRight}}, which expires moves older than 90 days. At most the 10,000 most recent moves are kept.`,
+	Synopsis: []string{
+		`[{{.LessThan}}ref{{.GreaterThan}}]`,
+	},
+}
+
+type ReflogCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ReflogCmd) Name() string {
+	return "reflog"
+}
+
+// Description returns a description of the command
+func (cmd ReflogCmd) Description() string {
+	return reflogDocs.ShortDesc
+}
+
+func (cmd ReflogCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(reflogDocs, ap)
+}
+
+func (cmd ReflogCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"ref", "The ref to show the history of. Defaults to every ref."})
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd ReflogCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd ReflogCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, reflogDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() > 1 {
+		verr := errhand.BuildDError("%s takes at most 1 arg", cmd.Name()).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	var name string
+	if apr.NArg() == 1 {
+		name = apr.Arg(0)
+	}
+
+	entries, err := dEnv.DoltDB.Reflog(ctx, name)
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to read the reflog").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	for _, entry := range entries {
+		if entry.Hash.IsEmpty() {
+			cli.Println(fmt.Sprintf("%s (%s) deleted", color.YellowString("%-32s", "-"), entry.Ref))
+			continue
+		}
+
+		var message string
+		commit, err := dEnv.DoltDB.ReadCommit(ctx, entry.Hash)
+		if err != nil && !errors.Is(err, datas.ErrCommitNotFound) {
+			verr := errhand.BuildDError("error: failed to read commit %s", entry.Hash.String()).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		if commit != nil {
+			meta, err := commit.GetCommitMeta(ctx)
+			if err != nil {
+				verr := errhand.BuildDError("error: failed to read commit %s", entry.Hash.String()).AddCause(err).Build()
+				return HandleVErrAndExitCode(verr, usage)
+			}
+			message = strings.SplitN(meta.Description, "\n", 2)[0]
+		}
+		cli.Println(fmt.Sprintf("%s (%s) %s", color.YellowString(entry.Hash.String()), entry.Ref, message))
+	}
+
+	return 0
+}
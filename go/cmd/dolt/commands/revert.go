@@ -33,10 +33,14 @@ var revertDocs = cli.CommandDocumentationContent{
 		"(e.g. {{.EmphasisLeft}}HEAD~1{{.EmphasisRight}}), this is similar to applying the patch from " +
 		"{{.EmphasisLeft}}HEAD~1..HEAD~2{{.EmphasisRight}}, giving us a patch of what to remove to effectively remove the " +
 		"influence of the specified commit. If multiple commits are specified, then this process is repeated for each " +
-		"commit in the order specified. This requires a clean working set." +
+		"commit in the order specified, committing the revert of each commit separately unless " +
+		"{{.EmphasisLeft}}--squash{{.EmphasisRight}} is given. A range {{.EmphasisLeft}}A..B{{.EmphasisRight}} reverts " +
+		"the commits reachable from B that aren't reachable from A, newest first. This requires a clean working set." +
+		"\n\nMerge commits are reverted relative to one of their parents, given by its number with " +
+		"{{.EmphasisLeft}}-m{{.EmphasisRight}}, so that the changes the merge brought in from its other parents are removed." +
 		"\n\nAny conflicts or constraint violations caused by the merge cause the command to fail.",
 	Synopsis: []string{
-		"<revision>...",
+		"[--squash] [-m {{.LessThan}}parent-number{{.GreaterThan}}] <revision>...",
 	},
 }
 
@@ -109,29 +113,64 @@ func (cmd RevertCmd) Exec(ctx context.Context, commandStr string, args []string,
 	}
 
 	headRef := dEnv.RepoState.CWBHeadRef()
-	commits := make([]*doltdb.Commit, apr.NArg())
-	for i, arg := range apr.Args {
-		commitSpec, err := doltdb.NewCommitSpec(arg)
-		if err != nil {
-			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
-		}
-		commit, err := dEnv.DoltDB.Resolve(ctx, commitSpec, headRef)
-		if err != nil {
-			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
-		}
-		commits[i] = commit
+	commits, err := merge.ResolveRevertCommits(ctx, dEnv.DoltDB, headRef, apr.Args)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	mainline := apr.GetIntOrDefault(cli.MainlineParam, 0)
+	if err = merge.CheckRevertCommits(commits, mainline); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 	tmpDir, err := dEnv.TempTableFilesDir()
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
-	workingRoot, revertMessage, err := merge.Revert(ctx, dEnv.DoltDB, workingRoot, headCommit, commits, opts)
+
+	// Pass in the final parameters for the author string.
+	var commitParams []string
+	authorStr, ok := apr.GetValue(cli.AuthorParam)
+	if ok {
+		commitParams = append(commitParams, "--author", authorStr)
+	}
+
+	if apr.Contains(cli.SquashParam) {
+		return revertAndCommit(ctx, dEnv, commits, mainline, opts, commitParams, usage)
+	}
+	for _, commit := range commits {
+		if res := revertAndCommit(ctx, dEnv, []*doltdb.Commit{commit}, mainline, opts, commitParams, usage); res != 0 {
+			return res
+		}
+	}
+	return 0
+}
+
+// revertAndCommit reverts |commits| in the working set, and commits the result with a message naming the commits
+// reverted. Nothing is committed when reverting the commits changes nothing.
+func revertAndCommit(ctx context.Context, dEnv *env.DoltEnv, commits []*doltdb.Commit, mainline int, opts editor.Options, commitParams []string, usage cli.UsagePrinter) int {
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	headHash, err := headRoot.HashOf()
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	workingRoot, err := dEnv.WorkingRoot(ctx)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
-	workingHash, err = workingRoot.HashOf()
+	workingRoot, revertMessage, err := merge.Revert(ctx, dEnv.DoltDB, workingRoot, headCommit, commits, mainline, opts)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	workingHash, err := workingRoot.HashOf()
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
@@ -149,12 +188,5 @@ func (cmd RevertCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return res
 	}
 
-	// Pass in the final parameters for the author string.
-	commitParams := []string{"-m", revertMessage}
-	authorStr, ok := apr.GetValue(cli.AuthorParam)
-	if ok {
-		commitParams = append(commitParams, "--author", authorStr)
-	}
-
-	return CommitCmd{}.Exec(ctx, "commit", commitParams, dEnv)
+	return CommitCmd{}.Exec(ctx, "commit", append([]string{"-m", revertMessage}, commitParams...), dEnv)
 }
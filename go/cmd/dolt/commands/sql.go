@@ -44,6 +44,7 @@ import (
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
@@ -67,7 +68,7 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 Multiple SQL statements must be separated by semicolons. Use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode to speed up large batches of INSERT / UPDATE statements. Pipe SQL files to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
-Queries can be saved to the query catalog with {{.EmphasisLeft}}-s{{.EmphasisRight}}. Alternatively {{.EmphasisLeft}}-x{{.EmphasisRight}} can be used to execute a saved query by name.
+Queries can be saved to the query catalog with {{.EmphasisLeft}}-s{{.EmphasisRight}}. Alternatively {{.EmphasisLeft}}-x{{.EmphasisRight}} can be used to execute a saved query by name. Saved queries are versioned with the rest of the database, so each branch has its own. Use {{.EmphasisLeft}}--branch{{.EmphasisRight}} with {{.EmphasisLeft}}-x{{.EmphasisRight}} or {{.EmphasisLeft}}--list-saved{{.EmphasisRight}} to use the saved queries of another branch. In SQL, saved queries are executed with {{.EmphasisLeft}}CALL dolt_exec_saved('name'){{.EmphasisRight}}.
 
 By default this command uses the dolt database in the current working directory, as well as any dolt databases that are found in the current directory. Any databases created with CREATE DATABASE are placed in the current directory as well. Running with {{.EmphasisLeft}}--data-dir <directory>{{.EmphasisRight}} uses each of the subdirectories of the supplied directory (each subdirectory must be a valid dolt data repository) as databases. Subdirectories starting with '.' are ignored.`,
 
@@ -77,8 +78,8 @@ By default this command uses the dolt database in the current working directory,
 		"[--data-dir {{.LessThan}}directory{{.GreaterThan}}] [-r {{.LessThan}}result format{{.GreaterThan}}]",
 		"-q {{.LessThan}}query{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-s {{.LessThan}}name{{.GreaterThan}} -m {{.LessThan}}message{{.GreaterThan}}] [-b]",
 		"-q {{.LessThan}}query{{.GreaterThan}} --data-dir {{.LessThan}}directory{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-b]",
-		"-x {{.LessThan}}name{{.GreaterThan}}",
-		"--list-saved",
+		"-x {{.LessThan}}name{{.GreaterThan}} [--branch {{.LessThan}}branch{{.GreaterThan}}]",
+		"--list-saved [--branch {{.LessThan}}branch{{.GreaterThan}}]",
 	},
 }
 
@@ -90,6 +91,7 @@ const (
 	saveFlag              = "save"
 	executeFlag           = "execute"
 	listSavedFlag         = "list-saved"
+	branchFlag            = "branch"
 	messageFlag           = "message"
 	BatchFlag             = "batch"
 	DataDirFlag           = "data-dir"
@@ -98,6 +100,7 @@ const (
 	DefaultCfgDirName     = ".doltcfg"
 	PrivsFilePathFlag     = "privilege-file"
 	BranchCtrlPathFlag    = "branch-control-file"
+	BranchCtrlBaseFlag    = "branch-control-base"
 	DefaultPrivsName      = "privileges.db"
 	DefaultBranchCtrlName = "branch_control.db"
 	continueFlag          = "continue"
@@ -151,6 +154,7 @@ func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsString(saveFlag, "s", "saved query name", "Used with --query, save the query to the query catalog with the name provided. Saved queries can be examined in the dolt_query_catalog system table.")
 	ap.SupportsString(executeFlag, "x", "saved query name", "Executes a saved query with the given name.")
 	ap.SupportsFlag(listSavedFlag, "l", "List all saved queries.")
+	ap.SupportsString(branchFlag, "", "branch", "Used with --execute or --list-saved, uses the saved queries of the branch given, and executes them on that branch.")
 	ap.SupportsString(messageFlag, "m", "saved query description", "Used with --query and --save, saves the query with the descriptive message given. See also `--name`.")
 	ap.SupportsFlag(BatchFlag, "b", "Use to enable more efficient batch processing for large SQL import scripts consisting of only INSERT statements. Other statements types are not guaranteed to work in this mode.")
 	ap.SupportsString(DataDirFlag, "", "directory", "Defines a directory whose subdirectories should all be dolt data repositories accessible as independent databases within. Defaults to the current directory.")
@@ -160,6 +164,7 @@ func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsString(fileInputFlag, "f", "input file", "Execute statements from the file given.")
 	ap.SupportsString(PrivsFilePathFlag, "", "privilege file", "Path to a file to load and store users and grants. Defaults to `$doltcfg-dir/privileges.db`. Will only be created if there is a change to privileges.")
 	ap.SupportsString(BranchCtrlPathFlag, "", "branch control file", "Path to a file to load and store branch control permissions. Defaults to `$doltcfg-dir/branch_control.db`. Will only be created if there is a change to branch control permissions.")
+	ap.SupportsString(BranchCtrlBaseFlag, "", "branch control base", "Location of read-only branch control permissions that are loaded at startup, beneath those of the branch control file. May be a path, or a `file`, `http`, `https`, `gs`, or `localbs` URL. Defaults to `$DOLT_BRANCH_CONTROL_BASE`.")
 	ap.SupportsString(UserFlag, "u", "user", fmt.Sprintf("Defines the local superuser (defaults to `%v`). If the specified user exists, will take on permissions of that user.", DefaultUser))
 	return ap
 }
@@ -280,6 +285,16 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 		return true, nil
 	})
 
+	// Saved queries of another branch are read from that branch, and executed on its revision database
+	if branch, ok := apr.GetValue(branchFlag); ok {
+		root, verr := branchWorkingRoot(ctx, mrEnv.GetEnv(currentDb), branch)
+		if verr != nil {
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		currentDb = currentDb + "/" + branch
+		initialRoots[currentDb] = root
+	}
+
 	format := engine.FormatTabular
 	if formatSr, ok := apr.GetValue(FormatFlag); ok {
 		var verr errhand.VerboseError
@@ -295,6 +310,7 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 		DoltCfgDirPath:     cfgDirPath,
 		PrivFilePath:       privsFp,
 		BranchCtrlFilePath: branchControlFilePath,
+		BranchCtrlBase:     apr.GetValueOrDefault(BranchCtrlBaseFlag, ""),
 		ServerUser:         username,
 		ServerHost:         DefaultHost,
 		Autocommit:         true,
@@ -379,7 +395,7 @@ func listSavedQueriesMode(
 		return 0
 	}
 
-	query := "SELECT * FROM " + doltdb.DoltQueryCatalogTableName
+	query := "SELECT * FROM " + doltdb.DoltQueryCatalogTableName + " ORDER BY " + doltdb.QueryCatalogOrderCol
 	return HandleVErrAndExitCode(execQuery(ctx, mrEnv, query, format, config), usage)
 }
 
@@ -719,6 +735,7 @@ func validateSqlArgs(apr *argparser.ArgParseResults) error {
 	_, execute := apr.GetValue(executeFlag)
 	_, dataDir := apr.GetValue(DataDirFlag)
 	_, multiDbDir := apr.GetValue(MultiDBDirFlag)
+	_, branch := apr.GetValue(branchFlag)
 
 	if len(apr.Args) > 0 && !query {
 		return errhand.BuildDError("Invalid Argument: use --query or -q to pass inline SQL queries").Build()
@@ -756,6 +773,10 @@ func validateSqlArgs(apr *argparser.ArgParseResults) error {
 		}
 	}
 
+	if branch && !execute && !list {
+		return errhand.BuildDError("Invalid Argument: --branch is only used with --execute|-x or --list-saved").Build()
+	}
+
 	if save && (dataDir || multiDbDir) {
 		return errhand.BuildDError("Invalid Argument: --data-dir queries cannot be saved").Build()
 	}
@@ -796,6 +817,37 @@ func saveQuery(ctx context.Context, root *doltdb.RootValue, query string, name s
 	return newRoot, nil
 }
 
+// branchWorkingRoot returns the working root of the branch given, which is the root of its head when it has no working
+// set.
+func branchWorkingRoot(ctx context.Context, dEnv *env.DoltEnv, branch string) (*doltdb.RootValue, errhand.VerboseError) {
+	branchRef := ref.NewBranchRef(branch)
+	if ok, err := dEnv.DoltDB.HasRef(ctx, branchRef); err != nil {
+		return nil, errhand.VerboseErrorFromError(err)
+	} else if !ok {
+		return nil, errhand.BuildDError("error: unknown branch '%s'", branch).Build()
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(branchRef)
+	if err != nil {
+		return nil, errhand.VerboseErrorFromError(err)
+	}
+	ws, err := dEnv.DoltDB.ResolveWorkingSet(ctx, wsRef)
+	if err == doltdb.ErrWorkingSetNotFound {
+		cm, err := dEnv.DoltDB.ResolveCommitRef(ctx, branchRef)
+		if err != nil {
+			return nil, errhand.VerboseErrorFromError(err)
+		}
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, errhand.VerboseErrorFromError(err)
+		}
+		return root, nil
+	} else if err != nil {
+		return nil, errhand.VerboseErrorFromError(err)
+	}
+	return ws.WorkingRoot(), nil
+}
+
 // runMultiStatementMode allows for the execution of more than one query, but it doesn't attempt any batch optimizations
 func runMultiStatementMode(ctx *sql.Context, se *engine.SqlEngine, input io.Reader, continueOnErr bool) error {
 	scanner := NewSqlStatementScanner(input)
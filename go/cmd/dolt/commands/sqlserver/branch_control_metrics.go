@@ -0,0 +1,85 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+var _ prometheus.Collector = (*branchControlCollector)(nil)
+
+// branchControlCollector exports the metrics of branch control. Branch control keeps its own counts, so they're read
+// whenever the metrics are collected rather than being updated by the server. Checks and denials are only labeled by
+// their operation, which is a fixed set, so that the number of series does not grow with the users or branches.
+type branchControlCollector struct {
+	metrics func() branch_control.Metrics
+
+	descChecks       *prometheus.Desc
+	descDenials      *prometheus.Desc
+	descRules        *prometheus.Desc
+	descSaveDuration *prometheus.Desc
+	descSaveFailures *prometheus.Desc
+	descMatchLatency *prometheus.Desc
+}
+
+func newBranchControlCollector(labels prometheus.Labels) *branchControlCollector {
+	return &branchControlCollector{
+		metrics: branch_control.GetMetrics,
+		descChecks: prometheus.NewDesc("dss_branch_control_checks",
+			"Count of branch control permission checks by operation", []string{"operation"}, labels),
+		descDenials: prometheus.NewDesc("dss_branch_control_denials",
+			"Count of branch control denials by operation, including those that were only audited", []string{"operation"}, labels),
+		descRules: prometheus.NewDesc("dss_branch_control_rules",
+			"Number of entries in each branch control table, excluding those of the base", []string{"table"}, labels),
+		descSaveDuration: prometheus.NewDesc("dss_branch_control_save_duration",
+			"Histogram of the time taken to save branch control data", nil, labels),
+		descSaveFailures: prometheus.NewDesc("dss_branch_control_save_failures",
+			"Count of failed saves of branch control data", nil, labels),
+		descMatchLatency: prometheus.NewDesc("dss_branch_control_match_duration",
+			"Histogram of the time taken to match branch control entries during a check", nil, labels),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *branchControlCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.descChecks
+	ch <- c.descDenials
+	ch <- c.descRules
+	ch <- c.descSaveDuration
+	ch <- c.descSaveFailures
+	ch <- c.descMatchLatency
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *branchControlCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := c.metrics()
+	for _, action := range branch_control.MetricActions {
+		ch <- prometheus.MustNewConstMetric(c.descChecks, prometheus.CounterValue, float64(metrics.Checks[action]), action)
+		ch <- prometheus.MustNewConstMetric(c.descDenials, prometheus.CounterValue, float64(metrics.Denials[action]), action)
+	}
+	ch <- prometheus.MustNewConstMetric(c.descRules, prometheus.GaugeValue, float64(metrics.AccessRules), "access")
+	ch <- prometheus.MustNewConstMetric(c.descRules, prometheus.GaugeValue, float64(metrics.NamespaceRules), "namespace")
+	ch <- prometheus.MustNewConstMetric(c.descSaveFailures, prometheus.CounterValue, float64(metrics.SaveDataFailures))
+	ch <- constHistogram(c.descSaveDuration, metrics.SaveDataDuration)
+	ch <- constHistogram(c.descMatchLatency, metrics.MatchLatency)
+}
+
+// constHistogram returns the given snapshot as a metric of the given description.
+func constHistogram(desc *prometheus.Desc, snapshot histogram.Snapshot) prometheus.Metric {
+	return prometheus.MustNewConstHistogram(desc, snapshot.Count, snapshot.Sum, snapshot.Buckets)
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+func TestBranchControlCollector(t *testing.T) {
+	metrics := branch_control.Metrics{
+		Checks:  make(map[string]uint64),
+		Denials: make(map[string]uint64),
+		SaveDataDuration: histogram.Snapshot{
+			Buckets: map[float64]uint64{0.001: 0, 0.01: 0},
+		},
+		MatchLatency: histogram.Snapshot{
+			Buckets: map[float64]uint64{0.000001: 0, 0.00001: 0},
+		},
+	}
+	collector := newBranchControlCollector(prometheus.Labels{"instance": "test"})
+	collector.metrics = func() branch_control.Metrics { return metrics }
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	gather := func() map[string]*dto.MetricFamily {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		gathered := make(map[string]*dto.MetricFamily)
+		for _, family := range families {
+			gathered[family.GetName()] = family
+		}
+		return gathered
+	}
+	// labeled returns the value of the series of the given family with the given label value
+	labeled := func(family *dto.MetricFamily, value string) float64 {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetValue() == value {
+					if metric.GetCounter() != nil {
+						return metric.GetCounter().GetValue()
+					}
+					return metric.GetGauge().GetValue()
+				}
+			}
+		}
+		require.Failf(t, "missing series", "%s has no series labeled %s", family.GetName(), value)
+		return 0
+	}
+
+	families := gather()
+	for _, name := range []string{"dss_branch_control_checks", "dss_branch_control_denials", "dss_branch_control_rules",
+		"dss_branch_control_save_duration", "dss_branch_control_save_failures", "dss_branch_control_match_duration"} {
+		require.Contains(t, families, name)
+	}
+	// Every operation is exported from the start, and is the only label beyond the constant labels
+	checks := families["dss_branch_control_checks"]
+	assert.Len(t, checks.GetMetric(), len(branch_control.MetricActions))
+	assert.Len(t, checks.GetMetric()[0].GetLabel(), 2)
+	assert.Zero(t, labeled(checks, "merge"))
+
+	// Simulate a denied merge, a rule insertion, and a failed save
+	metrics.Checks["merge"] = 1
+	metrics.Denials["merge"] = 1
+	metrics.AccessRules = 1
+	metrics.SaveDataFailures = 1
+	metrics.SaveDataDuration = histogram.Snapshot{Count: 1, Sum: 0.005, Buckets: map[float64]uint64{0.001: 0, 0.01: 1}}
+	metrics.MatchLatency = histogram.Snapshot{Count: 1, Sum: 0.000002, Buckets: map[float64]uint64{0.000001: 0, 0.00001: 1}}
+
+	families = gather()
+	assert.Equal(t, 1.0, labeled(families["dss_branch_control_checks"], "merge"))
+	assert.Equal(t, 0.0, labeled(families["dss_branch_control_checks"], "tag"))
+	assert.Equal(t, 1.0, labeled(families["dss_branch_control_denials"], "merge"))
+	assert.Equal(t, 1.0, labeled(families["dss_branch_control_rules"], "access"))
+	assert.Equal(t, 0.0, labeled(families["dss_branch_control_rules"], "namespace"))
+	assert.Equal(t, 1.0, families["dss_branch_control_save_failures"].GetMetric()[0].GetCounter().GetValue())
+	assert.Equal(t, uint64(1), families["dss_branch_control_save_duration"].GetMetric()[0].GetHistogram().GetSampleCount())
+	assert.Equal(t, uint64(1), families["dss_branch_control_match_duration"].GetMetric()[0].GetHistogram().GetSampleCount())
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+var _ prometheus.Collector = (*logCollector)(nil)
+
+// logCollector exports the time taken to produce each row of dolt_log, from which operators can find the latency
+// percentiles of the log function. The latencies are kept by the function itself, and are read on collection.
+type logCollector struct {
+	rowLatency func() histogram.Snapshot
+
+	descRowDuration *prometheus.Desc
+}
+
+func newLogCollector(labels prometheus.Labels) *logCollector {
+	return &logCollector{
+		rowLatency: sqle.GetLogRowLatency,
+		descRowDuration: prometheus.NewDesc("dss_dolt_log_row_duration",
+			"Histogram of the time taken to produce each row of the dolt_log table function", nil, labels),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *logCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.descRowDuration
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *logCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- constHistogram(c.descRowDuration, c.rowLatency())
+}
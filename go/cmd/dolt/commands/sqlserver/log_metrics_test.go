@@ -0,0 +1,55 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+func TestLogCollector(t *testing.T) {
+	latencies := histogram.New([]float64{0.001, 0.01})
+	collector := newLogCollector(prometheus.Labels{"instance": "test"})
+	collector.rowLatency = latencies.Snapshot
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	rowDuration := func() (uint64, map[float64]uint64) {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		require.Equal(t, "dss_dolt_log_row_duration", families[0].GetName())
+		hist := families[0].GetMetric()[0].GetHistogram()
+		buckets := make(map[float64]uint64)
+		for _, bucket := range hist.GetBucket() {
+			buckets[bucket.GetUpperBound()] = bucket.GetCumulativeCount()
+		}
+		return hist.GetSampleCount(), buckets
+	}
+
+	count, _ := rowDuration()
+	assert.Zero(t, count)
+	latencies.Observe(500 * time.Microsecond)
+	latencies.Observe(5 * time.Millisecond)
+	count, buckets := rowDuration()
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, map[float64]uint64{0.001: 1, 0.01: 2}, buckets)
+}
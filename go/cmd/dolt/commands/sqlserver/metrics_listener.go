@@ -33,6 +33,8 @@ type metricsListener struct {
 	gaugeConcurrentQueries prometheus.Gauge
 	histQueryDur           prometheus.Histogram
 	gaugeVersion           prometheus.Gauge
+	branchControl          *branchControlCollector
+	log                    *logCollector
 }
 
 func newMetricsListener(labels prometheus.Labels, versionStr string) (*metricsListener, error) {
@@ -68,6 +70,8 @@ func newMetricsListener(labels prometheus.Labels, versionStr string) (*metricsLi
 			Help:        "The version of dolt currently running on the machine",
 			ConstLabels: labels,
 		}),
+		branchControl: newBranchControlCollector(labels),
+		log:           newLogCollector(labels),
 	}
 
 	u32Version, err := version.Encode(versionStr)
@@ -87,6 +91,8 @@ func newMetricsListener(labels prometheus.Labels, versionStr string) (*metricsLi
 	prometheus.MustRegister(ml.gaugeConcurrentConn)
 	prometheus.MustRegister(ml.gaugeConcurrentQueries)
 	prometheus.MustRegister(ml.histQueryDur)
+	prometheus.MustRegister(ml.branchControl)
+	prometheus.MustRegister(ml.log)
 
 	ml.gaugeVersion.Set(f64Version)
 	return ml, nil
@@ -118,4 +124,6 @@ func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeConcurrentConn)
 	prometheus.Unregister(ml.gaugeConcurrentQueries)
 	prometheus.Unregister(ml.histQueryDur)
+	prometheus.Unregister(ml.branchControl)
+	prometheus.Unregister(ml.log)
 }
@@ -32,6 +32,7 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
@@ -141,6 +142,7 @@ func Serve(
 		InitialDb:         "",
 		IsReadOnly:        serverConfig.ReadOnly(),
 		PrivFilePath:      serverConfig.PrivilegeFilePath(),
+		BranchCtrlBase:    serverConfig.BranchControlBaseSource(),
 		DoltCfgDirPath:    serverConfig.CfgDir(),
 		ServerUser:        serverConfig.User(),
 		ServerPass:        serverConfig.Password(),
@@ -289,7 +291,14 @@ func Serve(
 		return
 	}
 
+	// Expired branch control entries are already ignored, but they're removed periodically so that they don't accumulate
+	stopExpirySweeper := branch_control.StartExpirySweeper(branch_control.DefaultExpirySweepInterval)
+	// Backups with a schedule set by dolt_backup_schedule are synced while the server runs
+	stopBackupScheduler := sqle.StartBackupScheduler(sqlEngine.NewContext, sqle.DefaultBackupSchedulerInterval)
+
 	serverController.registerCloseFunction(startError, func() error {
+		stopExpirySweeper()
+		stopBackupScheduler()
 		if metSrv != nil {
 			metSrv.Close()
 		}
@@ -139,6 +139,8 @@ type ServerConfig interface {
 	PrivilegeFilePath() string
 	// BranchControlFilePath returns the path to the file which contains the branch control permissions.
 	BranchControlFilePath() string
+	// BranchControlBaseSource returns the location of the read-only branch control permissions, which may be empty.
+	BranchControlBaseSource() string
 	// UserVars is an array containing user specific session variables
 	UserVars() []UserSessionVars
 	// JwksConfig is an array containing jwks config
@@ -183,6 +185,7 @@ type commandLineServerConfig struct {
 	persistenceBehavior     string
 	privilegeFilePath       string
 	branchControlFilePath   string
+	branchControlBase       string
 	allowCleartextPasswords bool
 	socket                  string
 	remotesapiPort          *int
@@ -305,6 +308,11 @@ func (cfg *commandLineServerConfig) BranchControlFilePath() string {
 	return cfg.branchControlFilePath
 }
 
+// BranchControlBaseSource returns the location of the read-only branch control permissions, which may be empty.
+func (cfg *commandLineServerConfig) BranchControlBaseSource() string {
+	return cfg.branchControlBase
+}
+
 // UserVars is an array containing user specific session variables.
 func (cfg *commandLineServerConfig) UserVars() []UserSessionVars {
 	return nil
@@ -431,6 +439,12 @@ func (cfg *commandLineServerConfig) withBranchControlFilePath(branchControlFileP
 	return cfg
 }
 
+// withBranchControlBaseSource updates the location of the read-only branch control permissions
+func (cfg *commandLineServerConfig) withBranchControlBaseSource(branchControlBase string) *commandLineServerConfig {
+	cfg.branchControlBase = branchControlBase
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) withAllowCleartextPasswords(allow bool) *commandLineServerConfig {
 	cfg.allowCleartextPasswords = allow
 	return cfg
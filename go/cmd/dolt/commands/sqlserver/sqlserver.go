@@ -149,6 +149,7 @@ func (cmd SqlServerCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsString(persistenceBehaviorFlag, "", "persistence-behavior", fmt.Sprintf("Indicate whether to `load` or `ignore` persisted global variables. Defaults to `%s`.", serverConfig.PersistenceBehavior()))
 	ap.SupportsString(commands.PrivsFilePathFlag, "", "privilege file", "Path to a file to load and store users and grants. Defaults to `$doltcfg-dir/privileges.db`. Will only be created if there is a change to privileges.")
 	ap.SupportsString(commands.BranchCtrlPathFlag, "", "branch control file", "Path to a file to load and store branch control permissions. Defaults to `$doltcfg-dir/branch_control.db`. Will only be created if there is a change to branch control permissions.")
+	ap.SupportsString(commands.BranchCtrlBaseFlag, "", "branch control base", "Location of read-only branch control permissions that are loaded at startup, beneath those of the branch control file. May be a path, or a `file`, `http`, `https`, `gs`, or `localbs` URL. Defaults to `$DOLT_BRANCH_CONTROL_BASE`.")
 	ap.SupportsString(allowCleartextPasswordsFlag, "", "allow-cleartext-passwords", "Allows use of cleartext passwords. Defaults to false.")
 	ap.SupportsOptionalString(socketFlag, "", "socket file", "Path for the unix socket file. Defaults to '/tmp/mysql.sock'.")
 	ap.SupportsUint(remotesapiPortFlag, "", "remotesapi port", "Sets the port for a server which can expose the databases in this sql-server over remotesapi.")
@@ -338,6 +339,10 @@ func SetupDoltConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResults, config S
 		serverConfig.withBranchControlFilePath(path)
 	}
 
+	if branchControlBase, ok := apr.GetValue(commands.BranchCtrlBaseFlag); ok {
+		serverConfig.withBranchControlBaseSource(branchControlBase)
+	}
+
 	return nil
 }
 
@@ -142,6 +142,7 @@ type YAMLConfig struct {
 	ClusterCfg        *ClusterYAMLConfig    `yaml:"cluster"`
 	PrivilegeFile     *string               `yaml:"privilege_file"`
 	BranchControlFile *string               `yaml:"branch_control_file"`
+	BranchControlBase *string               `yaml:"branch_control_base"`
 	Vars              []UserSessionVars     `yaml:"user_session_vars"`
 	Jwks              []engine.JwksConfig   `yaml:"jwks"`
 	GoldenMysqlConn   *string               `yaml:"golden_mysql_conn"`
@@ -369,6 +370,14 @@ func (cfg YAMLConfig) BranchControlFilePath() string {
 	return filepath.Join(cfg.CfgDir(), defaultBranchControlFilePath)
 }
 
+// BranchControlBaseSource returns the location of the read-only branch control permissions, which may be empty.
+func (cfg YAMLConfig) BranchControlBaseSource() string {
+	if cfg.BranchControlBase != nil {
+		return *cfg.BranchControlBase
+	}
+	return ""
+}
+
 // UserVars is an array containing user specific session variables
 func (cfg YAMLConfig) UserVars() []UserSessionVars {
 	if cfg.Vars != nil {
@@ -0,0 +1,245 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var stashDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Stash the changes in a dirty working set away",
+	LongDesc: `Use {{.EmphasisLeft}}dolt stash{{.EmphasisRight}} when you want to record the current state of the working set, but want to go back to a clean working set. The command saves your local modifications away and reverts the working set to match the {{.EmphasisLeft}}HEAD{{.EmphasisRight}} commit. Staged changes and new tables are stashed along with unstaged changes.
+
+{{.EmphasisLeft}}push{{.EmphasisRight}}
+Save your local modifications to a new stash, and revert the working set to match {{.EmphasisLeft}}HEAD{{.EmphasisRight}}. This is the default when no subcommand is given.
+
+{{.EmphasisLeft}}list{{.EmphasisRight}}
+List the stashes that you currently have, most recent first. Each stash is listed with its name and description.
+
+{{.EmphasisLeft}}pop{{.EmphasisRight}}
+Remove a single stash from the list of stashes and apply it on top of the current working set. The stash is applied through a three-way merge, and is only removed when it applies without conflicts or constraint violations. Staged changes are restored as unstaged changes.
+
+{{.EmphasisLeft}}apply{{.EmphasisRight}}
+Like {{.EmphasisLeft}}pop{{.EmphasisRight}}, but do not remove the stash from the list of stashes.
+
+{{.EmphasisLeft}}drop{{.EmphasisRight}}
+Remove a single stash from the list of stashes.
+
+{{.EmphasisLeft}}clear{{.EmphasisRight}}
+Remove all of the stashes.
+
+Stashes are named {{.EmphasisLeft}}stash@{n}{{.EmphasisRight}}, where n is the position of the stash in the list of stashes. The most recent stash is {{.EmphasisLeft}}stash@{0}{{.EmphasisRight}}, which is used when no stash is given.`,
+	Synopsis: []string{
+		"[push] [-m {{.LessThan}}msg{{.GreaterThan}}]",
+		"list",
+		"pop [{{.LessThan}}stash{{.GreaterThan}}]",
+		"apply [{{.LessThan}}stash{{.GreaterThan}}]",
+		"drop [{{.LessThan}}stash{{.GreaterThan}}]",
+		"clear",
+	},
+}
+
+type StashCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd StashCmd) Name() string {
+	return "stash"
+}
+
+// Description returns a description of the command
+func (cmd StashCmd) Description() string {
+	return "Stash the changes in a dirty working set away."
+}
+
+func (cmd StashCmd) Docs() *cli.CommandDocumentation {
+	ap := cli.CreateStashArgParser()
+	return cli.NewCommandDocumentation(stashDocs, ap)
+}
+
+func (cmd StashCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateStashArgParser()
+}
+
+// Exec executes the command
+func (cmd StashCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cli.CreateStashArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, stashDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	subcommand := cli.StashPushCmd
+	if apr.NArg() > 0 {
+		subcommand = apr.Arg(0)
+	}
+	if apr.NArg() > 2 || (subcommand != cli.StashPushCmd && apr.Contains(cli.MessageArg)) {
+		usage()
+		return 1
+	}
+	// Pushing a stash creates a commit, so we need user identity
+	if subcommand == cli.StashPushCmd && !cli.CheckUserNameAndEmail(dEnv) {
+		return 1
+	}
+	if subcommand != cli.StashListCmd && dEnv.IsLocked() {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
+	}
+
+	var verr errhand.VerboseError
+	switch subcommand {
+	case cli.StashPushCmd:
+		verr = stashPush(ctx, dEnv, apr)
+	case cli.StashListCmd:
+		verr = stashList(ctx, dEnv)
+	case cli.StashPopCmd, cli.StashApplyCmd:
+		verr = stashApply(ctx, dEnv, apr, subcommand == cli.StashPopCmd)
+	case cli.StashDropCmd:
+		verr = stashDrop(ctx, dEnv, apr)
+	case cli.StashClearCmd:
+		verr = errhand.VerboseErrorFromError(actions.ClearStashes(ctx, dEnv.DoltDB))
+	default:
+		verr = errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+func stashPush(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() > 1 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+	name, email, err := env.GetNameAndEmail(dEnv.Config)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	ws, err := dEnv.WorkingSet(ctx)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if ws.MergeActive() {
+		return errhand.BuildDError("error: unable to stash changes while a merge is in progress").Build()
+	}
+	roots, err := dEnv.Roots(ctx)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	msg, _ := apr.GetValue(cli.MessageArg)
+	props := actions.StashProps{Name: name, Email: email, Message: msg}
+	branch := dEnv.RepoStateReader().CWBHeadRef().GetPath()
+	roots, err = actions.StashChanges(ctx, dEnv.DoltDB, roots, headCommit, branch, props)
+	if err == actions.ErrNoLocalChanges {
+		cli.Println("No local changes to save")
+		return nil
+	} else if err != nil {
+		return errhand.BuildDError("error: failed to stash changes").AddCause(err).Build()
+	}
+	if err = dEnv.UpdateRoots(ctx, roots); err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	stash, err := actions.ResolveStash(ctx, dEnv.DoltDB, "")
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	meta, err := stash.Commit.GetCommitMeta(ctx)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	cli.Println("Saved working directory and index state " + meta.Description)
+	return nil
+}
+
+func stashList(ctx context.Context, dEnv *env.DoltEnv) errhand.VerboseError {
+	stashes, err := dEnv.DoltDB.GetStashes(ctx)
+	if err != nil {
+		return errhand.BuildDError("error: failed to read stashes").AddCause(err).Build()
+	}
+	for i, stash := range stashes {
+		meta, err := stash.Commit.GetCommitMeta(ctx)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		cli.Println(fmt.Sprintf("%s: %s", actions.StashName(i), meta.Description))
+	}
+	return nil
+}
+
+func stashApply(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, pop bool) errhand.VerboseError {
+	ws, err := dEnv.WorkingSet(ctx)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if ws.MergeActive() {
+		return errhand.BuildDError("error: unable to apply a stash while a merge is in progress").Build()
+	}
+
+	stashName := actions.StashName(0)
+	if apr.NArg() > 1 {
+		stashName = apr.Arg(1)
+	}
+	stash, err := actions.ResolveStash(ctx, dEnv.DoltDB, stashName)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	workingRoot, err := merge.ApplyStash(ctx, dEnv.DoltDB, ws.WorkingRoot(), stash, opts)
+	if err != nil {
+		return errhand.BuildDError("error: failed to apply %s", stashName).AddCause(err).Build()
+	}
+	if err = dEnv.UpdateWorkingRoot(ctx, workingRoot); err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	if pop {
+		if err = dEnv.DoltDB.DeleteStash(ctx, stash.Ref); err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		cli.Println(fmt.Sprintf("Dropped %s", stashName))
+	}
+	return nil
+}
+
+func stashDrop(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	stashName := actions.StashName(0)
+	if apr.NArg() > 1 {
+		stashName = apr.Arg(1)
+	}
+	stash, err := actions.ResolveStash(ctx, dEnv.DoltDB, stashName)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if err = dEnv.DoltDB.DeleteStash(ctx, stash.Ref); err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	cli.Println(fmt.Sprintf("Dropped %s", stashName))
+	return nil
+}
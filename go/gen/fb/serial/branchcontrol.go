@@ -122,7 +122,147 @@ func (rcv *BranchControl) TryNamespaceTbl(obj *BranchControlNamespace) (*BranchC
 	return nil, nil
 }
 
-const BranchControlNumFields = 2
+func (rcv *BranchControl) PendingMoves(obj *BranchControlPendingMove, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *BranchControl) TryPendingMoves(obj *BranchControlPendingMove, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlPendingMoveNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControl) PendingMovesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *BranchControl) Freezes(obj *BranchControlFreeze, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *BranchControl) TryFreezes(obj *BranchControlFreeze, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlFreezeNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControl) FreezesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *BranchControl) DefaultBranchChanges(obj *BranchControlDefaultBranchChange, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *BranchControl) TryDefaultBranchChanges(obj *BranchControlDefaultBranchChange, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlDefaultBranchChangeNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControl) DefaultBranchChangesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *BranchControl) Roles(obj *BranchControlRoleMember, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *BranchControl) TryRoles(obj *BranchControlRoleMember, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlRoleMemberNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControl) RolesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+const BranchControlNumFields = 6
 
 func BranchControlStart(builder *flatbuffers.Builder) {
 	builder.StartObject(BranchControlNumFields)
@@ -133,6 +273,30 @@ func BranchControlAddAccessTbl(builder *flatbuffers.Builder, accessTbl flatbuffe
 func BranchControlAddNamespaceTbl(builder *flatbuffers.Builder, namespaceTbl flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(namespaceTbl), 0)
 }
+func BranchControlAddPendingMoves(builder *flatbuffers.Builder, pendingMoves flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(pendingMoves), 0)
+}
+func BranchControlStartPendingMovesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlAddFreezes(builder *flatbuffers.Builder, freezes flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(freezes), 0)
+}
+func BranchControlStartFreezesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlAddDefaultBranchChanges(builder *flatbuffers.Builder, defaultBranchChanges flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(4, flatbuffers.UOffsetT(defaultBranchChanges), 0)
+}
+func BranchControlStartDefaultBranchChangesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlAddRoles(builder *flatbuffers.Builder, roles flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(5, flatbuffers.UOffsetT(roles), 0)
+}
+func BranchControlStartRolesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
 func BranchControlEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -466,7 +630,111 @@ func (rcv *BranchControlAccessValue) MutatePermissions(n uint64) bool {
 	return rcv._tab.MutateUint64Slot(10, n)
 }
 
-const BranchControlAccessValueNumFields = 4
+func (rcv *BranchControlAccessValue) Operations() uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetUint64(o + rcv._tab.Pos)
+	}
+	return 1
+}
+
+func (rcv *BranchControlAccessValue) MutateOperations(n uint64) bool {
+	return rcv._tab.MutateUint64Slot(12, n)
+}
+
+func (rcv *BranchControlAccessValue) Priority() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutatePriority(n int64) bool {
+	return rcv._tab.MutateInt64Slot(14, n)
+}
+
+func (rcv *BranchControlAccessValue) WindowStart() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutateWindowStart(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(16, n)
+}
+
+func (rcv *BranchControlAccessValue) WindowEnd() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutateWindowEnd(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(18, n)
+}
+
+func (rcv *BranchControlAccessValue) WindowDays() byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.GetByte(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutateWindowDays(n byte) bool {
+	return rcv._tab.MutateByteSlot(20, n)
+}
+
+func (rcv *BranchControlAccessValue) RequiresApproval() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *BranchControlAccessValue) MutateRequiresApproval(n bool) bool {
+	return rcv._tab.MutateBoolSlot(22, n)
+}
+
+func (rcv *BranchControlAccessValue) ExpiresAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(24))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutateExpiresAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(24, n)
+}
+
+func (rcv *BranchControlAccessValue) Refs() byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(26))
+	if o != 0 {
+		return rcv._tab.GetByte(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlAccessValue) MutateRefs(n byte) bool {
+	return rcv._tab.MutateByteSlot(26, n)
+}
+
+func (rcv *BranchControlAccessValue) TablePattern() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(28))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlAccessValueNumFields = 13
 
 func BranchControlAccessValueStart(builder *flatbuffers.Builder) {
 	builder.StartObject(BranchControlAccessValueNumFields)
@@ -483,6 +751,33 @@ func BranchControlAccessValueAddHost(builder *flatbuffers.Builder, host flatbuff
 func BranchControlAccessValueAddPermissions(builder *flatbuffers.Builder, permissions uint64) {
 	builder.PrependUint64Slot(3, permissions, 0)
 }
+func BranchControlAccessValueAddOperations(builder *flatbuffers.Builder, operations uint64) {
+	builder.PrependUint64Slot(4, operations, 1)
+}
+func BranchControlAccessValueAddPriority(builder *flatbuffers.Builder, priority int64) {
+	builder.PrependInt64Slot(5, priority, 0)
+}
+func BranchControlAccessValueAddWindowStart(builder *flatbuffers.Builder, windowStart uint32) {
+	builder.PrependUint32Slot(6, windowStart, 0)
+}
+func BranchControlAccessValueAddWindowEnd(builder *flatbuffers.Builder, windowEnd uint32) {
+	builder.PrependUint32Slot(7, windowEnd, 0)
+}
+func BranchControlAccessValueAddWindowDays(builder *flatbuffers.Builder, windowDays byte) {
+	builder.PrependByteSlot(8, windowDays, 0)
+}
+func BranchControlAccessValueAddRequiresApproval(builder *flatbuffers.Builder, requiresApproval bool) {
+	builder.PrependBoolSlot(9, requiresApproval, false)
+}
+func BranchControlAccessValueAddExpiresAt(builder *flatbuffers.Builder, expiresAt int64) {
+	builder.PrependInt64Slot(10, expiresAt, 0)
+}
+func BranchControlAccessValueAddRefs(builder *flatbuffers.Builder, refs byte) {
+	builder.PrependByteSlot(11, refs, 0)
+}
+func BranchControlAccessValueAddTablePattern(builder *flatbuffers.Builder, tablePattern flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(12, flatbuffers.UOffsetT(tablePattern), 0)
+}
 func BranchControlAccessValueEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -822,83 +1117,516 @@ func BranchControlNamespaceValueEnd(builder *flatbuffers.Builder) flatbuffers.UO
 	return builder.EndObject()
 }
 
-type BranchControlBinlog struct {
+type BranchControlPendingMove struct {
 	_tab flatbuffers.Table
 }
 
-func InitBranchControlBinlogRoot(o *BranchControlBinlog, buf []byte, offset flatbuffers.UOffsetT) error {
+func InitBranchControlPendingMoveRoot(o *BranchControlPendingMove, buf []byte, offset flatbuffers.UOffsetT) error {
 	n := flatbuffers.GetUOffsetT(buf[offset:])
 	o.Init(buf, n+offset)
-	if BranchControlBinlogNumFields < o.Table().NumFields() {
+	if BranchControlPendingMoveNumFields < o.Table().NumFields() {
 		return flatbuffers.ErrTableHasUnknownFields
 	}
 	return nil
 }
 
-func TryGetRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlBinlog, error) {
-	x := &BranchControlBinlog{}
-	return x, InitBranchControlBinlogRoot(x, buf, offset)
+func TryGetRootAsBranchControlPendingMove(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlPendingMove, error) {
+	x := &BranchControlPendingMove{}
+	return x, InitBranchControlPendingMoveRoot(x, buf, offset)
 }
 
-func GetRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) *BranchControlBinlog {
-	x := &BranchControlBinlog{}
-	InitBranchControlBinlogRoot(x, buf, offset)
+func GetRootAsBranchControlPendingMove(buf []byte, offset flatbuffers.UOffsetT) *BranchControlPendingMove {
+	x := &BranchControlPendingMove{}
+	InitBranchControlPendingMoveRoot(x, buf, offset)
 	return x
 }
 
-func TryGetSizePrefixedRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlBinlog, error) {
-	x := &BranchControlBinlog{}
-	return x, InitBranchControlBinlogRoot(x, buf, offset+flatbuffers.SizeUint32)
+func TryGetSizePrefixedRootAsBranchControlPendingMove(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlPendingMove, error) {
+	x := &BranchControlPendingMove{}
+	return x, InitBranchControlPendingMoveRoot(x, buf, offset+flatbuffers.SizeUint32)
 }
 
-func GetSizePrefixedRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) *BranchControlBinlog {
-	x := &BranchControlBinlog{}
-	InitBranchControlBinlogRoot(x, buf, offset+flatbuffers.SizeUint32)
+func GetSizePrefixedRootAsBranchControlPendingMove(buf []byte, offset flatbuffers.UOffsetT) *BranchControlPendingMove {
+	x := &BranchControlPendingMove{}
+	InitBranchControlPendingMoveRoot(x, buf, offset+flatbuffers.SizeUint32)
 	return x
 }
 
-func (rcv *BranchControlBinlog) Init(buf []byte, i flatbuffers.UOffsetT) {
+func (rcv *BranchControlPendingMove) Init(buf []byte, i flatbuffers.UOffsetT) {
 	rcv._tab.Bytes = buf
 	rcv._tab.Pos = i
 }
 
-func (rcv *BranchControlBinlog) Table() flatbuffers.Table {
+func (rcv *BranchControlPendingMove) Table() flatbuffers.Table {
 	return rcv._tab
 }
 
-func (rcv *BranchControlBinlog) Rows(obj *BranchControlBinlogRow, j int) bool {
+func (rcv *BranchControlPendingMove) Branch() []byte {
 	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
 	if o != 0 {
-		x := rcv._tab.Vector(o)
-		x += flatbuffers.UOffsetT(j) * 4
-		x = rcv._tab.Indirect(x)
-		obj.Init(rcv._tab.Bytes, x)
-		return true
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
 	}
-	return false
+	return nil
 }
 
-func (rcv *BranchControlBinlog) TryRows(obj *BranchControlBinlogRow, j int) (bool, error) {
-	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+func (rcv *BranchControlPendingMove) ProposedHash() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
 	if o != 0 {
-		x := rcv._tab.Vector(o)
-		x += flatbuffers.UOffsetT(j) * 4
-		x = rcv._tab.Indirect(x)
-		obj.Init(rcv._tab.Bytes, x)
-		if BranchControlBinlogRowNumFields < obj.Table().NumFields() {
-			return false, flatbuffers.ErrTableHasUnknownFields
-		}
-		return true, nil
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
 	}
-	return false, nil
+	return nil
 }
 
-func (rcv *BranchControlBinlog) RowsLength() int {
-	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+func (rcv *BranchControlPendingMove) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
 	if o != 0 {
-		return rcv._tab.VectorLen(o)
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
 	}
-	return 0
+	return nil
+}
+
+func (rcv *BranchControlPendingMove) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlPendingMove) CreatedAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlPendingMove) MutateCreatedAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(12, n)
+}
+
+const BranchControlPendingMoveNumFields = 5
+
+func BranchControlPendingMoveStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlPendingMoveNumFields)
+}
+func BranchControlPendingMoveAddBranch(builder *flatbuffers.Builder, branch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(branch), 0)
+}
+func BranchControlPendingMoveAddProposedHash(builder *flatbuffers.Builder, proposedHash flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(proposedHash), 0)
+}
+func BranchControlPendingMoveAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(user), 0)
+}
+func BranchControlPendingMoveAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(host), 0)
+}
+func BranchControlPendingMoveAddCreatedAt(builder *flatbuffers.Builder, createdAt int64) {
+	builder.PrependInt64Slot(4, createdAt, 0)
+}
+func BranchControlPendingMoveEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlFreeze struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlFreezeRoot(o *BranchControlFreeze, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	o.Init(buf, n+offset)
+	if BranchControlFreezeNumFields < o.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func TryGetRootAsBranchControlFreeze(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlFreeze, error) {
+	x := &BranchControlFreeze{}
+	return x, InitBranchControlFreezeRoot(x, buf, offset)
+}
+
+func GetRootAsBranchControlFreeze(buf []byte, offset flatbuffers.UOffsetT) *BranchControlFreeze {
+	x := &BranchControlFreeze{}
+	InitBranchControlFreezeRoot(x, buf, offset)
+	return x
+}
+
+func TryGetSizePrefixedRootAsBranchControlFreeze(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlFreeze, error) {
+	x := &BranchControlFreeze{}
+	return x, InitBranchControlFreezeRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func GetSizePrefixedRootAsBranchControlFreeze(buf []byte, offset flatbuffers.UOffsetT) *BranchControlFreeze {
+	x := &BranchControlFreeze{}
+	InitBranchControlFreezeRoot(x, buf, offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *BranchControlFreeze) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *BranchControlFreeze) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlFreeze) Branch() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlFreeze) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlFreeze) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlFreeze) Reason() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlFreeze) CreatedAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlFreeze) MutateCreatedAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(12, n)
+}
+
+const BranchControlFreezeNumFields = 5
+
+func BranchControlFreezeStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlFreezeNumFields)
+}
+func BranchControlFreezeAddBranch(builder *flatbuffers.Builder, branch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(branch), 0)
+}
+func BranchControlFreezeAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(user), 0)
+}
+func BranchControlFreezeAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(host), 0)
+}
+func BranchControlFreezeAddReason(builder *flatbuffers.Builder, reason flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(reason), 0)
+}
+func BranchControlFreezeAddCreatedAt(builder *flatbuffers.Builder, createdAt int64) {
+	builder.PrependInt64Slot(4, createdAt, 0)
+}
+func BranchControlFreezeEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlDefaultBranchChange struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlDefaultBranchChangeRoot(o *BranchControlDefaultBranchChange, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	o.Init(buf, n+offset)
+	if BranchControlDefaultBranchChangeNumFields < o.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func TryGetRootAsBranchControlDefaultBranchChange(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlDefaultBranchChange, error) {
+	x := &BranchControlDefaultBranchChange{}
+	return x, InitBranchControlDefaultBranchChangeRoot(x, buf, offset)
+}
+
+func GetRootAsBranchControlDefaultBranchChange(buf []byte, offset flatbuffers.UOffsetT) *BranchControlDefaultBranchChange {
+	x := &BranchControlDefaultBranchChange{}
+	InitBranchControlDefaultBranchChangeRoot(x, buf, offset)
+	return x
+}
+
+func TryGetSizePrefixedRootAsBranchControlDefaultBranchChange(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlDefaultBranchChange, error) {
+	x := &BranchControlDefaultBranchChange{}
+	return x, InitBranchControlDefaultBranchChangeRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func GetSizePrefixedRootAsBranchControlDefaultBranchChange(buf []byte, offset flatbuffers.UOffsetT) *BranchControlDefaultBranchChange {
+	x := &BranchControlDefaultBranchChange{}
+	InitBranchControlDefaultBranchChangeRoot(x, buf, offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *BranchControlDefaultBranchChange) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *BranchControlDefaultBranchChange) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlDefaultBranchChange) Database() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlDefaultBranchChange) OldBranch() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlDefaultBranchChange) NewBranch() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlDefaultBranchChange) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlDefaultBranchChange) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlDefaultBranchChange) ChangedAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlDefaultBranchChange) MutateChangedAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(14, n)
+}
+
+const BranchControlDefaultBranchChangeNumFields = 6
+
+func BranchControlDefaultBranchChangeStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlDefaultBranchChangeNumFields)
+}
+func BranchControlDefaultBranchChangeAddDatabase(builder *flatbuffers.Builder, database flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(database), 0)
+}
+func BranchControlDefaultBranchChangeAddOldBranch(builder *flatbuffers.Builder, oldBranch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(oldBranch), 0)
+}
+func BranchControlDefaultBranchChangeAddNewBranch(builder *flatbuffers.Builder, newBranch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(newBranch), 0)
+}
+func BranchControlDefaultBranchChangeAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(user), 0)
+}
+func BranchControlDefaultBranchChangeAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(4, flatbuffers.UOffsetT(host), 0)
+}
+func BranchControlDefaultBranchChangeAddChangedAt(builder *flatbuffers.Builder, changedAt int64) {
+	builder.PrependInt64Slot(5, changedAt, 0)
+}
+func BranchControlDefaultBranchChangeEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlRoleMember struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlRoleMemberRoot(o *BranchControlRoleMember, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	o.Init(buf, n+offset)
+	if BranchControlRoleMemberNumFields < o.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func TryGetRootAsBranchControlRoleMember(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlRoleMember, error) {
+	x := &BranchControlRoleMember{}
+	return x, InitBranchControlRoleMemberRoot(x, buf, offset)
+}
+
+func GetRootAsBranchControlRoleMember(buf []byte, offset flatbuffers.UOffsetT) *BranchControlRoleMember {
+	x := &BranchControlRoleMember{}
+	InitBranchControlRoleMemberRoot(x, buf, offset)
+	return x
+}
+
+func TryGetSizePrefixedRootAsBranchControlRoleMember(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlRoleMember, error) {
+	x := &BranchControlRoleMember{}
+	return x, InitBranchControlRoleMemberRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func GetSizePrefixedRootAsBranchControlRoleMember(buf []byte, offset flatbuffers.UOffsetT) *BranchControlRoleMember {
+	x := &BranchControlRoleMember{}
+	InitBranchControlRoleMemberRoot(x, buf, offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *BranchControlRoleMember) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *BranchControlRoleMember) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlRoleMember) Role() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlRoleMember) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlRoleMember) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlRoleMemberNumFields = 3
+
+func BranchControlRoleMemberStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlRoleMemberNumFields)
+}
+func BranchControlRoleMemberAddRole(builder *flatbuffers.Builder, role flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(role), 0)
+}
+func BranchControlRoleMemberAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(user), 0)
+}
+func BranchControlRoleMemberAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(host), 0)
+}
+func BranchControlRoleMemberEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlBinlog struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlBinlogRoot(o *BranchControlBinlog, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	o.Init(buf, n+offset)
+	if BranchControlBinlogNumFields < o.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func TryGetRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlBinlog, error) {
+	x := &BranchControlBinlog{}
+	return x, InitBranchControlBinlogRoot(x, buf, offset)
+}
+
+func GetRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) *BranchControlBinlog {
+	x := &BranchControlBinlog{}
+	InitBranchControlBinlogRoot(x, buf, offset)
+	return x
+}
+
+func TryGetSizePrefixedRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlBinlog, error) {
+	x := &BranchControlBinlog{}
+	return x, InitBranchControlBinlogRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func GetSizePrefixedRootAsBranchControlBinlog(buf []byte, offset flatbuffers.UOffsetT) *BranchControlBinlog {
+	x := &BranchControlBinlog{}
+	InitBranchControlBinlogRoot(x, buf, offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *BranchControlBinlog) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *BranchControlBinlog) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlBinlog) Rows(obj *BranchControlBinlogRow, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *BranchControlBinlog) TryRows(obj *BranchControlBinlogRow, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlBinlogRowNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControlBinlog) RowsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
 }
 
 const BranchControlBinlogNumFields = 1
@@ -1008,7 +1736,111 @@ func (rcv *BranchControlBinlogRow) MutatePermissions(n uint64) bool {
 	return rcv._tab.MutateUint64Slot(12, n)
 }
 
-const BranchControlBinlogRowNumFields = 5
+func (rcv *BranchControlBinlogRow) Operations() uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetUint64(o + rcv._tab.Pos)
+	}
+	return 1
+}
+
+func (rcv *BranchControlBinlogRow) MutateOperations(n uint64) bool {
+	return rcv._tab.MutateUint64Slot(14, n)
+}
+
+func (rcv *BranchControlBinlogRow) Priority() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutatePriority(n int64) bool {
+	return rcv._tab.MutateInt64Slot(16, n)
+}
+
+func (rcv *BranchControlBinlogRow) WindowStart() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(18))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutateWindowStart(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(18, n)
+}
+
+func (rcv *BranchControlBinlogRow) WindowEnd() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutateWindowEnd(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(20, n)
+}
+
+func (rcv *BranchControlBinlogRow) WindowDays() byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.GetByte(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutateWindowDays(n byte) bool {
+	return rcv._tab.MutateByteSlot(22, n)
+}
+
+func (rcv *BranchControlBinlogRow) RequiresApproval() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(24))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *BranchControlBinlogRow) MutateRequiresApproval(n bool) bool {
+	return rcv._tab.MutateBoolSlot(24, n)
+}
+
+func (rcv *BranchControlBinlogRow) ExpiresAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(26))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutateExpiresAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(26, n)
+}
+
+func (rcv *BranchControlBinlogRow) Refs() byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(28))
+	if o != 0 {
+		return rcv._tab.GetByte(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlBinlogRow) MutateRefs(n byte) bool {
+	return rcv._tab.MutateByteSlot(28, n)
+}
+
+func (rcv *BranchControlBinlogRow) TablePattern() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(30))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlBinlogRowNumFields = 14
 
 func BranchControlBinlogRowStart(builder *flatbuffers.Builder) {
 	builder.StartObject(BranchControlBinlogRowNumFields)
@@ -1028,6 +1860,33 @@ func BranchControlBinlogRowAddHost(builder *flatbuffers.Builder, host flatbuffer
 func BranchControlBinlogRowAddPermissions(builder *flatbuffers.Builder, permissions uint64) {
 	builder.PrependUint64Slot(4, permissions, 0)
 }
+func BranchControlBinlogRowAddOperations(builder *flatbuffers.Builder, operations uint64) {
+	builder.PrependUint64Slot(5, operations, 1)
+}
+func BranchControlBinlogRowAddPriority(builder *flatbuffers.Builder, priority int64) {
+	builder.PrependInt64Slot(6, priority, 0)
+}
+func BranchControlBinlogRowAddWindowStart(builder *flatbuffers.Builder, windowStart uint32) {
+	builder.PrependUint32Slot(7, windowStart, 0)
+}
+func BranchControlBinlogRowAddWindowEnd(builder *flatbuffers.Builder, windowEnd uint32) {
+	builder.PrependUint32Slot(8, windowEnd, 0)
+}
+func BranchControlBinlogRowAddWindowDays(builder *flatbuffers.Builder, windowDays byte) {
+	builder.PrependByteSlot(9, windowDays, 0)
+}
+func BranchControlBinlogRowAddRequiresApproval(builder *flatbuffers.Builder, requiresApproval bool) {
+	builder.PrependBoolSlot(10, requiresApproval, false)
+}
+func BranchControlBinlogRowAddExpiresAt(builder *flatbuffers.Builder, expiresAt int64) {
+	builder.PrependInt64Slot(11, expiresAt, 0)
+}
+func BranchControlBinlogRowAddRefs(builder *flatbuffers.Builder, refs byte) {
+	builder.PrependByteSlot(12, refs, 0)
+}
+func BranchControlBinlogRowAddTablePattern(builder *flatbuffers.Builder, tablePattern flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(13, flatbuffers.UOffsetT(tablePattern), 0)
+}
 func BranchControlBinlogRowEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -17,6 +17,7 @@ package branch_control
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	flatbuffers "github.com/google/flatbuffers/go"
@@ -30,12 +31,37 @@ type Permissions uint64
 const (
 	Permissions_Admin Permissions = 1 << iota // Permissions_Admin grants unrestricted control over a branch, including modification of table entries
 	Permissions_Write                         // Permissions_Write allows for all modifying operations on a branch, but does not allow modification of table entries
+	Permissions_Read                          // Permissions_Read allows for reading a branch through a branch-qualified database, which is implied by the other permissions
 )
 
+// Operations are a set of flags that denote the classes of operations that an entry applies to. When checking access,
+// a single class is given, and only entries that include the class will have their permissions considered.
+type Operations uint64
+
+const (
+	Operations_All       Operations = 1 << iota // Operations_All applies an entry to every class of operation
+	Operations_DirectDML                        // Operations_DirectDML covers direct modifications of tables and schemas, such as INSERT and ALTER TABLE
+	Operations_Merge                            // Operations_Merge covers merges, such as those performed by DOLT_MERGE
+	Operations_RefMove                          // Operations_RefMove covers procedures that move a branch's head, such as DOLT_RESET
+	Operations_Tag                              // Operations_Tag covers the creation and deletion of tags
+)
+
+// Includes returns whether the calling set of operations includes the given operation class.
+func (ops Operations) Includes(op Operations) bool {
+	return ops&Operations_All == Operations_All || ops&op == op
+}
+
 // Access contains all of the expressions that comprise the "dolt_branch_control" table, which handles write Access to
 // branches, along with write access to the branch control system tables.
 type Access struct {
 	binlog *Binlog
+	// base contains the read-only entries that were loaded from the base source. Entries in this table take precedence
+	// over those in the base, and only this table is saved. The base is never modified once loaded, and is only replaced
+	// while holding this table's write lock. Nil when there is no base source.
+	base *Access
+	// roles contains the members of the roles that entries may reference in place of a user. The base is matched using
+	// the roles of the table that it's layered under.
+	roles *Roles
 
 	Branches  []MatchExpression
 	Users     []MatchExpression
@@ -44,14 +70,28 @@ type Access struct {
 	SuperUser string
 	SuperHost string
 	RWMutex   *sync.RWMutex
+	// loadedDuplicates is the number of exact duplicate entries that were dropped when the table was deserialized
+	loadedDuplicates int
 }
 
-// AccessValue contains the user-facing values of a particular row, along with the permissions for a row.
+// AccessValue contains the user-facing values of a particular row, along with the permissions, operations, priority,
+// window, expiration, kinds of refs, and table pattern for a row.
 type AccessValue struct {
 	Branch      string
 	User        string
 	Host        string
 	Permissions Permissions
+	Operations  Operations
+	Priority    int64
+	Window      Window
+	// RequiresApproval turns the ref moves of users that are not admins into proposals, which an admin must approve
+	RequiresApproval bool
+	// ExpiresAt is the time at which the entry lapses, in milliseconds since the Unix epoch. Zero never expires.
+	ExpiresAt int64
+	// Refs are the kinds of refs that the branch expression is matched against. Zero only matches branches.
+	Refs Refs
+	// Table is a folded expression that restricts the entry to writes of matching tables. Empty applies to everything.
+	Table string
 }
 
 // newAccess returns a new Access.
@@ -68,13 +108,198 @@ func newAccess(superUser string, superHost string) *Access {
 	}
 }
 
-// Match returns whether any entries match the given branch, user, and host, along with their permissions. Requires
-// external synchronization handling, therefore manually manage the RWMutex.
+// Match returns whether any entries that apply to all operations match the given branch, user, and host, along with
+// their permissions. Requires external synchronization handling, therefore manually manage the RWMutex.
 func (tbl *Access) Match(branch string, user string, host string) (bool, Permissions) {
-	if tbl.SuperUser == user && tbl.SuperHost == host {
+	return tbl.MatchOperation(branch, user, host, Operations_All)
+}
+
+// MatchOperation returns whether any entries that include the given operation class match the given branch, user, and
+// host, along with their permissions. How the permissions of multiple matching entries are combined is determined by
+// the current MatchMode. Entries whose window does not contain the current time are ignored. Requires external
+// synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Access) MatchOperation(branch string, user string, host string, op Operations) (bool, Permissions) {
+	return tbl.MatchOperationAsOf(branch, user, host, op, now())
+}
+
+// MatchOperationAsOf is the same as MatchOperation, except that entries are matched against their windows using the
+// given time rather than the current time. Requires external synchronization handling, therefore manually manage the
+// RWMutex.
+func (tbl *Access) MatchOperationAsOf(branch string, user string, host string, op Operations, asOf time.Time) (bool, Permissions) {
+	return tbl.matchWithStrategy(branch, user, []string{host}, op, currentMatchMode().strategy(), asOf)
+}
+
+// MatchClientOperationAsOf is the same as MatchOperationAsOf, except that the host is the host of a client as reported
+// by the server. The entries are matched against every form of the host given by ClientHostForms, so that entries
+// written for either the IP address or the hostname of the client will match. Requires external synchronization
+// handling, therefore manually manage the RWMutex.
+func (tbl *Access) MatchClientOperationAsOf(branch string, user string, host string, op Operations, asOf time.Time) (bool, Permissions) {
+	return tbl.matchWithStrategy(branch, user, ClientHostForms(host), op, currentMatchMode().strategy(), asOf)
+}
+
+// MatchResult is the detailed result of matching a branch, user, and host against the Access table.
+type MatchResult struct {
+	// Matched is true when the super user or any entry matched
+	Matched bool
+	// Permissions are the permissions that were granted
+	Permissions Permissions
+	// SuperUser is true when the user and host are the super user that was configured for the server
+	SuperUser bool
+	// Indexes are the indexes within Values of the entries that granted their permissions. Indexes beyond the end of
+	// Values refer to the entries of the base, offset by the length of Values. These are still populated for the super
+	// user, although the entries do not change the granted permissions.
+	Indexes []uint32
+}
+
+// MatchDetailed returns the same permissions as MatchOperation, along with why they were granted. This allocates, so
+// it should only be used to explain permissions rather than to check them. Requires external synchronization handling,
+// therefore manually manage the RWMutex.
+func (tbl *Access) MatchDetailed(branch string, user string, host string, op Operations) MatchResult {
+	return tbl.MatchDetailedAsOf(branch, user, host, op, now())
+}
+
+// MatchDetailedAsOf is the same as MatchDetailed, except that entries are matched against their windows using the given
+// time, which allows for previewing the permissions that will be granted at some other time. Requires external
+// synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Access) MatchDetailedAsOf(branch string, user string, host string, op Operations, asOf time.Time) MatchResult {
+	return tbl.matchDetailed(branch, Refs_Branches, "", user, []string{host}, op, asOf)
+}
+
+// MatchClientDetailed is the same as MatchDetailed, except that the host is matched in every form given by
+// ClientHostForms, in the same way as MatchClientOperationAsOf. Requires external synchronization handling, therefore
+// manually manage the RWMutex.
+func (tbl *Access) MatchClientDetailed(branch string, user string, host string, op Operations) MatchResult {
+	return tbl.matchDetailed(branch, Refs_Branches, "", user, ClientHostForms(host), op, now())
+}
+
+// matchDetailed returns the detailed result of matching the given ref of the given kind, table, user, and any of the
+// given hosts.
+func (tbl *Access) matchDetailed(ref string, refs Refs, table string, user string, hosts []string, op Operations, asOf time.Time) MatchResult {
+	filteredIndexes := currentMatchMode().strategy().filter(tbl, tbl.matchHostForms(ref, refs, table, user, hosts, asOf), op)
+	result := MatchResult{
+		Matched:     len(filteredIndexes) > 0,
+		Permissions: tbl.combinePermissions(filteredIndexes),
+		Indexes:     append([]uint32(nil), filteredIndexes...),
+	}
+	indexPool.Put(filteredIndexes)
+	if tbl.isSuperUser(user, hosts) {
+		result.Matched = true
+		result.Permissions = Permissions_Admin
+		result.SuperUser = true
+	}
+	return result
+}
+
+// matchWithStrategy filters the entries down to those matching the given branch, user, and any of the given hosts at
+// the given time, and then combines the permissions of those chosen by the given strategy.
+func (tbl *Access) matchWithStrategy(branch string, user string, hosts []string, op Operations, strategy matchStrategy, asOf time.Time) (bool, Permissions) {
+	return tbl.matchRefWithStrategy(branch, Refs_Branches, "", user, hosts, op, strategy, asOf)
+}
+
+// matchRefWithStrategy is the same as matchWithStrategy, except that the entries are matched against a ref of the given
+// kind rather than a branch, along with the given table. An empty table only matches entries without a table pattern.
+func (tbl *Access) matchRefWithStrategy(ref string, refs Refs, table string, user string, hosts []string, op Operations, strategy matchStrategy, asOf time.Time) (bool, Permissions) {
+	if tbl.isSuperUser(user, hosts) {
 		return true, Permissions_Admin
 	}
 
+	filteredIndexes := strategy.filter(tbl, tbl.matchHostForms(ref, refs, table, user, hosts, asOf), op)
+	bRes, pRes := len(filteredIndexes) > 0, tbl.combinePermissions(filteredIndexes)
+	indexPool.Put(filteredIndexes)
+	return bRes, pRes
+}
+
+// isSuperUser returns whether the given user and any of the given hosts are the super user.
+func (tbl *Access) isSuperUser(user string, hosts []string) bool {
+	if tbl.SuperUser != user {
+		return false
+	}
+	for _, host := range hosts {
+		if tbl.SuperHost == host {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHostForms returns the collection indexes of all entries that match the given ref, user, and any of the given
+// hosts, following the same rules as matchExpressions. Each entry is only returned once, even when it matches multiple
+// hosts. The returned slice comes from the index pool.
+func (tbl *Access) matchHostForms(ref string, refs Refs, table string, user string, hosts []string, asOf time.Time) []uint32 {
+	filteredIndexes := tbl.matchExpressions(ref, refs, table, user, hosts[0], asOf)
+	for _, host := range hosts[1:] {
+		hostIndexes := tbl.matchExpressions(ref, refs, table, user, host, asOf)
+		for _, collectionIndex := range hostIndexes {
+			if !containsIndex(filteredIndexes, collectionIndex) {
+				filteredIndexes = append(filteredIndexes, collectionIndex)
+			}
+		}
+		indexPool.Put(hostIndexes)
+	}
+	return filteredIndexes
+}
+
+// containsIndex returns whether the given collection indexes contain the given collection index.
+func containsIndex(collectionIndexes []uint32, collectionIndex uint32) bool {
+	for _, index := range collectionIndexes {
+		if index == collectionIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// matchExpressions returns the collection indexes of all entries that apply to the given kind of ref and table, whose
+// expressions match the given ref, user, and host, and whose windows contain the given time. Entries that reference a
+// role that the user and host are a member of are matched as well. A user whose name looks like a reference to a role does not match any entries directly, so that
+// the user is unable to impersonate the role. The returned slice comes from the index pool, so it should be returned to
+// the pool once it is no longer used.
+func (tbl *Access) matchExpressions(ref string, refs Refs, table string, user string, host string, asOf time.Time) []uint32 {
+	var filteredIndexes []uint32
+	if IsRoleUser(user) {
+		filteredIndexes = indexPool.Get().([]uint32)[:0]
+	} else {
+		filteredIndexes = tbl.matchLayeredExpressions(ref, refs, table, user, host, asOf)
+	}
+	if tbl.roles == nil {
+		return filteredIndexes
+	}
+	tbl.roles.RWMutex.RLock()
+	roles := tbl.roles.Match(user, host)
+	tbl.roles.RWMutex.RUnlock()
+	for _, role := range roles {
+		roleIndexes := tbl.matchLayeredExpressions(ref, refs, table, RoleUser(role), host, asOf)
+		for _, collectionIndex := range roleIndexes {
+			if !containsIndex(filteredIndexes, collectionIndex) {
+				filteredIndexes = append(filteredIndexes, collectionIndex)
+			}
+		}
+		indexPool.Put(roleIndexes)
+	}
+	return filteredIndexes
+}
+
+// matchLayeredExpressions is the same as matchExpressions, except that roles are not considered. Matching entries of
+// the base are included, unless this table has an entry with the same expressions.
+func (tbl *Access) matchLayeredExpressions(ref string, refs Refs, table string, user string, host string, asOf time.Time) []uint32 {
+	filteredIndexes := tbl.matchOwnExpressions(ref, refs, table, user, host, asOf)
+	if tbl.base == nil {
+		return filteredIndexes
+	}
+	baseIndexes := tbl.base.matchOwnExpressions(ref, refs, table, user, host, asOf)
+	offset := uint32(len(tbl.Values))
+	for _, collectionIndex := range baseIndexes {
+		baseValue := tbl.base.Values[collectionIndex]
+		if tbl.GetIndex(baseValue.Branch, baseValue.User, baseValue.Host) == -1 {
+			filteredIndexes = append(filteredIndexes, offset+collectionIndex)
+		}
+	}
+	indexPool.Put(baseIndexes)
+	return filteredIndexes
+}
+
+// matchOwnExpressions is the same as matchLayeredExpressions, except that the base is not considered.
+func (tbl *Access) matchOwnExpressions(ref string, refs Refs, table string, user string, host string, asOf time.Time) []uint32 {
 	filteredIndexes := Match(tbl.Users, user, sql.Collation_utf8mb4_0900_bin)
 
 	filteredHosts := tbl.filterHosts(filteredIndexes)
@@ -84,12 +309,41 @@ func (tbl *Access) Match(branch string, user string, host string) (bool, Permiss
 
 	filteredBranches := tbl.filterBranches(filteredIndexes)
 	indexPool.Put(filteredIndexes)
-	filteredIndexes = Match(filteredBranches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	filteredIndexes = Match(filteredBranches, ref, sql.Collation_utf8mb4_0900_ai_ci)
 	matchExprPool.Put(filteredBranches)
 
-	bRes, pRes := len(filteredIndexes) > 0, tbl.gatherPermissions(filteredIndexes)
-	indexPool.Put(filteredIndexes)
-	return bRes, pRes
+	// Entries for other kinds of refs or other tables, outside of their window, or that have expired, are treated as
+	// though they do not exist
+	windowedIndexes := filteredIndexes[:0]
+	for _, collectionIndex := range filteredIndexes {
+		if value := tbl.Values[collectionIndex]; value.Refs.Includes(refs) && value.AppliesToTable(table) &&
+			value.Window.Contains(asOf) && !value.ExpiredAsOf(asOf) {
+			windowedIndexes = append(windowedIndexes, collectionIndex)
+		}
+	}
+	return windowedIndexes
+}
+
+// combinePermissions returns the union of the permissions of the given collection indexes.
+func (tbl *Access) combinePermissions(collectionIndexes []uint32) Permissions {
+	perms := Permissions(0)
+	for _, collectionIndex := range collectionIndexes {
+		perms |= tbl.value(collectionIndex).Permissions
+	}
+	return perms
+}
+
+// value returns the entry at the given collection index, which may refer to an entry of the base.
+func (tbl *Access) value(collectionIndex uint32) *AccessValue {
+	if offset := uint32(len(tbl.Values)); collectionIndex >= offset {
+		return &tbl.base.Values[collectionIndex-offset]
+	}
+	return &tbl.Values[collectionIndex]
+}
+
+// isBase returns whether the given collection index refers to an entry of the base.
+func (tbl *Access) isBase(collectionIndex uint32) bool {
+	return collectionIndex >= uint32(len(tbl.Values))
 }
 
 // GetIndex returns the index of the given branch, user, and host expressions. If the expressions cannot be found,
@@ -104,11 +358,40 @@ func (tbl *Access) GetIndex(branchExpr string, userExpr string, hostExpr string)
 	return -1
 }
 
+// RestrictsReads returns whether any entry, for any user and host, grants the read permission on the given branch at
+// the given time. Reads of a branch are only restricted once such an entry exists, so that branches without any read
+// entries remain readable by everyone. Requires external synchronization handling, therefore manually manage the
+// RWMutex.
+func (tbl *Access) RestrictsReads(branch string, asOf time.Time) bool {
+	if tbl.restrictsOwnReads(branch, asOf) {
+		return true
+	}
+	return tbl.base != nil && tbl.base.restrictsOwnReads(branch, asOf)
+}
+
+// restrictsOwnReads is the same as RestrictsReads, except that the base is not considered.
+func (tbl *Access) restrictsOwnReads(branch string, asOf time.Time) bool {
+	collectionIndexes := Match(tbl.Branches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	defer indexPool.Put(collectionIndexes)
+	for _, collectionIndex := range collectionIndexes {
+		if value := tbl.Values[collectionIndex]; value.Permissions&Permissions_Read != 0 &&
+			value.Refs.Includes(Refs_Branches) && value.Window.Contains(asOf) && !value.ExpiredAsOf(asOf) {
+			return true
+		}
+	}
+	return false
+}
+
 // Serialize returns the offset for the Access table written to the given builder.
 func (tbl *Access) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	tbl.RWMutex.RLock()
 	defer tbl.RWMutex.RUnlock()
 
+	return tbl.serialize(b)
+}
+
+// serialize is the same as Serialize, except that it requires external synchronization handling.
+func (tbl *Access) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	// Serialize the binlog
 	binlog := tbl.binlog.Serialize(b)
 	// Initialize field offset slices
@@ -213,11 +496,64 @@ func (tbl *Access) Deserialize(fb *serial.BranchControlAccess) error {
 			User:        string(serialAccessValue.User()),
 			Host:        string(serialAccessValue.Host()),
 			Permissions: Permissions(serialAccessValue.Permissions()),
+			Operations:  Operations(serialAccessValue.Operations()),
+			Priority:    serialAccessValue.Priority(),
+			Window: Window{
+				Start: serialAccessValue.WindowStart(),
+				End:   serialAccessValue.WindowEnd(),
+				Days:  Days(serialAccessValue.WindowDays()),
+			},
+			RequiresApproval: serialAccessValue.RequiresApproval(),
+			ExpiresAt:        serialAccessValue.ExpiresAt(),
+			Refs:             Refs(serialAccessValue.Refs()),
+			Table:            string(serialAccessValue.TablePattern()),
 		}
 	}
+	// Exact duplicates may have been written before duplicate entries were rejected, or by editing the file directly
+	tbl.loadedDuplicates = tbl.dropExactDuplicates()
 	return nil
 }
 
+// Insert adds the given entry to the table and the binlog. Assumes that the expressions have already been folded, and
+// that the entry does not already exist. Requires external synchronization handling.
+func (tbl *Access) Insert(value AccessValue) {
+	nextIdx := uint32(len(tbl.Values))
+	tbl.Branches = append(tbl.Branches, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(value.Branch, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Users = append(tbl.Users, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(value.User, sql.Collation_utf8mb4_0900_bin)})
+	tbl.Hosts = append(tbl.Hosts, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(value.Host, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Values = append(tbl.Values, value)
+	tbl.binlog.insertAccess(value)
+}
+
+// Delete removes the given entry from the table and writes the removal to the binlog. Does nothing if the entry does
+// not exist. Requires external synchronization handling.
+func (tbl *Access) Delete(branch string, user string, host string) {
+	tblIndex := tbl.GetIndex(branch, user, host)
+	if tblIndex == -1 {
+		return
+	}
+	tbl.deleteIndex(tblIndex)
+}
+
+// deleteIndex removes the entry at the given index from the table and the binlog. Requires external synchronization
+// handling.
+func (tbl *Access) deleteIndex(tblIndex int) {
+	tbl.binlog.deleteAccess(tbl.Values[tblIndex])
+	endIndex := len(tbl.Values) - 1
+	tbl.Branches[tblIndex], tbl.Branches[endIndex] = tbl.Branches[endIndex], tbl.Branches[tblIndex]
+	tbl.Users[tblIndex], tbl.Users[endIndex] = tbl.Users[endIndex], tbl.Users[tblIndex]
+	tbl.Hosts[tblIndex], tbl.Hosts[endIndex] = tbl.Hosts[endIndex], tbl.Hosts[tblIndex]
+	tbl.Values[tblIndex], tbl.Values[endIndex] = tbl.Values[endIndex], tbl.Values[tblIndex]
+	// The swapped element must now reference its new position
+	tbl.Branches[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Users[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Hosts[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Branches = tbl.Branches[:endIndex]
+	tbl.Users = tbl.Users[:endIndex]
+	tbl.Hosts = tbl.Hosts[:endIndex]
+	tbl.Values = tbl.Values[:endIndex]
+}
+
 // filterBranches returns all branches that match the given collection indexes.
 func (tbl *Access) filterBranches(filters []uint32) []MatchExpression {
 	if len(filters) == 0 {
@@ -254,25 +590,26 @@ func (tbl *Access) filterHosts(filters []uint32) []MatchExpression {
 	return matchExprs
 }
 
-// gatherPermissions combines all permissions from the given collection indexes and returns the result.
-func (tbl *Access) gatherPermissions(collectionIndexes []uint32) Permissions {
-	perms := Permissions(0)
-	for _, collectionIndex := range collectionIndexes {
-		perms |= tbl.Values[collectionIndex].Permissions
-	}
-	return perms
-}
-
 // Serialize returns the offset for the AccessValue written to the given builder.
 func (val *AccessValue) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	branch := b.CreateString(val.Branch)
 	user := b.CreateString(val.User)
 	host := b.CreateString(val.Host)
+	table := b.CreateString(val.Table)
 
 	serial.BranchControlAccessValueStart(b)
 	serial.BranchControlAccessValueAddBranch(b, branch)
 	serial.BranchControlAccessValueAddUser(b, user)
 	serial.BranchControlAccessValueAddHost(b, host)
 	serial.BranchControlAccessValueAddPermissions(b, uint64(val.Permissions))
+	serial.BranchControlAccessValueAddOperations(b, uint64(val.Operations))
+	serial.BranchControlAccessValueAddPriority(b, val.Priority)
+	serial.BranchControlAccessValueAddWindowStart(b, val.Window.Start)
+	serial.BranchControlAccessValueAddWindowEnd(b, val.Window.End)
+	serial.BranchControlAccessValueAddWindowDays(b, uint8(val.Window.Days))
+	serial.BranchControlAccessValueAddRequiresApproval(b, val.RequiresApproval)
+	serial.BranchControlAccessValueAddExpiresAt(b, val.ExpiresAt)
+	serial.BranchControlAccessValueAddRefs(b, uint8(val.Refs))
+	serial.BranchControlAccessValueAddTablePattern(b, table)
 	return serial.BranchControlAccessValueEnd(b)
 }
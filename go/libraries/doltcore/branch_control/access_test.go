@@ -0,0 +1,109 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDetailed(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "%", User: "root", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "%", User: "%", Host: "%", Permissions: Permissions_Admin, Operations: Operations_Tag})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+
+	// The super user is also covered by rules, which are reported without changing the granted permissions
+	result := access.MatchDetailed("main", "root", "localhost", Operations_DirectDML)
+	assert.Equal(t, MatchResult{Matched: true, Permissions: Permissions_Admin, SuperUser: true, Indexes: []uint32{0}}, result)
+	// The same user from another host is not the super user, so only the rule applies
+	result = access.MatchDetailed("main", "root", "127.0.0.1", Operations_DirectDML)
+	assert.Equal(t, MatchResult{Matched: true, Permissions: Permissions_Write, SuperUser: false, Indexes: []uint32{0}}, result)
+	// A wildcard admin rule is distinguishable from the super user
+	result = access.MatchDetailed("other", "bob", "localhost", Operations_Tag)
+	assert.Equal(t, MatchResult{Matched: true, Permissions: Permissions_Admin, SuperUser: false, Indexes: []uint32{1}}, result)
+	result = access.MatchDetailed("main", "alice", "localhost", Operations_DirectDML)
+	assert.Equal(t, MatchResult{Matched: true, Permissions: Permissions_Admin, SuperUser: false, Indexes: []uint32{2}}, result)
+	result = access.MatchDetailed("other", "alice", "localhost", Operations_DirectDML)
+	assert.Equal(t, MatchResult{Matched: false, Permissions: 0, SuperUser: false, Indexes: nil}, result)
+
+	// The detailed result always agrees with the result used for access checks
+	for _, user := range []string{"root", "alice", "bob"} {
+		for _, op := range []Operations{Operations_DirectDML, Operations_Tag} {
+			matched, perms := access.MatchOperation("main", user, "localhost", op)
+			result = access.MatchDetailed("main", user, "localhost", op)
+			assert.Equal(t, matched, result.Matched)
+			assert.Equal(t, perms, result.Permissions)
+		}
+	}
+}
+
+func TestCanReadBranch(t *testing.T) {
+	enableForTest(t)
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "alice", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "dev", User: "bob", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_Merge})
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	bob := testSessionContext{Context: context.Background(), user: "bob", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+
+	assert.NoError(t, CanReadBranch(alice, "secret"))
+	assert.NoError(t, CanReadBranch(bob, "MAIN"))
+	assert.True(t, ErrCannotReadBranch.Is(CanReadBranch(bob, "secret")))
+	// Only entries that apply to all operations allow the branch to be read
+	assert.True(t, ErrCannotReadBranch.Is(CanReadBranch(bob, "dev")))
+	assert.NoError(t, CanReadBranch(root, "secret"))
+	assert.NoError(t, CanReadBranch(context.Background(), "secret"))
+
+	// Reading does not allow writing
+	assert.True(t, ErrIncorrectPermissions.Is(CheckAccess(testBranchContext{testSessionContext: alice, branch: "main"}, Permissions_Write, Operations_DirectDML)))
+}
+
+func TestCanReadBranchWithoutReadEntries(t *testing.T) {
+	enableForTest(t)
+	carol := testSessionContext{Context: context.Background(), user: "carol", host: "localhost"}
+
+	// Without any entries granting reads, every branch may be read, even by users without any entries
+	assert.NoError(t, CanReadBranch(carol, "main"))
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	assert.NoError(t, CanReadBranch(carol, "main"))
+
+	// A read entry only restricts the branches that it matches
+	StaticController.Access.Insert(AccessValue{Branch: "secret%", User: "bob", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All})
+	assert.True(t, ErrCannotReadBranch.Is(CanReadBranch(carol, "secret_plans")))
+	assert.NoError(t, CanReadBranch(carol, "main"))
+
+	// An expired read entry no longer restricts reads
+	StaticController.Access.Delete("secret%", "bob", "localhost")
+	StaticController.Access.Insert(AccessValue{Branch: "secret%", User: "bob", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All, ExpiresAt: 1})
+	assert.NoError(t, CanReadBranch(carol, "secret_plans"))
+}
+
+func TestIsBranchHidden(t *testing.T) {
+	enableForTest(t)
+	bob := testSessionContext{Context: context.Background(), user: "bob", host: "localhost"}
+	carol := testSessionContext{Context: context.Background(), user: "carol", host: "localhost"}
+	StaticController.Access.Insert(AccessValue{Branch: "secret", User: "bob", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All})
+
+	// Branches are hidden from the users that can't read them, and contexts without a session see every branch
+	assert.True(t, MayHideBranches(carol))
+	assert.True(t, IsBranchHidden(carol, "secret"))
+	assert.False(t, IsBranchHidden(bob, "secret"))
+	assert.False(t, IsBranchHidden(carol, "main"))
+	assert.False(t, MayHideBranches(context.Background()))
+	assert.False(t, IsBranchHidden(context.Background(), "secret"))
+}
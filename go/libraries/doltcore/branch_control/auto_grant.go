@@ -0,0 +1,192 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// AutoGrantVariable is the name of the global system variable that selects the AutoGrantMode of branch control.
+const AutoGrantVariable = "dolt_branch_control_auto_grant"
+
+// AutoGrantMode determines whether the creator of a branch is automatically given control over the branch.
+type AutoGrantMode string
+
+const (
+	AutoGrantMode_Off     AutoGrantMode = "off"     // AutoGrantMode_Off leaves the tables untouched when branches are created
+	AutoGrantMode_On      AutoGrantMode = "on"      // AutoGrantMode_On grants the creator of a branch write and admin on the branch
+	AutoGrantMode_Cleanup AutoGrantMode = "cleanup" // AutoGrantMode_Cleanup grants the same as AutoGrantMode_On, and removes the grant once the branch is deleted
+)
+
+// AutoGrantModes contains every AutoGrantMode, in the order that they are presented to users.
+var AutoGrantModes = []string{string(AutoGrantMode_Off), string(AutoGrantMode_On), string(AutoGrantMode_Cleanup)}
+
+// autoGrantPermissions are the permissions that are granted to the creator of a branch.
+const autoGrantPermissions = Permissions_Write | Permissions_Admin
+
+// currentAutoGrantMode returns the AutoGrantMode set by the system variable. Defaults to AutoGrantMode_Off when the
+// variable has not been defined.
+func currentAutoGrantMode() AutoGrantMode {
+	_, val, ok := sql.SystemVariables.GetGlobal(AutoGrantVariable)
+	if !ok {
+		return AutoGrantMode_Off
+	}
+	if str, ok := val.(string); ok {
+		switch mode := AutoGrantMode(strings.ToLower(str)); mode {
+		case AutoGrantMode_On, AutoGrantMode_Cleanup:
+			return mode
+		}
+	}
+	return AutoGrantMode_Off
+}
+
+// GrantCreatedBranch gives the context's user write and admin permissions on the given branch, which the user has just
+// created. The entry matches the exact branch name, user, and host of the creator, and is folded and validated in the
+// same way as an insertion into the "dolt_branch_control" table, although the creator does not need to be an admin to
+// add it. Nothing is added when the creator is already an admin on the branch, when the entry would grant admin over
+// one of the EscalationConstraints, when the AutoGrantMode is off, or when the context does not belong to a SQL session.
+func GrantCreatedBranch(ctx context.Context, branchName string) error {
+	if !enabled || currentAutoGrantMode() == AutoGrantMode_Off {
+		return nil
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	value := AccessValue{
+		Branch:      strings.ToLower(FoldExpression(escapeExpression(branchName))),
+		User:        FoldExpression(escapeExpression(user)),
+		Host:        strings.ToLower(FoldExpression(escapeExpression(host))),
+		Permissions: autoGrantPermissions,
+		Operations:  Operations_All,
+	}
+	if len(value.Branch) > math.MaxUint16 || len(value.User) > math.MaxUint16 || len(value.Host) > math.MaxUint16 {
+		return ErrExpressionsTooLong.New(value.Branch, value.User, value.Host)
+	}
+	// The branch has already been created, so a grant that only the super user could add is skipped rather than failing
+	if CheckEscalation(ctx, value.Branch, value.Permissions) != nil {
+		return nil
+	}
+
+	access := StaticController.Access
+	access.RWMutex.Lock()
+	// An existing entry that makes the creator an admin already covers everything that the new entry would grant
+	if _, perms := access.Match(strings.ToLower(branchName), user, strings.ToLower(host)); perms&Permissions_Admin == Permissions_Admin ||
+		access.GetIndex(value.Branch, value.User, value.Host) != -1 {
+		access.RWMutex.Unlock()
+		return nil
+	}
+	access.Insert(value)
+	recordTransaction(ctx, []func(){func() {
+		access.RWMutex.Lock()
+		defer access.RWMutex.Unlock()
+		access.Delete(value.Branch, value.User, value.Host)
+	}})
+	access.RWMutex.Unlock()
+
+	if InTransaction(ctx) {
+		return nil
+	}
+	return SaveData(ctx)
+}
+
+// RevokeDeletedBranch removes the entries that GrantCreatedBranch added for the given branch, which has just been
+// deleted. As the tables do not record how an entry was added, every entry with the exact form of an automatic grant
+// is removed: one that matches only the given branch, a single user and host, and grants write and admin on all
+// operations without any further restrictions. Nothing is removed unless the AutoGrantMode is cleanup.
+func RevokeDeletedBranch(ctx context.Context, branchName string) error {
+	if !enabled || currentAutoGrantMode() != AutoGrantMode_Cleanup {
+		return nil
+	}
+	if GetBranchAwareSession(ctx) == nil {
+		return nil
+	}
+	branch := strings.ToLower(FoldExpression(escapeExpression(branchName)))
+
+	access := StaticController.Access
+	access.RWMutex.Lock()
+	var revoked []AccessValue
+	for _, value := range access.Values {
+		if value.Branch == branch && isAutoGrant(value) {
+			revoked = append(revoked, value)
+		}
+	}
+	undos := make([]func(), len(revoked))
+	for i, value := range revoked {
+		access.Delete(value.Branch, value.User, value.Host)
+		value := value
+		undos[i] = func() {
+			access.RWMutex.Lock()
+			defer access.RWMutex.Unlock()
+			if access.GetIndex(value.Branch, value.User, value.Host) == -1 {
+				access.Insert(value)
+			}
+		}
+	}
+	recordTransaction(ctx, undos)
+	access.RWMutex.Unlock()
+
+	if len(revoked) == 0 || InTransaction(ctx) {
+		return nil
+	}
+	return SaveData(ctx)
+}
+
+// isAutoGrant returns whether the given entry has the form of an entry added by GrantCreatedBranch.
+func isAutoGrant(value AccessValue) bool {
+	return value.Permissions == autoGrantPermissions && value.Operations == Operations_All && value.Priority == 0 &&
+		value.Window == (Window{}) && !value.RequiresApproval && value.ExpiresAt == 0 && value.Refs == 0 && len(value.Table) == 0 &&
+		isLiteralExpression(value.User) && isLiteralExpression(value.Host)
+}
+
+// escapeExpression returns an expression that only matches the given string, by escaping every character that would
+// otherwise be read as a wildcard or an escape.
+func escapeExpression(str string) string {
+	var sb strings.Builder
+	sb.Grow(len(str))
+	for _, r := range str {
+		switch r {
+		case '\\', '_', '%':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// isLiteralExpression returns whether the given expression only matches a single string, as it has no unescaped
+// wildcards.
+func isLiteralExpression(expr string) bool {
+	escaped := false
+	for _, r := range expr {
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '_', '%':
+			return false
+		}
+	}
+	return true
+}
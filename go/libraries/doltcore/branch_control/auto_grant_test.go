@@ -0,0 +1,102 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoGrant(t *testing.T) {
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{{
+		Name:    AutoGrantVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemEnumType(AutoGrantVariable, AutoGrantModes...),
+		Default: string(AutoGrantMode_Off),
+	}})
+	enableForTest(t)
+	ctx := testSessionContext{Context: context.Background(), user: "Al_ice", host: "LocalHost"}
+	access := StaticController.Access
+
+	require.NoError(t, GrantCreatedBranch(ctx, "off"))
+	assert.Empty(t, access.Values)
+
+	require.NoError(t, sql.SystemVariables.SetGlobal(AutoGrantVariable, string(AutoGrantMode_On)))
+	defer sql.SystemVariables.SetGlobal(AutoGrantVariable, string(AutoGrantMode_Off))
+	require.NoError(t, GrantCreatedBranch(ctx, "Feature%1"))
+	require.Len(t, access.Values, 1)
+	assert.Equal(t, AccessValue{Branch: "feature\\%1", User: "Al\\_ice", Host: "localhost", Permissions: Permissions_Write | Permissions_Admin,
+		Operations: Operations_All}, access.Values[0])
+	// The grant is written to the binlog, so that it is saved like any other insertion
+	rows := access.binlog.Rows()
+	require.Len(t, rows, 1)
+	assert.True(t, rows[0].IsInsert)
+	assert.Equal(t, "feature\\%1", rows[0].Branch)
+
+	_, perms := access.Match("feature%1", "Al_ice", "localhost")
+	assert.Equal(t, Permissions_Write|Permissions_Admin, perms)
+	_, perms = access.Match("featurex1", "Al_ice", "localhost")
+	assert.Zero(t, perms)
+	_, perms = access.Match("feature%1", "Alxice", "localhost")
+	assert.Zero(t, perms)
+
+	// Creators that are already admins are not granted anything, including the super user
+	require.NoError(t, GrantCreatedBranch(ctx, "feature%1"))
+	require.NoError(t, GrantCreatedBranch(testSessionContext{Context: context.Background(), user: "root", host: "localhost"}, "rootbranch"))
+	access.Insert(AccessValue{Branch: "owned%", User: "Al_ice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	require.NoError(t, GrantCreatedBranch(ctx, "owned1"))
+	assert.Len(t, access.Values, 2)
+
+	// Contexts without a session are not granted anything
+	require.NoError(t, GrantCreatedBranch(context.Background(), "nosession"))
+	assert.Len(t, access.Values, 2)
+
+	// Deletion only removes the grant in cleanup mode, and leaves entries that were not in the form of a grant
+	access.Insert(AccessValue{Branch: "feature\\%1", User: "%", Host: "%", Permissions: Permissions_Write | Permissions_Admin, Operations: Operations_All})
+	require.NoError(t, RevokeDeletedBranch(ctx, "feature%1"))
+	assert.Len(t, access.Values, 3)
+	require.NoError(t, sql.SystemVariables.SetGlobal(AutoGrantVariable, string(AutoGrantMode_Cleanup)))
+	require.NoError(t, RevokeDeletedBranch(ctx, "FEATURE%1"))
+	require.Len(t, access.Values, 2)
+	assert.Equal(t, -1, access.GetIndex("feature\\%1", "Al\\_ice", "localhost"))
+	assert.NotEqual(t, -1, access.GetIndex("feature\\%1", "%", "%"))
+}
+
+func TestEscapeExpression(t *testing.T) {
+	tests := []struct {
+		str     string
+		escaped string
+	}{
+		{"main", "main"},
+		{"feature_1", "feature\\_1"},
+		{"100%", "100\\%"},
+		{"back\\slash", "back\\\\slash"},
+	}
+	for _, test := range tests {
+		t.Run(test.str, func(t *testing.T) {
+			escaped := escapeExpression(test.str)
+			assert.Equal(t, test.escaped, escaped)
+			assert.Equal(t, escaped, FoldExpression(escaped))
+			assert.True(t, isLiteralExpression(escaped))
+		})
+	}
+	assert.False(t, isLiteralExpression("feature_1"))
+	assert.False(t, isLiteralExpression("%"))
+}
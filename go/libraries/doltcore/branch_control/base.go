@@ -0,0 +1,176 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+// The base source contains rules that are loaded read-only when the server starts, such as those that are mounted into
+// a container or served by a configuration service. Its rules are layered beneath the controller's tables, which then
+// act as a writable overlay for runtime changes. Overlay entries take precedence over base entries according to the
+// MatchMode, and only the overlay is written to the branch control file.
+
+// BaseSourceEnvVar is the environment variable that provides the base source when one has not been configured.
+const BaseSourceEnvVar = "DOLT_BRANCH_CONTROL_BASE"
+
+// SourceStatus describes one of the sources that the controller's rules are loaded from.
+type SourceStatus struct {
+	// Name is either "base" or "overlay"
+	Name string
+	// Location is the base source or the path of the branch control file, which is empty when it was not configured
+	Location      string
+	AccessRows    int
+	NamespaceRows int
+	// DuplicateRows is the number of exact duplicate entries of both tables that were dropped when the source was loaded
+	DuplicateRows int
+}
+
+// Status returns the status of the base source, followed by the status of the overlay. The context's user must be an
+// admin over all branches.
+func (controller *Controller) Status(ctx context.Context) ([]SourceStatus, error) {
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	controller.Namespace.RWMutex.RLock()
+	defer controller.Namespace.RWMutex.RUnlock()
+
+	if err := controller.checkGlobalAdmin(ctx, ErrStatusPermissions); err != nil {
+		return nil, err
+	}
+
+	base := SourceStatus{Name: "base", Location: controller.baseSource}
+	if controller.Access.base != nil {
+		base.AccessRows = len(controller.Access.base.Values)
+		base.DuplicateRows += controller.Access.base.loadedDuplicates
+	}
+	if controller.Namespace.base != nil {
+		base.NamespaceRows = len(controller.Namespace.base.Values)
+		base.DuplicateRows += controller.Namespace.base.loadedDuplicates
+	}
+	overlay := SourceStatus{
+		Name:          "overlay",
+		Location:      controller.branchControlFilePath,
+		AccessRows:    len(controller.Access.Values),
+		NamespaceRows: len(controller.Namespace.Values),
+		DuplicateRows: controller.Access.loadedDuplicates + controller.Namespace.loadedDuplicates,
+	}
+	return []SourceStatus{base, overlay}, nil
+}
+
+// ReloadBase reloads the rules of the context's controller from its base source, replacing the previous base. The
+// overlay is unaffected. The context's user must be an admin over all branches.
+func ReloadBase(ctx context.Context) error {
+	//TODO: load from the context's controller
+	if !enabled {
+		return nil
+	}
+
+	StaticController.Access.RWMutex.RLock()
+	err := StaticController.checkGlobalAdmin(ctx, ErrReloadPermissions)
+	StaticController.Access.RWMutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	if len(StaticController.baseSource) == 0 {
+		return ErrNoBaseSource.New()
+	}
+	return StaticController.loadBase(ctx)
+}
+
+// loadBase loads the rules from the controller's base source, which must be set, and layers the controller's tables
+// over them. The previous base is kept if the rules cannot be loaded.
+func (controller *Controller) loadBase(ctx context.Context) error {
+	data, err := readBaseSource(ctx, controller.baseSource)
+	if err != nil {
+		return ErrLoadingBaseSource.New(controller.baseSource, err.Error())
+	}
+	base := CreateController(ctx)
+	if serial.GetFileID(data) == serial.BranchControlFileID {
+		err = base.deserialize(data)
+	} else if len(bytes.TrimSpace(data)) > 0 {
+		// Anything other than a branch control file is expected to be an exported JSON document. The import does not
+		// use the given context, as the base is not subject to the permissions of the user that requested the load.
+		err = base.Import(context.Background(), data, ImportMode_Replace)
+	}
+	if err != nil {
+		return ErrLoadingBaseSource.New(controller.baseSource, err.Error())
+	}
+
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	controller.Namespace.RWMutex.Lock()
+	defer controller.Namespace.RWMutex.Unlock()
+	controller.Access.base = base.Access
+	controller.Namespace.base = base.Namespace
+	return nil
+}
+
+// readBaseSource returns the contents of the given base source. The source may be a local path, a file, http, or https
+// URL, or a blobstore URL using either the gs or localbs scheme, where the final path element is the blob's key.
+func readBaseSource(ctx context.Context, source string) ([]byte, error) {
+	urlObj, err := url.Parse(source)
+	// Windows drive letters are parsed as single letter schemes, so they're treated as paths as well
+	if err != nil || len(urlObj.Scheme) <= 1 {
+		return os.ReadFile(source)
+	}
+
+	switch strings.ToLower(urlObj.Scheme) {
+	case "file":
+		return os.ReadFile(filepath.FromSlash(filepath.Join(urlObj.Host, urlObj.Path)))
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected response status `%s`", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	case "gs":
+		gcs, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer gcs.Close()
+		data, _, err := blobstore.GetBytes(ctx, blobstore.NewGCSBlobstore(gcs, urlObj.Host, ""), strings.TrimPrefix(urlObj.Path, "/"), blobstore.AllRange)
+		return data, err
+	case "localbs":
+		dir, key := path.Split(urlObj.Path)
+		bs := blobstore.NewLocalBlobstore(filepath.Join(urlObj.Host, filepath.FromSlash(dir)))
+		data, _, err := blobstore.GetBytes(ctx, bs, key, blobstore.AllRange)
+		return data, err
+	default:
+		return nil, fmt.Errorf("unsupported scheme `%s`", urlObj.Scheme)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+// baseTestData returns an exported document granting alice write access to main, with only alice able to create
+// release branches.
+func baseTestData(t *testing.T) []byte {
+	data, err := json.Marshal(ExportedData{
+		Access: []ExportedAccessRow{
+			{Branch: "main", User: "alice", Host: "%", Permissions: uint64(Permissions_Write), Operations: uint64(Operations_All)},
+		},
+		Namespace: []ExportedNamespaceRow{
+			{Branch: "release%", User: "alice", Host: "%"},
+		},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// newBaseTestController returns a controller that saves its overlay to the given file, with its base loaded from the
+// given source.
+func newBaseTestController(t *testing.T, source string, overlayPath string) *Controller {
+	controller := newJournalTestController(overlayPath)
+	if len(source) > 0 {
+		controller.baseSource = source
+		require.NoError(t, controller.loadBase(context.Background()))
+	}
+	return controller
+}
+
+// requirePermissions verifies the permissions that the controller grants to the user on the branch.
+func requirePermissions(t *testing.T, controller *Controller, mode MatchMode, branch string, user string, expected Permissions) {
+	_, perms := controller.Access.matchWithStrategy(branch, user, []string{"localhost"}, Operations_All, mode.strategy(), now())
+	require.Equal(t, expected, perms, "%s on %s using %s", user, branch, mode)
+}
+
+func TestBaseOnly(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.json")
+	overlayPath := filepath.Join(dir, "branch_control.db")
+	require.NoError(t, os.WriteFile(basePath, baseTestData(t), 0777))
+	controller := newBaseTestController(t, basePath, overlayPath)
+
+	requirePermissions(t, controller, MatchMode_Union, "main", "alice", Permissions_Write)
+	requirePermissions(t, controller, MatchMode_Union, "main", "bob", 0)
+	assert.True(t, controller.Namespace.CanCreate("release1", "alice", "localhost"))
+	assert.False(t, controller.Namespace.CanCreate("release1", "bob", "localhost"))
+	assert.True(t, controller.Namespace.CanCreate("other", "bob", "localhost"))
+
+	status, err := controller.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []SourceStatus{
+		{Name: "base", Location: basePath, AccessRows: 1, NamespaceRows: 1},
+		{Name: "overlay", Location: overlayPath, AccessRows: 0, NamespaceRows: 0},
+	}, status)
+
+	// Only the overlay is saved, so the base rules are not copied into the file
+	controller.Access.Insert(AccessValue{Branch: "dev", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, controller.save(false))
+	loaded := loadJournalTestController(t, overlayPath)
+	assert.Equal(t, []AccessValue{{Branch: "dev", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All}}, loaded.Access.Values)
+	assert.Empty(t, loaded.Namespace.Values)
+}
+
+func TestOverlayOnly(t *testing.T) {
+	overlayPath := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newBaseTestController(t, "", overlayPath)
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Namespace.Insert("release%", "alice", "%")
+
+	requirePermissions(t, controller, MatchMode_Union, "main", "alice", Permissions_Write)
+	assert.False(t, controller.Namespace.CanCreate("release1", "bob", "localhost"))
+	status, err := controller.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []SourceStatus{
+		{Name: "base"},
+		{Name: "overlay", Location: overlayPath, AccessRows: 1, NamespaceRows: 1},
+	}, status)
+}
+
+func TestBaseAndOverlay(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.json")
+	data, err := json.Marshal(ExportedData{
+		Access: []ExportedAccessRow{
+			{Branch: "main", User: "alice", Host: "%", Permissions: uint64(Permissions_Admin), Operations: uint64(Operations_All)},
+			{Branch: "%", User: "bob", Host: "%", Permissions: uint64(Permissions_Write), Operations: uint64(Operations_All)},
+			{Branch: "feature%", User: "carl", Host: "%", Permissions: uint64(Permissions_Write), Operations: uint64(Operations_All)},
+			{Branch: "%", User: "dave", Host: "%", Permissions: uint64(Permissions_Write), Operations: uint64(Operations_All), Priority: 1},
+			{Branch: "release", User: "dave", Host: "%", Permissions: uint64(Permissions_Write), Operations: uint64(Operations_All), Priority: 2},
+		},
+		Namespace: []ExportedNamespaceRow{
+			{Branch: "release%", User: "alice", Host: "%"},
+			{Branch: "dev%", User: "alice", Host: "%"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(basePath, data, 0777))
+	controller := newBaseTestController(t, basePath, filepath.Join(dir, "branch_control.db"))
+	// An overlay entry with the same expressions replaces the base entry in every mode
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "feature_", User: "carl", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "r%", User: "dave", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All, Priority: 1})
+	controller.Namespace.Insert("release%", "bob", "%")
+
+	for _, mode := range []MatchMode{MatchMode_Union, MatchMode_MostSpecific, MatchMode_Ordered} {
+		requirePermissions(t, controller, mode, "main", "alice", Permissions_Write)
+	}
+	// The union combines both sources
+	requirePermissions(t, controller, MatchMode_Union, "dev1", "bob", Permissions_Write|Permissions_Admin)
+	requirePermissions(t, controller, MatchMode_MostSpecific, "dev1", "bob", Permissions_Admin)
+	requirePermissions(t, controller, MatchMode_Union, "featurex", "carl", Permissions_Write|Permissions_Admin)
+	// Both entries are equally specific, so only the overlay entry is used
+	requirePermissions(t, controller, MatchMode_MostSpecific, "featurex", "carl", Permissions_Admin)
+	requirePermissions(t, controller, MatchMode_MostSpecific, "featurexy", "carl", Permissions_Write)
+	// Both entries have the same priority, so the overlay entry is used even though the base entry sorts first
+	requirePermissions(t, controller, MatchMode_Ordered, "rx", "dave", Permissions_Admin)
+	requirePermissions(t, controller, MatchMode_Ordered, "release", "dave", Permissions_Write)
+	requirePermissions(t, controller, MatchMode_Ordered, "other", "dave", Permissions_Write)
+
+	// The overlay's namespace entry is as specific as the base's, so it takes precedence
+	assert.True(t, controller.Namespace.CanCreate("release1", "bob", "localhost"))
+	assert.False(t, controller.Namespace.CanCreate("release1", "alice", "localhost"))
+	assert.True(t, controller.Namespace.CanCreate("dev1", "alice", "localhost"))
+	assert.False(t, controller.Namespace.CanCreate("dev1", "bob", "localhost"))
+	controller.Namespace.Insert("release1%", "carl", "%")
+	assert.True(t, controller.Namespace.CanCreate("release1", "carl", "localhost"))
+	assert.False(t, controller.Namespace.CanCreate("release1", "bob", "localhost"))
+	assert.True(t, controller.Namespace.CanCreate("release2", "bob", "localhost"))
+
+	// Reloading replaces the base while keeping the overlay
+	data, err = json.Marshal(ExportedData{
+		Access: []ExportedAccessRow{
+			{Branch: "%", User: "erin", Host: "%", Permissions: uint64(Permissions_Admin), Operations: uint64(Operations_All)},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(basePath, data, 0777))
+	require.NoError(t, controller.loadBase(context.Background()))
+	requirePermissions(t, controller, MatchMode_Union, "main", "erin", Permissions_Admin)
+	requirePermissions(t, controller, MatchMode_Union, "main", "alice", Permissions_Write)
+	requirePermissions(t, controller, MatchMode_Union, "other", "bob", 0)
+	assert.True(t, controller.Namespace.CanCreate("dev1", "bob", "localhost"))
+	status, err := controller.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, SourceStatus{Name: "base", Location: basePath, AccessRows: 1, NamespaceRows: 0}, status[0])
+	assert.Equal(t, 4, status[1].AccessRows)
+	assert.Equal(t, 2, status[1].NamespaceRows)
+
+	// A base that cannot be loaded leaves the previous base in place
+	require.NoError(t, os.WriteFile(basePath, []byte("{not json"), 0777))
+	assert.True(t, ErrLoadingBaseSource.Is(controller.loadBase(context.Background())))
+	requirePermissions(t, controller, MatchMode_Union, "main", "erin", Permissions_Admin)
+
+	// The status is only visible to admins over all branches
+	_, err = controller.Status(testSessionContext{Context: context.Background(), user: "dave", host: "localhost"})
+	assert.True(t, ErrStatusPermissions.Is(err))
+}
+
+func TestBaseSources(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	jsonData := baseTestData(t)
+
+	// A branch control file may be used as the base as well
+	filePath := filepath.Join(dir, "base.db")
+	fileController := newBaseTestController(t, "", filePath)
+	require.NoError(t, fileController.Import(ctx, jsonData, ImportMode_Replace))
+	require.NoError(t, fileController.save(true))
+
+	bs := blobstore.NewLocalBlobstore(dir)
+	_, err := blobstore.PutBytes(ctx, bs, "base_blob", jsonData)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/base.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(jsonData)
+	}))
+	defer server.Close()
+
+	for _, source := range []string{
+		filePath,
+		"file://" + filepath.ToSlash(filePath),
+		"localbs://" + filepath.ToSlash(filepath.Join(dir, "base_blob")),
+		server.URL + "/base.json",
+	} {
+		t.Run(source, func(t *testing.T) {
+			controller := newBaseTestController(t, source, "")
+			requirePermissions(t, controller, MatchMode_Union, "main", "alice", Permissions_Write)
+			assert.False(t, controller.Namespace.CanCreate("release1", "bob", "localhost"))
+		})
+	}
+
+	for _, source := range []string{
+		server.URL + "/missing.json",
+		filepath.Join(dir, "missing.json"),
+		"ftp://example.com/base.json",
+	} {
+		t.Run(source, func(t *testing.T) {
+			controller := newJournalTestController("")
+			controller.baseSource = source
+			assert.True(t, ErrLoadingBaseSource.Is(controller.loadBase(ctx)))
+		})
+	}
+}
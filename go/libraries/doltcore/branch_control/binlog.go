@@ -32,13 +32,22 @@ type Binlog struct {
 	RWMutex *sync.RWMutex
 }
 
-// BinlogRow is a row within the Binlog.
+// BinlogRow is a row within the Binlog. Rows of the Access table's Binlog hold the entire entry, so that replaying the
+// rows reconstructs the table.
 type BinlogRow struct {
 	IsInsert    bool
 	Branch      string
 	User        string
 	Host        string
 	Permissions uint64
+	Operations  uint64
+	Priority    int64
+	Window      Window
+	// RequiresApproval, ExpiresAt, Refs, and Table are only set for rows of the Access table
+	RequiresApproval bool
+	ExpiresAt        int64
+	Refs             Refs
+	Table            string
 }
 
 // BinlogOverlay enables transactional use cases over Binlog. Unlike a Binlog, a BinlogOverlay requires external
@@ -53,13 +62,7 @@ type BinlogOverlay struct {
 func NewAccessBinlog(vals []AccessValue) *Binlog {
 	rows := make([]BinlogRow, len(vals))
 	for i, val := range vals {
-		rows[i] = BinlogRow{
-			IsInsert:    true,
-			Branch:      val.Branch,
-			User:        val.User,
-			Host:        val.Host,
-			Permissions: uint64(val.Permissions),
-		}
+		rows[i] = accessBinlogRow(true, val)
 	}
 	return &Binlog{
 		rows:    rows,
@@ -91,18 +94,23 @@ func (binlog *Binlog) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	binlog.RWMutex.RLock()
 	defer binlog.RWMutex.RUnlock()
 
+	return serializeBinlogRows(b, binlog.rows)
+}
+
+// serializeBinlogRows returns the offset for a Binlog containing the given rows written to the given builder.
+func serializeBinlogRows(b *flatbuffers.Builder, binlogRows []BinlogRow) flatbuffers.UOffsetT {
 	// Initialize row offset slice
-	rowOffsets := make([]flatbuffers.UOffsetT, len(binlog.rows))
+	rowOffsets := make([]flatbuffers.UOffsetT, len(binlogRows))
 	// Get each row's offset
-	for i, row := range binlog.rows {
+	for i, row := range binlogRows {
 		rowOffsets[i] = row.Serialize(b)
 	}
 	// Get the row vector
-	serial.BranchControlBinlogStartRowsVector(b, len(binlog.rows))
+	serial.BranchControlBinlogStartRowsVector(b, len(binlogRows))
 	for i := len(rowOffsets) - 1; i >= 0; i-- {
 		b.PrependUOffsetT(rowOffsets[i])
 	}
-	rows := b.EndVector(len(binlog.rows))
+	rows := b.EndVector(len(binlogRows))
 	// Write the binlog
 	serial.BranchControlBinlogStart(b)
 	serial.BranchControlBinlogAddRows(b, rows)
@@ -118,21 +126,38 @@ func (binlog *Binlog) Deserialize(fb *serial.BranchControlBinlog) error {
 	if len(binlog.rows) != 0 {
 		return fmt.Errorf("cannot deserialize to a non-empty binlog")
 	}
+	binlog.rows = deserializeBinlogRows(fb)
+	return nil
+}
+
+// deserializeBinlogRows returns the rows from the flatbuffers representation of a Binlog.
+func deserializeBinlogRows(fb *serial.BranchControlBinlog) []BinlogRow {
 	// Initialize the rows
-	binlog.rows = make([]BinlogRow, fb.RowsLength())
+	rows := make([]BinlogRow, fb.RowsLength())
 	// Read the rows
 	for i := 0; i < fb.RowsLength(); i++ {
 		serialBinlogRow := &serial.BranchControlBinlogRow{}
 		fb.Rows(serialBinlogRow, i)
-		binlog.rows[i] = BinlogRow{
+		rows[i] = BinlogRow{
 			IsInsert:    serialBinlogRow.IsInsert(),
 			Branch:      string(serialBinlogRow.Branch()),
 			User:        string(serialBinlogRow.User()),
 			Host:        string(serialBinlogRow.Host()),
 			Permissions: serialBinlogRow.Permissions(),
+			Operations:  serialBinlogRow.Operations(),
+			Priority:    serialBinlogRow.Priority(),
+			Window: Window{
+				Start: serialBinlogRow.WindowStart(),
+				End:   serialBinlogRow.WindowEnd(),
+				Days:  Days(serialBinlogRow.WindowDays()),
+			},
+			RequiresApproval: serialBinlogRow.RequiresApproval(),
+			ExpiresAt:        serialBinlogRow.ExpiresAt(),
+			Refs:             Refs(serialBinlogRow.Refs()),
+			Table:            string(serialBinlogRow.TablePattern()),
 		}
 	}
-	return nil
+	return rows
 }
 
 // NewOverlay returns a new BinlogOverlay for the calling Binlog.
@@ -195,11 +220,74 @@ func (binlog *Binlog) Rows() []BinlogRow {
 	return binlog.rows
 }
 
+// rowsFrom returns a copy of the rows beginning at the given index, along with the number of rows in the Binlog.
+func (binlog *Binlog) rowsFrom(start int) ([]BinlogRow, int) {
+	binlog.RWMutex.RLock()
+	defer binlog.RWMutex.RUnlock()
+
+	if start > len(binlog.rows) {
+		start = len(binlog.rows)
+	}
+	return append([]BinlogRow(nil), binlog.rows[start:]...), len(binlog.rows)
+}
+
+// insertAccess adds an insert entry for the given Access value to the Binlog.
+func (binlog *Binlog) insertAccess(value AccessValue) {
+	binlog.RWMutex.Lock()
+	defer binlog.RWMutex.Unlock()
+
+	binlog.rows = append(binlog.rows, accessBinlogRow(true, value))
+}
+
+// deleteAccess adds a delete entry for the given Access value to the Binlog.
+func (binlog *Binlog) deleteAccess(value AccessValue) {
+	binlog.RWMutex.Lock()
+	defer binlog.RWMutex.Unlock()
+
+	binlog.rows = append(binlog.rows, accessBinlogRow(false, value))
+}
+
+// accessBinlogRow returns the BinlogRow that records the insertion or deletion of the given Access value.
+func accessBinlogRow(isInsert bool, value AccessValue) BinlogRow {
+	return BinlogRow{
+		IsInsert:         isInsert,
+		Branch:           value.Branch,
+		User:             value.User,
+		Host:             value.Host,
+		Permissions:      uint64(value.Permissions),
+		Operations:       uint64(value.Operations),
+		Priority:         value.Priority,
+		Window:           value.Window,
+		RequiresApproval: value.RequiresApproval,
+		ExpiresAt:        value.ExpiresAt,
+		Refs:             value.Refs,
+		Table:            value.Table,
+	}
+}
+
+// accessValue returns the Access value recorded by the row.
+func (row *BinlogRow) accessValue() AccessValue {
+	return AccessValue{
+		Branch:           row.Branch,
+		User:             row.User,
+		Host:             row.Host,
+		Permissions:      Permissions(row.Permissions),
+		Operations:       Operations(row.Operations),
+		Priority:         row.Priority,
+		Window:           row.Window,
+		RequiresApproval: row.RequiresApproval,
+		ExpiresAt:        row.ExpiresAt,
+		Refs:             row.Refs,
+		Table:            row.Table,
+	}
+}
+
 // Serialize returns the offset for the BinlogRow written to the given builder.
 func (row *BinlogRow) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	branch := b.CreateString(row.Branch)
 	user := b.CreateString(row.User)
 	host := b.CreateString(row.Host)
+	table := b.CreateString(row.Table)
 
 	serial.BranchControlBinlogRowStart(b)
 	serial.BranchControlBinlogRowAddIsInsert(b, row.IsInsert)
@@ -207,6 +295,15 @@ func (row *BinlogRow) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	serial.BranchControlBinlogRowAddUser(b, user)
 	serial.BranchControlBinlogRowAddHost(b, host)
 	serial.BranchControlBinlogRowAddPermissions(b, row.Permissions)
+	serial.BranchControlBinlogRowAddOperations(b, row.Operations)
+	serial.BranchControlBinlogRowAddPriority(b, row.Priority)
+	serial.BranchControlBinlogRowAddWindowStart(b, row.Window.Start)
+	serial.BranchControlBinlogRowAddWindowEnd(b, row.Window.End)
+	serial.BranchControlBinlogRowAddWindowDays(b, uint8(row.Window.Days))
+	serial.BranchControlBinlogRowAddRequiresApproval(b, row.RequiresApproval)
+	serial.BranchControlBinlogRowAddExpiresAt(b, row.ExpiresAt)
+	serial.BranchControlBinlogRowAddRefs(b, uint8(row.Refs))
+	serial.BranchControlBinlogRowAddTablePattern(b, table)
 	return serial.BranchControlBinlogRowEnd(b)
 }
 
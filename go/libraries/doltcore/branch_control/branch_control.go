@@ -19,23 +19,62 @@ import (
 	goerrors "errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/gen/fb/serial"
 )
 
 var (
-	ErrIncorrectPermissions = errors.NewKind("`%s`@`%s` does not have the correct permissions on branch `%s`")
-	ErrCannotCreateBranch   = errors.NewKind("`%s`@`%s` cannot create a branch named `%s`")
-	ErrCannotDeleteBranch   = errors.NewKind("`%s`@`%s` cannot delete the branch `%s`")
-	ErrExpressionsTooLong   = errors.NewKind("expressions are too long [%q, %q, %q]")
-	ErrInsertingRow         = errors.NewKind("`%s`@`%s` cannot add the row [%q, %q, %q, %q]")
-	ErrUpdatingRow          = errors.NewKind("`%s`@`%s` cannot update the row [%q, %q, %q]")
-	ErrUpdatingToRow        = errors.NewKind("`%s`@`%s` cannot update the row [%q, %q, %q] to the new branch expression %q")
-	ErrDeletingRow          = errors.NewKind("`%s`@`%s` cannot delete the row [%q, %q, %q]")
+	ErrIncorrectPermissions    = errors.NewKind("`%s`@`%s` does not have the correct permissions on branch `%s`")
+	ErrCannotCreateBranch      = errors.NewKind("`%s`@`%s` cannot create a branch named `%s`")
+	ErrCannotDeleteBranch      = errors.NewKind("`%s`@`%s` cannot delete the branch `%s`")
+	ErrCannotReadBranch        = errors.NewKind("`%s`@`%s` cannot read the branch `%s`")
+	ErrCannotModifyTag         = errors.NewKind("`%s`@`%s` cannot create or delete the tag `%s`")
+	ErrCannotPushRef           = errors.NewKind("`%s`@`%s` cannot push to the remote ref `%s`")
+	ErrCannotFetchRef          = errors.NewKind("`%s`@`%s` cannot fetch the remote ref `%s`")
+	ErrInvalidRefs             = errors.NewKind("invalid refs `%d`")
+	ErrTablePatternTooLong     = errors.NewKind("table pattern is too long: `%s`")
+	ErrLoadingReplicatedBase   = errors.NewKind("unable to load the replicated branch control tables: %s")
+	ErrExpressionsTooLong      = errors.NewKind("expressions are too long [%q, %q, %q]")
+	ErrInsertingRow            = errors.NewKind("`%s`@`%s` cannot add the row [%q, %q, %q, %q]")
+	ErrUpdatingRow             = errors.NewKind("`%s`@`%s` cannot update the row [%q, %q, %q]")
+	ErrUpdatingToRow           = errors.NewKind("`%s`@`%s` cannot update the row [%q, %q, %q] to the new branch expression %q")
+	ErrDeletingRow             = errors.NewKind("`%s`@`%s` cannot delete the row [%q, %q, %q]")
+	ErrExportImportPermissions = errors.NewKind("`%s`@`%s` must be an admin on all branches to export or import branch control data")
+	ErrInvalidImportMode       = errors.NewKind("invalid import mode `%s`, expected `replace` or `merge`")
+	ErrImportingData           = errors.NewKind("unable to import branch control data: %s")
+	ErrPrunePermissions        = errors.NewKind("`%s`@`%s` must be an admin on all branches to prune branch control data")
+	ErrInvalidWindow           = errors.NewKind("invalid window from %d to %d seconds past midnight, the start must be before the end")
+	ErrInvalidWindowDays       = errors.NewKind("invalid window days `%d`")
+	ErrCompactPermissions      = errors.NewKind("`%s`@`%s` must be an admin on all branches to compact branch control data")
+	ErrStatusPermissions       = errors.NewKind("`%s`@`%s` must be an admin on all branches to view the branch control status")
+	ErrReloadPermissions       = errors.NewKind("`%s`@`%s` must be an admin on all branches to reload branch control data")
+	ErrNoBaseSource            = errors.NewKind("no base source has been configured for branch control data")
+	ErrLoadingBaseSource       = errors.NewKind("unable to load branch control data from `%s`: %s")
+	ErrNoPendingMove           = errors.NewKind("branch `%s` does not have a pending move")
+	ErrApprovingMove           = errors.NewKind("`%s`@`%s` must be an admin on branch `%s` to approve or reject its pending move")
+	ErrApprovingOwnMove        = errors.NewKind("`%s`@`%s` cannot approve their own proposed move of branch `%s`")
+	ErrMovePending             = errors.NewKind("branch `%s` already has a pending move to `%s`, which must be approved or rejected first")
+	ErrUnexpectedPendingMove   = errors.NewKind("the pending move of branch `%s` is to `%s` rather than the expected `%s`")
+	ErrAuditPermissions        = errors.NewKind("`%s`@`%s` must be an admin on all branches to view the permissions of other users")
+	ErrBranchFrozen            = errors.NewKind("branch `%s` is frozen by the branch expression %q: %s")
+	ErrEditingFrozenRow        = errors.NewKind("`%s`@`%s` cannot modify entries for the branch expression %q, as it affects the frozen branch expression %q")
+	ErrFreezePermissions       = errors.NewKind("`%s`@`%s` must be an admin on the branch expression %q to freeze or unfreeze it")
+	ErrNotFrozen               = errors.NewKind("the branch expression %q is not frozen")
+	ErrEmptyFreezeExpression   = errors.NewKind("cannot freeze an empty branch expression")
+	ErrDedupPermissions        = errors.NewKind("`%s`@`%s` must be an admin on all branches to deduplicate branch control data")
+	ErrChangingDefault         = errors.NewKind("`%s`@`%s` must be an admin on branch `%s` to change the default branch of database `%s`")
+	ErrEscalatingAdmin         = errors.NewKind("`%s`@`%s` cannot grant admin on the branch expression %q, as only the super user may grant admin over the escalation constraint %q")
+	ErrRolePermissions         = errors.NewKind("`%s`@`%s` must be an admin on all branches to modify branch control roles")
+	ErrEmptyRoleName           = errors.NewKind("role names may not be empty")
+	ErrNestedRole              = errors.NewKind("the role %q may not contain the role %q")
 )
 
 // Context represents the interface that must be inherited from the context.
@@ -48,11 +87,26 @@ type Context interface {
 
 // Controller is the central hub for branch control functions. This is passed within a context.
 type Controller struct {
-	Access    *Access
-	Namespace *Namespace
+	Access       *Access
+	Namespace    *Namespace
+	PendingMoves *PendingMoves
+	Freezes      *Freezes
+
+	// DefaultBranchChanges is the history of changes to the default branch of every database
+	DefaultBranchChanges *DefaultBranchChanges
+	// Roles contains the members of the roles that entries of the Access table may reference
+	Roles *Roles
 
 	branchControlFilePath string
 	doltConfigDirPath     string
+	// baseSource is the location of the read-only rules that the tables are layered over, which may be empty
+	baseSource string
+
+	// saveMutex serializes writes to the branch control file, while journal tracks what the file already contains
+	saveMutex *sync.Mutex
+	journal   journalState
+	// replicationTargets are given a snapshot of the controller after every save. Guarded by the save mutex.
+	replicationTargets []ReplicationTarget
 }
 
 // TODO: delete me
@@ -75,19 +129,38 @@ func CreateController(ctx context.Context) *Controller {
 func CreateControllerWithSuperUser(ctx context.Context, superUser string, superHost string) *Controller {
 	//TODO: put in the context
 	accessTbl := newAccess(superUser, superHost)
+	rolesTbl := newRoles()
+	accessTbl.roles = rolesTbl
 	return &Controller{
-		Access:    accessTbl,
-		Namespace: newNamespace(accessTbl, superUser, superHost),
+		Access:               accessTbl,
+		Namespace:            newNamespace(accessTbl, superUser, superHost),
+		PendingMoves:         newPendingMoves(),
+		Freezes:              newFreezes(),
+		DefaultBranchChanges: newDefaultBranchChanges(),
+		Roles:                rolesTbl,
+		saveMutex:            &sync.Mutex{},
 	}
 }
 
-// LoadData loads the data from the given location into the controller.
-func LoadData(ctx context.Context, branchControlFilePath string, doltConfigDirPath string) error {
+// LoadData loads the data from the given location into the controller. When a base source is given, or is set through
+// the environment, its rules are loaded as a read-only base, with the data from the given location acting as a
+// writable overlay.
+func LoadData(ctx context.Context, branchControlFilePath string, baseSource string, doltConfigDirPath string) error {
 	//TODO: load into the context's controller
 	if !enabled {
 		return nil
 	}
 
+	if len(baseSource) == 0 {
+		baseSource = os.Getenv(BaseSourceEnvVar)
+	}
+	if len(baseSource) != 0 {
+		StaticController.baseSource = baseSource
+		if err := StaticController.loadBase(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Do not attempt to load from an empty file path
 	if len(branchControlFilePath) == 0 {
 		return nil
@@ -95,10 +168,44 @@ func LoadData(ctx context.Context, branchControlFilePath string, doltConfigDirPa
 
 	StaticController.branchControlFilePath = branchControlFilePath
 	StaticController.doltConfigDirPath = doltConfigDirPath
-	data, err := os.ReadFile(branchControlFilePath)
+	if err := StaticController.load(); err != nil {
+		return err
+	}
+	StaticController.saveMutex.Lock()
+	defer StaticController.saveMutex.Unlock()
+	StaticController.replicate()
+	return nil
+}
+
+// LoadFile returns a new controller containing the data from the given branch control file, regardless of whether branch
+// control is enabled, so that the file may be managed without running a server. The base is not loaded, as the returned
+// controller only represents the contents of the file, which SaveFile writes back to. Missing files are treated as empty.
+func LoadFile(ctx context.Context, branchControlFilePath string, doltConfigDirPath string) (*Controller, error) {
+	controller := CreateController(ctx)
+	controller.branchControlFilePath = branchControlFilePath
+	controller.doltConfigDirPath = doltConfigDirPath
+	if err := controller.load(); err != nil {
+		return nil, err
+	}
+	return controller, nil
+}
+
+// SaveFile writes the controller to the file that it was loaded from, as a single snapshot.
+func (controller *Controller) SaveFile() error {
+	return controller.save(true)
+}
+
+// load loads the data from the controller's file, which must be set.
+func (controller *Controller) load() error {
+	data, err := os.ReadFile(controller.branchControlFilePath)
 	if err != nil && !goerrors.Is(err, os.ErrNotExist) {
 		return err
 	}
+	return controller.deserialize(data)
+}
+
+// deserialize loads the contents of a branch control file into the controller's tables, which must be empty.
+func (controller *Controller) deserialize(data []byte) error {
 	// Nothing to load so we can return
 	if len(data) == 0 {
 		return nil
@@ -107,7 +214,11 @@ func LoadData(ctx context.Context, branchControlFilePath string, doltConfigDirPa
 	if serial.GetFileID(data) != serial.BranchControlFileID {
 		return fmt.Errorf("unable to deserialize branch controller, unknown file ID `%s`", serial.GetFileID(data))
 	}
-	bc, err := serial.TryGetRootAsBranchControl(data, serial.MessagePrefixSz)
+	snapshot, tail, ok := nextMessage(data)
+	if !ok {
+		return fmt.Errorf("unable to deserialize branch controller, the file is truncated")
+	}
+	bc, err := serial.TryGetRootAsBranchControl(snapshot, serial.MessagePrefixSz)
 	if err != nil {
 		return err
 	}
@@ -120,12 +231,44 @@ func LoadData(ctx context.Context, branchControlFilePath string, doltConfigDirPa
 		return err
 	}
 	// The Deserialize functions acquire write locks, so we don't acquire them here
-	if err = StaticController.Access.Deserialize(access); err != nil {
+	if err = controller.Access.Deserialize(access); err != nil {
+		return err
+	}
+	if err = controller.Namespace.Deserialize(namespace); err != nil {
+		return err
+	}
+	controller.PendingMoves.RWMutex.Lock()
+	err = controller.PendingMoves.deserialize(bc)
+	controller.PendingMoves.RWMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	controller.Freezes.RWMutex.Lock()
+	err = controller.Freezes.deserialize(bc)
+	controller.Freezes.RWMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	controller.DefaultBranchChanges.RWMutex.Lock()
+	err = controller.DefaultBranchChanges.deserialize(bc)
+	controller.DefaultBranchChanges.RWMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	controller.Roles.RWMutex.Lock()
+	err = controller.Roles.deserialize(bc)
+	controller.Roles.RWMutex.Unlock()
+	if err != nil {
 		return err
 	}
-	if err = StaticController.Namespace.Deserialize(namespace); err != nil {
+	if err = controller.replayJournal(tail); err != nil {
 		return err
 	}
+	if duplicates := controller.Access.loadedDuplicates + controller.Namespace.loadedDuplicates; duplicates > 0 {
+		logrus.Warnf("dropped %d duplicate branch control entries while loading", duplicates)
+		// The snapshot in the file still contains the duplicates, so the next save replaces it
+		controller.journal = journalState{}
+	}
 	return nil
 }
 
@@ -136,30 +279,107 @@ func SaveData(ctx context.Context) error {
 		return nil
 	}
 
+	return StaticController.save(false)
+}
+
+// CompactData rewrites the file of the context's controller as a single snapshot, discarding the journal of changes
+// that follows the previous snapshot. The context's user must be an admin over all branches.
+func CompactData(ctx context.Context) error {
+	//TODO: load from the context's controller
+	if !enabled {
+		return nil
+	}
+
+	StaticController.Access.RWMutex.RLock()
+	err := StaticController.checkGlobalAdmin(ctx, ErrCompactPermissions)
+	StaticController.Access.RWMutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	return StaticController.save(true)
+}
+
+// save writes the changes made since the previous save to the controller's file. Changes are appended to the file as a
+// journal entry, unless a snapshot is forced or the journal has grown large enough to be compacted into a new snapshot.
+func (controller *Controller) save(forceSnapshot bool) (err error) {
 	// If we never set a save location then we just return
-	if len(StaticController.branchControlFilePath) == 0 {
+	if len(controller.branchControlFilePath) == 0 {
 		return nil
 	}
+	controller.saveMutex.Lock()
+	defer controller.saveMutex.Unlock()
+	start := time.Now()
+	defer func() {
+		recordSave(time.Since(start), err)
+		if err == nil {
+			controller.replicate()
+		}
+	}()
+
 	// Create the doltcfg directory if it doesn't exist
-	if len(StaticController.doltConfigDirPath) != 0 {
-		if _, err := os.Stat(StaticController.doltConfigDirPath); os.IsNotExist(err) {
-			if mkErr := os.Mkdir(StaticController.doltConfigDirPath, 0777); mkErr != nil {
+	if len(controller.doltConfigDirPath) != 0 {
+		if _, err := os.Stat(controller.doltConfigDirPath); os.IsNotExist(err) {
+			if mkErr := os.Mkdir(controller.doltConfigDirPath, 0777); mkErr != nil {
 				return mkErr
 			}
 		} else if err != nil {
 			return err
 		}
 	}
+	if !forceSnapshot {
+		if appended, err := controller.appendJournal(); err != nil || appended {
+			return err
+		}
+	}
+	return controller.writeSnapshot()
+}
+
+// writeSnapshot replaces the controller's file with a snapshot of both tables, the pending moves, the freezes, the
+// default branch changes, and the roles. Requires the save mutex to be held.
+func (controller *Controller) writeSnapshot() error {
+	data, snapshotJournal := controller.serializeSnapshot()
+	if err := os.WriteFile(controller.branchControlFilePath, data, 0777); err != nil {
+		// The file is in an unknown state, so the next save must write a snapshot as well
+		controller.journal = journalState{}
+		return err
+	}
+	controller.journal = snapshotJournal
+	return nil
+}
+
+// serializeSnapshot returns the contents of a branch control file containing only a snapshot of the controller, along
+// with the state of the journal that the snapshot represents.
+func (controller *Controller) serializeSnapshot() ([]byte, journalState) {
+	controller.Access.RWMutex.RLock()
+	controller.Namespace.RWMutex.RLock()
+	controller.PendingMoves.RWMutex.RLock()
+	controller.Freezes.RWMutex.RLock()
+	controller.DefaultBranchChanges.RWMutex.RLock()
+	controller.Roles.RWMutex.RLock()
 	b := flatbuffers.NewBuilder(1024)
-	// The Serialize functions acquire read locks, so we don't acquire them here
-	accessOffset := StaticController.Access.Serialize(b)
-	namespaceOffset := StaticController.Namespace.Serialize(b)
+	accessOffset := controller.Access.serialize(b)
+	namespaceOffset := controller.Namespace.serialize(b)
+	pendingOffset := controller.PendingMoves.serialize(b)
+	freezesOffset := controller.Freezes.serialize(b)
+	defaultBranchOffset := controller.DefaultBranchChanges.serialize(b)
+	rolesOffset := controller.Roles.serialize(b)
 	serial.BranchControlStart(b)
 	serial.BranchControlAddAccessTbl(b, accessOffset)
 	serial.BranchControlAddNamespaceTbl(b, namespaceOffset)
+	serial.BranchControlAddPendingMoves(b, pendingOffset)
+	serial.BranchControlAddFreezes(b, freezesOffset)
+	serial.BranchControlAddDefaultBranchChanges(b, defaultBranchOffset)
+	serial.BranchControlAddRoles(b, rolesOffset)
 	root := serial.BranchControlEnd(b)
 	data := serial.FinishMessage(b, root, []byte(serial.BranchControlFileID))
-	return os.WriteFile(StaticController.branchControlFilePath, data, 0777)
+	snapshotJournal := controller.newJournalState()
+	controller.Roles.RWMutex.RUnlock()
+	controller.DefaultBranchChanges.RWMutex.RUnlock()
+	controller.Freezes.RWMutex.RUnlock()
+	controller.PendingMoves.RWMutex.RUnlock()
+	controller.Namespace.RWMutex.RUnlock()
+	controller.Access.RWMutex.RUnlock()
+	return data, snapshotJournal
 }
 
 // Reset is a temporary function just for testing. Once the controller is in the context, this will be unnecessary.
@@ -168,12 +388,27 @@ func Reset() {
 	StaticController = CreateControllerWithSuperUser(context.Background(), StaticController.Access.SuperUser, StaticController.Access.SuperHost)
 }
 
-// CheckAccess returns whether the given context has the correct permissions on its selected branch. In general, SQL
+// CheckAccess returns whether the given context has the correct permissions on its selected branch for the given
+// operation class. Only entries that include the operation class are considered. In general, SQL
 // statements will almost always return a *sql.Context, so any checks from the SQL path will correctly check for branch
 // permissions. However, not all CLI commands use *sql.Context, and therefore will not have any user associated with
 // the context. In these cases, CheckAccess will pass as we want to allow all local commands to ignore branch
-// permissions.
-func CheckAccess(ctx context.Context, flags Permissions) error {
+// permissions. Entries are only considered when their window contains the current time, according to the server's
+// clock in UTC. When branch control is in audit mode, denied operations are reported but allowed. Operations on frozen
+// branches are denied to everyone other than the super user, even in audit mode.
+func CheckAccess(ctx context.Context, flags Permissions, op Operations) error {
+	return CheckAccessAsOf(ctx, flags, op, now())
+}
+
+// CheckAccessAsOf is the same as CheckAccess, except that entries are only considered when their window contains the
+// given time. This allows an admin to preview whether an operation will be allowed at a different time.
+func CheckAccessAsOf(ctx context.Context, flags Permissions, op Operations, asOf time.Time) error {
+	return checkAccessAsOf(ctx, "", flags, op, asOf)
+}
+
+// checkAccessAsOf is the same as CheckAccessAsOf, except that entries whose table pattern matches the given table are
+// considered as well. An empty table only considers entries without a table pattern.
+func checkAccessAsOf(ctx context.Context, table string, flags Permissions, op Operations, asOf time.Time) error {
 	if !enabled {
 		return nil
 	}
@@ -192,18 +427,25 @@ func CheckAccess(ctx context.Context, flags Permissions) error {
 		return err
 	}
 	// Get the permissions for the branch, user, and host combination
-	_, perms := StaticController.Access.Match(branch, user, host)
+	matchStart := time.Now()
+	_, perms := StaticController.Access.MatchClientTableOperationAsOf(branch, table, user, host, op, asOf)
+	recordCheck(operationAction(op), time.Since(matchStart))
+	// A freeze overrides every entry, so it's consulted before the permissions are
+	if err = checkFrozen(ctx, user, host, branch, operationAction(op)); err != nil {
+		return err
+	}
 	// If either the flags match or the user is an admin for this branch, then we allow access
 	if (perms&flags == flags) || (perms&Permissions_Admin == Permissions_Admin) {
 		return nil
 	}
-	return ErrIncorrectPermissions.New(user, host, branch)
+	return enforce(ctx, Denial{User: user, Host: host, Branch: branch, Action: operationAction(op), Err: ErrIncorrectPermissions.New(user, host, branch)})
 }
 
 // CanCreateBranch returns whether the given context can create a branch with the given name. In general, SQL statements
 // will almost always return a *sql.Context, so any checks from the SQL path will be able to validate a branch's name.
 // However, not all CLI commands use *sql.Context, and therefore will not have any user associated with the context. In
-// these cases, CanCreateBranch will pass as we want to allow all local commands to freely create branches.
+// these cases, CanCreateBranch will pass as we want to allow all local commands to freely create branches. Denied
+// creations are allowed in audit mode.
 func CanCreateBranch(ctx context.Context, branchName string) error {
 	if !enabled {
 		return nil
@@ -217,16 +459,29 @@ func CanCreateBranch(ctx context.Context, branchName string) error {
 
 	user := branchAwareSession.GetUser()
 	host := branchAwareSession.GetHost()
-	if StaticController.Namespace.CanCreate(branchName, user, host) {
+	// The namespace restricts creation by the branch alone, so the client may create the branch under any form of its host
+	matchStart := time.Now()
+	canCreate := false
+	for _, hostForm := range ClientHostForms(host) {
+		if canCreate = StaticController.Namespace.CanCreate(branchName, user, hostForm); canCreate {
+			break
+		}
+	}
+	recordCheck("create_branch", time.Since(matchStart))
+	if err := checkFrozen(ctx, user, host, branchName, "create_branch"); err != nil {
+		return err
+	}
+	if canCreate {
 		return nil
 	}
-	return ErrCannotCreateBranch.New(user, host, branchName)
+	return enforce(ctx, Denial{User: user, Host: host, Branch: branchName, Action: "create_branch", Err: ErrCannotCreateBranch.New(user, host, branchName)})
 }
 
 // CanDeleteBranch returns whether the given context can delete a branch with the given name. In general, SQL statements
 // will almost always return a *sql.Context, so any checks from the SQL path will be able to validate a branch's name.
 // However, not all CLI commands use *sql.Context, and therefore will not have any user associated with the context. In
-// these cases, CanDeleteBranch will pass as we want to allow all local commands to freely delete branches.
+// these cases, CanDeleteBranch will pass as we want to allow all local commands to freely delete branches. Denied
+// deletions are allowed in audit mode.
 func CanDeleteBranch(ctx context.Context, branchName string) error {
 	if !enabled {
 		return nil
@@ -242,12 +497,103 @@ func CanDeleteBranch(ctx context.Context, branchName string) error {
 	user := branchAwareSession.GetUser()
 	host := branchAwareSession.GetHost()
 	// Get the permissions for the branch, user, and host combination
-	_, perms := StaticController.Access.Match(branchName, user, host)
+	matchStart := time.Now()
+	_, perms := StaticController.Access.MatchClientOperationAsOf(branchName, user, host, Operations_All, now())
+	recordCheck("delete_branch", time.Since(matchStart))
+	if err := checkFrozen(ctx, user, host, branchName, "delete_branch"); err != nil {
+		return err
+	}
 	// If the user has the write or admin flags, then we allow access
 	if (perms&Permissions_Write == Permissions_Write) || (perms&Permissions_Admin == Permissions_Admin) {
 		return nil
 	}
-	return ErrCannotDeleteBranch.New(user, host, branchName)
+	return enforce(ctx, Denial{User: user, Host: host, Branch: branchName, Action: "delete_branch", Err: ErrCannotDeleteBranch.New(user, host, branchName)})
+}
+
+// CanReadBranch returns whether the given context can read the branch with the given name, whether through a
+// branch-qualified database such as `mydb/branch`, or through anything else that resolves the branch for reading.
+// Reads are opt-in: a branch is only restricted once an entry grants the read permission on it, after which any
+// permission on the branch allows it to be read, so that the branch is hidden from users without any entries granting
+// them permissions. As with CheckAccess, contexts without a session are always allowed, and denied reads are allowed in
+// audit mode.
+func CanReadBranch(ctx context.Context, branchName string) error {
+	branchAwareSession, readable := canReadBranch(ctx, branchName)
+	if readable {
+		return nil
+	}
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	return enforce(ctx, Denial{User: user, Host: host, Branch: branchName, Action: "read_branch", Err: ErrCannotReadBranch.New(user, host, branchName)})
+}
+
+// IsBranchHidden returns whether the branch with the given name is hidden from the given context, which is the case
+// when CanReadBranch would deny reading it. Unlike CanReadBranch, no denial is reported, so that listings may skip the
+// branches that are hidden from the context. Branches are never hidden in audit mode.
+func IsBranchHidden(ctx context.Context, branchName string) bool {
+	_, readable := canReadBranch(ctx, branchName)
+	return !readable && currentEnforcementMode() != EnforcementMode_Audit
+}
+
+// MayHideBranches returns whether any branch may be hidden from the given context, so that callers may skip searching
+// for hidden branches when none can be.
+func MayHideBranches(ctx context.Context) bool {
+	return enabled && GetBranchAwareSession(ctx) != nil && currentEnforcementMode() != EnforcementMode_Audit
+}
+
+// canReadBranch returns whether the given context can read the branch with the given name, along with the session
+// that the permissions were matched for. The session is nil when the read is allowed without matching any permissions.
+func canReadBranch(ctx context.Context, branchName string) (Context, bool) {
+	if !enabled {
+		return nil, true
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow the read
+	if branchAwareSession == nil {
+		return nil, true
+	}
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+
+	// Get the permissions for the branch, user, and host combination
+	matchStart := time.Now()
+	asOf := now()
+	if !StaticController.Access.RestrictsReads(branchName, asOf) {
+		recordCheck("read_branch", time.Since(matchStart))
+		return branchAwareSession, true
+	}
+	_, perms := StaticController.Access.MatchClientOperationAsOf(branchName, branchAwareSession.GetUser(), branchAwareSession.GetHost(), Operations_All, asOf)
+	recordCheck("read_branch", time.Since(matchStart))
+	return branchAwareSession, perms&(Permissions_Read|Permissions_Write|Permissions_Admin) != 0
+}
+
+// BranchPermissions returns the permissions that the context's user has on the given branch, which are the same
+// permissions that CheckAccess uses for enforcement. The branch is folded in the same way as the branch expressions of
+// the system tables. The permissions are returned even when branch control is disabled, in which case they are not
+// enforced.
+func BranchPermissions(ctx context.Context, branch string) (Permissions, error) {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, where all operations are allowed
+	if branchAwareSession == nil {
+		return Permissions_Admin, nil
+	}
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+
+	_, perms := StaticController.Access.MatchClientOperationAsOf(strings.ToLower(FoldExpression(branch)), branchAwareSession.GetUser(), branchAwareSession.GetHost(), Operations_All, now())
+	return perms, nil
+}
+
+// UserBranchPermissions returns the permissions that the given user and host have on the given branch. As this allows
+// for auditing other users, the context's user must be an admin on all branches.
+func UserBranchPermissions(ctx context.Context, branch string, user string, host string) (Permissions, error) {
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+
+	if err := StaticController.checkGlobalAdmin(ctx, ErrAuditPermissions); err != nil {
+		return 0, err
+	}
+	_, perms := StaticController.Access.MatchClientOperationAsOf(strings.ToLower(FoldExpression(branch)), user, host, Operations_All, now())
+	return perms, nil
 }
 
 // GetBranchAwareSession returns the session contained within the context. If the context does NOT contain a session,
@@ -0,0 +1,29 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import "testing"
+
+// enableForTest enables branch control with an empty StaticController for the duration of the test. The previous
+// state is restored once the test and its subtests complete.
+func enableForTest(t *testing.T) {
+	wasEnabled := enabled
+	enabled = true
+	Reset()
+	t.Cleanup(func() {
+		enabled = wasEnabled
+		Reset()
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+)
+
+// Duplicate entries have the same effect as a single entry, but each is matched separately, and they make the tables
+// harder to audit. Exact duplicates are dropped whenever a table is deserialized, while entries that only differ in
+// their permissions are collapsed on request by Dedup, as that changes the rows that users see.
+
+// dropExactDuplicates removes every entry that is identical to an earlier entry, keeping the order of the remaining
+// entries, and returns the number of removed entries. The binlog is unchanged, as the table's contents are unchanged.
+// Requires external synchronization handling.
+func (tbl *Access) dropExactDuplicates() int {
+	seen := make(map[AccessValue]struct{}, len(tbl.Values))
+	kept := 0
+	for i, value := range tbl.Values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		tbl.Branches[kept], tbl.Users[kept], tbl.Hosts[kept], tbl.Values[kept] = tbl.Branches[i], tbl.Users[i], tbl.Hosts[i], value
+		tbl.Branches[kept].CollectionIndex = uint32(kept)
+		tbl.Users[kept].CollectionIndex = uint32(kept)
+		tbl.Hosts[kept].CollectionIndex = uint32(kept)
+		kept++
+	}
+	removed := len(tbl.Values) - kept
+	tbl.Branches = tbl.Branches[:kept]
+	tbl.Users = tbl.Users[:kept]
+	tbl.Hosts = tbl.Hosts[:kept]
+	tbl.Values = tbl.Values[:kept]
+	return removed
+}
+
+// dropExactDuplicates is the same as the Access table's function of the same name. Requires external synchronization
+// handling.
+func (tbl *Namespace) dropExactDuplicates() int {
+	seen := make(map[NamespaceValue]struct{}, len(tbl.Values))
+	kept := 0
+	for i, value := range tbl.Values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		tbl.Branches[kept], tbl.Users[kept], tbl.Hosts[kept], tbl.Values[kept] = tbl.Branches[i], tbl.Users[i], tbl.Hosts[i], value
+		tbl.Branches[kept].CollectionIndex = uint32(kept)
+		tbl.Users[kept].CollectionIndex = uint32(kept)
+		tbl.Hosts[kept].CollectionIndex = uint32(kept)
+		kept++
+	}
+	removed := len(tbl.Values) - kept
+	tbl.Branches = tbl.Branches[:kept]
+	tbl.Users = tbl.Users[:kept]
+	tbl.Hosts = tbl.Hosts[:kept]
+	tbl.Values = tbl.Values[:kept]
+	return removed
+}
+
+// Dedup collapses every group of Access entries that are identical except for their permissions into a single entry
+// with the union of the group's permissions, and removes every duplicate Namespace entry. Changes are written to the
+// binlogs as deletions of the grouped entries followed by the insertion of the collapsed entry. Entries of the base are
+// never modified. The context's user must be an admin over all branches. Returns the number of removed Access and
+// Namespace entries.
+func (controller *Controller) Dedup(ctx context.Context) (accessCount int, namespaceCount int, err error) {
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	controller.Namespace.RWMutex.Lock()
+	defer controller.Namespace.RWMutex.Unlock()
+
+	if err = controller.checkGlobalAdmin(ctx, ErrDedupPermissions); err != nil {
+		return 0, 0, err
+	}
+
+	// Groups are collected first, in the order of their first entry, as deleting an entry reorders the remaining entries
+	var accessGroups [][]AccessValue
+	accessGroupIndexes := make(map[AccessValue]int)
+	for _, value := range controller.Access.Values {
+		key := value
+		key.Permissions = 0
+		if idx, ok := accessGroupIndexes[key]; ok {
+			accessGroups[idx] = append(accessGroups[idx], value)
+		} else {
+			accessGroupIndexes[key] = len(accessGroups)
+			accessGroups = append(accessGroups, []AccessValue{value})
+		}
+	}
+	namespaceDuplicates := make(map[NamespaceValue]int)
+	var namespaceValues []NamespaceValue
+	for _, value := range controller.Namespace.Values {
+		if _, ok := namespaceDuplicates[value]; ok {
+			namespaceDuplicates[value]++
+		} else {
+			namespaceDuplicates[value] = 0
+			namespaceValues = append(namespaceValues, value)
+		}
+	}
+
+	// Collapsing entries is a rule edit, so it may not modify the entries of frozen branches
+	var editedExprs []string
+	for _, group := range accessGroups {
+		if len(group) > 1 {
+			editedExprs = append(editedExprs, group[0].Branch)
+		}
+	}
+	for _, value := range namespaceValues {
+		if namespaceDuplicates[value] > 0 {
+			editedExprs = append(editedExprs, value.Branch)
+		}
+	}
+	if err = controller.checkRuleEdit(ctx, editedExprs...); err != nil {
+		return 0, 0, err
+	}
+
+	for _, group := range accessGroups {
+		if len(group) == 1 {
+			continue
+		}
+		collapsed := group[0]
+		for _, value := range group {
+			collapsed.Permissions |= value.Permissions
+			controller.Access.deleteIndex(controller.Access.indexOfValue(value))
+		}
+		controller.Access.Insert(collapsed)
+		accessCount += len(group) - 1
+	}
+	for _, value := range namespaceValues {
+		// Every duplicate is identical, so deleting by expression removes one of them each time
+		for i := 0; i < namespaceDuplicates[value]; i++ {
+			controller.Namespace.Delete(value.Branch, value.User, value.Host)
+		}
+		namespaceCount += namespaceDuplicates[value]
+	}
+	return accessCount, namespaceCount, nil
+}
+
+// indexOfValue returns the index of the first entry that is identical to the given entry, or -1 if there is no such
+// entry. Unlike GetIndex, this distinguishes between entries that share their expressions. Requires external
+// synchronization handling.
+func (tbl *Access) indexOfValue(value AccessValue) int {
+	for i, existing := range tbl.Values {
+		if existing == value {
+			return i
+		}
+	}
+	return -1
+}
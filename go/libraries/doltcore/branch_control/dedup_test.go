@@ -0,0 +1,150 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupOnLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	alice := AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All}
+	bob := AccessValue{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All}
+	// Insert does not check for existing entries, which lets us write a file that contains duplicates
+	controller.Access.Insert(alice)
+	controller.Access.Insert(bob)
+	controller.Access.Insert(alice)
+	controller.Access.Insert(alice)
+	controller.Namespace.Insert("dev%", "bob", "%")
+	controller.Namespace.Insert("dev%", "bob", "%")
+	require.NoError(t, controller.save(true))
+
+	loaded := loadJournalTestController(t, path)
+	assert.Equal(t, []AccessValue{alice, bob}, loaded.Access.Values)
+	require.Len(t, loaded.Access.Branches, 2)
+	for i := range loaded.Access.Values {
+		assert.Equal(t, uint32(i), loaded.Access.Branches[i].CollectionIndex)
+		assert.Equal(t, uint32(i), loaded.Access.Users[i].CollectionIndex)
+		assert.Equal(t, uint32(i), loaded.Access.Hosts[i].CollectionIndex)
+	}
+	assert.Equal(t, 2, loaded.Access.loadedDuplicates)
+	require.Len(t, loaded.Namespace.Values, 1)
+	assert.Equal(t, 1, loaded.Namespace.loadedDuplicates)
+	_, perms := loaded.Access.Match("dev1", "bob", "localhost")
+	assert.Equal(t, Permissions_Admin, perms)
+	assert.True(t, loaded.Namespace.CanCreate("dev1", "bob", "localhost"))
+
+	status, err := loaded.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status, 2)
+	assert.Equal(t, 0, status[0].DuplicateRows)
+	assert.Equal(t, 2, status[1].AccessRows)
+	assert.Equal(t, 3, status[1].DuplicateRows)
+
+	// The file still holds the duplicates, so the next save must replace it with a new snapshot
+	assert.Equal(t, journalState{}, loaded.journal)
+	require.NoError(t, loaded.save(false))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, rest, ok := nextMessage(data)
+	require.True(t, ok)
+	assert.Empty(t, rest)
+	reloaded := loadJournalTestController(t, path)
+	assert.Equal(t, []AccessValue{alice, bob}, reloaded.Access.Values)
+	assert.Equal(t, 0, reloaded.Access.loadedDuplicates)
+	assert.Equal(t, 0, reloaded.Namespace.loadedDuplicates)
+}
+
+func TestDedup(t *testing.T) {
+	ctx := context.Background()
+	controller := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	// Entries that differ in anything other than their permissions are kept apart
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_Merge})
+	controller.Access.Insert(AccessValue{Branch: "%", User: "admin", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Namespace.Insert("dev%", "bob", "%")
+	controller.Namespace.Insert("dev%", "bob", "%")
+	controller.Namespace.Insert("main", "alice", "%")
+	binlogRows := len(controller.Access.binlog.Rows())
+
+	// Only a global admin may deduplicate
+	_, _, err := controller.Dedup(testSessionContext{Context: ctx, user: "bob", host: "localhost"})
+	assert.True(t, ErrDedupPermissions.Is(err))
+	require.Len(t, controller.Access.Values, 6)
+
+	accessCount, namespaceCount, err := controller.Dedup(testSessionContext{Context: ctx, user: "admin", host: "localhost"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, accessCount)
+	assert.Equal(t, 1, namespaceCount)
+	require.Len(t, controller.Access.Values, 4)
+	require.Len(t, controller.Namespace.Values, 2)
+	assert.ElementsMatch(t, []AccessValue{
+		{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin | Permissions_Write, Operations: Operations_All},
+		{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All},
+		{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_Merge},
+		{Branch: "%", User: "admin", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All},
+	}, controller.Access.Values)
+	for i, value := range controller.Access.Values {
+		assert.Equal(t, uint32(i), controller.Access.Branches[i].CollectionIndex, value.Branch)
+	}
+	_, perms := controller.Access.MatchOperation("main", "alice", "localhost", Operations_DirectDML)
+	assert.Equal(t, Permissions_Admin|Permissions_Write, perms)
+
+	// Every collapsed entry is deleted in the binlog, followed by the insertion of the collapsed entry
+	rows := controller.Access.binlog.Rows()[binlogRows:]
+	require.Len(t, rows, 4)
+	for _, row := range rows[:3] {
+		assert.False(t, row.IsInsert)
+		assert.Equal(t, "main", row.Branch)
+	}
+	assert.True(t, rows[3].IsInsert)
+	assert.Equal(t, uint64(Permissions_Admin|Permissions_Write), rows[3].Permissions)
+
+	// Running it again has nothing left to remove
+	accessCount, namespaceCount, err = controller.Dedup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, accessCount)
+	assert.Equal(t, 0, namespaceCount)
+}
+
+func TestDedupFrozenBranches(t *testing.T) {
+	enableForTest(t)
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "release1", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "release1", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	require.NoError(t, FreezeBranch(alice, "release%", ""))
+
+	// Collapsing the entries of a frozen branch is a rule edit, so only the super user may do so
+	_, _, err := StaticController.Dedup(alice)
+	assert.True(t, ErrEditingFrozenRow.Is(err))
+	assert.Len(t, StaticController.Access.Values, 3)
+	accessCount, _, err := StaticController.Dedup(root)
+	require.NoError(t, err)
+	assert.Equal(t, 1, accessCount)
+	assert.Len(t, StaticController.Access.Values, 2)
+}
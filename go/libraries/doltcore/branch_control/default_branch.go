@@ -0,0 +1,175 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// The default branch of a database is the branch that new sessions and clones begin on, so changing it affects every
+// user of the database. A user must therefore be an admin on both the current and the new default branch to change it,
+// and every change is recorded along with the user that made it. Changes are saved alongside the tables, in the same
+// way as the pending moves.
+
+// DefaultBranchChange is a change of a database's default branch, along with the user that made it.
+type DefaultBranchChange struct {
+	Database  string
+	OldBranch string
+	NewBranch string
+	User      string
+	Host      string
+	ChangedAt time.Time
+}
+
+// DefaultBranchChanges is the history of default branch changes across every database, in the order that they were made.
+type DefaultBranchChanges struct {
+	Values []DefaultBranchChange
+	// version is incremented on every modification, so that a save is able to determine whether the changes differ
+	version uint64
+	RWMutex *sync.RWMutex
+}
+
+// newDefaultBranchChanges returns a new DefaultBranchChanges.
+func newDefaultBranchChanges() *DefaultBranchChanges {
+	return &DefaultBranchChanges{
+		Values:  nil,
+		RWMutex: &sync.RWMutex{},
+	}
+}
+
+// Latest returns the most recent change of the given database's default branch, along with whether the default branch
+// has ever been changed. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *DefaultBranchChanges) Latest(database string) (DefaultBranchChange, bool) {
+	for i := len(tbl.Values) - 1; i >= 0; i-- {
+		if strings.EqualFold(tbl.Values[i].Database, database) {
+			return tbl.Values[i], true
+		}
+	}
+	return DefaultBranchChange{}, false
+}
+
+// add appends the given change to the history. Requires external synchronization handling.
+func (tbl *DefaultBranchChanges) add(change DefaultBranchChange) {
+	tbl.version++
+	tbl.Values = append(tbl.Values, change)
+}
+
+// serialize returns the offset of the vector of changes written to the given builder. Requires external
+// synchronization handling.
+func (tbl *DefaultBranchChanges) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, change := range tbl.Values {
+		database := b.CreateString(change.Database)
+		oldBranch := b.CreateString(change.OldBranch)
+		newBranch := b.CreateString(change.NewBranch)
+		user := b.CreateString(change.User)
+		host := b.CreateString(change.Host)
+		serial.BranchControlDefaultBranchChangeStart(b)
+		serial.BranchControlDefaultBranchChangeAddDatabase(b, database)
+		serial.BranchControlDefaultBranchChangeAddOldBranch(b, oldBranch)
+		serial.BranchControlDefaultBranchChangeAddNewBranch(b, newBranch)
+		serial.BranchControlDefaultBranchChangeAddUser(b, user)
+		serial.BranchControlDefaultBranchChangeAddHost(b, host)
+		serial.BranchControlDefaultBranchChangeAddChangedAt(b, change.ChangedAt.UnixMilli())
+		offsets[i] = serial.BranchControlDefaultBranchChangeEnd(b)
+	}
+	serial.BranchControlStartDefaultBranchChangesVector(b, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
+
+// deserialize replaces the changes with those of the given snapshot or journal entry. Requires external
+// synchronization handling.
+func (tbl *DefaultBranchChanges) deserialize(bc *serial.BranchControl) error {
+	values := make([]DefaultBranchChange, bc.DefaultBranchChangesLength())
+	for i := range values {
+		serialChange := &serial.BranchControlDefaultBranchChange{}
+		if _, err := bc.TryDefaultBranchChanges(serialChange, i); err != nil {
+			return err
+		}
+		values[i] = DefaultBranchChange{
+			Database:  string(serialChange.Database()),
+			OldBranch: string(serialChange.OldBranch()),
+			NewBranch: string(serialChange.NewBranch()),
+			User:      string(serialChange.User()),
+			Host:      string(serialChange.Host()),
+			ChangedAt: time.UnixMilli(serialChange.ChangedAt()).UTC(),
+		}
+	}
+	tbl.version++
+	tbl.Values = values
+	return nil
+}
+
+// ChangeDefaultBranch checks whether the context's user may change the default branch of the given database from the
+// old branch to the new branch, and records the change when they may. The user must be an admin on both branches,
+// although the old branch is not checked when it is empty, such as when the database has never had a default branch.
+// Denied changes are allowed in audit mode. As with CheckAccess, contexts without a session are always allowed, and
+// their changes are recorded without a user.
+func ChangeDefaultBranch(ctx context.Context, database string, oldBranch string, newBranch string) error {
+	if !enabled {
+		return nil
+	}
+	change := DefaultBranchChange{Database: database, OldBranch: oldBranch, NewBranch: newBranch, ChangedAt: now()}
+	if branchAwareSession := GetBranchAwareSession(ctx); branchAwareSession != nil {
+		change.User = branchAwareSession.GetUser()
+		change.Host = branchAwareSession.GetHost()
+		for _, branch := range []string{oldBranch, newBranch} {
+			if len(branch) == 0 {
+				continue
+			}
+			matchStart := time.Now()
+			isAdmin := isExpressionAdmin(strings.ToLower(FoldExpression(branch)), change.User, change.Host)
+			recordCheck("default_branch", time.Since(matchStart))
+			if isAdmin {
+				continue
+			}
+			denial := Denial{User: change.User, Host: change.Host, Branch: branch, Action: "default_branch",
+				Err: ErrChangingDefault.New(change.User, change.Host, branch, database)}
+			if err := enforce(ctx, denial); err != nil {
+				return err
+			}
+		}
+	}
+
+	StaticController.DefaultBranchChanges.RWMutex.Lock()
+	defer StaticController.DefaultBranchChanges.RWMutex.Unlock()
+	StaticController.DefaultBranchChanges.add(change)
+	return nil
+}
+
+// LatestDefaultBranchChange returns the most recent change of the given database's default branch, along with whether
+// the default branch has ever been changed. The context's user must be an admin over all branches.
+func (controller *Controller) LatestDefaultBranchChange(ctx context.Context, database string) (DefaultBranchChange, bool, error) {
+	controller.Access.RWMutex.RLock()
+	err := controller.checkGlobalAdmin(ctx, ErrStatusPermissions)
+	controller.Access.RWMutex.RUnlock()
+	if err != nil {
+		return DefaultBranchChange{}, false, err
+	}
+	controller.DefaultBranchChanges.RWMutex.RLock()
+	defer controller.DefaultBranchChanges.RWMutex.RUnlock()
+	change, ok := controller.DefaultBranchChanges.Latest(database)
+	return change, ok, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeDefaultBranch(t *testing.T) {
+	enableForTest(t)
+	changedAt := time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+	defer SetTimeSource(SetTimeSource(func() time.Time {
+		return changedAt
+	}))
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	StaticController.branchControlFilePath = path
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "dev", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, StaticController.save(true))
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	bob := testSessionContext{Context: context.Background(), user: "bob", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+
+	// Write permissions aren't enough, and both branches must be administered
+	assert.True(t, ErrChangingDefault.Is(ChangeDefaultBranch(bob, "mydb", "main", "dev")))
+	assert.True(t, ErrChangingDefault.Is(ChangeDefaultBranch(alice, "mydb", "main", "release")))
+	assert.True(t, ErrChangingDefault.Is(ChangeDefaultBranch(alice, "mydb", "release", "main")))
+	assert.Empty(t, StaticController.DefaultBranchChanges.Values)
+
+	require.NoError(t, ChangeDefaultBranch(alice, "mydb", "main", "dev"))
+	require.NoError(t, ChangeDefaultBranch(alice, "mydb", "dev", "main"))
+	require.NoError(t, ChangeDefaultBranch(context.Background(), "otherdb", "", "release"))
+	latest, ok, err := StaticController.LatestDefaultBranchChange(root, "MYDB")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, DefaultBranchChange{Database: "mydb", OldBranch: "dev", NewBranch: "main", User: "alice", Host: "localhost", ChangedAt: changedAt}, latest)
+	// Only a global admin may view the changes
+	_, _, err = StaticController.LatestDefaultBranchChange(alice, "mydb")
+	assert.True(t, ErrStatusPermissions.Is(err))
+	_, ok, err = StaticController.LatestDefaultBranchChange(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The changes are journaled, and survive a reload
+	require.NoError(t, StaticController.save(false))
+	loaded := loadJournalTestController(t, path)
+	assert.Equal(t, StaticController.DefaultBranchChanges.Values, loaded.DefaultBranchChanges.Values)
+	latest, ok = loaded.DefaultBranchChanges.Latest("otherdb")
+	require.True(t, ok)
+	assert.Equal(t, "", latest.OldBranch)
+	assert.Equal(t, "release", latest.NewBranch)
+	assert.Equal(t, "", latest.User)
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+)
+
+// EnforcementVariable is the name of the global system variable that selects the EnforcementMode of branch control.
+const EnforcementVariable = "dolt_branch_control_enforcement"
+
+// EnforcementMode determines what happens to operations that branch control denies. Only operations on branches are
+// affected, as modifications of the branch control tables themselves are always enforced.
+type EnforcementMode string
+
+const (
+	EnforcementMode_Enforce EnforcementMode = "enforce" // EnforcementMode_Enforce fails denied operations
+	EnforcementMode_Audit   EnforcementMode = "audit"   // EnforcementMode_Audit reports denied operations, but allows them to proceed
+)
+
+// EnforcementModes contains every EnforcementMode, in the order that they are presented to users.
+var EnforcementModes = []string{string(EnforcementMode_Enforce), string(EnforcementMode_Audit)}
+
+// currentEnforcementMode returns the EnforcementMode set by the system variable. Defaults to EnforcementMode_Enforce
+// when the variable has not been defined.
+func currentEnforcementMode() EnforcementMode {
+	_, val, ok := sql.SystemVariables.GetGlobal(EnforcementVariable)
+	if !ok {
+		return EnforcementMode_Enforce
+	}
+	if str, ok := val.(string); ok && EnforcementMode(strings.ToLower(str)) == EnforcementMode_Audit {
+		return EnforcementMode_Audit
+	}
+	return EnforcementMode_Enforce
+}
+
+// Denial is a decision by branch control that an operation is not allowed.
+type Denial struct {
+	User   string
+	Host   string
+	Branch string
+	// Action is the operation class that was checked, or the branch creation or deletion that was attempted
+	Action string
+	// Err is the error that fails the operation when it is enforced
+	Err error
+	// AuditOnly is true when the operation was allowed to proceed, as branch control is in audit mode
+	AuditOnly bool
+}
+
+// DenialObserver is given every Denial, whether or not it was enforced.
+type DenialObserver func(ctx context.Context, denial Denial)
+
+var (
+	denialObserver      DenialObserver = LogDenial
+	denialObserverMutex                = &sync.RWMutex{}
+)
+
+// SetDenialObserver replaces the observer of denials, returning the previous observer. By default, denials are logged
+// through LogDenial.
+func SetDenialObserver(observer DenialObserver) DenialObserver {
+	denialObserverMutex.Lock()
+	defer denialObserverMutex.Unlock()
+	previous := denialObserver
+	denialObserver = observer
+	return previous
+}
+
+// LogDenial logs the given denial as a warning, using the logger of the context when it is a *sql.Context.
+func LogDenial(ctx context.Context, denial Denial) {
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	if sqlCtx, ok := ctx.(*sql.Context); ok {
+		logger = sqlCtx.GetLogger()
+	}
+	logger.WithFields(logrus.Fields{
+		"user":       denial.User,
+		"host":       denial.Host,
+		"branch":     denial.Branch,
+		"action":     denial.Action,
+		"audit_only": denial.AuditOnly,
+	}).Warn(denial.Err.Error())
+}
+
+// enforce reports the given denial to the observer, and returns the error that fails the operation. In audit mode, the
+// denial is marked as audit only, and no error is returned so that the operation proceeds.
+func enforce(ctx context.Context, denial Denial) error {
+	denial.AuditOnly = currentEnforcementMode() == EnforcementMode_Audit
+	return report(ctx, denial)
+}
+
+// report reports the given denial to the observer, and returns the error that fails the operation unless the denial is
+// audit only.
+func report(ctx context.Context, denial Denial) error {
+	recordDenial(denial.Action)
+	denialObserverMutex.RLock()
+	observer := denialObserver
+	denialObserverMutex.RUnlock()
+	if observer != nil {
+		observer(ctx, denial)
+	}
+	if denial.AuditOnly {
+		return nil
+	}
+	return denial.Err
+}
+
+// operationAction returns the name of the given operation class, which is the same name used by the operations column
+// of the dolt_branch_control table.
+func operationAction(op Operations) string {
+	switch op {
+	case Operations_DirectDML:
+		return "direct_dml"
+	case Operations_Merge:
+		return "merge"
+	case Operations_RefMove:
+		return "ref_move"
+	case Operations_Tag:
+		return "tag"
+	default:
+		return "all"
+	}
+}
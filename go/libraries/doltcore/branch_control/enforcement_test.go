@@ -0,0 +1,111 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforcementModes(t *testing.T) {
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{{
+		Name:    EnforcementVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemEnumType(EnforcementVariable, EnforcementModes...),
+		Default: string(EnforcementMode_Enforce),
+	}})
+	enableForTest(t)
+	var denials []Denial
+	previous := SetDenialObserver(func(ctx context.Context, denial Denial) {
+		denials = append(denials, denial)
+	})
+	defer SetDenialObserver(previous)
+
+	StaticController.Namespace.Insert("other%", "root", "localhost")
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "bob", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All})
+	ctx := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	checks := func() []error {
+		_, refMoveErr := CheckRefMove(ctx, "main")
+		return []error{
+			refMoveErr,
+			CheckAccess(ctx, Permissions_Write, Operations_Merge),
+			CanCreateBranch(ctx, "otherbranch"),
+			CanDeleteBranch(ctx, "main"),
+			CanReadBranch(ctx, "main"),
+		}
+	}
+
+	for _, err := range checks() {
+		assert.Error(t, err)
+	}
+	enforcedDenials := denials
+	denials = nil
+	assert.True(t, IsBranchHidden(ctx, "main"))
+
+	require.NoError(t, sql.SystemVariables.SetGlobal(EnforcementVariable, string(EnforcementMode_Audit)))
+	defer sql.SystemVariables.SetGlobal(EnforcementVariable, string(EnforcementMode_Enforce))
+	for _, err := range checks() {
+		assert.NoError(t, err)
+	}
+	// Branches that can't be read are still listed in audit mode, where the reads are allowed
+	assert.False(t, MayHideBranches(ctx))
+	assert.False(t, IsBranchHidden(ctx, "main"))
+
+	// Both modes report the same denials, which are only marked as audit only in audit mode
+	require.Len(t, enforcedDenials, 5)
+	require.Len(t, denials, 5)
+	assert.Equal(t, []string{"ref_move", "merge", "create_branch", "delete_branch", "read_branch"},
+		[]string{denials[0].Action, denials[1].Action, denials[2].Action, denials[3].Action, denials[4].Action})
+	for i := range denials {
+		assert.False(t, enforcedDenials[i].AuditOnly)
+		assert.True(t, denials[i].AuditOnly)
+		assert.Equal(t, enforcedDenials[i].Err.Error(), denials[i].Err.Error())
+		// Errors hold the stack that created them, so they're compared by their messages
+		denials[i].AuditOnly, denials[i].Err, enforcedDenials[i].Err = false, nil, nil
+		assert.Equal(t, enforcedDenials[i], denials[i])
+	}
+
+	// Administration of branch control is enforced in audit mode, and is not reported as a denial
+	denials = nil
+	assert.True(t, ErrStatusPermissions.Is(StaticController.checkGlobalAdmin(ctx, ErrStatusPermissions)))
+	assert.Empty(t, denials)
+}
+
+func TestLogDenial(t *testing.T) {
+	hook := test.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+	denial := Denial{User: "alice", Host: "localhost", Branch: "main", Action: "merge", Err: ErrIncorrectPermissions.New("alice", "localhost", "main")}
+	LogDenial(context.Background(), denial)
+	denial.AuditOnly = true
+	LogDenial(context.Background(), denial)
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	for i, entry := range entries {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "`alice`@`localhost` does not have the correct permissions on branch `main`", entry.Message)
+		assert.Equal(t, "main", entry.Data["branch"])
+		assert.Equal(t, "merge", entry.Data["action"])
+		assert.Equal(t, i == 1, entry.Data["audit_only"])
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// EscalationVariable is the name of the global system variable that lists the escalation constraints, which are branch
+// expressions separated by commas.
+const EscalationVariable = "dolt_branch_control_escalation_constraints"
+
+// DefaultEscalationConstraints are the escalation constraints used when the system variable has not been defined.
+const DefaultEscalationConstraints = "%"
+
+// EscalationConstraints returns the folded branch expressions set by the system variable. Only the super user may add or
+// update an entry that grants admin on every branch matched by one of these expressions, even when the user making the
+// change is an admin over them through an existing entry.
+func EscalationConstraints() []string {
+	constraints := DefaultEscalationConstraints
+	if _, val, ok := sql.SystemVariables.GetGlobal(EscalationVariable); ok {
+		if str, ok := val.(string); ok {
+			constraints = str
+		}
+	}
+	var folded []string
+	for _, constraint := range strings.Split(constraints, ",") {
+		constraint = strings.ToLower(FoldExpression(strings.TrimSpace(constraint)))
+		if len(constraint) > 0 {
+			folded = append(folded, constraint)
+		}
+	}
+	return folded
+}
+
+// CheckEscalation returns an error if an entry with the given folded branch expression and permissions would grant admin
+// over any of the EscalationConstraints, and the context's user is not the super user. An entry covers a constraint
+// when its branch expression matches the constraint as though it were a branch name, so narrower grants are allowed.
+// Contexts without a session are always allowed.
+func CheckEscalation(ctx context.Context, branchExpr string, perms Permissions) error {
+	if !enabled || perms&Permissions_Admin != Permissions_Admin {
+		return nil
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	if StaticController.Access.isSuperUser(user, ClientHostForms(host)) {
+		return nil
+	}
+	expr := []MatchExpression{{CollectionIndex: 0, SortOrders: ParseExpression(branchExpr, sql.Collation_utf8mb4_0900_ai_ci)}}
+	for _, constraint := range EscalationConstraints() {
+		matches := Match(expr, constraint, sql.Collation_utf8mb4_0900_ai_ci)
+		matched := len(matches) > 0
+		indexPool.Put(matches)
+		if matched {
+			return ErrEscalatingAdmin.New(user, host, branchExpr, constraint)
+		}
+	}
+	return nil
+}
+
+// CheckRoleEscalation returns an error if adding a member to the given role would grant the member admin over any of the
+// EscalationConstraints through an entry that references the role, and the context's user is not the super user.
+// Contexts without a session are always allowed.
+func CheckRoleEscalation(ctx context.Context, role string) error {
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+	return StaticController.Access.checkRoleEscalation(ctx, role)
+}
+
+// checkRoleEscalation is the same as CheckRoleEscalation, except that the entries of the given table and its base are
+// checked. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Access) checkRoleEscalation(ctx context.Context, role string) error {
+	for access := tbl; access != nil; access = access.base {
+		userIndexes := Match(access.Users, RoleUser(role), sql.Collation_utf8mb4_0900_bin)
+		for _, collectionIndex := range userIndexes {
+			value := access.Values[collectionIndex]
+			if err := CheckEscalation(ctx, value.Branch, value.Permissions); err != nil {
+				indexPool.Put(userIndexes)
+				return err
+			}
+		}
+		indexPool.Put(userIndexes)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEscalation(t *testing.T) {
+	enableForTest(t)
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+
+	// Without the system variable, only % is constrained
+	assert.Equal(t, []string{"%"}, EscalationConstraints())
+	assert.True(t, ErrEscalatingAdmin.Is(CheckEscalation(alice, "%", Permissions_Admin)))
+	assert.True(t, ErrEscalatingAdmin.Is(CheckEscalation(alice, "%", Permissions_Admin|Permissions_Write)))
+	assert.NoError(t, CheckEscalation(alice, "%", Permissions_Write))
+	assert.NoError(t, CheckEscalation(alice, "dev%", Permissions_Admin))
+	assert.NoError(t, CheckEscalation(root, "%", Permissions_Admin))
+	assert.NoError(t, CheckEscalation(context.Background(), "%", Permissions_Admin))
+
+	// An entry is constrained when its expression covers a constraint, but not when it's narrower
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{{
+		Name:    EscalationVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemStringType(EscalationVariable),
+		Default: DefaultEscalationConstraints,
+	}})
+	require.NoError(t, sql.SystemVariables.SetGlobal(EscalationVariable, " Release% , ,main"))
+	defer func() {
+		require.NoError(t, sql.SystemVariables.SetGlobal(EscalationVariable, DefaultEscalationConstraints))
+	}()
+	assert.Equal(t, []string{"release%", "main"}, EscalationConstraints())
+	assert.True(t, ErrEscalatingAdmin.Is(CheckEscalation(alice, "%", Permissions_Admin)))
+	assert.True(t, ErrEscalatingAdmin.Is(CheckEscalation(alice, "rel%", Permissions_Admin)))
+	assert.True(t, ErrEscalatingAdmin.Is(CheckEscalation(alice, "main", Permissions_Admin)))
+	assert.NoError(t, CheckEscalation(alice, "release1", Permissions_Admin))
+	assert.NoError(t, CheckEscalation(alice, "dev%", Permissions_Admin))
+}
+
+func TestCheckRoleEscalation(t *testing.T) {
+	enableForTest(t)
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "@ops", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "dev%", User: "@devs", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "@team%", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+
+	// Joining a role grants every entry that references it, including those whose user expression matches the role
+	assert.True(t, ErrEscalatingAdmin.Is(CheckRoleEscalation(alice, "ops")))
+	assert.True(t, ErrEscalatingAdmin.Is(CheckRoleEscalation(alice, "teamblue")))
+	assert.NoError(t, CheckRoleEscalation(alice, "devs"))
+	assert.NoError(t, CheckRoleEscalation(alice, "unreferenced"))
+	assert.NoError(t, CheckRoleEscalation(root, "ops"))
+	assert.NoError(t, CheckRoleEscalation(context.Background(), "ops"))
+}
+
+func TestImportEscalation(t *testing.T) {
+	enableForTest(t)
+	controller := StaticController
+	controller.Access.Insert(AccessValue{Branch: "%", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "%", User: "@ops", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+
+	// Importing is held to the same constraints as inserting, for both entries and role members
+	adminData := []byte(`{"access":[{"branch":"%","user":"bob","host":"localhost","permissions":1}]}`)
+	assert.True(t, ErrEscalatingAdmin.Is(controller.Import(alice, adminData, ImportMode_Merge)))
+	roleData := []byte(`{"roles":[{"role":"ops","user":"bob","host":"localhost"}]}`)
+	assert.True(t, ErrEscalatingAdmin.Is(controller.Import(alice, roleData, ImportMode_Merge)))
+	assert.Len(t, controller.Access.Values, 2)
+	assert.Empty(t, controller.Roles.Values)
+
+	// Replacing removes the entries of the role, so its members may be imported alongside entries that are allowed
+	require.NoError(t, controller.Import(alice, []byte(`{"access":[{"branch":"dev%","user":"@ops","host":"%","permissions":1}],`+
+		`"roles":[{"role":"ops","user":"bob","host":"localhost"}]}`), ImportMode_Replace))
+	assert.Len(t, controller.Roles.Values, 1)
+	require.NoError(t, controller.Import(root, adminData, ImportMode_Merge))
+}
+
+func TestAutoGrantEscalation(t *testing.T) {
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{{
+		Name:    AutoGrantVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemEnumType(AutoGrantVariable, AutoGrantModes...),
+		Default: string(AutoGrantMode_Off),
+	}, {
+		Name:    EscalationVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemStringType(EscalationVariable),
+		Default: DefaultEscalationConstraints,
+	}})
+	enableForTest(t)
+	require.NoError(t, sql.SystemVariables.SetGlobal(AutoGrantVariable, string(AutoGrantMode_On)))
+	defer sql.SystemVariables.SetGlobal(AutoGrantVariable, string(AutoGrantMode_Off))
+	require.NoError(t, sql.SystemVariables.SetGlobal(EscalationVariable, "main"))
+	defer func() {
+		require.NoError(t, sql.SystemVariables.SetGlobal(EscalationVariable, DefaultEscalationConstraints))
+	}()
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	access := StaticController.Access
+
+	// Creating a branch that is constrained, such as by recreating it after it was deleted, does not grant admin over it
+	require.NoError(t, GrantCreatedBranch(alice, "MAIN"))
+	assert.Empty(t, access.Values)
+	require.NoError(t, GrantCreatedBranch(alice, "feature"))
+	require.Len(t, access.Values, 1)
+	assert.Equal(t, "feature", access.Values[0].Branch)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+)
+
+// Evaluation explains the permissions that a user and host have on a branch, so that a denial may be debugged without
+// reproducing the failing write.
+type Evaluation struct {
+	// Permissions are the effective permissions across all operations
+	Permissions Permissions
+	// SuperUser is true when the user and host are the super user that was configured for the server
+	SuperUser bool
+	// Freeze is the freeze that applies to the branch, which is only valid when Frozen is true
+	Freeze Freeze
+	Frozen bool
+	// Entries are the entries that granted their permissions, in the order that they were chosen
+	Entries []EvaluatedEntry
+}
+
+// EvaluatedEntry is an entry that contributed to an Evaluation.
+type EvaluatedEntry struct {
+	AccessValue
+	// Base is true when the entry was loaded from a base rather than inserted on this server
+	Base bool
+}
+
+// Evaluate returns the Evaluation of the given user and host on the given branch, using the StaticController. As this
+// allows for auditing other users, the context's user must be an admin on all branches.
+func Evaluate(ctx context.Context, user string, host string, branch string) (Evaluation, error) {
+	return StaticController.Evaluate(ctx, user, host, branch)
+}
+
+// Evaluate returns the Evaluation of the given user and host on the given branch. As this allows for auditing other
+// users, the context's user must be an admin on all branches.
+func (controller *Controller) Evaluate(ctx context.Context, user string, host string, branch string) (Evaluation, error) {
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+
+	if err := controller.checkGlobalAdmin(ctx, ErrAuditPermissions); err != nil {
+		return Evaluation{}, err
+	}
+	branch = strings.ToLower(FoldExpression(branch))
+	result := controller.Access.MatchClientDetailed(branch, user, host, Operations_All)
+	evaluation := Evaluation{
+		Permissions: result.Permissions,
+		SuperUser:   result.SuperUser,
+		Entries:     make([]EvaluatedEntry, len(result.Indexes)),
+	}
+	for i, collectionIndex := range result.Indexes {
+		evaluation.Entries[i] = EvaluatedEntry{
+			AccessValue: *controller.Access.value(collectionIndex),
+			Base:        controller.Access.isBase(collectionIndex),
+		}
+	}
+	// The Access table is locked before the freezes, in the same order as every other check
+	controller.Freezes.RWMutex.RLock()
+	evaluation.Freeze, evaluation.Frozen = controller.Freezes.Match(branch)
+	controller.Freezes.RWMutex.RUnlock()
+	return evaluation, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.json")
+	require.NoError(t, os.WriteFile(basePath, baseTestData(t), 0777))
+	controller := newBaseTestController(t, basePath, filepath.Join(dir, "branch_control.db"))
+	controller.Access.Insert(AccessValue{Branch: "%", User: "admin", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "ma%", User: "alice", Host: "localhost", Permissions: Permissions_Read, Operations: Operations_All})
+	controller.Freezes.put(Freeze{Branch: "main", User: "admin", Host: "localhost", Reason: "incident"})
+	admin := testSessionContext{Context: context.Background(), user: "admin", host: "localhost"}
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+
+	_, err := controller.Evaluate(alice, "alice", "localhost", "main")
+	assert.True(t, ErrAuditPermissions.Is(err))
+
+	evaluation, err := controller.Evaluate(admin, "alice", "localhost", "MAIN")
+	require.NoError(t, err)
+	assert.Equal(t, Permissions_Write|Permissions_Read, evaluation.Permissions)
+	assert.False(t, evaluation.SuperUser)
+	assert.True(t, evaluation.Frozen)
+	assert.Equal(t, "incident", evaluation.Freeze.Reason)
+	require.Len(t, evaluation.Entries, 2)
+	branches := map[string]bool{}
+	for _, entry := range evaluation.Entries {
+		branches[entry.Branch] = entry.Base
+	}
+	assert.Equal(t, map[string]bool{"main": true, "ma%": false}, branches)
+
+	evaluation, err = controller.Evaluate(admin, "bob", "localhost", "other")
+	require.NoError(t, err)
+	assert.Equal(t, Permissions(0), evaluation.Permissions)
+	assert.False(t, evaluation.Frozen)
+	assert.Empty(t, evaluation.Entries)
+}
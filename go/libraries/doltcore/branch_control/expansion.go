@@ -0,0 +1,94 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ExpandBranches returns the given branches that the expression would match when used as the branch expression of an
+// entry. The expression is folded in the same way as the tables fold their branch expressions, so that a pattern may be
+// validated before it is inserted.
+func ExpandBranches(expr string, branches []string) []string {
+	pattern := []MatchExpression{{
+		CollectionIndex: 0,
+		SortOrders:      ParseExpression(strings.ToLower(FoldExpression(expr)), sql.Collation_utf8mb4_0900_ai_ci),
+	}}
+	var matched []string
+	for _, branch := range branches {
+		if branchMatchesPattern(pattern, strings.ToLower(branch)) {
+			matched = append(matched, branch)
+		}
+	}
+	return matched
+}
+
+// ExpandUsers returns the users that the expression would match when used as the user expression of an entry, out of
+// every user that is named by the entries of the Access and Namespace tables and their bases, along with the members of
+// roles. The stored user expressions are matched as though they were user names, in the same way that Prune matches
+// branch expressions. Role users are never matched, as a role is not a user that may connect. The returned users are
+// sorted.
+func (controller *Controller) ExpandUsers(expr string) []string {
+	pattern := []MatchExpression{{
+		CollectionIndex: 0,
+		SortOrders:      ParseExpression(FoldExpression(expr), sql.Collation_utf8mb4_0900_bin),
+	}}
+
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	controller.Namespace.RWMutex.RLock()
+	defer controller.Namespace.RWMutex.RUnlock()
+	controller.Roles.RWMutex.RLock()
+	defer controller.Roles.RWMutex.RUnlock()
+
+	users := make(map[string]struct{})
+	for _, value := range controller.Access.Values {
+		users[value.User] = struct{}{}
+	}
+	for _, value := range controller.Namespace.Values {
+		users[value.User] = struct{}{}
+	}
+	if controller.Access.base != nil {
+		for _, value := range controller.Access.base.Values {
+			users[value.User] = struct{}{}
+		}
+	}
+	if controller.Namespace.base != nil {
+		for _, value := range controller.Namespace.base.Values {
+			users[value.User] = struct{}{}
+		}
+	}
+	for _, member := range controller.Roles.Values {
+		users[member.User] = struct{}{}
+	}
+	var matched []string
+	for user := range users {
+		if IsRoleUser(user) {
+			continue
+		}
+		matches := Match(pattern, user, sql.Collation_utf8mb4_0900_bin)
+		if len(matches) > 0 {
+			matched = append(matched, user)
+		}
+		if matches != nil {
+			indexPool.Put(matches)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandBranches(t *testing.T) {
+	branches := []string{"main", "Feature1", "feature_2", "featurex", "other"}
+	assert.Equal(t, []string{"Feature1", "feature_2", "featurex"}, ExpandBranches("FEATURE%", branches))
+	assert.Equal(t, []string{"feature_2"}, ExpandBranches("feature\\_%", branches))
+	assert.Equal(t, []string{"main"}, ExpandBranches("main", branches))
+	assert.Empty(t, ExpandBranches("missing%", branches))
+}
+
+func TestExpandUsers(t *testing.T) {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "Alan", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: RoleUser("admins"), Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Namespace.Insert("release%", "bob", "%")
+	controller.Roles.Insert(RoleMember{Role: "admins", User: "carl", Host: "%"})
+
+	assert.Equal(t, []string{"Alan", "alice", "bob", "carl"}, controller.ExpandUsers("%"))
+	// Users are case-sensitive, unlike branches
+	assert.Equal(t, []string{"alice"}, controller.ExpandUsers("al%"))
+	assert.Equal(t, []string{"carl"}, controller.ExpandUsers("c_rl"))
+	assert.Empty(t, controller.ExpandUsers("admins"))
+}
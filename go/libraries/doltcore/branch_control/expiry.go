@@ -0,0 +1,89 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entries of the Access table may be given an expiration, so that temporary grants lapse without anyone needing to
+// remember to remove them. An expired entry is ignored by matching as soon as it expires, while the sweeper removes
+// expired entries from the table in the background, so that they do not accumulate. Entries of the base are never
+// removed, as the base is read-only.
+
+// DefaultExpirySweepInterval is the interval at which the sweeper started by servers removes expired entries.
+const DefaultExpirySweepInterval = time.Minute
+
+// ExpiredAsOf returns whether the entry has expired at the given time.
+func (value AccessValue) ExpiredAsOf(asOf time.Time) bool {
+	return value.ExpiresAt != 0 && value.ExpiresAt <= asOf.UnixMilli()
+}
+
+// PruneExpired removes every entry of the Access table that has expired at the given time, writing each removal to the
+// binlog. Returns the number of removed entries.
+func (controller *Controller) PruneExpired(asOf time.Time) int {
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+
+	// Expired entries are collected first, as deleting an entry reorders the remaining entries
+	var expired []AccessValue
+	for _, value := range controller.Access.Values {
+		if value.ExpiredAsOf(asOf) {
+			expired = append(expired, value)
+		}
+	}
+	for _, value := range expired {
+		controller.Access.Delete(value.Branch, value.User, value.Host)
+	}
+	return len(expired)
+}
+
+// StartExpirySweeper periodically removes the expired entries of the Access table, saving the table whenever entries
+// are removed. Returns a function that stops the sweeper and waits for it to exit, which does nothing if branch control
+// is not enabled.
+func StartExpirySweeper(interval time.Duration) (stop func()) {
+	//TODO: sweep the context's controller
+	if !enabled {
+		return func() {}
+	}
+	controller := StaticController
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if removed := controller.PruneExpired(now()); removed > 0 {
+					if err := controller.save(false); err != nil {
+						logrus.Errorf("failed to save branch control after removing %d expired entries: %s", removed, err.Error())
+					}
+				}
+			}
+		}
+	}()
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchIgnoresExpiredEntries(t *testing.T) {
+	defer SetTimeSource(SetTimeSource(func() time.Time {
+		return wednesdayNoon
+	}))
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		ExpiresAt: wednesdayNoon.Add(time.Hour).UnixMilli()})
+	access.Insert(AccessValue{Branch: "%", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		ExpiresAt: wednesdayNoon.UnixMilli()})
+
+	matched, perms := access.Match("main", "alice", "localhost")
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Write, perms)
+	// The expiration is exclusive, so an entry expiring at the current time no longer applies
+	matched, _ = access.Match("main", "bob", "localhost")
+	assert.False(t, matched)
+	matched, _ = access.MatchOperationAsOf("main", "alice", "localhost", Operations_All, wednesdayNoon.Add(2*time.Hour))
+	assert.False(t, matched)
+}
+
+func TestPruneExpired(t *testing.T) {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		ExpiresAt: wednesdayNoon.UnixMilli()})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		ExpiresAt: wednesdayNoon.Add(time.Hour).UnixMilli()})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "carl", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+
+	assert.Equal(t, 0, controller.PruneExpired(wednesdayNoon.Add(-time.Second)))
+	assert.Equal(t, 1, controller.PruneExpired(wednesdayNoon))
+	require.Len(t, controller.Access.Values, 2)
+	assert.Equal(t, 1, controller.PruneExpired(wednesdayNoon.AddDate(1, 0, 0)))
+	require.Len(t, controller.Access.Values, 1)
+	assert.Equal(t, "carl", controller.Access.Values[0].User)
+	// Each removal is written to the binlog, so that the journal records it
+	assert.Len(t, controller.Access.binlog.Rows(), 5)
+}
+
+func TestExpirySweeper(t *testing.T) {
+	enableForTest(t)
+	defer SetTimeSource(SetTimeSource(func() time.Time {
+		return wednesdayNoon
+	}))
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	StaticController.branchControlFilePath = path
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		ExpiresAt: wednesdayNoon.UnixMilli()})
+	require.NoError(t, StaticController.save(true))
+
+	stop := StartExpirySweeper(time.Millisecond)
+	defer stop()
+	// The removal is saved, so that the entry is not restored when the file is next loaded
+	require.Eventually(t, func() bool {
+		loaded, err := LoadFile(context.Background(), path, "")
+		return err == nil && len(loaded.Access.Values) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
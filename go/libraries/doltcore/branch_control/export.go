@@ -0,0 +1,280 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ImportMode determines how imported data is combined with the data that is already present in the controller.
+type ImportMode string
+
+const (
+	ImportMode_Replace ImportMode = "replace" // ImportMode_Replace removes all existing entries before importing
+	ImportMode_Merge   ImportMode = "merge"   // ImportMode_Merge keeps existing entries, overwriting those that share expressions with imported entries
+)
+
+// ExportedData is the JSON representation of the Access and Namespace tables, along with the members of every role. The
+// super user is not included, as it is set by each server at startup.
+type ExportedData struct {
+	Access    []ExportedAccessRow    `json:"access"`
+	Namespace []ExportedNamespaceRow `json:"namespace"`
+	Roles     []ExportedRoleRow      `json:"roles,omitempty"`
+}
+
+// ExportedAccessRow is the JSON representation of an AccessValue.
+type ExportedAccessRow struct {
+	Branch           string `json:"branch"`
+	User             string `json:"user"`
+	Host             string `json:"host"`
+	Permissions      uint64 `json:"permissions"`
+	Operations       uint64 `json:"operations"`
+	Priority         int64  `json:"priority"`
+	WindowStart      uint32 `json:"window_start,omitempty"`
+	WindowEnd        uint32 `json:"window_end,omitempty"`
+	WindowDays       uint8  `json:"window_days,omitempty"`
+	RequiresApproval bool   `json:"requires_approval,omitempty"`
+	ExpiresAt        int64  `json:"expires_at,omitempty"`
+	Refs             uint8  `json:"refs,omitempty"`
+	TablePattern     string `json:"table_pattern,omitempty"`
+}
+
+// ExportedNamespaceRow is the JSON representation of a NamespaceValue.
+type ExportedNamespaceRow struct {
+	Branch string `json:"branch"`
+	User   string `json:"user"`
+	Host   string `json:"host"`
+}
+
+// ExportedRoleRow is the JSON representation of a RoleMember.
+type ExportedRoleRow struct {
+	Role string `json:"role"`
+	User string `json:"user"`
+	Host string `json:"host"`
+}
+
+// Export returns a JSON document containing every entry of the Access and Namespace tables, along with the members of
+// every role. The context's user must be an admin over all branches.
+func (controller *Controller) Export(ctx context.Context) ([]byte, error) {
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	controller.Namespace.RWMutex.RLock()
+	defer controller.Namespace.RWMutex.RUnlock()
+
+	if err := controller.checkGlobalAdmin(ctx, ErrExportImportPermissions); err != nil {
+		return nil, err
+	}
+	// Matching the context's user reads the roles, so they're only locked once the permissions have been checked
+	controller.Roles.RWMutex.RLock()
+	defer controller.Roles.RWMutex.RUnlock()
+
+	data := ExportedData{
+		Access:    make([]ExportedAccessRow, len(controller.Access.Values)),
+		Namespace: make([]ExportedNamespaceRow, len(controller.Namespace.Values)),
+	}
+	for i, value := range controller.Access.Values {
+		data.Access[i] = ExportedAccessRow{
+			Branch:           value.Branch,
+			User:             value.User,
+			Host:             value.Host,
+			Permissions:      uint64(value.Permissions),
+			Operations:       uint64(value.Operations),
+			Priority:         value.Priority,
+			WindowStart:      value.Window.Start,
+			WindowEnd:        value.Window.End,
+			WindowDays:       uint8(value.Window.Days),
+			RequiresApproval: value.RequiresApproval,
+			ExpiresAt:        value.ExpiresAt,
+			Refs:             uint8(value.Refs),
+			TablePattern:     value.Table,
+		}
+	}
+	for i, value := range controller.Namespace.Values {
+		data.Namespace[i] = ExportedNamespaceRow{
+			Branch: value.Branch,
+			User:   value.User,
+			Host:   value.Host,
+		}
+	}
+	for _, member := range controller.Roles.Values {
+		data.Roles = append(data.Roles, ExportedRoleRow{
+			Role: member.Role,
+			User: member.User,
+			Host: member.Host,
+		})
+	}
+	return json.Marshal(data)
+}
+
+// Import loads the given JSON document, as created by Export, into the Access and Namespace tables and the roles. The
+// context's user must be an admin over all branches. All entries are validated before any modifications are made, and
+// every modification of the tables is written to the binlog of its respective table.
+func (controller *Controller) Import(ctx context.Context, jsonData []byte, mode ImportMode) error {
+	if mode != ImportMode_Replace && mode != ImportMode_Merge {
+		return ErrInvalidImportMode.New(string(mode))
+	}
+	var data ExportedData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return ErrImportingData.New(err.Error())
+	}
+	// Fold every expression and verify that the lengths fit within an uint16, just as inserting through the tables would
+	for i, row := range data.Access {
+		branch, user, host, err := foldImportedExpressions(row.Branch, row.User, row.Host)
+		if err != nil {
+			return err
+		}
+		if row.Operations == 0 {
+			row.Operations = uint64(Operations_All)
+		}
+		window, err := NewWindow(row.WindowStart, row.WindowEnd, Days(row.WindowDays))
+		if err != nil {
+			return ErrImportingData.New(err.Error())
+		}
+		if err = Refs(row.Refs).Validate(); err != nil {
+			return ErrImportingData.New(err.Error())
+		}
+		table := strings.ToLower(FoldExpression(row.TablePattern))
+		if len(table) > math.MaxUint16 {
+			return ErrTablePatternTooLong.New(table)
+		}
+		// Admin over the escalation constraints may only be imported by the super user, just as it may only be inserted
+		if err = CheckEscalation(ctx, branch, Permissions(row.Permissions)); err != nil {
+			return err
+		}
+		data.Access[i] = ExportedAccessRow{Branch: branch, User: user, Host: host, Permissions: row.Permissions, Operations: row.Operations,
+			Priority: row.Priority, WindowStart: window.Start, WindowEnd: window.End, WindowDays: uint8(window.Days),
+			RequiresApproval: row.RequiresApproval, ExpiresAt: row.ExpiresAt, Refs: uint8(NewRefs(Refs(row.Refs))),
+			TablePattern: table}
+	}
+	for i, row := range data.Namespace {
+		branch, user, host, err := foldImportedExpressions(row.Branch, row.User, row.Host)
+		if err != nil {
+			return err
+		}
+		data.Namespace[i] = ExportedNamespaceRow{Branch: branch, User: user, Host: host}
+	}
+	for i, row := range data.Roles {
+		// Roles have no branch, so an empty one is folded alongside the user and host
+		_, user, host, err := foldImportedExpressions("", row.User, row.Host)
+		if err != nil {
+			return err
+		}
+		if len(row.Role) == 0 {
+			return ErrEmptyRoleName.New()
+		}
+		if IsRoleUser(user) {
+			return ErrNestedRole.New(row.Role, strings.TrimPrefix(user, RolePrefix))
+		}
+		if len(RoleUser(row.Role)) > math.MaxUint16 {
+			return ErrExpressionsTooLong.New(row.Role, user, host)
+		}
+		data.Roles[i] = ExportedRoleRow{Role: row.Role, User: user, Host: host}
+	}
+
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	controller.Namespace.RWMutex.Lock()
+	defer controller.Namespace.RWMutex.Unlock()
+
+	if err := controller.checkGlobalAdmin(ctx, ErrExportImportPermissions); err != nil {
+		return err
+	}
+	// Merging keeps the existing entries, so new members may not join a role whose entries would escalate them
+	if mode == ImportMode_Merge {
+		for _, row := range data.Roles {
+			if err := controller.Access.checkRoleEscalation(ctx, row.Role); err != nil {
+				return err
+			}
+		}
+	}
+	controller.Roles.RWMutex.Lock()
+	defer controller.Roles.RWMutex.Unlock()
+
+	if mode == ImportMode_Replace {
+		for len(controller.Access.Values) > 0 {
+			value := controller.Access.Values[0]
+			controller.Access.Delete(value.Branch, value.User, value.Host)
+		}
+		for len(controller.Namespace.Values) > 0 {
+			value := controller.Namespace.Values[0]
+			controller.Namespace.Delete(value.Branch, value.User, value.Host)
+		}
+		if len(controller.Roles.Values) > 0 {
+			controller.Roles.version++
+			controller.Roles.setValues(nil)
+		}
+	}
+	for _, row := range data.Access {
+		// Merging overwrites the permissions and operations of an existing entry
+		controller.Access.Delete(row.Branch, row.User, row.Host)
+		controller.Access.Insert(AccessValue{
+			Branch:           row.Branch,
+			User:             row.User,
+			Host:             row.Host,
+			Permissions:      Permissions(row.Permissions),
+			Operations:       Operations(row.Operations),
+			Priority:         row.Priority,
+			Window:           Window{Start: row.WindowStart, End: row.WindowEnd, Days: Days(row.WindowDays)},
+			RequiresApproval: row.RequiresApproval,
+			ExpiresAt:        row.ExpiresAt,
+			Refs:             Refs(row.Refs),
+			Table:            row.TablePattern,
+		})
+	}
+	for _, row := range data.Namespace {
+		if controller.Namespace.GetIndex(row.Branch, row.User, row.Host) == -1 {
+			controller.Namespace.Insert(row.Branch, row.User, row.Host)
+		}
+	}
+	for _, row := range data.Roles {
+		if controller.Roles.GetIndex(row.Role, row.User, row.Host) == -1 {
+			controller.Roles.Insert(RoleMember{Role: row.Role, User: row.User, Host: row.Host})
+		}
+	}
+	return nil
+}
+
+// checkGlobalAdmin returns an error of the given kind if the context's user is not an admin over all branches. A context
+// without a session is always allowed, as it does not originate from SQL. Requires external synchronization handling of
+// the Access table.
+func (controller *Controller) checkGlobalAdmin(ctx context.Context, errKind *errors.Kind) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	if _, perms := controller.Access.MatchClientOperationAsOf("%", user, host, Operations_All, now()); perms&Permissions_Admin != Permissions_Admin {
+		return errKind.New(user, host)
+	}
+	return nil
+}
+
+// foldImportedExpressions folds the given expressions in the same way as the system tables. Branch and host are
+// case-insensitive, while user is case-sensitive.
+func foldImportedExpressions(branch string, user string, host string) (string, string, string, error) {
+	branch = strings.ToLower(FoldExpression(branch))
+	user = FoldExpression(user)
+	host = strings.ToLower(FoldExpression(host))
+	if len(branch) > math.MaxUint16 || len(user) > math.MaxUint16 || len(host) > math.MaxUint16 {
+		return "", "", "", ErrExpressionsTooLong.New(branch, user, host)
+	}
+	return branch, user, host, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSessionContext is a Context that represents a SQL session for the given user and host.
+type testSessionContext struct {
+	context.Context
+	user string
+	host string
+}
+
+var _ Context = testSessionContext{}
+
+func (ctx testSessionContext) GetBranch() (string, error) { return "main", nil }
+func (ctx testSessionContext) GetUser() string            { return ctx.user }
+func (ctx testSessionContext) GetHost() string            { return ctx.host }
+func (ctx testSessionContext) GetController() *Controller { return nil }
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	source.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	source.Access.Insert(AccessValue{Branch: "prefix%", User: "bob", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	source.Access.Insert(AccessValue{Branch: "%", User: "carl", Host: "%", Permissions: Permissions_Write, Operations: Operations_Merge | Operations_Tag})
+	source.Access.Insert(AccessValue{Branch: "release\\_%", User: "%", Host: "192.168.%", Permissions: Permissions_Write, Operations: Operations_All})
+	source.Access.Insert(AccessValue{Branch: "other", User: "dave", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Window: Window{Start: 3600, End: 7200, Days: Days_Monday}})
+	source.Namespace.Insert("prefix%", "bob", "localhost")
+	source.Namespace.Insert("release\\_%", "alice", "%")
+	source.Access.Insert(AccessValue{Branch: "team", User: RoleUser("devs"), Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	source.Roles.Insert(RoleMember{Role: "devs", User: "erin", Host: "%"})
+
+	data, err := source.Export(ctx)
+	require.NoError(t, err)
+	target := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	require.NoError(t, target.Import(ctx, data, ImportMode_Replace))
+	require.Len(t, target.Access.Values, 6)
+	require.Len(t, target.Namespace.Values, 2)
+	require.Len(t, target.Roles.Values, 1)
+	assert.Equal(t, source.Access.Values[4].Window, target.Access.Values[4].Window)
+
+	branches := []string{"main", "other", "prefix", "prefixed", "release_1", "releasex1", "team"}
+	users := []string{"root", "alice", "bob", "carl", "dave", "erin"}
+	hosts := []string{"localhost", "192.168.1.1", "10.0.0.1"}
+	ops := []Operations{Operations_All, Operations_DirectDML, Operations_Merge, Operations_Tag}
+	for _, branch := range branches {
+		for _, user := range users {
+			for _, host := range hosts {
+				t.Run(fmt.Sprintf("%s@%s on %s", user, host, branch), func(t *testing.T) {
+					for _, op := range ops {
+						sourceMatched, sourcePerms := source.Access.MatchOperation(branch, user, host, op)
+						targetMatched, targetPerms := target.Access.MatchOperation(branch, user, host, op)
+						assert.Equal(t, sourceMatched, targetMatched)
+						assert.Equal(t, sourcePerms, targetPerms)
+					}
+					assert.Equal(t, source.Namespace.CanCreate(branch, user, host), target.Namespace.CanCreate(branch, user, host))
+				})
+			}
+		}
+	}
+	// Exporting the imported data must reproduce the original document
+	roundTripped, err := target.Export(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTripped))
+}
+
+func TestImportModes(t *testing.T) {
+	ctx := context.Background()
+	controller := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "other", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+
+	data := `{"access":[{"branch":"MAIN","user":"alice","host":"%%%","permissions":1,"operations":1}],"namespace":[{"branch":"main","user":"alice","host":"%"}]}`
+	require.NoError(t, controller.Import(ctx, []byte(data), ImportMode_Merge))
+	require.Len(t, controller.Access.Values, 2)
+	// Expressions are folded and lowercased, so the imported entry overwrites the existing one
+	_, perms := controller.Access.Match("main", "alice", "localhost")
+	assert.Equal(t, Permissions_Admin, perms)
+	_, perms = controller.Access.Match("other", "bob", "localhost")
+	assert.Equal(t, Permissions_Write, perms)
+
+	require.NoError(t, controller.Import(ctx, []byte(data), ImportMode_Replace))
+	require.Len(t, controller.Access.Values, 1)
+	require.Len(t, controller.Namespace.Values, 1)
+	matched, _ := controller.Access.Match("other", "bob", "localhost")
+	assert.False(t, matched)
+	// Every modification is recorded in the binlog
+	assert.Len(t, controller.Access.binlog.Rows(), 7)
+
+	err := controller.Import(ctx, []byte(data), "overwrite")
+	assert.True(t, ErrInvalidImportMode.Is(err))
+	err = controller.Import(ctx, []byte("{"), ImportMode_Merge)
+	assert.True(t, ErrImportingData.Is(err))
+	longExpr := strings.Repeat("a", 70000)
+	err = controller.Import(ctx, []byte(fmt.Sprintf(`{"access":[{"branch":"%s","user":"a","host":"a","permissions":1}]}`, longExpr)), ImportMode_Merge)
+	assert.True(t, ErrExpressionsTooLong.Is(err))
+	err = controller.Import(ctx, []byte(`{"access":[{"branch":"a","user":"a","host":"a","permissions":1,"window_start":7200,"window_end":3600}]}`), ImportMode_Merge)
+	assert.True(t, ErrImportingData.Is(err))
+	require.Len(t, controller.Access.Values, 1)
+
+	// Roles are replaced along with the tables, and nested roles are rejected just as they are by the roles table
+	controller.Roles.Insert(RoleMember{Role: "devs", User: "alice", Host: "%"})
+	require.NoError(t, controller.Import(ctx, []byte(`{"access":[],"namespace":[],"roles":[{"role":"ops","user":"bob","host":"LOCALHOST"}]}`), ImportMode_Replace))
+	assert.Equal(t, []RoleMember{{Role: "ops", User: "bob", Host: "localhost"}}, controller.Roles.Values)
+	err = controller.Import(ctx, []byte(`{"roles":[{"role":"ops","user":"@devs","host":"%"}]}`), ImportMode_Merge)
+	assert.True(t, ErrNestedRole.Is(err))
+	err = controller.Import(ctx, []byte(`{"roles":[{"role":"","user":"bob","host":"%"}]}`), ImportMode_Merge)
+	assert.True(t, ErrEmptyRoleName.Is(err))
+	require.Len(t, controller.Roles.Values, 1)
+}
+
+func TestLoadAndSaveFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	// A missing file loads as an empty controller
+	controller, err := LoadFile(ctx, path, "")
+	require.NoError(t, err)
+	require.Len(t, controller.Access.Values, 0)
+	require.NoError(t, controller.Import(ctx, []byte(`{"access":[{"branch":"main","user":"alice","host":"%","permissions":2}],"namespace":[],"roles":[{"role":"devs","user":"bob","host":"%"}]}`), ImportMode_Merge))
+	require.NoError(t, controller.SaveFile())
+
+	loaded, err := LoadFile(ctx, path, "")
+	require.NoError(t, err)
+	requireSameControllerState(t, controller, loaded)
+}
+
+func TestExportImportRequiresGlobalAdmin(t *testing.T) {
+	ctx := context.Background()
+	controller := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "%", User: "admin", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "branchadmin", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+
+	rootCtx := testSessionContext{Context: ctx, user: "root", host: "localhost"}
+	data, err := controller.Export(rootCtx)
+	require.NoError(t, err)
+	require.NoError(t, controller.Import(testSessionContext{Context: ctx, user: "admin", host: "localhost"}, data, ImportMode_Merge))
+
+	branchAdminCtx := testSessionContext{Context: ctx, user: "branchadmin", host: "localhost"}
+	_, err = controller.Export(branchAdminCtx)
+	assert.True(t, ErrExportImportPermissions.Is(err))
+	err = controller.Import(branchAdminCtx, data, ImportMode_Replace)
+	assert.True(t, ErrExportImportPermissions.Is(err))
+	require.Len(t, controller.Access.Values, 2)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	controller := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "feature1", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "feature_%", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "%", User: "admin", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Namespace.Insert("feature%", "bob", "%")
+	controller.Namespace.Insert("main", "alice", "%")
+
+	// Only a global admin may prune
+	_, _, err := controller.Prune(testSessionContext{Context: ctx, user: "bob", host: "localhost"}, "feature%")
+	assert.True(t, ErrPrunePermissions.Is(err))
+	require.Len(t, controller.Access.Values, 4)
+
+	// The pattern is folded and lowercased, so it matches regardless of case
+	accessCount, namespaceCount, err := controller.Prune(testSessionContext{Context: ctx, user: "admin", host: "localhost"}, "FEATURE%%")
+	require.NoError(t, err)
+	assert.Equal(t, 2, accessCount)
+	assert.Equal(t, 1, namespaceCount)
+	require.Len(t, controller.Access.Values, 2)
+	require.Len(t, controller.Namespace.Values, 1)
+	matched, _ := controller.Access.Match("feature1", "alice", "localhost")
+	assert.False(t, matched)
+	_, perms := controller.Access.Match("main", "alice", "localhost")
+	assert.Equal(t, Permissions_Write, perms)
+
+	// The super user is not stored in the tables, so pruning every branch leaves it intact
+	accessCount, _, err = controller.Prune(ctx, "%")
+	require.NoError(t, err)
+	assert.Equal(t, 2, accessCount)
+	require.Len(t, controller.Access.Values, 0)
+	require.Len(t, controller.Namespace.Values, 0)
+	_, perms = controller.Access.Match("main", "root", "localhost")
+	assert.Equal(t, Permissions_Admin, perms)
+}
@@ -145,6 +145,10 @@ func ParseExpression(str string, collation sql.CollationID) []int32 {
 			}
 		}
 	}
+	// A trailing escape character has nothing to escape, so it matches itself
+	if escaped {
+		orders = append(orders, sortFunc('\\'))
+	}
 	return orders
 }
 
@@ -0,0 +1,170 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// fuzzExpressionSeeds are expressions that exercise escapes, wildcards, and invalid UTF-8.
+var fuzzExpressionSeeds = []string{
+	"",
+	"%",
+	"_",
+	"%%%%",
+	"%_%_",
+	"_%_%",
+	`\`,
+	`\\`,
+	`%\`,
+	`a\`,
+	`\%`,
+	`%\%`,
+	`a\%b`,
+	"a_%_b%_%c",
+	"\xff",
+	"%\xed\xa0\x80%",
+	"\xc3\x28_",
+	"\U0010FFFF%",
+}
+
+// likeToRegexp returns a regular expression that matches the same strings as the given expression when using a binary
+// collation. This serves as a reference implementation for the matcher.
+func likeToRegexp(expr string) *regexp.Regexp {
+	sb := strings.Builder{}
+	sb.WriteString("(?s)^")
+	escaped := false
+	for _, r := range expr {
+		if escaped {
+			escaped = false
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		sb.WriteString(regexp.QuoteMeta(`\`))
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func FuzzFoldExpression(f *testing.F) {
+	for _, seed := range fuzzExpressionSeeds {
+		f.Add(seed, "abc")
+	}
+	f.Fuzz(func(t *testing.T, expr string, str string) {
+		folded := FoldExpression(expr)
+		require.True(t, utf8.ValidString(folded))
+		require.Equal(t, folded, FoldExpression(folded), "folding must be idempotent")
+		require.LessOrEqual(t, utf8.RuneCountInString(folded), utf8.RuneCountInString(expr))
+		// Folding must not change which strings are matched. The matcher operates on runes, so invalid UTF-8 in the
+		// expression is compared as the replacement character.
+		validExpr := string([]rune(expr))
+		validStr := string([]rune(str))
+		assert.Equal(t, likeToRegexp(validExpr).MatchString(validStr), likeToRegexp(folded).MatchString(validStr))
+	})
+}
+
+func FuzzParseExpression(f *testing.F) {
+	for _, seed := range fuzzExpressionSeeds {
+		f.Add(seed, "abc", false)
+		f.Add(seed, "ABC", true)
+	}
+	f.Fuzz(func(t *testing.T, expr string, str string, caseInsensitive bool) {
+		collation := sql.Collation_utf8mb4_0900_bin
+		if caseInsensitive {
+			collation = sql.Collation_utf8mb4_0900_ai_ci
+		}
+		// Expressions that have not been folded must still parse without issue
+		_ = ParseExpression(expr, collation)
+		folded := FoldExpression(expr)
+		orders := ParseExpression(folded, collation)
+		if len(folded) > math.MaxUint16 {
+			require.Nil(t, orders)
+			return
+		}
+		require.LessOrEqual(t, len(orders), utf8.RuneCountInString(folded))
+		for i, order := range orders {
+			if order == anyMatch && i+1 < len(orders) {
+				require.NotEqual(t, anyMatch, orders[i+1])
+				require.NotEqual(t, singleMatch, orders[i+1])
+			}
+		}
+
+		matches := Match([]MatchExpression{{0, orders}}, str, collation)
+		if collation == sql.Collation_utf8mb4_0900_bin {
+			expected := len(str) > 0 && likeToRegexp(folded).MatchString(string([]rune(str)))
+			require.Equal(t, expected, len(matches) == 1, "%q matching %q", folded, str)
+		}
+	})
+}
+
+func FuzzAccessPipeline(f *testing.F) {
+	for _, seed := range fuzzExpressionSeeds {
+		f.Add(seed, seed, seed, "main", "user", "localhost")
+	}
+	f.Fuzz(func(t *testing.T, branchExpr string, userExpr string, hostExpr string, branch string, user string, host string) {
+		branchExpr, userExpr, hostExpr, err := foldImportedExpressions(branchExpr, userExpr, hostExpr)
+		if err != nil {
+			require.True(t, ErrExpressionsTooLong.Is(err))
+			return
+		}
+		access := newAccess("root", "localhost")
+		access.Insert(AccessValue{Branch: branchExpr, User: userExpr, Host: hostExpr, Permissions: Permissions_Write, Operations: Operations_All})
+
+		b := flatbuffers.NewBuilder(1024)
+		b.Finish(access.Serialize(b))
+		loaded := newAccess("root", "localhost")
+		require.NoError(t, loaded.Deserialize(serial.GetRootAsBranchControlAccess(b.FinishedBytes(), 0)))
+		require.Equal(t, access.Values, loaded.Values)
+		// Empty expressions may deserialize as empty slices rather than nil, so only the contents are compared
+		require.Len(t, loaded.Branches, len(access.Branches))
+		for i := range access.Branches {
+			require.Equal(t, access.Branches[i].CollectionIndex, loaded.Branches[i].CollectionIndex)
+			require.ElementsMatch(t, access.Branches[i].SortOrders, loaded.Branches[i].SortOrders)
+		}
+
+		branch, host = strings.ToLower(branch), strings.ToLower(host)
+		expected := access.MatchDetailed(branch, user, host, Operations_All)
+		require.Equal(t, expected, loaded.MatchDetailed(branch, user, host, Operations_All))
+
+		namespace := newNamespace(access, "root", "localhost")
+		namespace.Insert(branchExpr, userExpr, hostExpr)
+		_ = namespace.CanCreate(branch, user, host)
+		_ = CanCreateBranch(context.Background(), branch)
+	})
+}
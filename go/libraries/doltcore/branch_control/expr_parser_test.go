@@ -59,6 +59,15 @@ func TestSingleMatch(t *testing.T) {
 		{`a\\\b`, "a", false, sql.Collation_utf8mb4_0900_ai_ci},
 		{`A%%%%`, "abc", true, sql.Collation_utf8mb4_0900_ai_ci},
 		{`A%%%%bc`, "abc", true, sql.Collation_utf8mb4_0900_ai_ci},
+		{`abc\`, "abc", false, sql.Collation_utf8mb4_0900_bin},
+		{`abc\`, `abc\`, true, sql.Collation_utf8mb4_0900_bin},
+		{`%\`, `abc\`, true, sql.Collation_utf8mb4_0900_ai_ci},
+		{"%%%_", "a", true, sql.Collation_utf8mb4_0900_bin},
+		{"%%%_", "", false, sql.Collation_utf8mb4_0900_bin},
+		{"___", "ab", false, sql.Collation_utf8mb4_0900_bin},
+		{"___", "abc", true, sql.Collation_utf8mb4_0900_bin},
+		{"a\xffb", "a\uFFFDb", true, sql.Collation_utf8mb4_0900_bin},
+		{"a_b", "a\xffb", true, sql.Collation_utf8mb4_0900_bin},
 	}
 
 	for _, test := range tests {
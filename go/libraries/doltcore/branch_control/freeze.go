@@ -0,0 +1,292 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// A freeze locks every branch matching its branch expression, so that only the super user may modify the branches,
+// regardless of the entries of the Access table. Freezes are checked before any entry is considered, and are enforced
+// even in audit mode, as they're meant to stop all writes during an incident. Entries of either table that affect a
+// frozen branch may not be modified while the freeze is in place, so that a freeze may not be circumvented by granting
+// new permissions. Freezes are saved alongside the tables, in the same way as the pending moves.
+
+// Freeze is a lock on the branches matching the branch expression, along with the user that placed it.
+type Freeze struct {
+	Branch    string
+	User      string
+	Host      string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// Freezes contains every freeze, with at most one freeze per branch expression.
+type Freezes struct {
+	Values   []Freeze
+	Branches []MatchExpression
+	// version is incremented on every modification, so that a save is able to determine whether the freezes changed
+	version uint64
+	RWMutex *sync.RWMutex
+}
+
+// newFreezes returns a new Freezes.
+func newFreezes() *Freezes {
+	return &Freezes{
+		Values:   nil,
+		Branches: nil,
+		RWMutex:  &sync.RWMutex{},
+	}
+}
+
+// Match returns the freeze whose branch expression matches the given branch, along with whether any freeze matched.
+// Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Freezes) Match(branch string) (Freeze, bool) {
+	if len(tbl.Values) == 0 {
+		return Freeze{}, false
+	}
+	matches := Match(tbl.Branches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	defer indexPool.Put(matches)
+	if len(matches) == 0 {
+		return Freeze{}, false
+	}
+	return tbl.Values[matches[0]], true
+}
+
+// affecting returns a freeze whose branches may be matched by the given folded branch expression, along with whether
+// any freeze is affected. As comparing two expressions is not exact, an expression affects a freeze when either
+// expression matches the other as though it were a branch name, which is the same approach taken when determining
+// whether a user is an admin on a branch expression. Requires external synchronization handling.
+func (tbl *Freezes) affecting(branchExpr string) (Freeze, bool) {
+	if freeze, ok := tbl.Match(branchExpr); ok {
+		return freeze, true
+	}
+	expr := []MatchExpression{{CollectionIndex: 0, SortOrders: ParseExpression(branchExpr, sql.Collation_utf8mb4_0900_ai_ci)}}
+	for _, freeze := range tbl.Values {
+		matches := Match(expr, freeze.Branch, sql.Collation_utf8mb4_0900_ai_ci)
+		matched := len(matches) > 0
+		indexPool.Put(matches)
+		if matched {
+			return freeze, true
+		}
+	}
+	return Freeze{}, false
+}
+
+// getIndex returns the index of the freeze with the given branch expression, or -1 if there is no such freeze.
+func (tbl *Freezes) getIndex(branch string) int {
+	for i, freeze := range tbl.Values {
+		if freeze.Branch == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// put adds the given freeze, replacing the freeze of the same branch expression. Requires external synchronization
+// handling.
+func (tbl *Freezes) put(freeze Freeze) {
+	tbl.version++
+	if idx := tbl.getIndex(freeze.Branch); idx != -1 {
+		tbl.Values[idx] = freeze
+		return
+	}
+	tbl.Branches = append(tbl.Branches, MatchExpression{CollectionIndex: uint32(len(tbl.Values)), SortOrders: ParseExpression(freeze.Branch, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Values = append(tbl.Values, freeze)
+}
+
+// remove removes the freeze of the given branch expression, returning whether it existed. Requires external
+// synchronization handling.
+func (tbl *Freezes) remove(branch string) bool {
+	idx := tbl.getIndex(branch)
+	if idx == -1 {
+		return false
+	}
+	tbl.version++
+	tbl.setValues(append(tbl.Values[:idx], tbl.Values[idx+1:]...))
+	return true
+}
+
+// setValues replaces every freeze with the given freezes, rebuilding their match expressions. Requires external
+// synchronization handling.
+func (tbl *Freezes) setValues(values []Freeze) {
+	tbl.Values = values
+	tbl.Branches = make([]MatchExpression, len(values))
+	for i, freeze := range values {
+		tbl.Branches[i] = MatchExpression{CollectionIndex: uint32(i), SortOrders: ParseExpression(freeze.Branch, sql.Collation_utf8mb4_0900_ai_ci)}
+	}
+}
+
+// serialize returns the offset of the vector of freezes written to the given builder. Requires external
+// synchronization handling.
+func (tbl *Freezes) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, freeze := range tbl.Values {
+		branch := b.CreateString(freeze.Branch)
+		user := b.CreateString(freeze.User)
+		host := b.CreateString(freeze.Host)
+		reason := b.CreateString(freeze.Reason)
+		serial.BranchControlFreezeStart(b)
+		serial.BranchControlFreezeAddBranch(b, branch)
+		serial.BranchControlFreezeAddUser(b, user)
+		serial.BranchControlFreezeAddHost(b, host)
+		serial.BranchControlFreezeAddReason(b, reason)
+		serial.BranchControlFreezeAddCreatedAt(b, freeze.CreatedAt.UnixMilli())
+		offsets[i] = serial.BranchControlFreezeEnd(b)
+	}
+	serial.BranchControlStartFreezesVector(b, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
+
+// deserialize replaces the freezes with those of the given snapshot or journal entry. Requires external
+// synchronization handling.
+func (tbl *Freezes) deserialize(bc *serial.BranchControl) error {
+	values := make([]Freeze, bc.FreezesLength())
+	for i := range values {
+		serialFreeze := &serial.BranchControlFreeze{}
+		if _, err := bc.TryFreezes(serialFreeze, i); err != nil {
+			return err
+		}
+		values[i] = Freeze{
+			Branch:    string(serialFreeze.Branch()),
+			User:      string(serialFreeze.User()),
+			Host:      string(serialFreeze.Host()),
+			Reason:    string(serialFreeze.Reason()),
+			CreatedAt: time.UnixMilli(serialFreeze.CreatedAt()).UTC(),
+		}
+	}
+	tbl.version++
+	tbl.setValues(values)
+	return nil
+}
+
+// FreezeBranch freezes every branch matching the given branch expression, replacing any freeze of the same expression.
+// The context's user must be an admin on the expression.
+func FreezeBranch(ctx context.Context, branchExpr string, reason string) error {
+	branch, err := foldFreezeExpression(branchExpr)
+	if err != nil {
+		return err
+	}
+	freeze := Freeze{Branch: branch, Reason: reason, CreatedAt: now()}
+	if branchAwareSession := GetBranchAwareSession(ctx); branchAwareSession != nil {
+		freeze.User = branchAwareSession.GetUser()
+		freeze.Host = branchAwareSession.GetHost()
+		if !isExpressionAdmin(branch, freeze.User, freeze.Host) {
+			return ErrFreezePermissions.New(freeze.User, freeze.Host, branch)
+		}
+	}
+
+	StaticController.Freezes.RWMutex.Lock()
+	defer StaticController.Freezes.RWMutex.Unlock()
+	StaticController.Freezes.put(freeze)
+	return nil
+}
+
+// UnfreezeBranch removes the freeze of the given branch expression. The context's user must be an admin on the
+// expression, which the user may be while the freeze is in place, as the freeze does not modify any entries.
+func UnfreezeBranch(ctx context.Context, branchExpr string) error {
+	branch, err := foldFreezeExpression(branchExpr)
+	if err != nil {
+		return err
+	}
+	if branchAwareSession := GetBranchAwareSession(ctx); branchAwareSession != nil {
+		if !isExpressionAdmin(branch, branchAwareSession.GetUser(), branchAwareSession.GetHost()) {
+			return ErrFreezePermissions.New(branchAwareSession.GetUser(), branchAwareSession.GetHost(), branch)
+		}
+	}
+
+	StaticController.Freezes.RWMutex.Lock()
+	defer StaticController.Freezes.RWMutex.Unlock()
+	if !StaticController.Freezes.remove(branch) {
+		return ErrNotFrozen.New(branch)
+	}
+	return nil
+}
+
+// CheckRuleEdit returns an error if any of the given folded branch expressions, which belong to entries that are being
+// added, modified, or removed from either table, affect a frozen branch. The super user may always edit entries.
+func CheckRuleEdit(ctx context.Context, branchExprs ...string) error {
+	return StaticController.checkRuleEdit(ctx, branchExprs...)
+}
+
+// checkRuleEdit is the same as CheckRuleEdit, using the freezes of the calling controller.
+func (controller *Controller) checkRuleEdit(ctx context.Context, branchExprs ...string) error {
+	if !enabled {
+		return nil
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	if controller.Access.isSuperUser(user, ClientHostForms(host)) {
+		return nil
+	}
+	controller.Freezes.RWMutex.RLock()
+	defer controller.Freezes.RWMutex.RUnlock()
+	for _, branchExpr := range branchExprs {
+		if freeze, ok := controller.Freezes.affecting(branchExpr); ok {
+			return ErrEditingFrozenRow.New(user, host, branchExpr, freeze.Branch)
+		}
+	}
+	return nil
+}
+
+// checkFrozen returns an error if the given branch is frozen and the user is not the super user. The denial is always
+// enforced, although it is reported in the same way as any other denial.
+func checkFrozen(ctx context.Context, user string, host string, branch string, action string) error {
+	StaticController.Freezes.RWMutex.RLock()
+	freeze, frozen := StaticController.Freezes.Match(branch)
+	StaticController.Freezes.RWMutex.RUnlock()
+	if !frozen || StaticController.Access.isSuperUser(user, ClientHostForms(host)) {
+		return nil
+	}
+	return report(ctx, Denial{User: user, Host: host, Branch: branch, Action: action, Err: ErrBranchFrozen.New(branch, freeze.Branch, freeze.Reason)})
+}
+
+// isExpressionAdmin returns whether the given user is an admin on the given folded branch expression, which is used as
+// though it were a branch name. The Access table is locked before the freezes are, so this must not be called while the
+// freezes are locked.
+func isExpressionAdmin(branchExpr string, user string, host string) bool {
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+	_, perms := StaticController.Access.MatchClientOperationAsOf(branchExpr, user, host, Operations_All, now())
+	return perms&Permissions_Admin == Permissions_Admin
+}
+
+// foldFreezeExpression folds the given branch expression in the same way as the branch expressions of the tables.
+func foldFreezeExpression(branchExpr string) (string, error) {
+	branch := strings.ToLower(FoldExpression(branchExpr))
+	if len(branch) == 0 {
+		return "", ErrEmptyFreezeExpression.New()
+	}
+	if len(branch) > math.MaxUint16 {
+		return "", ErrExpressionsTooLong.New(branch, "", "")
+	}
+	return branch, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeze(t *testing.T) {
+	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{{
+		Name:    EnforcementVariable,
+		Scope:   sql.SystemVariableScope_Global,
+		Dynamic: true,
+		Type:    sql.NewSystemEnumType(EnforcementVariable, EnforcementModes...),
+		Default: string(EnforcementMode_Enforce),
+	}})
+	enableForTest(t)
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	bob := testSessionContext{Context: context.Background(), user: "bob", host: "localhost"}
+	root := testSessionContext{Context: context.Background(), user: "root", host: "localhost"}
+
+	assert.True(t, ErrFreezePermissions.Is(FreezeBranch(bob, "main", "")))
+	require.NoError(t, FreezeBranch(alice, "Release%", "code freeze"))
+	require.Len(t, StaticController.Freezes.Values, 1)
+	assert.Equal(t, "release%", StaticController.Freezes.Values[0].Branch)
+	assert.Equal(t, "alice", StaticController.Freezes.Values[0].User)
+
+	release := testBranchContext{testSessionContext: alice, branch: "release1"}
+	assert.True(t, ErrBranchFrozen.Is(CheckAccess(release, Permissions_Write, Operations_DirectDML)))
+	assert.True(t, ErrBranchFrozen.Is(CanCreateBranch(alice, "release2")))
+	assert.True(t, ErrBranchFrozen.Is(CanDeleteBranch(alice, "release1")))
+	_, err := CheckRefMove(alice, "release1")
+	assert.True(t, ErrBranchFrozen.Is(err))
+	assert.NoError(t, CheckAccess(alice, Permissions_Write, Operations_DirectDML))
+	assert.NoError(t, CheckAccess(testBranchContext{testSessionContext: root, branch: "release1"}, Permissions_Write, Operations_DirectDML))
+
+	// Freezes are enforced even in audit mode
+	require.NoError(t, sql.SystemVariables.SetGlobal(EnforcementVariable, string(EnforcementMode_Audit)))
+	defer sql.SystemVariables.SetGlobal(EnforcementVariable, string(EnforcementMode_Enforce))
+	assert.True(t, ErrBranchFrozen.Is(CheckAccess(release, Permissions_Write, Operations_DirectDML)))
+
+	// Rule edits are denied when either expression matches the other
+	for _, expr := range []string{"release1", "release%", "%", "rel_ase%"} {
+		assert.True(t, ErrEditingFrozenRow.Is(CheckRuleEdit(alice, expr)), expr)
+	}
+	for _, expr := range []string{"main", "feature%", "relief"} {
+		assert.NoError(t, CheckRuleEdit(alice, expr), expr)
+	}
+	assert.NoError(t, CheckRuleEdit(root, "release1"))
+	_, _, err = StaticController.Prune(root, "%")
+	require.NoError(t, err)
+	StaticController.Access.Insert(AccessValue{Branch: "%", User: "alice", Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	StaticController.Access.Insert(AccessValue{Branch: "release1", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	_, _, err = StaticController.Prune(alice, "release%")
+	assert.True(t, ErrEditingFrozenRow.Is(err))
+	assert.Len(t, StaticController.Access.Values, 2)
+
+	assert.True(t, ErrFreezePermissions.Is(UnfreezeBranch(bob, "release%")))
+	require.NoError(t, UnfreezeBranch(alice, "release%"))
+	assert.True(t, ErrNotFrozen.Is(UnfreezeBranch(alice, "release%")))
+	assert.NoError(t, CheckAccess(release, Permissions_Write, Operations_DirectDML))
+}
+
+// testBranchContext is a testSessionContext whose session is on the given branch.
+type testBranchContext struct {
+	testSessionContext
+	branch string
+}
+
+func (ctx testBranchContext) GetBranch() (string, error) { return ctx.branch, nil }
@@ -0,0 +1,204 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// SkipNameResolveVariable is the name of the global system variable that, as in MySQL, stops the server from resolving
+// the hostnames of clients, so that clients are only identified by their IP address.
+const SkipNameResolveVariable = "skip_name_resolve"
+
+// HostResolver returns the hostnames of the given IP address.
+type HostResolver func(ip string) ([]string, error)
+
+// hostResolutionTTL is how long the hostnames of an IP address are cached before they're resolved again.
+const hostResolutionTTL = time.Minute
+
+// numericNameRegex matches hostnames that begin with a numeric component, such as `10.0.0.example.com`. As in MySQL,
+// such names are ignored, as they could otherwise be matched by IP address patterns such as `10.0.0.%`.
+var numericNameRegex = regexp.MustCompile(`^[0-9]+\.`)
+
+// resolvedHost is a cached resolution of an IP address.
+type resolvedHost struct {
+	names   []string
+	expires time.Time
+}
+
+var (
+	hostResolver      HostResolver = net.LookupAddr
+	resolvedHosts                  = make(map[string]resolvedHost)
+	hostResolverMutex              = &sync.Mutex{}
+)
+
+// SetHostResolver replaces the resolver of client hostnames, returning the previous resolver. Any cached resolutions
+// are discarded. This is intended for tests, which should not depend on DNS.
+func SetHostResolver(resolver HostResolver) HostResolver {
+	hostResolverMutex.Lock()
+	defer hostResolverMutex.Unlock()
+	previous := hostResolver
+	hostResolver = resolver
+	resolvedHosts = make(map[string]resolvedHost)
+	return previous
+}
+
+// resolvesHostnames returns whether the server resolves the hostnames of clients, which is the case unless
+// skip_name_resolve has been enabled.
+func resolvesHostnames() bool {
+	_, val, ok := sql.SystemVariables.GetGlobal(SkipNameResolveVariable)
+	if !ok {
+		return true
+	}
+	skip, err := sql.ConvertToBool(val)
+	return err != nil || !skip
+}
+
+// ClientHostForms returns every form of the given client host that host expressions are matched against, in the same
+// way that MySQL matches accounts. A client is always matched by the host that the server reports for it. When the
+// server resolves hostnames and the client is reported by its IP address, the client is also matched by its hostnames,
+// where loopback addresses are always known as `localhost`. Resolution failures are ignored, leaving only the IP form.
+func ClientHostForms(host string) []string {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	if ip == nil || !resolvesHostnames() {
+		return []string{host}
+	}
+	if ip.IsLoopback() {
+		return []string{host, "localhost"}
+	}
+	forms := []string{host}
+	for _, name := range resolveHost(host) {
+		if !numericNameRegex.MatchString(name) {
+			forms = append(forms, name)
+		}
+	}
+	return forms
+}
+
+// resolveHost returns the lowercased hostnames of the given IP address, using the cached resolution when one exists.
+func resolveHost(ip string) []string {
+	hostResolverMutex.Lock()
+	defer hostResolverMutex.Unlock()
+	currentTime := now()
+	if resolved, ok := resolvedHosts[ip]; ok && currentTime.Before(resolved.expires) {
+		return resolved.names
+	}
+	names, err := hostResolver(ip)
+	if err != nil {
+		names = nil
+	}
+	resolved := resolvedHost{names: make([]string, 0, len(names)), expires: currentTime.Add(hostResolutionTTL)}
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSuffix(name, ".")); len(name) > 0 {
+			resolved.names = append(resolved.names, name)
+		}
+	}
+	resolvedHosts[ip] = resolved
+	return resolved.names
+}
+
+// hostExpressionKind is the form of client host that a host expression is written for.
+type hostExpressionKind byte
+
+const (
+	hostExpressionKind_Any  hostExpressionKind = iota // hostExpressionKind_Any is only made of wildcards, and matches every form
+	hostExpressionKind_IP                             // hostExpressionKind_IP matches IPv4 or IPv6 addresses
+	hostExpressionKind_Name                           // hostExpressionKind_Name matches hostnames
+)
+
+// classifyHostExpression returns the form of client host that the given folded host expression is written for.
+func classifyHostExpression(hostExpr string) hostExpressionKind {
+	literals := hostExpressionLiterals(hostExpr)
+	if len(literals) == 0 {
+		return hostExpressionKind_Any
+	}
+	isIPv4, isIPv6 := true, strings.ContainsRune(literals, ':')
+	for _, r := range literals {
+		isDigit := r >= '0' && r <= '9'
+		isIPv4 = isIPv4 && (isDigit || r == '.')
+		isIPv6 = isIPv6 && (isDigit || r == '.' || r == ':' || (r >= 'a' && r <= 'f'))
+	}
+	if isIPv4 || isIPv6 {
+		return hostExpressionKind_IP
+	}
+	return hostExpressionKind_Name
+}
+
+// hostExpressionLiterals returns the characters of the given folded host expression that are not wildcards.
+func hostExpressionLiterals(hostExpr string) string {
+	literals := strings.Builder{}
+	escaped := false
+	for _, r := range hostExpr {
+		switch {
+		case escaped:
+			escaped = false
+			literals.WriteRune(r)
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+		default:
+			literals.WriteRune(r)
+		}
+	}
+	return literals.String()
+}
+
+// hostSpecificity returns how specific the given folded host expression is, following the order in which MySQL
+// considers accounts. Expressions without wildcards are the most specific, followed by patterns with the longest
+// literal prefix before their first wildcard, so that `%` is the least specific of all.
+func hostSpecificity(hostExpr string) int {
+	escaped := false
+	for i, r := range hostExpr {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+			return i
+		}
+	}
+	return math.MaxInt
+}
+
+// HostExpressionWarning returns a warning when the given folded host expression is written for a form of client host
+// that the server does not match it against, as such an expression likely does not match the clients it was written
+// for. Returns an empty string when there is nothing to warn about. This is best-effort, as a pattern may be written
+// to match multiple forms.
+func HostExpressionWarning(hostExpr string) string {
+	switch kind := classifyHostExpression(hostExpr); {
+	case kind == hostExpressionKind_Name && !resolvesHostnames():
+		if hostExpr == "localhost" {
+			return fmt.Sprintf("host expression `%s` does not match clients connecting through a loopback address, "+
+				"as %s is enabled so clients are only identified by their IP address", hostExpr, SkipNameResolveVariable)
+		}
+		return fmt.Sprintf("host expression `%s` is a hostname, but %s is enabled so clients are only identified by "+
+			"their IP address", hostExpr, SkipNameResolveVariable)
+	case kind == hostExpressionKind_IP && resolvesHostnames() && hostSpecificity(hostExpr) != math.MaxInt:
+		return fmt.Sprintf("host expression `%s` is an IP address pattern, which is only matched against the IP "+
+			"addresses of clients, while clients are also identified by their resolved hostnames", hostExpr)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setSkipNameResolve sets the skip_name_resolve system variable, returning a function that restores its default.
+func setSkipNameResolve(t *testing.T, skip bool) func() {
+	val := int8(0)
+	if skip {
+		val = 1
+	}
+	require.NoError(t, sql.SystemVariables.AssignValues(map[string]interface{}{SkipNameResolveVariable: val}))
+	return func() {
+		require.NoError(t, sql.SystemVariables.AssignValues(map[string]interface{}{SkipNameResolveVariable: int8(0)}))
+	}
+}
+
+// fakeResolver returns a HostResolver over the given names, which counts how often each IP address is resolved.
+func fakeResolver(names map[string][]string, lookups map[string]int) HostResolver {
+	return func(ip string) ([]string, error) {
+		lookups[ip]++
+		if resolved, ok := names[ip]; ok {
+			return resolved, nil
+		}
+		return nil, fmt.Errorf("no hostname for %s", ip)
+	}
+}
+
+func TestClientHostForms(t *testing.T) {
+	frozen := time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+	defer SetTimeSource(SetTimeSource(func() time.Time { return frozen }))
+	lookups := make(map[string]int)
+	defer SetHostResolver(SetHostResolver(fakeResolver(map[string][]string{
+		"10.0.0.5":  {"Build1.Example.com."},
+		"10.0.0.6":  {"10.0.0.example.com.", "build2.example.com."},
+		"fe80::1":   {"ipv6host.example.com."},
+		"127.0.0.1": {"shouldnotbeused."},
+	}, lookups)))
+
+	tests := []struct {
+		host  string
+		forms []string
+	}{
+		{"10.0.0.5", []string{"10.0.0.5", "build1.example.com"}},
+		// Names that begin with a numeric component could be confused with IP patterns, so they're ignored
+		{"10.0.0.6", []string{"10.0.0.6", "build2.example.com"}},
+		{"fe80::1", []string{"fe80::1", "ipv6host.example.com"}},
+		// Loopback addresses are always localhost, without a lookup
+		{"127.0.0.1", []string{"127.0.0.1", "localhost"}},
+		{"::1", []string{"::1", "localhost"}},
+		// Failed lookups only leave the IP address
+		{"10.0.0.7", []string{"10.0.0.7"}},
+		// Hosts that are not IP addresses are only matched as they are
+		{"localhost", []string{"localhost"}},
+		{"Build1.Example.com", []string{"build1.example.com"}},
+	}
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			assert.Equal(t, test.forms, ClientHostForms(test.host))
+		})
+	}
+	assert.Zero(t, lookups["127.0.0.1"])
+
+	// Resolutions are cached until they expire
+	ClientHostForms("10.0.0.5")
+	assert.Equal(t, 1, lookups["10.0.0.5"])
+	frozen = frozen.Add(hostResolutionTTL)
+	ClientHostForms("10.0.0.5")
+	ClientHostForms("10.0.0.5")
+	assert.Equal(t, 2, lookups["10.0.0.5"])
+
+	// Nothing is resolved when skip_name_resolve is enabled
+	defer setSkipNameResolve(t, true)()
+	assert.Equal(t, []string{"10.0.0.5"}, ClientHostForms("10.0.0.5"))
+	assert.Equal(t, []string{"127.0.0.1"}, ClientHostForms("127.0.0.1"))
+	assert.Equal(t, 2, lookups["10.0.0.5"])
+}
+
+func TestMatchClientHostForms(t *testing.T) {
+	defer SetHostResolver(SetHostResolver(fakeResolver(map[string][]string{
+		"10.0.0.5": {"build1.example.com."},
+	}, make(map[string]int))))
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "10.0.0.%", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%.example.com", Permissions: Permissions_Admin, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "bob", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+
+	match := func(user string, host string) Permissions {
+		_, perms := access.MatchClientOperationAsOf("main", user, host, Operations_All, now())
+		return perms
+	}
+	// Both the IP address and hostname entries match the client
+	assert.Equal(t, Permissions_Write|Permissions_Admin, match("alice", "10.0.0.5"))
+	result := access.MatchClientDetailed("main", "alice", "10.0.0.5", Operations_All)
+	assert.Equal(t, []uint32{0, 1}, result.Indexes)
+	// The super user and localhost entries match loopback clients
+	assert.Equal(t, Permissions_Write, match("bob", "127.0.0.1"))
+	assert.Equal(t, Permissions_Admin, match("root", "::1"))
+	// Matching a probe directly only considers the host as it is given
+	_, perms := access.Match("main", "alice", "10.0.0.5")
+	assert.Equal(t, Permissions_Write, perms)
+
+	defer setSkipNameResolve(t, true)()
+	assert.Equal(t, Permissions_Write, match("alice", "10.0.0.5"))
+	assert.Equal(t, Permissions(0), match("bob", "127.0.0.1"))
+	assert.Equal(t, Permissions(0), match("root", "127.0.0.1"))
+}
+
+func TestHostPrecedence(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "10.%", Permissions: Permissions_Admin, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "10.0.0.%", Permissions: 0, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "10.0.0.5", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+
+	// Among entries with the same priority, the most specific host is chosen, regardless of how the hosts sort
+	ordered := func(host string) Permissions {
+		_, perms := access.matchWithStrategy("main", "alice", []string{host}, Operations_All, orderedMatchStrategy{}, now())
+		return perms
+	}
+	assert.Equal(t, Permissions_Write, ordered("10.0.0.5"))
+	assert.Equal(t, Permissions(0), ordered("10.0.0.6"))
+	assert.Equal(t, Permissions_Admin, ordered("10.1.0.1"))
+	assert.Equal(t, Permissions_Admin, ordered("192.168.0.1"))
+	assert.Equal(t, Permissions_Write, ordered("localhost"))
+	// Priority still comes first
+	access.Delete("main", "alice", "%")
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All, Priority: 1})
+	assert.Equal(t, Permissions_Admin, ordered("10.0.0.5"))
+
+	assert.Equal(t, []int{0, 3, 5}, []int{hostSpecificity("%"), hostSpecificity("10.%"), hostSpecificity("10.0._")})
+	assert.Greater(t, hostSpecificity("localhost"), hostSpecificity("localhos_"))
+	assert.Greater(t, hostSpecificity(`10\%`), hostSpecificity("10%"))
+}
+
+func TestHostExpressionWarning(t *testing.T) {
+	kinds := map[string]hostExpressionKind{
+		"%":             hostExpressionKind_Any,
+		"_%":            hostExpressionKind_Any,
+		"10.0.0.%":      hostExpressionKind_IP,
+		"192.168.1.1":   hostExpressionKind_IP,
+		"fe80::%":       hostExpressionKind_IP,
+		"localhost":     hostExpressionKind_Name,
+		"%.example.com": hostExpressionKind_Name,
+		"build_":        hostExpressionKind_Name,
+		"cafe":          hostExpressionKind_Name,
+	}
+	for hostExpr, kind := range kinds {
+		assert.Equal(t, kind, classifyHostExpression(hostExpr), hostExpr)
+	}
+
+	// Names resolve by default, so only IP address patterns are warned about
+	assert.Empty(t, HostExpressionWarning("%"))
+	assert.Empty(t, HostExpressionWarning("localhost"))
+	assert.Empty(t, HostExpressionWarning("%.example.com"))
+	assert.Empty(t, HostExpressionWarning("192.168.1.1"))
+	assert.Contains(t, HostExpressionWarning("10.0.0.%"), "IP address pattern")
+
+	defer setSkipNameResolve(t, true)()
+	assert.Empty(t, HostExpressionWarning("%"))
+	assert.Empty(t, HostExpressionWarning("10.0.0.%"))
+	assert.Empty(t, HostExpressionWarning("192.168.1.1"))
+	assert.Contains(t, HostExpressionWarning("%.example.com"), "is a hostname")
+	assert.Contains(t, HostExpressionWarning("localhost"), "loopback address")
+}
@@ -0,0 +1,274 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"os"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// The branch control file begins with a snapshot of both tables, which is a BranchControl message. Changes made after
+// the snapshot are appended to the file as journal entries, so that a single modification does not rewrite every
+// entry. Each journal entry is also a BranchControl message, except that its tables only contain the binlog rows that
+// were added since the previous entry. Loading the file deserializes the snapshot and then replays the rows of every
+// journal entry in order, which reconstructs the tables along with their binlogs. Pending moves, freezes, default branch
+// changes, and roles have no binlog, so every entry contains all of them, and those of the final entry replace those of
+// the snapshot.
+
+// journalCompactionMinRows is the minimum number of journaled rows before the file is compacted into a new snapshot.
+// Beyond this minimum, the file is compacted once the journal holds more rows than both tables combined, so that the
+// cost of rewriting the snapshot is amortized over the writes that were journaled since the previous snapshot.
+var journalCompactionMinRows = 1024
+
+// journalState tracks the binlog rows of the Access and Namespace tables that the branch control file contains.
+type journalState struct {
+	// access and namespace are the binlogs that the file was written from. A table whose binlog has since been replaced
+	// must be written to a new snapshot, as its new rows do not follow those in the file. Both are nil when the file
+	// must be rewritten, such as when it ends with a partially written journal entry.
+	access    *Binlog
+	namespace *Binlog
+	// accessRows and namespaceRows are the number of rows from each binlog that the file contains
+	accessRows    int
+	namespaceRows int
+	// journalRows is the number of rows in the journal entries that follow the snapshot
+	journalRows int
+	// pendingVersion is the version of the pending moves that the file contains
+	pendingVersion uint64
+	// freezesVersion is the version of the freezes that the file contains
+	freezesVersion uint64
+	// defaultBranchVersion is the version of the default branch changes that the file contains
+	defaultBranchVersion uint64
+	// rolesVersion is the version of the roles that the file contains
+	rolesVersion uint64
+}
+
+// newJournalState returns a journalState for a file containing a snapshot of the controller's tables, pending moves,
+// freezes, default branch changes, and roles. Requires external synchronization handling of all of them.
+func (controller *Controller) newJournalState() journalState {
+	return journalState{
+		access:               controller.Access.binlog,
+		namespace:            controller.Namespace.binlog,
+		accessRows:           len(controller.Access.binlog.Rows()),
+		namespaceRows:        len(controller.Namespace.binlog.Rows()),
+		pendingVersion:       controller.PendingMoves.version,
+		freezesVersion:       controller.Freezes.version,
+		defaultBranchVersion: controller.DefaultBranchChanges.version,
+		rolesVersion:         controller.Roles.version,
+	}
+}
+
+// appendJournal appends the binlog rows that were added since the previous save to the controller's file as a journal
+// entry. Returns false without modifying the file when a snapshot must be written instead. Requires the save mutex to
+// be held.
+func (controller *Controller) appendJournal() (bool, error) {
+	controller.Access.RWMutex.RLock()
+	controller.Namespace.RWMutex.RLock()
+	journal := controller.journal
+	if journal.access == nil || journal.access != controller.Access.binlog || journal.namespace != controller.Namespace.binlog {
+		controller.Namespace.RWMutex.RUnlock()
+		controller.Access.RWMutex.RUnlock()
+		return false, nil
+	}
+	accessRows, accessLen := controller.Access.binlog.rowsFrom(journal.accessRows)
+	namespaceRows, namespaceLen := controller.Namespace.binlog.rowsFrom(journal.namespaceRows)
+	tableRows := len(controller.Access.Values) + len(controller.Namespace.Values)
+	controller.Namespace.RWMutex.RUnlock()
+	controller.Access.RWMutex.RUnlock()
+	controller.PendingMoves.RWMutex.RLock()
+	defer controller.PendingMoves.RWMutex.RUnlock()
+	pendingVersion := controller.PendingMoves.version
+	controller.Freezes.RWMutex.RLock()
+	defer controller.Freezes.RWMutex.RUnlock()
+	freezesVersion := controller.Freezes.version
+	controller.DefaultBranchChanges.RWMutex.RLock()
+	defer controller.DefaultBranchChanges.RWMutex.RUnlock()
+	defaultBranchVersion := controller.DefaultBranchChanges.version
+	controller.Roles.RWMutex.RLock()
+	defer controller.Roles.RWMutex.RUnlock()
+	rolesVersion := controller.Roles.version
+
+	if len(accessRows) == 0 && len(namespaceRows) == 0 && pendingVersion == journal.pendingVersion &&
+		freezesVersion == journal.freezesVersion && defaultBranchVersion == journal.defaultBranchVersion &&
+		rolesVersion == journal.rolesVersion {
+		return true, nil
+	}
+	journalRows := journal.journalRows + len(accessRows) + len(namespaceRows)
+	if journalRows >= journalCompactionMinRows && journalRows > tableRows {
+		return false, nil
+	}
+
+	b := flatbuffers.NewBuilder(1024)
+	accessBinlog := serializeBinlogRows(b, accessRows)
+	namespaceBinlog := serializeBinlogRows(b, namespaceRows)
+	serial.BranchControlAccessStart(b)
+	serial.BranchControlAccessAddBinlog(b, accessBinlog)
+	accessOffset := serial.BranchControlAccessEnd(b)
+	serial.BranchControlNamespaceStart(b)
+	serial.BranchControlNamespaceAddBinlog(b, namespaceBinlog)
+	namespaceOffset := serial.BranchControlNamespaceEnd(b)
+	pendingOffset := controller.PendingMoves.serialize(b)
+	freezesOffset := controller.Freezes.serialize(b)
+	defaultBranchOffset := controller.DefaultBranchChanges.serialize(b)
+	rolesOffset := controller.Roles.serialize(b)
+	serial.BranchControlStart(b)
+	serial.BranchControlAddAccessTbl(b, accessOffset)
+	serial.BranchControlAddNamespaceTbl(b, namespaceOffset)
+	serial.BranchControlAddPendingMoves(b, pendingOffset)
+	serial.BranchControlAddFreezes(b, freezesOffset)
+	serial.BranchControlAddDefaultBranchChanges(b, defaultBranchOffset)
+	serial.BranchControlAddRoles(b, rolesOffset)
+	root := serial.BranchControlEnd(b)
+	data := serial.FinishMessage(b, root, []byte(serial.BranchControlFileID))
+
+	f, err := os.OpenFile(controller.branchControlFilePath, os.O_WRONLY|os.O_APPEND, 0777)
+	if err != nil {
+		return false, err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A partial entry may have been written, so the next save must write a snapshot
+		controller.journal = journalState{}
+		return false, err
+	}
+	controller.journal.accessRows = accessLen
+	controller.journal.namespaceRows = namespaceLen
+	controller.journal.journalRows = journalRows
+	controller.journal.pendingVersion = pendingVersion
+	controller.journal.freezesVersion = freezesVersion
+	controller.journal.defaultBranchVersion = defaultBranchVersion
+	controller.journal.rolesVersion = rolesVersion
+	return true, nil
+}
+
+// replayJournal applies every journal entry in the given data, which is the remainder of the file after the snapshot,
+// to the controller's tables. A journal entry that was only partially written, such as when the process exits during a
+// save, ends the journal, and causes the next save to write a new snapshot.
+func (controller *Controller) replayJournal(data []byte) error {
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	controller.Namespace.RWMutex.Lock()
+	defer controller.Namespace.RWMutex.Unlock()
+	controller.PendingMoves.RWMutex.Lock()
+	defer controller.PendingMoves.RWMutex.Unlock()
+	controller.Freezes.RWMutex.Lock()
+	defer controller.Freezes.RWMutex.Unlock()
+	controller.DefaultBranchChanges.RWMutex.Lock()
+	defer controller.DefaultBranchChanges.RWMutex.Unlock()
+	controller.Roles.RWMutex.Lock()
+	defer controller.Roles.RWMutex.Unlock()
+
+	journalRows := 0
+	complete := true
+	for len(data) > 0 {
+		entry, rest, ok := nextMessage(data)
+		if !ok || serial.GetFileID(entry) != serial.BranchControlFileID {
+			complete = false
+			break
+		}
+		bc, err := serial.TryGetRootAsBranchControl(entry, serial.MessagePrefixSz)
+		if err != nil {
+			return err
+		}
+		accessRows, namespaceRows, err := journalEntryRows(bc)
+		if err != nil {
+			return err
+		}
+		for _, row := range accessRows {
+			if row.IsInsert {
+				controller.Access.Insert(row.accessValue())
+			} else {
+				controller.Access.Delete(row.Branch, row.User, row.Host)
+			}
+		}
+		for _, row := range namespaceRows {
+			if row.IsInsert {
+				controller.Namespace.Insert(row.Branch, row.User, row.Host)
+			} else {
+				controller.Namespace.Delete(row.Branch, row.User, row.Host)
+			}
+		}
+		if err = controller.PendingMoves.deserialize(bc); err != nil {
+			return err
+		}
+		if err = controller.Freezes.deserialize(bc); err != nil {
+			return err
+		}
+		if err = controller.DefaultBranchChanges.deserialize(bc); err != nil {
+			return err
+		}
+		if err = controller.Roles.deserialize(bc); err != nil {
+			return err
+		}
+		journalRows += len(accessRows) + len(namespaceRows)
+		data = rest
+	}
+
+	controller.journal = journalState{}
+	if complete {
+		controller.journal = controller.newJournalState()
+		controller.journal.journalRows = journalRows
+	}
+	return nil
+}
+
+// journalEntryRows returns the binlog rows of both tables from a journal entry.
+func journalEntryRows(bc *serial.BranchControl) (accessRows []BinlogRow, namespaceRows []BinlogRow, err error) {
+	access, err := bc.TryAccessTbl(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if access != nil {
+		binlog, err := access.TryBinlog(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if binlog != nil {
+			accessRows = deserializeBinlogRows(binlog)
+		}
+	}
+	namespace, err := bc.TryNamespaceTbl(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if namespace != nil {
+		binlog, err := namespace.TryBinlog(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if binlog != nil {
+			namespaceRows = deserializeBinlogRows(binlog)
+		}
+	}
+	return accessRows, namespaceRows, nil
+}
+
+// nextMessage splits the first message from the given data, using the size from the message's prefix. Returns false
+// if the data ends before the message does.
+func nextMessage(data []byte) (message []byte, rest []byte, ok bool) {
+	if len(data) < serial.MessagePrefixSz {
+		return nil, nil, false
+	}
+	size := serial.MessagePrefixSz + (int(data[1])<<16 | int(data[2])<<8 | int(data[3]))
+	if len(data) < size {
+		return nil, nil, false
+	}
+	return data[:size], data[size:], true
+}
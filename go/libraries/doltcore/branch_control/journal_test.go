@@ -0,0 +1,283 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	journalTestBranches = []string{"main", "%", "feature%", "release\\_%", "dev_"}
+	journalTestUsers    = []string{"alice", "bob", "%", "car_"}
+	journalTestHosts    = []string{"localhost", "%", "192.168.%"}
+)
+
+// newJournalTestController returns a controller that saves to the given file.
+func newJournalTestController(path string) *Controller {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	controller.branchControlFilePath = path
+	return controller
+}
+
+// loadJournalTestController returns a new controller with the data loaded from the given file.
+func loadJournalTestController(t *testing.T, path string) *Controller {
+	controller := newJournalTestController(path)
+	require.NoError(t, controller.load())
+	return controller
+}
+
+// mutateRandomly applies a random insert, update, or delete to one of the controller's tables.
+func mutateRandomly(r *rand.Rand, controller *Controller) {
+	branch := journalTestBranches[r.Intn(len(journalTestBranches))]
+	user := journalTestUsers[r.Intn(len(journalTestUsers))]
+	host := journalTestHosts[r.Intn(len(journalTestHosts))]
+	if r.Intn(4) == 0 {
+		if controller.Namespace.GetIndex(branch, user, host) == -1 {
+			controller.Namespace.Insert(branch, user, host)
+		} else {
+			controller.Namespace.Delete(branch, user, host)
+		}
+		return
+	}
+	value := AccessValue{
+		Branch:           branch,
+		User:             user,
+		Host:             host,
+		Permissions:      Permissions(r.Intn(3) + 1),
+		Operations:       Operations(1 << r.Intn(5)),
+		Priority:         int64(r.Intn(3)),
+		Window:           Window{Start: uint32(r.Intn(12)) * 3600, End: uint32(r.Intn(12)+12) * 3600, Days: Days(r.Intn(128))},
+		RequiresApproval: r.Intn(2) == 0,
+		ExpiresAt:        int64(r.Intn(2)) * wednesdayNoon.UnixMilli(),
+		Refs:             Refs(r.Intn(8)),
+		Table:            []string{"", "staging\\_%"}[r.Intn(2)],
+	}
+	if controller.Access.GetIndex(branch, user, host) == -1 {
+		controller.Access.Insert(value)
+	} else if r.Intn(2) == 0 {
+		// Updates are a deletion followed by an insertion, just as with the system table
+		controller.Access.Delete(branch, user, host)
+		controller.Access.Insert(value)
+	} else {
+		controller.Access.Delete(branch, user, host)
+	}
+}
+
+// requireSameControllerState verifies that both controllers contain the same entries and binlogs, and that they grant
+// the same permissions.
+func requireSameControllerState(t *testing.T, expected *Controller, actual *Controller) {
+	// Empty tables may be deserialized as empty slices rather than nil, so the slices are compared after appending to
+	// empty slices
+	require.Equal(t, append([]AccessValue{}, expected.Access.Values...), append([]AccessValue{}, actual.Access.Values...))
+	require.Equal(t, append([]NamespaceValue{}, expected.Namespace.Values...), append([]NamespaceValue{}, actual.Namespace.Values...))
+	require.Equal(t, append([]BinlogRow{}, expected.Access.binlog.Rows()...), append([]BinlogRow{}, actual.Access.binlog.Rows()...))
+	require.Equal(t, append([]BinlogRow{}, expected.Namespace.binlog.Rows()...), append([]BinlogRow{}, actual.Namespace.binlog.Rows()...))
+	require.Equal(t, append([]PendingMove{}, expected.PendingMoves.Values...), append([]PendingMove{}, actual.PendingMoves.Values...))
+	require.Equal(t, append([]Freeze{}, expected.Freezes.Values...), append([]Freeze{}, actual.Freezes.Values...))
+	require.Equal(t, append([]RoleMember{}, expected.Roles.Values...), append([]RoleMember{}, actual.Roles.Values...))
+	for _, branch := range []string{"main", "feature1", "release_1", "releasex1", "dev1", "other"} {
+		for _, user := range []string{"alice", "bob", "carl", "dave"} {
+			for _, host := range []string{"localhost", "192.168.1.1", "10.0.0.1"} {
+				for _, op := range []Operations{Operations_All, Operations_DirectDML, Operations_Merge} {
+					require.Equal(t, expected.Access.MatchDetailed(branch, user, host, op), actual.Access.MatchDetailed(branch, user, host, op),
+						"%s@%s on %s", user, host, branch)
+				}
+				require.Equal(t, expected.Namespace.CanCreate(branch, user, host), actual.Namespace.CanCreate(branch, user, host),
+					"%s@%s on %s", user, host, branch)
+			}
+		}
+	}
+}
+
+func TestJournalReplay(t *testing.T) {
+	oldMinRows := journalCompactionMinRows
+	journalCompactionMinRows = 16
+	defer func() {
+		journalCompactionMinRows = oldMinRows
+	}()
+
+	for seed := int64(0); seed < 25; seed++ {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			r := rand.New(rand.NewSource(seed))
+			path := filepath.Join(t.TempDir(), "branch_control.db")
+			controller := newJournalTestController(path)
+			for i := 0; i < 200; i++ {
+				mutateRandomly(r, controller)
+				switch r.Intn(10) {
+				case 0, 1, 2:
+					require.NoError(t, controller.save(false))
+				case 3:
+					require.NoError(t, controller.save(true))
+				case 4:
+					// Saving and then continuing from a loaded controller must also be equivalent
+					require.NoError(t, controller.save(false))
+					loaded := loadJournalTestController(t, path)
+					requireSameControllerState(t, controller, loaded)
+					controller = loaded
+				}
+			}
+			require.NoError(t, controller.save(false))
+			requireSameControllerState(t, controller, loadJournalTestController(t, path))
+		})
+	}
+}
+
+func TestJournalAppendsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, controller.save(false))
+	snapshot, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// A change is appended to the file, leaving the snapshot in place
+	controller.Access.Insert(AccessValue{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_Merge, Priority: 2})
+	controller.Namespace.Insert("dev%", "bob", "%")
+	require.NoError(t, controller.save(false))
+	journaled, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Greater(t, len(journaled), len(snapshot))
+	assert.True(t, bytes.HasPrefix(journaled, snapshot))
+	assert.Equal(t, 2, controller.journal.journalRows)
+
+	// Saving without any changes leaves the file untouched
+	require.NoError(t, controller.save(false))
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, journaled, unchanged)
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+
+	// Compacting replaces the journal with a new snapshot
+	require.NoError(t, controller.save(true))
+	compacted, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, rest, ok := nextMessage(compacted)
+	require.True(t, ok)
+	assert.Empty(t, rest)
+	assert.Equal(t, 0, controller.journal.journalRows)
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+
+	// Replacing the tables, such as through Reset, cannot be journaled
+	replaced := newJournalTestController(path)
+	replaced.journal = controller.journal
+	replaced.Access.Insert(AccessValue{Branch: "other", User: "carl", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, replaced.save(false))
+	requireSameControllerState(t, replaced, loadJournalTestController(t, path))
+}
+
+func TestJournalTruncatedEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	controller.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, controller.save(false))
+	controller.Access.Insert(AccessValue{Branch: "dev%", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	require.NoError(t, controller.save(false))
+	expected := loadJournalTestController(t, path)
+	controller.Access.Delete("main", "alice", "%")
+	require.NoError(t, controller.save(false))
+
+	// The final entry was only partially written, so it is ignored
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data[:len(data)-3], 0777))
+	loaded := loadJournalTestController(t, path)
+	requireSameControllerState(t, expected, loaded)
+	assert.Nil(t, loaded.journal.access)
+
+	// The next save must rewrite the file, as anything appended after the partial entry would be unreadable
+	loaded.Namespace.Insert("dev%", "bob", "%")
+	require.NoError(t, loaded.save(false))
+	requireSameControllerState(t, loaded, loadJournalTestController(t, path))
+}
+
+func TestJournalPendingMoves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	createdAt := time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+	controller.PendingMoves.put(PendingMove{Branch: "main", ProposedHash: "abc", User: "alice", Host: "localhost", CreatedAt: createdAt})
+	require.NoError(t, controller.save(false))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+
+	// Pending moves are journaled even when the tables are unchanged, and each entry replaces the previous moves
+	controller.PendingMoves.put(PendingMove{Branch: "main", ProposedHash: "def", User: "bob", Host: "%", CreatedAt: createdAt})
+	controller.PendingMoves.put(PendingMove{Branch: "dev", ProposedHash: "ghi", User: "alice", Host: "%", CreatedAt: createdAt})
+	require.NoError(t, controller.save(false))
+	loaded := loadJournalTestController(t, path)
+	requireSameControllerState(t, controller, loaded)
+	move, ok := loaded.PendingMoves.Get("main")
+	require.True(t, ok)
+	assert.Equal(t, "def", move.ProposedHash)
+
+	controller.PendingMoves.remove("main")
+	require.NoError(t, controller.save(false))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+	require.NoError(t, controller.save(true))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+}
+
+func TestJournalFreezes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	createdAt := time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+	controller.Freezes.put(Freeze{Branch: "main", User: "alice", Host: "localhost", Reason: "incident", CreatedAt: createdAt})
+	require.NoError(t, controller.save(false))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+
+	// Freezes are journaled in full in the same way as pending moves
+	controller.Freezes.put(Freeze{Branch: "release%", User: "bob", Host: "%", CreatedAt: createdAt})
+	controller.Freezes.remove("main")
+	require.NoError(t, controller.save(false))
+	loaded := loadJournalTestController(t, path)
+	requireSameControllerState(t, controller, loaded)
+	_, frozen := loaded.Freezes.Match("main")
+	assert.False(t, frozen)
+	freeze, frozen := loaded.Freezes.Match("release1")
+	require.True(t, frozen)
+	assert.Equal(t, "release%", freeze.Branch)
+
+	require.NoError(t, controller.save(true))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+}
+
+func TestJournalRoles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	controller := newJournalTestController(path)
+	controller.Access.Insert(AccessValue{Branch: "main", User: RoleUser("devs"), Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Roles.Insert(RoleMember{Role: "devs", User: "alice", Host: "%"})
+	require.NoError(t, controller.save(false))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+
+	// Roles are journaled in full in the same way as freezes
+	controller.Roles.Insert(RoleMember{Role: "devs", User: "bob", Host: "localhost"})
+	controller.Roles.Delete("devs", "alice", "%")
+	require.NoError(t, controller.save(false))
+	loaded := loadJournalTestController(t, path)
+	requireSameControllerState(t, controller, loaded)
+	assert.Equal(t, []string{"devs"}, loaded.Roles.Match("bob", "localhost"))
+	assert.Empty(t, loaded.Roles.Match("alice", "localhost"))
+
+	require.NoError(t, controller.save(true))
+	requireSameControllerState(t, controller, loadJournalTestController(t, path))
+}
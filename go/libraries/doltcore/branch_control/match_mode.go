@@ -0,0 +1,167 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// MatchModeVariable is the name of the global system variable that selects the MatchMode of the Access table.
+const MatchModeVariable = "dolt_branch_control_match_mode"
+
+// MatchMode determines how the permissions of multiple matching Access entries are combined. Entries from the base
+// source are matched alongside the overlay, except that an overlay entry replaces any base entry with the same
+// expressions, and is chosen over base entries that the mode would otherwise consider equal.
+type MatchMode string
+
+const (
+	MatchMode_Union        MatchMode = "union"         // MatchMode_Union combines the permissions of every matching entry
+	MatchMode_MostSpecific MatchMode = "most_specific" // MatchMode_MostSpecific uses the entries with the longest matching branch expression
+	MatchMode_Ordered      MatchMode = "ordered"       // MatchMode_Ordered uses the single matching entry with the highest priority
+)
+
+// MatchModes contains every MatchMode, in the order that they are presented to users.
+var MatchModes = []string{string(MatchMode_Union), string(MatchMode_MostSpecific), string(MatchMode_Ordered)}
+
+// currentMatchMode returns the MatchMode set by the system variable. Defaults to MatchMode_Union when the variable has
+// not been defined.
+func currentMatchMode() MatchMode {
+	_, val, ok := sql.SystemVariables.GetGlobal(MatchModeVariable)
+	if !ok {
+		return MatchMode_Union
+	}
+	if str, ok := val.(string); ok {
+		return MatchMode(strings.ToLower(str))
+	}
+	return MatchMode_Union
+}
+
+// strategy returns the matchStrategy that implements the MatchMode.
+func (mode MatchMode) strategy() matchStrategy {
+	switch mode {
+	case MatchMode_MostSpecific:
+		return mostSpecificMatchStrategy{}
+	case MatchMode_Ordered:
+		return orderedMatchStrategy{}
+	default:
+		return unionMatchStrategy{}
+	}
+}
+
+// matchStrategy determines which of the entries that have matched a branch, user, and host grant their permissions.
+type matchStrategy interface {
+	// filter returns the collection indexes whose permissions apply, which are always a subset of those whose operations
+	// include the given operation class. The returned slice shares the backing array of the given slice.
+	filter(tbl *Access, collectionIndexes []uint32, op Operations) []uint32
+}
+
+// unionMatchStrategy combines the permissions of all matching entries.
+type unionMatchStrategy struct{}
+
+var _ matchStrategy = unionMatchStrategy{}
+
+// filter implements the interface matchStrategy.
+func (unionMatchStrategy) filter(tbl *Access, collectionIndexes []uint32, op Operations) []uint32 {
+	filtered := collectionIndexes[:0]
+	for _, collectionIndex := range collectionIndexes {
+		if tbl.value(collectionIndex).Operations.Includes(op) {
+			filtered = append(filtered, collectionIndex)
+		}
+	}
+	return filtered
+}
+
+// mostSpecificMatchStrategy combines the permissions of the matching entries with the longest branch expression. This
+// is the same specificity that is used by the Namespace table. Base entries are only used when no overlay entry is as
+// specific.
+type mostSpecificMatchStrategy struct{}
+
+var _ matchStrategy = mostSpecificMatchStrategy{}
+
+// filter implements the interface matchStrategy.
+func (mostSpecificMatchStrategy) filter(tbl *Access, collectionIndexes []uint32, op Operations) []uint32 {
+	longest := -1
+	longestIsBase := false
+	filtered := collectionIndexes[:0]
+	for _, collectionIndex := range collectionIndexes {
+		value := tbl.value(collectionIndex)
+		if !value.Operations.Includes(op) {
+			continue
+		}
+		isBase := tbl.isBase(collectionIndex)
+		if len(value.Branch) > longest || (len(value.Branch) == longest && longestIsBase && !isBase) {
+			longest = len(value.Branch)
+			longestIsBase = isBase
+			filtered = append(filtered[:0], collectionIndex)
+		} else if len(value.Branch) == longest && isBase == longestIsBase {
+			filtered = append(filtered, collectionIndex)
+		}
+	}
+	return filtered
+}
+
+// orderedMatchStrategy uses the permissions of the matching entry with the highest priority. Among entries with the
+// same priority, overlay entries come before base entries, and are then ordered by their branch, user, and host
+// expressions.
+type orderedMatchStrategy struct{}
+
+var _ matchStrategy = orderedMatchStrategy{}
+
+// filter implements the interface matchStrategy.
+func (orderedMatchStrategy) filter(tbl *Access, collectionIndexes []uint32, op Operations) []uint32 {
+	var first *AccessValue
+	var firstIndex uint32
+	for _, collectionIndex := range collectionIndexes {
+		value := tbl.value(collectionIndex)
+		if !value.Operations.Includes(op) {
+			continue
+		}
+		if first == nil || value.Priority > first.Priority || (value.Priority == first.Priority && tbl.ranksBefore(collectionIndex, firstIndex)) {
+			first = value
+			firstIndex = collectionIndex
+		}
+	}
+	if first == nil {
+		return collectionIndexes[:0]
+	}
+	return append(collectionIndexes[:0], firstIndex)
+}
+
+// ranksBefore returns whether the entry at the first collection index comes before the entry at the second when their
+// priorities are the same. Overlay entries come before base entries, and are otherwise in the canonical sort order.
+func (tbl *Access) ranksBefore(collectionIndex uint32, otherIndex uint32) bool {
+	if isBase, otherIsBase := tbl.isBase(collectionIndex), tbl.isBase(otherIndex); isBase != otherIsBase {
+		return otherIsBase
+	}
+	return tbl.value(collectionIndex).sortsBefore(tbl.value(otherIndex))
+}
+
+// sortsBefore returns whether the calling value comes before the given value in the canonical sort order, which
+// compares the branch, user, and host expressions in that order. As in MySQL, more specific host expressions come
+// before less specific ones, so that a literal host is chosen over a pattern that matches the same client.
+func (val *AccessValue) sortsBefore(other *AccessValue) bool {
+	if val.Branch != other.Branch {
+		return val.Branch < other.Branch
+	}
+	if val.User != other.User {
+		return val.User < other.User
+	}
+	if specificity, otherSpecificity := hostSpecificity(val.Host), hostSpecificity(other.Host); specificity != otherSpecificity {
+		return specificity > otherSpecificity
+	}
+	return val.Host < other.Host
+}
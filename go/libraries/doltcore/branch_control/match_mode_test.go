@@ -0,0 +1,80 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchModes(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All, Priority: 0})
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: 0, Operations: Operations_All, Priority: 10})
+	access.Insert(AccessValue{Branch: "release%", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All, Priority: -5})
+	access.Insert(AccessValue{Branch: "release%", User: "%", Host: "%", Permissions: Permissions_Write, Operations: Operations_All, Priority: -5})
+	access.Insert(AccessValue{Branch: "%", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_Merge, Priority: 0})
+	access.Insert(AccessValue{Branch: "feature", User: "bob", Host: "%", Permissions: Permissions_Admin, Operations: Operations_DirectDML, Priority: 0})
+
+	tests := []struct {
+		branch       string
+		user         string
+		op           Operations
+		matched      bool
+		union        Permissions
+		mostSpecific Permissions
+		ordered      Permissions
+	}{
+		// The higher priority entry on main has no permissions, and is also the most specific
+		{"main", "alice", Operations_DirectDML, true, Permissions_Write, 0, 0},
+		{"other", "alice", Operations_DirectDML, true, Permissions_Write, Permissions_Write, Permissions_Write},
+		// Both release entries have the same length and priority, so the canonical sort places the "%" user first
+		{"release1", "alice", Operations_DirectDML, true, Permissions_Write | Permissions_Admin, Permissions_Write | Permissions_Admin, Permissions_Write},
+		{"release1", "carl", Operations_DirectDML, true, Permissions_Write, Permissions_Write, Permissions_Write},
+		// Entries that do not include the operation are ignored by every mode
+		{"feature", "bob", Operations_Merge, true, Permissions_Write, Permissions_Write, Permissions_Write},
+		{"feature", "bob", Operations_DirectDML, true, Permissions_Admin, Permissions_Admin, Permissions_Admin},
+		{"other", "bob", Operations_DirectDML, false, 0, 0, 0},
+		{"main", "carl", Operations_DirectDML, false, 0, 0, 0},
+	}
+
+	modes := []struct {
+		mode  MatchMode
+		perms func(idx int) Permissions
+	}{
+		{MatchMode_Union, func(idx int) Permissions { return tests[idx].union }},
+		{MatchMode_MostSpecific, func(idx int) Permissions { return tests[idx].mostSpecific }},
+		{MatchMode_Ordered, func(idx int) Permissions { return tests[idx].ordered }},
+	}
+	for _, mode := range modes {
+		for i, test := range tests {
+			t.Run(fmt.Sprintf("%s: %s on %s", mode.mode, test.user, test.branch), func(t *testing.T) {
+				matched, perms := access.matchWithStrategy(test.branch, test.user, []string{"localhost"}, test.op, mode.mode.strategy(), now())
+				assert.Equal(t, test.matched, matched)
+				assert.Equal(t, mode.perms(i), perms)
+			})
+		}
+	}
+
+	// The super user is unaffected by the mode
+	for _, mode := range modes {
+		matched, perms := access.matchWithStrategy("main", "root", []string{"localhost"}, Operations_All, mode.mode.strategy(), now())
+		assert.True(t, matched)
+		assert.Equal(t, Permissions_Admin, perms)
+	}
+}
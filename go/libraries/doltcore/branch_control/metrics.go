@@ -0,0 +1,113 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+// MetricActions are the actions that checks and denials are counted by, which are the operation classes along with the
+// creation, deletion, and reading of branches, changes of a database's default branch, and pushes to and fetches from
+// remotes. This is a fixed set, so that metrics labeled by action have a bounded cardinality.
+var MetricActions = []string{"all", "direct_dml", "merge", "ref_move", "tag", "create_branch", "delete_branch", "default_branch", "read_branch", "push", "fetch"}
+
+// MatchLatencyBuckets are the upper bounds, in seconds, of the buckets that the latencies of matching entries are
+// counted in.
+var MatchLatencyBuckets = []float64{0.000001, 0.00001, 0.0001, 0.001, 0.01, 0.1}
+
+// SaveDataBuckets are the upper bounds, in seconds, of the buckets that the durations of saves are counted in.
+var SaveDataBuckets = []float64{0.001, 0.01, 0.1, 1, 10}
+
+// Metrics are the counts of branch control's activity since the process started, along with the current size of the
+// tables.
+type Metrics struct {
+	// Checks and Denials are keyed by each of the MetricActions. Denials include those that were only audited.
+	Checks  map[string]uint64
+	Denials map[string]uint64
+	// AccessRules and NamespaceRules are the number of entries in each table, excluding those of the base
+	AccessRules      int
+	NamespaceRules   int
+	SaveDataFailures uint64
+	SaveDataDuration histogram.Snapshot
+	MatchLatency     histogram.Snapshot
+}
+
+var (
+	checkCounts      = make([]uint64, len(MetricActions))
+	denialCounts     = make([]uint64, len(MetricActions))
+	saveDataFailures uint64
+	saveDataDuration = histogram.New(SaveDataBuckets)
+	matchLatency     = histogram.New(MatchLatencyBuckets)
+)
+
+// actionIndex returns the index of the given action within MetricActions. Unknown actions are counted as "all".
+func actionIndex(action string) int {
+	for i, metricAction := range MetricActions {
+		if metricAction == action {
+			return i
+		}
+	}
+	return 0
+}
+
+// recordCheck counts a check of the given action, whose entries took the given duration to match.
+func recordCheck(action string, matchDuration time.Duration) {
+	atomic.AddUint64(&checkCounts[actionIndex(action)], 1)
+	matchLatency.Observe(matchDuration)
+}
+
+// recordDenial counts a denial of the given action.
+func recordDenial(action string) {
+	atomic.AddUint64(&denialCounts[actionIndex(action)], 1)
+}
+
+// recordSave counts a save of the controller's data that took the given duration.
+func recordSave(duration time.Duration, err error) {
+	saveDataDuration.Observe(duration)
+	if err != nil {
+		atomic.AddUint64(&saveDataFailures, 1)
+	}
+}
+
+// GetMetrics returns the metrics of branch control, where the rule counts are those of the static controller.
+func GetMetrics() Metrics {
+	metrics := Metrics{
+		Checks:           make(map[string]uint64, len(MetricActions)),
+		Denials:          make(map[string]uint64, len(MetricActions)),
+		SaveDataFailures: atomic.LoadUint64(&saveDataFailures),
+		SaveDataDuration: saveDataDuration.Snapshot(),
+		MatchLatency:     matchLatency.Snapshot(),
+	}
+	for i, action := range MetricActions {
+		metrics.Checks[action] = atomic.LoadUint64(&checkCounts[i])
+		metrics.Denials[action] = atomic.LoadUint64(&denialCounts[i])
+	}
+
+	controller := StaticController
+	if controller.Access != nil {
+		controller.Access.RWMutex.RLock()
+		metrics.AccessRules = len(controller.Access.Values)
+		controller.Access.RWMutex.RUnlock()
+	}
+	if controller.Namespace != nil {
+		controller.Namespace.RWMutex.RLock()
+		metrics.NamespaceRules = len(controller.Namespace.Values)
+		controller.Namespace.RWMutex.RUnlock()
+	}
+	return metrics
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetrics(t *testing.T) {
+	enableForTest(t)
+
+	before := GetMetrics()
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_Merge})
+	StaticController.Namespace.Insert("other%", "root", "localhost")
+	ctx := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	assert.NoError(t, CheckAccess(ctx, Permissions_Write, Operations_Merge))
+	assert.Error(t, CheckAccess(ctx, Permissions_Write, Operations_Tag))
+	assert.Error(t, CanCreateBranch(ctx, "otherbranch"))
+
+	after := GetMetrics()
+	assert.Equal(t, 1, after.AccessRules)
+	assert.Equal(t, 1, after.NamespaceRules)
+	assert.Equal(t, before.Checks["merge"]+1, after.Checks["merge"])
+	assert.Equal(t, before.Checks["tag"]+1, after.Checks["tag"])
+	assert.Equal(t, before.Checks["create_branch"]+1, after.Checks["create_branch"])
+	assert.Equal(t, before.Denials["merge"], after.Denials["merge"])
+	assert.Equal(t, before.Denials["tag"]+1, after.Denials["tag"])
+	assert.Equal(t, before.Denials["create_branch"]+1, after.Denials["create_branch"])
+	assert.Equal(t, before.MatchLatency.Count+3, after.MatchLatency.Count)
+	for _, action := range MetricActions {
+		assert.Contains(t, after.Checks, action)
+		assert.Contains(t, after.Denials, action)
+	}
+
+	// Saves are timed, and failures are counted
+	dir := t.TempDir()
+	StaticController.branchControlFilePath = filepath.Join(dir, "branch_control.db")
+	require.NoError(t, SaveData(ctx))
+	saved := GetMetrics()
+	assert.Equal(t, after.SaveDataDuration.Count+1, saved.SaveDataDuration.Count)
+	assert.Equal(t, after.SaveDataFailures, saved.SaveDataFailures)
+
+	blocker := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	StaticController.branchControlFilePath = filepath.Join(blocker, "branch_control.db")
+	require.Error(t, CompactData(testSessionContext{Context: context.Background(), user: "root", host: "localhost"}))
+	failed := GetMetrics()
+	assert.Equal(t, saved.SaveDataDuration.Count+1, failed.SaveDataDuration.Count)
+	assert.Equal(t, saved.SaveDataFailures+1, failed.SaveDataFailures)
+}
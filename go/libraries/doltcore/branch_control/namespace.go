@@ -30,6 +30,9 @@ import (
 type Namespace struct {
 	access *Access
 	binlog *Binlog
+	// base contains the read-only entries that were loaded from the base source, following the same rules as the base of
+	// the Access table. Nil when there is no base source.
+	base *Namespace
 
 	Branches  []MatchExpression
 	Users     []MatchExpression
@@ -38,6 +41,8 @@ type Namespace struct {
 	SuperUser string
 	SuperHost string
 	RWMutex   *sync.RWMutex
+	// loadedDuplicates is the number of exact duplicate entries that were dropped when the table was deserialized
+	loadedDuplicates int
 }
 
 // NamespaceValue contains the user-facing values of a particular row.
@@ -63,34 +68,59 @@ func newNamespace(accessTbl *Access, superUser string, superHost string) *Namesp
 }
 
 // CanCreate checks the given branch, and returns whether the given user and host combination is able to create that
-// branch. Handles the super user case.
+// branch. Handles the super user case. Only the entries of the most specific namespace that matches the branch are used,
+// following the ordering of the namespace hierarchy. The entries of the base are only used when they contain a more
+// specific matching branch expression than this table.
 func (tbl *Namespace) CanCreate(branch string, user string, host string) bool {
 	// Super user can always create branches
 	if user == tbl.SuperUser && host == tbl.SuperHost {
 		return true
 	}
-	matchedSet := Match(tbl.Branches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	filteredIndexes, spec, matched := tbl.mostSpecificMatches(branch)
+	if tbl.base != nil {
+		baseIndexes, baseSpec, baseMatched := tbl.base.mostSpecificMatches(branch)
+		if baseMatched && (!matched || baseSpec.compare(spec) > 0) {
+			indexPool.Put(filteredIndexes)
+			return tbl.base.matchesUserHost(baseIndexes, user, host)
+		}
+		indexPool.Put(baseIndexes)
+	}
 	// If there are no branch entries, then the Namespace is unrestricted
-	if len(matchedSet) == 0 {
-		indexPool.Put(matchedSet)
+	if !matched {
+		indexPool.Put(filteredIndexes)
 		return true
 	}
+	return tbl.matchesUserHost(filteredIndexes, user, host)
+}
 
-	// We take either the longest match, or the set of longest matches if multiple matches have the same length
-	longest := -1
+// mostSpecificMatches returns the collection indexes of the entries with the most specific branch expression that
+// matches the given branch, along with the specificity of that expression and whether any entries matched. The returned
+// slice comes from the index pool.
+func (tbl *Namespace) mostSpecificMatches(branch string) ([]uint32, specificity, bool) {
+	matchedSet := Match(tbl.Branches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	// We take either the most specific match, or the set of matches if multiple matches are equally specific
+	var mostSpecific specificity
+	matched := false
 	filteredIndexes := indexPool.Get().([]uint32)[:0]
-	for _, matched := range matchedSet {
-		matchedValue := tbl.Values[matched]
-		// If we've found a longer match, then we reset the slice. We append to it in the following if statement.
-		if len(matchedValue.Branch) > longest {
+	for _, matchedIndex := range matchedSet {
+		matchedSpec := expressionSpecificity(tbl.Values[matchedIndex].Branch)
+		// If we've found a more specific match, then we reset the slice. We append to it in the following if statement.
+		if !matched || matchedSpec.compare(mostSpecific) > 0 {
 			filteredIndexes = filteredIndexes[:0]
+			mostSpecific = matchedSpec
+			matched = true
 		}
-		if len(matchedValue.Branch) >= longest {
-			filteredIndexes = append(filteredIndexes, matched)
+		if matchedSpec.compare(mostSpecific) >= 0 {
+			filteredIndexes = append(filteredIndexes, matchedIndex)
 		}
 	}
 	indexPool.Put(matchedSet)
+	return filteredIndexes, mostSpecific, matched
+}
 
+// matchesUserHost returns whether any of the given collection indexes match the given user and host. The given slice
+// is returned to the index pool.
+func (tbl *Namespace) matchesUserHost(filteredIndexes []uint32, user string, host string) bool {
 	filteredUsers := tbl.filterUsers(filteredIndexes)
 	indexPool.Put(filteredIndexes)
 	filteredIndexes = Match(filteredUsers, user, sql.Collation_utf8mb4_0900_bin)
@@ -127,6 +157,11 @@ func (tbl *Namespace) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	tbl.RWMutex.RLock()
 	defer tbl.RWMutex.RUnlock()
 
+	return tbl.serialize(b)
+}
+
+// serialize is the same as Serialize, except that it requires external synchronization handling.
+func (tbl *Namespace) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
 	// Serialize the binlog
 	binlog := tbl.binlog.Serialize(b)
 	// Initialize field offset slices
@@ -232,9 +267,48 @@ func (tbl *Namespace) Deserialize(fb *serial.BranchControlNamespace) error {
 			Host:   string(serialNamespaceValue.Host()),
 		}
 	}
+	tbl.loadedDuplicates = tbl.dropExactDuplicates()
 	return nil
 }
 
+// Insert adds the given entry to the table and the binlog. Assumes that the expressions have already been folded, and
+// that the entry does not already exist. Requires external synchronization handling.
+func (tbl *Namespace) Insert(branch string, user string, host string) {
+	nextIdx := uint32(len(tbl.Values))
+	tbl.Branches = append(tbl.Branches, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(branch, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Users = append(tbl.Users, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(user, sql.Collation_utf8mb4_0900_bin)})
+	tbl.Hosts = append(tbl.Hosts, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(host, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Values = append(tbl.Values, NamespaceValue{
+		Branch: branch,
+		User:   user,
+		Host:   host,
+	})
+	tbl.binlog.Insert(branch, user, host, 0)
+}
+
+// Delete removes the given entry from the table and writes the removal to the binlog. Does nothing if the entry does
+// not exist. Requires external synchronization handling.
+func (tbl *Namespace) Delete(branch string, user string, host string) {
+	tblIndex := tbl.GetIndex(branch, user, host)
+	if tblIndex == -1 {
+		return
+	}
+	tbl.binlog.Delete(branch, user, host, 0)
+	endIndex := len(tbl.Values) - 1
+	tbl.Branches[tblIndex], tbl.Branches[endIndex] = tbl.Branches[endIndex], tbl.Branches[tblIndex]
+	tbl.Users[tblIndex], tbl.Users[endIndex] = tbl.Users[endIndex], tbl.Users[tblIndex]
+	tbl.Hosts[tblIndex], tbl.Hosts[endIndex] = tbl.Hosts[endIndex], tbl.Hosts[tblIndex]
+	tbl.Values[tblIndex], tbl.Values[endIndex] = tbl.Values[endIndex], tbl.Values[tblIndex]
+	// The swapped element must now reference its new position
+	tbl.Branches[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Users[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Hosts[tblIndex].CollectionIndex = uint32(tblIndex)
+	tbl.Branches = tbl.Branches[:endIndex]
+	tbl.Users = tbl.Users[:endIndex]
+	tbl.Hosts = tbl.Hosts[:endIndex]
+	tbl.Values = tbl.Values[:endIndex]
+}
+
 // filterBranches returns all branches that match the given collection indexes.
 func (tbl *Namespace) filterBranches(filters []uint32) []MatchExpression {
 	if len(filters) == 0 {
@@ -0,0 +1,107 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Namespaces form a hierarchy through their branch expressions, so that an entry for "team1/%" also applies to the
+// nested namespace "team1/alice/%" until the nested namespace is given entries of its own. When several namespaces match
+// a branch, only the entries of the most specific namespace are used. A namespace is more specific when it has a longer
+// literal prefix, which is every character before its first wildcard. Namespaces with the same literal prefix are then
+// ordered by their total number of literal characters, so that "team1/a_ice" is more specific than "team1/a%". Entries
+// of namespaces that are equally specific are combined.
+
+// specificity is the position of a branch expression within the namespace hierarchy.
+type specificity struct {
+	// prefix is the number of characters before the first wildcard
+	prefix int
+	// literals is the number of characters that are not wildcards
+	literals int
+}
+
+// expressionSpecificity returns the specificity of the given folded branch expression. An escaped character counts as a
+// single literal character.
+func expressionSpecificity(branchExpr string) specificity {
+	var spec specificity
+	inPrefix := true
+	escaped := false
+	for _, r := range branchExpr {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+			continue
+		case r == '%' || r == '_':
+			inPrefix = false
+			continue
+		}
+		spec.literals++
+		if inPrefix {
+			spec.prefix++
+		}
+	}
+	return spec
+}
+
+// compare returns a positive number when the calling specificity is more specific than the given specificity, a
+// negative number when it is less specific, and zero when they're equally specific.
+func (spec specificity) compare(other specificity) int {
+	if spec.prefix != other.prefix {
+		return spec.prefix - other.prefix
+	}
+	return spec.literals - other.literals
+}
+
+// InheritsFrom returns the branch expression of the namespace that the given folded branch expression inherits from,
+// which is the most specific namespace that covers every branch of the given expression while being less specific than
+// it. Namespaces of the base are included. As comparing two expressions is not exact, a namespace covers the expression
+// when it matches the expression as though it were a branch name. Ties are broken by choosing the lowest expression, so
+// that the inheritance source is deterministic. Returns false when the expression is not nested within any namespace.
+// Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Namespace) InheritsFrom(branchExpr string) (string, bool) {
+	spec := expressionSpecificity(branchExpr)
+	parent, parentSpec, found := tbl.inheritsFrom(branchExpr, spec)
+	if tbl.base != nil {
+		baseParent, baseSpec, baseFound := tbl.base.inheritsFrom(branchExpr, spec)
+		if baseFound && (!found || baseSpec.compare(parentSpec) > 0 || (baseSpec.compare(parentSpec) == 0 && baseParent < parent)) {
+			return baseParent, true
+		}
+	}
+	return parent, found
+}
+
+// inheritsFrom is the same as InheritsFrom, except that the base is not considered.
+func (tbl *Namespace) inheritsFrom(branchExpr string, spec specificity) (string, specificity, bool) {
+	matchedSet := Match(tbl.Branches, branchExpr, sql.Collation_utf8mb4_0900_ai_ci)
+	defer indexPool.Put(matchedSet)
+
+	var parent string
+	var parentSpec specificity
+	found := false
+	for _, matched := range matchedSet {
+		candidate := tbl.Values[matched].Branch
+		candidateSpec := expressionSpecificity(candidate)
+		if candidate == branchExpr || candidateSpec.compare(spec) >= 0 {
+			continue
+		}
+		if !found || candidateSpec.compare(parentSpec) > 0 || (candidateSpec.compare(parentSpec) == 0 && candidate < parent) {
+			parent, parentSpec, found = candidate, candidateSpec, true
+		}
+	}
+	return parent, parentSpec, found
+}
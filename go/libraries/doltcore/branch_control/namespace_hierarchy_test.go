@@ -0,0 +1,59 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpressionSpecificity(t *testing.T) {
+	assert.Equal(t, specificity{prefix: 6, literals: 6}, expressionSpecificity("team1/%"))
+	assert.Equal(t, specificity{prefix: 12, literals: 12}, expressionSpecificity("team1/alice/%"))
+	assert.Equal(t, specificity{prefix: 0, literals: 7}, expressionSpecificity("%/alice/%"))
+	assert.Equal(t, specificity{prefix: 8, literals: 8}, expressionSpecificity("release\\_%"))
+	// A longer literal prefix is more specific, regardless of the length of the expression
+	assert.Positive(t, expressionSpecificity("team1/%").compare(expressionSpecificity("%/alice/%")))
+	assert.Positive(t, expressionSpecificity("team1/a_ice").compare(expressionSpecificity("team1/a%")))
+	assert.Zero(t, expressionSpecificity("team1/%").compare(expressionSpecificity("team2/%")))
+}
+
+func TestNamespaceHierarchy(t *testing.T) {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	namespace := controller.Namespace
+	namespace.Insert("team1/%", "alice", "%")
+	namespace.Insert("team1/bob/%", "bob", "%")
+	namespace.Insert("%/shared/%", "carl", "%")
+
+	// Nested namespaces use their parent's entries until they're given their own
+	assert.True(t, namespace.CanCreate("team1/alice/feature", "alice", "localhost"))
+	assert.False(t, namespace.CanCreate("team1/alice/feature", "bob", "localhost"))
+	assert.True(t, namespace.CanCreate("team1/bob/feature", "bob", "localhost"))
+	assert.False(t, namespace.CanCreate("team1/bob/feature", "alice", "localhost"))
+	// The literal prefix of "team1/%" is longer than that of "%/shared/%", so it's the more specific namespace
+	assert.True(t, namespace.CanCreate("team1/shared/feature", "alice", "localhost"))
+	assert.False(t, namespace.CanCreate("team1/shared/feature", "carl", "localhost"))
+	assert.True(t, namespace.CanCreate("team2/shared/feature", "carl", "localhost"))
+
+	parent, ok := namespace.InheritsFrom("team1/bob/%")
+	assert.True(t, ok)
+	assert.Equal(t, "team1/%", parent)
+	_, ok = namespace.InheritsFrom("team1/%")
+	assert.False(t, ok)
+	_, ok = namespace.InheritsFrom("%/shared/%")
+	assert.False(t, ok)
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// Access entries may require approval for ref moves. A user that is not an admin on such a branch may only propose a
+// move of its head, which is held as a pending move until a different user that is an admin on the branch approves or
+// rejects it. Pending moves are saved alongside the tables, so that they survive restarts.
+
+// PendingMove is a proposed move of a branch's head that has not yet been approved or rejected.
+type PendingMove struct {
+	Branch       string
+	ProposedHash string
+	User         string
+	Host         string
+	CreatedAt    time.Time
+}
+
+// PendingMoves contains the pending move of each branch, as a branch has at most one pending move at a time.
+type PendingMoves struct {
+	Values []PendingMove
+	// version is incremented on every modification, so that a save is able to determine whether the moves changed
+	version uint64
+	RWMutex *sync.RWMutex
+}
+
+// newPendingMoves returns a new PendingMoves.
+func newPendingMoves() *PendingMoves {
+	return &PendingMoves{
+		Values:  nil,
+		RWMutex: &sync.RWMutex{},
+	}
+}
+
+// Get returns the pending move of the given branch, along with whether the branch has one. Requires external
+// synchronization handling, therefore manually manage the RWMutex.
+func (tbl *PendingMoves) Get(branch string) (PendingMove, bool) {
+	if idx := tbl.getIndex(branch); idx != -1 {
+		return tbl.Values[idx], true
+	}
+	return PendingMove{}, false
+}
+
+// getIndex returns the index of the given branch's pending move, or -1 if it does not have one.
+func (tbl *PendingMoves) getIndex(branch string) int {
+	for i, move := range tbl.Values {
+		if move.Branch == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// put adds the given move, replacing the pending move of the same branch. Requires external synchronization handling.
+func (tbl *PendingMoves) put(move PendingMove) {
+	tbl.version++
+	if idx := tbl.getIndex(move.Branch); idx != -1 {
+		tbl.Values[idx] = move
+		return
+	}
+	tbl.Values = append(tbl.Values, move)
+}
+
+// remove removes the pending move of the given branch. Requires external synchronization handling.
+func (tbl *PendingMoves) remove(branch string) {
+	if idx := tbl.getIndex(branch); idx != -1 {
+		tbl.version++
+		tbl.Values = append(tbl.Values[:idx], tbl.Values[idx+1:]...)
+	}
+}
+
+// serialize returns the offset of the vector of pending moves written to the given builder. Requires external
+// synchronization handling.
+func (tbl *PendingMoves) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, move := range tbl.Values {
+		branch := b.CreateString(move.Branch)
+		proposedHash := b.CreateString(move.ProposedHash)
+		user := b.CreateString(move.User)
+		host := b.CreateString(move.Host)
+		serial.BranchControlPendingMoveStart(b)
+		serial.BranchControlPendingMoveAddBranch(b, branch)
+		serial.BranchControlPendingMoveAddProposedHash(b, proposedHash)
+		serial.BranchControlPendingMoveAddUser(b, user)
+		serial.BranchControlPendingMoveAddHost(b, host)
+		serial.BranchControlPendingMoveAddCreatedAt(b, move.CreatedAt.UnixMilli())
+		offsets[i] = serial.BranchControlPendingMoveEnd(b)
+	}
+	serial.BranchControlStartPendingMovesVector(b, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
+
+// deserialize replaces the pending moves with those of the given snapshot or journal entry. Requires external
+// synchronization handling.
+func (tbl *PendingMoves) deserialize(bc *serial.BranchControl) error {
+	values := make([]PendingMove, bc.PendingMovesLength())
+	for i := range values {
+		serialMove := &serial.BranchControlPendingMove{}
+		if _, err := bc.TryPendingMoves(serialMove, i); err != nil {
+			return err
+		}
+		values[i] = PendingMove{
+			Branch:       string(serialMove.Branch()),
+			ProposedHash: string(serialMove.ProposedHash()),
+			User:         string(serialMove.User()),
+			Host:         string(serialMove.Host()),
+			CreatedAt:    time.UnixMilli(serialMove.CreatedAt()).UTC(),
+		}
+	}
+	tbl.version++
+	tbl.Values = values
+	return nil
+}
+
+// CheckRefMove returns whether the given context may move the head of the given branch, which is the same as checking
+// for write permissions on ref moves through CheckAccess. In addition, returns true when the move must be proposed
+// through ProposeMove rather than applied, which is the case when the user is not an admin on the branch and any of the
+// entries that granted their permissions require approval. In audit mode, a move without write permissions is reported
+// and allowed, while approval is still required by the entries that grant permissions.
+func CheckRefMove(ctx context.Context, branch string) (bool, error) {
+	if !enabled {
+		return false, nil
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow all operations
+	if branchAwareSession == nil {
+		return false, nil
+	}
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	matchStart := time.Now()
+	result := StaticController.Access.MatchClientDetailed(branch, user, host, Operations_RefMove)
+	recordCheck(operationAction(Operations_RefMove), time.Since(matchStart))
+	if err := checkFrozen(ctx, user, host, branch, operationAction(Operations_RefMove)); err != nil {
+		return false, err
+	}
+	if result.Permissions&Permissions_Admin == Permissions_Admin {
+		return false, nil
+	}
+	if result.Permissions&Permissions_Write != Permissions_Write {
+		return false, enforce(ctx, Denial{User: user, Host: host, Branch: branch, Action: operationAction(Operations_RefMove), Err: ErrIncorrectPermissions.New(user, host, branch)})
+	}
+	for _, collectionIndex := range result.Indexes {
+		if StaticController.Access.value(collectionIndex).RequiresApproval {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ProposeMove records a proposal to move the head of the given branch to the given commit. A branch has at most one
+// pending move, so a proposal is rejected while another is pending, rather than replacing the move that an approver may
+// be reviewing. The context's user must be able to move the branch's head, although they do not need to be required to
+// propose the move.
+func ProposeMove(ctx context.Context, branch string, proposedHash string) error {
+	if _, err := CheckRefMove(ctx, branch); err != nil {
+		return err
+	}
+	move := PendingMove{Branch: branch, ProposedHash: proposedHash, CreatedAt: now()}
+	if branchAwareSession := GetBranchAwareSession(ctx); branchAwareSession != nil {
+		move.User = branchAwareSession.GetUser()
+		move.Host = branchAwareSession.GetHost()
+	}
+
+	StaticController.PendingMoves.RWMutex.Lock()
+	defer StaticController.PendingMoves.RWMutex.Unlock()
+	if pending, ok := StaticController.PendingMoves.Get(branch); ok {
+		return ErrMovePending.New(branch, pending.ProposedHash)
+	}
+	StaticController.PendingMoves.put(move)
+	return nil
+}
+
+// ApproveMove applies the pending move of the given branch by calling the given function, and removes the move once it
+// has been applied. The pending move must be to |expectedHash|, so that an approver never applies a move other than
+// the one they reviewed. The context's user must be an admin on the branch, and must not be the user that proposed the
+// move. The pending moves remain locked while the function is called, so that a move is never applied twice.
+func ApproveMove(ctx context.Context, branch string, expectedHash string, apply func(move PendingMove) error) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession != nil && !isBranchAdmin(branch, branchAwareSession.GetUser(), branchAwareSession.GetHost()) {
+		return ErrApprovingMove.New(branchAwareSession.GetUser(), branchAwareSession.GetHost(), branch)
+	}
+
+	StaticController.PendingMoves.RWMutex.Lock()
+	defer StaticController.PendingMoves.RWMutex.Unlock()
+
+	move, ok := StaticController.PendingMoves.Get(branch)
+	if !ok {
+		return ErrNoPendingMove.New(branch)
+	}
+	if branchAwareSession != nil && branchAwareSession.GetUser() == move.User {
+		return ErrApprovingOwnMove.New(branchAwareSession.GetUser(), branchAwareSession.GetHost(), branch)
+	}
+	if move.ProposedHash != expectedHash {
+		return ErrUnexpectedPendingMove.New(branch, move.ProposedHash, expectedHash)
+	}
+	if err := apply(move); err != nil {
+		return err
+	}
+	StaticController.PendingMoves.remove(branch)
+	return nil
+}
+
+// RejectMove removes the pending move of the given branch without applying it. The context's user must be an admin on
+// the branch, or the user that proposed the move, so that a proposal may be withdrawn.
+func RejectMove(ctx context.Context, branch string) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	isAdmin := branchAwareSession == nil || isBranchAdmin(branch, branchAwareSession.GetUser(), branchAwareSession.GetHost())
+
+	StaticController.PendingMoves.RWMutex.Lock()
+	defer StaticController.PendingMoves.RWMutex.Unlock()
+
+	move, ok := StaticController.PendingMoves.Get(branch)
+	if !ok {
+		return ErrNoPendingMove.New(branch)
+	}
+	if !isAdmin && branchAwareSession.GetUser() != move.User {
+		return ErrApprovingMove.New(branchAwareSession.GetUser(), branchAwareSession.GetHost(), branch)
+	}
+	StaticController.PendingMoves.remove(branch)
+	return nil
+}
+
+// isBranchAdmin returns whether the given user is an admin on the given branch for ref moves. The Access table is
+// locked before the pending moves are, so this must not be called while the pending moves are locked.
+func isBranchAdmin(branch string, user string, host string) bool {
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+	_, perms := StaticController.Access.MatchClientOperationAsOf(branch, user, host, Operations_RefMove, now())
+	return perms&Permissions_Admin == Permissions_Admin
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Prune removes every entry from the Access and Namespace tables whose branch expression matches the given pattern. The
+// stored branch expressions are matched as though they were branch names, so the pattern "feature%" removes entries for
+// both "feature1" and "feature%". The super user is never removed, as it is not stored in the tables. The context's
+// user must be an admin over all branches. Returns the number of removed Access and Namespace entries.
+func (controller *Controller) Prune(ctx context.Context, branchPattern string) (accessCount int, namespaceCount int, err error) {
+	pattern := []MatchExpression{{
+		CollectionIndex: 0,
+		SortOrders:      ParseExpression(strings.ToLower(FoldExpression(branchPattern)), sql.Collation_utf8mb4_0900_ai_ci),
+	}}
+
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	controller.Namespace.RWMutex.Lock()
+	defer controller.Namespace.RWMutex.Unlock()
+
+	if err = controller.checkGlobalAdmin(ctx, ErrPrunePermissions); err != nil {
+		return 0, 0, err
+	}
+
+	// Matching entries are collected first, as deleting an entry reorders the remaining entries
+	var accessValues []AccessValue
+	for _, value := range controller.Access.Values {
+		if branchMatchesPattern(pattern, value.Branch) {
+			accessValues = append(accessValues, value)
+		}
+	}
+	var namespaceValues []NamespaceValue
+	for _, value := range controller.Namespace.Values {
+		if branchMatchesPattern(pattern, value.Branch) {
+			namespaceValues = append(namespaceValues, value)
+		}
+	}
+	// Pruning is a rule edit, so it may not remove the entries of frozen branches
+	prunedExprs := make([]string, 0, len(accessValues)+len(namespaceValues))
+	for _, value := range accessValues {
+		prunedExprs = append(prunedExprs, value.Branch)
+	}
+	for _, value := range namespaceValues {
+		prunedExprs = append(prunedExprs, value.Branch)
+	}
+	if err = controller.checkRuleEdit(ctx, prunedExprs...); err != nil {
+		return 0, 0, err
+	}
+	for _, value := range accessValues {
+		controller.Access.Delete(value.Branch, value.User, value.Host)
+	}
+	for _, value := range namespaceValues {
+		controller.Namespace.Delete(value.Branch, value.User, value.Host)
+	}
+	return len(accessValues), len(namespaceValues), nil
+}
+
+// branchMatchesPattern returns whether the given branch expression matches the pattern.
+func branchMatchesPattern(pattern []MatchExpression, branch string) bool {
+	matches := Match(pattern, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	if matches == nil {
+		return false
+	}
+	defer indexPool.Put(matches)
+	return len(matches) > 0
+}
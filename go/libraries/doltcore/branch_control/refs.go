@@ -0,0 +1,142 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// Entries of the Access table apply to branches by default, but may instead, or additionally, apply to tags and to the
+// refs of remotes. The branch expression of an entry is matched against the name of each kind of ref that the entry
+// applies to: the branch name for branches, the tag name for tags, and the remote's name followed by a slash and the
+// remote branch or tag for remotes, such as "origin/main". Tags and remotes are unrestricted until the first entry
+// applying to them is added, so that existing tables continue to behave as they did before refs existed. Once they are
+// restricted, creating or deleting a tag requires write permissions on the tag, pushing requires write permissions on
+// the remote ref being written, and fetching requires any permission on the remote ref being fetched.
+
+// Refs are a set of flags that denote the kinds of refs that an entry applies to.
+type Refs uint8
+
+const (
+	Refs_Branches Refs = 1 << iota // Refs_Branches applies an entry to branches
+	Refs_Tags                      // Refs_Tags applies an entry to the creation and deletion of tags
+	Refs_Remotes                   // Refs_Remotes applies an entry to pushes and fetches of the refs of remotes
+
+	Refs_All = Refs_Branches | Refs_Tags | Refs_Remotes
+)
+
+// NewRefs returns the given refs, with only branches stored as the zero value, so that entries applying to branches
+// are equal to those that were written before refs existed.
+func NewRefs(refs Refs) Refs {
+	if refs == Refs_Branches {
+		return 0
+	}
+	return refs
+}
+
+// Validate returns an error if the refs contain flags that do not denote a kind of ref.
+func (refs Refs) Validate() error {
+	if refs&^Refs_All != 0 {
+		return ErrInvalidRefs.New(refs)
+	}
+	return nil
+}
+
+// OrBranches returns the refs, with the zero value represented by Refs_Branches.
+func (refs Refs) OrBranches() Refs {
+	if refs == 0 {
+		return Refs_Branches
+	}
+	return refs
+}
+
+// Includes returns whether the calling set of refs includes the given kind of ref.
+func (refs Refs) Includes(kind Refs) bool {
+	return refs.OrBranches()&kind == kind
+}
+
+// RemoteRefName returns the name that the branch expressions of entries applying to remotes are matched against, for
+// the given ref of the given remote.
+func RemoteRefName(remote string, ref string) string {
+	return remote + "/" + ref
+}
+
+// RestrictsRefs returns whether any entry applies to the given kind of ref. Requires external synchronization handling,
+// therefore manually manage the RWMutex.
+func (tbl *Access) RestrictsRefs(kind Refs) bool {
+	for _, value := range tbl.Values {
+		if value.Refs.Includes(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchClientRefOperationAsOf is the same as MatchClientOperationAsOf, except that only entries applying to the given
+// kind of ref are matched against the given ref. Requires external synchronization handling, therefore manually manage
+// the RWMutex.
+func (tbl *Access) MatchClientRefOperationAsOf(ref string, refs Refs, user string, host string, op Operations, asOf time.Time) (bool, Permissions) {
+	return tbl.matchRefWithStrategy(ref, refs, "", user, ClientHostForms(host), op, currentMatchMode().strategy(), asOf)
+}
+
+// CanModifyTag returns whether the given context can create or delete the tag with the given name, which requires write
+// permissions from entries that apply to tags and to the tag operation class. As with CheckAccess, contexts without a
+// session are always allowed, and denied modifications are allowed in audit mode.
+func CanModifyTag(ctx context.Context, tagName string) error {
+	return checkRef(ctx, tagName, Refs_Tags, Operations_Tag, Permissions_Write|Permissions_Admin, operationAction(Operations_Tag), ErrCannotModifyTag)
+}
+
+// CanPushRef returns whether the given context can push to the given ref of the given remote, which requires write
+// permissions from entries that apply to remotes. The ref is the name of the branch or tag on the remote.
+func CanPushRef(ctx context.Context, remote string, ref string) error {
+	return checkRef(ctx, RemoteRefName(remote, ref), Refs_Remotes, Operations_All, Permissions_Write|Permissions_Admin, "push", ErrCannotPushRef)
+}
+
+// CanFetchRef returns whether the given context can fetch the given ref of the given remote, which requires any
+// permission from entries that apply to remotes. The ref is the name of the branch on the remote.
+func CanFetchRef(ctx context.Context, remote string, ref string) error {
+	return checkRef(ctx, RemoteRefName(remote, ref), Refs_Remotes, Operations_All, Permissions_Read|Permissions_Write|Permissions_Admin, "fetch", ErrCannotFetchRef)
+}
+
+// checkRef returns an error of the given kind if the context's user has none of the allowed permissions on the given ref
+// of the given kind, while any entry applies to that kind of ref.
+func checkRef(ctx context.Context, ref string, refs Refs, op Operations, allowed Permissions, action string, errKind *errors.Kind) error {
+	if !enabled {
+		return nil
+	}
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow the operation
+	if branchAwareSession == nil {
+		return nil
+	}
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+	if !StaticController.Access.RestrictsRefs(refs) {
+		return nil
+	}
+
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	matchStart := time.Now()
+	_, perms := StaticController.Access.MatchClientRefOperationAsOf(ref, refs, user, host, op, now())
+	recordCheck(action, time.Since(matchStart))
+	if perms&allowed != 0 {
+		return nil
+	}
+	return enforce(ctx, Denial{User: user, Host: host, Branch: ref, Action: action, Err: errKind.New(user, host, ref)})
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefsIncludes(t *testing.T) {
+	assert.True(t, Refs(0).Includes(Refs_Branches))
+	assert.False(t, Refs(0).Includes(Refs_Tags))
+	assert.True(t, (Refs_Tags | Refs_Remotes).Includes(Refs_Remotes))
+	assert.False(t, (Refs_Tags | Refs_Remotes).Includes(Refs_Branches))
+	assert.Equal(t, Refs(0), NewRefs(Refs_Branches))
+	assert.Equal(t, Refs_All, NewRefs(Refs_All))
+	assert.NoError(t, Refs_All.Validate())
+	assert.True(t, ErrInvalidRefs.Is(Refs(8).Validate()))
+}
+
+func TestMatchRefs(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "v%", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All, Refs: Refs_Tags})
+	access.Insert(AccessValue{Branch: "origin/%", User: "carl", Host: "%", Permissions: Permissions_Read, Operations: Operations_All,
+		Refs: Refs_Branches | Refs_Remotes})
+
+	// Entries without refs only apply to branches
+	matched, _ := access.MatchClientRefOperationAsOf("v1", Refs_Tags, "alice", "localhost", Operations_All, wednesdayNoon)
+	assert.False(t, matched)
+	matched, perms := access.MatchClientRefOperationAsOf("v1", Refs_Tags, "bob", "localhost", Operations_All, wednesdayNoon)
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Write, perms)
+	matched, _ = access.Match("v1", "bob", "localhost")
+	assert.False(t, matched)
+	matched, _ = access.MatchClientRefOperationAsOf(RemoteRefName("origin", "main"), Refs_Remotes, "carl", "localhost", Operations_All, wednesdayNoon)
+	assert.True(t, matched)
+	matched, _ = access.Match("origin/main", "carl", "localhost")
+	assert.True(t, matched)
+	matched, perms = access.MatchClientRefOperationAsOf("anything", Refs_Remotes, "root", "localhost", Operations_All, wednesdayNoon)
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Admin, perms)
+
+	assert.True(t, access.RestrictsRefs(Refs_Tags))
+	assert.True(t, access.RestrictsRefs(Refs_Remotes))
+}
+
+func TestCanModifyRefs(t *testing.T) {
+	enableForTest(t)
+	alice := testSessionContext{Context: context.Background(), user: "alice", host: "localhost"}
+	bob := testSessionContext{Context: context.Background(), user: "bob", host: "localhost"}
+
+	// Tags and remotes are unrestricted until an entry applies to them
+	require.NoError(t, CanModifyTag(alice, "v1"))
+	require.NoError(t, CanPushRef(alice, "origin", "main"))
+	require.NoError(t, CanFetchRef(alice, "origin", "main"))
+
+	StaticController.Access.Insert(AccessValue{Branch: "v%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Refs: Refs_Tags})
+	StaticController.Access.Insert(AccessValue{Branch: "origin/%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Refs: Refs_Remotes})
+	StaticController.Access.Insert(AccessValue{Branch: "origin/%", User: "bob", Host: "%", Permissions: Permissions_Read, Operations: Operations_All,
+		Refs: Refs_Remotes})
+
+	require.NoError(t, CanModifyTag(alice, "v1"))
+	assert.True(t, ErrCannotModifyTag.Is(CanModifyTag(alice, "release")))
+	assert.True(t, ErrCannotModifyTag.Is(CanModifyTag(bob, "v1")))
+	require.NoError(t, CanPushRef(alice, "origin", "main"))
+	assert.True(t, ErrCannotPushRef.Is(CanPushRef(alice, "upstream", "main")))
+	assert.True(t, ErrCannotPushRef.Is(CanPushRef(bob, "origin", "main")))
+	require.NoError(t, CanFetchRef(bob, "origin", "main"))
+	assert.True(t, ErrCannotFetchRef.Is(CanFetchRef(bob, "upstream", "main")))
+	// Contexts without a session are always allowed
+	require.NoError(t, CanPushRef(context.Background(), "upstream", "main"))
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// Branch control is local to each server, so read replicas would otherwise enforce whatever rules they were separately
+// given. When dolt_replicate_branch_control is enabled, a server that replicates its databases writes a snapshot of its
+// branch control tables to each replication target after every save, and read replicas that also enable it load the
+// snapshot that they pull from their remote as the base that their own tables are layered over. It is disabled by
+// default, as the snapshot may be read by anyone that can read the remote. Replicated rules therefore replace any base source that the replica was configured
+// with, while the replica's own entries continue to act as a writable overlay.
+
+// ReplicationTarget receives a snapshot of the branch control tables, in the same format as the branch control file.
+type ReplicationTarget func(data []byte) error
+
+// AddReplicationTarget adds a target that is given a snapshot of the context's controller after every save, and once
+// the controller's data has been loaded, so that targets added before loading do not have to wait for the next change.
+func AddReplicationTarget(ctx context.Context, target ReplicationTarget) {
+	//TODO: use the context's controller
+	if !enabled {
+		return
+	}
+
+	StaticController.saveMutex.Lock()
+	defer StaticController.saveMutex.Unlock()
+	StaticController.replicationTargets = append(StaticController.replicationTargets, target)
+}
+
+// replicate gives a snapshot of the controller to every replication target. Replication failures do not fail the save,
+// as the changes have already been written locally, so they're logged instead. Requires the save mutex to be held.
+func (controller *Controller) replicate() {
+	if len(controller.replicationTargets) == 0 {
+		return
+	}
+	data, _ := controller.serializeSnapshot()
+	for _, target := range controller.replicationTargets {
+		if err := target(data); err != nil {
+			logrus.Warnf("failed to replicate branch control: %s", err.Error())
+		}
+	}
+}
+
+// LoadReplicatedBase loads the snapshot of a primary's branch control tables, as written to a ReplicationTarget, as the
+// base of the context's controller, replacing the previous base. The previous base is kept if the snapshot cannot be
+// loaded.
+func LoadReplicatedBase(ctx context.Context, data []byte) error {
+	//TODO: use the context's controller
+	if !enabled {
+		return nil
+	}
+
+	base := CreateController(ctx)
+	if serial.GetFileID(data) != serial.BranchControlFileID {
+		return ErrLoadingReplicatedBase.New(fmt.Sprintf("unknown file ID `%s`", serial.GetFileID(data)))
+	}
+	if err := base.deserialize(data); err != nil {
+		return ErrLoadingReplicatedBase.New(err.Error())
+	}
+
+	StaticController.Access.RWMutex.Lock()
+	defer StaticController.Access.RWMutex.Unlock()
+	StaticController.Namespace.RWMutex.Lock()
+	defer StaticController.Namespace.RWMutex.Unlock()
+	StaticController.Access.base = base.Access
+	StaticController.Namespace.base = base.Namespace
+	return nil
+}
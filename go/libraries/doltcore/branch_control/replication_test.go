@@ -0,0 +1,61 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplication(t *testing.T) {
+	enableForTest(t)
+	ctx := context.Background()
+	var replicated [][]byte
+	AddReplicationTarget(ctx, func(data []byte) error {
+		replicated = append(replicated, data)
+		return nil
+	})
+	// Loading the data gives the targets a snapshot, even when the file does not exist yet
+	path := filepath.Join(t.TempDir(), "branch_control.db")
+	require.NoError(t, LoadData(ctx, path, "", ""))
+	require.Len(t, replicated, 1)
+
+	StaticController.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	StaticController.Namespace.Insert("team%", "alice", "%")
+	require.NoError(t, StaticController.save(false))
+	require.Len(t, replicated, 2)
+
+	// The replica loads the primary's tables as its base, beneath its own entries
+	primary := replicated[1]
+	Reset()
+	StaticController.Access.Insert(AccessValue{Branch: "other", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	require.NoError(t, LoadReplicatedBase(ctx, primary))
+	matched, perms := StaticController.Access.Match("main", "alice", "localhost")
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Write, perms)
+	matched, _ = StaticController.Access.Match("other", "bob", "localhost")
+	assert.True(t, matched)
+	assert.False(t, StaticController.Namespace.CanCreate("team1", "bob", "localhost"))
+	require.Len(t, StaticController.Access.Values, 1)
+
+	// A snapshot that cannot be loaded keeps the previous base
+	assert.True(t, ErrLoadingReplicatedBase.Is(LoadReplicatedBase(ctx, []byte("invalid"))))
+	matched, _ = StaticController.Access.Match("main", "alice", "localhost")
+	assert.True(t, matched)
+}
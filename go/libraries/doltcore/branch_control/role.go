@@ -0,0 +1,201 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// A role groups users together, so that an entry of the Access table may grant permissions to every member of the role
+// rather than to each user individually. An entry references a role by using RolePrefix followed by the role's name as
+// its user expression, such as "@developers". The members of a role are given by user and host expressions, and a user
+// matching any member of a role also matches every entry that references the role, in addition to the entries that
+// match the user directly. The host expression of such an entry still applies, which further restricts where the
+// members may connect from. Roles may not contain other roles, and a user whose name begins with RolePrefix only
+// matches entries through the roles that it is a member of. Members are saved alongside the tables, in the same way as
+// the pending moves.
+
+// RolePrefix is the prefix of a user expression that references a role rather than a user.
+const RolePrefix = "@"
+
+// RoleMember is a member of a role, given by a user and host expression.
+type RoleMember struct {
+	Role string
+	User string
+	Host string
+}
+
+// Roles contains the members of every role.
+type Roles struct {
+	Values []RoleMember
+	Users  []MatchExpression
+	Hosts  []MatchExpression
+	// version is incremented on every modification, so that a save is able to determine whether the roles changed
+	version uint64
+	RWMutex *sync.RWMutex
+}
+
+// newRoles returns a new Roles.
+func newRoles() *Roles {
+	return &Roles{
+		Values:  nil,
+		Users:   nil,
+		Hosts:   nil,
+		RWMutex: &sync.RWMutex{},
+	}
+}
+
+// RoleUser returns the user expression that references the given role.
+func RoleUser(role string) string {
+	return RolePrefix + role
+}
+
+// IsRoleUser returns whether the given user expression references a role.
+func IsRoleUser(user string) bool {
+	return strings.HasPrefix(user, RolePrefix)
+}
+
+// Match returns the name of every role that the given user and host are a member of. Each role is only returned once,
+// even when multiple members of the role match. Requires external synchronization handling, therefore manually manage
+// the RWMutex.
+func (tbl *Roles) Match(user string, host string) []string {
+	if len(tbl.Values) == 0 {
+		return nil
+	}
+	userIndexes := Match(tbl.Users, user, sql.Collation_utf8mb4_0900_bin)
+	filteredHosts := make([]MatchExpression, len(userIndexes))
+	for i, collectionIndex := range userIndexes {
+		filteredHosts[i] = tbl.Hosts[collectionIndex]
+	}
+	indexPool.Put(userIndexes)
+	hostIndexes := Match(filteredHosts, host, sql.Collation_utf8mb4_0900_ai_ci)
+	defer indexPool.Put(hostIndexes)
+
+	var roles []string
+	for _, collectionIndex := range hostIndexes {
+		role := tbl.Values[collectionIndex].Role
+		found := false
+		for _, existing := range roles {
+			if existing == role {
+				found = true
+				break
+			}
+		}
+		if !found {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetIndex returns the index of the given member, or -1 if the member does not exist. Assumes that the expressions have
+// already been folded. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Roles) GetIndex(role string, user string, host string) int {
+	for i, member := range tbl.Values {
+		if member.Role == role && member.User == user && member.Host == host {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert adds the given member. Assumes that the expressions have already been folded, and that the member does not
+// already exist. Requires external synchronization handling.
+func (tbl *Roles) Insert(member RoleMember) {
+	tbl.version++
+	nextIdx := uint32(len(tbl.Values))
+	tbl.Users = append(tbl.Users, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(member.User, sql.Collation_utf8mb4_0900_bin)})
+	tbl.Hosts = append(tbl.Hosts, MatchExpression{CollectionIndex: nextIdx, SortOrders: ParseExpression(member.Host, sql.Collation_utf8mb4_0900_ai_ci)})
+	tbl.Values = append(tbl.Values, member)
+}
+
+// Delete removes the given member, returning whether it existed. Requires external synchronization handling.
+func (tbl *Roles) Delete(role string, user string, host string) bool {
+	idx := tbl.GetIndex(role, user, host)
+	if idx == -1 {
+		return false
+	}
+	tbl.version++
+	tbl.setValues(append(tbl.Values[:idx], tbl.Values[idx+1:]...))
+	return true
+}
+
+// setValues replaces every member with the given members, rebuilding their match expressions. Requires external
+// synchronization handling.
+func (tbl *Roles) setValues(values []RoleMember) {
+	tbl.Values = values
+	tbl.Users = make([]MatchExpression, len(values))
+	tbl.Hosts = make([]MatchExpression, len(values))
+	for i, member := range values {
+		tbl.Users[i] = MatchExpression{CollectionIndex: uint32(i), SortOrders: ParseExpression(member.User, sql.Collation_utf8mb4_0900_bin)}
+		tbl.Hosts[i] = MatchExpression{CollectionIndex: uint32(i), SortOrders: ParseExpression(member.Host, sql.Collation_utf8mb4_0900_ai_ci)}
+	}
+}
+
+// CheckRoleEdit returns an error if the context's user may not modify the members of roles. As a role may be referenced
+// by entries on any branch, the user must be an admin over all branches.
+func CheckRoleEdit(ctx context.Context) error {
+	StaticController.Access.RWMutex.RLock()
+	defer StaticController.Access.RWMutex.RUnlock()
+	return StaticController.checkGlobalAdmin(ctx, ErrRolePermissions)
+}
+
+// serialize returns the offset of the vector of members written to the given builder. Requires external
+// synchronization handling.
+func (tbl *Roles) serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, member := range tbl.Values {
+		role := b.CreateString(member.Role)
+		user := b.CreateString(member.User)
+		host := b.CreateString(member.Host)
+		serial.BranchControlRoleMemberStart(b)
+		serial.BranchControlRoleMemberAddRole(b, role)
+		serial.BranchControlRoleMemberAddUser(b, user)
+		serial.BranchControlRoleMemberAddHost(b, host)
+		offsets[i] = serial.BranchControlRoleMemberEnd(b)
+	}
+	serial.BranchControlStartRolesVector(b, len(offsets))
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
+
+// deserialize replaces the members with those of the given snapshot or journal entry. Requires external
+// synchronization handling.
+func (tbl *Roles) deserialize(bc *serial.BranchControl) error {
+	values := make([]RoleMember, bc.RolesLength())
+	for i := range values {
+		serialMember := &serial.BranchControlRoleMember{}
+		if _, err := bc.TryRoles(serialMember, i); err != nil {
+			return err
+		}
+		values[i] = RoleMember{
+			Role: string(serialMember.Role()),
+			User: string(serialMember.User()),
+			Host: string(serialMember.Host()),
+		}
+	}
+	tbl.version++
+	tbl.setValues(values)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles(t *testing.T) {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	controller.Access.Insert(AccessValue{Branch: "main", User: RoleUser("devs"), Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "release%", User: RoleUser("release_managers"), Host: "localhost", Permissions: Permissions_Admin, Operations: Operations_All})
+	controller.Access.Insert(AccessValue{Branch: "feature%", User: "carl", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	controller.Roles.Insert(RoleMember{Role: "devs", User: "alice", Host: "%"})
+	controller.Roles.Insert(RoleMember{Role: "devs", User: "car_", Host: "%"})
+	controller.Roles.Insert(RoleMember{Role: "release_managers", User: "alice", Host: "localhost"})
+
+	tests := []struct {
+		branch string
+		user   string
+		host   string
+		perms  Permissions
+	}{
+		{"main", "alice", "localhost", Permissions_Write},
+		{"main", "alice", "10.0.0.1", Permissions_Write},
+		{"main", "carl", "localhost", Permissions_Write},
+		{"main", "bob", "localhost", 0},
+		{"release1", "alice", "localhost", Permissions_Admin},
+		// The role's entry is limited to localhost, even though alice's membership of devs is not
+		{"release1", "alice", "10.0.0.1", 0},
+		{"release1", "carl", "localhost", 0},
+		// Direct entries still apply alongside those of the roles
+		{"feature1", "carl", "localhost", Permissions_Write},
+		{"feature1", "alice", "localhost", 0},
+		// Users are never matched against the role names themselves
+		{"main", RoleUser("devs"), "localhost", 0},
+	}
+	for _, test := range tests {
+		_, perms := controller.Access.Match(test.branch, test.user, test.host)
+		assert.Equal(t, test.perms, perms, "%s@%s on %s", test.user, test.host, test.branch)
+	}
+
+	assert.ElementsMatch(t, []string{"devs", "release_managers"}, controller.Roles.Match("alice", "localhost"))
+	result := controller.Access.MatchDetailed("main", "alice", "localhost", Operations_All)
+	require.True(t, result.Matched)
+	assert.Equal(t, []uint32{0}, result.Indexes)
+
+	// Removing a member removes the permissions that the member was granted through the role
+	require.True(t, controller.Roles.Delete("devs", "alice", "%"))
+	require.False(t, controller.Roles.Delete("devs", "alice", "%"))
+	_, perms := controller.Access.Match("main", "alice", "localhost")
+	assert.Equal(t, Permissions(0), perms)
+	_, perms = controller.Access.Match("main", "carl", "localhost")
+	assert.Equal(t, Permissions_Write, perms)
+
+	// The roles follow the statement's copy of the table
+	_, perms = controller.Access.Clone().Match("main", "carl", "localhost")
+	assert.Equal(t, Permissions_Write, perms)
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Entries of the Access table may be restricted to a subset of tables through a table pattern, which is an expression
+// following the same rules as the branch expression. Such an entry only applies when writing to a table that matches
+// the pattern, so that a user may be granted write permissions on a branch while only being able to modify some of its
+// tables. Every other check, such as merges, ref moves, schema changes, and modification of the branch control tables,
+// ignores entries with a table pattern.
+
+// AppliesToTable returns whether the entry applies to the given table. Entries without a table pattern apply to every
+// table, along with checks that are not made for a specific table, which are denoted by an empty table name.
+func (value AccessValue) AppliesToTable(table string) bool {
+	if len(value.Table) == 0 {
+		return true
+	}
+	if len(table) == 0 {
+		return false
+	}
+	pattern := []MatchExpression{{CollectionIndex: 0, SortOrders: ParseExpression(value.Table, sql.Collation_utf8mb4_0900_ai_ci)}}
+	return branchMatchesPattern(pattern, table)
+}
+
+// MatchClientTableOperationAsOf is the same as MatchClientOperationAsOf, except that entries with a table pattern are
+// also considered when the pattern matches the given table. Requires external synchronization handling, therefore
+// manually manage the RWMutex.
+func (tbl *Access) MatchClientTableOperationAsOf(branch string, table string, user string, host string, op Operations, asOf time.Time) (bool, Permissions) {
+	return tbl.matchRefWithStrategy(branch, Refs_Branches, table, user, ClientHostForms(host), op, currentMatchMode().strategy(), asOf)
+}
+
+// CheckTableAccess is the same as CheckAccess, except that the check is made for writing to the given table on the
+// context's selected branch, so that entries whose table pattern matches the table are considered as well.
+func CheckTableAccess(ctx context.Context, table string, flags Permissions, op Operations) error {
+	return checkAccessAsOf(ctx, table, flags, op, now())
+}
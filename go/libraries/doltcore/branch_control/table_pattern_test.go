@@ -0,0 +1,61 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTablePatterns(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Table: "staging_%"})
+	access.Insert(AccessValue{Branch: "main", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+
+	matched, perms := access.MatchClientTableOperationAsOf("main", "STAGING_users", "alice", "localhost", Operations_DirectDML, wednesdayNoon)
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Write, perms)
+	matched, _ = access.MatchClientTableOperationAsOf("main", "users", "alice", "localhost", Operations_DirectDML, wednesdayNoon)
+	assert.False(t, matched)
+	// Checks that aren't made for a table ignore entries with a table pattern
+	matched, _ = access.Match("main", "alice", "localhost")
+	assert.False(t, matched)
+	// Entries without a table pattern apply to every table
+	matched, _ = access.MatchClientTableOperationAsOf("main", "users", "bob", "localhost", Operations_DirectDML, wednesdayNoon)
+	assert.True(t, matched)
+}
+
+func TestTablePatternRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	source.Access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Table: "staging_%"})
+
+	data, err := source.Export(ctx)
+	require.NoError(t, err)
+	target := CreateControllerWithSuperUser(ctx, "root", "localhost")
+	require.NoError(t, target.Import(ctx, data, ImportMode_Replace))
+	require.Len(t, target.Access.Values, 1)
+	assert.Equal(t, "staging_%", target.Access.Values[0].Table)
+
+	// Table patterns are folded on import, just as they are when inserted through the table
+	require.NoError(t, target.Import(ctx, []byte(`{"access":[{"branch":"main","user":"bob","host":"%","permissions":2,"table_pattern":"Staging_%%"}]}`), ImportMode_Merge))
+	require.Len(t, target.Access.Values, 2)
+	assert.Equal(t, "staging_%", target.Access.Values[1].Table)
+}
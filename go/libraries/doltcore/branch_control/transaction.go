@@ -0,0 +1,205 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Statements modify a private copy of a table, which is created by Clone. Once a statement completes, the rows that it
+// wrote to the copy's binlog are replayed onto the shared table by Apply while holding the shared table's write lock, so
+// that concurrent readers never observe a partially applied statement. A statement that fails simply discards its copy.
+//
+// Within an explicit transaction, the changes that were applied are additionally recorded against the session, so that
+// ROLLBACK may undo them through RollbackTransaction, while COMMIT saves them through CommitTransaction.
+
+var (
+	// transactions holds the undo functions of every session's open transaction, keyed by the session's ID. The
+	// functions are in the order that their changes were applied.
+	transactions      = make(map[uint32][]func())
+	transactionsMutex = &sync.Mutex{}
+)
+
+// Clone returns a copy of the table that may be modified without affecting the calling table. The copy begins with an
+// empty binlog, so that its binlog only holds the modifications made to the copy, which may then be applied to the
+// calling table using Apply. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Access) Clone() *Access {
+	return &Access{
+		binlog:    NewAccessBinlog(nil),
+		base:      tbl.base,
+		roles:     tbl.roles,
+		Branches:  append([]MatchExpression(nil), tbl.Branches...),
+		Users:     append([]MatchExpression(nil), tbl.Users...),
+		Hosts:     append([]MatchExpression(nil), tbl.Hosts...),
+		Values:    append([]AccessValue(nil), tbl.Values...),
+		SuperUser: tbl.SuperUser,
+		SuperHost: tbl.SuperHost,
+		RWMutex:   &sync.RWMutex{},
+	}
+}
+
+// Apply replays the modifications that were made to the given clone onto the calling table, which acquires its own
+// write lock. Entries that were concurrently written to the calling table are replaced by those of the clone. When the
+// context is within an explicit transaction, the modifications are recorded so that they may be rolled back.
+func (tbl *Access) Apply(ctx context.Context, clone *Access) {
+	rows, _ := clone.binlog.rowsFrom(0)
+	if len(rows) == 0 {
+		return
+	}
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+
+	undos := make([]func(), len(rows))
+	for i, row := range rows {
+		var previous *AccessValue
+		if tblIndex := tbl.GetIndex(row.Branch, row.User, row.Host); tblIndex != -1 {
+			value := tbl.Values[tblIndex]
+			previous = &value
+			tbl.Delete(row.Branch, row.User, row.Host)
+		}
+		if row.IsInsert {
+			tbl.Insert(row.accessValue())
+		}
+		branch, user, host := row.Branch, row.User, row.Host
+		undos[i] = func() {
+			tbl.RWMutex.Lock()
+			defer tbl.RWMutex.Unlock()
+			tbl.Delete(branch, user, host)
+			if previous != nil {
+				tbl.Insert(*previous)
+			}
+		}
+	}
+	recordTransaction(ctx, undos)
+}
+
+// Clone returns a copy of the table that may be modified without affecting the calling table, following the same
+// rules as the Access table's Clone. The copy continues to reference the calling table's Access table. Requires external
+// synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Namespace) Clone() *Namespace {
+	return &Namespace{
+		access:    tbl.access,
+		binlog:    NewNamespaceBinlog(nil),
+		base:      tbl.base,
+		Branches:  append([]MatchExpression(nil), tbl.Branches...),
+		Users:     append([]MatchExpression(nil), tbl.Users...),
+		Hosts:     append([]MatchExpression(nil), tbl.Hosts...),
+		Values:    append([]NamespaceValue(nil), tbl.Values...),
+		SuperUser: tbl.SuperUser,
+		SuperHost: tbl.SuperHost,
+		RWMutex:   &sync.RWMutex{},
+	}
+}
+
+// Apply replays the modifications that were made to the given clone onto the calling table, following the same rules
+// as the Access table's Apply.
+func (tbl *Namespace) Apply(ctx context.Context, clone *Namespace) {
+	rows, _ := clone.binlog.rowsFrom(0)
+	if len(rows) == 0 {
+		return
+	}
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+
+	undos := make([]func(), len(rows))
+	for i, row := range rows {
+		existed := tbl.GetIndex(row.Branch, row.User, row.Host) != -1
+		tbl.Delete(row.Branch, row.User, row.Host)
+		if row.IsInsert {
+			tbl.Insert(row.Branch, row.User, row.Host)
+		}
+		branch, user, host := row.Branch, row.User, row.Host
+		undos[i] = func() {
+			tbl.RWMutex.Lock()
+			defer tbl.RWMutex.Unlock()
+			tbl.Delete(branch, user, host)
+			if existed {
+				tbl.Insert(branch, user, host)
+			}
+		}
+	}
+	recordTransaction(ctx, undos)
+}
+
+// InTransaction returns whether the context belongs to a session that is within an explicit transaction, either due
+// to START TRANSACTION or due to autocommit being disabled. Changes made within such a transaction are only saved once
+// the transaction commits.
+func InTransaction(ctx context.Context) bool {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok || sqlCtx.Session == nil {
+		return false
+	}
+	if sqlCtx.GetIgnoreAutoCommit() {
+		return true
+	}
+	autocommit, err := sqlCtx.GetSessionVariable(sqlCtx, sql.AutoCommitSessionVar)
+	if err != nil {
+		return false
+	}
+	isAutocommit, err := sql.ConvertToBool(autocommit)
+	return err == nil && !isAutocommit
+}
+
+// CommitTransaction ends the context's transaction, saving the changes that were made within it. Does nothing if the
+// transaction did not modify any tables.
+func CommitTransaction(ctx context.Context) error {
+	if undos := endTransaction(ctx); len(undos) == 0 {
+		return nil
+	}
+	return SaveData(ctx)
+}
+
+// RollbackTransaction ends the context's transaction, undoing the changes that were made within it. As the undone
+// changes may have already been saved by other sessions, the undoing is saved as well. Does nothing if the transaction
+// did not modify any tables.
+func RollbackTransaction(ctx context.Context) error {
+	undos := endTransaction(ctx)
+	if len(undos) == 0 {
+		return nil
+	}
+	for i := len(undos) - 1; i >= 0; i-- {
+		undos[i]()
+	}
+	return SaveData(ctx)
+}
+
+// recordTransaction adds the given undo functions to the context's transaction. Does nothing if the context is not
+// within an explicit transaction.
+func recordTransaction(ctx context.Context, undos []func()) {
+	if !InTransaction(ctx) {
+		return
+	}
+	id := ctx.(*sql.Context).Session.ID()
+	transactionsMutex.Lock()
+	defer transactionsMutex.Unlock()
+	transactions[id] = append(transactions[id], undos...)
+}
+
+// endTransaction removes the context's transaction, returning its undo functions.
+func endTransaction(ctx context.Context) []func() {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok || sqlCtx.Session == nil {
+		return nil
+	}
+	id := sqlCtx.Session.ID()
+	transactionsMutex.Lock()
+	defer transactionsMutex.Unlock()
+	undos := transactions[id]
+	delete(transactions, id)
+	return undos
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyIsAtomicForReaders(t *testing.T) {
+	const statementRows = 2000
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+
+	// Readers must only ever observe the table before or after the statement, and never anything in between
+	var wg sync.WaitGroup
+	var observedPartial, observedComplete int32
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				access.RWMutex.RLock()
+				count := len(access.Values)
+				_, firstPerms := access.Match("branch0", "user", "localhost")
+				_, lastPerms := access.Match(fmt.Sprintf("branch%d", statementRows-1), "user", "localhost")
+				access.RWMutex.RUnlock()
+				if (count != 0 && count != statementRows) || firstPerms != lastPerms {
+					atomic.AddInt32(&observedPartial, 1)
+				}
+				if count == statementRows {
+					atomic.AddInt32(&observedComplete, 1)
+				}
+			}
+		}()
+	}
+
+	access.RWMutex.RLock()
+	clone := access.Clone()
+	access.RWMutex.RUnlock()
+	for i := 0; i < statementRows; i++ {
+		clone.Insert(AccessValue{Branch: fmt.Sprintf("branch%d", i), User: "user", Host: "localhost", Permissions: Permissions_Write, Operations: Operations_All})
+	}
+	access.RWMutex.RLock()
+	assert.Empty(t, access.Values)
+	access.RWMutex.RUnlock()
+	access.Apply(context.Background(), clone)
+
+	// Wait until a reader has seen the applied statement before stopping them
+	for atomic.LoadInt32(&observedComplete) == 0 {
+		runtime.Gosched()
+	}
+	close(stop)
+	wg.Wait()
+	assert.Zero(t, atomic.LoadInt32(&observedPartial))
+	assert.Len(t, access.Values, statementRows)
+	// Every row of the statement was written to the shared binlog, so that it is saved
+	rows, _ := access.binlog.rowsFrom(0)
+	assert.Len(t, rows, statementRows)
+}
+
+func TestApplyReplacesConcurrentWrites(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	clone := access.Clone()
+	clone.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+	// Another statement writes the same entry, and another entry, before the clone is applied
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "main", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+
+	access.Apply(context.Background(), clone)
+	require.Len(t, access.Values, 2)
+	assert.Equal(t, Permissions_Admin, access.Values[access.GetIndex("main", "alice", "%")].Permissions)
+	assert.NotEqual(t, -1, access.GetIndex("main", "bob", "%"))
+}
+
+func TestTransactions(t *testing.T) {
+	controller := CreateControllerWithSuperUser(context.Background(), "root", "localhost")
+	access, namespace := controller.Access, controller.Namespace
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	access.Insert(AccessValue{Branch: "other", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+	namespace.Insert("main", "alice", "%")
+	originalAccess := append([]AccessValue(nil), access.Values...)
+
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	ctx.SetIgnoreAutoCommit(true)
+	require.True(t, InTransaction(ctx))
+	statement := func(modify func(access *Access, namespace *Namespace)) {
+		accessClone, namespaceClone := access.Clone(), namespace.Clone()
+		modify(accessClone, namespaceClone)
+		access.Apply(ctx, accessClone)
+		namespace.Apply(ctx, namespaceClone)
+	}
+	modifications := func() {
+		statement(func(access *Access, namespace *Namespace) {
+			access.Delete("main", "alice", "%")
+			access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All})
+			access.Insert(AccessValue{Branch: "new", User: "carol", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+			namespace.Delete("main", "alice", "%")
+		})
+		statement(func(access *Access, namespace *Namespace) {
+			access.Delete("other", "bob", "%")
+			namespace.Insert("new", "carol", "%")
+		})
+	}
+
+	// Rolling back restores the tables as they were before the transaction
+	modifications()
+	require.Len(t, access.Values, 2)
+	assert.Equal(t, -1, access.GetIndex("other", "bob", "%"))
+	require.NoError(t, RollbackTransaction(ctx))
+	assert.ElementsMatch(t, originalAccess, access.Values)
+	assert.Equal(t, []NamespaceValue{{Branch: "main", User: "alice", Host: "%"}}, namespace.Values)
+
+	// Committing keeps the changes, so a later rollback does not undo them
+	modifications()
+	require.NoError(t, CommitTransaction(ctx))
+	require.NoError(t, RollbackTransaction(ctx))
+	assert.Len(t, access.Values, 2)
+	assert.Equal(t, Permissions_Admin, access.Values[access.GetIndex("main", "alice", "%")].Permissions)
+	assert.Equal(t, []NamespaceValue{{Branch: "new", User: "carol", Host: "%"}}, namespace.Values)
+
+	// Outside of a transaction, changes are not recorded
+	ctx.SetIgnoreAutoCommit(false)
+	require.False(t, InTransaction(ctx))
+	statement(func(access *Access, namespace *Namespace) {
+		access.Delete("new", "carol", "%")
+	})
+	require.NoError(t, RollbackTransaction(ctx))
+	assert.Equal(t, -1, access.GetIndex("new", "carol", "%"))
+}
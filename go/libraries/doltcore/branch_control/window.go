@@ -0,0 +1,136 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sync"
+	"time"
+)
+
+// SecondsPerDay is the number of seconds in a day, which is the exclusive upper bound of a window's time of day.
+const SecondsPerDay = 24 * 60 * 60
+
+// Days are a set of flags that denote the days of the week on which a Window applies. The flags are ordered to match
+// time.Weekday.
+type Days uint8
+
+const (
+	Days_Sunday Days = 1 << iota
+	Days_Monday
+	Days_Tuesday
+	Days_Wednesday
+	Days_Thursday
+	Days_Friday
+	Days_Saturday
+
+	Days_All = Days_Sunday | Days_Monday | Days_Tuesday | Days_Wednesday | Days_Thursday | Days_Friday | Days_Saturday
+)
+
+// Window restricts an entry to a range of the day, on specific days of the week. Windows are always evaluated in UTC,
+// regardless of the server's time zone, so that every server in a cluster agrees on whether an entry applies. The zero
+// value applies at all times, which is also how entries that were written before windows existed are read.
+type Window struct {
+	// Start is the inclusive start of the window, in seconds since midnight.
+	Start uint32
+	// End is the exclusive end of the window, in seconds since midnight. Zero represents the end of the day.
+	End uint32
+	// Days are the days on which the window applies. Zero represents every day.
+	Days Days
+}
+
+// NewWindow returns a Window from the given bounds. An end of SecondsPerDay and a full set of days are stored as their
+// zero values, so that a window covering all times is always equal to the zero value.
+func NewWindow(start uint32, end uint32, days Days) (Window, error) {
+	if end == SecondsPerDay {
+		end = 0
+	}
+	if days == Days_All {
+		days = 0
+	}
+	window := Window{Start: start, End: end, Days: days}
+	if err := window.Validate(); err != nil {
+		return Window{}, err
+	}
+	return window, nil
+}
+
+// EndOrMax returns the end of the window, with the end of the day represented by SecondsPerDay rather than zero.
+func (w Window) EndOrMax() uint32 {
+	if w.End == 0 {
+		return SecondsPerDay
+	}
+	return w.End
+}
+
+// DaysOrAll returns the days of the window, with every day represented by Days_All rather than zero.
+func (w Window) DaysOrAll() Days {
+	if w.Days == 0 {
+		return Days_All
+	}
+	return w.Days
+}
+
+// IsAlways returns whether the window applies at all times.
+func (w Window) IsAlways() bool {
+	return w == Window{}
+}
+
+// Validate returns an error if the window is inverted or empty, or if any of its bounds are out of range. Windows may
+// not wrap around midnight, so such a window must be split into two entries.
+func (w Window) Validate() error {
+	end := w.EndOrMax()
+	if w.Start >= SecondsPerDay || end > SecondsPerDay || w.Start >= end {
+		return ErrInvalidWindow.New(w.Start, end)
+	}
+	if w.Days&^Days_All != 0 {
+		return ErrInvalidWindowDays.New(uint8(w.Days))
+	}
+	return nil
+}
+
+// Contains returns whether the given time falls within the window. The time is converted to UTC before comparison.
+func (w Window) Contains(t time.Time) bool {
+	if w.IsAlways() {
+		return true
+	}
+	t = t.UTC()
+	if w.DaysOrAll()&(1<<uint(t.Weekday())) == 0 {
+		return false
+	}
+	secondOfDay := uint32(t.Hour()*60*60 + t.Minute()*60 + t.Second())
+	return secondOfDay >= w.Start && secondOfDay < w.EndOrMax()
+}
+
+var (
+	timeSource      = time.Now
+	timeSourceMutex = &sync.RWMutex{}
+)
+
+// SetTimeSource replaces the clock that is used to determine whether entries are within their windows, returning the
+// previous clock. This is intended for tests, which may freeze the clock.
+func SetTimeSource(source func() time.Time) func() time.Time {
+	timeSourceMutex.Lock()
+	defer timeSourceMutex.Unlock()
+	previous := timeSource
+	timeSource = source
+	return previous
+}
+
+// now returns the current time according to the time source.
+func now() time.Time {
+	timeSourceMutex.RLock()
+	defer timeSourceMutex.RUnlock()
+	return timeSource()
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// wednesdayNoon is a Wednesday at noon in UTC.
+var wednesdayNoon = time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+func TestWindowContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   Window
+		time     time.Time
+		expected bool
+	}{
+		{"zero value", Window{}, wednesdayNoon, true},
+		{"inside", Window{Start: 9 * 3600, End: 17 * 3600}, wednesdayNoon, true},
+		{"start is inclusive", Window{Start: 12 * 3600, End: 17 * 3600}, wednesdayNoon, true},
+		{"end is exclusive", Window{Start: 9 * 3600, End: 12 * 3600}, wednesdayNoon, false},
+		{"end of day", Window{Start: 12 * 3600}, wednesdayNoon.Add(12*time.Hour - time.Second), true},
+		{"matching day", Window{Days: Days_Wednesday}, wednesdayNoon, true},
+		{"other day", Window{Days: Days_Saturday | Days_Sunday}, wednesdayNoon, false},
+		// 20:00 on Tuesday in New York is midnight on Wednesday in UTC
+		{"converted to UTC", Window{End: 3600, Days: Days_Wednesday}, wednesdayNoon.Add(-12 * time.Hour).In(time.FixedZone("EDT", -4*3600)), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.window.Contains(test.time))
+		})
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+	window, err := NewWindow(0, SecondsPerDay, Days_All)
+	require.NoError(t, err)
+	assert.True(t, window.IsAlways())
+
+	window, err = NewWindow(9*3600, 17*3600, Days_Monday)
+	require.NoError(t, err)
+	assert.Equal(t, Window{Start: 9 * 3600, End: 17 * 3600, Days: Days_Monday}, window)
+
+	_, err = NewWindow(17*3600, 9*3600, Days_All)
+	assert.True(t, ErrInvalidWindow.Is(err))
+	_, err = NewWindow(9*3600, 9*3600, Days_All)
+	assert.True(t, ErrInvalidWindow.Is(err))
+	_, err = NewWindow(0, SecondsPerDay+1, Days_All)
+	assert.True(t, ErrInvalidWindow.Is(err))
+	_, err = NewWindow(0, 0, Days(1<<7))
+	assert.True(t, ErrInvalidWindowDays.Is(err))
+}
+
+func TestMatchWithinWindow(t *testing.T) {
+	defer SetTimeSource(SetTimeSource(func() time.Time {
+		return wednesdayNoon
+	}))
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "%", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Window: Window{Start: 9 * 3600, End: 17 * 3600, Days: Days_All &^ (Days_Saturday | Days_Sunday)}})
+	access.Insert(AccessValue{Branch: "%", User: "alice", Host: "%", Permissions: Permissions_Admin, Operations: Operations_All,
+		Window: Window{Days: Days_Saturday}})
+
+	matched, perms := access.Match("main", "alice", "localhost")
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Write, perms)
+	// Previewing a different time uses the entries whose windows contain that time
+	saturday := wednesdayNoon.AddDate(0, 0, 3)
+	matched, perms = access.MatchOperationAsOf("main", "alice", "localhost", Operations_All, saturday)
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Admin, perms)
+	result := access.MatchDetailedAsOf("main", "alice", "localhost", Operations_All, saturday)
+	assert.Equal(t, MatchResult{Matched: true, Permissions: Permissions_Admin, Indexes: []uint32{1}}, result)
+	result = access.MatchDetailedAsOf("main", "alice", "localhost", Operations_All, wednesdayNoon.Add(6*time.Hour))
+	assert.Equal(t, MatchResult{Matched: false, Permissions: 0, Indexes: nil}, result)
+	// The super user has no window
+	matched, perms = access.MatchOperationAsOf("main", "root", "localhost", Operations_All, saturday)
+	assert.True(t, matched)
+	assert.Equal(t, Permissions_Admin, perms)
+}
+
+func TestWindowSerialization(t *testing.T) {
+	access := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	access.Insert(AccessValue{Branch: "main", User: "alice", Host: "%", Permissions: Permissions_Write, Operations: Operations_All,
+		Window: Window{Start: 9 * 3600, End: 17 * 3600, Days: Days_Monday | Days_Friday}})
+	access.Insert(AccessValue{Branch: "other", User: "bob", Host: "%", Permissions: Permissions_Write, Operations: Operations_All})
+
+	b := flatbuffers.NewBuilder(1024)
+	b.Finish(access.Serialize(b))
+	loaded := CreateControllerWithSuperUser(context.Background(), "root", "localhost").Access
+	require.NoError(t, loaded.Deserialize(serial.GetRootAsBranchControlAccess(b.FinishedBytes(), 0)))
+	assert.Equal(t, access.Values, loaded.Values)
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var ErrBlameKeylessTable = errors.New("unable to blame a table without a primary key")
+var ErrBlameUnsupportedFormat = errors.New("blame is only supported for the __DOLT__ storage format")
+
+// TableBlame holds the commit which last changed each row of a table, as of a commit.
+type TableBlame struct {
+	// Schema is the schema of the table at the blamed commit
+	Schema schema.Schema
+	// Rows are the rows of the table at the blamed commit
+	Rows prolly.Map
+
+	origins map[string]*doltdb.Commit
+}
+
+// Origin returns the commit which last changed the row with the given key, which must be a key of Rows.
+func (tb *TableBlame) Origin(key val.Tuple) *doltdb.Commit {
+	return tb.origins[string(key)]
+}
+
+// BlameTable finds the commit which last changed each row of the named table, as of the commit given. Rather than
+// diffing every row at every commit, it walks backwards along the first parents of the commit, diffing the rows of the
+// table at each commit against its parent and skipping commits that didn't change the rows at all, and stops as soon
+// as every row has been blamed. A row is blamed on the commit that added it, or that last changed any of its values.
+// When the table is missing from a parent, or its primary key changed, every row that has not yet been blamed is
+// blamed on the commit that created the table or changed its key.
+func BlameTable(ctx context.Context, start *doltdb.Commit, tableName string) (*TableBlame, error) {
+	root, err := start.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, name, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, doltdb.ErrTableNotFound
+	}
+	if !types.IsFormat_DOLT(tbl.Format()) {
+		return nil, ErrBlameUnsupportedFormat
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, ErrBlameKeylessTable
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := durable.ProllyMapFromIndex(idx)
+	count, err := rows.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	tb := &TableBlame{Schema: sch, Rows: rows, origins: make(map[string]*doltdb.Commit, count)}
+	keyDesc, _ := rows.Descriptors()
+
+	commit, commitRows := start, rows
+	for len(tb.origins) < count {
+		parentRows, ok, err := blameParentRows(ctx, commit, name, keyDesc)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			err = tb.blameRemaining(ctx, commit)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		if parentRows.HashOf() != commitRows.HashOf() {
+			err = tb.blameDiff(ctx, commit, parentRows, commitRows)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		commit, err = commit.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		commitRows = parentRows
+	}
+
+	return tb, nil
+}
+
+// blameParentRows returns the rows of the named table in the first parent of the commit given, or false if the commit
+// has no parents, the parent doesn't have the table, or the table's primary key differs from |keyDesc|.
+func blameParentRows(ctx context.Context, commit *doltdb.Commit, tableName string, keyDesc val.TupleDesc) (prolly.Map, bool, error) {
+	if commit.NumParents() == 0 {
+		return prolly.Map{}, false, nil
+	}
+	parent, err := commit.GetParent(ctx, 0)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	root, err := parent.GetRootValue(ctx)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	if err != nil || !ok {
+		return prolly.Map{}, false, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	rows := durable.ProllyMapFromIndex(idx)
+	parentKeyDesc, _ := rows.Descriptors()
+	if !parentKeyDesc.Equals(keyDesc) {
+		return prolly.Map{}, false, nil
+	}
+	return rows, true, nil
+}
+
+// blameDiff blames every row that was added or modified between |from| and |to| on |commit|, unless the row was
+// deleted later or has already been blamed on a later commit.
+func (tb *TableBlame) blameDiff(ctx context.Context, commit *doltdb.Commit, from, to prolly.Map) error {
+	err := prolly.DiffMaps(ctx, from, to, func(ctx context.Context, d tree.Diff) error {
+		if d.Type == tree.RemovedDiff {
+			return nil
+		}
+		key := string(d.Key)
+		if _, ok := tb.origins[key]; ok {
+			return nil
+		}
+		ok, err := tb.Rows.Has(ctx, val.Tuple(d.Key))
+		if err != nil {
+			return err
+		}
+		if ok {
+			tb.origins[key] = commit
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// blameRemaining blames every row that has not been blamed yet on |commit|.
+func (tb *TableBlame) blameRemaining(ctx context.Context, commit *doltdb.Commit) error {
+	iter, err := tb.Rows.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		key, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := tb.origins[string(key)]; !ok {
+			tb.origins[string(key)] = commit
+		}
+	}
+}
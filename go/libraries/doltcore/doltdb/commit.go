@@ -64,6 +64,12 @@ func (c *Commit) GetCommitMeta(ctx context.Context) (*datas.CommitMeta, error) {
 	return datas.GetCommitMeta(ctx, c.dCommit.NomsValue())
 }
 
+// GetCommitMetaWithoutDescription gets the metadata associated with the commit, without its description, which is
+// cheaper for callers that do not need the description.
+func (c *Commit) GetCommitMetaWithoutDescription(ctx context.Context) (*datas.CommitMeta, error) {
+	return datas.GetCommitMetaWithoutDescription(ctx, c.dCommit.NomsValue())
+}
+
 // DatasParents returns the []*datas.Commit of the commit parents.
 func (c *Commit) DatasParents() []*datas.Commit {
 	return c.parents
@@ -87,6 +93,12 @@ func (c *Commit) Height() (uint64, error) {
 	return c.dCommit.Height(), nil
 }
 
+// GetAncestorsAtHeight returns the hashes of the ancestors of the commit with the given height, without walking the
+// commit graph. Returns false if the commit's format does not store the heights of its ancestors.
+func (c *Commit) GetAncestorsAtHeight(ctx context.Context, height uint64) ([]hash.Hash, bool, error) {
+	return datas.GetAncestorsAtHeight(ctx, c.dCommit, height, c.vrw, c.ns)
+}
+
 // GetRootValue gets the RootValue of the commit.
 func (c *Commit) GetRootValue(ctx context.Context) (*RootValue, error) {
 	rootV, err := datas.GetCommittedValue(ctx, c.vrw, c.dCommit.NomsValue())
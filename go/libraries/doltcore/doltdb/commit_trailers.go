@@ -0,0 +1,71 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"regexp"
+	"strings"
+)
+
+var trailerKeyRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// CommitTrailer is a "Key: value" line from the trailer block of a commit message, such as
+// "Signed-off-by: Jane Doe <jane@example.com>".
+type CommitTrailer struct {
+	Key   string
+	Value string
+}
+
+// ParseCommitTrailers returns the trailers of a commit message. The trailer block is the last paragraph of the message,
+// and is only recognized when it follows at least one other paragraph and every line in it is a trailer. Lines that
+// begin with whitespace continue the value of the preceding trailer. Keys are returned as written, and should be
+// compared without regard to case.
+func ParseCommitTrailers(message string) []CommitTrailer {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(message, "\r\n", "\n"), " \t\n"), "\n")
+
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "" {
+			start = i + 1
+			break
+		}
+	}
+	if start <= 0 || start >= len(lines) {
+		return nil
+	}
+
+	var trailers []CommitTrailer
+	for _, line := range lines[start:] {
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(trailers) == 0 {
+				return nil
+			}
+			last := &trailers[len(trailers)-1]
+			last.Value = strings.TrimSpace(last.Value + " " + strings.TrimSpace(line))
+			continue
+		}
+
+		sep := strings.IndexByte(line, ':')
+		if sep < 0 {
+			return nil
+		}
+		key := strings.TrimRight(line[:sep], " \t")
+		if !trailerKeyRegex.MatchString(key) {
+			return nil
+		}
+		trailers = append(trailers, CommitTrailer{Key: key, Value: strings.TrimSpace(line[sep+1:])})
+	}
+	return trailers
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected []CommitTrailer
+	}{
+		{
+			name:    "single trailer",
+			message: "add users table\n\nSigned-off-by: Jane Doe <jane@example.com>\n",
+			expected: []CommitTrailer{
+				{Key: "Signed-off-by", Value: "Jane Doe <jane@example.com>"},
+			},
+		},
+		{
+			name:    "several trailers after a body",
+			message: "add users table\n\nThe table replaces accounts.\n\nSigned-off-by: Jane Doe\nReviewed-by:Bob\nTicket : 42",
+			expected: []CommitTrailer{
+				{Key: "Signed-off-by", Value: "Jane Doe"},
+				{Key: "Reviewed-by", Value: "Bob"},
+				{Key: "Ticket", Value: "42"},
+			},
+		},
+		{
+			name:    "continuation lines",
+			message: "subject\r\n\r\nNote: first line\r\n  second line\r\nSigned-off-by: Jane",
+			expected: []CommitTrailer{
+				{Key: "Note", Value: "first line second line"},
+				{Key: "Signed-off-by", Value: "Jane"},
+			},
+		},
+		{
+			name:    "subject only",
+			message: "Signed-off-by: Jane",
+		},
+		{
+			name:    "key in the body",
+			message: "subject\n\nSigned-off-by: Jane\n\nthis paragraph is not a trailer block",
+		},
+		{
+			name:    "last paragraph mixes prose and trailers",
+			message: "subject\n\nfixes the build\nSigned-off-by: Jane",
+		},
+		{
+			name:    "keys may not contain spaces",
+			message: "subject\n\nSigned off by: Jane",
+		},
+		{
+			name:    "empty message",
+			message: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ParseCommitTrailers(test.message))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrEmptyCommittedData = errors.New("cannot commit empty data")
+
+// CommitData replaces the head of the internal ref |dref| with a commit without parents, whose value is a blob of
+// |data| rather than a root value. The data is stored in chunks, so it is pushed, pulled, and kept by garbage
+// collection along with the ref. Returns datas.ErrMergeNeeded when the ref is moved by another writer concurrently.
+func (ddb *DoltDB) CommitData(ctx context.Context, dref ref.DoltRef, data []byte, meta *datas.CommitMeta) (*Commit, error) {
+	ds, err := ddb.db.GetDataset(ctx, dref.String())
+	if err != nil {
+		return nil, err
+	}
+	_, err = commitData(ctx, ddb.db, ddb.vrw, ddb.ns, ds, data, meta)
+	if err != nil {
+		return nil, err
+	}
+	return ddb.ResolveCommitRef(ctx, dref)
+}
+
+// ReadCommittedData returns the data of a commit that was written by CommitData.
+func (ddb *DoltDB) ReadCommittedData(ctx context.Context, cm *Commit) ([]byte, error) {
+	return readCommittedData(ctx, ddb.vrw, ddb.ns, cm.dCommit.NomsValue())
+}
+
+// commitData commits |data| to |ds| through |db|, replacing the dataset's head rather than following it. The previous
+// head is checked when the new head is set, so the commit fails rather than losing a concurrent write.
+func commitData(ctx context.Context, db datas.Database, vrw types.ValueReadWriter, ns tree.NodeStore, ds datas.Dataset, data []byte, meta *datas.CommitMeta) (datas.Dataset, error) {
+	if len(data) == 0 {
+		return datas.Dataset{}, ErrEmptyCommittedData
+	}
+
+	var val types.Value
+	if vrw.Format().UsesFlatbuffers() {
+		blob, err := tree.NewImmutableTreeFromReader(ctx, bytes.NewReader(data), ns, tree.DefaultFixedChunkLength)
+		if err != nil {
+			return datas.Dataset{}, err
+		}
+		val, err = vrw.ReadValue(ctx, blob.Addr)
+		if err != nil {
+			return datas.Dataset{}, err
+		}
+	} else {
+		blob, err := types.NewBlob(ctx, vrw, bytes.NewReader(data))
+		if err != nil {
+			return datas.Dataset{}, err
+		}
+		val = blob
+	}
+
+	opts := datas.CommitOptions{Meta: meta}
+	if head, ok := ds.MaybeHeadAddr(); ok {
+		opts.Amend = true
+		opts.ExpectedHead = head
+	}
+	return db.Commit(ctx, ds, val, opts)
+}
+
+// readCommittedData returns the data of the given commit value, which was written by commitData.
+func readCommittedData(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, commitVal types.Value) ([]byte, error) {
+	val, err := datas.GetCommittedValue(ctx, vrw, commitVal)
+	if err != nil {
+		return nil, err
+	}
+	switch val := val.(type) {
+	case types.SerialMessage:
+		addr, err := val.Hash(vrw.Format())
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewByteArray(addr, ns).ToBytes(ctx)
+	case types.Blob:
+		return io.ReadAll(val.Reader(ctx))
+	default:
+		return nil, errors.New("commit does not hold committed data")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCommitData(t *testing.T) {
+	for _, nbf := range []*types.NomsBinFormat{types.Format_LD_1, types.Format_DOLT} {
+		t.Run(nbf.VersionString(), func(t *testing.T) {
+			ctx := context.Background()
+			ddb, err := LoadDoltDB(ctx, nbf, InMemDoltDB, filesys.LocalFS)
+			require.NoError(t, err)
+			dataRef := ref.NewInternalRef("data")
+			meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "data")
+			require.NoError(t, err)
+
+			first, err := ddb.CommitData(ctx, dataRef, []byte("first"), meta)
+			require.NoError(t, err)
+			data, err := ddb.ReadCommittedData(ctx, first)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("first"), data)
+
+			// Data spanning many chunks replaces the head rather than following it
+			large := bytes.Repeat([]byte("0123456789"), 100_000)
+			second, err := ddb.CommitData(ctx, dataRef, large, meta)
+			require.NoError(t, err)
+			assert.Equal(t, 0, second.NumParents())
+			head, err := ddb.ResolveCommitRef(ctx, dataRef)
+			require.NoError(t, err)
+			data, err = ddb.ReadCommittedData(ctx, head)
+			require.NoError(t, err)
+			assert.Equal(t, large, data)
+
+			// A write based on a head that has since moved fails
+			ds, err := ddb.db.GetDataset(ctx, dataRef.String())
+			require.NoError(t, err)
+			_, err = ddb.CommitData(ctx, dataRef, []byte("third"), meta)
+			require.NoError(t, err)
+			_, err = commitData(ctx, ddb.db, ddb.vrw, ddb.ns, ds, []byte("lost"), meta)
+			assert.Equal(t, datas.ErrMergeNeeded, err)
+
+			_, err = ddb.CommitData(ctx, dataRef, nil, meta)
+			assert.Equal(t, ErrEmptyCommittedData, err)
+		})
+	}
+}
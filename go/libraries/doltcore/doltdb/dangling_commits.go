@@ -0,0 +1,169 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrDanglingCommitsUnsupported is returned when listing the dangling commits of a database whose chunks cannot be
+// enumerated, such as a remote database.
+var ErrDanglingCommitsUnsupported = errors.New("dangling commits cannot be listed for this database")
+
+// DanglingCommit is a commit that cannot be reached from any ref of the database.
+type DanglingCommit struct {
+	Commit *Commit
+	Hash   hash.Hash
+	Meta   *datas.CommitMeta
+}
+
+// DanglingCommits returns the commits of the database that cannot be reached from any ref, or from the merge in
+// progress of any working set, most recent first. Such commits are left behind by resets, forced branch updates, and
+// deleted branches, and are removed by garbage collection once they are no longer in the reflog. Finding them reads
+// every chunk of the database.
+func (ddb *DoltDB) DanglingCommits(ctx context.Context) ([]DanglingCommit, error) {
+	cs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.IterableChunkStore)
+	if !ok {
+		return nil, ErrDanglingCommitsUnsupported
+	}
+
+	reachable, err := ddb.reachableCommits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dangling []DanglingCommit
+	seen := make(hash.HashSet)
+	err = cs.IterateAllAddresses(ctx, func(h hash.Hash) error {
+		if reachable.Has(h) || seen.Has(h) {
+			return nil
+		}
+		seen.Insert(h)
+
+		v, err := ddb.vrw.ReadValue(ctx, h)
+		if err != nil || v == nil {
+			return err
+		}
+		if isCommit, err := datas.IsCommit(v); err != nil || !isCommit {
+			return err
+		}
+		// the replaced heads of committed data, such as the reflog, are not commits of the database's tables
+		committed, err := datas.GetCommittedValue(ctx, ddb.vrw, v)
+		if err != nil {
+			return err
+		}
+		if !isRootValue(ddb.vrw.Format(), committed) {
+			return nil
+		}
+
+		cm, err := ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return err
+		}
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		dangling = append(dangling, DanglingCommit{Commit: cm, Hash: h, Meta: meta})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(dangling, func(i, j int) bool {
+		if dangling[i].Meta.UserTimestamp != dangling[j].Meta.UserTimestamp {
+			return dangling[i].Meta.UserTimestamp > dangling[j].Meta.UserTimestamp
+		}
+		return dangling[i].Hash.Less(dangling[j].Hash)
+	})
+	return dangling, nil
+}
+
+// reachableCommits returns the addresses of every commit reachable from the head of a dataset of the database. The
+// commits of tags, and the commits being merged into working sets, are reachable through their datasets.
+func (ddb *DoltDB) reachableCommits(ctx context.Context) (hash.HashSet, error) {
+	dss, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var heads []hash.Hash
+	err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+		ds, err := ddb.db.GetDataset(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !ds.HasHead():
+		case ds.IsTag():
+			_, commitAddr, err := ds.HeadTag()
+			if err != nil {
+				return err
+			}
+			heads = append(heads, commitAddr)
+		case ds.IsWorkingSet():
+			head, err := ds.HeadWorkingSet()
+			if err != nil {
+				return err
+			}
+			if head.MergeState != nil {
+				fromCommit, err := head.MergeState.FromCommit(ctx, ddb.vrw)
+				if err != nil {
+					return err
+				}
+				heads = append(heads, fromCommit.Addr())
+			}
+		default:
+			heads = append(heads, addr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make(hash.HashSet)
+	for len(heads) > 0 {
+		h := heads[len(heads)-1]
+		heads = heads[:len(heads)-1]
+		if reachable.Has(h) {
+			continue
+		}
+		reachable.Insert(h)
+
+		cm, err := datas.LoadCommitAddr(ctx, ddb.vrw, h)
+		if errors.Is(err, datas.ErrCommitNotFound) {
+			// the ancestors of shallow clones are absent
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		parents, err := datas.GetCommitParents(ctx, ddb.vrw, cm.NomsValue())
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			heads = append(heads, parent.Addr())
+		}
+	}
+	return reachable, nil
+}
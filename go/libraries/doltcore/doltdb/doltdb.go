@@ -76,7 +76,7 @@ func DoltDBFromCS(cs chunks.ChunkStore) *DoltDB {
 	ns := tree.NewNodeStore(cs)
 	db := datas.NewTypesDatabase(vrw, ns)
 
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}
+	return &DoltDB{hooksDatabase{Database: db, reflog: newReflog(db, vrw, ns)}, vrw, ns}
 }
 
 // HackDatasDatabaseFromDoltDB unwraps a DoltDB to a datas.Database.
@@ -93,6 +93,7 @@ func LoadDoltDB(ctx context.Context, nbf *types.NomsBinFormat, urlStr string, fs
 }
 
 func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr string, fs filesys.Filesys, params map[string]interface{}) (*DoltDB, error) {
+	local := urlStr == LocalDirDoltDB || strings.HasPrefix(urlStr, InMemDoltDB)
 	if urlStr == LocalDirDoltDB {
 		exists, isDir := fs.Exists(dbfactory.DoltDataDir)
 
@@ -116,7 +117,14 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 		return nil, err
 	}
 
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}, nil
+	// Moves are only recorded for local databases. Remotes are moved by pushes from other repositories, whose own
+	// reflogs record those moves.
+	var rl *reflog
+	if local {
+		rl = newReflog(db, vrw, ns)
+	}
+
+	return &DoltDB{hooksDatabase{Database: db, reflog: rl}, vrw, ns}, nil
 }
 
 // NomsRoot returns the hash of the noms dataset map
@@ -273,11 +281,19 @@ type Roots struct {
 // Resolve takes a CommitSpec and returns a Commit, or an error if the commit cannot be found.
 // If the CommitSpec is HEAD, Resolve also needs the DoltRef of the current working branch.
 func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef) (*Commit, error) {
+	commit, _, err := ddb.ResolveWithRef(ctx, cs, cwb)
+	return commit, err
+}
+
+// ResolveWithRef is the same as Resolve, except that it also returns the ref that the CommitSpec was resolved through,
+// which is the current working branch for HEAD. The ref is nil for a CommitSpec that gives a commit hash.
+func (ddb *DoltDB) ResolveWithRef(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef) (*Commit, ref.DoltRef, error) {
 	if cs == nil {
 		panic("nil commit spec")
 	}
 
 	var commitVal *datas.Commit
+	var resolvedRef ref.DoltRef
 	var err error
 	switch cs.csType {
 	case hashCommitSpec:
@@ -306,32 +322,39 @@ func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef)
 		for _, candidate := range candidates {
 			commitVal, err = getCommitValForRefStr(ctx, ddb.db, ddb.vrw, candidate)
 			if err == nil {
+				// Refs of types that can't be parsed still resolve, but aren't reported
+				resolvedRef, _ = ref.Parse(candidate)
 				break
 			}
 			if err != ErrBranchNotFound {
-				return nil, err
+				return nil, nil, err
 			} else {
 				err = fmt.Errorf("%w: %s", ErrBranchNotFound, cs.baseSpec)
 			}
 		}
 	case headCommitSpec:
 		if cwb == nil {
-			return nil, fmt.Errorf("cannot use a nil current working branch with a HEAD commit spec")
+			return nil, nil, fmt.Errorf("cannot use a nil current working branch with a HEAD commit spec")
 		}
 		commitVal, err = getCommitValForRefStr(ctx, ddb.db, ddb.vrw, cwb.String())
+		resolvedRef = cwb
 	default:
 		panic("unrecognized commit spec csType: " + cs.csType)
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	commit, err := NewCommit(ctx, ddb.vrw, ddb.ns, commitVal)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return commit.GetAncestor(ctx, cs.aSpec)
+	commit, err = commit.GetAncestor(ctx, cs.aSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commit, resolvedRef, nil
 }
 
 // ResolveCommitRef takes a DoltRef and returns a Commit, or an error if the commit cannot be found. The ref given must
@@ -767,6 +790,50 @@ func (ddb *DoltDB) GetTagsWithHashes(ctx context.Context) ([]TagWithHash, error)
 	return refs, err
 }
 
+// TagMetaWithHash contains the metadata of a Tag along with the hash of the Commit that it references. Unlike
+// TagWithHash, the Commit is not loaded, so CommitFound reports whether it is present in the database.
+type TagMetaWithHash struct {
+	Name        string
+	Meta        *datas.TagMeta
+	Hash        hash.Hash
+	CommitFound bool
+}
+
+// GetTagMetasWithHashes returns the metadata of every tag. Tags that reference commits which are missing from the
+// database, such as those in a shallow clone, are returned rather than causing an error.
+func (ddb *DoltDB) GetTagMetasWithHashes(ctx context.Context) ([]TagMetaWithHash, error) {
+	var tags []TagMetaWithHash
+	err := ddb.VisitRefsOfType(ctx, tagsRefFilter, func(r ref.DoltRef, _ hash.Hash) error {
+		tr, ok := r.(ref.TagRef)
+		if !ok {
+			return nil
+		}
+		ds, err := ddb.db.GetDataset(ctx, tr.String())
+		if err != nil || !ds.HasHead() {
+			return ErrTagNotFound
+		}
+		if !ds.IsTag() {
+			return fmt.Errorf("tagRef head is not a tag")
+		}
+		meta, commitAddr, err := ds.HeadTag()
+		if err != nil {
+			return err
+		}
+		commitVal, err := ddb.vrw.ReadValue(ctx, commitAddr)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, TagMetaWithHash{
+			Name:        tr.GetPath(),
+			Meta:        meta,
+			Hash:        commitAddr,
+			CommitFound: commitVal != nil,
+		})
+		return nil
+	})
+	return tags, err
+}
+
 var workspacesRefFilter = map[ref.RefType]struct{}{ref.WorkspaceRefType: {}}
 
 // GetWorkspaces returns a list of all workspaces in the database.
@@ -873,6 +940,25 @@ func (ddb *DoltDB) GetRefsOfType(ctx context.Context, refTypeFilter map[ref.RefT
 	return refs, err
 }
 
+// RefWithHash is a ref along with the address of its head. For tags, the address is that of the tag rather than of the
+// tagged commit.
+type RefWithHash struct {
+	Ref  ref.DoltRef
+	Hash hash.Hash
+}
+
+// GetRefsWithHashes returns every ref of the given types along with the addresses of their heads. Every ref is read from
+// a single snapshot of the datasets, so the results are consistent with one another even while refs are concurrently
+// created or deleted.
+func (ddb *DoltDB) GetRefsWithHashes(ctx context.Context, refTypeFilter map[ref.RefType]struct{}) ([]RefWithHash, error) {
+	var refs []RefWithHash
+	err := ddb.VisitRefsOfType(ctx, refTypeFilter, func(r ref.DoltRef, addr hash.Hash) error {
+		refs = append(refs, RefWithHash{r, addr})
+		return nil
+	})
+	return refs, err
+}
+
 // NewBranchAtCommit creates a new branch with HEAD at the commit given. Branch names must pass IsValidUserBranchName.
 func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, branchRef ref.DoltRef, commit *Commit) error {
 	if !IsValidBranchRef(branchRef) {
@@ -1161,12 +1247,18 @@ func (ddb *DoltDB) GC(ctx context.Context, uncommitedVals ...hash.Hash) error {
 		return err
 	}
 
+	// The reflog is expired before the datasets are read, as expiring it moves its ref
+	reflogRoots, err := ddb.reflogRoots(ctx)
+	if err != nil {
+		return err
+	}
+
 	datasets, err := ddb.db.Datasets(ctx)
 	if err != nil {
 		return err
 	}
 	newGen := hash.NewHashSet(uncommitedVals...)
-	oldGen := make(hash.HashSet)
+	oldGen := reflogRoots
 	err = datasets.IterAll(ctx, func(keyStr string, h hash.Hash) error {
 		var isOldGen bool
 		switch {
@@ -1196,6 +1288,31 @@ func (ddb *DoltDB) GC(ctx context.Context, uncommitedVals ...hash.Hash) error {
 	return collector.GC(ctx, oldGen, newGen)
 }
 
+// reflogRoots expires the reflog and returns the commits referenced by the entries that are kept, which are kept by
+// garbage collection so that they can be found with the reflog after they are no longer reachable from any ref.
+func (ddb *DoltDB) reflogRoots(ctx context.Context) (hash.HashSet, error) {
+	entries, err := ddb.db.reflog.expire(ctx, datas.CommitNowFunc())
+	if err != nil {
+		return nil, err
+	}
+	roots := make(hash.HashSet)
+	for _, entry := range entries {
+		if !entry.Hash.IsEmpty() {
+			roots.Insert(entry.Hash)
+		}
+	}
+
+	// commits that were already collected can't be kept
+	absent, err := datas.ChunkStoreFromDatabase(ddb.db).HasMany(ctx, roots)
+	if err != nil {
+		return nil, err
+	}
+	for h := range absent {
+		roots.Remove(h)
+	}
+	return roots, nil
+}
+
 func (ddb *DoltDB) ShallowGC(ctx context.Context) error {
 	return datas.PruneTableFiles(ctx, ddb.db)
 }
@@ -37,6 +37,9 @@ type ArtifactIndex interface {
 	ConstraintViolationCount(ctx context.Context) (uint64, error)
 	// ClearConflicts clears all conflicts
 	ClearConflicts(ctx context.Context) (ArtifactIndex, error)
+	// ClearConstraintViolations clears all foreign key violations, unique key
+	// violations, and check constraint violations.
+	ClearConstraintViolations(ctx context.Context) (ArtifactIndex, error)
 }
 
 // RefFromArtifactIndex persists |idx| and returns the types.Ref targeting it.
@@ -147,3 +150,15 @@ func (i prollyArtifactIndex) ClearConflicts(ctx context.Context) (ArtifactIndex,
 	}
 	return prollyArtifactIndex{updated}, nil
 }
+
+func (i prollyArtifactIndex) ClearConstraintViolations(ctx context.Context) (ArtifactIndex, error) {
+	updated := i.index
+	for _, artType := range []prolly.ArtifactType{prolly.ArtifactTypeForeignKeyViol, prolly.ArtifactTypeUniqueKeyViol, prolly.ArtifactTypeChkConsViol} {
+		var err error
+		updated, err = updated.ClearArtifactsOfType(ctx, artType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return prollyArtifactIndex{updated}, nil
+}
@@ -47,37 +47,13 @@ type gcTest struct {
 var gcTests = []gcTest{
 	{
 		name: "gc test",
-		stages: []stage{
-			{
-				preStageFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, i interface{}) interface{} {
-					return nil
-				},
-				commands: []testCommand{
-					{commands.CheckoutCmd{}, []string{"-b", "temp"}},
-					{commands.SqlCmd{}, []string{"-q", "INSERT INTO test VALUES (0),(1),(2);"}},
-					{commands.AddCmd{}, []string{"."}},
-					{commands.CommitCmd{}, []string{"-m", "commit"}},
-				},
+		stages: append(append([]stage{}, deleteTempBranchStages...), stage{
+			// the deleted branch's commit is only referenced by the reflog
+			preStageFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, i interface{}) interface{} {
+				require.NoError(t, ddb.ClearReflog(ctx))
+				return i
 			},
-			{
-				preStageFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, i interface{}) interface{} {
-					cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("temp"))
-					require.NoError(t, err)
-					h, err := cm.HashOf()
-					require.NoError(t, err)
-					cs, err := doltdb.NewCommitSpec(h.String())
-					require.NoError(t, err)
-					_, err = ddb.Resolve(ctx, cs, nil)
-					require.NoError(t, err)
-					return h
-				},
-				commands: []testCommand{
-					{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
-					{commands.BranchCmd{}, []string{"-D", "temp"}},
-					{commands.SqlCmd{}, []string{"-q", "INSERT INTO test VALUES (4),(5),(6);"}},
-				},
-			},
-		},
+		}),
 		query:    "select * from test;",
 		expected: []sql.Row{{int32(4)}, {int32(5)}, {int32(6)}},
 		postGCFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, prevRes interface{}) {
@@ -88,6 +64,52 @@ var gcTests = []gcTest{
 			require.Error(t, err)
 		},
 	},
+	{
+		name:     "gc keeps commits in the reflog",
+		stages:   deleteTempBranchStages,
+		query:    "select * from test;",
+		expected: []sql.Row{{int32(4)}, {int32(5)}, {int32(6)}},
+		postGCFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, prevRes interface{}) {
+			h := prevRes.(hash.Hash)
+			cs, err := doltdb.NewCommitSpec(h.String())
+			require.NoError(t, err)
+			_, err = ddb.Resolve(ctx, cs, nil)
+			require.NoError(t, err)
+		},
+	},
+}
+
+// deleteTempBranchStages commit to a branch and then delete it, returning the hash of its commit
+var deleteTempBranchStages = []stage{
+	{
+		preStageFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, i interface{}) interface{} {
+			return nil
+		},
+		commands: []testCommand{
+			{commands.CheckoutCmd{}, []string{"-b", "temp"}},
+			{commands.SqlCmd{}, []string{"-q", "INSERT INTO test VALUES (0),(1),(2);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "commit"}},
+		},
+	},
+	{
+		preStageFunc: func(ctx context.Context, t *testing.T, ddb *doltdb.DoltDB, i interface{}) interface{} {
+			cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("temp"))
+			require.NoError(t, err)
+			h, err := cm.HashOf()
+			require.NoError(t, err)
+			cs, err := doltdb.NewCommitSpec(h.String())
+			require.NoError(t, err)
+			_, err = ddb.Resolve(ctx, cs, nil)
+			require.NoError(t, err)
+			return h
+		},
+		commands: []testCommand{
+			{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
+			{commands.BranchCmd{}, []string{"-D", "temp"}},
+			{commands.SqlCmd{}, []string{"-q", "INSERT INTO test VALUES (4),(5),(6);"}},
+		},
+	},
 }
 
 var gcSetupCommon = []testCommand{
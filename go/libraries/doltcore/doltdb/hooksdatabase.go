@@ -16,6 +16,7 @@ package doltdb
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/dolthub/dolt/go/store/datas"
@@ -26,6 +27,8 @@ import (
 type hooksDatabase struct {
 	datas.Database
 	postCommitHooks []CommitHook
+	// reflog records the moves of refs, and is independent of the hooks, which are replaced by SetCommitHooks
+	reflog *reflog
 }
 
 // CommitHook is an abstraction for executing arbitrary commands after atomic database commits
@@ -83,6 +86,7 @@ func (db hooksDatabase) CommitWithWorkingSet(
 		prevWsHash,
 		opts)
 	if err == nil {
+		err = db.recordRefMove(ctx, commitDS)
 		db.ExecuteCommitHooks(ctx, commitDS, false)
 	}
 	return commitDS, workingSetDS, err
@@ -91,6 +95,7 @@ func (db hooksDatabase) CommitWithWorkingSet(
 func (db hooksDatabase) Commit(ctx context.Context, ds datas.Dataset, v types.Value, opts datas.CommitOptions) (datas.Dataset, error) {
 	ds, err := db.Database.Commit(ctx, ds, v, opts)
 	if err == nil {
+		err = db.recordRefMove(ctx, ds)
 		db.ExecuteCommitHooks(ctx, ds, false)
 	}
 	return ds, err
@@ -99,6 +104,7 @@ func (db hooksDatabase) Commit(ctx context.Context, ds datas.Dataset, v types.Va
 func (db hooksDatabase) SetHead(ctx context.Context, ds datas.Dataset, newHeadAddr hash.Hash) (datas.Dataset, error) {
 	ds, err := db.Database.SetHead(ctx, ds, newHeadAddr)
 	if err == nil {
+		err = db.recordRefMove(ctx, ds)
 		db.ExecuteCommitHooks(ctx, ds, false)
 	}
 	return ds, err
@@ -107,6 +113,7 @@ func (db hooksDatabase) SetHead(ctx context.Context, ds datas.Dataset, newHeadAd
 func (db hooksDatabase) FastForward(ctx context.Context, ds datas.Dataset, newHeadAddr hash.Hash) (datas.Dataset, error) {
 	ds, err := db.Database.FastForward(ctx, ds, newHeadAddr)
 	if err == nil {
+		err = db.recordRefMove(ctx, ds)
 		db.ExecuteCommitHooks(ctx, ds, false)
 	}
 	return ds, err
@@ -115,11 +122,31 @@ func (db hooksDatabase) FastForward(ctx context.Context, ds datas.Dataset, newHe
 func (db hooksDatabase) Delete(ctx context.Context, ds datas.Dataset) (datas.Dataset, error) {
 	ds, err := db.Database.Delete(ctx, ds)
 	if err == nil {
-		db.ExecuteCommitHooks(ctx, datas.NewHeadlessDataset(ds.Database(), ds.ID()), false)
+		headless := datas.NewHeadlessDataset(ds.Database(), ds.ID())
+		err = db.recordRefMove(ctx, headless)
+		db.ExecuteCommitHooks(ctx, headless, false)
 	}
 	return ds, err
 }
 
+func (db hooksDatabase) Tag(ctx context.Context, ds datas.Dataset, commitAddr hash.Hash, opts datas.TagOptions) (datas.Dataset, error) {
+	ds, err := db.Database.Tag(ctx, ds, commitAddr, opts)
+	if err == nil {
+		err = db.recordRefMove(ctx, ds)
+	}
+	return ds, err
+}
+
+// recordRefMove records the move of the ref of the given dataset in the reflog. The ref has already moved when the move
+// is recorded, so an error means that the move succeeded but is missing from the reflog.
+func (db hooksDatabase) recordRefMove(ctx context.Context, ds datas.Dataset) error {
+	err := db.reflog.record(ctx, ds)
+	if err != nil {
+		return fmt.Errorf("%s was moved, but the move could not be recorded in the reflog: %w", ds.ID(), err)
+	}
+	return nil
+}
+
 func (db hooksDatabase) UpdateWorkingSet(ctx context.Context, ds datas.Dataset, workingSet datas.WorkingSetSpec, prevHash hash.Hash) (datas.Dataset, error) {
 	ds, err := db.Database.UpdateWorkingSet(ctx, ds, workingSet, prevHash)
 	if err == nil {
@@ -0,0 +1,391 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ReflogRef is the internal ref that the reflog of a database is committed to
+var ReflogRef = ref.NewInternalRef("reflog")
+
+const (
+	// ReflogRetention is how long entries are kept in the reflog before garbage collection expires them
+	ReflogRetention = 90 * 24 * time.Hour
+	// ReflogMaxEntries is the most entries that are kept in the reflog
+	ReflogMaxEntries = 10_000
+
+	reflogCommitName  = "Dolt System Account"
+	reflogCommitEmail = "doltuser@dolthub.com"
+)
+
+// reflogRefTypes are the types of refs whose moves are recorded in the reflog. Working sets change with every write,
+// and are not refs to commits, so they are not recorded.
+var reflogRefTypes = map[ref.RefType]struct{}{
+	ref.BranchRefType: {},
+	ref.RemoteRefType: {},
+	ref.TagRefType:    {},
+}
+
+// ReflogEntry is a single move of a ref, recorded when the ref was created, updated, or deleted.
+type ReflogEntry struct {
+	// Ref is the full name of the ref that moved, such as refs/heads/main
+	Ref string
+	// Hash is the commit that the ref moved to, and is empty when the ref was deleted
+	Hash hash.Hash
+	// Timestamp is the time of the move
+	Timestamp time.Time
+}
+
+// reflog records the moves of the refs of a database, so that a commit which is no longer referenced, such as after
+// an accidental reset or a forced branch update, can still be found. Entries are committed to ReflogRef, so they are
+// shared by every process that opens the database, and the commits they reference are kept by garbage collection
+// until the entries expire. Entries are written through the database underneath the hooks, so writing the reflog is
+// not itself a move of a ref. Entries are only recorded for moves made through the DoltDB, so the reflog of a database
+// that was cloned or pulled into starts with the moves made after it was loaded.
+type reflog struct {
+	mu  sync.Mutex
+	db  datas.Database
+	vrw types.ValueReadWriter
+	ns  tree.NodeStore
+
+	// entries are the parsed entries of the commit at |addr|, which are reused until the reflog is written to by
+	// another process
+	addr    hash.Hash
+	entries []ReflogEntry
+}
+
+func newReflog(db datas.Database, vrw types.ValueReadWriter, ns tree.NodeStore) *reflog {
+	return &reflog{db: db, vrw: vrw, ns: ns}
+}
+
+// record records the move of the ref of the given dataset to its current head. Datasets that are not refs of the types
+// in reflogRefTypes are ignored.
+func (rl *reflog) record(ctx context.Context, ds datas.Dataset) error {
+	if rl == nil {
+		return nil
+	}
+	if !ref.IsRef(ds.ID()) {
+		return nil
+	}
+	dref, err := ref.Parse(ds.ID())
+	if errors.Is(err, ref.ErrUnknownRefType) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, ok := reflogRefTypes[dref.GetType()]; !ok {
+		return nil
+	}
+
+	entry := ReflogEntry{Ref: ds.ID(), Timestamp: datas.CommitNowFunc()}
+	if ds.IsTag() {
+		_, entry.Hash, err = ds.HeadTag()
+		if err != nil {
+			return err
+		}
+	} else if addr, ok := ds.MaybeHeadAddr(); ok {
+		entry.Hash = addr
+	}
+
+	return rl.update(ctx, func(entries []ReflogEntry) ([]ReflogEntry, bool) {
+		return append(entries[:len(entries):len(entries)], entry), true
+	})
+}
+
+// read returns every recorded entry, in the order that they were recorded. The returned slice must not be modified.
+func (rl *reflog) read(ctx context.Context) ([]ReflogEntry, error) {
+	if rl == nil {
+		return nil, nil
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	_, entries, err := rl.load(ctx)
+	return entries, err
+}
+
+// update replaces the entries of the reflog with those returned by |edit|, which returns false when the reflog should
+// be left as it is. The entries given to |edit| must not be modified in place. The reflog is trimmed to
+// ReflogMaxEntries, and |edit| is retried if another process writes to the reflog concurrently.
+func (rl *reflog) update(ctx context.Context, edit func([]ReflogEntry) ([]ReflogEntry, bool)) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for {
+		ds, entries, err := rl.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		updated, ok := edit(entries)
+		if !ok {
+			return nil
+		}
+		// Trimming drops more than needed so that the entries that are kept, and the chunks that hold them, are
+		// rewritten once per many moves rather than on every move.
+		if len(updated) > ReflogMaxEntries {
+			updated = updated[len(updated)-ReflogMaxEntries*3/4:]
+		}
+
+		err = rl.write(ctx, ds, updated)
+		if errors.Is(err, datas.ErrMergeNeeded) {
+			continue
+		}
+		return err
+	}
+}
+
+// expire removes the entries that are older than ReflogRetention, or are beyond the newest ReflogMaxEntries, and
+// returns the entries that are kept.
+func (rl *reflog) expire(ctx context.Context, now time.Time) ([]ReflogEntry, error) {
+	if rl == nil {
+		return nil, nil
+	}
+	var kept []ReflogEntry
+	err := rl.update(ctx, func(entries []ReflogEntry) ([]ReflogEntry, bool) {
+		start := 0
+		if len(entries) > ReflogMaxEntries {
+			start = len(entries) - ReflogMaxEntries
+		}
+		for start < len(entries) && now.Sub(entries[start].Timestamp) > ReflogRetention {
+			start++
+		}
+		kept = entries[start:]
+		return kept, start > 0
+	})
+	return kept, err
+}
+
+// clear removes every entry of the reflog.
+func (rl *reflog) clear(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	ds, err := rl.db.GetDataset(ctx, ReflogRef.String())
+	if err != nil {
+		return err
+	}
+	_, err = rl.db.Delete(ctx, ds)
+	if err != nil {
+		return err
+	}
+	rl.addr, rl.entries = hash.Hash{}, nil
+	return nil
+}
+
+// load returns the dataset of the reflog along with its entries, which are only parsed when the reflog has been
+// written since they were last loaded. Callers must hold |mu|.
+func (rl *reflog) load(ctx context.Context) (datas.Dataset, []ReflogEntry, error) {
+	ds, err := rl.db.GetDataset(ctx, ReflogRef.String())
+	if err != nil {
+		return datas.Dataset{}, nil, err
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		return ds, nil, nil
+	}
+	if addr == rl.addr {
+		return ds, rl.entries, nil
+	}
+
+	commitVal, err := rl.vrw.ReadValue(ctx, addr)
+	if err != nil {
+		return datas.Dataset{}, nil, err
+	}
+	data, err := readCommittedData(ctx, rl.vrw, rl.ns, commitVal)
+	if err != nil {
+		return datas.Dataset{}, nil, err
+	}
+	entries, err := parseReflog(data)
+	if err != nil {
+		return datas.Dataset{}, nil, err
+	}
+
+	rl.addr, rl.entries = addr, entries
+	return ds, entries, nil
+}
+
+// write commits |entries| to the reflog, replacing the head of |ds|. Callers must hold |mu|.
+func (rl *reflog) write(ctx context.Context, ds datas.Dataset, entries []ReflogEntry) error {
+	if len(entries) == 0 {
+		_, err := rl.db.Delete(ctx, ds)
+		if err != nil {
+			return err
+		}
+		rl.addr, rl.entries = hash.Hash{}, nil
+		return nil
+	}
+
+	meta, err := datas.NewCommitMeta(reflogCommitName, reflogCommitEmail, "reflog")
+	if err != nil {
+		return err
+	}
+	ds, err = commitData(ctx, rl.db, rl.vrw, rl.ns, ds, formatReflog(entries), meta)
+	if err != nil {
+		return err
+	}
+	rl.addr, _ = ds.MaybeHeadAddr()
+	rl.entries = entries
+	return nil
+}
+
+// formatReflog returns the committed form of |entries|, which is one line per entry.
+func formatReflog(entries []ReflogEntry) []byte {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "%d\t%s\t%s\n", entry.Timestamp.UnixNano(), reflogHashString(entry.Hash), entry.Ref)
+	}
+	return buf.Bytes()
+}
+
+// parseReflog parses entries in the form written by formatReflog.
+func parseReflog(data []byte) ([]ReflogEntry, error) {
+	var entries []ReflogEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		entry, err := parseReflogLine(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// parseReflogLine parses a line of the reflog, which holds the timestamp in nanoseconds, the hash, which is "-" for
+// deletions, and the ref, separated by tabs.
+func parseReflogLine(line string) (ReflogEntry, error) {
+	fields := strings.SplitN(line, "\t", 3)
+	if len(fields) != 3 {
+		return ReflogEntry{}, fmt.Errorf("invalid reflog entry: %q", line)
+	}
+	nanos, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return ReflogEntry{}, fmt.Errorf("invalid reflog entry: %q", line)
+	}
+	entry := ReflogEntry{Ref: fields[2], Timestamp: time.Unix(0, nanos)}
+	if fields[1] != "-" {
+		var ok bool
+		entry.Hash, ok = hash.MaybeParse(fields[1])
+		if !ok {
+			return ReflogEntry{}, fmt.Errorf("invalid reflog entry: %q", line)
+		}
+	}
+	return entry, nil
+}
+
+func reflogHashString(h hash.Hash) string {
+	if h.IsEmpty() {
+		return "-"
+	}
+	return h.String()
+}
+
+// Reflog returns the recorded moves of the ref with the given name, most recent first, or the moves of every ref when
+// the name is empty. The name may be the full name of a ref, such as refs/heads/main, or the name of a branch, tag, or
+// remote-tracking branch, which are tried in that order. Refs that have been deleted still have a reflog, so names are
+// matched against the reflog rather than against the refs that exist now.
+func (ddb *DoltDB) Reflog(ctx context.Context, name string) ([]ReflogEntry, error) {
+	entries, err := ddb.db.reflog.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return reflogMatching(entries, func(string) bool { return true }), nil
+	}
+
+	candidates := []string{name}
+	if !ref.IsRef(name) {
+		candidates = []string{
+			ref.NewBranchRef(name).String(),
+			ref.NewTagRef(name).String(),
+			ref.PrefixForType(ref.RemoteRefType) + name,
+		}
+	}
+	for _, candidate := range candidates {
+		matched := reflogMatching(entries, func(r string) bool { return r == candidate })
+		if len(matched) > 0 {
+			return matched, nil
+		}
+	}
+	return nil, nil
+}
+
+// RecordReflogHead records the current head of |dref| in the reflog, unless it is already the ref's most recent entry.
+// Resets call it before moving a branch's head, so that the commit the branch pointed to can be found in the reflog
+// even when the move that made it the head wasn't recorded, such as in a repository that was cloned.
+func (ddb *DoltDB) RecordReflogHead(ctx context.Context, dref ref.DoltRef) error {
+	if ddb.db.reflog == nil {
+		return nil
+	}
+	ds, err := ddb.db.GetDataset(ctx, dref.String())
+	if err != nil {
+		return err
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		return nil
+	}
+
+	return ddb.db.reflog.update(ctx, func(entries []ReflogEntry) ([]ReflogEntry, bool) {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].Ref == dref.String() {
+				if entries[i].Hash == addr {
+					return nil, false
+				}
+				break
+			}
+		}
+		entry := ReflogEntry{Ref: dref.String(), Hash: addr, Timestamp: datas.CommitNowFunc()}
+		return append(entries[:len(entries):len(entries)], entry), true
+	})
+}
+
+// ClearReflog removes every entry of the reflog. Clones call it, as the reflog of the database they were cloned from
+// records moves that were not made in the clone.
+func (ddb *DoltDB) ClearReflog(ctx context.Context) error {
+	return ddb.db.reflog.clear(ctx)
+}
+
+// reflogMatching returns the entries whose refs match, in reverse order.
+func reflogMatching(entries []ReflogEntry, match func(string) bool) []ReflogEntry {
+	var matched []ReflogEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if match(entries[i].Ref) {
+			matched = append(matched, entries[i])
+		}
+	}
+	return matched
+}
@@ -0,0 +1,262 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func reflogRefs(entries []ReflogEntry) []string {
+	refs := make([]string, len(entries))
+	for i, entry := range entries {
+		refs[i] = entry.Ref
+	}
+	return refs
+}
+
+func TestReflog(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	mainRef := ref.NewBranchRef("main")
+	initial, err := ddb.ResolveCommitRef(ctx, mainRef)
+	require.NoError(t, err)
+	initialHash, err := initial.HashOf()
+	require.NoError(t, err)
+
+	entries, err := ddb.Reflog(ctx, "main")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "refs/heads/main", entries[0].Ref)
+	assert.Equal(t, initialHash, entries[0].Hash)
+
+	rv, err := initial.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, valHash, err := ddb.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "second commit")
+	require.NoError(t, err)
+	second, err := ddb.CommitWithParentCommits(ctx, valHash, mainRef, []*Commit{initial}, meta)
+	require.NoError(t, err)
+	secondHash, err := second.HashOf()
+	require.NoError(t, err)
+
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), second))
+	require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef("v1"), initial, datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "")))
+	require.NoError(t, ddb.SetHeadToCommit(ctx, mainRef, initial))
+	require.NoError(t, ddb.DeleteBranch(ctx, ref.NewBranchRef("other")))
+
+	t.Run("single ref", func(t *testing.T) {
+		entries, err := ddb.Reflog(ctx, "main")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, []hash.Hash{initialHash, secondHash, initialHash}, []hash.Hash{entries[0].Hash, entries[1].Hash, entries[2].Hash})
+		assert.False(t, entries[0].Timestamp.Before(entries[2].Timestamp))
+
+		full, err := ddb.Reflog(ctx, "refs/heads/main")
+		require.NoError(t, err)
+		assert.Equal(t, entries, full)
+	})
+
+	t.Run("deleted ref", func(t *testing.T) {
+		entries, err := ddb.Reflog(ctx, "other")
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.True(t, entries[0].Hash.IsEmpty())
+		assert.Equal(t, secondHash, entries[1].Hash)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		entries, err := ddb.Reflog(ctx, "v1")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "refs/tags/v1", entries[0].Ref)
+		assert.Equal(t, initialHash, entries[0].Hash)
+	})
+
+	t.Run("all refs", func(t *testing.T) {
+		entries, err := ddb.Reflog(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"refs/heads/other",
+			"refs/heads/main",
+			"refs/tags/v1",
+			"refs/heads/other",
+			"refs/heads/main",
+			"refs/heads/main",
+		}, reflogRefs(entries))
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		entries, err := ddb.Reflog(ctx, "missing")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestReflogStorage(t *testing.T) {
+	for _, nbf := range []*types.NomsBinFormat{types.Format_LD_1, types.Format_DOLT} {
+		t.Run(nbf.VersionString(), func(t *testing.T) {
+			ctx := context.Background()
+			ddb, err := LoadDoltDB(ctx, nbf, InMemDoltDB, filesys.LocalFS)
+			require.NoError(t, err)
+			h := hash.Of([]byte("commit"))
+			now := time.Unix(0, 1234567890)
+			appendEntry := func(rl *reflog, entry ReflogEntry) error {
+				return rl.update(ctx, func(entries []ReflogEntry) ([]ReflogEntry, bool) {
+					return append(entries[:len(entries):len(entries)], entry), true
+				})
+			}
+
+			rl := newReflog(ddb.db.Database, ddb.vrw, ddb.ns)
+			entries, err := rl.read(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			require.NoError(t, appendEntry(rl, ReflogEntry{Ref: "refs/heads/main", Hash: h, Timestamp: now}))
+
+			// a second reflog over the same database, as in another process, sees the entries of the first, and the
+			// first sees the entries that the second appends after the first last read
+			other := newReflog(ddb.db.Database, ddb.vrw, ddb.ns)
+			require.NoError(t, appendEntry(other, ReflogEntry{Ref: "refs/heads/main", Timestamp: now}))
+			require.NoError(t, appendEntry(rl, ReflogEntry{Ref: "refs/heads/other", Hash: h, Timestamp: now}))
+			expected := []ReflogEntry{
+				{Ref: "refs/heads/main", Hash: h, Timestamp: now},
+				{Ref: "refs/heads/main", Timestamp: now},
+				{Ref: "refs/heads/other", Hash: h, Timestamp: now},
+			}
+			entries, err = other.read(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, expected, entries)
+
+			// writing the reflog is not a move of a ref, and isn't recorded
+			entries, err = ddb.Reflog(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, entries, 3)
+
+			require.NoError(t, rl.clear(ctx))
+			entries, err = other.read(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestReflogRetention(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	rl := newReflog(ddb.db.Database, ddb.vrw, ddb.ns)
+	now := time.Now()
+
+	t.Run("trimmed when full", func(t *testing.T) {
+		full := make([]ReflogEntry, ReflogMaxEntries+1)
+		for i := range full {
+			full[i] = ReflogEntry{Ref: "refs/heads/main", Hash: hash.Of([]byte{byte(i), byte(i >> 8)}), Timestamp: now}
+		}
+		require.NoError(t, rl.update(ctx, func([]ReflogEntry) ([]ReflogEntry, bool) {
+			return full, true
+		}))
+		entries, err := rl.read(ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, ReflogMaxEntries*3/4)
+		assert.Equal(t, full[len(full)-1], entries[len(entries)-1])
+	})
+
+	t.Run("expired by age", func(t *testing.T) {
+		old := ReflogEntry{Ref: "refs/heads/main", Hash: hash.Of([]byte("old")), Timestamp: now.Add(-ReflogRetention - time.Hour)}
+		recent := ReflogEntry{Ref: "refs/heads/main", Hash: hash.Of([]byte("recent")), Timestamp: now.Add(-time.Hour)}
+		require.NoError(t, rl.update(ctx, func([]ReflogEntry) ([]ReflogEntry, bool) {
+			return []ReflogEntry{old, recent}, true
+		}))
+
+		kept, err := rl.expire(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, []ReflogEntry{recent}, kept)
+		entries, err := rl.read(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []ReflogEntry{recent}, entries)
+
+		kept, err = rl.expire(ctx, now.Add(ReflogRetention))
+		require.NoError(t, err)
+		assert.Empty(t, kept)
+		entries, err = rl.read(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestParseReflog(t *testing.T) {
+	h := hash.Of([]byte("commit"))
+	entries := []ReflogEntry{
+		{Ref: "refs/heads/main", Hash: h, Timestamp: time.Unix(0, 1234567890)},
+		{Ref: "refs/heads/main", Timestamp: time.Unix(0, 1234567891)},
+	}
+	parsed, err := parseReflog(formatReflog(entries))
+	require.NoError(t, err)
+	assert.Equal(t, entries, parsed)
+
+	_, err = parseReflogLine("not an entry")
+	assert.Error(t, err)
+	_, err = parseReflog([]byte("1\tnot a hash\trefs/heads/main\n"))
+	assert.Error(t, err)
+}
+
+func TestRecordReflogHead(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	mainRef := ref.NewBranchRef("main")
+	initial, err := ddb.ResolveCommitRef(ctx, mainRef)
+	require.NoError(t, err)
+	initialHash, err := initial.HashOf()
+	require.NoError(t, err)
+
+	// the head is already the most recent entry
+	require.NoError(t, ddb.RecordReflogHead(ctx, mainRef))
+	entries, err := ddb.Reflog(ctx, "main")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// a reflog that starts after the head moved, as it does for a clone, doesn't have the head until it's recorded
+	require.NoError(t, ddb.ClearReflog(ctx))
+	require.NoError(t, ddb.RecordReflogHead(ctx, mainRef))
+	require.NoError(t, ddb.RecordReflogHead(ctx, mainRef))
+	entries, err = ddb.Reflog(ctx, "main")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, initialHash, entries[0].Hash)
+
+	// refs that don't exist have no head to record
+	require.NoError(t, ddb.RecordReflogHead(ctx, ref.NewBranchRef("missing")))
+	entries, err = ddb.Reflog(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+var stashesRefFilter = map[ref.RefType]struct{}{ref.StashRefType: {}}
+
+// Stash is a set of working set changes that were put aside. The root of the stash's commit is the working root at
+// the time of the stash, and its only parent is the head commit that the changes were made on.
+type Stash struct {
+	Ref    ref.StashRef
+	Commit *Commit
+}
+
+// GetStashes returns every stash in the database, most recent first.
+func (ddb *DoltDB) GetStashes(ctx context.Context) ([]Stash, error) {
+	refs, err := ddb.GetRefsOfType(ctx, stashesRefFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	stashes := make([]Stash, len(refs))
+	for i, r := range refs {
+		cm, err := ddb.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		stashes[i] = Stash{Ref: r.(ref.StashRef), Commit: cm}
+	}
+	sort.Slice(stashes, func(i, j int) bool {
+		return stashes[i].Ref.ID() > stashes[j].Ref.ID()
+	})
+	return stashes, nil
+}
+
+// NewStashAtCommit creates a new stash of the given branch's changes at the commit given. The stash is given an id
+// greater than that of every existing stash, so that it is the most recent.
+func (ddb *DoltDB) NewStashAtCommit(ctx context.Context, branch string, c *Commit) (ref.StashRef, error) {
+	refs, err := ddb.GetRefsOfType(ctx, stashesRefFilter)
+	if err != nil {
+		return ref.StashRef{}, err
+	}
+
+	var id uint64
+	for _, r := range refs {
+		if stashID := r.(ref.StashRef).ID(); stashID >= id {
+			id = stashID + 1
+		}
+	}
+
+	addr, err := c.HashOf()
+	if err != nil {
+		return ref.StashRef{}, err
+	}
+
+	stashRef := ref.NewStashRef(id, branch)
+	return stashRef, ddb.SetHead(ctx, stashRef, addr)
+}
+
+// DeleteStash deletes the stash given, returning an error if it doesn't exist.
+func (ddb *DoltDB) DeleteStash(ctx context.Context, stashRef ref.StashRef) error {
+	err := ddb.deleteRef(ctx, stashRef)
+
+	if err == ErrBranchNotFound {
+		return ErrStashNotFound
+	}
+
+	return err
+}
@@ -123,6 +123,8 @@ var writeableSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	DocTableName,
+	StatisticsTableName,
+	RebasePlanTableName,
 }
 
 var persistedSystemTables = []string{
@@ -130,6 +132,8 @@ var persistedSystemTables = []string{
 	DoltQueryCatalogTableName,
 	SchemasTableName,
 	ProceduresTableName,
+	StatisticsTableName,
+	RebasePlanTableName,
 }
 
 var generatedSystemTables = []string{
@@ -137,6 +141,7 @@ var generatedSystemTables = []string{
 	LogTableName,
 	TableOfTablesInConflictName,
 	TableOfTablesWithViolationsName,
+	SchemaConflictsTableName,
 	CommitsTableName,
 	CommitAncestorsTableName,
 	StatusTableName,
@@ -153,6 +158,7 @@ var generatedSystemTablePrefixes = []string{
 	DoltHistoryTablePrefix,
 	DoltConfTablePrefix,
 	DoltConstViolTablePrefix,
+	DoltWorkspaceTablePrefix,
 }
 
 const (
@@ -235,6 +241,8 @@ const (
 	DoltConfTablePrefix = "dolt_conflicts_"
 	// DoltConstViolTablePrefix is the prefix assigned to all the generated constraint violation tables
 	DoltConstViolTablePrefix = "dolt_constraint_violations_"
+	// DoltWorkspaceTablePrefix is the prefix assigned to all the generated workspace tables
+	DoltWorkspaceTablePrefix = "dolt_workspace_"
 )
 
 const (
@@ -250,12 +258,18 @@ const (
 	// TableOfTablesWithViolationsName is the constraint violations system table name
 	TableOfTablesWithViolationsName = "dolt_constraint_violations"
 
+	// SchemaConflictsTableName is the schema conflicts system table name
+	SchemaConflictsTableName = "dolt_schema_conflicts"
+
 	// BranchesTableName is the branches system table name
 	BranchesTableName = "dolt_branches"
 
 	// RemotesTableName is the remotes system table name
 	RemotesTableName = "dolt_remotes"
 
+	// BackupsTableName is the backups system table name
+	BackupsTableName = "dolt_backups"
+
 	// CommitsTableName is the commits system table name
 	CommitsTableName = "dolt_commits"
 
@@ -270,6 +284,57 @@ const (
 
 	// TagsTableName is the tags table name
 	TagsTableName = "dolt_tags"
+
+	// ReflogTableName is the reflog system table name
+	ReflogTableName = "dolt_reflog"
+
+	// StashesTableName is the stashes system table name
+	StashesTableName = "dolt_stashes"
+
+	// DanglingCommitsTableName is the dangling commits system table name
+	DanglingCommitsTableName = "dolt_dangling_commits"
+)
+
+const (
+	// StatisticsTableName is the name of the table of persisted table and column statistics.
+	StatisticsTableName = "dolt_statistics"
+	// StatisticsTableNameCol is the name of the table that the statistics describe.
+	StatisticsTableNameCol = "table_name"
+	// StatisticsColumnNameCol is the name of the column that the statistics describe.
+	StatisticsColumnNameCol = "column_name"
+	// StatisticsDataHashCol is the hash of the table's row data when the statistics were computed.
+	StatisticsDataHashCol = "data_hash"
+	// StatisticsRowCountCol is the number of rows in the table.
+	StatisticsRowCountCol = "row_count"
+	// StatisticsDistinctCountCol is the number of distinct non-null values in the column.
+	StatisticsDistinctCountCol = "distinct_count"
+	// StatisticsNullCountCol is the number of null values in the column.
+	StatisticsNullCountCol = "null_count"
+	// StatisticsMeanCol is the mean of the values of a numeric column.
+	StatisticsMeanCol = "mean"
+	// StatisticsMinCol is the minimum value of a numeric column.
+	StatisticsMinCol = "min"
+	// StatisticsMaxCol is the maximum value of a numeric column.
+	StatisticsMaxCol = "max"
+	// StatisticsBucketsCol is the histogram of the values of a numeric column, as a JSON array of buckets.
+	StatisticsBucketsCol = "buckets"
+	// StatisticsCreatedAtCol is the time that the statistics were computed, in UTC.
+	StatisticsCreatedAtCol = "created_at"
+)
+
+const (
+	// RebasePlanTableName is the name of the table holding the plan of an interactive rebase, which is edited to
+	// choose what is done with each commit being rebased.
+	RebasePlanTableName = "dolt_rebase_plan"
+	// RebasePlanOrderCol is the position of a step in the rebase plan. Steps are performed in ascending order.
+	RebasePlanOrderCol = "rebase_order"
+	// RebasePlanActionCol is what is done with the commit of a step: pick, reword, squash, or drop.
+	RebasePlanActionCol = "action"
+	// RebasePlanCommitHashCol is the hash of the commit of a step.
+	RebasePlanCommitHashCol = "commit_hash"
+	// RebasePlanCommitMessageCol is the message of the commit of a step, which is used as the new message of
+	// reworded commits.
+	RebasePlanCommitMessageCol = "commit_message"
 )
 
 const (
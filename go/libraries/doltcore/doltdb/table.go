@@ -250,6 +250,27 @@ func (t *Table) clearConflicts(ctx context.Context) (*Table, error) {
 	return &Table{table: table}, nil
 }
 
+// ClearConstraintViolations deletes all constraint violations for this table.
+func (t *Table) ClearConstraintViolations(ctx context.Context) (*Table, error) {
+	if t.Format() == types.Format_DOLT {
+		artIdx, err := t.table.GetArtifacts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		artIdx, err = artIdx.ClearConstraintViolations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return t.SetArtifacts(ctx, artIdx)
+	}
+
+	emptyMap, err := types.NewMap(ctx, t.ValueReadWriter())
+	if err != nil {
+		return nil, err
+	}
+	return t.SetConstraintViolations(ctx, emptyMap)
+}
+
 // GetConflictSchemas returns the merge conflict schemas for this table.
 func (t *Table) GetConflictSchemas(ctx context.Context, tblName string) (base, sch, mergeSch schema.Schema, err error) {
 	if t.Format() == types.Format_DOLT {
@@ -32,6 +32,10 @@ type MergeState struct {
 	// the spec string that was used to specify |commit|
 	commitSpecStr   string
 	preMergeWorking *RootValue
+	// the tables whose schemas could not be merged
+	unmergableTables []string
+	// whether this is a cherry-pick of |commit| rather than a merge
+	isCherryPick bool
 }
 
 // TodoWorkingSetMeta returns an incomplete WorkingSetMeta, suitable for methods that don't have the means to construct
@@ -63,6 +67,29 @@ func (m MergeState) PreMergeWorkingRoot() *RootValue {
 	return m.preMergeWorking
 }
 
+// UnmergableTables returns the tables whose schemas could not be merged. Until their schema conflicts are resolved,
+// these tables are left as they were before the merge.
+func (m MergeState) UnmergableTables() []string {
+	return m.unmergableTables
+}
+
+// IsCherryPick returns whether this is a cherry-pick that stopped on conflicts rather than a merge. The commit of a
+// cherry-pick is not a parent of the commit that completes it.
+func (m MergeState) IsCherryPick() bool {
+	return m.isCherryPick
+}
+
+// HasSchemaConflicts returns whether any table's schema could not be merged.
+func (m MergeState) HasSchemaConflicts() bool {
+	return len(m.unmergableTables) > 0
+}
+
+// WithUnmergableTables returns a copy of this MergeState with the given tables recorded as having schema conflicts.
+func (m MergeState) WithUnmergableTables(tables []string) *MergeState {
+	m.unmergableTables = tables
+	return &m
+}
+
 type WorkingSet struct {
 	Name        string
 	meta        *datas.WorkingSetMeta
@@ -106,6 +133,19 @@ func (ws WorkingSet) StartMerge(commit *Commit, commitSpecStr string) *WorkingSe
 	return &ws
 }
 
+// StartCherryPick returns a copy of this WorkingSet with a merge state for a cherry-pick of |commit|, which records
+// the cherry-pick while its conflicts are resolved, so that it can be aborted.
+func (ws WorkingSet) StartCherryPick(commit *Commit, commitSpecStr string) *WorkingSet {
+	ws.mergeState = &MergeState{
+		commit:          commit,
+		commitSpecStr:   commitSpecStr,
+		preMergeWorking: ws.workingRoot,
+		isCherryPick:    true,
+	}
+
+	return &ws
+}
+
 func (ws WorkingSet) AbortMerge() *WorkingSet {
 	ws.workingRoot = ws.mergeState.PreMergeWorkingRoot()
 	ws.stagedRoot = ws.workingRoot
@@ -191,6 +231,14 @@ func NewWorkingSet(ctx context.Context, name string, vrw types.ValueReadWriter,
 		if err != nil {
 			return nil, err
 		}
+		unmergableTables, err := dsws.MergeState.UnmergableTables(ctx, vrw)
+		if err != nil {
+			return nil, err
+		}
+		isCherryPick, err := dsws.MergeState.IsCherryPick(ctx, vrw)
+		if err != nil {
+			return nil, err
+		}
 
 		commit, err := NewCommit(ctx, vrw, ns, fromDCommit)
 		if err != nil {
@@ -208,9 +256,11 @@ func NewWorkingSet(ctx context.Context, name string, vrw types.ValueReadWriter,
 		}
 
 		mergeState = &MergeState{
-			commit:          commit,
-			commitSpecStr:   commitSpec,
-			preMergeWorking: preMergeWorkingRoot,
+			commit:           commit,
+			commitSpecStr:    commitSpec,
+			preMergeWorking:  preMergeWorkingRoot,
+			unmergableTables: unmergableTables,
+			isCherryPick:     isCherryPick,
 		}
 	}
 
@@ -286,7 +336,7 @@ func (ws *WorkingSet) writeValues(ctx context.Context, db *DoltDB) (
 			return types.Ref{}, types.Ref{}, nil, err
 		}
 
-		mergeState, err = datas.NewMergeState(ctx, db.vrw, preMergeWorking, dCommit, ws.mergeState.commitSpecStr)
+		mergeState, err = datas.NewMergeState(ctx, db.vrw, preMergeWorking, dCommit, ws.mergeState.commitSpecStr, ws.mergeState.unmergableTables, ws.mergeState.isCherryPick)
 		if err != nil {
 			return types.Ref{}, types.Ref{}, nil, err
 		}
@@ -300,7 +300,7 @@ func (m Merge) Exec(t *testing.T, dEnv *env.DoltEnv) error {
 		tmpDir, err := dEnv.TempTableFilesDir()
 		require.NoError(t, err)
 		opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
-		mergedRoot, tblToStats, err := merge.MergeCommits(context.Background(), cm1, cm2, opts)
+		mergedRoot, tblToStats, err := merge.MergeCommits(context.Background(), cm1, cm2, opts, merge.MergeOpts{IsCherryPick: false})
 		require.NoError(t, err)
 		for _, stats := range tblToStats {
 			require.True(t, stats.Conflicts == 0)
@@ -179,6 +179,12 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		return fmt.Errorf("%w; %s", ErrCloneFailed, err.Error())
 	}
 
+	// The reflog of the source records moves that weren't made in the clone
+	err = dEnv.DoltDB.ClearReflog(ctx)
+	if err != nil {
+		return fmt.Errorf("%w; %s", ErrCloneFailed, err.Error())
+	}
+
 	branches, err := dEnv.DoltDB.GetBranches(ctx)
 	if err != nil {
 		return fmt.Errorf("%w; %s", ErrFailedToListBranches, err.Error())
@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/statistics"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -185,6 +186,11 @@ func GetCommitStaged(
 		}
 	}
 
+	roots, err = refreshStatistics(ctx, roots)
+	if err != nil {
+		return nil, err
+	}
+
 	meta, err := datas.NewCommitMetaWithUserTS(props.Name, props.Email, props.Message, props.Date)
 	if err != nil {
 		return nil, err
@@ -192,3 +198,54 @@ func GetCommitStaged(
 
 	return db.NewPendingCommit(ctx, roots, mergeParents, meta)
 }
+
+// refreshStatistics brings the statistics persisted in the staged root up to date with the staged tables, so that
+// every commit carries current statistics for the tables that have been analyzed. The statistics of the working root
+// are updated along with them unless they have unstaged changes of their own.
+func refreshStatistics(ctx context.Context, roots doltdb.Roots) (doltdb.Roots, error) {
+	stagedStats, ok, err := roots.Staged.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil || !ok {
+		return roots, err
+	}
+
+	staged, err := statistics.Refresh(ctx, roots.Staged)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	newStats, _, err := staged.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	oldHash, err := stagedStats.HashOf()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	newHash, err := newStats.HashOf()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	if oldHash == newHash {
+		return roots, nil
+	}
+
+	workingStats, ok, err := roots.Working.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	if ok {
+		workingHash, err := workingStats.HashOf()
+		if err != nil {
+			return doltdb.Roots{}, err
+		}
+		if workingHash == oldHash {
+			roots.Working, err = roots.Working.PutTable(ctx, doltdb.StatisticsTableName, newStats)
+			if err != nil {
+				return doltdb.Roots{}, err
+			}
+		}
+	}
+
+	roots.Staged = staged
+	return roots, nil
+}
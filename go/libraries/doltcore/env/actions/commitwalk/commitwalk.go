@@ -17,6 +17,8 @@ package commitwalk
 import (
 	"container/heap"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -38,6 +40,8 @@ type q struct {
 	pending           []*c
 	numVisiblePending int
 	loaded            map[hash.Hash]*c
+	// stats counts the commits that are read from the database, and is nil when the walk is not counted
+	stats *WalkStats
 }
 
 func (q *q) NumVisiblePending() int {
@@ -110,7 +114,7 @@ func (q *q) SetInvisible(ctx context.Context, ddb *doltdb.DoltDB, id hash.Hash)
 	return nil
 }
 
-func load(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash) (*doltdb.Commit, error) {
+func load(ctx context.Context, ddb *doltdb.DoltDB, stats *WalkStats, h hash.Hash) (*doltdb.Commit, error) {
 	cs, err := doltdb.NewCommitSpec(h.String())
 	if err != nil {
 		return nil, err
@@ -119,6 +123,7 @@ func load(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash) (*doltdb.Commit,
 	if err != nil {
 		return nil, err
 	}
+	stats.read()
 	return c, nil
 }
 
@@ -127,7 +132,7 @@ func (q *q) Get(ctx context.Context, ddb *doltdb.DoltDB, id hash.Hash) (*c, erro
 		return l, nil
 	}
 
-	l, err := load(ctx, ddb, id)
+	l, err := load(ctx, ddb, q.stats, id)
 	if err != nil {
 		return nil, err
 	}
@@ -145,8 +150,46 @@ func (q *q) Get(ctx context.Context, ddb *doltdb.DoltDB, id hash.Hash) (*c, erro
 	return c, nil
 }
 
-func newQueue() *q {
-	return &q{loaded: make(map[hash.Hash]*c)}
+func newQueue(stats *WalkStats) *q {
+	return &q{loaded: make(map[hash.Hash]*c), stats: stats}
+}
+
+// MissingAncestorError is returned by the iterators when an ancestor cannot be loaded because its chunks are not
+// present in the database, such as when the history was truncated by a shallow or partial clone. The walk ends at the
+// missing ancestor, so callers may treat this error as the end of the iteration rather than as a failure.
+type MissingAncestorError struct {
+	// Hash is the commit whose parent is missing, which is the zero hash when the iterator did not reach its commit
+	Hash hash.Hash
+	// Commit is the boundary commit at Hash. It is nil when the iterator would not have returned the commit, in which
+	// case every commit that the iterator would have returned has already been returned.
+	Commit *doltdb.Commit
+	// Missing is the hash of the ancestor that could not be loaded
+	Missing hash.Hash
+}
+
+var _ error = (*MissingAncestorError)(nil)
+
+func (e *MissingAncestorError) Error() string {
+	return fmt.Sprintf("ancestor commit %s is missing from the database", e.Missing.String())
+}
+
+// Unwrap returns datas.ErrCommitNotFound, so that the error may be matched with errors.Is.
+func (e *MissingAncestorError) Unwrap() error {
+	return datas.ErrCommitNotFound
+}
+
+// missingAncestor converts an error from loading the parent of the given commit into a MissingAncestorError when the
+// parent is missing. Other errors are returned unchanged. The commit is only included when the iterator would have
+// returned it.
+func missingAncestor(err error, child *c, returned bool, parentID hash.Hash) error {
+	if !errors.Is(err, datas.ErrCommitNotFound) {
+		return err
+	}
+	missingErr := &MissingAncestorError{Hash: child.hash, Missing: parentID}
+	if returned {
+		missingErr.Commit = child.commit
+	}
+	return missingErr
 }
 
 // GetDotDotRevisions returns the commits reachable from commit at hash
@@ -160,7 +203,7 @@ func newQueue() *q {
 // Roughly mimics `git log main..feature`.
 func GetDotDotRevisions(ctx context.Context, includedDB *doltdb.DoltDB, includedHead hash.Hash, excludedDB *doltdb.DoltDB, excludedHead hash.Hash, num int) ([]*doltdb.Commit, error) {
 	var commitList []*doltdb.Commit
-	q := newQueue()
+	q := newQueue(walkStatsFromContext(ctx))
 	if err := q.SetInvisible(ctx, excludedDB, excludedHead); err != nil {
 		return nil, err
 	}
@@ -206,23 +249,37 @@ func GetTopologicalOrderCommits(ctx context.Context, ddb *doltdb.DoltDB, startCo
 // GetTopologicalOrderCommitIterator returns an iterator for commits generated with the same semantics as
 // GetTopologicalOrderCommits
 func GetTopologicalOrderIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
-	return newCommiterator(ctx, ddb, startCommitHash, matchFn)
+	return newCommiterator(ctx, ddb, startCommitHash, matchFn, false)
+}
+
+// GetFirstParentIterator returns an iterator over the commits reached from the commit at hash `startCommitHash` by
+// following only the first parent of each commit, which is the history of the branch that merges were made into.
+//
+// Roughly mimics `git log --first-parent`.
+func GetFirstParentIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newCommiterator(ctx, ddb, startCommitHash, matchFn, true)
 }
 
 type commiterator struct {
 	ddb             *doltdb.DoltDB
 	startCommitHash hash.Hash
 	matchFn         func(*doltdb.Commit) (bool, error)
-	q               *q
+	// firstParent restricts the walk to the first parent of each commit
+	firstParent bool
+	q           *q
+	stats       *WalkStats
 }
 
 var _ doltdb.CommitItr = (*commiterator)(nil)
+var _ statsIterator = (*commiterator)(nil)
 
-func newCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (*commiterator, error) {
+func newCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error), firstParent bool) (*commiterator, error) {
 	itr := &commiterator{
 		ddb:             ddb,
 		startCommitHash: startCommitHash,
 		matchFn:         matchFn,
+		firstParent:     firstParent,
+		stats:           walkStatsFromContext(ctx),
 	}
 
 	err := itr.Reset(ctx)
@@ -237,23 +294,28 @@ func newCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash ha
 func (i *commiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
 	if i.q.NumVisiblePending() > 0 {
 		nextC := i.q.PopPending()
+		matches := true
+		if i.matchFn != nil {
+			var err error
+			matches, err = i.matchFn(nextC.commit)
+
+			if err != nil {
+				return hash.Hash{}, nil, err
+			}
+		}
+		i.stats.visit(matches)
+
 		parents, err := nextC.commit.ParentHashes(ctx)
 		if err != nil {
 			return hash.Hash{}, nil, err
 		}
+		if i.firstParent && len(parents) > 1 {
+			parents = parents[:1]
+		}
 
 		for _, parentID := range parents {
 			if err := i.q.AddPendingIfUnseen(ctx, nextC.ddb, parentID); err != nil {
-				return hash.Hash{}, nil, err
-			}
-		}
-
-		matches := true
-		if i.matchFn != nil {
-			matches, err = i.matchFn(nextC.commit)
-
-			if err != nil {
-				return hash.Hash{}, nil, err
+				return hash.Hash{}, nil, missingAncestor(err, nextC, matches, parentID)
 			}
 		}
 
@@ -267,9 +329,13 @@ func (i *commiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, err
 	return hash.Hash{}, nil, io.EOF
 }
 
+func (i *commiterator) walkStats() *WalkStats {
+	return i.stats
+}
+
 // Reset implements doltdb.CommitItr
 func (i *commiterator) Reset(ctx context.Context) error {
-	i.q = newQueue()
+	i.q = newQueue(i.stats)
 	if err := i.q.AddPendingIfUnseen(ctx, i.ddb, i.startCommitHash); err != nil {
 		return err
 	}
@@ -303,25 +369,77 @@ func GetTopNTopoOrderedCommitsMatching(ctx context.Context, ddb *doltdb.DoltDB,
 // GetDotDotRevisionsIterator returns an iterator for commits generated with the same semantics as
 // GetDotDotRevisions
 func GetDotDotRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, excludingCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
-	return newDotDotCommiterator(ctx, ddb, startCommitHash, excludingCommitHash, matchFn)
+	return newDotDotCommiterator(ctx, ddb, []hash.Hash{startCommitHash}, []hash.Hash{excludingCommitHash}, matchFn, false)
+}
+
+// GetFirstParentDotDotRevisionsIterator returns an iterator for commits generated with the same semantics as
+// GetDotDotRevisions, except that only the first parent of each included commit is followed. Every parent of an
+// excluded commit is still excluded, so that no commit reachable from `excludingCommitHash` is returned.
+func GetFirstParentDotDotRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, excludingCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newDotDotCommiterator(ctx, ddb, []hash.Hash{startCommitHash}, []hash.Hash{excludingCommitHash}, matchFn, true)
+}
+
+// GetMultiRevisionsIterator returns an iterator for the commits that are reachable from any of `startCommitHashes`, but
+// not from any of `excludingCommitHashes`, in the same order as GetDotDotRevisions. The walks from every start commit
+// share a single frontier, so a commit reachable from several of them is only returned once.
+//
+// Roughly mimics `git log a b ^c ^d`.
+func GetMultiRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHashes, excludingCommitHashes []hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newDotDotCommiterator(ctx, ddb, startCommitHashes, excludingCommitHashes, matchFn, false)
+}
+
+// GetFirstParentMultiRevisionsIterator returns an iterator for commits generated with the same semantics as
+// GetMultiRevisionsIterator, except that only the first parent of each included commit is followed.
+func GetFirstParentMultiRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHashes, excludingCommitHashes []hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newDotDotCommiterator(ctx, ddb, startCommitHashes, excludingCommitHashes, matchFn, true)
+}
+
+// GetThreeDotRevisionsIterator returns an iterator for the commits that are reachable from either `leftCommitHash` or
+// `rightCommitHash`, but not from `mergeBaseHash`, in the same order as GetDotDotRevisions. `mergeBaseHash` is the
+// merge base of the two commits, and may be empty when their histories are unrelated, in which case every commit
+// reachable from either is returned.
+//
+// Roughly mimics `git log left...right`.
+func GetThreeDotRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, leftCommitHash, rightCommitHash, mergeBaseHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newThreeDotCommiterator(ctx, ddb, leftCommitHash, rightCommitHash, mergeBaseHash, matchFn, false)
+}
+
+// GetFirstParentThreeDotRevisionsIterator returns an iterator for commits generated with the same semantics as
+// GetThreeDotRevisionsIterator, except that only the first parent of each included commit is followed.
+func GetFirstParentThreeDotRevisionsIterator(ctx context.Context, ddb *doltdb.DoltDB, leftCommitHash, rightCommitHash, mergeBaseHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	return newThreeDotCommiterator(ctx, ddb, leftCommitHash, rightCommitHash, mergeBaseHash, matchFn, true)
+}
+
+func newThreeDotCommiterator(ctx context.Context, ddb *doltdb.DoltDB, leftCommitHash, rightCommitHash, mergeBaseHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error), firstParent bool) (*dotDotCommiterator, error) {
+	var excludingCommitHashes []hash.Hash
+	if !mergeBaseHash.IsEmpty() {
+		excludingCommitHashes = append(excludingCommitHashes, mergeBaseHash)
+	}
+	return newDotDotCommiterator(ctx, ddb, []hash.Hash{leftCommitHash, rightCommitHash}, excludingCommitHashes, matchFn, firstParent)
 }
 
 type dotDotCommiterator struct {
-	ddb                 *doltdb.DoltDB
-	startCommitHash     hash.Hash
-	excludingCommitHash hash.Hash
-	matchFn             func(*doltdb.Commit) (bool, error)
-	q                   *q
+	ddb                   *doltdb.DoltDB
+	startCommitHashes     []hash.Hash
+	excludingCommitHashes []hash.Hash
+	matchFn               func(*doltdb.Commit) (bool, error)
+	// firstParent restricts the walk of included commits to their first parents
+	firstParent bool
+	q           *q
+	stats       *WalkStats
 }
 
 var _ doltdb.CommitItr = (*dotDotCommiterator)(nil)
+var _ statsIterator = (*dotDotCommiterator)(nil)
 
-func newDotDotCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, excludingCommitHash hash.Hash, matchFn func(*doltdb.Commit) (bool, error)) (*dotDotCommiterator, error) {
+func newDotDotCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHashes, excludingCommitHashes []hash.Hash, matchFn func(*doltdb.Commit) (bool, error), firstParent bool) (*dotDotCommiterator, error) {
 	itr := &dotDotCommiterator{
-		ddb:                 ddb,
-		startCommitHash:     startCommitHash,
-		excludingCommitHash: excludingCommitHash,
-		matchFn:             matchFn,
+		ddb:                   ddb,
+		startCommitHashes:     startCommitHashes,
+		excludingCommitHashes: excludingCommitHashes,
+		matchFn:               matchFn,
+		firstParent:           firstParent,
+		stats:                 walkStatsFromContext(ctx),
 	}
 
 	err := itr.Reset(ctx)
@@ -336,27 +454,34 @@ func newDotDotCommiterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitH
 func (i *dotDotCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
 	if i.q.NumVisiblePending() > 0 {
 		nextC := i.q.PopPending()
+		matches := true
+		if i.matchFn != nil {
+			var err error
+			matches, err = i.matchFn(nextC.commit)
+			if err != nil {
+				return hash.Hash{}, nil, err
+			}
+		}
+		// Excluded commits are not returned, but are not counted as skipped by the match function
+		i.stats.visit(nextC.invisible || matches)
+
 		parents, err := nextC.commit.ParentHashes(ctx)
 		if err != nil {
 			return hash.Hash{}, nil, err
 		}
+		// Excluded commits exclude all of their ancestors, so only the walk of included commits is restricted
+		if i.firstParent && !nextC.invisible && len(parents) > 1 {
+			parents = parents[:1]
+		}
 
 		for _, parentID := range parents {
 			if nextC.invisible {
 				if err := i.q.SetInvisible(ctx, nextC.ddb, parentID); err != nil {
-					return hash.Hash{}, nil, err
+					return hash.Hash{}, nil, missingAncestor(err, nextC, false, parentID)
 				}
 			}
 			if err := i.q.AddPendingIfUnseen(ctx, nextC.ddb, parentID); err != nil {
-				return hash.Hash{}, nil, err
-			}
-		}
-
-		matches := true
-		if i.matchFn != nil {
-			matches, err = i.matchFn(nextC.commit)
-			if err != nil {
-				return hash.Hash{}, nil, err
+				return hash.Hash{}, nil, missingAncestor(err, nextC, !nextC.invisible && matches, parentID)
 			}
 		}
 
@@ -370,17 +495,25 @@ func (i *dotDotCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commi
 	return hash.Hash{}, nil, io.EOF
 }
 
+func (i *dotDotCommiterator) walkStats() *WalkStats {
+	return i.stats
+}
+
 // Reset implements doltdb.CommitItr
 func (i *dotDotCommiterator) Reset(ctx context.Context) error {
-	i.q = newQueue()
-	if err := i.q.SetInvisible(ctx, i.ddb, i.excludingCommitHash); err != nil {
-		return err
-	}
-	if err := i.q.AddPendingIfUnseen(ctx, i.ddb, i.excludingCommitHash); err != nil {
-		return err
+	i.q = newQueue(i.stats)
+	for _, excludingCommitHash := range i.excludingCommitHashes {
+		if err := i.q.SetInvisible(ctx, i.ddb, excludingCommitHash); err != nil {
+			return err
+		}
+		if err := i.q.AddPendingIfUnseen(ctx, i.ddb, excludingCommitHash); err != nil {
+			return err
+		}
 	}
-	if err := i.q.AddPendingIfUnseen(ctx, i.ddb, i.startCommitHash); err != nil {
-		return err
+	for _, startCommitHash := range i.startCommitHashes {
+		if err := i.q.AddPendingIfUnseen(ctx, i.ddb, startCommitHash); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -16,6 +16,9 @@ package commitwalk
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -26,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/datas/pull"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -211,12 +215,864 @@ func TestGetDotDotRevisions(t *testing.T) {
 	assertEqualHashes(t, featureCommits[1], res[2])
 }
 
+func TestGetReverseIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	commit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this:
+	//
+	//          feature:  *-----*--*
+	//                   /     /      \
+	// main: --*--*--*--*--*--*--*--*--*
+	mainHead := commit
+	for i := 0; i < 3; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), mainHead))
+	featureHead := mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, mainHead)
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	featureHead = mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, featureHead, mainHead)
+	featureHead = mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, featureHead)
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead, featureHead)
+
+	// A small buffer forces the hashes to be spilled across several blocks
+	defer func(size int) { reverseBufferSize = size }(reverseBufferSize)
+	reverseBufferSize = 3
+
+	tests := []struct {
+		name  string
+		child func() (doltdb.CommitItr, error)
+	}{
+		{
+			name: "topological",
+			child: func() (doltdb.CommitItr, error) {
+				return GetTopologicalOrderIterator(ctx, dEnv.DoltDB, mustGetHash(t, mainHead), nil)
+			},
+		},
+		{
+			name: "dot dot",
+			child: func() (doltdb.CommitItr, error) {
+				return GetDotDotRevisionsIterator(ctx, dEnv.DoltDB, mustGetHash(t, mainHead), mustGetHash(t, featureHead), nil)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forward, err := test.child()
+			require.NoError(t, err)
+			var forwardHashes []hash.Hash
+			for {
+				h, _, err := forward.Next(ctx)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				forwardHashes = append(forwardHashes, h)
+			}
+
+			child, err := test.child()
+			require.NoError(t, err)
+			itr := GetReverseIterator(dEnv.DoltDB, child)
+			for pass := 0; pass < 2; pass++ {
+				seen := make(map[hash.Hash]bool)
+				var reverseHashes []hash.Hash
+				for {
+					h, cm, err := itr.Next(ctx)
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					assert.Equal(t, h, mustGetHash(t, cm))
+					// Every parent that is part of the output must have already been emitted
+					parents, err := cm.ParentHashes(ctx)
+					require.NoError(t, err)
+					for _, parent := range parents {
+						if containsHash(forwardHashes, parent) {
+							assert.True(t, seen[parent], "commit %s emitted before its parent %s", h, parent)
+						}
+					}
+					seen[h] = true
+					reverseHashes = append(reverseHashes, h)
+				}
+				require.Len(t, reverseHashes, len(forwardHashes))
+				for i := range forwardHashes {
+					assert.Equal(t, forwardHashes[len(forwardHashes)-1-i], reverseHashes[i])
+				}
+				require.NoError(t, itr.Reset(ctx))
+			}
+			require.NoError(t, itr.(io.Closer).Close())
+		})
+	}
+}
+
+func TestGetThreeDotRevisionsIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	commit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this:
+	//
+	//          feature:  *--*
+	//                   /
+	// main: --*--*--*--*--*--*--*
+	base := commit
+	for i := 0; i < 3; i++ {
+		base = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, base)
+	}
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), base))
+	featureCommits := []*doltdb.Commit{mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, base)}
+	featureCommits = append(featureCommits, mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, featureCommits[0]))
+	mainCommits := []*doltdb.Commit{mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, base)}
+	for i := 1; i < 3; i++ {
+		mainCommits = append(mainCommits, mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainCommits[i-1]))
+	}
+
+	collect := func(itr doltdb.CommitItr) []hash.Hash {
+		var hashes []hash.Hash
+		for {
+			h, _, err := itr.Next(ctx)
+			if err == io.EOF {
+				return hashes
+			}
+			require.NoError(t, err)
+			hashes = append(hashes, h)
+		}
+	}
+
+	featureHash, mainHash, baseHash := mustGetHash(t, featureCommits[1]), mustGetHash(t, mainCommits[2]), mustGetHash(t, base)
+	itr, err := GetThreeDotRevisionsIterator(ctx, dEnv.DoltDB, featureHash, mainHash, baseHash, nil)
+	require.NoError(t, err)
+	// Commits on both sides are ordered by height, with ties broken by timestamp
+	expected := []hash.Hash{
+		mustGetHash(t, mainCommits[2]),
+		mustGetHash(t, mainCommits[1]),
+		mustGetHash(t, featureCommits[1]),
+		mustGetHash(t, mainCommits[0]),
+		mustGetHash(t, featureCommits[0]),
+	}
+	assert.Equal(t, expected, collect(itr))
+	require.NoError(t, itr.Reset(ctx))
+	hashes := collect(itr)
+	assert.Equal(t, expected, hashes)
+
+	// The order of the revisions does not change the result
+	itr, err = GetThreeDotRevisionsIterator(ctx, dEnv.DoltDB, mainHash, featureHash, baseHash, nil)
+	require.NoError(t, err)
+	assert.Equal(t, hashes, collect(itr))
+
+	// When one revision is an ancestor of the other, it is also the merge base, so only the other side is returned
+	itr, err = GetThreeDotRevisionsIterator(ctx, dEnv.DoltDB, baseHash, featureHash, baseHash, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{mustGetHash(t, featureCommits[1]), mustGetHash(t, featureCommits[0])}, collect(itr))
+
+	// Without a merge base, every commit reachable from either side is returned
+	itr, err = GetThreeDotRevisionsIterator(ctx, dEnv.DoltDB, featureHash, mainHash, hash.Hash{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, collect(itr), 9)
+}
+
+func TestGetMultiRevisionsIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	commit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this:
+	//
+	//             left:  *--*
+	//                   /
+	// main: --*--*--*--*--*
+	//                   \
+	//            right:  *--*
+	base := commit
+	for i := 0; i < 3; i++ {
+		base = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, base)
+	}
+	var branches [][]*doltdb.Commit
+	for _, name := range []string{"left", "right"} {
+		require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef(name), base))
+		commits := []*doltdb.Commit{mustCreateCommit(t, dEnv.DoltDB, name, rvh, base)}
+		commits = append(commits, mustCreateCommit(t, dEnv.DoltDB, name, rvh, commits[0]))
+		branches = append(branches, commits)
+	}
+	mainHead := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, base)
+
+	collect := func(itr doltdb.CommitItr) []hash.Hash {
+		var hashes []hash.Hash
+		for {
+			h, _, err := itr.Next(ctx)
+			if err == io.EOF {
+				return hashes
+			}
+			require.NoError(t, err)
+			hashes = append(hashes, h)
+		}
+	}
+
+	leftHash, rightHash := mustGetHash(t, branches[0][1]), mustGetHash(t, branches[1][1])
+	mainHash, baseHash := mustGetHash(t, mainHead), mustGetHash(t, base)
+
+	// Every head is walked together, and commits are ordered by height, with ties broken by timestamp
+	itr, err := GetMultiRevisionsIterator(ctx, dEnv.DoltDB, []hash.Hash{leftHash, rightHash, mainHash}, []hash.Hash{baseHash}, nil)
+	require.NoError(t, err)
+	expected := []hash.Hash{
+		mustGetHash(t, branches[1][1]),
+		mustGetHash(t, branches[0][1]),
+		mainHash,
+		mustGetHash(t, branches[1][0]),
+		mustGetHash(t, branches[0][0]),
+	}
+	assert.Equal(t, expected, collect(itr))
+	require.NoError(t, itr.Reset(ctx))
+	assert.Equal(t, expected, collect(itr))
+
+	// Commits reachable from any excluded commit are excluded, including the heads themselves
+	itr, err = GetMultiRevisionsIterator(ctx, dEnv.DoltDB, []hash.Hash{leftHash, rightHash, mainHash}, []hash.Hash{rightHash, mainHash}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{leftHash, mustGetHash(t, branches[0][0])}, collect(itr))
+
+	// Commits shared by several heads are only returned once
+	itr, err = GetMultiRevisionsIterator(ctx, dEnv.DoltDB, []hash.Hash{leftHash, rightHash}, nil, nil)
+	require.NoError(t, err)
+	hashes := collect(itr)
+	assert.Len(t, hashes, 8)
+	assert.Equal(t, baseHash, hashes[4])
+
+	// The first parent walk of each head stops at excluded commits in the same way
+	itr, err = GetFirstParentMultiRevisionsIterator(ctx, dEnv.DoltDB, []hash.Hash{leftHash, mainHash}, []hash.Hash{baseHash}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{leftHash, mainHash, mustGetHash(t, branches[0][0])}, collect(itr))
+}
+
+func TestGetFirstParentIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	initCommit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := initCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this, where topic is merged into feature, which is then merged into main:
+	//
+	//                  topic: *
+	//                        / \
+	//          feature:  *--*--*
+	//                   /       \
+	// main: --*--*--*--*-----*--*--*
+	m1 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, initCommit)
+	m2 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m1)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), m2))
+	f1 := mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, m2)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("topic"), f1))
+	t1 := mustCreateCommit(t, dEnv.DoltDB, "topic", rvh, f1)
+	f2 := mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, f1)
+	f3 := mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, f2, t1)
+	m3 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m2)
+	m4 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m3, f3)
+	m5 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m4)
+
+	collect := func(itr doltdb.CommitItr) []hash.Hash {
+		var hashes []hash.Hash
+		for {
+			h, _, err := itr.Next(ctx)
+			if err == io.EOF {
+				return hashes
+			}
+			require.NoError(t, err)
+			hashes = append(hashes, h)
+		}
+	}
+	hashes := func(commits ...*doltdb.Commit) []hash.Hash {
+		var hashes []hash.Hash
+		for _, cm := range commits {
+			hashes = append(hashes, mustGetHash(t, cm))
+		}
+		return hashes
+	}
+
+	// Only the mainline is walked, so neither feature nor topic commits are returned
+	itr, err := GetFirstParentIterator(ctx, dEnv.DoltDB, mustGetHash(t, m5), nil)
+	require.NoError(t, err)
+	expected := hashes(m5, m4, m3, m2, m1, initCommit)
+	assert.Equal(t, expected, collect(itr))
+	require.NoError(t, itr.Reset(ctx))
+	assert.Equal(t, expected, collect(itr))
+
+	itr, err = GetFirstParentIterator(ctx, dEnv.DoltDB, mustGetHash(t, f3), nil)
+	require.NoError(t, err)
+	assert.Equal(t, hashes(f3, f2, f1, m2, m1, initCommit), collect(itr))
+
+	// Merges that are reached by the walk can still be matched
+	itr, err = GetFirstParentIterator(ctx, dEnv.DoltDB, mustGetHash(t, m5), func(cm *doltdb.Commit) (bool, error) {
+		return cm.NumParents() > 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hashes(m4), collect(itr))
+
+	// Only the included side is restricted to first parents, so every ancestor of the excluded commit is excluded
+	itr, err = GetFirstParentDotDotRevisionsIterator(ctx, dEnv.DoltDB, mustGetHash(t, m5), mustGetHash(t, f2), nil)
+	require.NoError(t, err)
+	assert.Equal(t, hashes(m5, m4, m3), collect(itr))
+	itr, err = GetFirstParentDotDotRevisionsIterator(ctx, dEnv.DoltDB, mustGetHash(t, f3), mustGetHash(t, m3), nil)
+	require.NoError(t, err)
+	assert.Equal(t, hashes(f3, f2, f1), collect(itr))
+	itr, err = GetDotDotRevisionsIterator(ctx, dEnv.DoltDB, mustGetHash(t, f3), mustGetHash(t, m3), nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, hashes(f3, f2, t1, f1), collect(itr))
+
+	itr, err = GetFirstParentThreeDotRevisionsIterator(ctx, dEnv.DoltDB, mustGetHash(t, f3), mustGetHash(t, m3), mustGetHash(t, m2), nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, hashes(f3, f2, f1, m3), collect(itr))
+}
+
+func TestWalkStats(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	initCommit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := initCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+	m1 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, initCommit)
+	m2 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m1)
+	m3 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m2)
+
+	drain := func(itr doltdb.CommitItr) int {
+		count := 0
+		for {
+			_, _, err := itr.Next(ctx)
+			if err == io.EOF {
+				return count
+			}
+			require.NoError(t, err)
+			count++
+		}
+	}
+	skipM2 := func(cm *doltdb.Commit) (bool, error) {
+		h, err := cm.HashOf()
+		return h != mustGetHash(t, m2), err
+	}
+
+	// Every commit is visited and read once, and the commit rejected by the match function is skipped
+	stats := &WalkStats{}
+	itr, err := GetTopologicalOrderIterator(WithWalkStats(ctx, stats), dEnv.DoltDB, mustGetHash(t, m3), skipM2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, drain(itr))
+	assert.Equal(t, WalkStats{Visited: 4, Skipped: 1, ChunkReads: 4}, *stats)
+
+	// Wrapping iterators count their work in the stats of their child, and the stats are kept across resets
+	reverse := GetReverseIterator(dEnv.DoltDB, itr)
+	require.NoError(t, reverse.Reset(ctx))
+	assert.Equal(t, 3, drain(reverse))
+	assert.Equal(t, WalkStats{Visited: 8, Skipped: 2, ChunkReads: 11}, *stats)
+
+	// Excluded commits are visited without being skipped
+	stats = &WalkStats{}
+	itr, err = GetDotDotRevisionsIterator(WithWalkStats(ctx, stats), dEnv.DoltDB, mustGetHash(t, m3), mustGetHash(t, m1), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, drain(itr))
+	assert.Zero(t, stats.Skipped)
+	assert.GreaterOrEqual(t, stats.Visited, uint64(2))
+
+	// Nothing is counted by iterators that are created without stats
+	itr, err = GetTopologicalOrderIterator(ctx, dEnv.DoltDB, mustGetHash(t, m3), skipM2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, drain(itr))
+	assert.Nil(t, walkStatsOf(itr))
+}
+
+func TestGetHeightRangeIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	commit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this, where the commit on other is only reachable through the merge into main:
+	//
+	//          feature:  *-----*--*
+	//                   /     /      \
+	// main: --*--*--*--*--*--*--*--*--*--*
+	//             \                     /
+	//       other: *-------------------
+	mainHead := commit
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), mainHead))
+	otherHead := mustCreateCommit(t, dEnv.DoltDB, "other", rvh, mainHead)
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), mainHead))
+	featureHead := mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, mainHead)
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	featureHead = mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, featureHead, mainHead)
+	featureHead = mustCreateCommit(t, dEnv.DoltDB, "feature", rvh, featureHead)
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead, featureHead)
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead, otherHead)
+
+	all, err := GetTopologicalOrderIterator(ctx, dEnv.DoltDB, mustGetHash(t, mainHead), nil)
+	require.NoError(t, err)
+	var allHashes []hash.Hash
+	var allHeights []uint64
+	var allNumParents []int
+	for {
+		h, cm, err := all.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		height, err := cm.Height()
+		require.NoError(t, err)
+		allHashes = append(allHashes, h)
+		allHeights = append(allHeights, height)
+		allNumParents = append(allNumParents, cm.NumParents())
+	}
+	mainHeight, err := mainHead.Height()
+	require.NoError(t, err)
+
+	for minHeight := uint64(0); minHeight <= mainHeight+1; minHeight++ {
+		for maxHeight := minHeight; maxHeight <= mainHeight+1; maxHeight++ {
+			var expected []hash.Hash
+			for i, h := range allHashes {
+				if allHeights[i] >= minHeight && allHeights[i] <= maxHeight {
+					expected = append(expected, h)
+				}
+			}
+
+			itr, err := GetHeightRangeIterator(ctx, dEnv.DoltDB, mustGetHash(t, mainHead), minHeight, maxHeight, nil)
+			require.NoError(t, err)
+			for pass := 0; pass < 2; pass++ {
+				var actual []hash.Hash
+				for {
+					h, cm, err := itr.Next(ctx)
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					assert.Equal(t, h, mustGetHash(t, cm))
+					actual = append(actual, h)
+				}
+				assert.Equal(t, expected, actual, "heights %d to %d", minHeight, maxHeight)
+				require.NoError(t, itr.Reset(ctx))
+			}
+		}
+	}
+
+	// Commits must both match and be within the range
+	matchFn := func(cm *doltdb.Commit) (bool, error) {
+		return cm.NumParents() > 1, nil
+	}
+	var expected []hash.Hash
+	for i, h := range allHashes {
+		if allHeights[i] < mainHeight && allNumParents[i] > 1 {
+			expected = append(expected, h)
+		}
+	}
+	require.Len(t, expected, 2)
+	itr, err := GetHeightRangeIterator(ctx, dEnv.DoltDB, mustGetHash(t, mainHead), 0, mainHeight-1, matchFn)
+	require.NoError(t, err)
+	var actual []hash.Hash
+	for {
+		h, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual = append(actual, h)
+	}
+	assert.Equal(t, expected, actual)
+}
+
+// BenchmarkGetHeightRangeIterator reads pages of commits from a linear history. Reading a later page should take
+// about as long as reading the first.
+func TestFilterDateRange(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	initCommit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := initCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// A commit is made on each day of January, except that the sixth commit's clock was a month behind
+	day := func(d int) time.Time {
+		return time.Date(2023, time.January, d, 12, 0, 0, 0, time.UTC)
+	}
+	var commits []*doltdb.Commit
+	head := initCommit
+	for d := 1; d <= 10; d++ {
+		ts := day(d)
+		if d == 6 {
+			ts = ts.AddDate(0, -1, 0)
+		}
+		head = mustCreateCommitAt(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, ts, head)
+		commits = append(commits, head)
+	}
+
+	walk := func(since, until time.Time) ([]hash.Hash, WalkStats) {
+		stats := &WalkStats{}
+		child, err := GetTopologicalOrderIterator(WithWalkStats(ctx, stats), dEnv.DoltDB, mustGetHash(t, head), nil)
+		require.NoError(t, err)
+		itr := FilterDateRange(child, since, until)
+		var hashes []hash.Hash
+		for {
+			h, _, err := itr.Next(ctx)
+			if err == io.EOF {
+				return hashes, *stats
+			}
+			require.NoError(t, err)
+			hashes = append(hashes, h)
+		}
+	}
+	hashesOf := func(days ...int) []hash.Hash {
+		var hashes []hash.Hash
+		for _, d := range days {
+			hashes = append(hashes, mustGetHash(t, commits[d-1]))
+		}
+		return hashes
+	}
+
+	// The skewed commit is older than since, but it's followed by commits within the range, so the walk continues
+	hashes, _ := walk(day(4), day(8))
+	assert.Equal(t, hashesOf(8, 7, 5, 4), hashes)
+
+	// The walk stops once enough commits in a row are older than since, without reading the rest of the history
+	hashes, stats := walk(day(10), time.Time{})
+	assert.Equal(t, hashesOf(10), hashes)
+	assert.Equal(t, uint64(1+DateRangeSlop), stats.Visited)
+
+	// Either end of the range may be open, and the skewed commit is within a range that only ends on the second
+	hashes, _ = walk(time.Time{}, day(2))
+	assert.Equal(t, hashesOf(6, 2, 1), hashes)
+	hashes, _ = walk(time.Time{}, time.Time{})
+	assert.Len(t, hashes, 11)
+
+	// The range is inclusive at both ends
+	hashes, _ = walk(day(3), day(3))
+	assert.Equal(t, hashesOf(3), hashes)
+}
+
+func TestAncestorSet(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	commit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this, where release is cut from main and then merged back:
+	//
+	// release:          *--*--*
+	//                  /       \
+	// main: --*--*--*--*--*--*--*--*
+	mainHead := commit
+	for i := 0; i < 3; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("release"), mainHead))
+	releaseHead := mainHead
+	for i := 0; i < 3; i++ {
+		releaseHead = mustCreateCommit(t, dEnv.DoltDB, "release", rvh, releaseHead)
+	}
+	for i := 0; i < 2; i++ {
+		mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+	}
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead, releaseHead)
+	mainHead = mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainHead)
+
+	type logged struct {
+		hash   hash.Hash
+		height uint64
+	}
+	walk := func(head *doltdb.Commit) []logged {
+		itr, err := GetTopologicalOrderIterator(ctx, dEnv.DoltDB, mustGetHash(t, head), nil)
+		require.NoError(t, err)
+		var commits []logged
+		for {
+			h, cm, err := itr.Next(ctx)
+			if err == io.EOF {
+				return commits
+			}
+			require.NoError(t, err)
+			height, err := cm.Height()
+			require.NoError(t, err)
+			commits = append(commits, logged{h, height})
+		}
+	}
+	all := walk(mainHead)
+	releaseAncestors := make(map[hash.Hash]bool)
+	for _, cm := range walk(releaseHead) {
+		releaseAncestors[cm.hash] = true
+	}
+	require.Less(t, len(releaseAncestors), len(all))
+
+	t.Run("descending", func(t *testing.T) {
+		as, err := NewAncestorSet(ctx, dEnv.DoltDB, mustGetHash(t, releaseHead))
+		require.NoError(t, err)
+		for _, cm := range all {
+			contains, err := as.Contains(ctx, cm.hash, cm.height)
+			require.NoError(t, err)
+			assert.Equal(t, releaseAncestors[cm.hash], contains, "commit at height %d", cm.height)
+		}
+	})
+	t.Run("ascending", func(t *testing.T) {
+		as, err := NewAncestorSet(ctx, dEnv.DoltDB, mustGetHash(t, releaseHead))
+		require.NoError(t, err)
+		for i := len(all) - 1; i >= 0; i-- {
+			contains, err := as.Contains(ctx, all[i].hash, all[i].height)
+			require.NoError(t, err)
+			assert.Equal(t, releaseAncestors[all[i].hash], contains, "commit at height %d", all[i].height)
+		}
+	})
+	t.Run("walks no lower than asked", func(t *testing.T) {
+		as, err := NewAncestorSet(ctx, dEnv.DoltDB, mustGetHash(t, releaseHead))
+		require.NoError(t, err)
+		releaseHeight, err := releaseHead.Height()
+		require.NoError(t, err)
+		contains, err := as.Contains(ctx, mustGetHash(t, releaseHead), releaseHeight)
+		require.NoError(t, err)
+		assert.True(t, contains)
+		assert.Len(t, as.reached, 1)
+	})
+}
+
+func TestMissingAncestors(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	initCommit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := initCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	mainCommits := []*doltdb.Commit{initCommit}
+	for i := 1; i < 6; i++ {
+		mainCommits = append(mainCommits, mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, mainCommits[i-1]))
+	}
+
+	// Loading a commit requires its parents, so removing the chunk of the second commit leaves the fourth commit as
+	// the oldest commit that can be loaded
+	missing := hash.NewHashSet(mustGetHash(t, mainCommits[2]))
+	ddb := doltdb.DoltDBFromCS(&chunks.MissingChunkStore{
+		ChunkStore: datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB)),
+		Missing:    missing,
+	})
+	head := mustGetHash(t, mainCommits[5])
+	boundary := mustGetHash(t, mainCommits[4])
+	_, err = ddb.ReadCommit(ctx, mustGetHash(t, mainCommits[3]))
+	require.ErrorIs(t, err, datas.ErrCommitNotFound)
+
+	// walk returns the hashes that the iterator returns before it fails, along with the error that it fails with
+	walk := func(t *testing.T, itr doltdb.CommitItr) ([]hash.Hash, *MissingAncestorError) {
+		var hashes []hash.Hash
+		for {
+			h, _, err := itr.Next(ctx)
+			var missingErr *MissingAncestorError
+			if errors.As(err, &missingErr) {
+				return hashes, missingErr
+			}
+			require.NoError(t, err)
+			hashes = append(hashes, h)
+		}
+	}
+	topological := func() doltdb.CommitItr {
+		itr, err := GetTopologicalOrderIterator(ctx, ddb, head, nil)
+		require.NoError(t, err)
+		return itr
+	}
+
+	t.Run("topological", func(t *testing.T) {
+		hashes, missingErr := walk(t, topological())
+		assert.Equal(t, []hash.Hash{head}, hashes)
+		assert.Equal(t, boundary, missingErr.Hash)
+		assert.Equal(t, boundary, mustGetHash(t, missingErr.Commit))
+		assert.Equal(t, mustGetHash(t, mainCommits[3]), missingErr.Missing)
+		assert.ErrorIs(t, missingErr, datas.ErrCommitNotFound)
+	})
+	t.Run("unmatched boundary", func(t *testing.T) {
+		itr, err := GetTopologicalOrderIterator(ctx, ddb, head, func(cm *doltdb.Commit) (bool, error) {
+			return mustGetHash(t, cm) != boundary, nil
+		})
+		require.NoError(t, err)
+		hashes, missingErr := walk(t, itr)
+		assert.Equal(t, []hash.Hash{head}, hashes)
+		assert.Equal(t, boundary, missingErr.Hash)
+		assert.Nil(t, missingErr.Commit)
+	})
+	t.Run("dot dot", func(t *testing.T) {
+		itr, err := GetDotDotRevisionsIterator(ctx, ddb, head, mustGetHash(t, initCommit), nil)
+		require.NoError(t, err)
+		hashes, missingErr := walk(t, itr)
+		assert.Equal(t, []hash.Hash{head}, hashes)
+		assert.Equal(t, boundary, mustGetHash(t, missingErr.Commit))
+	})
+	t.Run("reverse", func(t *testing.T) {
+		hashes, missingErr := walk(t, GetReverseIterator(ddb, topological()))
+		assert.Equal(t, []hash.Hash{boundary, head}, hashes)
+		assert.Equal(t, boundary, missingErr.Hash)
+		assert.Nil(t, missingErr.Commit)
+	})
+	t.Run("height range", func(t *testing.T) {
+		height, err := mainCommits[5].Height()
+		require.NoError(t, err)
+		hashes, missingErr := walk(t, FilterHeightRange(topological(), height, height))
+		assert.Equal(t, []hash.Hash{head}, hashes)
+		assert.Nil(t, missingErr.Commit)
+		hashes, missingErr = walk(t, FilterHeightRange(topological(), height-1, height))
+		assert.Equal(t, []hash.Hash{head}, hashes)
+		assert.Equal(t, boundary, mustGetHash(t, missingErr.Commit))
+	})
+	t.Run("ancestor set", func(t *testing.T) {
+		as, err := NewAncestorSet(ctx, ddb, head)
+		require.NoError(t, err)
+		contains, err := as.Contains(ctx, mustGetHash(t, initCommit), 1)
+		require.NoError(t, err)
+		assert.False(t, contains)
+		contains, err = as.Contains(ctx, boundary, 5)
+		require.NoError(t, err)
+		assert.True(t, contains)
+	})
+}
+
+func BenchmarkGetHeightRangeIterator(b *testing.B) {
+	const numCommits = 10_000
+	const pageSize = 100
+
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(b, err)
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(b, err)
+	head, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(b, err)
+	rv, err := head.GetRootValue(ctx)
+	require.NoError(b, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(b, err)
+	for i := 1; i < numCommits; i++ {
+		head = mustCreateCommit(b, dEnv.DoltDB, env.DefaultInitBranch, rvh, head)
+	}
+	headHash := mustGetHash(b, head)
+
+	for _, page := range []uint64{1, 10, 100} {
+		b.Run(fmt.Sprintf("page %d", page), func(b *testing.B) {
+			maxHeight := numCommits - (page-1)*pageSize
+			for i := 0; i < b.N; i++ {
+				itr, err := GetHeightRangeIterator(ctx, dEnv.DoltDB, headHash, maxHeight-pageSize+1, maxHeight, nil)
+				require.NoError(b, err)
+				for n := 0; ; n++ {
+					_, _, err := itr.Next(ctx)
+					if err == io.EOF {
+						require.Equal(b, pageSize, n)
+						break
+					}
+					require.NoError(b, err)
+				}
+			}
+		})
+	}
+}
+
+func containsHash(hashes []hash.Hash, h hash.Hash) bool {
+	for _, other := range hashes {
+		if other == h {
+			return true
+		}
+	}
+	return false
+}
+
 func assertEqualHashes(t *testing.T, lc, rc *doltdb.Commit) {
 	assert.Equal(t, mustGetHash(t, lc), mustGetHash(t, rc))
 }
 
-func mustCreateCommit(t *testing.T, ddb *doltdb.DoltDB, bn string, rvh hash.Hash, parents ...*doltdb.Commit) *doltdb.Commit {
-	cm, err := datas.NewCommitMetaWithUserTS("Bill Billerson", "bill@billerson.com", "A New Commit.", MonotonicNow())
+func mustCreateCommit(t testing.TB, ddb *doltdb.DoltDB, bn string, rvh hash.Hash, parents ...*doltdb.Commit) *doltdb.Commit {
+	return mustCreateCommitAt(t, ddb, bn, rvh, MonotonicNow(), parents...)
+}
+
+func mustCreateCommitAt(t testing.TB, ddb *doltdb.DoltDB, bn string, rvh hash.Hash, ts time.Time, parents ...*doltdb.Commit) *doltdb.Commit {
+	cm, err := datas.NewCommitMetaWithUserTS("Bill Billerson", "bill@billerson.com", "A New Commit.", ts)
 	require.NoError(t, err)
 	pcs := make([]*doltdb.CommitSpec, 0, len(parents))
 	for _, parent := range parents {
@@ -256,8 +1112,105 @@ func mustForkDB(t *testing.T, fromDB *doltdb.DoltDB, bn string, cm *doltdb.Commi
 	return forkEnv
 }
 
-func mustGetHash(t *testing.T, c *doltdb.Commit) hash.Hash {
+func mustGetHash(t testing.TB, c *doltdb.Commit) hash.Hash {
 	h, err := c.HashOf()
 	require.NoError(t, err)
 	return h
 }
+
+func TestGetSourceIterator(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createUninitializedEnv()
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	initCommit, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	rv, err := initCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	// Branches look like this, sharing most of their history with main:
+	//
+	//                   b: *--*
+	//                     /
+	// main: --*--*--*--*--*
+	//                     \
+	//                   a: *
+	m1 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, initCommit)
+	m2 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m1)
+	m3 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m2)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("a"), m3))
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("b"), m3))
+	a1 := mustCreateCommit(t, dEnv.DoltDB, "a", rvh, m3)
+	b1 := mustCreateCommit(t, dEnv.DoltDB, "b", rvh, m3)
+	b2 := mustCreateCommit(t, dEnv.DoltDB, "b", rvh, b1)
+	m4 := mustCreateCommit(t, dEnv.DoltDB, env.DefaultInitBranch, rvh, m3)
+	heads := []hash.Hash{mustGetHash(t, m4), mustGetHash(t, a1), mustGetHash(t, b2)}
+	const main, a, b = 0, 1, 2
+
+	type sourcedCommit struct {
+		commit  *doltdb.Commit
+		sources []int
+	}
+	collect := func(itr *SourceIterator) []sourcedCommit {
+		var commits []sourcedCommit
+		for {
+			h, cm, err := itr.Next(ctx)
+			if err == io.EOF {
+				return commits
+			}
+			require.NoError(t, err)
+			commits = append(commits, sourcedCommit{commit: cm, sources: itr.Sources(h)})
+		}
+	}
+
+	// Every head walks the whole history, and shared commits are attributed to each head that reaches them
+	itr, err := GetSourceIterator(ctx, dEnv.DoltDB, heads, 0, nil, false)
+	require.NoError(t, err)
+	expected := []sourcedCommit{
+		{b2, []int{b}},
+		{m4, []int{main}},
+		{b1, []int{b}},
+		{a1, []int{a}},
+		{m3, []int{main, a, b}},
+		{m2, []int{main, a, b}},
+		{m1, []int{main, a, b}},
+		{initCommit, []int{main, a, b}},
+	}
+	assert.Equal(t, expected, collect(itr))
+	require.NoError(t, itr.Reset(ctx))
+	assert.Equal(t, expected, collect(itr))
+
+	// Each head stops once two commits have been attributed to it, so m3 is the last commit of both main and a, and b
+	// is already done before reaching it
+	itr, err = GetSourceIterator(ctx, dEnv.DoltDB, heads, 2, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []sourcedCommit{
+		{b2, []int{b}},
+		{m4, []int{main}},
+		{b1, []int{b}},
+		{a1, []int{a}},
+		{m3, []int{main, a}},
+	}, collect(itr))
+
+	// Commits that don't match are walked through without counting against any head
+	itr, err = GetSourceIterator(ctx, dEnv.DoltDB, heads, 1, func(cm *doltdb.Commit) (bool, error) {
+		h := mustGetHash(t, cm)
+		return h != mustGetHash(t, m4) && h != mustGetHash(t, b2), nil
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []sourcedCommit{
+		{b1, []int{b}},
+		{a1, []int{a}},
+		{m3, []int{main}},
+	}, collect(itr))
+
+	// Heads at the same commit are both attributed
+	itr, err = GetSourceIterator(ctx, dEnv.DoltDB, []hash.Hash{mustGetHash(t, m2), mustGetHash(t, m2)}, 1, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []sourcedCommit{{m2, []int{0, 1}}}, collect(itr))
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// AncestorSet answers whether commits are reachable from a head commit. The ancestors of the head are walked in
+// descending order of height, and only as far down as the lowest commit that has been asked about, since a commit can
+// never be reachable from a commit at the same or a lower height. Asking about commits in descending order of height,
+// as the log does, therefore walks each ancestor at most once, and never walks below the oldest commit in the log.
+type AncestorSet struct {
+	ddb     *doltdb.DoltDB
+	q       *q
+	reached map[hash.Hash]struct{}
+}
+
+// NewAncestorSet returns an AncestorSet for the commit at |head|, which is considered to be its own ancestor.
+func NewAncestorSet(ctx context.Context, ddb *doltdb.DoltDB, head hash.Hash) (*AncestorSet, error) {
+	as := &AncestorSet{ddb: ddb, q: newQueue(walkStatsFromContext(ctx)), reached: make(map[hash.Hash]struct{})}
+	if err := as.q.AddPendingIfUnseen(ctx, ddb, head); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// Contains returns whether the commit |h|, with the given |height|, is reachable from the head commit.
+func (as *AncestorSet) Contains(ctx context.Context, h hash.Hash, height uint64) (bool, error) {
+	for as.q.Len() > 0 && as.q.pending[0].height >= height {
+		nextC := as.q.PopPending()
+		as.reached[nextC.hash] = struct{}{}
+		parents, err := nextC.commit.ParentHashes(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, parentID := range parents {
+			// Ancestors that are missing from the database, such as those omitted by a shallow clone, cannot be
+			// walked, so they are skipped
+			if err := as.q.AddPendingIfUnseen(ctx, as.ddb, parentID); err != nil && !errors.Is(err, datas.ErrCommitNotFound) {
+				return false, err
+			}
+		}
+	}
+	_, ok := as.reached[h]
+	return ok, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// DateRangeSlop is the number of consecutive commits older than the start of a date range that FilterDateRange reads
+// before it stops reading the iterator. Commit timestamps are set by the committer's clock, so a commit may be older
+// than one of its ancestors, and the walk allows for a few such commits before giving up, as git does.
+const DateRangeSlop = 5
+
+// FilterDateRange returns an iterator over the commits of the given iterator whose timestamps are within the range
+// [since, until], where a zero time leaves that end of the range open. The given iterator should return commits in
+// descending order of height, so that the walk may stop once DateRangeSlop commits in a row are older than since.
+func FilterDateRange(child doltdb.CommitItr, since, until time.Time) doltdb.CommitItr {
+	return &dateFilterCommiterator{child: child, since: since, until: until}
+}
+
+type dateFilterCommiterator struct {
+	child doltdb.CommitItr
+	since time.Time
+	until time.Time
+	// older counts the consecutive commits older than since
+	older int
+	done  bool
+}
+
+var _ doltdb.CommitItr = (*dateFilterCommiterator)(nil)
+var _ statsIterator = (*dateFilterCommiterator)(nil)
+
+// Next implements doltdb.CommitItr
+func (i *dateFilterCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
+	for !i.done {
+		h, commit, err := i.child.Next(ctx)
+		var missingErr *MissingAncestorError
+		if errors.As(err, &missingErr) && missingErr.Commit != nil {
+			// The walk ends at the boundary commit, which is only returned when it is within the range
+			i.done = true
+			within, withinErr := i.within(ctx, missingErr.Commit)
+			if withinErr != nil {
+				return hash.Hash{}, nil, withinErr
+			}
+			if !within {
+				filtered := *missingErr
+				filtered.Commit = nil
+				return hash.Hash{}, nil, &filtered
+			}
+			return hash.Hash{}, nil, err
+		} else if err != nil {
+			return hash.Hash{}, nil, err
+		}
+
+		within, err := i.within(ctx, commit)
+		if err != nil {
+			return hash.Hash{}, nil, err
+		}
+		if within {
+			return h, commit, nil
+		}
+		if i.older >= DateRangeSlop {
+			i.done = true
+		}
+	}
+	return hash.Hash{}, nil, io.EOF
+}
+
+// within returns whether the given commit is within the range, counting the commits that are older than it.
+func (i *dateFilterCommiterator) within(ctx context.Context, commit *doltdb.Commit) (bool, error) {
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return false, err
+	}
+	t := meta.Time()
+	if !i.since.IsZero() && t.Before(i.since) {
+		i.older++
+		return false, nil
+	}
+	i.older = 0
+	return i.until.IsZero() || !t.After(i.until), nil
+}
+
+func (i *dateFilterCommiterator) walkStats() *WalkStats {
+	return walkStatsOf(i.child)
+}
+
+// Reset implements doltdb.CommitItr
+func (i *dateFilterCommiterator) Reset(ctx context.Context) error {
+	i.older = 0
+	i.done = false
+	return i.child.Reset(ctx)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// GetHeightRangeIterator returns an iterator for commits generated with the same semantics as
+// GetTopologicalOrderIterator, except that only the commits with heights in the range [minHeight, maxHeight] are
+// returned. The topological order returns commits in descending order of height, with ties broken by timestamp. When
+// the start commit is above maxHeight, the commits of each height are therefore read directly from the start commit's
+// parent closure, so that the commits above maxHeight are never loaded. Commits that do not store a parent closure are
+// walked from the start commit instead.
+func GetHeightRangeIterator(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash hash.Hash, minHeight, maxHeight uint64, matchFn func(*doltdb.Commit) (bool, error)) (doltdb.CommitItr, error) {
+	stats := walkStatsFromContext(ctx)
+	start, err := load(ctx, ddb, stats, startCommitHash)
+	if err != nil {
+		return nil, err
+	}
+	startHeight, err := start.Height()
+	if err != nil {
+		return nil, err
+	}
+	if startHeight > maxHeight {
+		_, ok, err := start.GetAncestorsAtHeight(ctx, maxHeight)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			itr := &heightCommiterator{ddb: ddb, start: start, minHeight: minHeight, maxHeight: maxHeight, matchFn: matchFn, stats: stats}
+			return itr, itr.Reset(ctx)
+		}
+	}
+
+	child, err := GetTopologicalOrderIterator(ctx, ddb, startCommitHash, matchFn)
+	if err != nil {
+		return nil, err
+	}
+	return FilterHeightRange(child, minHeight, maxHeight), nil
+}
+
+// heightCommiterator emits the ancestors of a commit by reading each height from the commit's parent closure, beginning
+// with maxHeight.
+type heightCommiterator struct {
+	ddb       *doltdb.DoltDB
+	start     *doltdb.Commit
+	minHeight uint64
+	maxHeight uint64
+	matchFn   func(*doltdb.Commit) (bool, error)
+	stats     *WalkStats
+
+	// height is the next height to read from the parent closure, while pending holds the unemitted commits of the
+	// previous height
+	height  uint64
+	pending []*c
+}
+
+var _ doltdb.CommitItr = (*heightCommiterator)(nil)
+var _ statsIterator = (*heightCommiterator)(nil)
+
+// Next implements doltdb.CommitItr
+func (i *heightCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
+	for {
+		for len(i.pending) == 0 {
+			if i.height == 0 || i.height < i.minHeight {
+				return hash.Hash{}, nil, io.EOF
+			}
+			if err := i.loadHeight(ctx); err != nil {
+				return hash.Hash{}, nil, err
+			}
+		}
+
+		nextC := i.pending[0]
+		i.pending = i.pending[1:]
+		if i.matchFn != nil {
+			matches, err := i.matchFn(nextC.commit)
+			if err != nil {
+				return hash.Hash{}, nil, err
+			}
+			i.stats.visit(matches)
+			if !matches {
+				continue
+			}
+		} else {
+			i.stats.visit(true)
+		}
+		return nextC.hash, nextC.commit, nil
+	}
+}
+
+// loadHeight loads every commit at the current height into pending, ordered from the newest to the oldest.
+func (i *heightCommiterator) loadHeight(ctx context.Context) error {
+	hashes, _, err := i.start.GetAncestorsAtHeight(ctx, i.height)
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		commit, err := load(ctx, i.ddb, i.stats, h)
+		if errors.Is(err, datas.ErrCommitNotFound) {
+			// The commit that references the missing ancestor is unknown, as the ancestors are read from the closure
+			return &MissingAncestorError{Missing: h}
+		} else if err != nil {
+			return err
+		}
+		meta, err := commit.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		i.pending = append(i.pending, &c{ddb: i.ddb, commit: commit, meta: meta, hash: h, height: i.height})
+	}
+	sort.SliceStable(i.pending, func(a, b int) bool {
+		return i.pending[a].meta.UserTimestamp > i.pending[b].meta.UserTimestamp
+	})
+	i.height--
+	return nil
+}
+
+func (i *heightCommiterator) walkStats() *WalkStats {
+	return i.stats
+}
+
+// Reset implements doltdb.CommitItr
+func (i *heightCommiterator) Reset(ctx context.Context) error {
+	i.height = i.maxHeight
+	i.pending = nil
+	return nil
+}
+
+// FilterHeightRange returns an iterator over the commits of the given iterator with heights in the range
+// [minHeight, maxHeight]. The given iterator must return commits in descending order of height, such as the iterators
+// returned by GetTopologicalOrderIterator and GetDotDotRevisionsIterator, so that it is no longer read once a commit
+// below minHeight is returned.
+func FilterHeightRange(child doltdb.CommitItr, minHeight, maxHeight uint64) doltdb.CommitItr {
+	return &heightFilterCommiterator{child: child, minHeight: minHeight, maxHeight: maxHeight}
+}
+
+type heightFilterCommiterator struct {
+	child     doltdb.CommitItr
+	minHeight uint64
+	maxHeight uint64
+	done      bool
+}
+
+var _ doltdb.CommitItr = (*heightFilterCommiterator)(nil)
+var _ statsIterator = (*heightFilterCommiterator)(nil)
+
+// Next implements doltdb.CommitItr
+func (i *heightFilterCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
+	for !i.done {
+		h, commit, err := i.child.Next(ctx)
+		var missingErr *MissingAncestorError
+		if errors.As(err, &missingErr) && missingErr.Commit != nil {
+			// The walk ends at the boundary commit, which is only returned when it is within the range
+			i.done = true
+			height, heightErr := missingErr.Commit.Height()
+			if heightErr != nil {
+				return hash.Hash{}, nil, heightErr
+			}
+			if height < i.minHeight || height > i.maxHeight {
+				filtered := *missingErr
+				filtered.Commit = nil
+				return hash.Hash{}, nil, &filtered
+			}
+			return hash.Hash{}, nil, err
+		} else if err != nil {
+			return hash.Hash{}, nil, err
+		}
+		height, err := commit.Height()
+		if err != nil {
+			return hash.Hash{}, nil, err
+		}
+		if height < i.minHeight {
+			i.done = true
+		} else if height <= i.maxHeight {
+			return h, commit, nil
+		}
+	}
+	return hash.Hash{}, nil, io.EOF
+}
+
+func (i *heightFilterCommiterator) walkStats() *WalkStats {
+	return walkStatsOf(i.child)
+}
+
+// Reset implements doltdb.CommitItr
+func (i *heightFilterCommiterator) Reset(ctx context.Context) error {
+	i.done = false
+	return i.child.Reset(ctx)
+}
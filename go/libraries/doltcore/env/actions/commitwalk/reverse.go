@@ -0,0 +1,191 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+)
+
+// reverseBufferSize is the number of hashes that a reverse iterator holds in memory. Any hashes beyond this are spilled
+// to a temporary file.
+var reverseBufferSize = 1 << 16
+
+// reverseCommiterator emits the commits of its child iterator in the opposite order. Only the hashes of the child's
+// commits are buffered, and each commit is loaded again as it is emitted.
+type reverseCommiterator struct {
+	ddb   *doltdb.DoltDB
+	child doltdb.CommitItr
+	// stats are those of the child, as each commit is loaded again from the database
+	stats *WalkStats
+
+	buffered bool
+	// missing is returned once every buffered commit has been returned, when the child ended at a missing ancestor
+	missing   *MissingAncestorError
+	hashes    []hash.Hash
+	spill     *os.File
+	numSpills int
+}
+
+var _ doltdb.CommitItr = (*reverseCommiterator)(nil)
+var _ io.Closer = (*reverseCommiterator)(nil)
+var _ statsIterator = (*reverseCommiterator)(nil)
+
+// GetReverseIterator returns an iterator that emits the commits of the given iterator in reverse order. When given an
+// iterator in reverse topological order, such as the one returned by GetTopologicalOrderIterator, the returned
+// iterator emits every commit after all of its parents. The child iterator is fully consumed on the first call to
+// Next. The returned iterator implements io.Closer, which removes any spilled hashes.
+func GetReverseIterator(ddb *doltdb.DoltDB, child doltdb.CommitItr) doltdb.CommitItr {
+	return &reverseCommiterator{ddb: ddb, child: child, stats: walkStatsOf(child)}
+}
+
+// Next implements doltdb.CommitItr
+func (i *reverseCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
+	if !i.buffered {
+		if err := i.bufferChild(ctx); err != nil {
+			return hash.Hash{}, nil, err
+		}
+		i.buffered = true
+	}
+
+	if len(i.hashes) == 0 {
+		if i.numSpills == 0 {
+			if i.missing != nil {
+				return hash.Hash{}, nil, i.missing
+			}
+			return hash.Hash{}, nil, io.EOF
+		}
+		if err := i.readSpill(); err != nil {
+			return hash.Hash{}, nil, err
+		}
+	}
+
+	h := i.hashes[len(i.hashes)-1]
+	i.hashes = i.hashes[:len(i.hashes)-1]
+	commit, err := load(ctx, i.ddb, i.stats, h)
+	if err != nil {
+		return hash.Hash{}, nil, err
+	}
+	return h, commit, nil
+}
+
+func (i *reverseCommiterator) walkStats() *WalkStats {
+	return i.stats
+}
+
+// Reset implements doltdb.CommitItr
+func (i *reverseCommiterator) Reset(ctx context.Context) error {
+	if err := i.Close(); err != nil {
+		return err
+	}
+	i.buffered = false
+	i.missing = nil
+	i.hashes = nil
+	return i.child.Reset(ctx)
+}
+
+// Close removes the file holding any spilled hashes.
+func (i *reverseCommiterator) Close() error {
+	if i.spill == nil {
+		return nil
+	}
+	name := i.spill.Name()
+	err := i.spill.Close()
+	i.spill = nil
+	i.numSpills = 0
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// bufferChild reads every hash from the child iterator. Whenever the in-memory buffer is full, it is appended to the
+// spill file as a single block.
+func (i *reverseCommiterator) bufferChild(ctx context.Context) error {
+	i.hashes = make([]hash.Hash, 0, reverseBufferSize)
+	for {
+		h, _, err := i.child.Next(ctx)
+		var missingErr *MissingAncestorError
+		if err == io.EOF {
+			return nil
+		} else if errors.As(err, &missingErr) {
+			// The boundary commit is the oldest commit, so it's returned first, while the error is returned last
+			i.missing = &MissingAncestorError{Hash: missingErr.Hash, Missing: missingErr.Missing}
+			if missingErr.Commit == nil {
+				return nil
+			}
+			h = missingErr.Hash
+		} else if err != nil {
+			return err
+		}
+
+		if len(i.hashes) == reverseBufferSize {
+			if err = i.writeSpill(); err != nil {
+				return err
+			}
+		}
+		i.hashes = append(i.hashes, h)
+		if i.missing != nil {
+			return nil
+		}
+	}
+}
+
+// writeSpill appends the in-memory buffer to the spill file, and then empties the buffer.
+func (i *reverseCommiterator) writeSpill() error {
+	if i.spill == nil {
+		f, err := tempfiles.MovableTempFileProvider.NewFile("", "commit_walk_reverse_")
+		if err != nil {
+			return err
+		}
+		i.spill = f
+	}
+
+	block := make([]byte, 0, len(i.hashes)*hash.ByteLen)
+	for _, h := range i.hashes {
+		block = append(block, h[:]...)
+	}
+	if _, err := i.spill.Write(block); err != nil {
+		return err
+	}
+	i.numSpills++
+	i.hashes = i.hashes[:0]
+	return nil
+}
+
+// readSpill replaces the in-memory buffer with the most recently spilled block, which is then removed from the file.
+func (i *reverseCommiterator) readSpill() error {
+	i.numSpills--
+	offset := int64(i.numSpills) * int64(reverseBufferSize*hash.ByteLen)
+	block := make([]byte, reverseBufferSize*hash.ByteLen)
+	if _, err := i.spill.ReadAt(block, offset); err != nil {
+		return err
+	}
+	if err := i.spill.Truncate(offset); err != nil {
+		return err
+	}
+
+	i.hashes = i.hashes[:0]
+	for start := 0; start < len(block); start += hash.ByteLen {
+		i.hashes = append(i.hashes, hash.New(block[start:start+hash.ByteLen]))
+	}
+	return nil
+}
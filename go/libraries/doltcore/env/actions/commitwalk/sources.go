@@ -0,0 +1,179 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// SourceIterator is an iterator over the commits reachable from any of several heads, in the same order as
+// GetTopologicalOrderIterator, that tracks which heads reach each returned commit.
+//
+// Roughly mimics `git log --all --source`.
+type SourceIterator struct {
+	ddb         *doltdb.DoltDB
+	heads       []hash.Hash
+	matchFn     func(*doltdb.Commit) (bool, error)
+	perSource   int
+	firstParent bool
+	q           *q
+	stats       *WalkStats
+	// reached holds the indexes of the heads that have reached each commit in the queue. Commits are visited in
+	// descending order of height, so every child of a commit has propagated its heads before the commit is visited.
+	reached map[hash.Hash][]int
+	// sources holds the indexes of the heads that each returned commit is attributed to
+	sources map[hash.Hash][]int
+	// emitted is the number of returned commits that are attributed to each head
+	emitted []int
+}
+
+var _ doltdb.CommitItr = (*SourceIterator)(nil)
+var _ statsIterator = (*SourceIterator)(nil)
+
+// GetSourceIterator returns an iterator over the commits reachable from any of the given heads. When |perSource| is
+// positive, the walk from each head stops once that many commits attributed to it have been returned, where a commit
+// is attributed to every head that reaches it and has not yet met its quota. A commit shared by several heads counts
+// against each of them. Commits that don't match |matchFn| are walked through, but are not returned or counted.
+func GetSourceIterator(ctx context.Context, ddb *doltdb.DoltDB, heads []hash.Hash, perSource int, matchFn func(*doltdb.Commit) (bool, error), firstParent bool) (*SourceIterator, error) {
+	itr := &SourceIterator{
+		ddb:         ddb,
+		heads:       heads,
+		matchFn:     matchFn,
+		perSource:   perSource,
+		firstParent: firstParent,
+		stats:       walkStatsFromContext(ctx),
+	}
+
+	err := itr.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return itr, nil
+}
+
+// Sources returns the indexes of the heads that the returned commit with the given hash is attributed to, in
+// ascending order. Returns nil for commits that have not been returned.
+func (i *SourceIterator) Sources(h hash.Hash) []int {
+	return i.sources[h]
+}
+
+// Next implements doltdb.CommitItr
+func (i *SourceIterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commit, error) {
+	for i.q.NumVisiblePending() > 0 {
+		nextC := i.q.PopPending()
+		reached := i.reached[nextC.hash]
+		delete(i.reached, nextC.hash)
+		// Heads that met their quota after this commit was queued no longer walk through it
+		active := i.withinQuota(reached)
+		if len(active) == 0 {
+			continue
+		}
+
+		matches := true
+		if i.matchFn != nil {
+			var err error
+			matches, err = i.matchFn(nextC.commit)
+			if err != nil {
+				return hash.Hash{}, nil, err
+			}
+		}
+		i.stats.visit(matches)
+		if matches {
+			i.sources[nextC.hash] = active
+			for _, head := range active {
+				i.emitted[head]++
+			}
+			active = i.withinQuota(active)
+		}
+
+		if len(active) > 0 {
+			parents, err := nextC.commit.ParentHashes(ctx)
+			if err != nil {
+				return hash.Hash{}, nil, err
+			}
+			if i.firstParent && len(parents) > 1 {
+				parents = parents[:1]
+			}
+			for _, parentID := range parents {
+				if err := i.q.AddPendingIfUnseen(ctx, nextC.ddb, parentID); err != nil {
+					return hash.Hash{}, nil, missingAncestor(err, nextC, matches, parentID)
+				}
+				i.reached[parentID] = unionHeads(i.reached[parentID], active)
+			}
+		}
+
+		if matches {
+			return nextC.hash, nextC.commit, nil
+		}
+	}
+
+	return hash.Hash{}, nil, io.EOF
+}
+
+// withinQuota returns the given heads that have not met their quota.
+func (i *SourceIterator) withinQuota(heads []int) []int {
+	if i.perSource <= 0 {
+		return heads
+	}
+	var within []int
+	for _, head := range heads {
+		if i.emitted[head] < i.perSource {
+			within = append(within, head)
+		}
+	}
+	return within
+}
+
+// unionHeads returns the sorted union of two sorted sets of head indexes.
+func unionHeads(a, b []int) []int {
+	union := make([]int, 0, len(a)+len(b))
+	j := 0
+	for _, head := range a {
+		for j < len(b) && b[j] < head {
+			union = append(union, b[j])
+			j++
+		}
+		if j < len(b) && b[j] == head {
+			j++
+		}
+		union = append(union, head)
+	}
+	return append(union, b[j:]...)
+}
+
+func (i *SourceIterator) walkStats() *WalkStats {
+	return i.stats
+}
+
+// Reset implements doltdb.CommitItr
+func (i *SourceIterator) Reset(ctx context.Context) error {
+	i.q = newQueue(i.stats)
+	i.reached = make(map[hash.Hash][]int)
+	i.sources = make(map[hash.Hash][]int)
+	i.emitted = make([]int, len(i.heads))
+	for idx, head := range i.heads {
+		if err := i.q.AddPendingIfUnseen(ctx, i.ddb, head); err != nil {
+			return err
+		}
+		// Heads are added in order, so the indexes of each commit stay sorted
+		i.reached[head] = append(i.reached[head], idx)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitwalk
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// WalkStats counts the work done by an iterator, so that slow walks can be diagnosed. Stats are counted by iterators
+// that are created with a context returned by WithWalkStats, and by any iterator that wraps one. Iterators that are
+// created without stats do not count anything.
+type WalkStats struct {
+	// Visited is the number of commits taken from the walk, including those that are excluded or skipped
+	Visited uint64
+	// Skipped is the number of visited commits that were rejected by the match function
+	Skipped uint64
+	// ChunkReads is the number of commits that were read from the database, each of which reads the commit's chunk
+	ChunkReads uint64
+}
+
+type walkStatsKey struct{}
+
+// WithWalkStats returns a context that makes the iterators created with it count their work in the given stats.
+func WithWalkStats(ctx context.Context, stats *WalkStats) context.Context {
+	return context.WithValue(ctx, walkStatsKey{}, stats)
+}
+
+// walkStatsFromContext returns the stats attached to the given context, or nil when there are none.
+func walkStatsFromContext(ctx context.Context) *WalkStats {
+	stats, _ := ctx.Value(walkStatsKey{}).(*WalkStats)
+	return stats
+}
+
+// statsIterator is an iterator that counts its work in stats, which wrapping iterators count their own work in.
+type statsIterator interface {
+	walkStats() *WalkStats
+}
+
+// walkStatsOf returns the stats of the given iterator, or nil when it does not count its work.
+func walkStatsOf(itr doltdb.CommitItr) *WalkStats {
+	if si, ok := itr.(statsIterator); ok {
+		return si.walkStats()
+	}
+	return nil
+}
+
+// visit counts a visited commit, which the match function rejected when |matches| is false.
+func (s *WalkStats) visit(matches bool) {
+	if s == nil {
+		return
+	}
+	s.Visited++
+	if !matches {
+		s.Skipped++
+	}
+}
+
+// read counts a commit that was read from the database.
+func (s *WalkStats) read() {
+	if s != nil {
+		s.ChunkReads++
+	}
+}
@@ -18,10 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
@@ -40,6 +42,7 @@ var ErrFailedToGetRemoteDb = errors.New("failed to get remote db")
 var ErrFailedToDeleteBackup = errors.New("failed to delete backup")
 var ErrFailedToGetBackupDb = errors.New("failed to get backup db")
 var ErrUnknownPushErr = errors.New("unknown push error")
+var ErrFetchRejectedRefs = errors.New("fetch rejected refs")
 
 type ProgStarter func(ctx context.Context) (*sync.WaitGroup, chan pull.PullProgress, chan pull.Stats)
 type ProgStopper func(cancel context.CancelFunc, wg *sync.WaitGroup, progChan chan pull.PullProgress, statsCh chan pull.Stats)
@@ -92,11 +95,16 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 
 func DoPush(ctx context.Context, rsr env.RepoStateReader, rsw env.RepoStateWriter, srcDB, destDB *doltdb.DoltDB, tempTableDir string, opts *env.PushOpts, progStarter ProgStarter, progStopper ProgStopper) error {
 	var err error
+	if err = branch_control.CanPushRef(ctx, opts.Remote.Name, opts.DestRef.GetPath()); err != nil {
+		return err
+	}
 
 	switch opts.SrcRef.GetType() {
 	case ref.BranchRefType:
 		if opts.SrcRef == ref.EmptyBranchRef {
 			err = deleteRemoteBranch(ctx, opts.DestRef, opts.RemoteRef, srcDB, destDB, opts.Remote)
+		} else if err = canCreateRemoteBranch(ctx, destDB, opts.DestRef); err != nil {
+			return err
 		} else {
 			err = PushToRemoteBranch(ctx, rsr, tempTableDir, opts.Mode, opts.SrcRef, opts.DestRef, opts.RemoteRef, srcDB, destDB, opts.Remote, progStarter, progStopper)
 		}
@@ -123,6 +131,17 @@ func DoPush(ctx context.Context, rsr env.RepoStateReader, rsw env.RepoStateWrite
 	return err
 }
 
+// canCreateRemoteBranch returns an error if the given branch does not exist on the remote, and the context's user is not
+// allowed to create a branch with its name. Pushing a new branch creates it on the remote, along with a remote tracking
+// branch in the local database, so it is subject to the same namespaces as creating a local branch.
+func canCreateRemoteBranch(ctx context.Context, destDB *doltdb.DoltDB, destRef ref.DoltRef) error {
+	exists, err := destDB.HasRef(ctx, destRef)
+	if err != nil || exists {
+		return err
+	}
+	return branch_control.CanCreateBranch(ctx, destRef.GetPath())
+}
+
 // PushTag pushes a commit tag and all underlying data from a local source database to a remote destination database.
 func PushTag(ctx context.Context, tempTableDir string, destRef ref.TagRef, srcDB, destDB *doltdb.DoltDB, tag *doltdb.Tag, progChan chan pull.PullProgress, statsCh chan pull.Stats) error {
 	var err error
@@ -359,15 +378,32 @@ func FetchRemoteBranch(
 	return srcDBCommit, nil
 }
 
+// CanCreateFetchDestRef returns an error if the given destination ref of a fetch is a local branch that does not exist
+// yet, and the context's user is not allowed to create a branch with its name. Refspecs may write to refs/heads, which
+// would otherwise create branches outside of the user's namespaces.
+func CanCreateFetchDestRef(ctx context.Context, ddb *doltdb.DoltDB, destRef ref.DoltRef) error {
+	if destRef.GetType() != ref.BranchRefType {
+		return nil
+	}
+	exists, err := ddb.HasRef(ctx, destRef)
+	if err != nil || exists {
+		return err
+	}
+	return branch_control.CanCreateBranch(ctx, destRef.GetPath())
+}
+
 // FetchRefSpecs is the common SQL and CLI entrypoint for fetching branches, tags, and heads from a remote.
 // This function takes dbData which is a env.DbData object for handling repoState read and write, and srcDB is
-// a remote *doltdb.DoltDB object that is used to fetch remote branches from.
+// a remote *doltdb.DoltDB object that is used to fetch remote branches from. Destination refs that would create a
+// branch outside of the user's namespaces are skipped, while every other ref is still fetched, and an
+// ErrFetchRejectedRefs error listing the skipped refs is returned once the fetch completes.
 func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, refSpecs []ref.RemoteRefSpec, remote env.Remote, mode ref.UpdateMode, progStarter ProgStarter, progStopper ProgStopper) error {
 	branchRefs, err := srcDB.GetHeadRefs(ctx)
 	if err != nil {
 		return env.ErrFailedToReadDb
 	}
 
+	var rejected []string
 	for _, rs := range refSpecs {
 		rsSeen := false
 
@@ -376,6 +412,18 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 
 			if remoteTrackRef != nil {
 				rsSeen = true
+				if err = branch_control.CanFetchRef(ctx, remote.Name, branchRef.GetPath()); branch_control.ErrCannotFetchRef.Is(err) {
+					rejected = append(rejected, err.Error())
+					continue
+				} else if err != nil {
+					return err
+				}
+				if err = CanCreateFetchDestRef(ctx, dbData.Ddb, remoteTrackRef); branch_control.ErrCannotCreateBranch.Is(err) {
+					rejected = append(rejected, err.Error())
+					continue
+				} else if err != nil {
+					return err
+				}
 				tmpDir, err := dbData.Rsw.TempTableFilesDir()
 				if err != nil {
 					return err
@@ -429,6 +477,9 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 		return err
 	}
 
+	if len(rejected) > 0 {
+		return fmt.Errorf("%w: %s", ErrFetchRejectedRefs, strings.Join(rejected, "; "))
+	}
 	return nil
 }
 
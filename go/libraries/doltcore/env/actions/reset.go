@@ -114,7 +114,12 @@ func ResetHard(ctx context.Context, dEnv *env.DoltEnv, cSpecStr string, roots do
 	}
 
 	if newHead != nil {
-		err = dEnv.DoltDB.SetHeadToCommit(ctx, dEnv.RepoStateReader().CWBHeadRef(), newHead)
+		// the head moved from is recorded, so that the reset can be undone with the reflog
+		headRef := dEnv.RepoStateReader().CWBHeadRef()
+		if err = dEnv.DoltDB.RecordReflogHead(ctx, headRef); err != nil {
+			return err
+		}
+		err = dEnv.DoltDB.SetHeadToCommit(ctx, headRef, newHead)
 		if err != nil {
 			return err
 		}
@@ -180,7 +185,10 @@ func ResetSoftToRef(ctx context.Context, dbData env.DbData, cSpecStr string) err
 		return err
 	}
 
-	// Update the head to this commit
+	// Update the head to this commit, recording the head moved from so that the reset can be undone with the reflog
+	if err = dbData.Ddb.RecordReflogHead(ctx, dbData.Rsr.CWBHeadRef()); err != nil {
+		return err
+	}
 	if err = dbData.Ddb.SetHeadToCommit(ctx, dbData.Rsr.CWBHeadRef(), newHead); err != nil {
 		return err
 	}
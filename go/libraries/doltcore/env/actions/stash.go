@@ -0,0 +1,139 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+var ErrNoLocalChanges = errors.New("no local changes to save")
+var ErrNoStashEntries = errors.New("no stash entries found")
+var ErrInvalidStashSpec = errors.New("invalid stash; stashes are named stash@{n}, or n, where n is the position of the stash in the list of stashes")
+
+// StashProps are the properties of a new stash.
+type StashProps struct {
+	Name    string
+	Email   string
+	Message string
+}
+
+// StashName returns the name of the stash at the given position in the list of stashes, e.g. stash@{0} for the most
+// recent stash.
+func StashName(idx int) string {
+	return fmt.Sprintf("stash@{%d}", idx)
+}
+
+// StashChanges puts aside the uncommitted changes of the given roots, which belong to the given branch, and returns
+// the roots with those changes removed. Staged changes are stashed along with unstaged changes and new tables. Without
+// a message, the stash is described by the head commit that the changes were made on.
+func StashChanges(ctx context.Context, ddb *doltdb.DoltDB, roots doltdb.Roots, headCommit *doltdb.Commit, branch string, props StashProps) (doltdb.Roots, error) {
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	workingHash, err := roots.Working.HashOf()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	if headHash == workingHash && headHash == stagedHash {
+		return doltdb.Roots{}, ErrNoLocalChanges
+	}
+
+	desc := "On " + branch + ": " + props.Message
+	if len(props.Message) == 0 {
+		headMeta, err := headCommit.GetCommitMeta(ctx)
+		if err != nil {
+			return doltdb.Roots{}, err
+		}
+		commitHash, err := headCommit.HashOf()
+		if err != nil {
+			return doltdb.Roots{}, err
+		}
+		desc = fmt.Sprintf("WIP on %s: %s %s", branch, commitHash.String(), headMeta.Description)
+	}
+	meta, err := datas.NewCommitMeta(props.Name, props.Email, desc)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	_, valHash, err := ddb.WriteRootValue(ctx, roots.Working)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	stashCommit, err := ddb.CommitDanglingWithParentCommits(ctx, valHash, []*doltdb.Commit{headCommit}, meta)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	if _, err = ddb.NewStashAtCommit(ctx, branch, stashCommit); err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	roots.Working = roots.Head
+	roots.Staged = roots.Head
+	return roots, nil
+}
+
+// ResolveStash returns the stash named by the given spec, which is either stash@{n} or n, where n is the position of
+// the stash in the list of stashes, most recent first. An empty spec names the most recent stash.
+func ResolveStash(ctx context.Context, ddb *doltdb.DoltDB, spec string) (doltdb.Stash, error) {
+	idx := 0
+	if len(spec) > 0 {
+		if strings.HasPrefix(spec, "stash@{") && strings.HasSuffix(spec, "}") {
+			spec = spec[len("stash@{") : len(spec)-1]
+		}
+		var err error
+		idx, err = strconv.Atoi(spec)
+		if err != nil || idx < 0 {
+			return doltdb.Stash{}, ErrInvalidStashSpec
+		}
+	}
+
+	stashes, err := ddb.GetStashes(ctx)
+	if err != nil {
+		return doltdb.Stash{}, err
+	}
+	if len(stashes) == 0 {
+		return doltdb.Stash{}, ErrNoStashEntries
+	}
+	if idx >= len(stashes) {
+		return doltdb.Stash{}, fmt.Errorf("%w: %s", doltdb.ErrStashNotFound, StashName(idx))
+	}
+	return stashes[idx], nil
+}
+
+// ClearStashes deletes every stash.
+func ClearStashes(ctx context.Context, ddb *doltdb.DoltDB) error {
+	stashes, err := ddb.GetStashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, stash := range stashes {
+		if err = ddb.DeleteStash(ctx, stash.Ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
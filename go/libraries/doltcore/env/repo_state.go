@@ -62,6 +62,8 @@ type RepoState struct {
 	Remotes  map[string]Remote       `json:"remotes"`
 	Backups  map[string]Remote       `json:"backups"`
 	Branches map[string]BranchConfig `json:"branches"`
+	// BackupSchedules maps the names of backups to the cron expressions sql-server syncs them on
+	BackupSchedules map[string]string `json:"backup_schedules,omitempty"`
 	// |staged|, |working|, and |merge| are legacy fields left over from when Dolt repos stored this info in the repo
 	// state file, not in the DB directly. They're still here so that we can migrate existing repositories forward to the
 	// new storage format, but they should be used only for this purpose and are no longer written.
@@ -73,25 +75,27 @@ type RepoState struct {
 // repoStateLegacy only exists to unmarshall legacy repo state files, since the JSON marshaller can't work with
 // unexported fields
 type repoStateLegacy struct {
-	Head     ref.MarshalableRef      `json:"head"`
-	Remotes  map[string]Remote       `json:"remotes"`
-	Backups  map[string]Remote       `json:"backups"`
-	Branches map[string]BranchConfig `json:"branches"`
-	Staged   string                  `json:"staged,omitempty"`
-	Working  string                  `json:"working,omitempty"`
-	Merge    *mergeState             `json:"merge,omitempty"`
+	Head            ref.MarshalableRef      `json:"head"`
+	Remotes         map[string]Remote       `json:"remotes"`
+	Backups         map[string]Remote       `json:"backups"`
+	Branches        map[string]BranchConfig `json:"branches"`
+	Staged          string                  `json:"staged,omitempty"`
+	Working         string                  `json:"working,omitempty"`
+	Merge           *mergeState             `json:"merge,omitempty"`
+	BackupSchedules map[string]string       `json:"backup_schedules,omitempty"`
 }
 
 // repoStateLegacyFromRepoState creates a new repoStateLegacy from a RepoState file. Only for testing.
 func repoStateLegacyFromRepoState(rs *RepoState) *repoStateLegacy {
 	return &repoStateLegacy{
-		Head:     rs.Head,
-		Remotes:  rs.Remotes,
-		Backups:  rs.Backups,
-		Branches: rs.Branches,
-		Staged:   rs.staged,
-		Working:  rs.working,
-		Merge:    rs.merge,
+		Head:            rs.Head,
+		Remotes:         rs.Remotes,
+		Backups:         rs.Backups,
+		Branches:        rs.Branches,
+		Staged:          rs.staged,
+		Working:         rs.working,
+		Merge:           rs.merge,
+		BackupSchedules: rs.BackupSchedules,
 	}
 }
 
@@ -102,13 +106,14 @@ type mergeState struct {
 
 func (rs *repoStateLegacy) toRepoState() *RepoState {
 	return &RepoState{
-		Head:     rs.Head,
-		Remotes:  rs.Remotes,
-		Backups:  rs.Backups,
-		Branches: rs.Branches,
-		staged:   rs.Staged,
-		working:  rs.Working,
-		merge:    rs.Merge,
+		Head:            rs.Head,
+		Remotes:         rs.Remotes,
+		Backups:         rs.Backups,
+		Branches:        rs.Branches,
+		staged:          rs.Staged,
+		working:         rs.Working,
+		merge:           rs.Merge,
+		BackupSchedules: rs.BackupSchedules,
 	}
 }
 
@@ -216,4 +221,17 @@ func (rs *RepoState) AddBackup(r Remote) {
 
 func (rs *RepoState) RemoveBackup(r Remote) {
 	delete(rs.Backups, r.Name)
+	delete(rs.BackupSchedules, r.Name)
+}
+
+// SetBackupSchedule sets the cron expression that the backup named is synced on. An empty schedule removes it.
+func (rs *RepoState) SetBackupSchedule(name, schedule string) {
+	if schedule == "" {
+		delete(rs.BackupSchedules, name)
+		return
+	}
+	if rs.BackupSchedules == nil {
+		rs.BackupSchedules = make(map[string]string)
+	}
+	rs.BackupSchedules[name] = schedule
 }
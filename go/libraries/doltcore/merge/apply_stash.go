@@ -0,0 +1,66 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+var ErrStashConflicts = errors.New("applying the stash would create conflicts; commit or stash your changes and try again")
+var ErrStashConstraintViolations = errors.New("applying the stash would create constraint violations; commit or stash your changes and try again")
+
+// ApplyStash applies the changes of the given stash to the given root through a three-way merge with the following
+// characteristics:
+//
+// Base:   the commit that the stash's changes were made on
+// Ours:   root
+// Theirs: the stash
+//
+// Currently, we error on conflicts or constraint violations generated by the merge, leaving the stash in place.
+func ApplyStash(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, stash doltdb.Stash, opts editor.Options) (*doltdb.RootValue, error) {
+	stashRoot, err := stash.Commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parentCM, err := ddb.ResolveParent(ctx, stash.Commit, 0)
+	if err != nil {
+		return nil, err
+	}
+	parentRoot, err := parentCM.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	root, _, err = MergeRoots(ctx, root, stashRoot, parentRoot, stash.Commit, parentCM, opts, MergeOpts{IsCherryPick: false})
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := root.HasConflicts(ctx); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, ErrStashConflicts
+	}
+	if ok, err := root.HasConstraintViolations(ctx); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, ErrStashConstraintViolations
+	}
+
+	return root, nil
+}
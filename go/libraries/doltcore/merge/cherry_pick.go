@@ -0,0 +1,59 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+var ErrCherryPickMergeCommit = errors.New("cherry-picking a merge commit is not supported.")
+var ErrCherryPickNoParents = errors.New("cherry-picking a commit without parents is not supported.")
+
+// CherryPick applies the changes introduced by the given commit to the given root through a three-way merge with the
+// following characteristics:
+//
+// Base:   the parent of the commit
+// Ours:   root
+// Theirs: the commit
+//
+// Conflicts and constraint violations generated by the merge are recorded in the returned root, as they are for any
+// merge, and are counted by the returned stats. Merge commits and commits without parents can't be cherry-picked.
+func CherryPick(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, commit *doltdb.Commit, opts editor.Options) (*doltdb.RootValue, map[string]*MergeStats, error) {
+	if len(commit.DatasParents()) > 1 {
+		return nil, nil, ErrCherryPickMergeCommit
+	}
+	if len(commit.DatasParents()) == 0 {
+		return nil, nil, ErrCherryPickNoParents
+	}
+
+	cherryRoot, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentCM, err := ddb.ResolveParent(ctx, commit, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentRoot, err := parentCM.GetRootValue(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return MergeRoots(ctx, root, cherryRoot, parentRoot, commit, parentCM, opts, MergeOpts{IsCherryPick: true})
+}
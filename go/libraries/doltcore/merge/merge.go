@@ -42,7 +42,7 @@ var ErrConflictsIncompatible = errors.New("the existing conflicts are of a diffe
 
 var ErrMultipleViolationsForRow = errors.New("multiple violations for row not supported")
 
-func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options) (*doltdb.RootValue, map[string]*MergeStats, error) {
+func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options, mergeOpts MergeOpts) (*doltdb.RootValue, map[string]*MergeStats, error) {
 	ancCommit, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 	if err != nil {
 		return nil, nil, err
@@ -63,7 +63,7 @@ func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts
 		return nil, nil, err
 	}
 
-	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, MergeOpts{IsCherryPick: false})
+	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mergeOpts)
 }
 
 // MergeRoots three-way merges |ourRoot|, |theirRoot|, and |ancRoot| and returns
@@ -128,6 +128,16 @@ func MergeRoots(
 	}
 
 	for _, tblName := range tblNames {
+		if tblName == doltdb.StatisticsTableName {
+			// Statistics are derived from the other tables and are recomputed when the merge is committed, so rather
+			// than merging them row by row, ours are kept, or theirs if only they have any.
+			mergedRoot, err = mergeStatisticsTable(ctx, mergedRoot, theirRoot, ancRoot, tblToStats)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
 		mergedTable, stats, err := merger.MergeTable(ctx, tblName, opts, mergeOpts)
 		if err != nil {
 			return nil, nil, err
@@ -223,6 +233,30 @@ func MergeRoots(
 
 // mergeCVsWithStash merges the table constraint violations in |stash| with |root|.
 // Returns an updated root with all the merged CVs.
+// mergeStatisticsTable merges the dolt_statistics table into |mergedRoot|, which starts out as our root. Our table is
+// kept if we have one. Otherwise theirs is taken, unless we dropped the table since the ancestor.
+func mergeStatisticsTable(ctx context.Context, mergedRoot, theirRoot, ancRoot *doltdb.RootValue, tblToStats map[string]*MergeStats) (*doltdb.RootValue, error) {
+	ourHasTable, err := mergedRoot.HasTable(ctx, doltdb.StatisticsTableName)
+	if err != nil {
+		return nil, err
+	}
+	ancHasTable, err := ancRoot.HasTable(ctx, doltdb.StatisticsTableName)
+	if err != nil {
+		return nil, err
+	}
+	if ourHasTable || ancHasTable {
+		tblToStats[doltdb.StatisticsTableName] = &MergeStats{Operation: TableUnmodified}
+		return mergedRoot, nil
+	}
+
+	theirTbl, ok, err := theirRoot.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil || !ok {
+		return mergedRoot, err
+	}
+	tblToStats[doltdb.StatisticsTableName] = &MergeStats{Operation: TableAdded}
+	return mergedRoot.PutTable(ctx, doltdb.StatisticsTableName, theirTbl)
+}
+
 func mergeCVsWithStash(ctx context.Context, root *doltdb.RootValue, stash *violationStash) (*doltdb.RootValue, error) {
 	updatedRoot := root
 	for name, stashed := range stash.Stash {
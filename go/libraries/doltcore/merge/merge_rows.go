@@ -34,6 +34,9 @@ import (
 
 type MergeOpts struct {
 	IsCherryPick bool
+	// KeepSchemaConflicts leaves tables whose schemas cannot be merged as they are in our root, reporting their
+	// conflicts in their MergeStats, rather than failing the merge.
+	KeepSchemaConflicts bool
 }
 
 type TableMerger struct {
@@ -125,7 +128,9 @@ func (rm *RootMerger) MergeTable(ctx context.Context, tblName string, opts edito
 		return nil, nil, err
 	}
 	if schConflicts.Count() != 0 {
-		// error on schema conflicts for now
+		if mergeOpts.KeepSchemaConflicts {
+			return tm.leftTbl, &MergeStats{Operation: TableUnmodified, SchemaConflicts: schConflicts.Count()}, nil
+		}
 		return nil, nil, fmt.Errorf("%w.\n%s", ErrSchemaConflict, schConflicts.AsError().Error())
 	}
 
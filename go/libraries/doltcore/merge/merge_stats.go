@@ -30,4 +30,5 @@ type MergeStats struct {
 	Modifications        int
 	Conflicts            int
 	ConstraintViolations int
+	SchemaConflicts      int
 }
@@ -16,12 +16,19 @@ package merge
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 )
 
+var ErrRevertMergeNoMainline = errors.New("reverting a merge commit requires the parent whose changes are kept to be given with -m")
+var ErrRevertMainlineNotMerge = errors.New("a parent was given with -m, but a commit being reverted is not a merge commit")
+
 // Revert is a convenience function for a three-way merge. In particular, given some root and a collection of commits
 // that are all parents of the root value, this applies a three-way merge with the following characteristics (assuming
 // a commit is HEAD~1):
@@ -31,18 +38,13 @@ import (
 // Theirs: HEAD~2
 //
 // The root is updated with the merged result, and this process is repeated for each commit given, in the order given.
-// Currently, we error on conflicts or constraint violations generated by the merge.
-func Revert(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, headCommit *doltdb.Commit, commits []*doltdb.Commit, opts editor.Options) (*doltdb.RootValue, string, error) {
+// Merge commits are reverted relative to the parent numbered |mainline|, counting from 1, which must be 0 when no merge
+// commits are given. Currently, we error on conflicts or constraint violations generated by the merge.
+func Revert(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, headCommit *doltdb.Commit, commits []*doltdb.Commit, mainline int, opts editor.Options) (*doltdb.RootValue, string, error) {
 	revertMessage := "Revert"
 
-	for _, cm := range commits {
-		if len(cm.DatasParents()) == 0 {
-			h, err := cm.HashOf()
-			if err != nil {
-				return nil, "", err
-			}
-			return nil, "", fmt.Errorf("cannot revert commit with no parents (%s)", h.String())
-		}
+	if err := CheckRevertCommits(commits, mainline); err != nil {
+		return nil, "", err
 	}
 
 	for i, baseCommit := range commits {
@@ -59,7 +61,11 @@ func Revert(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, hea
 		}
 		revertMessage = fmt.Sprintf(`%s "%s"`, revertMessage, baseMeta.Description)
 
-		parentCM, err := ddb.ResolveParent(ctx, baseCommit, 0)
+		parentIdx := 0
+		if mainline > 0 {
+			parentIdx = mainline - 1
+		}
+		parentCM, err := ddb.ResolveParent(ctx, baseCommit, parentIdx)
 		if err != nil {
 			return nil, "", err
 		}
@@ -86,3 +92,87 @@ func Revert(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, hea
 
 	return root, revertMessage, nil
 }
+
+// CheckRevertCommits returns an error if any of |commits| can't be reverted relative to the parent numbered
+// |mainline|, so that reverts made a commit at a time can be checked before any commit is made.
+func CheckRevertCommits(commits []*doltdb.Commit, mainline int) error {
+	for _, cm := range commits {
+		numParents := len(cm.DatasParents())
+		if numParents == 0 {
+			h, err := cm.HashOf()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("cannot revert commit with no parents (%s)", h.String())
+		}
+		if numParents > 1 && mainline == 0 {
+			return ErrRevertMergeNoMainline
+		}
+		if numParents == 1 && mainline != 0 {
+			return ErrRevertMainlineNotMerge
+		}
+		if mainline < 0 || mainline > numParents {
+			h, err := cm.HashOf()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("commit %s does not have parent %d", h.String(), mainline)
+		}
+	}
+	return nil
+}
+
+// ResolveRevertCommits resolves the commits named by |specs| in the order they are reverted in. Ranges of the form A..B
+// are expanded to the commits reachable from B that aren't reachable from A, newest first, so that changes are reverted
+// before the changes they were made on top of.
+func ResolveRevertCommits(ctx context.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, specs []string) ([]*doltdb.Commit, error) {
+	var commits []*doltdb.Commit
+	for _, spec := range specs {
+		if strings.Contains(spec, "...") {
+			return nil, fmt.Errorf("symmetric ranges are not supported by revert: %s", spec)
+		}
+		excludedSpec, includedSpec, ok := strings.Cut(spec, "..")
+		if !ok {
+			cm, err := resolveRevertCommit(ctx, ddb, headRef, spec)
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, cm)
+			continue
+		}
+
+		excluded, err := resolveRevertCommit(ctx, ddb, headRef, excludedSpec)
+		if err != nil {
+			return nil, err
+		}
+		included, err := resolveRevertCommit(ctx, ddb, headRef, includedSpec)
+		if err != nil {
+			return nil, err
+		}
+		excludedHash, err := excluded.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		includedHash, err := included.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		rng, err := commitwalk.GetDotDotRevisions(ctx, ddb, includedHash, ddb, excludedHash, -1)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, rng...)
+	}
+	return commits, nil
+}
+
+func resolveRevertCommit(ctx context.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, spec string) (*doltdb.Commit, error) {
+	if len(spec) == 0 {
+		spec = "HEAD"
+	}
+	cs, err := doltdb.NewCommitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return ddb.Resolve(ctx, cs, headRef)
+}
@@ -76,7 +76,7 @@ func traverseRefHistory(ctx context.Context, r ref.DoltRef, old, new *doltdb.Dol
 	case ref.TagRefType:
 		return traverseTagHistory(ctx, r.(ref.TagRef), old, new, prog)
 
-	case ref.RemoteRefType:
+	case ref.RemoteRefType, ref.StashRefType:
 		return traverseBranchHistory(ctx, r, old, new, prog)
 
 	case ref.WorkspaceRefType, ref.InternalRefType:
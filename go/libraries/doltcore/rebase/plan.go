@@ -0,0 +1,338 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// The actions of the steps of a rebase plan.
+const (
+	// ActionPick applies the changes of the commit as a new commit with the same message.
+	ActionPick = "pick"
+	// ActionReword applies the changes of the commit as a new commit with the message of the step.
+	ActionReword = "reword"
+	// ActionSquash applies the changes of the commit to the commit of the previous step, appending its message.
+	ActionSquash = "squash"
+	// ActionDrop leaves the commit out of the rebased branch.
+	ActionDrop = "drop"
+)
+
+var ErrSquashFirstStep = errors.New("the first commit of a rebase plan that isn't dropped can't be squashed, as there's no previous commit to squash it into")
+var ErrRebaseConflicts = goerrors.NewKind("rebasing commit %s resulted in conflicts; drop or squash the commits that conflict in the rebase plan, or abort the rebase")
+var ErrRebaseConstraintViolations = goerrors.NewKind("rebasing commit %s resulted in constraint violations; drop or squash the commits that cause them in the rebase plan, or abort the rebase")
+
+// Schema is the schema of the dolt_rebase_plan table.
+var Schema = schema.MustSchemaFromCols(schema.NewColCollection(
+	schema.NewColumn(doltdb.RebasePlanOrderCol, schema.DoltRebasePlanOrderTag, types.UintKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.RebasePlanActionCol, schema.DoltRebasePlanActionTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.RebasePlanCommitHashCol, schema.DoltRebasePlanCommitHashTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.RebasePlanCommitMessageCol, schema.DoltRebasePlanCommitMessageTag, types.StringKind, false, schema.NotNullConstraint{}),
+))
+
+// Step is a step of a rebase plan, which says what is done with one of the commits being rebased.
+type Step struct {
+	Order         uint64
+	Action        string
+	CommitHash    string
+	CommitMessage string
+}
+
+// NewPlan returns a plan that picks each of the commits given, in the order given. Merge commits are left out of the
+// plan, as rebasing flattens the history of the branch.
+func NewPlan(ctx context.Context, commits []*doltdb.Commit) ([]Step, error) {
+	var plan []Step
+	for _, cm := range commits {
+		if cm.NumParents() > 1 {
+			continue
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, Step{
+			Order:         uint64(len(plan) + 1),
+			Action:        ActionPick,
+			CommitHash:    h.String(),
+			CommitMessage: meta.Description,
+		})
+	}
+	return plan, nil
+}
+
+// LoadPlan returns the steps of the rebase plan persisted in |root| in the order they're performed in, and whether
+// there is a plan.
+func LoadPlan(ctx context.Context, root *doltdb.RootValue) ([]Step, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.RebasePlanTableName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !schema.SchemasAreEqual(sch, Schema) {
+		return nil, false, fmt.Errorf("the schema of the %s table has been changed", doltdb.RebasePlanTableName)
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	iter, err := table.NewTableIterator(ctx, sch, rows, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	defer iter.Close(ctx)
+
+	var plan []Step
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		plan = append(plan, Step{
+			Order:         r[0].(uint64),
+			Action:        strings.ToLower(strings.TrimSpace(r[1].(string))),
+			CommitHash:    strings.TrimSpace(r[2].(string)),
+			CommitMessage: r[3].(string),
+		})
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].Order < plan[j].Order
+	})
+	return plan, true, nil
+}
+
+// WritePlan replaces the dolt_rebase_plan table of |root| with one holding |plan|, and returns the updated root.
+func WritePlan(ctx context.Context, root *doltdb.RootValue, plan []Step) (*doltdb.RootValue, error) {
+	rows := make([]sql.Row, len(plan))
+	for i, step := range plan {
+		rows[i] = sql.Row{step.Order, step.Action, step.CommitHash, step.CommitMessage}
+	}
+
+	var idx durable.Index
+	var err error
+	if types.IsFormat_DOLT(root.VRW().Format()) {
+		idx, err = prollyIndexFromRows(ctx, root, rows)
+	} else {
+		idx, err = nomsIndexFromRows(ctx, root, rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := doltdb.NewTable(ctx, root.VRW(), root.NodeStore(), Schema, idx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return root.PutTable(ctx, doltdb.RebasePlanTableName, tbl)
+}
+
+// Execute performs the steps of |plan| on top of |onto|, and returns the last commit made, which is |onto| when the
+// plan makes no commits. Commits keep the author and date of the commit they were made from, or of the first commit
+// squashed into them, and commits that wouldn't change anything are left out. The commits are made without moving any
+// branch, so that nothing changes when the plan can't be performed, such as when a step results in conflicts.
+func Execute(ctx context.Context, ddb *doltdb.DoltDB, onto *doltdb.Commit, plan []Step, opts editor.Options) (*doltdb.Commit, error) {
+	head := onto
+	root, err := onto.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the commit being built, which later steps may squash commits into
+	var pending *datas.CommitMeta
+	commitPending := func() error {
+		if pending == nil {
+			return nil
+		}
+		meta := pending
+		pending = nil
+
+		headRoot, err := head.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+		if changed, err := rootsDiffer(root, headRoot); err != nil || !changed {
+			return err
+		}
+		r, h, err := ddb.WriteRootValue(ctx, root)
+		if err != nil {
+			return err
+		}
+		root = r
+		head, err = ddb.CommitDanglingWithParentCommits(ctx, h, []*doltdb.Commit{head}, meta)
+		return err
+	}
+
+	for _, step := range plan {
+		switch step.Action {
+		case ActionDrop:
+			continue
+		case ActionPick, ActionReword, ActionSquash:
+		default:
+			return nil, fmt.Errorf("invalid action '%s' for commit %s in the rebase plan; valid actions are %s, %s, %s, and %s",
+				step.Action, step.CommitHash, ActionPick, ActionReword, ActionSquash, ActionDrop)
+		}
+		if step.Action == ActionSquash && pending == nil {
+			return nil, ErrSquashFirstStep
+		}
+		if step.Action != ActionSquash {
+			if err = commitPending(); err != nil {
+				return nil, err
+			}
+		}
+
+		cs, err := doltdb.NewCommitSpec(step.CommitHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit hash '%s' in the rebase plan: %w", step.CommitHash, err)
+		}
+		cm, err := ddb.Resolve(ctx, cs, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find commit %s of the rebase plan: %w", step.CommitHash, err)
+		}
+		cmMeta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		root, _, err = merge.CherryPick(ctx, ddb, root, cm, opts)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := root.HasConflicts(ctx); err != nil {
+			return nil, err
+		} else if ok {
+			return nil, ErrRebaseConflicts.New(step.CommitHash)
+		}
+		if ok, err := root.HasConstraintViolations(ctx); err != nil {
+			return nil, err
+		} else if ok {
+			return nil, ErrRebaseConstraintViolations.New(step.CommitHash)
+		}
+
+		if step.Action == ActionSquash {
+			pending.Description = pending.Description + "\n\n" + cmMeta.Description
+			continue
+		}
+
+		msg := cmMeta.Description
+		if step.Action == ActionReword {
+			msg = step.CommitMessage
+		}
+		pending, err = datas.NewCommitMetaWithUserTS(cmMeta.Name, cmMeta.Email, msg, cmMeta.Time())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = commitPending(); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+func rootsDiffer(left, right *doltdb.RootValue) (bool, error) {
+	lh, err := left.HashOf()
+	if err != nil {
+		return false, err
+	}
+	rh, err := right.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return !lh.Equal(rh), nil
+}
+
+func prollyIndexFromRows(ctx context.Context, root *doltdb.RootValue, rows []sql.Row) (durable.Index, error) {
+	idx, err := durable.NewEmptyIndex(ctx, root.VRW(), root.NodeStore(), Schema)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(idx)
+	mut := m.Mutate()
+
+	numPks := Schema.GetPKCols().Size()
+	kb := val.NewTupleBuilder(Schema.GetKeyDescriptor())
+	vb := val.NewTupleBuilder(Schema.GetValueDescriptor())
+	for _, r := range rows {
+		for i := 0; i < numPks; i++ {
+			if err := index.PutField(ctx, m.NodeStore(), kb, i, r[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := numPks; i < len(r); i++ {
+			if err := index.PutField(ctx, m.NodeStore(), vb, i-numPks, r[i]); err != nil {
+				return nil, err
+			}
+		}
+		if err := mut.Put(ctx, kb.Build(m.Pool()), vb.Build(m.Pool())); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err = mut.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return durable.IndexFromProllyMap(m), nil
+}
+
+func nomsIndexFromRows(ctx context.Context, root *doltdb.RootValue, rows []sql.Row) (durable.Index, error) {
+	m, err := types.NewMap(ctx, root.VRW())
+	if err != nil {
+		return nil, err
+	}
+	me := m.Edit()
+	for _, r := range rows {
+		dRow, err := sqlutil.SqlRowToDoltRow(ctx, root.VRW(), r, Schema)
+		if err != nil {
+			return nil, err
+		}
+		me.Set(dRow.NomsMapKey(Schema), dRow.NomsMapValue(Schema))
+	}
+
+	m, err = me.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return durable.IndexFromNomsMap(m, root.VRW(), root.NodeStore()), nil
+}
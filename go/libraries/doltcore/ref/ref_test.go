@@ -46,6 +46,10 @@ func TestJsonMarshalAndUnmarshal(t *testing.T) {
 			NewWorkspaceRef("newworkspace"),
 			`{"test":"refs/workspaces/newworkspace"}`,
 		},
+		{
+			NewStashRef(3, "feature/branch"),
+			`{"test":"refs/stashes/3/feature/branch"}`,
+		},
 	}
 
 	for _, test := range tests {
@@ -160,6 +164,21 @@ func TestEqualsStr(t *testing.T) {
 			"refs/remotes/origin/newworkspace",
 			false,
 		},
+		{
+			NewStashRef(3, "main"),
+			"refs/stashes/3/main",
+			true,
+		},
+		{
+			NewStashRef(3, "main"),
+			"refs/stashes/4/main",
+			false,
+		},
+		{
+			NewStashRef(3, "main"),
+			"refs/stashes/main",
+			false,
+		},
 	}
 
 	for _, test := range tests {
@@ -170,3 +189,19 @@ func TestEqualsStr(t *testing.T) {
 		}
 	}
 }
+
+func TestNewStashRefFromPathStr(t *testing.T) {
+	sr, err := NewStashRefFromPathStr("refs/stashes/12/feature/branch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr.ID() != 12 || sr.Branch() != "feature/branch" {
+		t.Error("unexpected stash ref", sr.ID(), sr.Branch())
+	}
+
+	for _, path := range []string{"12", "12/", "main", "main/12", "-1/main"} {
+		if _, err = NewStashRefFromPathStr(path); err != ErrInvalidStashRef {
+			t.Error("expected an invalid stash ref for", path)
+		}
+	}
+}
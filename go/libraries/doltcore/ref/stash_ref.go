@@ -0,0 +1,93 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ref
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidStashRef is returned when a stash ref's path is not in the format id/branch
+var ErrInvalidStashRef = errors.New("invalid stash ref")
+
+// StashRef is a reference to a stash, which is a commit of a working set's changes. The path of a stash ref is made up
+// of an increasing id, which orders the stashes, followed by the branch that the changes were stashed from, e.g.
+// refs/stashes/3/main.
+type StashRef struct {
+	id     uint64
+	branch string
+}
+
+var _ DoltRef = StashRef{}
+
+// NewStashRef creates a reference to the stash with the given id, made from the changes of the given branch.
+func NewStashRef(id uint64, branch string) StashRef {
+	return StashRef{id: id, branch: branch}
+}
+
+// NewStashRefFromPathStr creates a reference to a stash from a stash path or a stash ref e.g. 3/main, or
+// refs/stashes/3/main
+func NewStashRefFromPathStr(path string) (StashRef, error) {
+	if IsRef(path) {
+		prefix := PrefixForType(StashRefType)
+		if strings.HasPrefix(path, prefix) {
+			path = path[len(prefix):]
+		} else {
+			panic(path + " is a ref that is not of type " + prefix)
+		}
+	}
+
+	idStr, branch, ok := strings.Cut(path, "/")
+	if !ok || len(branch) == 0 {
+		return StashRef{}, ErrInvalidStashRef
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return StashRef{}, ErrInvalidStashRef
+	}
+
+	return StashRef{id: id, branch: branch}, nil
+}
+
+// GetType will return StashRefType
+func (sr StashRef) GetType() RefType {
+	return StashRefType
+}
+
+// GetPath returns the id of the stash followed by its branch
+func (sr StashRef) GetPath() string {
+	return strconv.FormatUint(sr.id, 10) + "/" + sr.branch
+}
+
+// ID returns the id of the stash. Stashes with larger ids were created more recently.
+func (sr StashRef) ID() uint64 {
+	return sr.id
+}
+
+// Branch returns the name of the branch that the stash's changes were made on
+func (sr StashRef) Branch() string {
+	return sr.branch
+}
+
+// String returns the fully qualified reference name e.g. refs/stashes/3/main
+func (sr StashRef) String() string {
+	return String(sr)
+}
+
+// MarshalJSON serializes a StashRef to JSON.
+func (sr StashRef) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(sr)
+}
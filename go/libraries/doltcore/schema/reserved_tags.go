@@ -108,3 +108,26 @@ const (
 	DoltConflictsOurCardinalityTag
 	DoltConflictsTheirCardinalityTag
 )
+
+// Tags for the dolt_statistics table
+const (
+	DoltStatisticsTableNameTag = iota + SystemTableReservedMin + uint64(8000)
+	DoltStatisticsColumnNameTag
+	DoltStatisticsDataHashTag
+	DoltStatisticsRowCountTag
+	DoltStatisticsDistinctCountTag
+	DoltStatisticsNullCountTag
+	DoltStatisticsMeanTag
+	DoltStatisticsMinTag
+	DoltStatisticsMaxTag
+	DoltStatisticsBucketsTag
+	DoltStatisticsCreatedAtTag
+)
+
+// Tags for the dolt_rebase_plan table
+const (
+	DoltRebasePlanOrderTag = iota + SystemTableReservedMin + uint64(9000)
+	DoltRebasePlanActionTag
+	DoltRebasePlanCommitHashTag
+	DoltRebasePlanCommitMessageTag
+)
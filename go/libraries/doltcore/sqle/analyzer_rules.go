@@ -0,0 +1,369 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+// Dolt's rule ids begin well after those of the engine, so that they never collide.
+const (
+	pruneTableFunctionColumnsId analyzer.RuleId = iota + 1000
+	checkDefaultBranchChangesId
+	pushdownTableFunctionLimitsId
+	execSavedQueriesId
+)
+
+// ExecSavedProcedureName is the name of the procedure that runs a query of the query catalog, which is replaced by the
+// saved query as the statement is analyzed.
+const ExecSavedProcedureName = "dolt_exec_saved"
+
+// AddAnalyzerRules adds Dolt's own analyzer rules to the given builder, returning the builder.
+func AddAnalyzerRules(builder *analyzer.Builder) *analyzer.Builder {
+	return builder.
+		AddPreAnalyzeRule(execSavedQueriesId, execSavedQueries).
+		AddPostAnalyzeRule(pruneTableFunctionColumnsId, pruneTableFunctionColumns).
+		AddPostAnalyzeRule(checkDefaultBranchChangesId, checkDefaultBranchChanges).
+		AddPostAnalyzeRule(pushdownTableFunctionLimitsId, pushdownTableFunctionLimits)
+}
+
+// execSavedQueries replaces every call of dolt_exec_saved with the saved query that it names, which is read from the
+// query catalog of the current database. The saved query is then analyzed in its place, so the statement returns the
+// saved query's own results. A saved query may not itself call dolt_exec_saved.
+func execSavedQueries(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		call, ok := n.(*plan.Call)
+		if !ok || !strings.EqualFold(call.Name, ExecSavedProcedureName) {
+			return n, transform.SameTree, nil
+		}
+		if len(call.Params) != 1 {
+			return nil, transform.SameTree, sql.ErrCallIncorrectParameterCount.New(call.Name, 1, len(call.Params))
+		}
+		// User variables are only resolved by the engine's own rules, which have yet to run
+		param := call.Params[0]
+		if col, ok := param.(*expression.UnresolvedColumn); ok && strings.HasPrefix(col.Name(), "@") {
+			param = expression.NewUserVar(strings.TrimPrefix(col.Name(), "@"))
+		}
+		if !param.Resolved() {
+			return nil, transform.SameTree, fmt.Errorf("%s requires the name of a saved query", ExecSavedProcedureName)
+		}
+		name, err := param.Eval(ctx, nil)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		name, err = sql.LongText.Convert(name)
+		if err != nil {
+			return nil, transform.SameTree, err
+		} else if name == nil {
+			return nil, transform.SameTree, fmt.Errorf("%s requires the name of a saved query", ExecSavedProcedureName)
+		}
+
+		sq, err := savedQuery(ctx, name.(string))
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		query, err := parse.Parse(ctx, sq.Query)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		if c, ok := query.(*plan.Call); ok && strings.EqualFold(c.Name, ExecSavedProcedureName) {
+			return nil, transform.SameTree, fmt.Errorf("saved query '%s' may not call %s", sq.ID, ExecSavedProcedureName)
+		}
+		return query, transform.NewTree, nil
+	})
+}
+
+// savedQuery returns the query with the given id from the query catalog of the session's current database.
+func savedQuery(ctx *sql.Context, id string) (dtables.SavedQuery, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if dbName == "" {
+		return dtables.SavedQuery{}, sql.ErrNoDatabaseSelected.New()
+	}
+	roots, ok := dsess.DSessFromSess(ctx.Session).GetRoots(ctx, dbName)
+	if !ok {
+		return dtables.SavedQuery{}, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	sq, err := dtables.RetrieveFromQueryCatalog(ctx, roots.Working, id)
+	if err == doltdb.ErrTableNotFound {
+		return dtables.SavedQuery{}, dtables.ErrQueryNotFound.New(id)
+	}
+	return sq, err
+}
+
+// projectedTableFunction is a table function that is able to skip work for the columns that a query does not read.
+type projectedTableFunction interface {
+	sql.TableFunction
+	// Projections returns the columns that are read, which is nil when the function has not been pruned
+	Projections() []string
+	// WithProjections returns a copy of the function that only needs to produce the given columns, without changing
+	// its schema
+	WithProjections(colNames []string) sql.Node
+}
+
+// pruneTableFunctionColumns tells table functions which of their columns are read, much like the engine does for
+// tables that implement sql.ProjectedTable, which it only does for resolved tables. As the schema of the function is
+// unchanged, this runs after the engine's rules have assigned the indexes of every field.
+//
+// A function is only pruned when its rows are consumed by a node that projects them, such as a Project or GroupBy,
+// possibly through nodes that only read individual fields of the rows, such as a Filter or Sort. Anything else, such as
+// a join, a subquery, or a node that compares entire rows, leaves the function unpruned.
+func pruneTableFunctionColumns(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		switch n.(type) {
+		case *plan.Project, *plan.GroupBy, *plan.Window:
+			return pruneProjectedTableFunction(n)
+		default:
+			return n, transform.SameTree, nil
+		}
+	})
+}
+
+// pruneProjectedTableFunction prunes the table function beneath the given projecting node, returning the node
+// unchanged when there is no such function or when it cannot be pruned.
+func pruneProjectedTableFunction(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	cols := make([]string, 0)
+	var path []sql.Node
+	for node := n; ; {
+		readCols, ok := readFieldNames(node)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+		cols = append(cols, readCols...)
+		path = append(path, node)
+
+		children := node.Children()
+		if len(children) != 1 {
+			return n, transform.SameTree, nil
+		}
+		switch child := children[0].(type) {
+		case projectedTableFunction:
+			if child.Projections() != nil {
+				return n, transform.SameTree, nil
+			}
+			pruned := child.WithProjections(cols)
+			for i := len(path) - 1; i >= 0; i-- {
+				var err error
+				pruned, err = path[i].WithChildren(pruned)
+				if err != nil {
+					return nil, transform.SameTree, err
+				}
+			}
+			return pruned, transform.NewTree, nil
+		case *plan.Filter, *plan.Sort, *plan.TopN, *plan.Limit, *plan.Offset, *plan.TableAlias:
+			node = child
+		default:
+			return n, transform.SameTree, nil
+		}
+	}
+}
+
+// readFieldNames returns the lowercased names of the fields that the given node's expressions read. Returns false when
+// the node has a subquery, as the fields that a subquery reads from the outer scope are not among its expressions.
+func readFieldNames(n sql.Node) ([]string, bool) {
+	ne, ok := n.(sql.Expressioner)
+	if !ok {
+		return nil, true
+	}
+	var names []string
+	for _, e := range ne.Expressions() {
+		hasSubquery := transform.InspectExpr(e, func(e sql.Expression) bool {
+			switch e := e.(type) {
+			case *plan.Subquery:
+				return true
+			case *expression.GetField:
+				names = append(names, strings.ToLower(e.Name()))
+			}
+			return false
+		})
+		if hasSubquery {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+// limitedTableFunction is a table function that is able to stop producing rows once a query has read enough of them.
+type limitedTableFunction interface {
+	sql.TableFunction
+	// Limit returns the number of rows that are returned after the offset is skipped, and false when the function has
+	// not been limited
+	Limit() (limit int64, offset int64, ok bool)
+	// WithLimit returns a copy of the function that skips |offset| rows and then returns at most |limit| rows
+	WithLimit(limit int64, offset int64) sql.Node
+}
+
+// pushdownTableFunctionLimits pushes the LIMIT and OFFSET of a query into a table function, so that the function stops
+// its work once the limit is reached, and skips the rows of the offset without producing them. A limit is only pushed
+// through nodes that return a row for every row of their child, such as a Project, as anything that filters or reorders
+// the rows must see every row. The Offset node is removed once the function skips its rows, while the Limit node is
+// kept, as it's harmless. Limits that aren't literals, and limits of queries with SQL_CALC_FOUND_ROWS, which counts
+// every row, are left alone.
+func pushdownTableFunctionLimits(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		limit, ok := n.(*plan.Limit)
+		if !ok || limit.CalcFoundRows {
+			return n, transform.SameTree, nil
+		}
+		limitVal, ok := literalRowCount(limit.Limit)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+
+		child := limit.Child
+		var offsetVal int64
+		if offset, ok := child.(*plan.Offset); ok {
+			if offsetVal, ok = literalRowCount(offset.Offset); !ok {
+				return n, transform.SameTree, nil
+			}
+			child = offset.Child
+		}
+
+		var path []sql.Node
+		for node := child; ; {
+			switch fn := node.(type) {
+			case limitedTableFunction:
+				if _, _, ok := fn.Limit(); ok {
+					return n, transform.SameTree, nil
+				}
+				limited := fn.WithLimit(limitVal, offsetVal)
+				for i := len(path) - 1; i >= 0; i-- {
+					var err error
+					limited, err = path[i].WithChildren(limited)
+					if err != nil {
+						return nil, transform.SameTree, err
+					}
+				}
+				newLimit, err := limit.WithChildren(limited)
+				return newLimit, transform.NewTree, err
+			case *plan.Project, *plan.TableAlias:
+				path = append(path, node)
+				node = node.Children()[0]
+			default:
+				return n, transform.SameTree, nil
+			}
+		}
+	})
+}
+
+// literalRowCount returns the value of the given LIMIT or OFFSET expression, and false when it's not a literal.
+func literalRowCount(e sql.Expression) (int64, bool) {
+	lit, ok := e.(*expression.Literal)
+	if !ok {
+		return 0, false
+	}
+	val, err := sql.Int64.Convert(lit.Value())
+	if err != nil {
+		return 0, false
+	}
+	count, ok := val.(int64)
+	return count, ok && count >= 0
+}
+
+// checkDefaultBranchChanges makes every SET statement that changes the default branch of a database go through branch
+// control, which must allow and record the change. The engine sets global variables without involving the session, so
+// the value that the variable is set to is wrapped in an expression that consults branch control as it's evaluated.
+// Session variables are left alone, as a database's default branch is only ever a global variable.
+func checkDefaultBranchChanges(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		set, ok := n.(*plan.Set)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+		exprs := make([]sql.Expression, len(set.Exprs))
+		copy(exprs, set.Exprs)
+		same := transform.SameTree
+		for i, expr := range exprs {
+			setField, ok := expr.(*expression.SetField)
+			if !ok {
+				continue
+			}
+			sysVar, ok := setField.Left.(*expression.SystemVar)
+			if !ok || sysVar.Scope == sql.SystemVariableScope_Session || sysVar.Scope == sql.SystemVariableScope_ResetPersist {
+				continue
+			}
+			isDefaultBranch, dbName := dsess.IsDefaultBranchKey(sysVar.Name)
+			if !isDefaultBranch {
+				continue
+			}
+			if _, ok = setField.Right.(*defaultBranchChange); ok {
+				continue
+			}
+			exprs[i] = expression.NewSetField(sysVar, &defaultBranchChange{UnaryExpression: expression.UnaryExpression{Child: setField.Right}, dbName: dbName})
+			same = transform.NewTree
+		}
+		if same == transform.SameTree {
+			return n, transform.SameTree, nil
+		}
+		newSet, err := set.WithExpressions(exprs...)
+		return newSet, transform.NewTree, err
+	})
+}
+
+// defaultBranchChange evaluates to the new default branch of a database, once branch control has allowed the change
+// from the current default branch.
+type defaultBranchChange struct {
+	expression.UnaryExpression
+	dbName string
+}
+
+var _ sql.Expression = (*defaultBranchChange)(nil)
+
+// Type implements the interface sql.Expression.
+func (e *defaultBranchChange) Type() sql.Type {
+	return e.Child.Type()
+}
+
+// Eval implements the interface sql.Expression.
+func (e *defaultBranchChange) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := e.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	oldBranch := dsess.DefaultBranch(ctx, e.dbName)
+	newBranch := dsess.DefaultBranchForValue(ctx, e.dbName, val)
+	if err = branch_control.ChangeDefaultBranch(ctx, e.dbName, oldBranch, newBranch); err != nil {
+		return nil, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// WithChildren implements the interface sql.Expression.
+func (e *defaultBranchChange) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(e, len(children), 1)
+	}
+	return &defaultBranchChange{UnaryExpression: expression.UnaryExpression{Child: children[0]}, dbName: e.dbName}, nil
+}
+
+// String implements the interface sql.Expression.
+func (e *defaultBranchChange) String() string {
+	return e.Child.String()
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ArgumentErrorCode identifies the validation rule that an argument to a table function failed. The values are part of
+// the error messages that clients may parse, so they must never change.
+type ArgumentErrorCode string
+
+const (
+	// ArgumentErrorInvalidOption is used for arguments that could not be parsed, such as unknown options
+	ArgumentErrorInvalidOption ArgumentErrorCode = "invalid_option"
+	// ArgumentErrorInvalidValue is used for option values that are not supported by the option
+	ArgumentErrorInvalidValue ArgumentErrorCode = "invalid_value"
+	// ArgumentErrorNotConstant is used for arguments that must be constant, but depend on a row
+	ArgumentErrorNotConstant ArgumentErrorCode = "not_constant"
+	// ArgumentErrorMissingRevision is used for arguments that require a revision that was not given
+	ArgumentErrorMissingRevision ArgumentErrorCode = "missing_revision"
+	// ArgumentErrorInvalidRevision is used for revisions whose syntax is not allowed, such as "a..b^"
+	ArgumentErrorInvalidRevision ArgumentErrorCode = "invalid_revision"
+	// ArgumentErrorConflictingRevisions is used for revisions that are each valid, but may not be given together
+	ArgumentErrorConflictingRevisions ArgumentErrorCode = "conflicting_revisions"
+	// ArgumentErrorConflictingOptions is used for options that are each valid, but may not be given together
+	ArgumentErrorConflictingOptions ArgumentErrorCode = "conflicting_options"
+	// ArgumentErrorUnfetchedRevision is used for remote-tracking revisions of a configured remote, such as
+	// "origin/main", that have not been fetched into the database
+	ArgumentErrorUnfetchedRevision ArgumentErrorCode = "unfetched_revision"
+)
+
+// ArgumentErrorDetail is the structured detail of an argument validation error, which allows clients that build
+// queries from forms to identify the argument that failed without parsing the human-readable message.
+type ArgumentErrorDetail struct {
+	// Index is the index of the offending argument, or -1 when the error is not caused by a single argument
+	Index int
+	// Flag is the name of the offending option without its dashes, and is empty for revisions
+	Flag string
+	// Code identifies the rule that failed
+	Code ArgumentErrorCode
+}
+
+var _ error = ArgumentErrorDetail{}
+
+// Error implements the error interface. The detail is appended to the message of the error that it's the cause of.
+func (detail ArgumentErrorDetail) Error() string {
+	return fmt.Sprintf("[arg_index=%d flag=%s code=%s]", detail.Index, detail.Flag, detail.Code)
+}
+
+// newArgumentError returns a sql.ErrInvalidArgumentDetails error for the given function, with the given detail as its
+// cause. The message begins with the given reason, followed by the detail in the form
+// "[arg_index=<index> flag=<flag> code=<code>]".
+func newArgumentError(functionName string, reason string, detail ArgumentErrorDetail) *errors.Error {
+	// The cause is formatted into the wrapped message, so any verbs within the reason must be escaped
+	return sql.ErrInvalidArgumentDetails.Wrap(detail, functionName, strings.ReplaceAll(reason, "%", "%%"))
+}
+
+// GetArgumentErrorDetail returns the structured detail of an error that was returned while validating the arguments of
+// a table function, along with whether the error has any detail.
+func GetArgumentErrorDetail(err error) (ArgumentErrorDetail, bool) {
+	if e, ok := err.(*errors.Error); ok {
+		detail, ok := e.Cause().(ArgumentErrorDetail)
+		return detail, ok
+	}
+	return ArgumentErrorDetail{}, false
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/cron"
+)
+
+// Backups may be given a schedule with dolt_backup_schedule, which is a cron expression of the times that sql-server
+// syncs them at. The scheduler rereads the schedules from the repo state file of each database on every check, so
+// schedules and backups added or removed while the server is running take effect without a restart. A backup first
+// runs at the first scheduled time after the scheduler learns of its schedule, and a scheduled time that passes while
+// the previous sync of the backup is still running is skipped.
+
+// DefaultBackupSchedulerInterval is the interval at which the scheduler started by servers checks for due backups.
+const DefaultBackupSchedulerInterval = 15 * time.Second
+
+type scheduledBackup struct {
+	schedule string
+	next     time.Time
+}
+
+type backupScheduler struct {
+	scheduled map[string]scheduledBackup
+}
+
+func newBackupScheduler() *backupScheduler {
+	return &backupScheduler{scheduled: make(map[string]scheduledBackup)}
+}
+
+// due returns the names of the backups of the database named which are due to be synced at |now|, given the
+// backup schedules of the database.
+func (bs *backupScheduler) due(dbName string, schedules map[string]string, now time.Time) []string {
+	prefix := dbName + "\x00"
+	for key := range bs.scheduled {
+		if strings.HasPrefix(key, prefix) {
+			if _, ok := schedules[strings.TrimPrefix(key, prefix)]; !ok {
+				delete(bs.scheduled, key)
+			}
+		}
+	}
+
+	var due []string
+	for name, expr := range schedules {
+		key := prefix + name
+		sb, ok := bs.scheduled[key]
+		if !ok || sb.schedule != expr {
+			s, err := cron.Parse(expr)
+			if err != nil {
+				continue
+			}
+			bs.scheduled[key] = scheduledBackup{schedule: expr, next: s.Next(now)}
+			continue
+		}
+		if sb.next.IsZero() || now.Before(sb.next) {
+			continue
+		}
+
+		due = append(due, name)
+		s, _ := cron.Parse(expr)
+		bs.scheduled[key] = scheduledBackup{schedule: expr, next: s.Next(now)}
+	}
+	sort.Strings(due)
+	return due
+}
+
+// StartBackupScheduler periodically syncs the backups of every database that have a schedule, running each sync
+// in a new session made with |newCtx|. Returns a function that stops the scheduler, cancelling any running syncs, and
+// waits for it to exit.
+func StartBackupScheduler(newCtx func(context.Context) (*sql.Context, error), interval time.Duration) (stop func()) {
+	bgCtx, cancel := context.WithCancel(context.Background())
+	scheduler := newBackupScheduler()
+	running := &sync.WaitGroup{}
+	exited := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-bgCtx.Done():
+				return
+			case now := <-ticker.C:
+				ctx, err := newCtx(bgCtx)
+				if err != nil {
+					continue
+				}
+				for _, db := range dueBackups(ctx, scheduler, now) {
+					running.Add(1)
+					go func(dbName string, b env.Remote) {
+						defer running.Done()
+						runScheduledBackup(bgCtx, newCtx, dbName, b)
+					}(db.dbName, db.backup)
+				}
+			}
+		}
+	}()
+	once := &sync.Once{}
+	return func() {
+		once.Do(cancel)
+		<-exited
+		running.Wait()
+	}
+}
+
+type dueBackup struct {
+	dbName string
+	backup env.Remote
+}
+
+func dueBackups(ctx *sql.Context, scheduler *backupScheduler, now time.Time) []dueBackup {
+	provider := dsess.DSessFromSess(ctx.Session).Provider()
+
+	var due []dueBackup
+	for _, db := range provider.AllDatabases(ctx) {
+		if _, ok := db.(SqlDatabase); !ok {
+			continue
+		}
+		// revision databases share the repo state file of their database, and have no file system of their own
+		fs, err := provider.FileSystemForDatabase(db.Name())
+		if err != nil {
+			continue
+		}
+		repoState, err := env.LoadRepoState(fs)
+		if err != nil {
+			ctx.GetLogger().Warnf("unable to load the backup schedules of database %s: %s", db.Name(), err.Error())
+			continue
+		}
+
+		for _, name := range scheduler.due(db.Name(), repoState.BackupSchedules, now) {
+			if b, ok := repoState.Backups[name]; ok {
+				due = append(due, dueBackup{dbName: db.Name(), backup: b})
+			}
+		}
+	}
+	return due
+}
+
+func runScheduledBackup(bgCtx context.Context, newCtx func(context.Context) (*sql.Context, error), dbName string, b env.Remote) {
+	ctx, err := newCtx(bgCtx)
+	if err != nil {
+		return
+	}
+	ctx.SetCurrentDatabase(dbName)
+
+	start := time.Now()
+	err = dfunctions.SyncBackup(ctx, dbName, b)
+	if err != nil {
+		ctx.GetLogger().Errorf("scheduled sync of backup %s of database %s failed: %s", b.Name, dbName, err.Error())
+		return
+	}
+	ctx.GetLogger().Infof("synced backup %s of database %s in %s", b.Name, dbName, time.Since(start).Round(time.Millisecond))
+}
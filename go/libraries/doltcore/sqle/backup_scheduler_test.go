@@ -0,0 +1,52 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupSchedulerDue(t *testing.T) {
+	start := time.Date(2022, time.September, 16, 1, 59, 50, 0, time.UTC)
+	schedules := map[string]string{
+		"nightly":  "0 2 * * *",
+		"frequent": "*/15 * * * *",
+		"never":    "0 0 30 2 *",
+	}
+
+	bs := newBackupScheduler()
+	// nothing runs when a schedule is first seen
+	assert.Empty(t, bs.due("db", schedules, start))
+	assert.Equal(t, []string{"frequent", "nightly"}, bs.due("db", schedules, start.Add(10*time.Second)))
+	// each scheduled time runs once
+	assert.Empty(t, bs.due("db", schedules, start.Add(25*time.Second)))
+	assert.Equal(t, []string{"frequent"}, bs.due("db", schedules, start.Add(15*time.Minute+10*time.Second)))
+	// schedules are per database
+	assert.Empty(t, bs.due("other", schedules, start.Add(15*time.Minute+10*time.Second)))
+
+	// a changed schedule starts over
+	schedules["frequent"] = "*/5 * * * *"
+	assert.Empty(t, bs.due("db", schedules, start.Add(20*time.Minute+10*time.Second)))
+	assert.Equal(t, []string{"frequent"}, bs.due("db", schedules, start.Add(25*time.Minute+10*time.Second)))
+
+	// a removed schedule is forgotten, so that it starts over if it is added again
+	delete(schedules, "nightly")
+	assert.Empty(t, bs.due("db", schedules, start.Add(26*time.Minute)))
+	schedules["nightly"] = "0 2 * * *"
+	assert.Empty(t, bs.due("db", schedules, start.Add(27*time.Minute)))
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// branchControlRef is the internal ref that snapshots of the branch control tables are committed to. Each snapshot is
+// stored as the data of a commit without parents, so that the ref is pushed by the same hooks as every other ref, and
+// only the latest snapshot is kept.
+var branchControlRef = ref.NewInternalRef("branch_control")
+
+// appliedBranchControl holds, for each read replica database, the commit of the replicated branch control tables that
+// was most recently loaded from it, so that the same snapshot is not loaded on every pull.
+var appliedBranchControl = struct {
+	mu     sync.Mutex
+	hashes map[string]hash.Hash
+}{hashes: make(map[string]hash.Hash)}
+
+// shouldReplicateBranchControl returns whether the branch control tables are replicated. They're only replicated when
+// dolt_replicate_branch_control is enabled, as anyone that can read the remote can then read the tables, and a read
+// replica only loads them when it is enabled on the replica as well.
+func shouldReplicateBranchControl() bool {
+	_, val, ok := sql.SystemVariables.GetGlobal(dsess.ReplicateBranchControl)
+	return ok && val == SysVarTrue
+}
+
+// replicateBranchControl commits the given snapshot of the branch control tables to the branch control ref of the given
+// database, which is skipped when the ref already holds the same snapshot.
+func replicateBranchControl(ctx context.Context, ddb *doltdb.DoltDB, data []byte) error {
+	current, _, ok, err := readBranchControl(ctx, ddb)
+	if err != nil {
+		return err
+	}
+	if ok && bytes.Equal(current, data) {
+		return nil
+	}
+	meta, err := datas.NewCommitMeta(env.DefaultName, env.DefaultEmail, "branch control")
+	if err != nil {
+		return err
+	}
+	_, err = ddb.CommitData(ctx, branchControlRef, data, meta)
+	return err
+}
+
+// readBranchControl returns the snapshot of the branch control tables that was committed to the branch control ref of
+// the given database, along with the commit's hash. Returns false if the ref does not exist.
+func readBranchControl(ctx context.Context, ddb *doltdb.DoltDB) ([]byte, hash.Hash, bool, error) {
+	if exists, err := ddb.HasRef(ctx, branchControlRef); err != nil || !exists {
+		return nil, hash.Hash{}, false, err
+	}
+	cm, err := ddb.ResolveCommitRef(ctx, branchControlRef)
+	if err != nil {
+		return nil, hash.Hash{}, false, err
+	}
+	cmHash, err := cm.HashOf()
+	if err != nil {
+		return nil, hash.Hash{}, false, err
+	}
+	data, err := ddb.ReadCommittedData(ctx, cm)
+	if err != nil {
+		return nil, hash.Hash{}, false, err
+	}
+	return data, cmHash, true, nil
+}
+
+// pullBranchControl loads the branch control tables that were replicated to the remote of the read replica as the
+// base of the branch control tables, when they've changed since they were last loaded from the same database.
+func pullBranchControl(ctx context.Context, rrd ReadReplicaDatabase) error {
+	if !shouldReplicateBranchControl() {
+		return nil
+	}
+	data, cmHash, ok, err := readBranchControl(ctx, rrd.srcDB)
+	if err != nil || !ok {
+		return err
+	}
+	appliedBranchControl.mu.Lock()
+	defer appliedBranchControl.mu.Unlock()
+	if appliedBranchControl.hashes[rrd.Name()] == cmHash {
+		return nil
+	}
+	if err = branch_control.LoadReplicatedBase(ctx, data); err != nil {
+		return err
+	}
+	appliedBranchControl.hashes[rrd.Name()] = cmHash
+	return nil
+}
@@ -211,7 +211,7 @@ func GetInitialDBState(ctx context.Context, db SqlDatabase) (dsess.InitialDbStat
 
 	var ws *doltdb.WorkingSet
 	if retainedErr == nil {
-		ws, err = env.WorkingSet(ctx, ddb, rsr)
+		ws, err = resolveWorkingSetForHead(ctx, ddb, rsr.CWBHeadRef(), headCommit)
 		if err != nil {
 			return dsess.InitialDbState{}, err
 		}
@@ -244,6 +244,29 @@ func GetInitialDBState(ctx context.Context, db SqlDatabase) (dsess.InitialDbStat
 	}, nil
 }
 
+// resolveWorkingSetForHead returns the working set of the given branch. Branches that have never had a working set
+// written, such as those created on a read replica, are given a working set that matches their head. The new working
+// set is only held in memory, as merely reading from a branch must never write to the database.
+func resolveWorkingSetForHead(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, head *doltdb.Commit) (*doltdb.WorkingSet, error) {
+	wsRef, err := ref.WorkingSetRefForHead(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == doltdb.ErrWorkingSetNotFound {
+		headRoot, err := head.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return doltdb.EmptyWorkingSet(wsRef).WithWorkingRoot(headRoot).WithStagedRoot(headRoot), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return ws, nil
+}
+
 // Name returns the name of this database, set at creation time.
 func (db Database) Name() string {
 	return db.name
@@ -418,6 +441,14 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			return nil, false, err
 		}
 		return dt, true, nil
+
+	case strings.HasPrefix(lwrName, doltdb.DoltWorkspaceTablePrefix):
+		suffix := tblName[len(doltdb.DoltWorkspaceTablePrefix):]
+		dt, err := dtables.NewWorkspaceTable(ctx, suffix, db.Name(), db.ddb, root)
+		if err != nil {
+			return nil, false, err
+		}
+		return dt, true, nil
 	}
 
 	var dt sql.Table
@@ -447,10 +478,14 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewTableOfTablesInConflict(ctx, db.name, db.ddb), true
 	case doltdb.TableOfTablesWithViolationsName:
 		dt, found = dtables.NewTableOfTablesConstraintViolations(ctx, root), true
+	case doltdb.SchemaConflictsTableName:
+		dt, found = NewSchemaConflictsTable(ctx, db.Name(), db.ddb), true
 	case doltdb.BranchesTableName:
-		dt, found = dtables.NewBranchesTable(ctx, db.ddb), true
+		dt, found = dtables.NewBranchesTable(ctx, db.Name(), db.ddb), true
 	case doltdb.RemotesTableName:
 		dt, found = dtables.NewRemotesTable(ctx, db.ddb), true
+	case doltdb.BackupsTableName:
+		dt, found = dtables.NewBackupsTable(ctx, db.Name()), true
 	case doltdb.CommitsTableName:
 		dt, found = dtables.NewCommitsTable(ctx, db.ddb), true
 	case doltdb.CommitAncestorsTableName:
@@ -466,11 +501,23 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 	case doltdb.MergeStatusTableName:
 		dt, found = dtables.NewMergeStatusTable(db.name), true
 	case doltdb.TagsTableName:
-		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+		dt, found = dtables.NewTagsTable(ctx, db.Name(), db.ddb), true
+	case doltdb.ReflogTableName:
+		dt, found = dtables.NewReflogTable(ctx, db.ddb), true
+	case doltdb.StashesTableName:
+		dt, found = dtables.NewStashesTable(ctx, db.ddb), true
+	case doltdb.DanglingCommitsTableName:
+		dt, found = dtables.NewDanglingCommitsTable(ctx, db.ddb), true
 	case dtables.AccessTableName:
 		dt, found = dtables.NewBranchControlTable(branch_control.StaticController.Access), true
 	case dtables.NamespaceTableName:
 		dt, found = dtables.NewBranchNamespaceControlTable(branch_control.StaticController.Namespace), true
+	case dtables.PendingMovesTableName:
+		dt, found = dtables.NewPendingMovesTable(branch_control.StaticController.PendingMoves), true
+	case dtables.FreezeTableName:
+		dt, found = dtables.NewFreezeTable(branch_control.StaticController.Freezes), true
+	case dtables.RolesTableName:
+		dt, found = dtables.NewBranchControlRolesTable(branch_control.StaticController.Roles), true
 	}
 	if found {
 		return dt, found, nil
@@ -558,7 +605,7 @@ func resolveAsOfCommitRef(ctx *sql.Context, ddb *doltdb.DoltDB, head ref.DoltRef
 		return nil, nil, err
 	}
 
-	cm, err := ddb.Resolve(ctx, cs, head)
+	cm, err := dsess.ResolveReadableCommit(ctx, ddb, cs, head)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -734,7 +781,7 @@ func (db Database) GetHeadRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 // DropTable drops the table with the name given.
 // The planner returns the correct case sensitive name in tableName
 func (db Database) DropTable(ctx *sql.Context, tableName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	if doltdb.IsReadOnlySystemTable(tableName) {
@@ -836,7 +883,7 @@ func (db Database) removeTableFromAutoIncrementTracker(
 
 // CreateTable creates a table with the name and schema given.
 func (db Database) CreateTable(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	if strings.ToLower(tableName) == doltdb.DocTableName {
@@ -844,6 +891,16 @@ func (db Database) CreateTable(ctx *sql.Context, tableName string, sch sql.Prima
 		if !dtables.DoltDocsSqlSchema.Equals(sch.Schema) {
 			return fmt.Errorf("incorrect schema for dolt_docs table")
 		}
+	} else if strings.ToLower(tableName) == doltdb.DoltQueryCatalogTableName {
+		if !dtables.DoltQueryCatalogSqlSchema.Equals(sch.Schema) {
+			return fmt.Errorf("the reserved table %s must be created with the schema of the query catalog", doltdb.DoltQueryCatalogTableName)
+		}
+		// The query catalog is read by the tags of its columns, so it's created with its own schema
+		ws, err := db.GetWorkingSet(ctx)
+		if err != nil {
+			return err
+		}
+		return db.createDoltTable(ctx, doltdb.DoltQueryCatalogTableName, ws.WorkingRoot(), dtables.DoltQueryCatalogSchema)
 	} else if doltdb.HasDoltPrefix(tableName) {
 		return ErrReservedTableName.New(tableName)
 	}
@@ -950,7 +1007,7 @@ func (db Database) CreateTemporaryTable(ctx *sql.Context, tableName string, pkSc
 
 // RenameTable implements sql.TableRenamer
 func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := db.GetRoot(ctx)
@@ -1167,7 +1224,7 @@ func (db Database) GetStoredProcedures(ctx *sql.Context) ([]sql.StoredProcedureD
 
 // SaveStoredProcedure implements sql.StoredProcedureDatabase.
 func (db Database) SaveStoredProcedure(ctx *sql.Context, spd sql.StoredProcedureDetails) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	return DoltProceduresAddProcedure(ctx, db, spd)
@@ -1175,14 +1232,14 @@ func (db Database) SaveStoredProcedure(ctx *sql.Context, spd sql.StoredProcedure
 
 // DropStoredProcedure implements sql.StoredProcedureDatabase.
 func (db Database) DropStoredProcedure(ctx *sql.Context, name string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	return DoltProceduresDropProcedure(ctx, db, name)
 }
 
 func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, definition string, created time.Time, existingErr error) (err error) {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	tbl, err := GetOrCreateDoltSchemasTable(ctx, db)
@@ -1236,7 +1293,7 @@ func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, defin
 }
 
 func (db Database) dropFragFromSchemasTable(ctx *sql.Context, fragType, name string, missingErr error) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	stbl, found, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
@@ -23,6 +23,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -100,6 +101,7 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 	for _, fn := range dfunctions.DoltFunctions {
 		funcs[strings.ToLower(fn.FunctionName())] = fn
 	}
+	funcs[logChecksumFunction.FunctionName()] = logChecksumFunction
 
 	externalProcedures := sql.NewExternalStoredProcedureRegistry()
 	for _, esp := range dprocedures.DoltProcedures {
@@ -653,6 +655,11 @@ func (p DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revDB string
 	}
 
 	if isBranch {
+		// Branches may be hidden from users that have no permissions on them
+		if err = branch_control.CanReadBranch(ctx, revSpec); err != nil {
+			return nil, dsess.InitialDbState{}, false, err
+		}
+
 		// fetch the upstream head if this is a replicated db
 		if replicaDb, ok := srcDb.(ReadReplicaDatabase); ok {
 			// TODO move this out of analysis phase, should only happen at read time
@@ -772,7 +779,7 @@ func (p DoltDatabaseProvider) resolveAncestorSpec(ctx *sql.Context, revSpec stri
 		return "", err
 	}
 
-	cm, err := ddb.ResolveCommitRef(ctx, ref)
+	cm, err := dsess.ResolveReadableCommitRef(ctx, ddb, ref)
 	if err != nil {
 		return "", err
 	}
@@ -838,6 +845,33 @@ func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.Ta
 	case "dolt_log":
 		dtf := &LogTableFunction{}
 		return dtf, nil
+	case "dolt_tags":
+		dtf := &TagsTableFunction{}
+		return dtf, nil
+	case "dolt_reflog":
+		dtf := &ReflogTableFunction{}
+		return dtf, nil
+	case "dolt_blame":
+		dtf := &BlameTableFunction{}
+		return dtf, nil
+	case "dolt_patch":
+		dtf := &PatchTableFunction{}
+		return dtf, nil
+	case "dolt_commit_graph":
+		dtf := &CommitGraphTableFunction{}
+		return dtf, nil
+	case "dolt_history":
+		dtf := &HistoryTableFunction{}
+		return dtf, nil
+	case "dolt_branch_status":
+		dtf := &BranchStatusTableFunction{}
+		return dtf, nil
+	case "dolt_blobstore_check":
+		dtf := &BlobstoreCheckTableFunction{}
+		return dtf, nil
+	case "dolt_branch_control_matches":
+		dtf := &BranchControlMatchesTableFunction{}
+		return dtf, nil
 	}
 
 	return nil, sql.ErrTableFunctionNotFound.New(name)
@@ -988,12 +1022,7 @@ func dbRevisionForBranch(ctx context.Context, srcDb SqlDatabase, revSpec string)
 		return Database{}, dsess.InitialDbState{}, err
 	}
 
-	wsRef, err := ref.WorkingSetRefForHead(branch)
-	if err != nil {
-		return Database{}, dsess.InitialDbState{}, err
-	}
-
-	ws, err := srcDb.DbData().Ddb.ResolveWorkingSet(ctx, wsRef)
+	ws, err := resolveWorkingSetForHead(ctx, srcDb.DbData().Ddb, branch, cm)
 	if err != nil {
 		return Database{}, dsess.InitialDbState{}, err
 	}
@@ -1112,7 +1141,7 @@ func dbRevisionForCommit(ctx context.Context, srcDb Database, revSpec string) (R
 		return ReadOnlyDatabase{}, dsess.InitialDbState{}, err
 	}
 
-	cm, err := srcDb.DbData().Ddb.Resolve(ctx, spec, srcDb.DbData().Rsr.CWBHeadRef())
+	cm, err := dsess.ResolveReadableCommit(ctx, srcDb.DbData().Ddb, spec, srcDb.DbData().Rsr.CWBHeadRef())
 	if err != nil {
 		return ReadOnlyDatabase{}, dsess.InitialDbState{}, err
 	}
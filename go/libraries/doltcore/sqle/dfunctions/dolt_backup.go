@@ -166,22 +166,55 @@ func DoDoltBackup(ctx *sql.Context, args []string) (int, error) {
 			return statusErr, fmt.Errorf("error: unknown backup: '%s'; %v", backupName, backups)
 		}
 
+		err = SyncBackup(ctx, dbName, b)
+		if err != nil {
+			return 1, err
+		}
+		return statusOk, nil
+
 	default:
 		return statusErr, fmt.Errorf("unrecognized dolt_backup parameter: %s", apr.Arg(0))
 	}
 
+	err = syncBackup(ctx, dbData, b)
+	if err != nil {
+		return 1, err
+	}
+	return statusOk, nil
+}
+
+// SyncBackup syncs the database named to the backup given, recording the outcome of the sync so that it's shown in
+// the dolt_backups system table. Only one sync of a backup may run at a time.
+func SyncBackup(ctx *sql.Context, dbName string, b env.Remote) error {
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	err := dsess.StartBackupRun(dbName, b.Name)
+	if err != nil {
+		return err
+	}
+	err = syncBackup(ctx, dbData, b)
+	dsess.FinishBackupRun(dbName, b.Name, err)
+	return err
+}
+
+func syncBackup(ctx *sql.Context, dbData env.DbData, b env.Remote) error {
+	sess := dsess.DSessFromSess(ctx.Session)
 	destDb, err := sess.Provider().GetRemoteDB(ctx, dbData.Ddb, b, true)
 	if err != nil {
-		return statusErr, fmt.Errorf("error loading backup destination: %w", err)
+		return fmt.Errorf("error loading backup destination: %w", err)
 	}
 
 	tmpDir, err := dbData.Rsw.TempTableFilesDir()
 	if err != nil {
-		return statusErr, err
+		return err
 	}
 	err = actions.SyncRoots(ctx, dbData.Ddb, destDb, tmpDir, runProgFuncs, stopProgFuncs)
 	if err != nil && err != pull.ErrDBUpToDate {
-		return 1, fmt.Errorf("error syncing backup: %w", err)
+		return fmt.Errorf("error syncing backup: %w", err)
 	}
-	return statusOk, nil
+	return nil
 }
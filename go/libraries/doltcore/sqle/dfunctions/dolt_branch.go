@@ -140,6 +140,12 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 	if err != nil {
 		return err
 	}
+	if err = branch_control.RevokeDeletedBranch(ctx, oldBranchName); err != nil {
+		return err
+	}
+	if err = branch_control.GrantCreatedBranch(ctx, newBranchName); err != nil {
+		return err
+	}
 
 	// The current branch on CLI can be deleted as user can be on different branch on SQL and delete it from SQL session.
 	// To update current head info on RepoState, we need DoltEnv to load CLI environment.
@@ -201,6 +207,9 @@ func deleteBranches(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParse
 		if err != nil {
 			return err
 		}
+		if err = branch_control.RevokeDeletedBranch(ctx, branchName); err != nil {
+			return err
+		}
 		if headOnCLI == branchName {
 			updateFS = true
 		}
@@ -295,7 +304,10 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 	if err := branch_control.CanCreateBranch(ctx, branchName); err != nil {
 		return err
 	}
-	return actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, apr.Contains(cli.ForceFlag))
+	if err := actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, apr.Contains(cli.ForceFlag)); err != nil {
+		return err
+	}
+	return branch_control.GrantCreatedBranch(ctx, branchName)
 }
 
 func copyBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults) error {
@@ -334,5 +346,5 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 		}
 	}
 
-	return nil
+	return branch_control.GrantCreatedBranch(ctx, destBr)
 }
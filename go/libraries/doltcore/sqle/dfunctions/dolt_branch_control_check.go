@@ -0,0 +1,150 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+const DoltBranchControlCheckFuncName = "dolt_branch_control_check"
+
+// operationsType converts operations into the same strings that are displayed by the "dolt_branch_control" table.
+var operationsType = sql.MustCreateSetType(dtables.OperationsStrings, sql.Collation_utf8mb4_0900_ai_ci)
+
+// BranchControlCheckFunc returns a JSON document explaining the permissions that a user and host have on a branch,
+// containing the effective permissions along with every entry that granted them, so that an admin may debug a denial
+// without reproducing the failing write.
+type BranchControlCheckFunc struct {
+	children []sql.Expression
+}
+
+// NewBranchControlCheckFunc creates a new BranchControlCheckFunc expression.
+func NewBranchControlCheckFunc(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(DoltBranchControlCheckFuncName, 3, len(args))
+	}
+	return &BranchControlCheckFunc{children: args}, nil
+}
+
+// Eval implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	args := make([]string, len(bc.children))
+	for i, child := range bc.children {
+		val, err := child.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		str, err := sql.LongText.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = str.(string)
+	}
+
+	evaluation, err := branch_control.Evaluate(ctx, args[0], args[1], args[2])
+	if err != nil {
+		return nil, err
+	}
+	perms, err := permissionsType.BitsToString(uint64(evaluation.Permissions))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]interface{}, len(evaluation.Entries))
+	for i, entry := range evaluation.Entries {
+		entryPerms, err := permissionsType.BitsToString(uint64(entry.Permissions))
+		if err != nil {
+			return nil, err
+		}
+		entryOps, err := operationsType.BitsToString(uint64(entry.Operations))
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = map[string]interface{}{
+			"branch":      entry.Branch,
+			"user":        entry.User,
+			"host":        entry.Host,
+			"permissions": entryPerms,
+			"operations":  entryOps,
+			"priority":    entry.Priority,
+			"base":        entry.Base,
+		}
+	}
+	doc := map[string]interface{}{
+		"permissions": perms,
+		"super_user":  evaluation.SuperUser,
+		"frozen":      evaluation.Frozen,
+		"entries":     entries,
+	}
+	if evaluation.Frozen {
+		doc["freeze"] = map[string]interface{}{
+			"branch": evaluation.Freeze.Branch,
+			"reason": evaluation.Freeze.Reason,
+		}
+	}
+	return sql.JSON.Convert(doc)
+}
+
+// String implements the Stringer interface.
+func (bc *BranchControlCheckFunc) String() string {
+	childrenStrings := make([]string, len(bc.children))
+	for i, child := range bc.children {
+		childrenStrings[i] = child.String()
+	}
+	return fmt.Sprintf("DOLT_BRANCH_CONTROL_CHECK(%s)", strings.Join(childrenStrings, ","))
+}
+
+// IsNullable implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) IsNullable() bool {
+	for _, child := range bc.children {
+		if child.IsNullable() {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolved implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) Resolved() bool {
+	for _, child := range bc.children {
+		if !child.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Type implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) Type() sql.Type {
+	return sql.JSON
+}
+
+// Children implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) Children() []sql.Expression {
+	return bc.children
+}
+
+// WithChildren implements the sql.Expression interface.
+func (bc *BranchControlCheckFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewBranchControlCheckFunc(children...)
+}
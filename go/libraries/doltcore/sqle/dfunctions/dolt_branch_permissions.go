@@ -0,0 +1,119 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+const DoltBranchPermissionsFuncName = "dolt_branch_permissions"
+
+// permissionsType converts permissions into the same strings that are displayed by the "dolt_branch_control" table.
+var permissionsType = sql.MustCreateSetType(dtables.PermissionsStrings, sql.Collation_utf8mb4_0900_ai_ci)
+
+// BranchPermissionsFunc returns the permissions that the current user has on a branch, which allows for checking
+// whether a write will succeed before attempting it. When given a user and host, returns their permissions instead.
+type BranchPermissionsFunc struct {
+	children []sql.Expression
+}
+
+// NewBranchPermissionsFunc creates a new BranchPermissionsFunc expression.
+func NewBranchPermissionsFunc(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 1 && len(args) != 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(DoltBranchPermissionsFuncName, "1 or 3", len(args))
+	}
+	return &BranchPermissionsFunc{children: args}, nil
+}
+
+// Eval implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	args := make([]string, len(bp.children))
+	for i, child := range bp.children {
+		val, err := child.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		str, err := sql.LongText.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = str.(string)
+	}
+
+	var perms branch_control.Permissions
+	var err error
+	if len(args) == 1 {
+		perms, err = branch_control.BranchPermissions(ctx, args[0])
+	} else {
+		perms, err = branch_control.UserBranchPermissions(ctx, args[0], args[1], args[2])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return permissionsType.BitsToString(uint64(perms))
+}
+
+// String implements the Stringer interface.
+func (bp *BranchPermissionsFunc) String() string {
+	childrenStrings := make([]string, len(bp.children))
+	for i, child := range bp.children {
+		childrenStrings[i] = child.String()
+	}
+	return fmt.Sprintf("DOLT_BRANCH_PERMISSIONS(%s)", strings.Join(childrenStrings, ","))
+}
+
+// IsNullable implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) IsNullable() bool {
+	for _, child := range bp.children {
+		if child.IsNullable() {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolved implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) Resolved() bool {
+	for _, child := range bp.children {
+		if !child.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Type implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) Type() sql.Type {
+	return sql.Text
+}
+
+// Children implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) Children() []sql.Expression {
+	return bp.children
+}
+
+// WithChildren implements the sql.Expression interface.
+func (bp *BranchPermissionsFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewBranchPermissionsFunc(children...)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
@@ -155,7 +156,7 @@ func DoDoltCheckout(ctx *sql.Context, args []string) (int, error) {
 // TODO: This is cribbed heavily from doltdb.*DoltDB.NewBranchAtCommit.
 func createWorkingSetForLocalBranch(ctx *sql.Context, ddb *doltdb.DoltDB, branchName string) error {
 	branchRef := ref.NewBranchRef(branchName)
-	commit, err := ddb.ResolveCommitRef(ctx, branchRef)
+	commit, err := dsess.ResolveReadableCommitRef(ctx, ddb, branchRef)
 	if err != nil {
 		return err
 	}
@@ -239,10 +240,16 @@ func checkoutNewBranch(ctx *sql.Context, dbName string, dbData env.DbData, branc
 		startPt = "head"
 	}
 
+	if err := branch_control.CanCreateBranch(ctx, branchName); err != nil {
+		return err
+	}
 	err := actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, false)
 	if err != nil {
 		return err
 	}
+	if err = branch_control.GrantCreatedBranch(ctx, branchName); err != nil {
+		return err
+	}
 
 	return checkoutBranch(ctx, dbName, branchName)
 }
@@ -22,8 +22,11 @@ import (
 	"github.com/dolthub/vitess/go/vt/proto/query"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 const DoltCommitFuncName = "dolt_commit"
@@ -77,18 +80,46 @@ func DoDoltCommit(ctx *sql.Context, args []string) (string, error) {
 		}
 	}
 
+	// An amended commit replaces the head of the branch, keeping its message and author unless new ones are given
+	var amended *doltdb.Commit
+	var amendedMeta *datas.CommitMeta
+	if apr.Contains(cli.AmendFlag) {
+		amended, err = amendableHeadCommit(ctx, dSess, dbName)
+		if err != nil {
+			return "", err
+		}
+		amendedMeta, err = amended.GetCommitMeta(ctx)
+		if err != nil {
+			return "", err
+		}
+		parent, err := amended.GetParent(ctx, 0)
+		if err != nil {
+			return "", err
+		}
+		roots.Head, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	var name, email string
 	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
 		name, email, err = cli.ParseAuthor(authorStr)
 		if err != nil {
 			return "", err
 		}
+	} else if amended != nil {
+		name = amendedMeta.Name
+		email = amendedMeta.Email
 	} else {
 		name = dSess.Username()
 		email = dSess.Email()
 	}
 
 	msg, msgOk := apr.GetValue(cli.MessageArg)
+	if !msgOk && amended != nil {
+		msg, msgOk = amendedMeta.Description, true
+	}
 	if !msgOk {
 		return "", fmt.Errorf("Must provide commit message.")
 	}
@@ -106,7 +137,7 @@ func DoDoltCommit(ctx *sql.Context, args []string) (string, error) {
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
-		AllowEmpty: apr.Contains(cli.AllowEmptyFlag),
+		AllowEmpty: apr.Contains(cli.AllowEmptyFlag) || amended != nil,
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
@@ -120,6 +151,18 @@ func DoDoltCommit(ctx *sql.Context, args []string) (string, error) {
 		return "", errors.New("nothing to commit")
 	}
 
+	if amended != nil {
+		pendingCommit.CommitOptions.Parents, err = amended.ParentHashes(ctx)
+		if err != nil {
+			return "", err
+		}
+		pendingCommit.CommitOptions.ExpectedHead, err = amended.HashOf()
+		if err != nil {
+			return "", err
+		}
+		pendingCommit.CommitOptions.Amend = true
+	}
+
 	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
 	if err != nil {
 		return "", err
@@ -133,6 +176,37 @@ func DoDoltCommit(ctx *sql.Context, args []string) (string, error) {
 	return h.String(), nil
 }
 
+// amendableHeadCommit returns the head commit of the current branch, or an error if it can't be amended. Amending
+// replaces the branch's head, so it's checked as a ref move, and is denied when moving the head requires approval.
+func amendableHeadCommit(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) (*doltdb.Commit, error) {
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	headRef, err := ws.Ref().ToHeadRef()
+	if err != nil {
+		return nil, err
+	}
+	requiresApproval, err := branch_control.CheckRefMove(ctx, headRef.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if requiresApproval {
+		return nil, fmt.Errorf("branch `%s` requires approval to move its head, so its last commit can't be amended", headRef.GetPath())
+	}
+	if ws.MergeActive() {
+		return nil, fmt.Errorf("error: you are in the middle of a merge; the merge must be committed or aborted before amending")
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if headCommit.NumParents() == 0 {
+		return nil, fmt.Errorf("error: the initial commit of a database can't be amended")
+	}
+	return headCommit, nil
+}
+
 func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]string, error) {
 	args := make([]string, len(children))
 	for i := range children {
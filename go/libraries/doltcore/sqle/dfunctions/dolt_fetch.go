@@ -85,6 +85,13 @@ func DoDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, fmt.Errorf("Could not load database %s", dbName)
 	}
 
+	return DoDoltFetchForDatabase(ctx, dbData, args)
+}
+
+// DoDoltFetchForDatabase fetches into the database of the given data, which may differ from the current database,
+// using the same arguments as DoDoltFetch.
+func DoDoltFetchForDatabase(ctx *sql.Context, dbData env.DbData, args []string) (int, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
 	apr, err := cli.CreateFetchArgParser().Parse(args)
 	if err != nil {
 		return cmdFailure, err
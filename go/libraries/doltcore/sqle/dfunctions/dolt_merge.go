@@ -17,12 +17,14 @@ package dfunctions
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
@@ -65,8 +67,44 @@ func (d DoltMergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 	return hasConflicts, err
 }
 
+// MergeStrategies are the strategies used to resolve the conflicts of a merge, by table. Conflicts of tables without a
+// strategy are left for the user to resolve.
+type MergeStrategies struct {
+	// Default is the strategy of tables without their own strategy, if any
+	Default string
+	Tables  map[string]string
+}
+
+// ForTable returns the strategy used to resolve the conflicts of the table named, or the empty string if they aren't
+// resolved.
+func (s MergeStrategies) ForTable(tblName string) string {
+	if strategy, ok := s.Tables[tblName]; ok {
+		return strategy
+	}
+	return s.Default
+}
+
+// IsEmpty returns whether there are no strategies, so that no conflicts are resolved.
+func (s MergeStrategies) IsEmpty() bool {
+	return s.Default == "" && len(s.Tables) == 0
+}
+
+// MergeConflictResolver resolves the conflicts of the merge in the working set given using the strategies given,
+// returning the new working set.
+type MergeConflictResolver func(ctx *sql.Context, dbName string, ws *doltdb.WorkingSet, strategies MergeStrategies) (*doltdb.WorkingSet, error)
+
 // DoDoltMerge returns has_conflicts and fast_forward status
 func DoDoltMerge(ctx *sql.Context, args []string) (int, int, error) {
+	return doDoltMerge(ctx, cli.CreateMergeArgParser(), args, nil)
+}
+
+// DoDoltMergeWithStrategies is DoDoltMerge with support for merge strategies, which resolve the conflicts of the merge
+// with |resolve| so that the merge can be committed.
+func DoDoltMergeWithStrategies(ctx *sql.Context, args []string, resolve MergeConflictResolver) (int, int, error) {
+	return doDoltMerge(ctx, cli.CreateMergeWithStrategiesArgParser(), args, resolve)
+}
+
+func doDoltMerge(ctx *sql.Context, ap *argparser.ArgParser, args []string, resolve MergeConflictResolver) (int, int, error) {
 	dbName := ctx.GetCurrentDatabase()
 
 	if len(dbName) == 0 {
@@ -75,15 +113,27 @@ func DoDoltMerge(ctx *sql.Context, args []string) (int, int, error) {
 
 	sess := dsess.DSessFromSess(ctx.Session)
 
-	apr, err := cli.CreateMergeArgParser().Parse(args)
+	apr, err := ap.Parse(args)
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	}
+
+	strategies, err := parseMergeStrategies(apr)
 	if err != nil {
 		return noConflictsOrViolations, threeWayMerge, err
 	}
+	if !strategies.IsEmpty() && apr.Contains(cli.SquashParam) {
+		return noConflictsOrViolations, threeWayMerge, fmt.Errorf("error: Flags '--%s' and '--%s' cannot be used together.\n", cli.SquashParam, cli.StrategyParam)
+	}
 
 	if apr.ContainsAll(cli.SquashParam, cli.NoFFParam) {
 		return noConflictsOrViolations, threeWayMerge, fmt.Errorf("error: Flags '--%s' and '--%s' cannot be used together.\n", cli.SquashParam, cli.NoFFParam)
 	}
 
+	if err = branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_Merge); err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	}
+
 	ws, err := sess.WorkingSet(ctx, dbName)
 	if err != nil {
 		return noConflictsOrViolations, threeWayMerge, err
@@ -132,7 +182,7 @@ func DoDoltMerge(ctx *sql.Context, args []string) (int, int, error) {
 		msg = userMsg
 	}
 
-	ws, conflicts, fastForward, err := performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
+	ws, conflicts, fastForward, err := performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg, strategies, resolve)
 	if err != nil || conflicts != 0 || fastForward != 0 {
 		return conflicts, fastForward, err
 	}
@@ -146,7 +196,7 @@ func DoDoltMerge(ctx *sql.Context, args []string) (int, int, error) {
 // fast-forward was performed. This commits the working set if merge is successful and
 // 'no-commit' flag is not defined.
 // TODO FF merging commit with constraint violations requires `constraint verify`
-func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots, ws *doltdb.WorkingSet, dbName string, spec *merge.MergeSpec, noCommit bool, msg string) (*doltdb.WorkingSet, int, int, error) {
+func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots, ws *doltdb.WorkingSet, dbName string, spec *merge.MergeSpec, noCommit bool, msg string, strategies MergeStrategies, resolve MergeConflictResolver) (*doltdb.WorkingSet, int, int, error) {
 	// todo: allow merges even when an existing merge is uncommitted
 	if ws.MergeActive() {
 		return ws, noConflictsOrViolations, threeWayMerge, doltdb.ErrMergeActive
@@ -202,6 +252,9 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 	}
 
 	ws, err = executeMerge(ctx, spec.Squash, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts())
+	if err == doltdb.ErrUnresolvedConflictsOrViolations && resolve != nil && !strategies.IsEmpty() {
+		ws, err = resolveMergeConflicts(ctx, sess, dbName, ws, strategies, resolve)
+	}
 	if err == doltdb.ErrUnresolvedConflictsOrViolations {
 		// if there are unresolved conflicts, write the resulting working set back to the session and return an
 		// error message
@@ -233,6 +286,53 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 	return ws, noConflictsOrViolations, threeWayMerge, nil
 }
 
+// resolveMergeConflicts resolves the conflicts of the merge in the working set given with |resolve|, staging the
+// result. Returns ErrUnresolvedConflictsOrViolations if any conflicts or constraint violations remain, along with the
+// new working set.
+func resolveMergeConflicts(ctx *sql.Context, sess *dsess.DoltSession, dbName string, ws *doltdb.WorkingSet, strategies MergeStrategies, resolve MergeConflictResolver) (*doltdb.WorkingSet, error) {
+	err := sess.SetWorkingSet(ctx, dbName, ws)
+	if err != nil {
+		return nil, err
+	}
+	ws, err = resolve(ctx, dbName, ws, strategies)
+	if err != nil {
+		return nil, err
+	}
+	ws = ws.WithStagedRoot(ws.WorkingRoot())
+
+	if ws.MergeState().HasSchemaConflicts() {
+		return ws, doltdb.ErrUnresolvedConflictsOrViolations
+	}
+	if conflicted, err := ws.WorkingRoot().TablesInConflict(ctx); err != nil {
+		return nil, err
+	} else if len(conflicted) > 0 {
+		return ws, doltdb.ErrUnresolvedConflictsOrViolations
+	}
+	if violated, err := ws.WorkingRoot().TablesWithConstraintViolations(ctx); err != nil {
+		return nil, err
+	} else if len(violated) > 0 {
+		return ws, doltdb.ErrUnresolvedConflictsOrViolations
+	}
+	return ws, nil
+}
+
+// parseMergeStrategies returns the merge strategies given by the --strategy and --table-strategy arguments.
+func parseMergeStrategies(apr *argparser.ArgParseResults) (MergeStrategies, error) {
+	strategies := MergeStrategies{Tables: make(map[string]string)}
+	strategies.Default, _ = apr.GetValue(cli.StrategyParam)
+	for _, tableStrategy := range apr.GetValueList(cli.TableStrategyParam) {
+		tblName, strategy, ok := strings.Cut(tableStrategy, "=")
+		if !ok || tblName == "" {
+			return MergeStrategies{}, fmt.Errorf("error: invalid --%s '%s'; expected table=strategy", cli.TableStrategyParam, tableStrategy)
+		}
+		if strategy != cli.OursStrategy && strategy != cli.TheirsStrategy && strategy != cli.UnionStrategy {
+			return MergeStrategies{}, fmt.Errorf("error: invalid strategy '%s' for table %s; valid strategies are %s", strategy, tblName, strings.Join(cli.MergeStrategies, ", "))
+		}
+		strategies.Tables[tblName] = strategy
+	}
+	return strategies, nil
+}
+
 func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Roots) (*doltdb.WorkingSet, error) {
 	tbls, err := doltdb.UnionTableNames(ctx, roots.Working, roots.Staged, roots.Head)
 	if err != nil {
@@ -250,7 +350,9 @@ func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Ro
 }
 
 func executeMerge(ctx *sql.Context, squash bool, head, cm *doltdb.Commit, cmSpec string, ws *doltdb.WorkingSet, opts editor.Options) (*doltdb.WorkingSet, error) {
-	mergeRoot, mergeStats, err := merge.MergeCommits(ctx, head, cm, opts)
+	// Schema conflicts are recorded in the merge state, which squash merges don't have
+	mergeOpts := merge.MergeOpts{KeepSchemaConflicts: !squash}
+	mergeRoot, mergeStats, err := merge.MergeCommits(ctx, head, cm, opts, mergeOpts)
 
 	if err != nil {
 		switch err {
@@ -395,6 +497,13 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 	if apr.Contains(cli.NoCommitFlag) && apr.Contains(cli.CommitFlag) {
 		return nil, errors.New("cannot define both 'commit' and 'no-commit' flags at the same time")
 	}
+	mergeCS, err := doltdb.NewCommitSpec(commitSpecStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dsess.ResolveReadableCommit(ctx, ddb, mergeCS, dbData.Rsr.CWBHeadRef()); err != nil {
+		return nil, err
+	}
 	mergeSpec, err := merge.NewMergeSpec(ctx, dbData.Rsr, ddb, roots, name, email, msg, commitSpecStr, apr.Contains(cli.SquashParam), apr.Contains(cli.NoFFParam), apr.Contains(cli.ForceFlag), apr.Contains(cli.NoCommitFlag), apr.Contains(cli.NoEditFlag), t)
 	if err != nil {
 		return nil, err
@@ -417,8 +526,13 @@ func mergeRootToWorking(
 		ws = ws.StartMerge(cm2, cm2Spec)
 	}
 
+	unmergableTables := tablesWithSchemaConflicts(mergeStats)
+	if len(unmergableTables) > 0 {
+		ws = ws.WithMergeState(ws.MergeState().WithUnmergableTables(unmergableTables))
+	}
+
 	ws = ws.WithWorkingRoot(workingRoot).WithStagedRoot(workingRoot)
-	if checkForConflicts(mergeStats) || checkForViolations(mergeStats) {
+	if len(unmergableTables) > 0 || checkForConflicts(mergeStats) || checkForViolations(mergeStats) {
 		// this error is recoverable in-session, so we return the new ws along with the error
 		return ws, doltdb.ErrUnresolvedConflictsOrViolations
 	}
@@ -455,6 +569,18 @@ func checkForConflicts(tblToStats map[string]*merge.MergeStats) bool {
 	return false
 }
 
+// tablesWithSchemaConflicts returns the sorted names of the tables whose schemas could not be merged.
+func tablesWithSchemaConflicts(tblToStats map[string]*merge.MergeStats) []string {
+	var tables []string
+	for tblName, stats := range tblToStats {
+		if stats.SchemaConflicts > 0 {
+			tables = append(tables, tblName)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
 func checkForViolations(tblToStats map[string]*merge.MergeStats) bool {
 	for _, stats := range tblToStats {
 		if stats.ConstraintViolations > 0 {
@@ -93,11 +93,11 @@ func resolveRefSpecs(ctx *sql.Context, leftSpec, rightSpec string) (left, right
 		return nil, nil, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	left, err = doltDB.Resolve(ctx, lcs, dbData.Rsr.CWBHeadRef())
+	left, err = dsess.ResolveReadableCommit(ctx, doltDB, lcs, dbData.Rsr.CWBHeadRef())
 	if err != nil {
 		return nil, nil, err
 	}
-	right, err = doltDB.Resolve(ctx, rcs, dbData.Rsr.CWBHeadRef())
+	right, err = dsess.ResolveReadableCommit(ctx, doltDB, rcs, dbData.Rsr.CWBHeadRef())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -134,6 +134,18 @@ func DoDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 			fmt.Errorf("branch %q not found on remote", pullSpec.Branch.GetPath())
 	}
 
+	// A pull merges into the current branch, so a ref that is outside of the user's namespaces fails the entire pull
+	// before anything has been fetched, rather than being skipped
+	for _, refSpec := range pullSpec.RefSpecs {
+		for _, branchRef := range branchRefs {
+			if remoteTrackRef := refSpec.DestRef(branchRef); remoteTrackRef != nil {
+				if err = actions.CanCreateFetchDestRef(ctx, dbData.Ddb, remoteTrackRef); err != nil {
+					return noConflictsOrViolations, threeWayMerge, err
+				}
+			}
+		}
+	}
+
 	var conflicts int
 	var fastForward int
 	for _, refSpec := range pullSpec.RefSpecs {
@@ -178,7 +190,7 @@ func DoDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 			}
 
 			msg := fmt.Sprintf("Merge branch '%s' of %s into %s", pullSpec.Branch.GetPath(), pullSpec.Remote.Url, dbData.Rsr.CWBHeadRef().GetPath())
-			ws, conflicts, fastForward, err = performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
+			ws, conflicts, fastForward, err = performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg, MergeStrategies{}, nil)
 			if err != nil && !errors.Is(doltdb.ErrUpToDate, err) {
 				return conflicts, fastForward, err
 			}
@@ -21,8 +21,11 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -87,6 +90,13 @@ func DoDoltReset(ctx *sql.Context, args []string) (int, error) {
 	}
 
 	if apr.Contains(cli.HardResetParam) {
+		// A hard reset may move the branch's head, so it's checked as a ref move rather than a direct modification
+		headRef := dbData.Rsr.CWBHeadRef()
+		requiresApproval, err := branch_control.CheckRefMove(ctx, headRef.GetPath())
+		if err != nil {
+			return 1, err
+		}
+
 		// Get the commitSpec for the branch if it exists
 		arg := ""
 		if apr.NArg() > 1 {
@@ -101,9 +111,18 @@ func DoDoltReset(ctx *sql.Context, args []string) (int, error) {
 			return 1, err
 		}
 
+		if newHead != nil && requiresApproval {
+			proposed, err := proposeHeadMove(ctx, dbData, headRef, newHead)
+			if err != nil {
+				return 1, err
+			} else if proposed {
+				return 0, nil
+			}
+		}
+
 		// TODO: this overrides the transaction setting, needs to happen at commit, not here
 		if newHead != nil {
-			if err := dbData.Ddb.SetHeadToCommit(ctx, dbData.Rsr.CWBHeadRef(), newHead); err != nil {
+			if err := moveHeadForReset(ctx, dbData, headRef, newHead); err != nil {
 				return 1, err
 			}
 		}
@@ -116,6 +135,42 @@ func DoDoltReset(ctx *sql.Context, args []string) (int, error) {
 		if err != nil {
 			return 1, err
 		}
+	} else if apr.NArg() == 1 && actions.ValidateIsRef(ctx, apr.Arg(0), dbData.Ddb, dbData.Rsr) {
+		// A reset to a commit moves the branch's head to it and stages its root, leaving the working root as it is
+		headRef := dbData.Rsr.CWBHeadRef()
+		requiresApproval, err := branch_control.CheckRefMove(ctx, headRef.GetPath())
+		if err != nil {
+			return 1, err
+		}
+
+		cs, err := doltdb.NewCommitSpec(apr.Arg(0))
+		if err != nil {
+			return 1, err
+		}
+		newHead, err := dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, headRef)
+		if err != nil {
+			return 1, err
+		}
+		if requiresApproval {
+			proposed, err := proposeHeadMove(ctx, dbData, headRef, newHead)
+			if err != nil {
+				return 1, err
+			} else if proposed {
+				return 0, nil
+			}
+		}
+
+		roots.Staged, err = newHead.GetRootValue(ctx)
+		if err != nil {
+			return 1, err
+		}
+		if err = moveHeadForReset(ctx, dbData, headRef, newHead); err != nil {
+			return 1, err
+		}
+		err = dSess.SetRoots(ctx, dbName, roots)
+		if err != nil {
+			return 1, err
+		}
 	} else {
 		roots, err = actions.ResetSoftTables(ctx, dbData, apr, roots)
 		if err != nil {
@@ -131,6 +186,44 @@ func DoDoltReset(ctx *sql.Context, args []string) (int, error) {
 	return 0, nil
 }
 
+// moveHeadForReset moves the given branch's head to |newHead|, first recording the head it moves from in the reflog,
+// so that a reset to an earlier commit can be undone by resetting to the commit found there.
+func moveHeadForReset(ctx *sql.Context, dbData env.DbData, headRef ref.DoltRef, newHead *doltdb.Commit) error {
+	if err := dbData.Ddb.RecordReflogHead(ctx, headRef); err != nil {
+		return err
+	}
+	return dbData.Ddb.SetHeadToCommit(ctx, headRef, newHead)
+}
+
+// proposeHeadMove records the move of the given branch's head to |newHead| as a pending move, rather than applying it,
+// for branches whose moves require approval. Returns false if the head would not move, as there's nothing to approve.
+func proposeHeadMove(ctx *sql.Context, dbData env.DbData, headRef ref.DoltRef, newHead *doltdb.Commit) (bool, error) {
+	head, err := dbData.Ddb.ResolveCommitRef(ctx, headRef)
+	if err != nil {
+		return false, err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return false, err
+	}
+	newHeadHash, err := newHead.HashOf()
+	if err != nil {
+		return false, err
+	}
+	if headHash == newHeadHash {
+		return false, nil
+	}
+
+	if err = branch_control.ProposeMove(ctx, headRef.GetPath(), newHeadHash.String()); err != nil {
+		return false, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return false, err
+	}
+	ctx.Warn(1105, "branch `%s` requires approval to move its head, so the move to `%s` was proposed instead", headRef.GetPath(), newHeadHash.String())
+	return true, nil
+}
+
 func (d DoltResetFunc) Resolved() bool {
 	for _, child := range d.Children() {
 		if !child.Resolved() {
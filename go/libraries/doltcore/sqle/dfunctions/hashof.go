@@ -80,7 +80,7 @@ func (t *HashOf) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 			return nil, err
 		}
 
-		cm, err = ddb.ResolveCommitRef(ctx, ref)
+		cm, err = dsess.ResolveReadableCommitRef(ctx, ddb, ref)
 		if err != nil {
 			return nil, err
 		}
@@ -37,6 +37,8 @@ var DoltFunctions = []sql.Function{
 	sql.FunctionN{Name: DoltPushFuncName, Fn: NewPushFunc},
 	sql.FunctionN{Name: DoltBranchFuncName, Fn: NewDoltBranchFunc},
 	sql.FunctionN{Name: DoltBackupFuncName, Fn: NewDoltBackupFunc},
+	sql.FunctionN{Name: DoltBranchPermissionsFuncName, Fn: NewBranchPermissionsFunc},
+	sql.FunctionN{Name: DoltBranchControlCheckFuncName, Fn: NewBranchControlCheckFunc},
 }
 
 // DolthubApiFunctions are the DoltFunctions that get exposed to Dolthub Api.
@@ -23,8 +23,8 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
-	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 )
 
 const (
@@ -96,17 +96,18 @@ func DoDoltRevert(ctx *sql.Context, row sql.Row, args []string) (int, error) {
 		return 1, err
 	}
 
-	commits := make([]*doltdb.Commit, apr.NArg())
-	for i, revisionStr := range apr.Args {
-		commitSpec, err := doltdb.NewCommitSpec(revisionStr)
-		if err != nil {
-			return 1, err
-		}
-		commit, err := ddb.Resolve(ctx, commitSpec, headRef)
-		if err != nil {
+	commits, err := merge.ResolveRevertCommits(ctx, ddb, headRef, apr.Args)
+	if err != nil {
+		return 1, err
+	}
+	for _, cm := range commits {
+		if err = dsess.CheckReadableCommit(ctx, ddb, cm); err != nil {
 			return 1, err
 		}
-		commits[i] = commit
+	}
+	mainline := apr.GetIntOrDefault(cli.MainlineParam, 0)
+	if err = merge.CheckRevertCommits(commits, mainline); err != nil {
+		return 1, err
 	}
 
 	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
@@ -116,33 +117,20 @@ func DoDoltRevert(ctx *sql.Context, row sql.Row, args []string) (int, error) {
 		return 1, fmt.Errorf("Could not load database %s", dbName)
 	}
 
-	workingRoot, revertMessage, err := merge.Revert(ctx, ddb, workingRoot, headCommit, commits, dbState.EditOpts())
-	if err != nil {
-		return 1, err
-	}
-	workingHash, err = workingRoot.HashOf()
-	if err != nil {
-		return 1, err
+	var commitArgs []string
+	if author, ok := apr.GetValue(cli.AuthorParam); ok {
+		commitArgs = append(commitArgs, "--author", author)
 	}
-	if !headHash.Equal(workingHash) {
-		err = dSess.SetRoot(ctx, dbName, workingRoot)
-		if err != nil {
-			return 1, err
-		}
-		stringType := typeinfo.StringDefaultType.ToSqlType()
 
-		expressions := []sql.Expression{expression.NewLiteral("-a", stringType), expression.NewLiteral("-m", stringType), expression.NewLiteral(revertMessage, stringType)}
-
-		author, hasAuthor := apr.GetValue(cli.AuthorParam)
-		if hasAuthor {
-			expressions = append(expressions, expression.NewLiteral("--author", stringType), expression.NewLiteral(author, stringType))
-		}
-
-		commitFunc, err := NewDoltCommitFunc(expressions...)
+	if apr.Contains(cli.SquashParam) {
+		err = revertAndCommit(ctx, dSess, ddb, dbName, commits, mainline, dbState.EditOpts(), commitArgs)
 		if err != nil {
 			return 1, err
 		}
-		_, err = commitFunc.Eval(ctx, row)
+		return 0, nil
+	}
+	for _, commit := range commits {
+		err = revertAndCommit(ctx, dSess, ddb, dbName, []*doltdb.Commit{commit}, mainline, dbState.EditOpts(), commitArgs)
 		if err != nil {
 			return 1, err
 		}
@@ -150,6 +138,54 @@ func DoDoltRevert(ctx *sql.Context, row sql.Row, args []string) (int, error) {
 	return 0, nil
 }
 
+// revertAndCommit reverts |commits| in the working set of the current branch, and commits the result with a message
+// naming the commits reverted. Nothing is committed when reverting the commits changes nothing.
+func revertAndCommit(ctx *sql.Context, dSess *dsess.DoltSession, ddb *doltdb.DoltDB, dbName string, commits []*doltdb.Commit, mainline int, opts editor.Options, commitArgs []string) error {
+	// committing ends the session's transaction, so another is started for each commit after the first
+	if ctx.GetTransaction() == nil {
+		tx, err := dSess.StartTransaction(ctx, dbName, sql.ReadWrite)
+		if err != nil {
+			return err
+		}
+		ctx.SetTransaction(tx)
+	}
+
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	headHash, err := headRoot.HashOf()
+	if err != nil {
+		return err
+	}
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	workingRoot, revertMessage, err := merge.Revert(ctx, ddb, roots.Working, headCommit, commits, mainline, opts)
+	if err != nil {
+		return err
+	}
+	workingHash, err := workingRoot.HashOf()
+	if err != nil {
+		return err
+	}
+	if headHash.Equal(workingHash) {
+		return nil
+	}
+
+	if err = dSess.SetRoot(ctx, dbName, workingRoot); err != nil {
+		return err
+	}
+	_, err = DoDoltCommit(ctx, append([]string{"-a", "-m", revertMessage}, commitArgs...))
+	return err
+}
+
 // String implements the Stringer interface.
 func (r *RevertFunc) String() string {
 	return fmt.Sprint("DOLT_REVERT()")
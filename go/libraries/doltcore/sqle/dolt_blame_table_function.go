@@ -0,0 +1,326 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var _ sql.TableFunction = (*BlameTableFunction)(nil)
+
+// blameCommitColumns are the columns that follow the primary key columns of the blamed table in the schema of the
+// dolt_blame table function, and match the columns of the dolt_blame_<table> system views.
+var blameCommitColumns = sql.Schema{
+	&sql.Column{Name: "commit", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "commit_date", Type: sql.Datetime, Nullable: false},
+	&sql.Column{Name: "committer", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "email", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "message", Type: sql.Text, Nullable: false},
+}
+
+// BlameTableFunction is the dolt_blame table function, which returns the primary key of each row of a table along
+// with the commit that last changed the row. The table is blamed as of HEAD, or as of the revision given before the
+// table name.
+type BlameTableFunction struct {
+	ctx *sql.Context
+
+	revisionExpr  sql.Expression
+	tableNameExpr sql.Expression
+	database      sql.Database
+
+	sqlSch    sql.Schema
+	start     *doltdb.Commit
+	tableName string
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (btf *BlameTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &BlameTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (btf *BlameTableFunction) Database() sql.Database {
+	return btf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (btf *BlameTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	btf.database = database
+	return btf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (btf *BlameTableFunction) FunctionName() string {
+	return "dolt_blame"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (btf *BlameTableFunction) Resolved() bool {
+	if btf.revisionExpr != nil && !btf.revisionExpr.Resolved() {
+		return false
+	}
+	return btf.tableNameExpr.Resolved()
+}
+
+// String implements the Stringer interface
+func (btf *BlameTableFunction) String() string {
+	if btf.revisionExpr != nil {
+		return fmt.Sprintf("DOLT_BLAME(%s, %s)", btf.revisionExpr.String(), btf.tableNameExpr.String())
+	}
+	return fmt.Sprintf("DOLT_BLAME(%s)", btf.tableNameExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (btf *BlameTableFunction) Schema() sql.Schema {
+	if !btf.Resolved() {
+		return nil
+	}
+
+	if btf.sqlSch == nil {
+		panic("schema hasn't been generated yet")
+	}
+
+	return btf.sqlSch
+}
+
+// Children implements the sql.Node interface.
+func (btf *BlameTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (btf *BlameTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return btf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (btf *BlameTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(btf.database.Name(), btf.tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (btf *BlameTableFunction) Expressions() []sql.Expression {
+	if btf.revisionExpr != nil {
+		return []sql.Expression{btf.revisionExpr, btf.tableNameExpr}
+	}
+	return []sql.Expression{btf.tableNameExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (btf *BlameTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) < 1 || len(expressions) > 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(btf.FunctionName(), "1 or 2", len(expressions))
+	}
+
+	// The schema depends on the table, so only literal / fully-resolved arguments are supported, as with dolt_diff
+	for _, expr := range expressions {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(btf.FunctionName(), expr.String())
+		}
+		if !sql.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(btf.FunctionName(), expr.String())
+		}
+	}
+
+	btf.revisionExpr = nil
+	btf.tableNameExpr = expressions[len(expressions)-1]
+	if len(expressions) == 2 {
+		btf.revisionExpr = expressions[0]
+	}
+
+	err := btf.generateSchema(btf.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return btf, nil
+}
+
+// generateSchema resolves the commit that the table is blamed as of, and generates the schema from the primary key of
+// the table at that commit.
+func (btf *BlameTableFunction) generateSchema(ctx *sql.Context) error {
+	sqledb, ok := btf.database.(Database)
+	if !ok {
+		return fmt.Errorf("unexpected database type: %T", btf.database)
+	}
+
+	tableName, err := btf.evaluateText(ctx, btf.tableNameExpr)
+	if err != nil {
+		return err
+	}
+
+	var start *doltdb.Commit
+	if btf.revisionExpr == nil {
+		start, _, err = logDatabaseHead(ctx, sqledb)
+	} else {
+		var revision string
+		revision, err = btf.evaluateText(ctx, btf.revisionExpr)
+		if err != nil {
+			return err
+		}
+		var cs *doltdb.CommitSpec
+		cs, err = doltdb.NewCommitSpec(revision)
+		if err != nil {
+			return err
+		}
+		start, err = dsess.ResolveReadableCommit(ctx, sqledb.ddb, cs, getCheckedOutBranch(ctx, sqledb.Name()))
+	}
+	if err != nil {
+		return err
+	}
+
+	root, err := start.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	tbl, name, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	// As with dolt_diff, the columns have no source, since they don't come from a real table
+	pkSch, err := sqlutil.FromDoltSchema("", sch)
+	if err != nil {
+		return err
+	}
+	sqlSch := make(sql.Schema, 0, len(pkSch.PkOrdinals)+len(blameCommitColumns))
+	for _, ord := range pkSch.PkOrdinals {
+		sqlSch = append(sqlSch, pkSch.Schema[ord])
+	}
+
+	btf.sqlSch = append(sqlSch, blameCommitColumns...)
+	btf.start = start
+	btf.tableName = name
+
+	return nil
+}
+
+// evaluateText evaluates the argument given, which must be a non-empty string.
+func (btf *BlameTableFunction) evaluateText(ctx *sql.Context, expr sql.Expression) (string, error) {
+	v, err := expr.Eval(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return "", sql.ErrInvalidArgumentDetails.New(btf.FunctionName(), expr.String())
+	}
+	return s, nil
+}
+
+// RowIter implements the sql.Node interface
+func (btf *BlameTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	blame, err := diff.BlameTable(ctx, btf.start, btf.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := blame.Rows.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyDesc, _ := blame.Rows.Descriptors()
+
+	return &blameRowIter{
+		blame:   blame,
+		iter:    iter,
+		keyDesc: keyDesc,
+		metas:   make(map[hash.Hash]*datas.CommitMeta),
+	}, nil
+}
+
+var _ sql.RowIter = (*blameRowIter)(nil)
+
+// blameRowIter iterates over the rows of a blamed table in primary key order, returning the primary key of each row
+// along with the commit it was blamed on.
+type blameRowIter struct {
+	blame   *diff.TableBlame
+	iter    prolly.MapIter
+	keyDesc val.TupleDesc
+	metas   map[hash.Hash]*datas.CommitMeta
+}
+
+// Next implements the sql.RowIter interface
+func (itr *blameRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	key, _, err := itr.iter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(sql.Row, itr.keyDesc.Count(), itr.keyDesc.Count()+len(blameCommitColumns))
+	for i := range row {
+		row[i], err = index.GetField(ctx, itr.keyDesc, i, key, itr.blame.Rows.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commit := itr.blame.Origin(key)
+	if commit == nil {
+		return nil, fmt.Errorf("unable to find the commit that last changed a row of %s", itr.blame.Schema.GetPKCols().GetColumnNames())
+	}
+	h, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := itr.metas[h]
+	if !ok {
+		meta, err = commit.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		itr.metas[h] = meta
+	}
+
+	return append(row, h.String(), meta.Time(), meta.Name, meta.Email, meta.Description), nil
+}
+
+// Close implements the sql.RowIter interface
+func (itr *blameRowIter) Close(*sql.Context) error {
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	blobstoreCheckMissing  = "missing"
+	blobstoreCheckOrphaned = "orphaned"
+	blobstoreCheckTotal    = "total"
+)
+
+var _ sql.TableFunction = (*BlobstoreCheckTableFunction)(nil)
+
+// BlobstoreCheckTableFunction is the dolt_blobstore_check table function, which compares the manifest of a
+// blobstore-backed database with the objects in its bucket. Each row belongs to one of three categories: "missing" for
+// referenced table files that are absent or implausibly small, "orphaned" for objects that the manifest doesn't
+// reference, and a single "total" row with the number of bytes that the manifest accounts for.
+type BlobstoreCheckTableFunction struct {
+	ctx *sql.Context
+
+	database sql.Database
+}
+
+var blobstoreCheckTableFunctionSchema = sql.Schema{
+	&sql.Column{Name: "category", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "object", Type: sql.Text, Nullable: true},
+	&sql.Column{Name: "size", Type: sql.Int64, Nullable: true},
+	&sql.Column{Name: "last_modified", Type: sql.Datetime, Nullable: true},
+	&sql.Column{Name: "age_seconds", Type: sql.Int64, Nullable: true},
+	&sql.Column{Name: "detail", Type: sql.Text, Nullable: false},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (bctf *BlobstoreCheckTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &BlobstoreCheckTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (bctf *BlobstoreCheckTableFunction) Database() sql.Database {
+	return bctf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (bctf *BlobstoreCheckTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	bctf.database = database
+	return bctf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (bctf *BlobstoreCheckTableFunction) FunctionName() string {
+	return "dolt_blobstore_check"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (bctf *BlobstoreCheckTableFunction) Resolved() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (bctf *BlobstoreCheckTableFunction) String() string {
+	return "DOLT_BLOBSTORE_CHECK()"
+}
+
+// Schema implements the sql.Node interface.
+func (bctf *BlobstoreCheckTableFunction) Schema() sql.Schema {
+	return blobstoreCheckTableFunctionSchema
+}
+
+// Children implements the sql.Node interface.
+func (bctf *BlobstoreCheckTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (bctf *BlobstoreCheckTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return bctf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node. The check lists every object in the bucket, including those
+// that no database references, so it's limited to users with the global SUPER privilege.
+func (bctf *BlobstoreCheckTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_Super))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (bctf *BlobstoreCheckTableFunction) Expressions() []sql.Expression {
+	return nil
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (bctf *BlobstoreCheckTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New(bctf.FunctionName(), 0, len(expressions))
+	}
+	return bctf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (bctf *BlobstoreCheckTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := bctf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", bctf.database)
+	}
+
+	cs := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(sqledb.ddb))
+	store, ok := cs.(*nbs.NomsBlockStore)
+	if !ok {
+		return nil, fmt.Errorf("database %s is not backed by a blobstore", sqledb.Name())
+	}
+	bs, ok := store.Blobstore()
+	if !ok {
+		return nil, fmt.Errorf("database %s is not backed by a blobstore", sqledb.Name())
+	}
+
+	report, err := nbs.CheckBlobstoreConsistency(ctx, bs)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(blobstoreCheckRows(report, time.Now())...), nil
+}
+
+// blobstoreCheckRows converts |report| into the rows of dolt_blobstore_check, with the ages of orphaned objects
+// measured from |now|.
+func blobstoreCheckRows(report nbs.BlobstoreConsistencyReport, now time.Time) []sql.Row {
+	rows := make([]sql.Row, 0, len(report.Missing)+len(report.Orphaned)+1)
+	for _, missing := range report.Missing {
+		if missing.Exists {
+			rows = append(rows, sql.Row{
+				blobstoreCheckMissing,
+				missing.Name,
+				missing.Size,
+				nil,
+				nil,
+				fmt.Sprintf("table file with %d chunks must be at least %d bytes", missing.ChunkCount, missing.MinSize),
+			})
+		} else {
+			rows = append(rows, sql.Row{
+				blobstoreCheckMissing,
+				missing.Name,
+				nil,
+				nil,
+				nil,
+				fmt.Sprintf("table file with %d chunks does not exist", missing.ChunkCount),
+			})
+		}
+	}
+
+	for _, orphan := range report.Orphaned {
+		var lastModified, age interface{}
+		if !orphan.LastModified.IsZero() {
+			lastModified = orphan.LastModified
+			age = int64(now.Sub(orphan.LastModified) / time.Second)
+		}
+		rows = append(rows, sql.Row{
+			blobstoreCheckOrphaned,
+			orphan.Key,
+			orphan.Size,
+			lastModified,
+			age,
+			"object is not referenced by the manifest",
+		})
+	}
+
+	rows = append(rows, sql.Row{
+		blobstoreCheckTotal,
+		nil,
+		report.AccountedBytes,
+		nil,
+		nil,
+		fmt.Sprintf("bytes referenced by the manifest, with %d orphaned bytes", report.OrphanedBytes),
+	})
+	return rows
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBlobstoreCheckTableFunction(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	cs, err := nbs.NewBSStore(ctx, types.Format_Default.VersionString(), bs, 1<<20, nbs.NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	ddb := doltdb.DoltDBFromCS(cs)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "name", "name@fake.horse"))
+	_, err = blobstore.PutBytes(ctx, bs, "orphan", make([]byte, 10))
+	require.NoError(t, err)
+
+	sqlCtx := sql.NewEmptyContext()
+	bctf := &BlobstoreCheckTableFunction{}
+	node, err := bctf.NewInstance(sqlCtx, Database{name: "bs", ddb: ddb}, nil)
+	require.NoError(t, err)
+	iter, err := node.RowIter(sqlCtx, nil)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(sqlCtx, nil, iter)
+	require.NoError(t, err)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, sql.Row{blobstoreCheckOrphaned, "orphan", int64(10)}, rows[0][:3])
+	assert.Equal(t, blobstoreCheckTotal, rows[1][0])
+	assert.Positive(t, rows[1][2])
+
+	// databases that aren't backed by a blobstore have nothing to check
+	dEnv := createLogEnvWithCommits(t, nil)
+	_, err = executeLogQueryErr(t, dEnv, "SELECT * FROM dolt_blobstore_check();")
+	assert.ErrorContains(t, err, "is not backed by a blobstore")
+
+	_, err = executeLogQueryErr(t, dEnv, "SELECT * FROM dolt_blobstore_check('main');")
+	assert.True(t, sql.ErrInvalidArgumentNumber.Is(err))
+}
+
+func TestBlobstoreCheckRows(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	report := nbs.BlobstoreConsistencyReport{
+		Missing: []nbs.MissingTableFile{
+			{Name: "truncated", ChunkCount: 2, Exists: true, Size: 10, MinSize: 70},
+			{Name: "absent", ChunkCount: 1, MinSize: 45},
+		},
+		Orphaned: []blobstore.BlobInfo{
+			{Key: "orphan", Size: 100, LastModified: now.Add(-time.Hour)},
+		},
+		AccountedBytes: 1000,
+		OrphanedBytes:  100,
+	}
+
+	assert.Equal(t, []sql.Row{
+		{blobstoreCheckMissing, "truncated", int64(10), nil, nil, "table file with 2 chunks must be at least 70 bytes"},
+		{blobstoreCheckMissing, "absent", nil, nil, nil, "table file with 1 chunks does not exist"},
+		{blobstoreCheckOrphaned, "orphan", int64(100), now.Add(-time.Hour), int64(3600), "object is not referenced by the manifest"},
+		{blobstoreCheckTotal, nil, int64(1000), nil, nil, "bytes referenced by the manifest, with 100 orphaned bytes"},
+	}, blobstoreCheckRows(report, now))
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+)
+
+var _ sql.TableFunction = (*BranchControlMatchesTableFunction)(nil)
+
+var branchControlMatchesTableFunctionSchema = sql.Schema{
+	&sql.Column{Name: "type", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "name", Type: sql.Text, Nullable: false},
+}
+
+// BranchControlMatchesTableFunction is the dolt_branch_control_matches table function, which returns every existing
+// branch that a match expression would cover as a branch expression, followed by every user that it would cover as a
+// user expression, so that a pattern may be validated before it is inserted into the branch control tables.
+type BranchControlMatchesTableFunction struct {
+	ctx *sql.Context
+
+	patternExpr sql.Expression
+
+	database sql.Database
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (bmtf *BranchControlMatchesTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &BranchControlMatchesTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (bmtf *BranchControlMatchesTableFunction) Database() sql.Database {
+	return bmtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (bmtf *BranchControlMatchesTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	bmtf.database = database
+	return bmtf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (bmtf *BranchControlMatchesTableFunction) FunctionName() string {
+	return "dolt_branch_control_matches"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (bmtf *BranchControlMatchesTableFunction) Resolved() bool {
+	return bmtf.patternExpr.Resolved()
+}
+
+// String implements the Stringer interface
+func (bmtf *BranchControlMatchesTableFunction) String() string {
+	return fmt.Sprintf("DOLT_BRANCH_CONTROL_MATCHES(%s)", bmtf.patternExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (bmtf *BranchControlMatchesTableFunction) Schema() sql.Schema {
+	return branchControlMatchesTableFunctionSchema
+}
+
+// Children implements the sql.Node interface.
+func (bmtf *BranchControlMatchesTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (bmtf *BranchControlMatchesTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return bmtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (bmtf *BranchControlMatchesTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := bmtf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(bmtf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (bmtf *BranchControlMatchesTableFunction) Expressions() []sql.Expression {
+	return []sql.Expression{bmtf.patternExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (bmtf *BranchControlMatchesTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(bmtf.FunctionName(), 1, len(expressions))
+	}
+	// The pattern is only evaluated in RowIter, so it may be any text expression
+	if expressions[0].Resolved() && !sql.IsText(expressions[0].Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(bmtf.FunctionName(), expressions[0].String())
+	}
+	bmtf.patternExpr = expressions[0]
+
+	return bmtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (bmtf *BranchControlMatchesTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := bmtf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", bmtf.database)
+	}
+
+	val, err := bmtf.patternExpr.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	pattern, ok := val.(string)
+	if !ok {
+		return nil, sql.ErrInvalidArgumentDetails.New(bmtf.FunctionName(), bmtf.patternExpr.String())
+	}
+
+	branchRefs, err := sqledb.ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branches := make([]string, len(branchRefs))
+	for i, branchRef := range branchRefs {
+		branches[i] = branchRef.GetPath()
+	}
+
+	var rows []sql.Row
+	for _, branch := range branch_control.ExpandBranches(pattern, branches) {
+		rows = append(rows, sql.Row{"branch", branch})
+	}
+	for _, user := range branch_control.StaticController.ExpandUsers(pattern) {
+		rows = append(rows, sql.Row{"user", user})
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,314 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var _ sql.TableFunction = (*BranchStatusTableFunction)(nil)
+
+// BranchStatusTableFunction is the dolt_branch_status table function, which returns how far each branch is ahead of
+// and behind a base ref. Remote tracking branches are included when --remotes is given.
+type BranchStatusTableFunction struct {
+	ctx *sql.Context
+
+	argumentExprs []sql.Expression
+
+	database sql.Database
+}
+
+var branchStatusTableFunctionSchema = sql.Schema{
+	&sql.Column{Name: "branch", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "head_hash", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "commits_ahead", Type: sql.Int64, Nullable: true},
+	&sql.Column{Name: "commits_behind", Type: sql.Int64, Nullable: true},
+	&sql.Column{Name: "last_committer", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "last_commit_date", Type: sql.Datetime, Nullable: false},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (btf *BranchStatusTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &BranchStatusTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (btf *BranchStatusTableFunction) Database() sql.Database {
+	return btf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (btf *BranchStatusTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	btf.database = database
+	return btf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (btf *BranchStatusTableFunction) FunctionName() string {
+	return "dolt_branch_status"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (btf *BranchStatusTableFunction) Resolved() bool {
+	for _, expr := range btf.argumentExprs {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (btf *BranchStatusTableFunction) String() string {
+	args := make([]string, len(btf.argumentExprs))
+	for i, expr := range btf.argumentExprs {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_BRANCH_STATUS(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (btf *BranchStatusTableFunction) Schema() sql.Schema {
+	return branchStatusTableFunctionSchema
+}
+
+// Children implements the sql.Node interface.
+func (btf *BranchStatusTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (btf *BranchStatusTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return btf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (btf *BranchStatusTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := btf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(btf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (btf *BranchStatusTableFunction) Expressions() []sql.Expression {
+	return btf.argumentExprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (btf *BranchStatusTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) < 1 || len(expressions) > 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(btf.FunctionName(), "1 or 2", len(expressions))
+	}
+
+	// The arguments are only evaluated in RowIter, so they may be any text expressions
+	for _, expr := range expressions {
+		if expr.Resolved() && !sql.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(btf.FunctionName(), expr.String())
+		}
+	}
+	btf.argumentExprs = expressions
+
+	return btf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (btf *BranchStatusTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := btf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", btf.database)
+	}
+
+	args, err := getDoltArgs(ctx, row, btf.argumentExprs, btf.FunctionName())
+	if err != nil {
+		return nil, err
+	}
+	apr, err := cli.CreateBranchStatusArgParser().Parse(args)
+	if err != nil {
+		return nil, sql.ErrInvalidArgumentDetails.New(btf.FunctionName(), err.Error())
+	}
+	if apr.NArg() != 1 {
+		return nil, sql.ErrInvalidArgumentDetails.New(btf.FunctionName(), "a single base ref is required")
+	}
+
+	cs, err := doltdb.NewCommitSpec(apr.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	base, err := dsess.ResolveReadableCommit(ctx, sqledb.ddb, cs, getCheckedOutBranch(ctx, sqledb.Name()))
+	if err != nil {
+		return nil, err
+	}
+	baseHash, err := base.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []branchStatusRef
+	localBranches, err := sqledb.ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range localBranches {
+		if dsess.IsHiddenRef(ctx, b.Ref) {
+			continue
+		}
+		branches = append(branches, branchStatusRef{ref: b.Ref, hash: b.Hash})
+	}
+	if apr.Contains(cli.RemotesFlag) {
+		remoteBranches, err := sqledb.ddb.GetRemotesWithHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range remoteBranches {
+			branches = append(branches, branchStatusRef{ref: b.Ref, hash: b.Hash})
+		}
+	}
+
+	return &branchStatusTableFunctionRowIter{ddb: sqledb.ddb, base: base, baseHash: baseHash, branches: branches}, nil
+}
+
+//------------------------------------
+// branchStatusTableFunctionRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*branchStatusTableFunctionRowIter)(nil)
+
+// branchStatusRef is a branch whose status is returned by dolt_branch_status, along with the hash of its head.
+type branchStatusRef struct {
+	ref  ref.DoltRef
+	hash hash.Hash
+}
+
+// branchStatusTableFunctionRowIter is a sql.RowIter implementation which compares each branch with the base commit.
+// Branches are only walked once they're reached, so a LIMIT avoids walking the remaining branches.
+type branchStatusTableFunctionRowIter struct {
+	ddb      *doltdb.DoltDB
+	base     *doltdb.Commit
+	baseHash hash.Hash
+	branches []branchStatusRef
+	idx      int
+}
+
+// Next implements the sql.RowIter interface
+func (itr *branchStatusTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.branches) {
+		return nil, io.EOF
+	}
+	branch := itr.branches[itr.idx]
+	itr.idx++
+
+	head, err := itr.ddb.ReadCommit(ctx, branch.hash)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := head.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ahead, behind, err := itr.aheadBehind(ctx, head, branch.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.NewRow(branch.ref.GetPath(), branch.hash.String(), ahead, behind, meta.Name, meta.Time()), nil
+}
+
+// aheadBehind returns the number of commits in base..branch and branch..base, which are the same commits that
+// `dolt log` returns for those ranges. Both counts are nil when the branch shares no history with the base, as
+// otherwise the complete history of both would be walked.
+func (itr *branchStatusTableFunctionRowIter) aheadBehind(ctx *sql.Context, head *doltdb.Commit, headHash hash.Hash) (ahead interface{}, behind interface{}, err error) {
+	mergeBase, err := doltdb.GetCommitAncestor(ctx, head, itr.base)
+	if err == doltdb.ErrNoCommonAncestor {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	mergeBaseHash, err := mergeBase.HashOf()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A branch that is an ancestor of the base is not ahead of it, and vice versa, so those walks are skipped
+	var aheadCount, behindCount int64
+	if mergeBaseHash != headHash {
+		aheadCount, err = countDotDotRevisions(ctx, itr.ddb, headHash, itr.baseHash)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if mergeBaseHash != itr.baseHash {
+		behindCount, err = countDotDotRevisions(ctx, itr.ddb, itr.baseHash, headHash)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return aheadCount, behindCount, nil
+}
+
+// countDotDotRevisions returns the number of commits that are reachable from |included| and are not reachable from
+// |excluded|.
+func countDotDotRevisions(ctx *sql.Context, ddb *doltdb.DoltDB, included, excluded hash.Hash) (int64, error) {
+	itr, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, included, excluded, nil)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for {
+		_, _, err = itr.Next(ctx)
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
+
+// Close implements the sql.RowIter interface
+func (itr *branchStatusTableFunctionRowIter) Close(_ *sql.Context) error {
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+func TestBranchStatusTableFunctionUnrelatedHistories(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "first"}})
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	root, err := head.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rootHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	// The first commit to a new branch has no parents, so it shares no history with main
+	orphan, err := dEnv.DoltDB.Commit(ctx, rootHash, ref.NewBranchRef("orphan"), &datas.CommitMeta{Name: "orphan", Email: "orphan@fake.horse", Description: "orphan"})
+	require.NoError(t, err)
+	orphanHash, err := orphan.HashOf()
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.SetHead(ctx, ref.NewRemoteRef("origin", "orphan"), orphanHash))
+	require.NoError(t, dEnv.DoltDB.SetHead(ctx, ref.NewRemoteRef("origin", "main"), headHash))
+
+	rows := executeLogQuery(t, dEnv, false, "SELECT branch, commits_ahead, commits_behind, last_committer FROM dolt_branch_status('main');")
+	assert.Equal(t, []sql.Row{
+		{"main", int64(0), int64(0), "name"},
+		{"orphan", nil, nil, "orphan"},
+	}, rows)
+
+	// Remote tracking branches are only included when requested
+	rows = executeLogQuery(t, dEnv, false, "SELECT branch, commits_ahead, commits_behind FROM dolt_branch_status('main', '--remotes');")
+	assert.Equal(t, []sql.Row{
+		{"main", int64(0), int64(0)},
+		{"orphan", nil, nil},
+		{"origin/main", int64(0), int64(0)},
+		{"origin/orphan", nil, nil},
+	}, rows)
+}
@@ -0,0 +1,308 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var _ sql.TableFunction = (*CommitGraphTableFunction)(nil)
+
+var commitGraphTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "parent_hash", Type: sql.Text, Nullable: true},
+	&sql.Column{Name: "parent_index", Type: sql.Int32, Nullable: false},
+	&sql.Column{Name: "generation", Type: sql.Uint64, Nullable: false},
+	&sql.Column{Name: "parent_generation", Type: sql.Uint64, Nullable: true},
+	&sql.Column{Name: "depth", Type: sql.Uint64, Nullable: false},
+}
+
+// CommitGraphTableFunction is the dolt_commit_graph table function, which returns an edge from every commit to each
+// of its parents, along with the generation of both commits, so that the commit graph can be drawn or queried for
+// ancestry without walking it commit by commit. The generation of a commit is one more than the largest generation of
+// its parents, so an ancestor always has a smaller generation than its descendants. The initial commit has a single
+// edge with a NULL parent.
+//
+// Without an argument, the graph contains every commit reachable from a branch, like dolt_commit_ancestors. With a
+// revision, it contains only the ancestors of that revision. The depth of a commit is the length of the shortest path
+// to it from the revision, or from any branch head.
+type CommitGraphTableFunction struct {
+	ctx *sql.Context
+
+	revisionExpr sql.Expression
+
+	database sql.Database
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (cgtf *CommitGraphTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &CommitGraphTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (cgtf *CommitGraphTableFunction) Database() sql.Database {
+	return cgtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (cgtf *CommitGraphTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	cgtf.database = database
+	return cgtf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (cgtf *CommitGraphTableFunction) FunctionName() string {
+	return "dolt_commit_graph"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (cgtf *CommitGraphTableFunction) Resolved() bool {
+	if cgtf.revisionExpr != nil {
+		return cgtf.revisionExpr.Resolved()
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (cgtf *CommitGraphTableFunction) String() string {
+	if cgtf.revisionExpr != nil {
+		return fmt.Sprintf("DOLT_COMMIT_GRAPH(%s)", cgtf.revisionExpr.String())
+	}
+	return "DOLT_COMMIT_GRAPH()"
+}
+
+// Schema implements the sql.Node interface.
+func (cgtf *CommitGraphTableFunction) Schema() sql.Schema {
+	return commitGraphTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (cgtf *CommitGraphTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (cgtf *CommitGraphTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return cgtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (cgtf *CommitGraphTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := cgtf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(cgtf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (cgtf *CommitGraphTableFunction) Expressions() []sql.Expression {
+	if cgtf.revisionExpr != nil {
+		return []sql.Expression{cgtf.revisionExpr}
+	}
+	return nil
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (cgtf *CommitGraphTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) > 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(cgtf.FunctionName(), "0 or 1", len(expressions))
+	}
+
+	cgtf.revisionExpr = nil
+	if len(expressions) == 1 {
+		// The revision is only evaluated in RowIter, so it may be any text expression
+		if expressions[0].Resolved() && !sql.IsText(expressions[0].Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(cgtf.FunctionName(), expressions[0].String())
+		}
+		cgtf.revisionExpr = expressions[0]
+	}
+
+	return cgtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (cgtf *CommitGraphTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := cgtf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", cgtf.database)
+	}
+	ddb := sqledb.GetDoltDB()
+
+	var starts []*doltdb.Commit
+	if cgtf.revisionExpr != nil {
+		val, err := cgtf.revisionExpr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		revision, ok := val.(string)
+		if !ok || revision == "" {
+			return nil, sql.ErrInvalidArgumentDetails.New(cgtf.FunctionName(), cgtf.revisionExpr.String())
+		}
+		cs, err := doltdb.NewCommitSpec(revision)
+		if err != nil {
+			return nil, err
+		}
+		cm, err := dsess.ResolveReadableCommit(ctx, ddb, cs, getCheckedOutBranch(ctx, sqledb.Name()))
+		if err != nil {
+			return nil, err
+		}
+		starts = append(starts, cm)
+	} else {
+		branches, err := ddb.GetBranches(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			if dsess.IsHiddenRef(ctx, branch) {
+				continue
+			}
+			cm, err := ddb.ResolveCommitRef(ctx, branch)
+			if err != nil {
+				return nil, err
+			}
+			starts = append(starts, cm)
+		}
+	}
+
+	itr := &commitGraphRowIter{visited: make(map[hash.Hash]struct{})}
+	for _, cm := range starts {
+		err := itr.visit(cm, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return itr, nil
+}
+
+// commitGraphNode is a commit waiting for its edges to be returned, along with its depth.
+type commitGraphNode struct {
+	commit *doltdb.Commit
+	depth  uint64
+}
+
+var _ sql.RowIter = (*commitGraphRowIter)(nil)
+
+// commitGraphRowIter walks the commit graph breadth first, so that each commit is first reached by one of its
+// shortest paths, and returns the edges of each commit in the order that the commits are reached.
+type commitGraphRowIter struct {
+	queue   []commitGraphNode
+	visited map[hash.Hash]struct{}
+	rows    []sql.Row
+}
+
+// visit queues the commit given, unless it has already been queued.
+func (itr *commitGraphRowIter) visit(cm *doltdb.Commit, depth uint64) error {
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	if _, ok := itr.visited[h]; ok {
+		return nil
+	}
+	itr.visited[h] = struct{}{}
+	itr.queue = append(itr.queue, commitGraphNode{commit: cm, depth: depth})
+	return nil
+}
+
+// Next implements the sql.RowIter interface
+func (itr *commitGraphRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for len(itr.rows) == 0 {
+		if len(itr.queue) == 0 {
+			return nil, io.EOF
+		}
+		node := itr.queue[0]
+		itr.queue = itr.queue[1:]
+
+		err := itr.addEdges(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r := itr.rows[0]
+	itr.rows = itr.rows[1:]
+	return r, nil
+}
+
+// addEdges adds the rows for the edges from the commit of the node given to its parents, and queues the parents.
+func (itr *commitGraphRowIter) addEdges(ctx *sql.Context, node commitGraphNode) error {
+	h, err := node.commit.HashOf()
+	if err != nil {
+		return err
+	}
+	generation, err := node.commit.Height()
+	if err != nil {
+		return err
+	}
+
+	if node.commit.NumParents() == 0 {
+		itr.rows = append(itr.rows, sql.NewRow(h.String(), nil, int32(0), generation, nil, node.depth))
+		return nil
+	}
+
+	for i := 0; i < node.commit.NumParents(); i++ {
+		parent, err := node.commit.GetParent(ctx, i)
+		if err != nil {
+			return err
+		}
+		ph, err := parent.HashOf()
+		if err != nil {
+			return err
+		}
+		parentGeneration, err := parent.Height()
+		if err != nil {
+			return err
+		}
+		itr.rows = append(itr.rows, sql.NewRow(h.String(), ph.String(), int32(i), generation, parentGeneration, node.depth))
+
+		err = itr.visit(parent, node.depth+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the sql.RowIter interface
+func (itr *commitGraphRowIter) Close(*sql.Context) error {
+	return nil
+}
@@ -51,6 +51,7 @@ var diffSummaryTableSchema = sql.Schema{
 	&sql.Column{Name: "new_row_count", Type: sql.Int64, Nullable: true},
 	&sql.Column{Name: "old_cell_count", Type: sql.Int64, Nullable: true},
 	&sql.Column{Name: "new_cell_count", Type: sql.Int64, Nullable: true},
+	&sql.Column{Name: "schema_change", Type: sql.Boolean, Nullable: false},
 }
 
 // NewInstance creates a new instance of TableFunction interface
@@ -339,7 +340,14 @@ func getDiffSummaryNodeFromDelta(ctx *sql.Context, delta diff.TableDelta, fromRo
 		return diffSummaryNode{}, false, err
 	}
 
-	return diffSummaryNode{tableName, diffSum, oldColLen, newColLen, keyless}, hasDiff, nil
+	// tables whose schema changed are included even when none of their rows changed, such as when a table is
+	// created, or a column is renamed
+	schemaChange, err := delta.HasSchemaChanged(ctx)
+	if err != nil {
+		return diffSummaryNode{}, false, err
+	}
+
+	return diffSummaryNode{tableName, diffSum, oldColLen, newColLen, keyless, schemaChange}, hasDiff || schemaChange, nil
 }
 
 // getDiffSummary returns diff.DiffSummaryProgress object and whether there is a data diff or not.
@@ -384,7 +392,7 @@ func getDiffSummary(ctx *sql.Context, td diff.TableDelta) (diff.DiffSummaryProgr
 	}
 
 	if (acc.Adds+acc.Removes+acc.Changes) == 0 && (acc.OldCellSize-acc.NewCellSize) == 0 {
-		return diff.DiffSummaryProgress{}, false, keyless, nil
+		return acc, false, keyless, nil
 	}
 
 	return acc, true, keyless, nil
@@ -410,11 +418,12 @@ func (d *diffSummaryTableFunctionRowIter) incrementIndexes() {
 }
 
 type diffSummaryNode struct {
-	tblName     string
-	diffSummary diff.DiffSummaryProgress
-	oldColLen   int
-	newColLen   int
-	keyless     bool
+	tblName      string
+	diffSummary  diff.DiffSummaryProgress
+	oldColLen    int
+	newColLen    int
+	keyless      bool
+	schemaChange bool
 }
 
 func NewDiffSummaryTableFunctionRowIter(ds []diffSummaryNode) sql.RowIter {
@@ -434,7 +443,7 @@ func (d *diffSummaryTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error
 	}
 
 	ds := d.diffSums[d.diffIdx]
-	return getRowFromDiffSummary(ds.tblName, ds.diffSummary, ds.newColLen, ds.oldColLen, ds.keyless), nil
+	return getRowFromDiffSummary(ds.tblName, ds.diffSummary, ds.newColLen, ds.oldColLen, ds.keyless, ds.schemaChange), nil
 }
 
 func (d *diffSummaryTableFunctionRowIter) Close(context *sql.Context) error {
@@ -444,7 +453,7 @@ func (d *diffSummaryTableFunctionRowIter) Close(context *sql.Context) error {
 // getRowFromDiffSummary takes diff.DiffSummaryProgress and calculates the row_modified, cell_added, cell_deleted.
 // If the number of cell change from old to new cell count does not equal to cell_added and/or cell_deleted, there
 // must be schema changes that affects cell_added and cell_deleted value addition to the row count * col length number.
-func getRowFromDiffSummary(tblName string, dsp diff.DiffSummaryProgress, newColLen, oldColLen int, keyless bool) sql.Row {
+func getRowFromDiffSummary(tblName string, dsp diff.DiffSummaryProgress, newColLen, oldColLen int, keyless, schemaChange bool) sql.Row {
 	// if table is keyless table, match current CLI command result
 	if keyless {
 		return sql.Row{
@@ -460,6 +469,7 @@ func getRowFromDiffSummary(tblName string, dsp diff.DiffSummaryProgress, newColL
 			nil,                // new_row_count
 			nil,                // old_cell_count
 			nil,                // new_cell_count
+			schemaChange,       // schema_change
 		}
 	}
 
@@ -479,6 +489,7 @@ func getRowFromDiffSummary(tblName string, dsp diff.DiffSummaryProgress, newColL
 		int64(dsp.NewRowSize),  // new_row_count
 		int64(dsp.OldCellSize), // old_cell_count
 		int64(dsp.NewCellSize), // new_cell_count
+		schemaChange,           // schema_change
 	}
 }
 
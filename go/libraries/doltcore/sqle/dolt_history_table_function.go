@@ -0,0 +1,390 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var _ sql.TableFunction = (*HistoryTableFunction)(nil)
+var _ limitedTableFunction = (*HistoryTableFunction)(nil)
+
+// HistoryTableFunction is the dolt_history table function, which returns the rows of a table at every commit of a
+// revision or revision range, in the same form as the dolt_history_<table> system table. The system table always
+// walks the history of HEAD, while the function walks the commits given, which may be a single revision, a two dot
+// range such as 'v1.0..v2.0', which excludes the ancestors of the first revision, or a three dot range. An empty side
+// of a range is HEAD. Like dolt_log, the walk stops once the LIMIT of the query has been reached, so that queries for
+// recent history don't walk the entire commit graph.
+type HistoryTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr sql.Expression
+	revisionExpr  sql.Expression
+	database      sql.Database
+
+	sqlSch    sql.Schema
+	table     *DoltTable
+	commits   []hash.Hash
+	excluding []hash.Hash
+
+	limited bool
+	limit   int64
+	offset  int64
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (htf *HistoryTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &HistoryTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (htf *HistoryTableFunction) Database() sql.Database {
+	return htf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (htf *HistoryTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	htf.database = database
+	return htf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (htf *HistoryTableFunction) FunctionName() string {
+	return "dolt_history"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (htf *HistoryTableFunction) Resolved() bool {
+	if htf.revisionExpr != nil && !htf.revisionExpr.Resolved() {
+		return false
+	}
+	return htf.tableNameExpr.Resolved()
+}
+
+// String implements the Stringer interface
+func (htf *HistoryTableFunction) String() string {
+	if htf.revisionExpr != nil {
+		return fmt.Sprintf("DOLT_HISTORY(%s, %s)", htf.tableNameExpr.String(), htf.revisionExpr.String())
+	}
+	return fmt.Sprintf("DOLT_HISTORY(%s)", htf.tableNameExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (htf *HistoryTableFunction) Schema() sql.Schema {
+	if !htf.Resolved() {
+		return nil
+	}
+
+	if htf.sqlSch == nil {
+		panic("schema hasn't been generated yet")
+	}
+
+	return htf.sqlSch
+}
+
+// Children implements the sql.Node interface.
+func (htf *HistoryTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (htf *HistoryTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return htf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (htf *HistoryTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(htf.database.Name(), htf.table.Name(), "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (htf *HistoryTableFunction) Expressions() []sql.Expression {
+	if htf.revisionExpr != nil {
+		return []sql.Expression{htf.tableNameExpr, htf.revisionExpr}
+	}
+	return []sql.Expression{htf.tableNameExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (htf *HistoryTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) < 1 || len(expressions) > 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(htf.FunctionName(), "1 or 2", len(expressions))
+	}
+
+	// The schema depends on the table, so only literal / fully-resolved arguments are supported, as with dolt_diff
+	for _, expr := range expressions {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(htf.FunctionName(), expr.String())
+		}
+		if !sql.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(htf.FunctionName(), expr.String())
+		}
+	}
+
+	htf.tableNameExpr = expressions[0]
+	htf.revisionExpr = nil
+	if len(expressions) == 2 {
+		htf.revisionExpr = expressions[1]
+	}
+
+	err := htf.generateSchema(htf.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return htf, nil
+}
+
+// Limit implements the limitedTableFunction interface.
+func (htf *HistoryTableFunction) Limit() (int64, int64, bool) {
+	return htf.limit, htf.offset, htf.limited
+}
+
+// WithLimit implements the limitedTableFunction interface. The rows of the offset are skipped without being converted,
+// and no more commits are walked once |limit| rows have been returned.
+func (htf *HistoryTableFunction) WithLimit(limit int64, offset int64) sql.Node {
+	nhtf := *htf
+	nhtf.limited = true
+	nhtf.limit = limit
+	nhtf.offset = offset
+	return &nhtf
+}
+
+// generateSchema resolves the commits to walk, and generates the schema from the table at the last revision of the
+// range, in the same way that dolt_history_<table> uses the table at HEAD.
+func (htf *HistoryTableFunction) generateSchema(ctx *sql.Context) error {
+	sqledb, ok := htf.database.(Database)
+	if !ok {
+		return fmt.Errorf("unexpected database type: %T", htf.database)
+	}
+
+	tableName, err := htf.evaluateText(ctx, htf.tableNameExpr)
+	if err != nil {
+		return err
+	}
+
+	revision := "HEAD"
+	if htf.revisionExpr != nil {
+		revision, err = htf.evaluateText(ctx, htf.revisionExpr)
+		if err != nil {
+			return err
+		}
+	}
+
+	// a single revision may not exclude anything, as there would be nothing left to walk
+	isRange := strings.Contains(revision, "..")
+	if !isRange && strings.HasPrefix(revision, "^") {
+		return sql.ErrInvalidArgumentDetails.New(htf.FunctionName(), htf.revisionExpr.String())
+	}
+
+	tip, excluding, threeDot := getRevisionsFromValue(revision)
+	tipCommit, err := htf.resolveRevision(ctx, sqledb, tip)
+	if err != nil {
+		return err
+	}
+	tipHash, err := tipCommit.HashOf()
+	if err != nil {
+		return err
+	}
+
+	htf.commits = []hash.Hash{tipHash}
+	htf.excluding = nil
+	if isRange {
+		exCommit, err := htf.resolveRevision(ctx, sqledb, excluding)
+		if err != nil {
+			return err
+		}
+		exHash, err := exCommit.HashOf()
+		if err != nil {
+			return err
+		}
+
+		if threeDot {
+			// a three dot range includes both revisions, and excludes the ancestors of their merge base
+			htf.commits = append(htf.commits, exHash)
+			exHash, err = merge.MergeBase(ctx, tipCommit, exCommit)
+			if err != nil {
+				return err
+			}
+		}
+		htf.excluding = []hash.Hash{exHash}
+	}
+
+	root, err := tipCommit.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	tbl, ok, err := sqledb.getTable(ctx, root, tableName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+	switch t := tbl.(type) {
+	case *AlterableDoltTable:
+		htf.table = t.DoltTable
+	case *WritableDoltTable:
+		htf.table = t.DoltTable
+	case *DoltTable:
+		htf.table = t
+	default:
+		return fmt.Errorf("unexpected table type: %T", tbl)
+	}
+
+	// As with dolt_diff, the columns have no source, since they don't come from a real table
+	sch := historyTableSchema("", htf.table)
+	for i := range sch {
+		col := *sch[i]
+		col.Source = ""
+		sch[i] = &col
+	}
+	htf.sqlSch = sch
+
+	return nil
+}
+
+// resolveRevision resolves one side of the revision range, which is HEAD when it's empty.
+func (htf *HistoryTableFunction) resolveRevision(ctx *sql.Context, db Database, revision string) (*doltdb.Commit, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	cs, err := doltdb.NewCommitSpec(revision)
+	if err != nil {
+		return nil, err
+	}
+	return dsess.ResolveReadableCommit(ctx, db.ddb, cs, getCheckedOutBranch(ctx, db.Name()))
+}
+
+// evaluateText evaluates the argument given, which must be a non-empty string.
+func (htf *HistoryTableFunction) evaluateText(ctx *sql.Context, expr sql.Expression) (string, error) {
+	v, err := expr.Eval(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return "", sql.ErrInvalidArgumentDetails.New(htf.FunctionName(), expr.String())
+	}
+	return s, nil
+}
+
+// RowIter implements the sql.Node interface
+func (htf *HistoryTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	sqledb, ok := htf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", htf.database)
+	}
+
+	cmItr, err := commitwalk.GetMultiRevisionsIterator(ctx, sqledb.ddb, htf.commits, htf.excluding, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// every column of the table is projected, followed by the commit columns
+	cols := htf.table.sch.GetAllCols()
+	projections := make([]uint64, 0, cols.Size()+3)
+	projections = append(projections, cols.Tags...)
+	projections = append(projections, schema.HistoryCommitHashTag, schema.HistoryCommitterTag, schema.HistoryCommitDateTag)
+
+	return &historyTableFunctionRowIter{
+		cmItr:       cmItr,
+		table:       htf.table,
+		projections: projections,
+		limited:     htf.limited,
+		remaining:   htf.limit,
+		offset:      htf.offset,
+	}, nil
+}
+
+var _ sql.RowIter = (*historyTableFunctionRowIter)(nil)
+
+// historyTableFunctionRowIter returns the rows of the table at each commit of the walk, until the limit is reached.
+type historyTableFunctionRowIter struct {
+	cmItr       doltdb.CommitItr
+	table       *DoltTable
+	projections []uint64
+	curr        *historyIter
+
+	limited   bool
+	remaining int64
+	offset    int64
+}
+
+// Next implements the sql.RowIter interface
+func (itr *historyTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for {
+		if itr.limited && itr.remaining <= 0 {
+			return nil, io.EOF
+		}
+
+		if itr.curr == nil {
+			h, cm, err := itr.cmItr.Next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			itr.curr, err = newRowItrForTableAtCommit(ctx, itr.table.Name(), itr.table, h, cm, sql.IndexLookup{}, itr.projections)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		r, err := itr.curr.Next(ctx)
+		if err == io.EOF {
+			itr.curr = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if itr.offset > 0 {
+			itr.offset--
+			continue
+		}
+		itr.remaining--
+		return r, nil
+	}
+}
+
+// Close implements the sql.RowIter interface
+func (itr *historyTableFunctionRowIter) Close(*sql.Context) error {
+	return nil
+}
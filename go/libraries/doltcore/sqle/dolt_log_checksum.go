@@ -0,0 +1,157 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const LogChecksumFuncName = "dolt_log_checksum"
+
+// logChecksumVersion is the first byte of every checksum's input. It must be incremented whenever the construction of
+// the checksum changes, so that checksums from servers running different versions never falsely agree.
+const logChecksumVersion byte = 1
+
+// logChecksumFunction is registered with the provider alongside the functions of the dfunctions package.
+var logChecksumFunction = sql.FunctionN{Name: LogChecksumFuncName, Fn: NewLogChecksumFunc}
+
+// LogChecksumFunc returns a checksum of the commits that dolt_log would return for the same arguments. Only the commit
+// graph contributes to the checksum, so two servers with identical histories always return the same checksum, while
+// refs, commit metadata, and the selected output columns have no effect.
+//
+// The checksum is the SHA-512 of the following input, truncated to the length of a commit hash, and encoded in the same
+// way as commit hashes:
+//
+//	version byte (logChecksumVersion)
+//	for each commit, in the order that dolt_log emits them:
+//	    commit hash (20 bytes)
+//	    number of parents (uint32, big endian)
+//	    each parent hash, in the commit's parent order (20 bytes each)
+type LogChecksumFunc struct {
+	expression.NaryExpression
+}
+
+var _ sql.FunctionExpression = (*LogChecksumFunc)(nil)
+
+// NewLogChecksumFunc creates a new LogChecksumFunc expression.
+func NewLogChecksumFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &LogChecksumFunc{expression.NaryExpression{ChildExpressions: args}}, nil
+}
+
+// FunctionName implements the sql.FunctionExpression interface.
+func (f *LogChecksumFunc) FunctionName() string {
+	return LogChecksumFuncName
+}
+
+// Description implements the sql.FunctionExpression interface.
+func (f *LogChecksumFunc) Description() string {
+	return "returns a checksum of the commit graph that dolt_log returns for the same arguments"
+}
+
+// Eval implements the sql.Expression interface.
+func (f *LogChecksumFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	args, err := getDoltArgs(ctx, row, f.Children(), f.FunctionName())
+	if err != nil {
+		return nil, err
+	}
+	node, err := newLogNode(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := node.RowIter(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+	logIter, ok := iter.(*logTableFunctionRowIter)
+	if !ok {
+		return nil, fmt.Errorf("unexpected row iterator type: %T", iter)
+	}
+
+	// Commits are read from the iterator's child, so that options which only affect the output columns are ignored
+	hasher := sha512.New()
+	hasher.Write([]byte{logChecksumVersion})
+	var numParents [4]byte
+	for {
+		h, cm, err := logIter.child.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hasher.Write(h[:])
+		binary.BigEndian.PutUint32(numParents[:], uint32(len(parents)))
+		hasher.Write(numParents[:])
+		for _, parent := range parents {
+			hasher.Write(parent[:])
+		}
+	}
+	return hash.New(hasher.Sum(nil)[:hash.ByteLen]).String(), nil
+}
+
+// String implements the fmt.Stringer interface.
+func (f *LogChecksumFunc) String() string {
+	childrenStrings := make([]string, len(f.Children()))
+	for i, child := range f.Children() {
+		childrenStrings[i] = child.String()
+	}
+	return fmt.Sprintf("DOLT_LOG_CHECKSUM(%s)", strings.Join(childrenStrings, ","))
+}
+
+// Type implements the sql.Expression interface.
+func (f *LogChecksumFunc) Type() sql.Type {
+	return sql.Text
+}
+
+// IsNullable implements the sql.Expression interface.
+func (f *LogChecksumFunc) IsNullable() bool {
+	return false
+}
+
+// WithChildren implements the sql.Expression interface.
+func (f *LogChecksumFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewLogChecksumFunc(children...)
+}
+
+// newLogNode returns a resolved dolt_log table function for the session's current database.
+func newLogNode(ctx *sql.Context, args []string) (sql.Node, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, sql.ErrNoDatabaseSelected.New()
+	}
+	db, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make([]sql.Expression, len(args))
+	for i, arg := range args {
+		exprs[i] = expression.NewLiteral(arg, sql.LongText)
+	}
+	return (&LogTableFunction{}).NewInstance(ctx, db, exprs)
+}
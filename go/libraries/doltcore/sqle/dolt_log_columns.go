@@ -0,0 +1,130 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrLogColumnProviderExists = errors.NewKind("a dolt_log column provider named `%s` is already registered")
+var ErrLogColumnProviderInvalid = errors.NewKind("invalid dolt_log column provider: %s")
+
+// LogColumnProvider computes an additional column of dolt_log, which is appended to the schema when the provider is
+// named by --with. Providers allow embedders to extend the log without forking the function.
+type LogColumnProvider struct {
+	// Name is the name of the provider, which is given to --with, and the name of the column it adds
+	Name string
+	// Type is the type of the column. Computed values are converted to it.
+	Type sql.Type
+	// Compute returns the value of the column for the given commit. It is only called for queries that read the column.
+	Compute func(ctx *sql.Context, cm *doltdb.Commit, h hash.Hash) (interface{}, error)
+}
+
+// logColumnProviders holds the registered providers by their lowercased names.
+var logColumnProviders = struct {
+	mu        *sync.RWMutex
+	providers map[string]LogColumnProvider
+}{
+	mu:        &sync.RWMutex{},
+	providers: make(map[string]LogColumnProvider),
+}
+
+func init() {
+	if err := RegisterLogColumnProvider(messageWordCountColumn); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterLogColumnProvider registers the given provider, so that it may be named by the --with option of dolt_log.
+// Names are case-insensitive, and a provider may not replace one that's already registered.
+func RegisterLogColumnProvider(provider LogColumnProvider) error {
+	if provider.Name == "" {
+		return ErrLogColumnProviderInvalid.New("the name must not be empty")
+	}
+	if provider.Type == nil || provider.Compute == nil {
+		return ErrLogColumnProviderInvalid.New(fmt.Sprintf("`%s` must have a type and a compute function", provider.Name))
+	}
+	logColumnProviders.mu.Lock()
+	defer logColumnProviders.mu.Unlock()
+	key := strings.ToLower(provider.Name)
+	if _, ok := logColumnProviders.providers[key]; ok {
+		return ErrLogColumnProviderExists.New(provider.Name)
+	}
+	logColumnProviders.providers[key] = provider
+	return nil
+}
+
+// UnregisterLogColumnProvider removes the provider with the given name, if one is registered. Queries that have
+// already been analyzed keep using the provider.
+func UnregisterLogColumnProvider(name string) {
+	logColumnProviders.mu.Lock()
+	defer logColumnProviders.mu.Unlock()
+	delete(logColumnProviders.providers, strings.ToLower(name))
+}
+
+// getLogColumnProvider returns the provider registered with the given name.
+func getLogColumnProvider(name string) (LogColumnProvider, bool) {
+	logColumnProviders.mu.RLock()
+	defer logColumnProviders.mu.RUnlock()
+	provider, ok := logColumnProviders.providers[strings.ToLower(name)]
+	return provider, ok
+}
+
+// logColumnProviderNames returns the names of every registered provider in sorted order.
+func logColumnProviderNames() []string {
+	logColumnProviders.mu.RLock()
+	defer logColumnProviders.mu.RUnlock()
+	names := make([]string, 0, len(logColumnProviders.providers))
+	for _, provider := range logColumnProviders.providers {
+		names = append(names, provider.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// computeLogColumn returns the value of the given provider's column for a commit, converted to the column's type.
+// Errors name the provider and the commit, as they're otherwise indistinguishable from the errors of the log itself.
+func computeLogColumn(ctx *sql.Context, provider LogColumnProvider, cm *doltdb.Commit, h hash.Hash) (interface{}, error) {
+	val, err := provider.Compute(ctx, cm, h)
+	if err == nil {
+		val, err = provider.Type.Convert(val)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dolt_log column provider `%s` failed on commit %s: %w", provider.Name, h.String(), err)
+	}
+	return val, nil
+}
+
+// messageWordCountColumn is a built-in provider that counts the words of each commit message.
+var messageWordCountColumn = LogColumnProvider{
+	Name: "message_word_count",
+	Type: sql.Int64,
+	Compute: func(ctx *sql.Context, cm *doltdb.Commit, _ hash.Hash) (interface{}, error) {
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return int64(len(strings.Fields(meta.Description))), nil
+	},
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/utils/histogram"
+)
+
+// The spans that dolt_log reports through the tracer of the query's context. The query's span lasts until its rows
+// have been read, and the others are its children.
+const (
+	logSpanQuery      = "dolt_log"
+	logSpanResolve    = "dolt_log.resolve"
+	logSpanDecorate   = "dolt_log.decorate"
+	logSpanAbbreviate = "dolt_log.abbreviate"
+)
+
+// LogRowLatencyBuckets are the upper bounds, in seconds, of the buckets that the time taken to produce each row of
+// dolt_log is counted in.
+var LogRowLatencyBuckets = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 1}
+
+var logRowLatency = histogram.New(LogRowLatencyBuckets)
+
+// GetLogRowLatency returns the time taken to produce each row of dolt_log since the process started.
+func GetLogRowLatency() histogram.Snapshot {
+	return logRowLatency.Snapshot()
+}
+
+// logTrace holds the span of a traced dolt_log query, along with the counts that are reported as the span's attributes
+// once the query's rows have been read.
+type logTrace struct {
+	span  trace.Span
+	stats *commitwalk.WalkStats
+	rows  int64
+	// metaDecoding is the total time spent reading the metadata of the commits in the log
+	metaDecoding time.Duration
+}
+
+// startLogTrace starts the span of a dolt_log query, returning the context that the query's commits are walked with.
+// The returned trace is nil when the span is not recorded, in which case nothing is counted, so that queries that are
+// not traced do no extra work.
+func startLogTrace(ctx *sql.Context) (*sql.Context, *logTrace) {
+	span, spanCtx := ctx.Span(logSpanQuery)
+	if !span.IsRecording() {
+		span.End()
+		return ctx, nil
+	}
+	lt := &logTrace{span: span, stats: &commitwalk.WalkStats{}}
+	return spanCtx.WithContext(commitwalk.WithWalkStats(spanCtx.Context, lt.stats)), lt
+}
+
+// end reports the counts of the query and ends its span. It may be called on a nil trace, and any call after the first
+// does nothing.
+func (lt *logTrace) end() {
+	if lt == nil || lt.span == nil {
+		return
+	}
+	lt.span.SetAttributes(
+		attribute.Int64("rows", lt.rows),
+		attribute.Int64("commits_visited", int64(lt.stats.Visited)),
+		attribute.Int64("commits_skipped", int64(lt.stats.Skipped)),
+		attribute.Int64("chunk_reads", int64(lt.stats.ChunkReads)),
+		attribute.Int64("meta_decoding_ns", lt.metaDecoding.Nanoseconds()),
+	)
+	lt.span.End()
+	lt.span = nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+// logPolicyAccount is the account that a committer's email is mapped to by the table given with --check-policy.
+type logPolicyAccount struct {
+	user string
+	host string
+}
+
+// policyBranch returns the branch that commits are checked against by --check-policy, which is the branch that the log
+// is read from. That's the included revision when one is given, which must name a branch, and the checked out branch
+// otherwise. A log of several included revisions isn't read from a single branch, so it can't be checked.
+func (ltf *LogTableFunction) policyBranch(ctx *sql.Context, db Database, args logArguments, revisions logRevisions, checkedOut ref.DoltRef) (string, error) {
+	detail := ArgumentErrorDetail{Index: args.policyIndex, Flag: cli.CheckPolicyParam, Code: ArgumentErrorConflictingOptions}
+	if len(revisions.included) == 0 {
+		if checkedOut == nil || checkedOut.GetType() != ref.BranchRefType {
+			return "", newArgumentError(ltf.FunctionName(), "--check-policy requires a branch, but no branch is checked out", detail)
+		}
+		return checkedOut.GetPath(), nil
+	}
+	if len(revisions.included) > 1 {
+		return "", newArgumentError(ltf.FunctionName(), "--check-policy requires a single branch, but more than one revision is included", detail)
+	}
+	branch := revisions.included[0].name
+	ok, err := db.ddb.HasRef(ctx, ref.NewBranchRef(branch))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", newArgumentError(ltf.FunctionName(), fmt.Sprintf("--check-policy requires a branch, but `%s` is not a branch", branch), detail)
+	}
+	return branch, nil
+}
+
+// logPolicyChecker determines whether the committer of a commit would be denied write on the logged branch by the
+// current branch control rules. Every commit by the same account has the same verdict, so each account's verdict is
+// only computed once.
+type logPolicyChecker struct {
+	branch   string
+	accounts map[string]logPolicyAccount
+	denied   map[logPolicyAccount]bool
+}
+
+// newLogPolicyChecker returns a checker for the given branch, which maps committers to accounts through the table with
+// the given name. The table must have the email, user, and host columns, and each email may only be mapped once. Rows
+// without an email or user are ignored.
+func newLogPolicyChecker(ctx *sql.Context, ltf *LogTableFunction, db Database, args logArguments, branch string) (*logPolicyChecker, error) {
+	tbl, ok, err := db.GetTableInsensitive(ctx, args.policyTable)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(args.policyTable)
+	}
+
+	detail := ArgumentErrorDetail{Index: args.policyIndex, Flag: cli.CheckPolicyParam, Code: ArgumentErrorInvalidValue}
+	columns := make(map[string]int)
+	for i, col := range tbl.Schema() {
+		columns[strings.ToLower(col.Name)] = i
+	}
+	for _, name := range []string{"email", "user", "host"} {
+		if _, ok := columns[name]; !ok {
+			msg := fmt.Sprintf("invalid --check-policy option: table `%s` has no %s column", tbl.Name(), name)
+			return nil, newArgumentError(ltf.FunctionName(), msg, detail)
+		}
+	}
+
+	partitions, err := tbl.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := sql.NewTableRowIter(ctx, tbl, partitions)
+	defer rows.Close(ctx)
+
+	checker := &logPolicyChecker{
+		branch:   branch,
+		accounts: make(map[string]logPolicyAccount),
+		denied:   make(map[logPolicyAccount]bool),
+	}
+	for {
+		row, err := rows.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		email, user, host := row[columns["email"]], row[columns["user"]], row[columns["host"]]
+		if email == nil || user == nil {
+			continue
+		}
+		if host == nil {
+			host = ""
+		}
+		key := strings.ToLower(fmt.Sprint(email))
+		if _, ok := checker.accounts[key]; ok {
+			msg := fmt.Sprintf("invalid --check-policy option: table `%s` maps the email `%s` more than once", tbl.Name(), email)
+			return nil, newArgumentError(ltf.FunctionName(), msg, detail)
+		}
+		checker.accounts[key] = logPolicyAccount{user: fmt.Sprint(user), host: fmt.Sprint(host)}
+	}
+	return checker, nil
+}
+
+// violation returns whether the account that the given email is mapped to would be denied write on the branch, or nil
+// when the email isn't mapped to an account. Admin implies write, so only accounts with neither are denied.
+func (checker *logPolicyChecker) violation(ctx *sql.Context, email string) (interface{}, error) {
+	account, ok := checker.accounts[strings.ToLower(email)]
+	if !ok {
+		return nil, nil
+	}
+	if denied, ok := checker.denied[account]; ok {
+		return denied, nil
+	}
+	perms, err := branch_control.UserBranchPermissions(ctx, checker.branch, account.user, account.host)
+	if err != nil {
+		return nil, err
+	}
+	denied := perms&(branch_control.Permissions_Write|branch_control.Permissions_Admin) == 0
+	checker.denied[account] = denied
+	return denied, nil
+}
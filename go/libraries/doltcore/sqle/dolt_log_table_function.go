@@ -15,31 +15,84 @@
 package sqle
 
 import (
+	goerrors "errors"
 	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
 var _ sql.TableFunction = (*LogTableFunction)(nil)
+var _ projectedTableFunction = (*LogTableFunction)(nil)
 
 type LogTableFunction struct {
 	ctx *sql.Context
 
-	revisionExpr       sql.Expression
-	secondRevisionExpr sql.Expression
+	// argumentExprs are evaluated again for every call to RowIter, as they may depend on the columns of other tables
+	argumentExprs []sql.Expression
 
-	notRevision string
-	minParents  int
 	showParents bool
 	decoration  string
+	showStat    bool
+	showGraph   bool
+	jsonFormat  bool
+	// showBoundary adds the is_boundary column
+	showBoundary bool
+	// containsRefs are the refs given with --contains, each of which adds a column to the schema
+	containsRefs []string
+	// firstParent restricts the walk to the first parent of each included commit
+	firstParent bool
+	// showShortHash adds the short_hash column
+	showShortHash bool
+	// abbrevLength is the length that commit_hash is abbreviated to, which also adds the subject column, and is 0 when
+	// the hashes are not abbreviated
+	abbrevLength int
+	// showSources adds the sources column, and is set when the log is read from the refs of --all or --branches
+	showSources bool
+	// checkPolicy adds the policy_violation column, and is set when --check-policy is given
+	checkPolicy bool
+	// columnProviders compute the columns given with --with, which are appended to the end of the schema
+	columnProviders []LogColumnProvider
+	// projections are the columns that the query reads from the function, and are nil when it may read every column.
+	// Messages are only loaded when a column that holds them is read.
+	projections []string
+	// limited is set when the query's LIMIT has been pushed into the function, in which case offset rows are skipped
+	// and then at most limit rows are returned
+	limited bool
+	limit   int64
+	offset  int64
+
+	// rawMetadata is set when the session has opted out of sanitizing commit metadata
+	rawMetadata bool
+	// legacyEmptyColumns is set when the session has opted into empty values, rather than NULL, for the columns that
+	// don't apply to a commit
+	legacyEmptyColumns bool
+	// defaultShowParents and defaultDecoration are read from the session's system variables, and are used when the
+	// corresponding options are not given
+	defaultShowParents bool
+	defaultDecoration  string
 
 	database sql.Database
 }
@@ -50,13 +103,64 @@ var logTableSchema = sql.Schema{
 	&sql.Column{Name: "email", Type: sql.Text},
 	&sql.Column{Name: "date", Type: sql.Datetime},
 	&sql.Column{Name: "message", Type: sql.Text},
+	&sql.Column{Name: "commit_order", Type: sql.Int64},
+	&sql.Column{Name: "committer_date_tz", Type: sql.Text, Nullable: true},
+}
+
+// logTableRawSchema is the schema used when dolt_log_raw_commit_metadata is set. Commit metadata imported from other
+// systems is not guaranteed to be valid UTF-8, so the metadata columns are binary to return the stored bytes unchanged.
+var logTableRawSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: sql.Text},
+	&sql.Column{Name: "committer", Type: sql.LongBlob},
+	&sql.Column{Name: "email", Type: sql.LongBlob},
+	&sql.Column{Name: "date", Type: sql.Datetime},
+	&sql.Column{Name: "message", Type: sql.LongBlob},
+	&sql.Column{Name: "commit_order", Type: sql.Int64},
+	&sql.Column{Name: "committer_date_tz", Type: sql.Text, Nullable: true},
+}
+
+// logTableStatSchema contains the columns that are appended to the schema when --stat is given.
+var logTableStatSchema = sql.Schema{
+	&sql.Column{Name: "tables_changed", Type: sql.Int32},
+	&sql.Column{Name: "rows_added", Type: sql.Int64},
+	&sql.Column{Name: "rows_modified", Type: sql.Int64},
+	&sql.Column{Name: "rows_deleted", Type: sql.Int64},
+}
+
+// logTableGraphSchema contains the columns that are appended to the schema when --graph is given.
+var logTableGraphSchema = sql.Schema{
+	&sql.Column{Name: "graph_order", Type: sql.Int64},
+	&sql.Column{Name: "parent_orders", Type: sql.JSON, Nullable: true},
+	&sql.Column{Name: "lane", Type: sql.Int64},
 }
 
 // NewInstance creates a new instance of TableFunction interface
 func (ltf *LogTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	rawMetadata, err := dsess.GetBooleanSystemVar(ctx, dsess.LogRawCommitMetadata)
+	if err != nil {
+		return nil, err
+	}
+	// The defaults change the schema, so they're read along with the arguments rather than in RowIter
+	defaultShowParents, err := dsess.GetBooleanSystemVar(ctx, dsess.LogShowParents)
+	if err != nil {
+		return nil, err
+	}
+	defaultDecoration, err := ctx.GetSessionVariable(ctx, dsess.LogDecorate)
+	if err != nil {
+		return nil, err
+	}
+	legacyEmptyColumns, err := dsess.GetBooleanSystemVar(ctx, dsess.LogLegacyEmptyColumns)
+	if err != nil {
+		return nil, err
+	}
+
 	newInstance := &LogTableFunction{
-		ctx:      ctx,
-		database: db,
+		ctx:                ctx,
+		database:           db,
+		rawMetadata:        rawMetadata,
+		defaultShowParents: defaultShowParents,
+		defaultDecoration:  defaultDecoration.(string),
+		legacyEmptyColumns: legacyEmptyColumns,
 	}
 
 	node, err := newInstance.WithExpressions(expressions...)
@@ -85,11 +189,10 @@ func (ltf *LogTableFunction) FunctionName() string {
 
 // Resolved implements the sql.Resolvable interface
 func (ltf *LogTableFunction) Resolved() bool {
-	if ltf.revisionExpr != nil && ltf.secondRevisionExpr != nil {
-		return ltf.revisionExpr.Resolved() && ltf.secondRevisionExpr.Resolved()
-	}
-	if ltf.revisionExpr != nil {
-		return ltf.revisionExpr.Resolved()
+	for _, expr := range ltf.argumentExprs {
+		if !expr.Resolved() {
+			return false
+		}
 	}
 	return true
 }
@@ -100,47 +203,111 @@ func (ltf *LogTableFunction) String() string {
 }
 
 func (ltf *LogTableFunction) getOptionsString() string {
-	var options []string
-
-	if ltf.revisionExpr != nil {
-		options = append(options, ltf.revisionExpr.String())
+	options := make([]string, len(ltf.argumentExprs))
+	for i, expr := range ltf.argumentExprs {
+		options[i] = expr.String()
 	}
+	return strings.Join(options, ", ")
+}
 
-	if ltf.secondRevisionExpr != nil {
-		options = append(options, ltf.secondRevisionExpr.String())
+// Schema implements the sql.Node interface.
+func (ltf *LogTableFunction) Schema() sql.Schema {
+	logSchema := logTableSchema
+	if ltf.rawMetadata {
+		logSchema = logTableRawSchema
 	}
 
-	if len(ltf.notRevision) > 0 {
-		options = append(options, fmt.Sprintf("--%s %s", cli.NotFlag, ltf.notRevision))
+	if ltf.abbrevLength > 0 {
+		subjectType := sql.Type(sql.Text)
+		if ltf.rawMetadata {
+			subjectType = sql.LongBlob
+		}
+		logSchema = append(logSchema, &sql.Column{Name: "subject", Type: subjectType})
 	}
-
-	if ltf.minParents > 0 {
-		options = append(options, fmt.Sprintf("--%s %d", cli.MinParentsFlag, ltf.minParents))
+	if ltf.showShortHash {
+		logSchema = append(logSchema, &sql.Column{Name: "short_hash", Type: sql.Text})
 	}
 
+	listType := sql.Type(sql.Text)
+	if ltf.jsonFormat {
+		listType = sql.JSON
+	}
 	if ltf.showParents {
-		options = append(options, fmt.Sprintf("--%s", cli.ParentsFlag))
+		logSchema = append(logSchema, &sql.Column{Name: "parents", Type: listType, Nullable: true})
 	}
-
-	if len(ltf.decoration) > 0 && ltf.decoration != "auto" {
-		options = append(options, fmt.Sprintf("--%s %s", cli.DecorateFlag, ltf.decoration))
+	if shouldDecorateWithRefs(ltf.decoration) {
+		logSchema = append(logSchema, &sql.Column{Name: "refs", Type: listType, Nullable: true})
+	}
+	if ltf.showSources {
+		logSchema = append(logSchema, &sql.Column{Name: "sources", Type: listType, Nullable: true})
+	}
+	if ltf.showStat {
+		logSchema = append(logSchema, logTableStatSchema...)
+	}
+	if ltf.showGraph {
+		logSchema = append(logSchema, logTableGraphSchema...)
+	}
+	if ltf.showBoundary {
+		logSchema = append(logSchema, &sql.Column{Name: "is_boundary", Type: sql.Boolean})
+	}
+	for i, containsRef := range ltf.containsRefs {
+		logSchema = append(logSchema, &sql.Column{Name: containedColumnName(i), Type: sql.Boolean, Comment: containsRef})
+	}
+	if ltf.checkPolicy {
+		logSchema = append(logSchema, &sql.Column{Name: "policy_violation", Type: sql.Boolean, Nullable: true})
+	}
+	for _, provider := range ltf.columnProviders {
+		logSchema = append(logSchema, &sql.Column{Name: provider.Name, Type: provider.Type, Nullable: true})
 	}
 
-	return strings.Join(options, ", ")
+	return logSchema
 }
 
-// Schema implements the sql.Node interface.
-func (ltf *LogTableFunction) Schema() sql.Schema {
-	logSchema := logTableSchema
+// Projections returns the columns that the query reads from the function, following the semantics of
+// sql.ProjectedTable. Returns nil when the function has not been pruned, so that every column may be read.
+func (ltf *LogTableFunction) Projections() []string {
+	return ltf.projections
+}
 
-	if ltf.showParents {
-		logSchema = append(logSchema, &sql.Column{Name: "parents", Type: sql.Text})
+// WithProjections returns a copy of the function that only needs to produce the given columns, following the semantics
+// of sql.ProjectedTable, except that the schema is unchanged. Columns that are not projected may be returned as NULL.
+func (ltf *LogTableFunction) WithProjections(colNames []string) sql.Node {
+	nltf := *ltf
+	nltf.projections = colNames
+	return &nltf
+}
+
+// Limit returns the limit and offset that have been pushed into the function, and false when there are none.
+func (ltf *LogTableFunction) Limit() (int64, int64, bool) {
+	return ltf.limit, ltf.offset, ltf.limited
+}
+
+// WithLimit returns a copy of the function that skips |offset| rows, and then stops walking the commit graph once
+// |limit| rows have been returned.
+func (ltf *LogTableFunction) WithLimit(limit int64, offset int64) sql.Node {
+	nltf := *ltf
+	nltf.limited = true
+	nltf.limit = limit
+	nltf.offset = offset
+	return &nltf
+}
+
+// readsMessage returns whether the query reads a column that holds the commit message.
+func (ltf *LogTableFunction) readsMessage() bool {
+	return ltf.readsColumn("message") || ltf.readsColumn("subject")
+}
+
+// readsColumn returns whether the query reads the column with the given name.
+func (ltf *LogTableFunction) readsColumn(name string) bool {
+	if ltf.projections == nil {
+		return true
 	}
-	if shouldDecorateWithRefs(ltf.decoration) {
-		logSchema = append(logSchema, &sql.Column{Name: "refs", Type: sql.Text})
+	for _, col := range ltf.projections {
+		if strings.EqualFold(col, name) {
+			return true
+		}
 	}
-
-	return logSchema
+	return false
 }
 
 // Children implements the sql.Node interface.
@@ -163,9 +330,14 @@ func (ltf *LogTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.Pri
 		return false
 	}
 
+	// Grants are made on the base database, so they apply to each of its revision databases
+	dbName := ltf.database.Name()
+	if revDb, ok := ltf.database.(dsess.RevisionDatabase); ok && revDb.Revision() != "" {
+		dbName = strings.TrimSuffix(dbName, dbRevisionDelimiter+revDb.Revision())
+	}
 	var operations []sql.PrivilegedOperation
 	for _, tblName := range tblNames {
-		operations = append(operations, sql.NewPrivilegedOperation(ltf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+		operations = append(operations, sql.NewPrivilegedOperation(dbName, tblName, "", sql.PrivilegeType_Select))
 	}
 
 	return opChecker.UserHasPrivileges(ctx, operations...)
@@ -173,23 +345,16 @@ func (ltf *LogTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.Pri
 
 // Expressions implements the sql.Expressioner interface.
 func (ltf *LogTableFunction) Expressions() []sql.Expression {
-	exprs := []sql.Expression{}
-	if ltf.revisionExpr != nil {
-		exprs = append(exprs, ltf.revisionExpr)
-	}
-	if ltf.secondRevisionExpr != nil {
-		exprs = append(exprs, ltf.secondRevisionExpr)
-	}
-	return exprs
+	return ltf.argumentExprs
 }
 
 // getDoltArgs builds an argument string from sql expressions so that we can
 // later parse the arguments with the same util as the CLI
-func getDoltArgs(ctx *sql.Context, expressions []sql.Expression, functionName string) ([]string, error) {
+func getDoltArgs(ctx *sql.Context, row sql.Row, expressions []sql.Expression, functionName string) ([]string, error) {
 	var args []string
 
 	for _, expr := range expressions {
-		childVal, err := expr.Eval(ctx, nil)
+		childVal, err := expr.Eval(ctx, row)
 		if err != nil {
 			return nil, err
 		}
@@ -211,411 +376,2047 @@ func getDoltArgs(ctx *sql.Context, expressions []sql.Expression, functionName st
 	return args, nil
 }
 
-func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
-	args, err := getDoltArgs(ltf.ctx, expression, ltf.FunctionName())
-	if err != nil {
-		return err
-	}
+// logArguments are the evaluated and parsed arguments of dolt_log.
+type logArguments struct {
+	revisions []string
+	// revisionIndexes hold the index of each revision within the arguments, and notIndex holds the index of the last
+	// --not, which may be given once for each revision that is excluded
+	revisionIndexes []int
+	notRevisions    []string
+	notIndex        int
+	minParents      int
+	showParents     bool
+	decoration      string
+	showStat        bool
+	reverse         bool
+	database        string
+	showGraph       bool
+	// maxParents is the maximum number of parents of the commits in the log, and is -1 when not given
+	maxParents int
+	// startOrder and endOrder bound the commit_order of the commits in the log, and are -1 when not given
+	startOrder   int64
+	endOrder     int64
+	jsonFormat   bool
+	showBoundary bool
+	containsRefs []string
+	// showMergeBase appends the merge base of a range's revisions, and mergeBaseIndex holds the index of --merge-base
+	showMergeBase  bool
+	mergeBaseIndex int
+	// trailerConditions are given by --trailer, and excludedTrailers are the keys given by --no-trailer
+	trailerConditions []logTrailerCondition
+	excludedTrailers  []string
+	firstParent       bool
+	showShortHash     bool
+	abbrevLength      int
+	// fetchRemote is the remote given by --fetch, which is fetched before any revision is resolved, and fetchIndex
+	// holds the index of --fetch
+	fetchRemote string
+	fetchIndex  int
+	// sourceRefs are the types of refs that the log is read from when --all or --branches is given, rather than from a
+	// revision, and sourcesIndex holds the index of the option. perSourceLimit is 0 when not given.
+	sourceRefs     map[ref.RefType]struct{}
+	sourcesIndex   int
+	perSourceLimit int
+	// columnProviders are the providers named by --with, without duplicates, and withIndex holds the index of --with
+	columnProviders []LogColumnProvider
+	withIndex       int
+	// policyTable is the table given with --check-policy that maps committers to accounts, and policyIndex holds the
+	// index of --check-policy
+	policyTable string
+	policyIndex int
+	// since and until are the dates given with --since and --until, which are zero when not given
+	since time.Time
+	until time.Time
+	// tables are the tables given with --tables, and are nil when not given
+	tables []string
+	// warnings describe the arguments that were given more than once, and are emitted when the function is executed
+	warnings []string
+}
 
-	apr, err := cli.CreateLogArgParser().Parse(args)
-	if err != nil {
-		return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), err.Error())
-	}
+// LogDuplicateArgumentWarningCode is the code of the warnings emitted by dolt_log for duplicate arguments.
+const LogDuplicateArgumentWarningCode int = 1105 // Since this is our own custom warning we'll use 1105, the code for an unknown error
 
-	if notRevisionStr, ok := apr.GetValue(cli.NotFlag); ok {
-		ltf.notRevision = notRevisionStr
-	}
+// logDuplicatePolicy determines how dolt_log handles an option that is given more than once.
+type logDuplicatePolicy int
 
-	minParents := apr.GetIntOrDefault(cli.MinParentsFlag, 0)
-	if apr.Contains(cli.MergesFlag) {
-		minParents = 2
-	}
+const (
+	// logDuplicateIdempotent is used by flags, which have the same effect no matter how many times they're given
+	logDuplicateIdempotent logDuplicatePolicy = iota
+	// logDuplicateLastValue is used by options that take a single value, where the last value given is used
+	logDuplicateLastValue
+	// logDuplicateEachValue is used by options where every value is significant, such as --contains, which adds a
+	// column for each value. These duplicates are intended, so they do not add a warning.
+	logDuplicateEachValue
+)
 
-	ltf.minParents = minParents
-	ltf.showParents = apr.Contains(cli.ParentsFlag)
+// logDuplicatePolicies holds the policy of every option supported by dolt_log. Duplicate revisions are removed, keeping
+// the first occurrence of each. Every duplicate other than those of logDuplicateEachValue options adds a warning, as
+// the duplication is most likely accidental.
+var logDuplicatePolicies = map[string]logDuplicatePolicy{
+	cli.NumberFlag:      logDuplicateLastValue,
+	cli.MinParentsFlag:  logDuplicateLastValue,
+	cli.MergesFlag:      logDuplicateIdempotent,
+	cli.MaxParentsFlag:  logDuplicateLastValue,
+	cli.NoMergesFlag:    logDuplicateIdempotent,
+	cli.FetchParam:      logDuplicateLastValue,
+	cli.ParentsFlag:     logDuplicateIdempotent,
+	cli.DecorateFlag:    logDuplicateLastValue,
+	cli.OneLineFlag:     logDuplicateIdempotent,
+	cli.NotFlag:         logDuplicateEachValue,
+	cli.StatFlag:        logDuplicateIdempotent,
+	cli.ReverseFlag:     logDuplicateIdempotent,
+	cli.DatabaseParam:   logDuplicateLastValue,
+	cli.GraphFlag:       logDuplicateIdempotent,
+	cli.StartOrderParam: logDuplicateLastValue,
+	cli.EndOrderParam:   logDuplicateLastValue,
+	cli.FormatParam:     logDuplicateLastValue,
+	cli.ContainsParam:   logDuplicateEachValue,
+	cli.BoundaryFlag:    logDuplicateIdempotent,
+	cli.MergeBaseFlag:   logDuplicateIdempotent,
+	cli.TrailerParam:    logDuplicateEachValue,
+	cli.NoTrailerParam:  logDuplicateEachValue,
+	cli.FirstParentFlag: logDuplicateIdempotent,
+	cli.ShortHashFlag:   logDuplicateIdempotent,
+	cli.AbbrevParam:     logDuplicateLastValue,
+
+	// Options that read the log from refs rather than from a revision
+	cli.AllFlag:          logDuplicateIdempotent,
+	cli.BranchesFlag:     logDuplicateIdempotent,
+	cli.SourceLimitParam: logDuplicateLastValue,
+
+	// Options that add the columns of registered providers. Each provider adds one column, so a provider that's given
+	// more than once is removed with its own warning.
+	cli.WithParam: logDuplicateEachValue,
+
+	// Options that check the log against the current branch control rules
+	cli.CheckPolicyParam: logDuplicateLastValue,
+
+	// Options that restrict the log to a date range
+	cli.SinceParam: logDuplicateLastValue,
+	cli.UntilParam: logDuplicateLastValue,
+
+	// Options that restrict the log to the commits that changed tables
+	cli.TablesParam: logDuplicateLastValue,
+}
 
-	decorateOption := apr.GetValueOrDefault(cli.DecorateFlag, "auto")
-	switch decorateOption {
-	case "short", "full", "auto", "no":
-	default:
-		return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), fmt.Sprintf("invalid --decorate option: %s", decorateOption))
+// logDuplicateWarnings returns a warning for each option that was given more than once, in the order that the options
+// are supported by the parser, followed by a warning for each duplicate revision.
+func logDuplicateWarnings(ap *argparser.ArgParser, apr *argparser.ArgParseResults, duplicateRevisions []string) []string {
+	var warnings []string
+	for _, opt := range ap.Supported {
+		if apr.Occurrences(opt.Name) < 2 {
+			continue
+		}
+		switch logDuplicatePolicies[opt.Name] {
+		case logDuplicateIdempotent:
+			warnings = append(warnings, fmt.Sprintf("--%s was given more than once", opt.Name))
+		case logDuplicateLastValue:
+			warnings = append(warnings, fmt.Sprintf("--%s was given more than once, so the last value `%s` is used", opt.Name, apr.MustGetValue(opt.Name)))
+		}
 	}
-	ltf.decoration = decorateOption
-
-	return nil
+	for _, revision := range duplicateRevisions {
+		warnings = append(warnings, fmt.Sprintf("revision `%s` was given more than once", revision))
+	}
+	return warnings
 }
 
-// WithExpressions implements the sql.Expressioner interface.
-func (ltf *LogTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
-	for _, expr := range expression {
-		if !expr.Resolved() {
-			return nil, ErrInvalidNonLiteralArgument.New(ltf.FunctionName(), expr.String())
+// dedupeRevisions returns the revisions of the given results without duplicates, keeping the first occurrence of each,
+// along with the index of each kept revision within the arguments and the duplicates that were removed.
+func dedupeRevisions(apr *argparser.ArgParseResults) (deduped []string, indexes []int, duplicates []string) {
+	seen := make(map[string]struct{}, len(apr.Args))
+	for i, revision := range apr.Args {
+		if _, ok := seen[revision]; ok {
+			duplicates = append(duplicates, revision)
+			continue
 		}
+		seen[revision] = struct{}{}
+		deduped = append(deduped, revision)
+		indexes = append(indexes, apr.ArgIndex(i))
 	}
+	return deduped, indexes, duplicates
+}
 
-	if err := ltf.addOptions(expression); err != nil {
-		return nil, err
+// parseArguments parses the evaluated arguments. Arguments are classified as options or revisions by their values, so a
+// revision may contain any characters as long as it does not begin with a dash. Arguments may be given more than once,
+// in which case they're handled according to logDuplicatePolicies.
+func (ltf *LogTableFunction) parseArguments(args []string) (logArguments, error) {
+	ap := cli.CreateLogTableFunctionArgParser()
+	apr, err := ap.ParseAllowingDuplicates(args)
+	if err != nil {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), err.Error(), ArgumentErrorDetail{Index: -1, Code: ArgumentErrorInvalidOption})
+	}
+	revisions, revisionIndexes, duplicateRevisions := dedupeRevisions(apr)
+
+	parsed := logArguments{
+		revisions:       revisions,
+		revisionIndexes: revisionIndexes,
+		notIndex:        apr.OptionIndex(cli.NotFlag),
+		minParents:      apr.GetIntOrDefault(cli.MinParentsFlag, 0),
+		maxParents:      apr.GetIntOrDefault(cli.MaxParentsFlag, -1),
+		showParents:     apr.Contains(cli.ParentsFlag) || ltf.defaultShowParents,
+		decoration:      apr.GetValueOrDefault(cli.DecorateFlag, ltf.defaultDecoration),
+		showStat:        apr.Contains(cli.StatFlag),
+		reverse:         apr.Contains(cli.ReverseFlag),
+		database:        apr.GetValueOrDefault(cli.DatabaseParam, ""),
+		showGraph:       apr.Contains(cli.GraphFlag),
+		startOrder:      int64(apr.GetIntOrDefault(cli.StartOrderParam, -1)),
+		endOrder:        int64(apr.GetIntOrDefault(cli.EndOrderParam, -1)),
+		showBoundary:    apr.Contains(cli.BoundaryFlag),
+		// Every value is kept, even when the same ref is given twice, as each adds a column
+		containsRefs:   apr.GetValueList(cli.ContainsParam),
+		showMergeBase:  apr.Contains(cli.MergeBaseFlag),
+		mergeBaseIndex: apr.OptionIndex(cli.MergeBaseFlag),
+		firstParent:    apr.Contains(cli.FirstParentFlag),
+		showShortHash:  apr.Contains(cli.ShortHashFlag),
+		fetchRemote:    apr.GetValueOrDefault(cli.FetchParam, ""),
+		fetchIndex:     apr.OptionIndex(cli.FetchParam),
+		perSourceLimit: apr.GetIntOrDefault(cli.SourceLimitParam, 0),
+		policyTable:    apr.GetValueOrDefault(cli.CheckPolicyParam, ""),
+		policyIndex:    apr.OptionIndex(cli.CheckPolicyParam),
+		notRevisions:   apr.GetValueList(cli.NotFlag),
+		warnings:       logDuplicateWarnings(ap, apr, duplicateRevisions),
 	}
-
-	// Gets revisions, excluding any flag-related expression
-	var filteredExpressions []sql.Expression
-	for i, ex := range expression {
-		if !strings.Contains(ex.String(), "--") && !(i > 0 && strings.Contains(expression[i-1].String(), "--")) {
-			filteredExpressions = append(filteredExpressions, ex)
+	if apr.Contains(cli.MergesFlag) {
+		parsed.minParents = 2
+	}
+	if apr.Contains(cli.MaxParentsFlag) && parsed.maxParents < 0 {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--max-parents must not be negative", logOptionErrorDetail(apr, cli.MaxParentsFlag, ArgumentErrorInvalidValue))
+	}
+	if apr.Contains(cli.NoMergesFlag) {
+		if apr.Contains(cli.MergesFlag) {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), "--merges cannot be used with --no-merges", logOptionErrorDetail(apr, cli.NoMergesFlag, ArgumentErrorConflictingOptions))
+		}
+		// --no-merges only lowers the limit, so that it may be combined with --max-parents 0
+		if parsed.maxParents < 0 || parsed.maxParents > 1 {
+			parsed.maxParents = 1
 		}
 	}
-
-	if len(filteredExpressions) > 2 {
-		return nil, sql.ErrInvalidArgumentNumber.New(ltf.FunctionName(), "0 to 2", len(filteredExpressions))
+	if abbrevStr, ok := apr.GetValue(cli.AbbrevParam); ok {
+		parsed.abbrevLength = logDefaultAbbrevLength
+		if abbrevStr != "" {
+			length, err := strconv.Atoi(abbrevStr)
+			if err != nil || length < logMinAbbrevLength || length > hash.StringLen {
+				return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --abbrev option: %s, the length must be between %d and %d", abbrevStr, logMinAbbrevLength, hash.StringLen), logOptionErrorDetail(apr, cli.AbbrevParam, ArgumentErrorInvalidValue))
+			}
+			parsed.abbrevLength = length
+		}
 	}
 
-	exLen := len(filteredExpressions)
-	if exLen > 0 {
-		ltf.revisionExpr = filteredExpressions[0]
+	for _, trailer := range apr.GetValueList(cli.TrailerParam) {
+		cond, err := newLogTrailerCondition(trailer)
+		if err != nil {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --trailer option: %s", err.Error()), logOptionErrorDetail(apr, cli.TrailerParam, ArgumentErrorInvalidValue))
+		}
+		parsed.trailerConditions = append(parsed.trailerConditions, cond)
 	}
-	if exLen == 2 {
-		ltf.secondRevisionExpr = filteredExpressions[1]
+	for _, key := range apr.GetValueList(cli.NoTrailerParam) {
+		if key == "" || strings.Contains(key, "=") {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --no-trailer option: %s", key), logOptionErrorDetail(apr, cli.NoTrailerParam, ArgumentErrorInvalidValue))
+		}
+		parsed.excludedTrailers = append(parsed.excludedTrailers, key)
 	}
 
-	if err := ltf.validateRevisionExpressions(); err != nil {
-		return nil, err
+	switch format := apr.GetValueOrDefault(cli.FormatParam, "text"); format {
+	case "text":
+	case "json":
+		parsed.jsonFormat = true
+	default:
+		return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --format option: %s", format), logOptionErrorDetail(apr, cli.FormatParam, ArgumentErrorInvalidValue))
 	}
 
-	return ltf, nil
-}
+	switch parsed.decoration {
+	case "short", "full", "auto", "no":
+	default:
+		return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --decorate option: %s", parsed.decoration), logOptionErrorDetail(apr, cli.DecorateFlag, ArgumentErrorInvalidValue))
+	}
 
-func (ltf *LogTableFunction) invalidArgDetailsErr(expr sql.Expression, reason string) *errors.Error {
-	return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), fmt.Sprintf("%s - %s", expr.String(), reason))
-}
+	if apr.Contains(cli.FetchParam) && parsed.fetchRemote == "" {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--fetch requires the name of a remote", logOptionErrorDetail(apr, cli.FetchParam, ArgumentErrorInvalidValue))
+	}
 
-func (ltf *LogTableFunction) validateRevisionExpressions() error {
-	if ltf.revisionExpr != nil {
-		if !sql.IsText(ltf.revisionExpr.Type()) {
-			return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), ltf.revisionExpr.String())
-		}
-		if ltf.secondRevisionExpr == nil && strings.Contains(ltf.revisionExpr.String(), "^") {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "second revision must exist if first revision contains '^'")
+	// --all already includes every branch, so it takes precedence when both are given
+	if apr.Contains(cli.AllFlag) {
+		parsed.sourceRefs = decorationRefFilter
+		parsed.sourcesIndex = apr.OptionIndex(cli.AllFlag)
+	} else if apr.Contains(cli.BranchesFlag) {
+		parsed.sourceRefs = map[ref.RefType]struct{}{ref.BranchRefType: {}}
+		parsed.sourcesIndex = apr.OptionIndex(cli.BranchesFlag)
+	}
+	if parsed.sourceRefs != nil {
+		flag := cli.BranchesFlag
+		if apr.Contains(cli.AllFlag) {
+			flag = cli.AllFlag
 		}
-		if strings.Contains(ltf.revisionExpr.String(), "..") && strings.Contains(ltf.revisionExpr.String(), "^") {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "revision cannot contain both '..' and '^'")
+		if len(parsed.revisions) > 0 || apr.Contains(cli.NotFlag) {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("--%s cannot be used with revisions", flag), ArgumentErrorDetail{Index: parsed.sourcesIndex, Flag: flag, Code: ArgumentErrorConflictingOptions})
 		}
 	}
-
-	if ltf.secondRevisionExpr != nil {
-		if !sql.IsText(ltf.secondRevisionExpr.Type()) {
-			return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), ltf.secondRevisionExpr.String())
-		}
-		if strings.Contains(ltf.secondRevisionExpr.String(), "..") {
-			return ltf.invalidArgDetailsErr(ltf.secondRevisionExpr, "second revision cannot contain '..'")
+	if apr.Contains(cli.SourceLimitParam) {
+		if parsed.perSourceLimit <= 0 {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), "--per-source-limit must be positive", logOptionErrorDetail(apr, cli.SourceLimitParam, ArgumentErrorInvalidValue))
 		}
-		if strings.Contains(ltf.revisionExpr.String(), "..") {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "revision cannot contain '..' if second revision exists")
+		if parsed.sourceRefs == nil {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), "--per-source-limit requires --all or --branches", logOptionErrorDetail(apr, cli.SourceLimitParam, ArgumentErrorConflictingOptions))
 		}
 	}
 
-	if ltf.revisionExpr != nil && ltf.secondRevisionExpr != nil {
-		if strings.Contains(ltf.revisionExpr.String(), "^") && strings.Contains(ltf.secondRevisionExpr.String(), "^") {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "both revisions cannot contain '^'")
+	parsed.withIndex = apr.OptionIndex(cli.WithParam)
+	for _, name := range apr.GetValueList(cli.WithParam) {
+		provider, ok := getLogColumnProvider(name)
+		if !ok {
+			msg := fmt.Sprintf("invalid --with option: unknown column provider `%s`, expected one of: %s", name, strings.Join(logColumnProviderNames(), ", "))
+			return logArguments{}, newArgumentError(ltf.FunctionName(), msg, logOptionErrorDetail(apr, cli.WithParam, ArgumentErrorInvalidValue))
 		}
-		if !strings.Contains(ltf.revisionExpr.String(), "^") && !strings.Contains(ltf.secondRevisionExpr.String(), "^") {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "one revision must contain '^' if two revisions provided")
+		if hasLogColumnProvider(parsed.columnProviders, provider.Name) {
+			parsed.warnings = append(parsed.warnings, fmt.Sprintf("--with provider `%s` was given more than once", provider.Name))
+			continue
 		}
+		parsed.columnProviders = append(parsed.columnProviders, provider)
 	}
 
-	if len(ltf.notRevision) > 0 {
-		if ltf.revisionExpr == nil && ltf.secondRevisionExpr == nil {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "must have revision in order to use --not")
+	if apr.Contains(cli.CheckPolicyParam) {
+		if parsed.policyTable == "" {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), "--check-policy requires the name of a table", logOptionErrorDetail(apr, cli.CheckPolicyParam, ArgumentErrorInvalidValue))
 		}
-		if ltf.revisionExpr != nil && (strings.Contains(ltf.revisionExpr.String(), "..") || strings.Contains(ltf.revisionExpr.String(), "^")) {
-			return ltf.invalidArgDetailsErr(ltf.revisionExpr, "cannot use --not if '..' or '^' present in revision")
+		// Commits are checked against a single branch, while the refs of --all and --branches may be anything
+		if parsed.sourceRefs != nil {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), "--check-policy cannot be used with --all or --branches", logOptionErrorDetail(apr, cli.CheckPolicyParam, ArgumentErrorConflictingOptions))
 		}
-		if ltf.secondRevisionExpr != nil && strings.Contains(ltf.secondRevisionExpr.String(), "^") {
-			return ltf.invalidArgDetailsErr(ltf.secondRevisionExpr, "cannot use --not if '^' present in second revision")
+	}
+
+	for _, opt := range []string{cli.SinceParam, cli.UntilParam} {
+		dateStr, ok := apr.GetValue(opt)
+		if !ok {
+			continue
 		}
-		if strings.Contains(ltf.notRevision, "..") {
-			return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), fmt.Sprintf("%s - %s", ltf.notRevision, "--not revision cannot contain '..'"))
+		t, err := cli.ParseDate(dateStr)
+		if err != nil {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --%s option: %s is not a supported date", opt, dateStr), logOptionErrorDetail(apr, opt, ArgumentErrorInvalidValue))
 		}
-		if strings.Contains(ltf.notRevision, "^") {
-			return sql.ErrInvalidArgumentDetails.New(ltf.FunctionName(), fmt.Sprintf("%s - %s", ltf.notRevision, "--not revision cannot contain '^'"))
+		if opt == cli.SinceParam {
+			parsed.since = t
+		} else {
+			parsed.until = t
 		}
 	}
+	if !parsed.since.IsZero() && !parsed.until.IsZero() && parsed.since.After(parsed.until) {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--until must not be before --since", logOptionErrorDetail(apr, cli.UntilParam, ArgumentErrorConflictingOptions))
+	}
 
-	return nil
-}
+	if tablesStr, ok := apr.GetValue(cli.TablesParam); ok {
+		tables, ok := parseLogTables(tablesStr)
+		if !ok {
+			return logArguments{}, newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --tables option: %s", tablesStr), logOptionErrorDetail(apr, cli.TablesParam, ArgumentErrorInvalidValue))
+		}
+		parsed.tables = tables
+	}
 
-// RowIter implements the sql.Node interface
-func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
-	revisionVal, excludingRevisionVal, err := ltf.evaluateArguments()
-	if err != nil {
-		return nil, err
+	if apr.Contains(cli.StartOrderParam) && parsed.startOrder < 0 {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--start-order must not be negative", logOptionErrorDetail(apr, cli.StartOrderParam, ArgumentErrorInvalidValue))
+	}
+	if apr.Contains(cli.EndOrderParam) && parsed.endOrder < 0 {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--end-order must not be negative", logOptionErrorDetail(apr, cli.EndOrderParam, ArgumentErrorInvalidValue))
 	}
 
-	sqledb, ok := ltf.database.(Database)
-	if !ok {
-		return nil, fmt.Errorf("unexpected database type: %T", ltf.database)
+	// The merge base is appended after the child is exhausted, where it has no place in the graph
+	if parsed.showMergeBase && parsed.showGraph {
+		return logArguments{}, newArgumentError(ltf.FunctionName(), "--merge-base cannot be used with --graph", logOptionErrorDetail(apr, cli.MergeBaseFlag, ArgumentErrorConflictingOptions))
 	}
 
-	sess := dsess.DSessFromSess(ctx.Session)
-	var commit *doltdb.Commit
+	return parsed, nil
+}
 
-	if len(revisionVal) > 0 {
-		cs, err := doltdb.NewCommitSpec(revisionVal)
-		if err != nil {
-			return nil, err
+// hasLogColumnProvider returns whether the given providers include one with the given name.
+func hasLogColumnProvider(providers []LogColumnProvider, name string) bool {
+	for _, provider := range providers {
+		if strings.EqualFold(provider.Name, name) {
+			return true
 		}
+	}
+	return false
+}
 
-		commit, err = sqledb.ddb.Resolve(ctx, cs, nil)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// If revisionExpr not defined, use session head
-		commit, err = sess.GetHeadCommit(ctx, sqledb.name)
-		if err != nil {
-			return nil, err
+// logOptionErrorDetail returns the detail of an error caused by the last occurrence of the given option.
+func logOptionErrorDetail(apr *argparser.ArgParseResults, option string, code ArgumentErrorCode) ArgumentErrorDetail {
+	return ArgumentErrorDetail{Index: apr.OptionIndex(option), Flag: option, Code: code}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ltf *LogTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	ltf.argumentExprs = exprs
+	for _, expr := range exprs {
+		// Unresolved arguments are evaluated once the analyzer has resolved them
+		if !expr.Resolved() {
+			return ltf, nil
 		}
 	}
 
-	matchFunc := func(commit *doltdb.Commit) (bool, error) {
-		return commit.NumParents() >= ltf.minParents, nil
+	// Options determine the schema, so they're parsed here. Arguments that depend on a row can only be evaluated in
+	// RowIter, and therefore must be revisions, so an empty placeholder takes their place until then.
+	constantExprs := make([]sql.Expression, len(exprs))
+	hasRowDependentArgs := false
+	for i, expr := range exprs {
+		if logArgumentDependsOnRow(expr) {
+			constantExprs[i] = expression.NewLiteral("", sql.LongText)
+			hasRowDependentArgs = true
+		} else {
+			constantExprs[i] = expr
+		}
 	}
 
-	cHashToRefs, err := getCommitHashToRefs(ctx, sqledb.ddb, ltf.decoration)
+	args, err := getDoltArgs(ltf.ctx, nil, constantExprs, ltf.FunctionName())
 	if err != nil {
 		return nil, err
 	}
-
-	// Two dot log
-	if len(excludingRevisionVal) > 0 {
-		exCs, err := doltdb.NewCommitSpec(excludingRevisionVal)
-		if err != nil {
+	parsed, err := ltf.parseArguments(args)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRowDependentArgs {
+		if err = ltf.validateRevisions(parsed); err != nil {
 			return nil, err
 		}
+	}
+	if err = ltf.resolveDatabaseArgument(parsed); err != nil {
+		return nil, err
+	}
 
-		excludingCommit, err := sqledb.ddb.Resolve(ctx, exCs, nil)
-		if err != nil {
-			return nil, err
+	ltf.showParents = parsed.showParents
+	ltf.decoration = parsed.decoration
+	ltf.showStat = parsed.showStat
+	ltf.showGraph = parsed.showGraph
+	ltf.jsonFormat = parsed.jsonFormat
+	ltf.showBoundary = parsed.showBoundary
+	ltf.containsRefs = parsed.containsRefs
+	ltf.firstParent = parsed.firstParent
+	ltf.showShortHash = parsed.showShortHash
+	ltf.abbrevLength = parsed.abbrevLength
+	ltf.showSources = parsed.sourceRefs != nil
+	ltf.checkPolicy = parsed.policyTable != ""
+	ltf.columnProviders = parsed.columnProviders
+
+	// Providers are appended last, so a provider's column may only clash with the columns that precede it
+	sch := ltf.Schema()
+	for i, provider := range ltf.columnProviders {
+		for _, col := range sch[:len(sch)-len(ltf.columnProviders)+i] {
+			if strings.EqualFold(col.Name, provider.Name) {
+				detail := ArgumentErrorDetail{Index: parsed.withIndex, Flag: cli.WithParam, Code: ArgumentErrorConflictingOptions}
+				return nil, newArgumentError(ltf.FunctionName(), fmt.Sprintf("--with provider `%s` clashes with the column of the same name", provider.Name), detail)
+			}
 		}
-		return ltf.NewDotDotLogTableFunctionRowIter(ctx, sqledb.ddb, commit, excludingCommit, matchFunc, cHashToRefs)
 	}
-
-	return ltf.NewLogTableFunctionRowIter(ctx, sqledb.ddb, commit, matchFunc, cHashToRefs)
+	return ltf, nil
 }
 
-func getCommitHashToRefs(ctx *sql.Context, ddb *doltdb.DoltDB, decoration string) (map[hash.Hash][]string, error) {
-	cHashToRefs := map[hash.Hash][]string{}
-
-	// Get all branches
-	branches, err := ddb.GetBranchesWithHashes(ctx)
-	if err != nil {
-		return nil, err
-	}
-	for _, b := range branches {
-		refName := b.Ref.String()
-		if decoration != "full" {
-			refName = b.Ref.GetPath() // trim out "refs/heads/"
+// resolveDatabaseArgument replaces the function's database with the one named by --database, as though the function
+// had been qualified with that database. The database is resolved through the session's provider, so it may be revision
+// qualified.
+func (ltf *LogTableFunction) resolveDatabaseArgument(args logArguments) error {
+	if args.database == "" {
+		// A row-dependent --database value is replaced with an empty placeholder, but the database must be known before
+		// privileges are checked
+		for i, expr := range ltf.argumentExprs {
+			if i > 0 && logArgumentDependsOnRow(expr) && isDatabaseOption(ltf.argumentExprs[i-1]) {
+				return newArgumentError(ltf.FunctionName(), "--database must be a constant", ArgumentErrorDetail{Index: i - 1, Flag: cli.DatabaseParam, Code: ArgumentErrorNotConstant})
+			}
 		}
-		cHashToRefs[b.Hash] = append(cHashToRefs[b.Hash], refName)
+		return nil
 	}
-
-	// Get all remote branches
-	remotes, err := ddb.GetRemotesWithHashes(ctx)
+	if ltf.database != nil && strings.EqualFold(ltf.database.Name(), args.database) {
+		return nil
+	}
+	sess := dsess.DSessFromSess(ltf.ctx.Session)
+	db, err := sess.Provider().Database(ltf.ctx, args.database)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, r := range remotes {
-		refName := r.Ref.String()
-		if decoration != "full" {
-			refName = r.Ref.GetPath() // trim out "refs/remotes/"
+
+	// The session may have been created before the database, in which case its state is loaded now, just as when
+	// starting a transaction
+	if _, _, err = sess.LookupDbState(ltf.ctx, db.Name()); err != nil {
+		sqledb, ok := logDatabase(db)
+		if !ok {
+			return err
+		}
+		init, err := GetInitialDBState(ltf.ctx, sqledb)
+		if err != nil {
+			return err
+		}
+		if err = sess.AddDB(ltf.ctx, init); err != nil {
+			return err
 		}
-		cHashToRefs[r.Hash] = append(cHashToRefs[r.Hash], refName)
 	}
 
-	// Get all tags
-	tags, err := ddb.GetTagsWithHashes(ctx)
-	if err != nil {
-		return nil, err
+	ltf.database = db
+	return nil
+}
+
+// isDatabaseOption returns whether the given argument is a literal --database option.
+func isDatabaseOption(expr sql.Expression) bool {
+	lit, ok := expr.(*expression.Literal)
+	if !ok {
+		return false
 	}
-	for _, t := range tags {
-		tagName := t.Tag.GetDoltRef().String()
-		if decoration != "full" {
-			tagName = t.Tag.Name // trim out "refs/tags/"
+	str, ok := lit.Value().(string)
+	return ok && strings.TrimLeft(str, "-") == cli.DatabaseParam && strings.HasPrefix(str, "-")
+}
+
+// logArgumentDependsOnRow returns whether the given argument references a column, a bind variable, or a subquery, none
+// of which may be evaluated until the function is executed.
+func logArgumentDependsOnRow(expr sql.Expression) bool {
+	return transform.InspectExpr(expr, func(e sql.Expression) bool {
+		switch e.(type) {
+		case *expression.GetField, *expression.BindVar, *plan.Subquery:
+			return true
+		default:
+			return false
 		}
-		tagName = fmt.Sprintf("tag: %s", tagName)
-		cHashToRefs[t.Hash] = append(cHashToRefs[t.Hash], tagName)
-	}
+	})
+}
 
-	return cHashToRefs, nil
+// invalidRevisionErr returns an error for the revision at the given index of the revisions, which fails validation for
+// the given reason.
+func (ltf *LogTableFunction) invalidRevisionErr(args logArguments, i int, reason string, code ArgumentErrorCode) *errors.Error {
+	return newArgumentError(ltf.FunctionName(), fmt.Sprintf("%s - %s", args.revisions[i], reason), ArgumentErrorDetail{Index: args.revisionIndexes[i], Code: code})
 }
 
-// evaluateArguments returns revisionValStr and excludingRevisionValStr.
-// It evaluates the argument expressions to turn them into values this LogTableFunction
-// can use. Note that this method only evals the expressions, and doesn't validate the values.
-func (ltf *LogTableFunction) evaluateArguments() (string, string, error) {
-	var revisionValStr string
-	var excludingRevisionValStr string
-	var err error
+// invalidNotRevisionErr returns an error for the given --not revision, which fails validation for the given reason.
+func (ltf *LogTableFunction) invalidNotRevisionErr(args logArguments, notRevision string, reason string, code ArgumentErrorCode) *errors.Error {
+	return newArgumentError(ltf.FunctionName(), fmt.Sprintf("%s - %s", notRevision, reason), ArgumentErrorDetail{Index: args.notIndex, Flag: cli.NotFlag, Code: code})
+}
 
-	if ltf.revisionExpr != nil {
-		revisionValStr, excludingRevisionValStr, err = getRevisionsFromExpr(ltf.ctx, ltf.revisionExpr, true)
-		if err != nil {
-			return "", "", err
+// validateRevisions checks that the evaluated revisions form a valid combination. Like git log, any number of revisions
+// may be given, each of which is included, excluded with '^', or a '..' range that includes one revision and excludes
+// another. A '...' range must be the only revision.
+func (ltf *LogTableFunction) validateRevisions(args logArguments) error {
+	included, excluded := 0, 0
+	for i, revision := range args.revisions {
+		if strings.Contains(revision, "...") && len(args.revisions) > 1 {
+			return ltf.invalidRevisionErr(args, i, "revision cannot contain '...' if other revisions exist", ArgumentErrorConflictingRevisions)
+		}
+		if strings.Contains(revision, "..") && strings.Contains(revision, "^") {
+			return ltf.invalidRevisionErr(args, i, "revision cannot contain both '..' and '^'", ArgumentErrorInvalidRevision)
 		}
+		switch {
+		case strings.Contains(revision, ".."):
+			included++
+			excluded++
+		case strings.Contains(revision, "^"):
+			excluded++
+		default:
+			included++
+		}
+	}
+	if excluded > 0 && included == 0 {
+		return ltf.invalidRevisionErr(args, 0, "a revision without '^' must exist if every revision contains '^'", ArgumentErrorMissingRevision)
 	}
 
-	if ltf.secondRevisionExpr != nil {
-		rvs, ervs, err := getRevisionsFromExpr(ltf.ctx, ltf.secondRevisionExpr, false)
-		if err != nil {
-			return "", "", err
+	if len(args.notRevisions) > 0 {
+		if included == 0 {
+			return ltf.invalidNotRevisionErr(args, args.notRevisions[0], "must have revision in order to use --not", ArgumentErrorMissingRevision)
 		}
-		if len(rvs) > 0 {
-			revisionValStr = rvs
+		if len(args.revisions) == 1 && strings.Contains(args.revisions[0], "...") {
+			return ltf.invalidRevisionErr(args, 0, "cannot use --not with a '...' range, which already excludes the ancestors of the merge base", ArgumentErrorConflictingRevisions)
 		}
-		if len(ervs) > 0 {
-			excludingRevisionValStr = ervs
+		for _, notRevision := range args.notRevisions {
+			if strings.Contains(notRevision, "..") {
+				return ltf.invalidNotRevisionErr(args, notRevision, "--not revision cannot contain '..'", ArgumentErrorInvalidRevision)
+			}
+			if strings.Contains(notRevision, "^") {
+				return ltf.invalidNotRevisionErr(args, notRevision, "--not revision cannot contain '^'", ArgumentErrorInvalidRevision)
+			}
 		}
+		excluded += len(args.notRevisions)
 	}
 
-	if len(ltf.notRevision) > 0 {
-		excludingRevisionValStr = ltf.notRevision
+	if args.showMergeBase {
+		if excluded == 0 {
+			return newArgumentError(ltf.FunctionName(), "--merge-base requires a range of revisions", ArgumentErrorDetail{Index: args.mergeBaseIndex, Flag: cli.MergeBaseFlag, Code: ArgumentErrorMissingRevision})
+		}
+		// The merge base is that of a single pair of revisions
+		if included > 1 || excluded > 1 {
+			return newArgumentError(ltf.FunctionName(), "--merge-base requires a range of exactly two revisions", ArgumentErrorDetail{Index: args.mergeBaseIndex, Flag: cli.MergeBaseFlag, Code: ArgumentErrorConflictingRevisions})
+		}
 	}
 
-	return revisionValStr, excludingRevisionValStr, nil
+	return nil
 }
 
-// Gets revisionName and/or excludingRevisionName from sql expression
-func getRevisionsFromExpr(ctx *sql.Context, expr sql.Expression, canDot bool) (string, string, error) {
-	revisionVal, err := expr.Eval(ctx, nil)
+// RowIter implements the sql.Node interface. When no revision is given, the log begins at the head of the function's
+// database, which for a revision database such as `mydb/<commit>` is the commit that it pins. Revisions that are given,
+// and the refs that decorate the log, are always resolved as they are now. Table functions cannot be qualified with
+// AS OF, so a revision database, either in use or named with --database, is how the log is pinned to a commit.
+func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (_ sql.RowIter, err error) {
+	args, revisions, err := ltf.evaluateArguments(ctx, row)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	revisionValStr, ok := revisionVal.(string)
+	// The query's span lasts until the returned iterator is closed, unless the iterator is never returned
+	ctx, lt := startLogTrace(ctx)
+	defer func() {
+		if err != nil {
+			lt.end()
+		}
+	}()
+	sqledb, ok := logDatabase(ltf.database)
 	if !ok {
-		return "", "", fmt.Errorf("received '%v' when expecting revision string", revisionVal)
-	}
-
-	if canDot && strings.Contains(revisionValStr, "..") {
-		refs := strings.Split(revisionValStr, "..")
-		return refs[1], refs[0], nil
+		return nil, fmt.Errorf("unexpected database type: %T", ltf.database)
 	}
 
-	if strings.Contains(revisionValStr, "^") {
-		return "", strings.TrimPrefix(revisionValStr, "^"), nil
+	if args.fetchRemote != "" {
+		if err = ltf.fetchRemote(ctx, sqledb, args); err != nil {
+			return nil, err
+		}
 	}
 
-	return revisionValStr, "", nil
-}
-
-//------------------------------------
-// logTableFunctionRowIter
-//------------------------------------
-
-var _ sql.RowIter = (*logTableFunctionRowIter)(nil)
-
-// logTableFunctionRowIter is a sql.RowIter implementation which iterates over each commit as if it's a row in the table.
-type logTableFunctionRowIter struct {
-	child       doltdb.CommitItr
-	showParents bool
-	decoration  string
-	cHashToRefs map[hash.Hash][]string
-	headHash    hash.Hash
-}
-
-func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]string) (*logTableFunctionRowIter, error) {
-	hash, err := commit.HashOf()
+	var commit *doltdb.Commit
+	resolveSpan, _ := ctx.Span(logSpanResolve)
+	headCommit, checkedOut, err := logDatabaseHead(ctx, sqledb)
 	if err != nil {
+		resolveSpan.End()
 		return nil, err
 	}
 
-	child, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, hash, matchFn)
+	commits, err := ltf.resolveRevisions(ctx, sqledb, revisions.included)
 	if err != nil {
+		resolveSpan.End()
 		return nil, err
 	}
-
-	return &logTableFunctionRowIter{
-		child:       child,
-		showParents: ltf.showParents,
-		decoration:  ltf.decoration,
-		cHashToRefs: cHashToRefs,
-		headHash:    hash,
-	}, nil
-}
-
-func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit, excludingCommit *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]string) (*logTableFunctionRowIter, error) {
-	hash, err := commit.HashOf()
-	if err != nil {
-		return nil, err
+	if len(commits) > 0 {
+		commit = commits[0]
+	} else {
+		// If no revision was given, use the database's head
+		commit = headCommit
+		commits = []*doltdb.Commit{headCommit}
 	}
+	resolveSpan.End()
 
-	exHash, err := excludingCommit.HashOf()
-	if err != nil {
-		return nil, err
+	var trailerFilter *logTrailerFilter
+	if len(args.trailerConditions) > 0 || len(args.excludedTrailers) > 0 {
+		trailerFilter = newLogTrailerFilter(args.trailerConditions, args.excludedTrailers)
 	}
-
-	child, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, hash, exHash, matchFn)
-	if err != nil {
-		return nil, err
+	var tableFilter *logTableFilter
+	if len(args.tables) > 0 {
+		tableFilter = newLogTableFilter(sqledb.ddb, args.tables)
 	}
-
-	return &logTableFunctionRowIter{
-		child:       child,
-		showParents: ltf.showParents,
-		decoration:  ltf.decoration,
-		cHashToRefs: cHashToRefs,
-		headHash:    hash,
-	}, nil
-}
-
-// Next retrieves the next row. It will return io.EOF if it's the last row.
-// After retrieving the last row, Close will be automatically closed.
-func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
-	h, cm, err := itr.child.Next(ctx)
-	if err != nil {
-		return nil, err
+	matchFunc := func(commit *doltdb.Commit) (bool, error) {
+		if commit.NumParents() < args.minParents {
+			return false, nil
+		}
+		if args.maxParents >= 0 && commit.NumParents() > args.maxParents {
+			return false, nil
+		}
+		if trailerFilter != nil {
+			if ok, err := trailerFilter.matches(ctx, commit); err != nil || !ok {
+				return false, err
+			}
+		}
+		if tableFilter != nil {
+			return tableFilter.matches(ctx, commit)
+		}
+		return true, nil
 	}
 
-	meta, err := cm.GetCommitMeta(ctx)
-	if err != nil {
-		return nil, err
+	if hook := logTableFunctionHooks.afterResolve; hook != nil {
+		if err = hook(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	row := sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description)
-
-	if itr.showParents {
-		prStr, err := getParentsString(ctx, cm)
+	var cHashToRefs map[hash.Hash][]logRef
+	if shouldDecorateWithRefs(ltf.decoration) {
+		decorateSpan, _ := ctx.Span(logSpanDecorate)
+		cHashToRefs, err = getCommitHashToRefs(ctx, sqledb.ddb, ltf.decoration, checkedOut)
+		decorateSpan.End()
 		if err != nil {
 			return nil, err
 		}
-		row = row.Append(sql.NewRow(prStr))
 	}
 
-	if shouldDecorateWithRefs(itr.decoration) {
-		branchNames := itr.cHashToRefs[h]
-		isHead := itr.headHash == h
-		row = row.Append(sql.NewRow(getRefsString(branchNames, isHead)))
+	var itr *logTableFunctionRowIter
+	if args.sourceRefs != nil {
+		itr, err = ltf.NewSourceLogTableFunctionRowIter(ctx, sqledb.ddb, args, headCommit, checkedOut, matchFunc, cHashToRefs)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(revisions.excluded) > 0 || len(commits) > 1 {
+		// Two and three dot log, or the log of several revisions
+		excludingCommits, err := ltf.resolveRevisions(ctx, sqledb, revisions.excluded)
+		if err != nil {
+			return nil, err
+		}
+
+		// A three dot range, and a range with --merge-base, exclude exactly one revision
+		var mergeBase *doltdb.Commit
+		if revisions.symmetric || args.showMergeBase {
+			// Revisions with unrelated histories have no merge base, so nothing is excluded from a three dot log
+			mergeBase, err = doltdb.GetCommitAncestor(ctx, excludingCommits[0], commit)
+			if err == doltdb.ErrNoCommonAncestor {
+				mergeBase = nil
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		if revisions.symmetric {
+			itr, err = ltf.NewThreeDotLogTableFunctionRowIter(ctx, sqledb.ddb, excludingCommits[0], commit, mergeBase, matchFunc, cHashToRefs)
+		} else {
+			itr, err = ltf.NewDotDotLogTableFunctionRowIter(ctx, sqledb.ddb, commits, excludingCommits, matchFunc, cHashToRefs)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if args.showMergeBase && mergeBase != nil {
+			itr.mergeBase = mergeBase
+			itr.mergeBaseHash, err = mergeBase.HashOf()
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		itr, err = ltf.NewLogTableFunctionRowIter(ctx, sqledb.ddb, commit, matchFunc, cHashToRefs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The commit_order of each commit is its height, and commits are emitted in descending order of height, so the
+	// range is applied before the commits are reversed
+	if args.startOrder >= 0 || args.endOrder >= 0 {
+		minHeight, maxHeight := uint64(0), uint64(math.MaxUint64)
+		if args.endOrder >= 0 {
+			minHeight = uint64(args.endOrder)
+		}
+		if args.startOrder >= 0 {
+			maxHeight = uint64(args.startOrder)
+		}
+		// The commits of each height are read from the parent closure of a single commit, which includes commits off
+		// the first-parent path, so a first-parent walk or a walk from several refs is filtered instead
+		if len(revisions.excluded) > 0 || len(commits) > 1 || ltf.firstParent || args.sourceRefs != nil {
+			itr.child = commitwalk.FilterHeightRange(itr.child, minHeight, maxHeight)
+		} else {
+			itr.child, err = commitwalk.GetHeightRangeIterator(ctx, sqledb.ddb, itr.headHash, minHeight, maxHeight, matchFunc)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Commits are walked in descending order of height, so the walk stops soon after it passes the start of the range
+	if !args.since.IsZero() || !args.until.IsZero() {
+		itr.child = commitwalk.FilterDateRange(itr.child, args.since, args.until)
+	}
+
+	// Each ref's ancestors are walked alongside the log, so the walk never goes below the oldest commit that is logged
+	for _, containsRef := range args.containsRefs {
+		containsCommit, err := ltf.resolveRevision(ctx, sqledb, containsRef, ArgumentErrorDetail{Index: -1, Flag: cli.ContainsParam})
+		if err != nil {
+			return nil, err
+		}
+		containsHash, err := containsCommit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		ancestors, err := commitwalk.NewAncestorSet(ctx, sqledb.ddb, containsHash)
+		if err != nil {
+			return nil, err
+		}
+		itr.containsSets = append(itr.containsSets, ancestors)
+	}
+
+	if args.policyTable != "" && ltf.readsColumn("policy_violation") {
+		branch, err := ltf.policyBranch(ctx, sqledb, args, revisions, checkedOut)
+		if err != nil {
+			return nil, err
+		}
+		itr.policy, err = newLogPolicyChecker(ctx, ltf, sqledb, args, branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	itr.checkPolicy = args.policyTable != ""
+
+	// Providers are only consulted for the columns that the query reads
+	for i := range ltf.columnProviders {
+		var provider *LogColumnProvider
+		if ltf.readsColumn(ltf.columnProviders[i].Name) {
+			provider = &ltf.columnProviders[i]
+		}
+		itr.columnProviders = append(itr.columnProviders, provider)
+	}
+
+	// The length of the short hashes and abbreviations depends on every commit in the log, so the commits are walked
+	// once to find them before any row is returned. The order doesn't matter, so this is done before the commits are
+	// reversed.
+	if ltf.showShortHash || ltf.abbrevLength > 0 {
+		abbreviateSpan, _ := ctx.Span(logSpanAbbreviate)
+		hashes, err := itr.walkHashes(ctx)
+		abbreviateSpan.End()
+		if err != nil {
+			return nil, err
+		}
+		if ltf.showShortHash {
+			itr.shortHashLength = shortHashLength(hashes)
+		}
+		if ltf.abbrevLength > 0 {
+			itr.abbrevLengths = abbreviationLengths(hashes, ltf.abbrevLength)
+		}
+	}
+
+	if args.reverse {
+		itr.child = commitwalk.GetReverseIterator(sqledb.ddb, itr.child)
+	}
+	itr.reversed = args.reverse
+	itr.limit = -1
+	if ltf.limited {
+		itr.limit, itr.offset = ltf.limit, ltf.offset
+	}
+	itr.legacyEmptyColumns = ltf.legacyEmptyColumns
+	itr.trace = lt
+	return itr, nil
+}
+
+// containedColumnName returns the name of the column added by the --contains option at index |i|. The first is named
+// contained, and any others are numbered from 2 in the order they were given.
+func containedColumnName(i int) string {
+	if i == 0 {
+		return "contained"
+	}
+	return fmt.Sprintf("contained_%d", i+1)
+}
+
+// decorationRefFilter contains the types of refs that are used to decorate commits.
+var decorationRefFilter = map[ref.RefType]struct{}{ref.BranchRefType: {}, ref.RemoteRefType: {}, ref.TagRefType: {}}
+
+// logTableFunctionHooks are invoked between the steps of building the row iterator of dolt_log, so that tests are able
+// to modify refs concurrently with the steps. Every hook is nil outside of tests.
+var logTableFunctionHooks struct {
+	// afterResolve is invoked after the revisions have been resolved, and before the refs are read for decoration
+	afterResolve func(ctx *sql.Context) error
+	// afterRefSnapshot is invoked after the refs have been read for decoration, and before any tags have been resolved
+	afterRefSnapshot func(ctx *sql.Context) error
+}
+
+// logRef is a ref that decorates a commit in the log.
+type logRef struct {
+	// name is the name of the ref, which is only the ref's path unless the decoration is "full"
+	name    string
+	refType string
+	// isHead is set for the branch that is checked out by the session
+	isHead bool
+}
+
+// logDatabase returns the Database underlying the given database. Databases qualified by a tag or commit are read only,
+// and read replicas wrap their database, so neither is a Database itself.
+func logDatabase(db sql.Database) (Database, bool) {
+	switch db := db.(type) {
+	case Database:
+		return db, true
+	case ReadOnlyDatabase:
+		return db.Database, true
+	case ReadReplicaDatabase:
+		return db.Database, true
+	default:
+		return Database{}, false
+	}
+}
+
+// logDatabaseHead returns the commit that the log of the given database starts from when no revision is given, along
+// with the branch that is checked out for the database, which is nil when there is no such branch. The head of a
+// revision database is the branch, tag, or commit that its name pins, such as `mydb/feature1`, rather than the head of
+// the base database. A branch's head is read from the session when the session has loaded the database, so that
+// commits made by the session's transaction are included.
+func logDatabaseHead(ctx *sql.Context, db Database) (*doltdb.Commit, ref.DoltRef, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	revision := db.Revision()
+	if revision == "" {
+		head, err := sess.GetHeadCommit(ctx, db.name)
+		return head, getCheckedOutBranch(ctx, db.name), err
+	}
+
+	// Revisions are resolved in the same order as the provider resolves revision databases
+	branch := ref.NewBranchRef(revision)
+	if ok, err := db.ddb.HasRef(ctx, branch); err != nil {
+		return nil, nil, err
+	} else if ok {
+		if dbState, ok, err := sess.LookupDbState(ctx, db.name); err == nil && ok && dbState.WorkingSet != nil {
+			head, err := sess.GetHeadCommit(ctx, db.name)
+			return head, branch, err
+		}
+		head, err := dsess.ResolveReadableCommitRef(ctx, db.ddb, branch)
+		return head, branch, err
+	}
+	tag := ref.NewTagRef(revision)
+	if ok, err := db.ddb.HasRef(ctx, tag); err != nil {
+		return nil, nil, err
+	} else if ok {
+		head, err := db.ddb.ResolveCommitRef(ctx, tag)
+		return head, nil, err
+	}
+	cs, err := doltdb.NewCommitSpec(revision)
+	if err != nil {
+		return nil, nil, err
+	}
+	head, err := dsess.ResolveReadableCommit(ctx, db.ddb, cs, nil)
+	return head, nil, err
+}
+
+// getCheckedOutBranch returns the branch that is checked out by the session for the given database, or nil if the
+// database does not have a checked out branch.
+func getCheckedOutBranch(ctx *sql.Context, dbName string) ref.DoltRef {
+	dbState, ok, err := dsess.DSessFromSess(ctx.Session).LookupDbState(ctx, dbName)
+	if err != nil || !ok || dbState.WorkingSet == nil {
+		return nil
+	}
+	headRef, err := dbState.WorkingSet.Ref().ToHeadRef()
+	if err != nil {
+		return nil
+	}
+	return headRef
+}
+
+// getCommitHashToRefs returns the refs that point to each commit. Refs are read from a single snapshot, so another
+// session may delete a ref after the log's revisions have been resolved without causing an error. Only tags must be
+// read again to find their commits, and tags that have been deleted since the snapshot are skipped.
+func getCommitHashToRefs(ctx *sql.Context, ddb *doltdb.DoltDB, decoration string, checkedOut ref.DoltRef) (map[hash.Hash][]logRef, error) {
+	cHashToRefs := map[hash.Hash][]logRef{}
+
+	refs, err := ddb.GetRefsWithHashes(ctx, decorationRefFilter)
+	if err != nil {
+		return nil, err
+	}
+	if hook := logTableFunctionHooks.afterRefSnapshot; hook != nil {
+		if err = hook(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range refs {
+		if dsess.IsHiddenRef(ctx, r.Ref) {
+			continue
+		}
+		switch dref := r.Ref.(type) {
+		case ref.BranchRef, ref.RemoteRef:
+			refName := dref.String()
+			if decoration != "full" {
+				refName = dref.GetPath() // trim out "refs/heads/" and "refs/remotes/"
+			}
+			refType := "branch"
+			if dref.GetType() == ref.RemoteRefType {
+				refType = "remote"
+			}
+			isHead := checkedOut != nil && ref.Equals(dref, checkedOut)
+			cHashToRefs[r.Hash] = append(cHashToRefs[r.Hash], logRef{name: refName, refType: refType, isHead: isHead})
+		case ref.TagRef:
+			tag, err := ddb.ResolveTag(ctx, dref)
+			if err == doltdb.ErrTagNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			h, err := tag.Commit.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			tagName := tag.GetDoltRef().String()
+			if decoration != "full" {
+				tagName = tag.Name // trim out "refs/tags/"
+			}
+			cHashToRefs[h] = append(cHashToRefs[h], logRef{name: tagName, refType: "tag"})
+		}
+	}
+
+	return cHashToRefs, nil
+}
+
+// evaluateArguments evaluates the argument expressions against the given row, and returns the parsed arguments along
+// with the revisions that are included in and excluded from the log.
+func (ltf *LogTableFunction) evaluateArguments(ctx *sql.Context, row sql.Row) (logArguments, logRevisions, error) {
+	args, err := getDoltArgs(ctx, row, ltf.argumentExprs, ltf.FunctionName())
+	if err != nil {
+		return logArguments{}, logRevisions{}, err
+	}
+	parsed, err := ltf.parseArguments(args)
+	if err != nil {
+		return logArguments{}, logRevisions{}, err
+	}
+	if err = ltf.validateRevisions(parsed); err != nil {
+		return logArguments{}, logRevisions{}, err
+	}
+	for _, warning := range parsed.warnings {
+		ctx.Warn(LogDuplicateArgumentWarningCode, "%s", warning)
+	}
+
+	var revisions logRevisions
+	for i, revision := range parsed.revisions {
+		rvs, ervs, symmetric := getRevisionsFromValue(revision)
+		if len(rvs) > 0 {
+			revisions.included = append(revisions.included, logRevision{name: rvs, arg: ArgumentErrorDetail{Index: parsed.revisionIndexes[i]}})
+		}
+		if len(ervs) > 0 {
+			revisions.excluded = append(revisions.excluded, logRevision{name: ervs, arg: ArgumentErrorDetail{Index: parsed.revisionIndexes[i]}})
+		}
+		revisions.symmetric = revisions.symmetric || symmetric
+	}
+
+	for _, notRevision := range parsed.notRevisions {
+		revisions.excluded = append(revisions.excluded, logRevision{name: notRevision, arg: ArgumentErrorDetail{Index: parsed.notIndex, Flag: cli.NotFlag}})
+	}
+
+	return parsed, revisions, nil
+}
+
+// logRevisions are the evaluated revisions that determine the commits in the log, in the order they were given.
+type logRevisions struct {
+	// included are the revisions whose ancestors are in the log, and excluded are the revisions whose ancestors are
+	// excluded from it. A three dot range is the only revision, and its left side is excluded.
+	included []logRevision
+	excluded []logRevision
+	// symmetric is set for three dot ranges, which include the commits of both revisions, and only exclude the
+	// ancestors of their merge base
+	symmetric bool
+}
+
+// logRevision is a single revision of the log, along with the argument that gave it, for errors caused by the revision.
+type logRevision struct {
+	name string
+	arg  ArgumentErrorDetail
+}
+
+// fetchRemote fetches the remote given by --fetch into the given database, so that its remote-tracking refs are
+// current before any revision is resolved.
+func (ltf *LogTableFunction) fetchRemote(ctx *sql.Context, db Database, args logArguments) error {
+	_, err := dfunctions.DoDoltFetchForDatabase(ctx, db.DbData(), []string{args.fetchRemote})
+	if goerrors.Is(err, env.ErrUnknownRemote) || goerrors.Is(err, env.ErrNoRemote) {
+		return newArgumentError(ltf.FunctionName(), fmt.Sprintf("invalid --fetch option: %s is not a configured remote", args.fetchRemote), ArgumentErrorDetail{Index: args.fetchIndex, Flag: cli.FetchParam, Code: ArgumentErrorInvalidValue})
+	}
+	return err
+}
+
+// resolveRevisions resolves each of the given revisions of the log, in order.
+func (ltf *LogTableFunction) resolveRevisions(ctx *sql.Context, db Database, revisions []logRevision) ([]*doltdb.Commit, error) {
+	commits := make([]*doltdb.Commit, len(revisions))
+	for i, revision := range revisions {
+		commit, err := ltf.resolveRevision(ctx, db, revision.name, revision.arg)
+		if err != nil {
+			return nil, err
+		}
+		commits[i] = commit
+	}
+	return commits, nil
+}
+
+// resolveRevision resolves the given revision of the log, which was given by the argument that |arg| identifies. A
+// revision of the form <remote>/<branch> that does not exist, where <remote> is a configured remote of the database,
+// most likely names a branch that has not been fetched yet, so it returns an error with the
+// ArgumentErrorUnfetchedRevision code that names the remote and branch, rather than the error of the resolution.
+func (ltf *LogTableFunction) resolveRevision(ctx *sql.Context, db Database, revision string, arg ArgumentErrorDetail) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(revision)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := dsess.ResolveReadableCommit(ctx, db.ddb, cs, nil)
+	if goerrors.Is(err, doltdb.ErrBranchNotFound) {
+		if remote, branch, ok := unfetchedRemoteBranch(db.rsr, revision); ok {
+			arg.Code = ArgumentErrorUnfetchedRevision
+			reason := fmt.Sprintf("remote-tracking ref %s/%s has not been fetched: branch `%s` of remote `%s` is not in the local database, use CALL DOLT_FETCH('%s') or '--fetch', '%s' to fetch it", remote, branch, branch, remote, remote, remote)
+			return nil, newArgumentError(ltf.FunctionName(), reason, arg)
+		}
+	}
+	return commit, err
+}
+
+// unfetchedRemoteBranch splits a revision of the form <remote>/<branch>, optionally prefixed with "refs/remotes/" and
+// followed by ancestry such as "~2", into its remote and branch. Returns false unless <remote> is configured in the
+// given repo state.
+func unfetchedRemoteBranch(rsr env.RepoStateReader, revision string) (remote string, branch string, ok bool) {
+	if rsr == nil {
+		return "", "", false
+	}
+	revision = strings.TrimPrefix(revision, "refs/remotes/")
+	if i := strings.IndexAny(revision, "~^"); i != -1 {
+		revision = revision[:i]
+	}
+	remote, branch, found := strings.Cut(revision, "/")
+	if !found || remote == "" || branch == "" {
+		return "", "", false
+	}
+	remotes, err := rsr.GetRemotes()
+	if err != nil {
+		return "", "", false
+	}
+	if _, ok := remotes[remote]; !ok {
+		return "", "", false
+	}
+	return remote, branch, true
+}
+
+// Gets revisionName and/or excludingRevisionName from an evaluated revision, along with whether the revision is a
+// three dot range. The three dot form is checked first, as it also contains "..".
+func getRevisionsFromValue(revisionValStr string) (string, string, bool) {
+	if strings.Contains(revisionValStr, "...") {
+		refs := strings.SplitN(revisionValStr, "...", 2)
+		return refs[1], refs[0], true
+	}
+
+	if strings.Contains(revisionValStr, "..") {
+		refs := strings.Split(revisionValStr, "..")
+		return refs[1], refs[0], false
+	}
+
+	if strings.Contains(revisionValStr, "^") {
+		return "", strings.TrimPrefix(revisionValStr, "^"), false
+	}
+
+	return revisionValStr, "", false
+}
+
+//------------------------------------
+// logTableFunctionRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*logTableFunctionRowIter)(nil)
+
+// logTableFunctionRowIter is a sql.RowIter implementation which iterates over each commit as if it's a row in the table.
+type logTableFunctionRowIter struct {
+	child       doltdb.CommitItr
+	ddb         *doltdb.DoltDB
+	showParents bool
+	showStat    bool
+	decoration  string
+	cHashToRefs map[hash.Hash][]logRef
+	headHash    hash.Hash
+	rawMetadata bool
+	jsonFormat  bool
+	// legacyEmptyColumns returns empty values rather than NULL for the columns that don't apply to a commit
+	legacyEmptyColumns bool
+	// showBoundary adds whether each commit has a parent that is missing from the database
+	showBoundary bool
+	// containsSets hold the ancestors of each ref given with --contains
+	containsSets []*commitwalk.AncestorSet
+	// columnProviders compute the columns given with --with. A provider is nil when the query doesn't read its column,
+	// which is then NULL.
+	columnProviders []*LogColumnProvider
+	// checkPolicy adds the policy_violation column, which is computed by policy. The policy is nil when the query
+	// doesn't read the column, which is then NULL.
+	checkPolicy bool
+	policy      *logPolicyChecker
+
+	// showGraph buffers every commit from the child on the first call to Next, as a commit's parents are emitted after it
+	showGraph bool
+	reversed  bool
+	graph     []logGraphEntry
+	graphPos  int
+	// done is set once the child has stopped at an ancestor that is missing from the database
+	done bool
+	// mergeBase is appended after the last commit from the child when --merge-base is given, and is cleared once it
+	// has been returned
+	mergeBase     *doltdb.Commit
+	mergeBaseHash hash.Hash
+	// shortHashLength is the length of the short_hash column, which is 0 when the column is not shown
+	shortHashLength int
+	// abbrevLengths holds the length that each commit hash is abbreviated to, and is nil when hashes are not abbreviated
+	abbrevLengths map[string]int
+	// skipMessage is set when the query does not read the message, in which case messages are never loaded and the
+	// columns that hold them are NULL
+	skipMessage bool
+	// trace is the trace of the query, which is nil when the query is not traced
+	trace *logTrace
+	// sources tracks the refs that each commit is attributed to when the log is read from the refs of --all or
+	// --branches, which are held by sourceRefs. It is nil otherwise.
+	sources    *commitwalk.SourceIterator
+	sourceRefs []logRef
+	// limit is the number of rows that are returned before the iterator stops, which is -1 when there's no limit, and
+	// offset is the number of rows that are skipped without being built before the first row is returned
+	limit    int64
+	offset   int64
+	returned int64
+}
+
+// nextCommit returns the next commit from the child, followed by the merge base when it's appended to the log.
+func (itr *logTableFunctionRowIter) nextCommit(ctx *sql.Context) (hash.Hash, *doltdb.Commit, error) {
+	if !itr.done {
+		h, cm, err := itr.child.Next(ctx)
+		var missingErr *commitwalk.MissingAncestorError
+		if err == nil {
+			return h, cm, nil
+		} else if goerrors.As(err, &missingErr) {
+			// The history is incomplete, such as in a shallow clone, so the log ends with the boundary commit rather
+			// than failing
+			itr.done = true
+			if missingErr.Commit != nil {
+				return missingErr.Hash, missingErr.Commit, nil
+			}
+		} else if err == io.EOF {
+			itr.done = true
+		} else {
+			return hash.Hash{}, nil, err
+		}
+	}
+
+	if itr.mergeBase != nil {
+		cm := itr.mergeBase
+		itr.mergeBase = nil
+		return itr.mergeBaseHash, cm, nil
+	}
+	return hash.Hash{}, nil, io.EOF
+}
+
+// logShortHashMinLength is the shortest length of the short_hash column, which matches the abbreviations that are
+// commonly shown by user interfaces.
+var logShortHashMinLength = 7
+
+// logDefaultAbbrevLength is the length that commit hashes are abbreviated to when --abbrev is given without a length.
+const logDefaultAbbrevLength = 8
+
+// logMinAbbrevLength is the shortest length that --abbrev accepts, which matches git.
+var logMinAbbrevLength = 4
+
+// walkHashes walks every commit that the iterator will return, and then resets it, returning the hash of each commit.
+// The short hashes and abbreviations computed from these hashes are unique among the commits of this log, but are not
+// guaranteed to be unique within the database, as other commits may share a prefix with a commit in the log.
+func (itr *logTableFunctionRowIter) walkHashes(ctx *sql.Context) ([]string, error) {
+	// nextCommit clears the merge base once it has been returned, so it's restored along with the child
+	mergeBase := itr.mergeBase
+	var hashes []string
+	for {
+		h, _, err := itr.nextCommit(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h.String())
+	}
+	if err := itr.child.Reset(ctx); err != nil {
+		return nil, err
+	}
+	itr.done = false
+	itr.mergeBase = mergeBase
+
+	return hashes, nil
+}
+
+// shortHashLength returns the shortest length of at least logShortHashMinLength at which the prefixes of the given
+// hashes are unique. Every short hash of a log has this same length. Identical hashes are ignored, as they are the same
+// commit.
+func shortHashLength(hashes []string) int {
+	sorted := append([]string(nil), hashes...)
+	sort.Strings(sorted)
+	length := logShortHashMinLength
+	for i := 1; i < len(sorted); i++ {
+		if common := commonPrefixLength(sorted[i-1], sorted[i]); sorted[i-1] != sorted[i] && common+1 > length {
+			length = common + 1
+		}
+	}
+	return length
+}
+
+// abbreviationLengths returns the length that each of the given hashes is abbreviated to, which is the given length
+// unless the hash shares that prefix with another hash, in which case the prefix is extended until it is unique, as git
+// does. Unlike short hashes, only the hashes that collide are extended. Identical hashes are ignored, as they are the
+// same commit.
+func abbreviationLengths(hashes []string, length int) map[string]int {
+	sorted := append([]string(nil), hashes...)
+	sort.Strings(sorted)
+	lengths := make(map[string]int, len(sorted))
+	for i, h := range sorted {
+		abbrevLength := length
+		// Sorted hashes share their longest prefixes with their neighbors
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(sorted) || sorted[j] == h {
+				continue
+			}
+			if common := commonPrefixLength(h, sorted[j]); common+1 > abbrevLength {
+				abbrevLength = common + 1
+			}
+		}
+		if abbrevLength > len(h) {
+			abbrevLength = len(h)
+		}
+		lengths[h] = abbrevLength
+	}
+	return lengths
+}
+
+// commonPrefixLength returns the length of the longest prefix shared by the given strings.
+func commonPrefixLength(a, b string) int {
+	common := 0
+	for common < len(a) && common < len(b) && a[common] == b[common] {
+		common++
+	}
+	return common
+}
+
+func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]logRef) (*logTableFunctionRowIter, error) {
+	hash, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	var child doltdb.CommitItr
+	if ltf.firstParent {
+		child, err = commitwalk.GetFirstParentIterator(ctx, ddb, hash, matchFn)
+	} else {
+		child, err = commitwalk.GetTopologicalOrderIterator(ctx, ddb, hash, matchFn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &logTableFunctionRowIter{
+		child:        child,
+		ddb:          ddb,
+		showParents:  ltf.showParents,
+		showStat:     ltf.showStat,
+		decoration:   ltf.decoration,
+		cHashToRefs:  cHashToRefs,
+		headHash:     hash,
+		rawMetadata:  ltf.rawMetadata,
+		showGraph:    ltf.showGraph,
+		jsonFormat:   ltf.jsonFormat,
+		showBoundary: ltf.showBoundary,
+		skipMessage:  !ltf.readsMessage(),
+	}, nil
+}
+
+// NewDotDotLogTableFunctionRowIter returns an iterator over the commits that are reachable from any of the given
+// commits, but not from any of the excluding commits. The first of the commits is the head of the log.
+func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commits, excludingCommits []*doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]logRef) (*logTableFunctionRowIter, error) {
+	hashes, err := logCommitHashes(commits)
+	if err != nil {
+		return nil, err
+	}
+	exHashes, err := logCommitHashes(excludingCommits)
+	if err != nil {
+		return nil, err
+	}
+	hash := hashes[0]
+
+	var child doltdb.CommitItr
+	if ltf.firstParent {
+		child, err = commitwalk.GetFirstParentMultiRevisionsIterator(ctx, ddb, hashes, exHashes, matchFn)
+	} else {
+		child, err = commitwalk.GetMultiRevisionsIterator(ctx, ddb, hashes, exHashes, matchFn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &logTableFunctionRowIter{
+		child:        child,
+		ddb:          ddb,
+		showParents:  ltf.showParents,
+		showStat:     ltf.showStat,
+		decoration:   ltf.decoration,
+		cHashToRefs:  cHashToRefs,
+		headHash:     hash,
+		rawMetadata:  ltf.rawMetadata,
+		showGraph:    ltf.showGraph,
+		jsonFormat:   ltf.jsonFormat,
+		showBoundary: ltf.showBoundary,
+		skipMessage:  !ltf.readsMessage(),
+	}, nil
+}
+
+// logCommitHashes returns the hash of each of the given commits.
+func logCommitHashes(commits []*doltdb.Commit) ([]hash.Hash, error) {
+	hashes := make([]hash.Hash, len(commits))
+	for i, commit := range commits {
+		h, err := commit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = h
+	}
+	return hashes, nil
+}
+
+// NewSourceLogTableFunctionRowIter returns an iterator over the commits that are reachable from any of the refs of
+// --all or --branches, which tracks the refs that each commit is attributed to for the sources column.
+func (ltf *LogTableFunction) NewSourceLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, args logArguments, headCommit *doltdb.Commit, checkedOut ref.DoltRef, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]logRef) (*logTableFunctionRowIter, error) {
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	sourceRefs, heads, err := getLogSources(ctx, ddb, args.sourceRefs, ltf.decoration, checkedOut, headHash)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := commitwalk.GetSourceIterator(ctx, ddb, heads, args.perSourceLimit, matchFn, ltf.firstParent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logTableFunctionRowIter{
+		child:        sources,
+		ddb:          ddb,
+		showParents:  ltf.showParents,
+		showStat:     ltf.showStat,
+		decoration:   ltf.decoration,
+		cHashToRefs:  cHashToRefs,
+		headHash:     headHash,
+		rawMetadata:  ltf.rawMetadata,
+		showGraph:    ltf.showGraph,
+		jsonFormat:   ltf.jsonFormat,
+		showBoundary: ltf.showBoundary,
+		skipMessage:  !ltf.readsMessage(),
+		sources:      sources,
+		sourceRefs:   sourceRefs,
+	}, nil
+}
+
+// getLogSources returns the refs of the given types that the log is read from, along with the commit of each. Names
+// follow the same decoration rules as the refs column. The checked out branch is read from the session, so that it
+// matches the head of a log without revisions. Tags that have been deleted since the refs were read are skipped.
+func getLogSources(ctx *sql.Context, ddb *doltdb.DoltDB, refTypes map[ref.RefType]struct{}, decoration string, checkedOut ref.DoltRef, headHash hash.Hash) ([]logRef, []hash.Hash, error) {
+	refs, err := ddb.GetRefsWithHashes(ctx, refTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sourceRefs []logRef
+	var heads []hash.Hash
+	for _, r := range refs {
+		if dsess.IsHiddenRef(ctx, r.Ref) {
+			continue
+		}
+		h := r.Hash
+		var source logRef
+		switch dref := r.Ref.(type) {
+		case ref.BranchRef, ref.RemoteRef:
+			source = logRef{name: dref.GetPath(), refType: "branch"}
+			if dref.GetType() == ref.RemoteRefType {
+				source.refType = "remote"
+			}
+			if checkedOut != nil && ref.Equals(dref, checkedOut) {
+				source.isHead = true
+				h = headHash
+			}
+		case ref.TagRef:
+			tag, err := ddb.ResolveTag(ctx, dref)
+			if err == doltdb.ErrTagNotFound {
+				continue
+			} else if err != nil {
+				return nil, nil, err
+			}
+			if h, err = tag.Commit.HashOf(); err != nil {
+				return nil, nil, err
+			}
+			source = logRef{name: tag.Name, refType: "tag"}
+		default:
+			continue
+		}
+		if decoration == "full" {
+			source.name = r.Ref.String()
+		}
+		sourceRefs = append(sourceRefs, source)
+		heads = append(heads, h)
+	}
+	return sourceRefs, heads, nil
+}
+
+// NewThreeDotLogTableFunctionRowIter returns an iterator over the commits that are reachable from either of the given
+// commits, but not from their merge base, which is nil when their histories are unrelated.
+func (ltf *LogTableFunction) NewThreeDotLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, left, right, mergeBase *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]logRef) (*logTableFunctionRowIter, error) {
+	leftHash, err := left.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	rightHash, err := right.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	var mergeBaseHash hash.Hash
+	if mergeBase != nil {
+		mergeBaseHash, err = mergeBase.HashOf()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var child doltdb.CommitItr
+	if ltf.firstParent {
+		child, err = commitwalk.GetFirstParentThreeDotRevisionsIterator(ctx, ddb, leftHash, rightHash, mergeBaseHash, matchFn)
+	} else {
+		child, err = commitwalk.GetThreeDotRevisionsIterator(ctx, ddb, leftHash, rightHash, mergeBaseHash, matchFn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &logTableFunctionRowIter{
+		child:        child,
+		ddb:          ddb,
+		showParents:  ltf.showParents,
+		showStat:     ltf.showStat,
+		decoration:   ltf.decoration,
+		cHashToRefs:  cHashToRefs,
+		headHash:     rightHash,
+		rawMetadata:  ltf.rawMetadata,
+		showGraph:    ltf.showGraph,
+		jsonFormat:   ltf.jsonFormat,
+		showBoundary: ltf.showBoundary,
+		skipMessage:  !ltf.readsMessage(),
+	}, nil
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+// After retrieving the last row, Close will be automatically closed.
+func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.limit >= 0 && itr.returned >= itr.limit {
+		return nil, io.EOF
+	}
+	for ; itr.offset > 0; itr.offset-- {
+		if err := itr.skipRow(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	row, err := itr.nextRow(ctx)
+	if err == nil {
+		itr.returned++
+		logRowLatency.Observe(time.Since(start))
+		if itr.trace != nil {
+			itr.trace.rows++
+		}
+	}
+	return row, err
+}
+
+// skipRow skips the next row of the log, without computing any of its columns.
+func (itr *logTableFunctionRowIter) skipRow(ctx *sql.Context) error {
+	var err error
+	if itr.showGraph {
+		_, err = itr.nextGraphEntry(ctx)
+	} else {
+		_, _, err = itr.nextCommit(ctx)
+	}
+	return err
+}
+
+// nextGraphEntry returns the next entry of the graph, building the graph from every commit of the child on the first
+// call.
+func (itr *logTableFunctionRowIter) nextGraphEntry(ctx *sql.Context) (logGraphEntry, error) {
+	if itr.graph == nil {
+		graph, err := buildLogGraph(ctx, itr.child, itr.reversed)
+		if err != nil {
+			return logGraphEntry{}, err
+		}
+		itr.graph = graph
+	}
+	if itr.graphPos >= len(itr.graph) {
+		return logGraphEntry{}, io.EOF
+	}
+	entry := itr.graph[itr.graphPos]
+	itr.graphPos++
+	return entry, nil
+}
+
+// nextRow returns the next row of the log.
+func (itr *logTableFunctionRowIter) nextRow(ctx *sql.Context) (sql.Row, error) {
+	var h hash.Hash
+	var cm *doltdb.Commit
+	var graphEntry logGraphEntry
+	if itr.showGraph {
+		var err error
+		graphEntry, err = itr.nextGraphEntry(ctx)
+		if err != nil {
+			return nil, err
+		}
+		h, cm = graphEntry.hash, graphEntry.commit
+	} else {
+		var err error
+		h, cm, err = itr.nextCommit(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var metaStart time.Time
+	if itr.trace != nil {
+		metaStart = time.Now()
+	}
+	meta, err := getLogCommitMeta(ctx, cm, !itr.skipMessage)
+	if itr.trace != nil {
+		itr.trace.metaDecoding += time.Since(metaStart)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := cm.Height()
+	if err != nil {
+		return nil, err
+	}
+
+	// commits written before the committer's time zone was recorded have no offset to report
+	var tz interface{}
+	if meta.UserTimezone != "" {
+		tz = meta.UserTimezone
+	}
+
+	commitHash := h.String()
+	if itr.abbrevLengths != nil {
+		commitHash = commitHash[:itr.abbrevLengths[commitHash]]
+	}
+
+	var row sql.Row
+	if itr.rawMetadata {
+		row = sql.NewRow(commitHash, []byte(meta.Name), []byte(meta.Email), meta.Time(), itr.messageValue(meta.Description), int64(height), tz)
+	} else {
+		row = sql.NewRow(commitHash, sanitizeCommitMetaString(meta.Name), sanitizeCommitMetaString(meta.Email), meta.Time(), itr.messageValue(meta.Description), int64(height), tz)
+	}
+
+	if itr.abbrevLengths != nil {
+		row = row.Append(sql.NewRow(itr.messageValue(commitSubject(meta.Description))))
+	}
+
+	if itr.shortHashLength > 0 {
+		row = row.Append(sql.NewRow(h.String()[:itr.shortHashLength]))
+	}
+
+	if itr.showParents {
+		var parents interface{}
+		if !itr.appliesToCommit(cm.NumParents()) {
+			parents = nil
+		} else if itr.jsonFormat {
+			parents, err = getParentsJSON(ctx, cm)
+		} else {
+			parents, err = getParentsString(ctx, cm)
+		}
+		if err != nil {
+			return nil, err
+		}
+		row = row.Append(sql.NewRow(parents))
+	}
+
+	if shouldDecorateWithRefs(itr.decoration) {
+		refs := itr.cHashToRefs[h]
+		if !itr.appliesToCommit(len(refs)) {
+			row = row.Append(sql.NewRow(nil))
+		} else if itr.jsonFormat {
+			refsJSON, err := getRefsJSON(refs)
+			if err != nil {
+				return nil, err
+			}
+			row = row.Append(sql.NewRow(refsJSON))
+		} else {
+			isHead := itr.headHash == h
+			row = row.Append(sql.NewRow(getRefsString(refs, isHead)))
+		}
+	}
+
+	if itr.sources != nil {
+		heads := itr.sources.Sources(h)
+		refs := make([]logRef, len(heads))
+		for i, head := range heads {
+			refs[i] = itr.sourceRefs[head]
+		}
+		if !itr.appliesToCommit(len(refs)) {
+			row = row.Append(sql.NewRow(nil))
+		} else if itr.jsonFormat {
+			refsJSON, err := getRefsJSON(refs)
+			if err != nil {
+				return nil, err
+			}
+			row = row.Append(sql.NewRow(refsJSON))
+		} else {
+			row = row.Append(sql.NewRow(getRefsString(refs, false)))
+		}
+	}
+
+	if itr.showStat {
+		statRow, err := getCommitStatRow(ctx, itr.ddb, cm)
+		if err != nil {
+			return nil, err
+		}
+		row = row.Append(statRow)
+	}
+
+	if itr.showGraph {
+		var parentOrders interface{}
+		if itr.appliesToCommit(len(graphEntry.parentOrders)) {
+			parentOrders, err = sql.JSON.Convert(graphEntry.parentOrders)
+			if err != nil {
+				return nil, err
+			}
+		}
+		row = row.Append(sql.NewRow(graphEntry.order, parentOrders, graphEntry.lane))
+	}
+
+	if itr.showBoundary {
+		isBoundary, err := hasMissingParent(ctx, itr.ddb, cm)
+		if err != nil {
+			return nil, err
+		}
+		row = row.Append(sql.NewRow(isBoundary))
+	}
+
+	for _, ancestors := range itr.containsSets {
+		contained, err := ancestors.Contains(ctx, h, height)
+		if err != nil {
+			return nil, err
+		}
+		row = row.Append(sql.NewRow(contained))
+	}
+
+	if itr.checkPolicy {
+		var violation interface{}
+		if itr.policy != nil {
+			violation, err = itr.policy.violation(ctx, meta.Email)
+			if err != nil {
+				return nil, err
+			}
+		}
+		row = row.Append(sql.NewRow(violation))
+	}
+
+	for _, provider := range itr.columnProviders {
+		var val interface{}
+		if provider != nil {
+			val, err = computeLogColumn(ctx, *provider, cm, h)
+			if err != nil {
+				return nil, err
+			}
+		}
+		row = row.Append(sql.NewRow(val))
 	}
 
 	return row, nil
 }
 
+// appliesToCommit returns whether a column that lists the given number of values, such as the parents or refs of a
+// commit, applies to the commit. Columns are NULL when they don't apply to a commit, such as the parents of a root
+// commit or the refs of a commit that isn't decorated by any ref, and are only empty when they apply but have an empty
+// value, such as the subject of an empty message. The legacy behavior returns empty values for every column that's
+// shown.
+func (itr *logTableFunctionRowIter) appliesToCommit(count int) bool {
+	return count > 0 || itr.legacyEmptyColumns
+}
+
+// logTrailerCondition is a --trailer filter, which is satisfied by a trailer with the given key and, if a value was
+// given, the given value.
+type logTrailerCondition struct {
+	key      string
+	value    string
+	hasValue bool
+	// pattern matches the value when it contains %, in which case it's a LIKE pattern rather than an exact value
+	pattern *expression.LikeMatcher
+}
+
+// newLogTrailerCondition parses the value of a --trailer option, which is a key optionally followed by = and a value.
+func newLogTrailerCondition(trailer string) (logTrailerCondition, error) {
+	key, value, hasValue := strings.Cut(trailer, "=")
+	if key == "" {
+		return logTrailerCondition{}, fmt.Errorf("%s has no trailer key", trailer)
+	}
+	cond := logTrailerCondition{key: key, value: value, hasValue: hasValue}
+	if strings.Contains(value, "%") {
+		matcher, err := expression.ConstructLikeMatcher(sql.Collation_Default, value, '\\')
+		if err != nil {
+			return logTrailerCondition{}, err
+		}
+		cond.pattern = &matcher
+	}
+	return cond, nil
+}
+
+// matches returns whether the given trailer satisfies the condition. Keys are compared without regard to case.
+func (cond logTrailerCondition) matches(trailer doltdb.CommitTrailer) bool {
+	if !strings.EqualFold(cond.key, trailer.Key) {
+		return false
+	}
+	switch {
+	case !cond.hasValue:
+		return true
+	case cond.pattern != nil:
+		return cond.pattern.Match(trailer.Value)
+	default:
+		return cond.value == trailer.Value
+	}
+}
+
+// logTrailerFilter selects commits by the trailers of their messages. Every condition must be satisfied by one of a
+// commit's trailers, and none of its trailers may have an excluded key.
+type logTrailerFilter struct {
+	conditions []logTrailerCondition
+	excluded   []string
+	// trailers holds the parsed trailers of each commit, as a commit may be matched more than once by walks that
+	// revisit commits, such as those bounded by commit_order
+	trailers map[hash.Hash][]doltdb.CommitTrailer
+}
+
+func newLogTrailerFilter(conditions []logTrailerCondition, excluded []string) *logTrailerFilter {
+	return &logTrailerFilter{
+		conditions: conditions,
+		excluded:   excluded,
+		trailers:   make(map[hash.Hash][]doltdb.CommitTrailer),
+	}
+}
+
+// commitTrailers returns the trailers of the given commit's message, parsing them the first time the commit is seen.
+func (f *logTrailerFilter) commitTrailers(ctx *sql.Context, cm *doltdb.Commit) ([]doltdb.CommitTrailer, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if trailers, ok := f.trailers[h]; ok {
+		return trailers, nil
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trailers := doltdb.ParseCommitTrailers(meta.Description)
+	f.trailers[h] = trailers
+	return trailers, nil
+}
+
+// matches returns whether the given commit's trailers satisfy the filter.
+func (f *logTrailerFilter) matches(ctx *sql.Context, cm *doltdb.Commit) (bool, error) {
+	trailers, err := f.commitTrailers(ctx, cm)
+	if err != nil {
+		return false, err
+	}
+	for _, trailer := range trailers {
+		for _, key := range f.excluded {
+			if strings.EqualFold(key, trailer.Key) {
+				return false, nil
+			}
+		}
+	}
+	for _, cond := range f.conditions {
+		satisfied := false
+		for _, trailer := range trailers {
+			if cond.matches(trailer) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// hasMissingParent returns whether any of the commit's parents cannot be loaded because they're missing from the
+// database, which makes the commit a boundary of the available history.
+func hasMissingParent(ctx *sql.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit) (bool, error) {
+	parentHashes, err := cm.ParentHashes(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, parentHash := range parentHashes {
+		_, err = ddb.ReadCommit(ctx, parentHash)
+		if goerrors.Is(err, datas.ErrCommitNotFound) {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
 func (itr *logTableFunctionRowIter) Close(_ *sql.Context) error {
+	itr.trace.end()
+	if closer, ok := itr.child.(io.Closer); ok {
+		return closer.Close()
+	}
 	return nil
 }
 
-func getRefsString(branchNames []string, isHead bool) string {
-	if len(branchNames) == 0 {
+// logGraphEntry is a commit emitted by dolt_log when --graph is given, along with its position in the commit graph.
+type logGraphEntry struct {
+	hash   hash.Hash
+	commit *doltdb.Commit
+	// order is the index at which the commit is emitted
+	order int64
+	// parentOrders are the orders of each parent, or -1 for parents that are not emitted
+	parentOrders []int64
+	// lane is the column that the commit occupies when drawing the graph
+	lane int64
+}
+
+// buildLogGraph reads every commit from the given iterator and assigns its position in the commit graph. Lanes are
+// allocated from the newest commit to the oldest in the same manner as `git log --graph`: a commit continues the lane of
+// the first child that named it as a parent, its first parent inherits its lane, and every other parent is given the
+// lowest free lane. As the iterator may have been reversed, the lanes are always assigned in the unreversed order so that
+// they do not depend on the order of the output.
+func buildLogGraph(ctx *sql.Context, child doltdb.CommitItr, reversed bool) ([]logGraphEntry, error) {
+	graph := make([]logGraphEntry, 0)
+	orders := make(map[hash.Hash]int64)
+	for {
+		h, cm, err := child.Next(ctx)
+		var missingErr *commitwalk.MissingAncestorError
+		if err == io.EOF {
+			break
+		} else if goerrors.As(err, &missingErr) {
+			// The graph ends at the boundary commit of an incomplete history
+			if missingErr.Commit != nil {
+				orders[missingErr.Hash] = int64(len(graph))
+				graph = append(graph, logGraphEntry{hash: missingErr.Hash, commit: missingErr.Commit, order: int64(len(graph))})
+			}
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		orders[h] = int64(len(graph))
+		graph = append(graph, logGraphEntry{hash: h, commit: cm, order: int64(len(graph))})
+	}
+
+	parents := make([][]hash.Hash, len(graph))
+	for i := range graph {
+		parentHashes, err := graph[i].commit.ParentHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		graph[i].parentOrders = make([]int64, len(parentHashes))
+		for j, parentHash := range parentHashes {
+			if order, ok := orders[parentHash]; ok {
+				graph[i].parentOrders[j] = order
+				// Parents that were not emitted do not occupy a lane
+				parents[i] = append(parents[i], parentHash)
+			} else {
+				graph[i].parentOrders[j] = -1
+			}
+		}
+	}
+
+	// Each lane holds the commit that is expected to appear in it next, with an empty hash denoting a free lane
+	var lanes []hash.Hash
+	allocateLane := func(h hash.Hash) int {
+		for lane := range lanes {
+			if lanes[lane].IsEmpty() {
+				lanes[lane] = h
+				return lane
+			}
+		}
+		lanes = append(lanes, h)
+		return len(lanes) - 1
+	}
+	for n := 0; n < len(graph); n++ {
+		i := n
+		if reversed {
+			i = len(graph) - 1 - n
+		}
+		entry := &graph[i]
+
+		lane := -1
+		for j := range lanes {
+			if lanes[j] == entry.hash {
+				if lane == -1 {
+					lane = j
+				}
+				// Every child that expected this commit converges into its lane
+				lanes[j] = hash.Hash{}
+			}
+		}
+		if lane == -1 {
+			lane = allocateLane(entry.hash)
+		}
+		lanes[lane] = hash.Hash{}
+		entry.lane = int64(lane)
+
+		for j, parentHash := range parents[i] {
+			alreadyExpected := false
+			for _, expected := range lanes {
+				if expected == parentHash {
+					alreadyExpected = true
+					break
+				}
+			}
+			if alreadyExpected {
+				continue
+			}
+			if j == 0 {
+				lanes[lane] = parentHash
+			} else {
+				allocateLane(parentHash)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// sanitizeCommitMetaString returns the given commit metadata with all invalid UTF-8 sequences replaced by U+FFFD, and
+// all NUL bytes removed. Histories imported from other systems may contain such metadata, which clients are unable to
+// decode, causing them to abort the entire result set.
+// commitSubject returns the first line of the given commit message, without a trailing carriage return.
+func commitSubject(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		message = message[:i]
+	}
+	return strings.TrimSuffix(message, "\r")
+}
+
+// getLogCommitMeta returns the metadata of a commit in the log, which only includes the message when withMessage is
+// set, as messages may be very large. This is a variable so that tests are able to observe which messages are loaded.
+var getLogCommitMeta = func(ctx *sql.Context, cm *doltdb.Commit, withMessage bool) (*datas.CommitMeta, error) {
+	if withMessage {
+		return cm.GetCommitMeta(ctx)
+	}
+	return cm.GetCommitMetaWithoutDescription(ctx)
+}
+
+// messageValue returns the value of a column that holds all or part of a commit message, which is NULL when the query
+// does not read the message.
+func (itr *logTableFunctionRowIter) messageValue(message string) interface{} {
+	if itr.skipMessage {
+		return nil
+	}
+	if itr.rawMetadata {
+		return []byte(message)
+	}
+	return sanitizeCommitMetaString(message)
+}
+
+// sanitizeCommitMetaString returns the given commit metadata as valid UTF-8 without NUL characters. Metadata that is
+// already valid is returned as it is, so that large messages aren't copied.
+func sanitizeCommitMetaString(str string) string {
+	if utf8.ValidString(str) && strings.IndexByte(str, 0) == -1 {
+		return str
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(str, string(utf8.RuneError)), "\x00", "")
+}
+
+func getRefsString(refs []logRef, isHead bool) string {
+	if len(refs) == 0 {
 		return ""
 	}
+	names := make([]string, len(refs))
+	for i, r := range refs {
+		if r.refType == "tag" {
+			names[i] = fmt.Sprintf("tag: %s", r.name)
+		} else {
+			names[i] = r.name
+		}
+	}
 	var refStr string
 	if isHead {
 		refStr += "HEAD -> "
 	}
-	refStr += strings.Join(branchNames, ", ")
+	refStr += strings.Join(names, ", ")
 
 	return refStr
 }
 
+// getRefsJSON returns the given refs as a JSON array of objects, each holding the name and type of a ref, along with
+// whether the ref is the branch checked out by the session.
+func getRefsJSON(refs []logRef) (interface{}, error) {
+	objs := make([]interface{}, len(refs))
+	for i, r := range refs {
+		objs[i] = map[string]interface{}{"name": r.name, "type": r.refType, "is_head": r.isHead}
+	}
+	return sql.JSON.Convert(objs)
+}
+
 func getParentsString(ctx *sql.Context, cm *doltdb.Commit) (string, error) {
 	parents, err := cm.ParentHashes(ctx)
 	if err != nil {
@@ -633,6 +2434,95 @@ func getParentsString(ctx *sql.Context, cm *doltdb.Commit) (string, error) {
 	return prStr, nil
 }
 
+// getParentsJSON returns the hashes of the parents of the given commit as a JSON array.
+func getParentsJSON(ctx *sql.Context, cm *doltdb.Commit) (interface{}, error) {
+	parents, err := cm.ParentHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]interface{}, len(parents))
+	for i, h := range parents {
+		strs[i] = h.String()
+	}
+	return sql.JSON.Convert(strs)
+}
+
+// getCommitStatRow returns the row containing the number of tables changed, along with the number of rows added,
+// modified, and deleted by the given commit. The commit is compared against its first parent, or against an empty root
+// for commits without parents, in which case all rows are reported as added.
+func getCommitStatRow(ctx *sql.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit) (sql.Row, error) {
+	toRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromRoot *doltdb.RootValue
+	if cm.NumParents() > 0 {
+		parent, err := ddb.ResolveParent(ctx, cm, 0)
+		if err != nil {
+			return nil, err
+		}
+		fromRoot, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fromRoot, err = doltdb.EmptyRootValue(ctx, ddb.ValueReadWriter(), ddb.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var tablesChanged int32
+	var rowsAdded, rowsModified, rowsDeleted int64
+	for _, delta := range deltas {
+		hasChanges, err := delta.HasChanges()
+		if err != nil {
+			return nil, err
+		}
+		if !hasChanges {
+			continue
+		}
+		tablesChanged++
+
+		// Rows cannot be matched between differing primary key sets, so we treat every row as having been replaced
+		if delta.HasPrimaryKeySetChanged() {
+			fromRows, toRows, err := delta.GetRowData(ctx)
+			if err != nil {
+				return nil, err
+			}
+			fromCount, err := fromRows.Count()
+			if err != nil {
+				return nil, err
+			}
+			toCount, err := toRows.Count()
+			if err != nil {
+				return nil, err
+			}
+			rowsAdded += int64(toCount)
+			rowsDeleted += int64(fromCount)
+			continue
+		}
+
+		diffSum, hasDiff, _, err := getDiffSummary(ctx, delta)
+		if err != nil {
+			return nil, err
+		}
+		if hasDiff {
+			rowsAdded += int64(diffSum.Adds)
+			rowsModified += int64(diffSum.Changes)
+			rowsDeleted += int64(diffSum.Removes)
+		}
+	}
+
+	return sql.NewRow(tablesChanged, rowsAdded, rowsModified, rowsDeleted), nil
+}
+
 // Default ("auto") for the dolt_log table function is "no"
 func shouldDecorateWithRefs(decoration string) bool {
 	return decoration == "full" || decoration == "short"
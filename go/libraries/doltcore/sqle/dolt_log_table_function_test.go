@@ -0,0 +1,1238 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// hostileCommitMetas are commit metadata values that are not valid for clients expecting UTF-8 text.
+var hostileCommitMetas = []datas.CommitMeta{
+	{Name: "bad\xffname", Email: "bad@fake.horse", Description: "invalid \xc3\x28 sequence"},
+	{Name: "nul\x00name", Email: "nul\x00@fake.horse", Description: "embedded\x00nul"},
+}
+
+// createHostileLogEnv returns an environment whose main branch contains commits with hostile metadata. These commits
+// are created directly through DoltDB, as the SQL and CLI paths would never write such metadata.
+func createHostileLogEnv(t *testing.T) *env.DoltEnv {
+	return createLogEnvWithCommits(t, hostileCommitMetas)
+}
+
+// createLogEnvWithCommits returns an environment whose main branch contains an empty commit for each given metadata.
+func createLogEnvWithCommits(t *testing.T, metas []datas.CommitMeta) *env.DoltEnv {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	root, err := head.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rootHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	for i := range metas {
+		meta := metas[i]
+		meta.Timestamp = uint64(datas.CommitNowFunc().UnixMilli())
+		meta.UserTimestamp = int64(meta.Timestamp)
+		_, err = dEnv.DoltDB.Commit(ctx, rootHash, ref.NewBranchRef(env.DefaultInitBranch), &meta)
+		require.NoError(t, err)
+	}
+	return dEnv
+}
+
+// executeLogQuery runs the given query against the environment, setting dolt_log_raw_commit_metadata beforehand.
+func executeLogQuery(t *testing.T, dEnv *env.DoltEnv, rawMetadata bool, query string) []sql.Row {
+	vars := map[string]interface{}{}
+	if rawMetadata {
+		vars[dsess.LogRawCommitMetadata] = int8(1)
+	}
+	return executeLogQueryWithVars(t, dEnv, vars, query)
+}
+
+// executeLogQueryWithVars runs the given query against the environment, setting the given session variables
+// beforehand.
+func executeLogQueryWithVars(t *testing.T, dEnv *env.DoltEnv, vars map[string]interface{}, query string) []sql.Row {
+	rows, err := executeLogQueryWithVarsErr(t, dEnv, vars, query)
+	require.NoError(t, err)
+	return rows
+}
+
+// executeLogQueryErr runs the given query against the environment, returning any error that is encountered.
+func executeLogQueryErr(t *testing.T, dEnv *env.DoltEnv, query string) ([]sql.Row, error) {
+	return executeLogQueryWithVarsErr(t, dEnv, nil, query)
+}
+
+// executeLogQueryWithVarsErr runs the given query against the environment, setting the given session variables
+// beforehand, and returns any error that the query encounters.
+func executeLogQueryWithVarsErr(t *testing.T, dEnv *env.DoltEnv, vars map[string]interface{}, query string) ([]sql.Row, error) {
+	ctx := context.Background()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(t, dEnv, ctx, db, root)
+	require.NoError(t, err)
+
+	for name, val := range vars {
+		require.NoError(t, sqlCtx.SetSessionVariable(sqlCtx, name, val))
+	}
+	sch, iter, err := engine.Query(sqlCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowIterToRows(sqlCtx, sch, iter)
+}
+
+func TestLogTableFunctionSanitizesCommitMeta(t *testing.T) {
+	dEnv := createHostileLogEnv(t)
+	rows := executeLogQuery(t, dEnv, false, "SELECT committer, email, message FROM dolt_log() LIMIT 2;")
+	assert.Equal(t, []sql.Row{
+		{"nulname", "nul@fake.horse", "embeddednul"},
+		{"bad�name", "bad@fake.horse", "invalid �( sequence"},
+	}, rows)
+	// Every commit must be returned, including those that precede the hostile commits
+	rows = executeLogQuery(t, dEnv, false, "SELECT count(*) FROM dolt_log();")
+	assert.Equal(t, []sql.Row{{int64(3)}}, rows)
+}
+
+func TestLogTableFunctionRawCommitMeta(t *testing.T) {
+	dEnv := createHostileLogEnv(t)
+	rows := executeLogQuery(t, dEnv, true, "SELECT committer, email, message FROM dolt_log() LIMIT 2;")
+	assert.Equal(t, []sql.Row{
+		{[]byte("nul\x00name"), []byte("nul\x00@fake.horse"), []byte("embedded\x00nul")},
+		{[]byte("bad\xffname"), []byte("bad@fake.horse"), []byte("invalid \xc3\x28 sequence")},
+	}, rows)
+}
+
+func TestLogTableFunctionCommitterTimezone(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "before time zones", Email: "old@fake.horse", Description: "written without a time zone"},
+		{Name: "india", Email: "india@fake.horse", Description: "written in India", UserTimezone: "+05:30"},
+	})
+	rows := executeLogQuery(t, dEnv, false, "SELECT committer, committer_date_tz FROM dolt_log() LIMIT 2;")
+	assert.Equal(t, []sql.Row{
+		{"india", "+05:30"},
+		{"before time zones", nil},
+	}, rows)
+}
+
+func TestLogTableFunctionTrailers(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "jane", Email: "jane@fake.horse", Description: "signed\n\nSigned-off-by: Jane Doe <jane@fake.horse>"},
+		{Name: "bob", Email: "bob@fake.horse", Description: "signed and reviewed\n\nsigned-off-by: Bob <bob@fake.horse>\nReviewed-by: Jane Doe <jane@fake.horse>"},
+		{Name: "body", Email: "body@fake.horse", Description: "mentions the key\n\nSigned-off-by: is only required on main\n\nso this is not a trailer"},
+		{Name: "unsigned", Email: "unsigned@fake.horse", Description: "unsigned"},
+	})
+
+	tests := []struct {
+		query    string
+		expected []sql.Row
+	}{
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by');", []sql.Row{{"bob"}, {"jane"}}},
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by=Jane Doe <jane@fake.horse>');", []sql.Row{{"jane"}}},
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by=Jane Doe');", nil},
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by=%@fake.horse>');", []sql.Row{{"bob"}, {"jane"}}},
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by', '--trailer', 'Reviewed-by=Jane%');", []sql.Row{{"bob"}}},
+		{"SELECT committer FROM dolt_log('--trailer', 'Signed-off-by', '--no-trailer', 'REVIEWED-BY');", []sql.Row{{"jane"}}},
+		{"SELECT committer FROM dolt_log('--no-trailer', 'Signed-off-by') LIMIT 2;", []sql.Row{{"unsigned"}, {"body"}}},
+		{"SELECT committer FROM dolt_log('main~3..main', '--no-trailer', 'Signed-off-by');", []sql.Row{{"unsigned"}, {"body"}}},
+		{"SELECT committer FROM dolt_log('main', '--not', 'main~3', '--trailer', 'Signed-off-by');", []sql.Row{{"bob"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, executeLogQuery(t, dEnv, false, test.query))
+		})
+	}
+}
+
+func TestLogTrailerFilterParsesEachCommitOnce(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "jane", Email: "jane@fake.horse", Description: "signed\n\nSigned-off-by: Jane"},
+	})
+	ctx := sql.NewEmptyContext()
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	h, err := head.HashOf()
+	require.NoError(t, err)
+
+	cond, err := newLogTrailerCondition("Signed-off-by=Jane")
+	require.NoError(t, err)
+	filter := newLogTrailerFilter([]logTrailerCondition{cond}, nil)
+	ok, err := filter.matches(ctx, head)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Matching again uses the cached trailers rather than the commit's message
+	filter.trailers[h] = nil
+	ok, err = filter.matches(ctx, head)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestShortHashLength(t *testing.T) {
+	assert.Equal(t, logShortHashMinLength, shortHashLength(nil))
+	assert.Equal(t, logShortHashMinLength, shortHashLength([]string{"abcdefghij", "abcdefzzzz", "zzzzzzzzzz"}))
+	assert.Equal(t, 8, shortHashLength([]string{"abcdefgzzz", "zzzzzzzzzz", "abcdefghij"}))
+	assert.Equal(t, 10, shortHashLength([]string{"abcdefghij", "abcdefghik", "abcdefgzzz"}))
+	// the same commit may be returned more than once, such as a merge base that is also in the range
+	assert.Equal(t, logShortHashMinLength, shortHashLength([]string{"abcdefghij", "abcdefghij"}))
+}
+
+func TestLogTableFunctionShortHash(t *testing.T) {
+	rows := executeLogQuery(t, createLogEnvWithCommits(t, nil), false, "SELECT commit_hash, short_hash FROM dolt_log('--show-short-hash');")
+	require.NotEmpty(t, rows)
+	for _, row := range rows {
+		assert.Equal(t, row[0].(string)[:7], row[1])
+	}
+
+	// Commits sharing a prefix of the minimum length are unlikely with real hashes, so the minimum is lowered until
+	// the commits that are generated here collide
+	defer func(minLength int) {
+		logShortHashMinLength = minLength
+	}(logShortHashMinLength)
+	logShortHashMinLength = 2
+
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, nil)
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	root, err := head.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rootHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	prefixes := make(map[string]bool)
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "no commits with a shared prefix were generated")
+		meta, err := datas.NewCommitMeta("brute", "brute@fake.horse", fmt.Sprintf("commit %d", i))
+		require.NoError(t, err)
+		cm, err := dEnv.DoltDB.Commit(ctx, rootHash, ref.NewBranchRef(env.DefaultInitBranch), meta)
+		require.NoError(t, err)
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+		prefix := h.String()[:logShortHashMinLength]
+		if prefixes[prefix] {
+			break
+		}
+		prefixes[prefix] = true
+	}
+
+	rows = executeLogQuery(t, dEnv, false, "SELECT commit_hash, short_hash FROM dolt_log('--show-short-hash');")
+	var hashes []string
+	shortHashes := make(map[string]bool)
+	for _, row := range rows {
+		hashes = append(hashes, row[0].(string))
+		shortHashes[row[1].(string)] = true
+		assert.True(t, strings.HasPrefix(row[0].(string), row[1].(string)))
+	}
+	assert.Len(t, shortHashes, len(rows))
+	length := shortHashLength(hashes)
+	assert.Greater(t, length, logShortHashMinLength)
+	for _, row := range rows {
+		assert.Len(t, row[1], length)
+	}
+
+	// Only the commits of the log are considered, so a log of one commit always uses the minimum length
+	rows = executeLogQuery(t, dEnv, false, "SELECT short_hash FROM dolt_log('main~1..main', '--show-short-hash');")
+	require.Len(t, rows, 1)
+	assert.Len(t, rows[0][0], logShortHashMinLength)
+}
+
+func TestAbbreviationLengths(t *testing.T) {
+	assert.Empty(t, abbreviationLengths(nil, 4))
+	// Only the hashes that share the requested prefix are extended, each just far enough to be unique
+	assert.Equal(t, map[string]int{
+		"abcdefghij": 8,
+		"abcdefgzzz": 8,
+		"abcdzzzzzz": 5,
+		"zzzzzzzzzz": 4,
+	}, abbreviationLengths([]string{"abcdefghij", "zzzzzzzzzz", "abcdefgzzz", "abcdzzzzzz"}, 4))
+	// A hash shorter than its collision is never extended past its own length, and identical hashes are the same commit
+	assert.Equal(t, map[string]int{"abcd": 4, "abcdefgh": 5}, abbreviationLengths([]string{"abcd", "abcdefgh"}, 2))
+	assert.Equal(t, map[string]int{"abcdefghij": 4}, abbreviationLengths([]string{"abcdefghij", "abcdefghij"}, 4))
+}
+
+func TestLogTableFunctionAbbrev(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "multi line", Email: "multi@fake.horse", Description: "first line\r\nsecond line\nthird line"},
+	})
+	rows := executeLogQuery(t, dEnv, false, "SELECT commit_hash, message, subject FROM dolt_log('--abbrev') LIMIT 1;")
+	require.Len(t, rows, 1)
+	assert.Equal(t, []interface{}{"first line\r\nsecond line\nthird line", "first line"}, []interface{}(rows[0][1:]))
+	assert.Len(t, rows[0][0], logDefaultAbbrevLength)
+	rows = executeLogQuery(t, dEnv, true, "SELECT commit_hash, subject FROM dolt_log('--abbrev=12') LIMIT 1;")
+	require.Len(t, rows, 1)
+	assert.Len(t, rows[0][0], 12)
+	assert.Equal(t, []byte("first line"), rows[0][1])
+
+	// As with short hashes, the minimum is lowered until the commits that are generated here collide
+	defer func(minLength int) {
+		logMinAbbrevLength = minLength
+	}(logMinAbbrevLength)
+	logMinAbbrevLength = 2
+
+	ctx := context.Background()
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	root, err := head.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rootHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	prefixes := make(map[string]bool)
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "no commits with a shared prefix were generated")
+		meta, err := datas.NewCommitMeta("brute", "brute@fake.horse", fmt.Sprintf("commit %d", i))
+		require.NoError(t, err)
+		cm, err := dEnv.DoltDB.Commit(ctx, rootHash, ref.NewBranchRef(env.DefaultInitBranch), meta)
+		require.NoError(t, err)
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+		prefix := h.String()[:logMinAbbrevLength]
+		if prefixes[prefix] {
+			break
+		}
+		prefixes[prefix] = true
+	}
+
+	full := executeLogQuery(t, dEnv, false, "SELECT commit_hash FROM dolt_log();")
+	abbreviated := executeLogQuery(t, dEnv, false, "SELECT commit_hash FROM dolt_log('--abbrev=2');")
+	require.Len(t, abbreviated, len(full))
+	var hashes []string
+	for _, row := range full {
+		hashes = append(hashes, row[0].(string))
+	}
+	lengths := abbreviationLengths(hashes, 2)
+	extended := 0
+	unique := make(map[string]bool)
+	for i, row := range abbreviated {
+		abbrev := row[0].(string)
+		assert.True(t, strings.HasPrefix(hashes[i], abbrev))
+		assert.Len(t, abbrev, lengths[hashes[i]])
+		if len(abbrev) > 2 {
+			extended++
+		}
+		unique[abbrev] = true
+	}
+	// The colliding commits are extended, while every other commit keeps the requested length
+	assert.GreaterOrEqual(t, extended, 2)
+	assert.Less(t, extended, len(abbreviated))
+	assert.Len(t, unique, len(abbreviated))
+}
+
+func TestLogTableFunctionProjections(t *testing.T) {
+	largeMessage := strings.Repeat("vendored changelog\n", 1<<16)
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "importer", Email: "importer@fake.horse", Description: largeMessage},
+		{Name: "importer", Email: "importer@fake.horse", Description: "small message"},
+	})
+
+	messageLoads := 0
+	defer func(getMeta func(ctx *sql.Context, cm *doltdb.Commit, withMessage bool) (*datas.CommitMeta, error)) {
+		getLogCommitMeta = getMeta
+	}(getLogCommitMeta)
+	getLogCommitMeta = func(ctx *sql.Context, cm *doltdb.Commit, withMessage bool) (*datas.CommitMeta, error) {
+		if withMessage {
+			messageLoads++
+		}
+		meta, err := cm.GetCommitMetaWithoutDescription(ctx)
+		if err != nil || !withMessage {
+			return meta, err
+		}
+		return cm.GetCommitMeta(ctx)
+	}
+
+	tests := []struct {
+		query         string
+		loadsMessages bool
+	}{
+		{"SELECT commit_hash FROM dolt_log();", false},
+		{"SELECT count(*) FROM dolt_log();", false},
+		{"SELECT commit_hash, committer FROM dolt_log() WHERE email LIKE 'importer%' ORDER BY date LIMIT 5;", false},
+		{"SELECT committer, count(*) FROM dolt_log('--abbrev') GROUP BY committer;", false},
+		{"SELECT commit_hash FROM dolt_log() WHERE message LIKE 'small%';", true},
+		{"SELECT commit_hash FROM dolt_log('--abbrev') ORDER BY subject;", true},
+		{"SELECT length(message) FROM dolt_log();", true},
+		{"SELECT * FROM dolt_log();", true},
+		// Messages are loaded whenever it can't be shown that they're not read
+		{"SELECT commit_hash, (SELECT count(*) FROM dolt_branches) FROM dolt_log();", true},
+		{"SELECT c.commit_hash FROM dolt_commits JOIN (SELECT * FROM dolt_log()) c ON c.commit_hash = dolt_commits.commit_hash;", true},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			messageLoads = 0
+			rows := executeLogQuery(t, dEnv, false, test.query)
+			require.NotEmpty(t, rows)
+			if test.loadsMessages {
+				assert.NotZero(t, messageLoads)
+			} else {
+				assert.Zero(t, messageLoads)
+			}
+		})
+	}
+
+	// Projected columns are the same as those of an unprojected query
+	all := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log('--abbrev');")
+	projected := executeLogQuery(t, dEnv, false, "SELECT commit_hash, committer, email, date, commit_order FROM dolt_log('--abbrev');")
+	messages := executeLogQuery(t, dEnv, false, "SELECT commit_hash, message, subject FROM dolt_log('--abbrev');")
+	require.Len(t, projected, len(all))
+	require.Len(t, messages, len(all))
+	for i, row := range all {
+		assert.Equal(t, sql.NewRow(row[0], row[1], row[2], row[3], row[5]), projected[i])
+		assert.Equal(t, sql.NewRow(row[0], row[4], row[7]), messages[i])
+	}
+	assert.Equal(t, largeMessage, all[1][4])
+	assert.Equal(t, "vendored changelog", all[1][7])
+}
+
+func TestLogTableFunctionLimitPushdown(t *testing.T) {
+	var metas []datas.CommitMeta
+	for i := 1; i <= 5; i++ {
+		metas = append(metas, datas.CommitMeta{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: fmt.Sprintf("commit %d", i)})
+	}
+	dEnv := createLogEnvWithCommits(t, metas)
+
+	metaLoads := 0
+	defer func(getMeta func(ctx *sql.Context, cm *doltdb.Commit, withMessage bool) (*datas.CommitMeta, error)) {
+		getLogCommitMeta = getMeta
+	}(getLogCommitMeta)
+	getLogCommitMeta = func(ctx *sql.Context, cm *doltdb.Commit, withMessage bool) (*datas.CommitMeta, error) {
+		metaLoads++
+		return cm.GetCommitMeta(ctx)
+	}
+
+	all := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log();")
+	require.Len(t, all, 6)
+	reversed := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log('--reverse');")
+	graph := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log('--graph');")
+
+	tests := []struct {
+		query    string
+		expected []sql.Row
+		// metaLoads is the number of rows that are built, which is every row when the limit isn't pushed down
+		metaLoads int
+	}{
+		{"SELECT * FROM dolt_log() LIMIT 2;", all[:2], 2},
+		{"SELECT * FROM dolt_log() LIMIT 2 OFFSET 3;", all[3:5], 2},
+		{"SELECT * FROM dolt_log() LIMIT 3, 2;", all[3:5], 2},
+		{"SELECT * FROM dolt_log() LIMIT 10 OFFSET 4;", all[4:], 2},
+		{"SELECT * FROM dolt_log() LIMIT 0;", nil, 0},
+		{"SELECT * FROM dolt_log('--reverse') LIMIT 2 OFFSET 1;", reversed[1:3], 2},
+		{"SELECT * FROM dolt_log('--graph') LIMIT 2 OFFSET 1;", graph[1:3], 2},
+		// Limits can't be pushed through nodes that filter or reorder the rows
+		{"SELECT * FROM dolt_log() WHERE message <> 'commit 5' LIMIT 1 OFFSET 1;", all[2:3], 3},
+		{"SELECT * FROM dolt_log() ORDER BY commit_order LIMIT 1 OFFSET 1;", all[4:5], 6},
+		{"SELECT * FROM (SELECT * FROM dolt_log()) l LIMIT 1 OFFSET 5;", all[5:], 6},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			metaLoads = 0
+			rows := executeLogQuery(t, dEnv, false, test.query)
+			assert.Equal(t, test.expected, rows)
+			assert.Equal(t, test.metaLoads, metaLoads)
+		})
+	}
+}
+
+func TestLogTableFunctionTracing(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: "first"},
+		{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: "second"},
+		{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: "third"},
+	})
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(t, dEnv, ctx, db, root)
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	tracedCtx := sql.NewContext(ctx, sql.WithSession(sqlCtx.Session), sql.WithTracer(tracer))
+	// query returns the number of rows of the given query, along with the attributes of the query's span by name
+	query := func(query string) (int, map[string]int64) {
+		sch, iter, err := engine.Query(tracedCtx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(tracedCtx, sch, iter)
+		require.NoError(t, err)
+		attributes := make(map[string]int64)
+		for _, span := range recorder.Ended() {
+			if span.Name() == logSpanQuery {
+				for _, kv := range span.Attributes() {
+					attributes[string(kv.Key)] = kv.Value.AsInt64()
+				}
+			}
+		}
+		return len(rows), attributes
+	}
+	spanNames := func() []string {
+		var names []string
+		for _, span := range recorder.Ended() {
+			if strings.HasPrefix(span.Name(), logSpanQuery) {
+				names = append(names, span.Name())
+			}
+		}
+		return names
+	}
+
+	// The history is the initial commit followed by the three fixture commits
+	before := GetLogRowLatency()
+	count, attributes := query("SELECT * FROM dolt_log('--decorate', 'short', '--abbrev');")
+	assert.Equal(t, 4, count)
+	assert.ElementsMatch(t, []string{logSpanResolve, logSpanDecorate, logSpanAbbreviate, logSpanQuery}, spanNames())
+	// Abbreviating walks the log once before the rows are returned, so every commit is visited and read twice
+	assert.Equal(t, int64(4), attributes["rows"])
+	assert.Equal(t, int64(8), attributes["commits_visited"])
+	assert.Equal(t, int64(0), attributes["commits_skipped"])
+	assert.Equal(t, int64(8), attributes["chunk_reads"])
+	assert.Greater(t, attributes["meta_decoding_ns"], int64(0))
+	assert.Equal(t, before.Count+4, GetLogRowLatency().Count)
+
+	// Commits rejected by the match function are visited and skipped, and only the resolution is traced otherwise
+	recorder = tracetest.NewSpanRecorder()
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	tracedCtx = sql.NewContext(ctx, sql.WithSession(sqlCtx.Session), sql.WithTracer(tracer))
+	count, attributes = query("SELECT commit_hash FROM dolt_log('--merges');")
+	assert.Zero(t, count)
+	assert.ElementsMatch(t, []string{logSpanResolve, logSpanQuery}, spanNames())
+	assert.Equal(t, int64(0), attributes["rows"])
+	assert.Equal(t, int64(4), attributes["commits_visited"])
+	assert.Equal(t, int64(4), attributes["commits_skipped"])
+	assert.Equal(t, int64(4), attributes["chunk_reads"])
+
+	// Nothing is allocated to trace queries when tracing is disabled
+	untracedCtx := sql.NewEmptyContext()
+	allocs := testing.AllocsPerRun(100, func() {
+		_, lt := startLogTrace(untracedCtx)
+		lt.end()
+	})
+	assert.Zero(t, allocs)
+}
+
+// TestLogTableFunctionWithoutWorkingSets runs dolt_log against a read-only engine whose branches have no working sets,
+// as is the case for branches that a read replica has fetched but never checked out. Reading the log must succeed
+// without writing any working sets.
+func TestLogTableFunctionWithoutWorkingSets(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: "first"},
+		{Name: "billy bob", Email: "bigbillieb@fake.horse", Description: "second"},
+	})
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	fetchedRef := ref.NewBranchRef("fetched")
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, fetchedRef, head))
+
+	var wsRefs []ref.WorkingSetRef
+	for _, branchRef := range []ref.DoltRef{ref.NewBranchRef(env.DefaultInitBranch), fetchedRef} {
+		wsRef, err := ref.WorkingSetRefForHead(branchRef)
+		require.NoError(t, err)
+		require.NoError(t, dEnv.DoltDB.DeleteWorkingSet(ctx, wsRef))
+		wsRefs = append(wsRefs, wsRef)
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	dbState, err := GetInitialDBState(ctx, db)
+	require.NoError(t, err)
+
+	pro, err := NewDoltDatabaseProviderWithDatabase(env.GetDefaultInitBranch(dEnv.Config), dEnv.FS, db, dEnv.FS)
+	require.NoError(t, err)
+	engine := sqle.NewDefault(pro)
+	engine.IsReadOnly = true
+	sqlCtx := NewTestSQLCtxWithProvider(ctx, pro)
+	require.NoError(t, dsess.DSessFromSess(sqlCtx.Session).AddDB(sqlCtx, dbState))
+
+	queryMessages := func(dbName string, query string) []sql.Row {
+		sqlCtx.SetCurrentDatabase(dbName)
+		sch, iter, err := engine.Query(sqlCtx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(sqlCtx, sch, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	expected := []sql.Row{{"second"}, {"first"}}
+	assert.Equal(t, expected, queryMessages("dolt", "SELECT message FROM dolt_log() LIMIT 2;"))
+	assert.Equal(t, expected, queryMessages("dolt", "SELECT message FROM dolt_log('fetched') LIMIT 2;"))
+	assert.Equal(t, expected, queryMessages("dolt/fetched", "SELECT message FROM dolt_log() LIMIT 2;"))
+	assert.Equal(t, expected, queryMessages("dolt/fetched", "SELECT message FROM dolt_log('--decorate', 'full') LIMIT 2;"))
+
+	for _, wsRef := range wsRefs {
+		_, err = dEnv.DoltDB.ResolveWorkingSet(ctx, wsRef)
+		assert.Equal(t, doltdb.ErrWorkingSetNotFound, err)
+	}
+}
+
+func TestLogTableFunctionRevisionDatabases(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(t, dEnv, ctx, db, root)
+	require.NoError(t, err)
+	query := func(query string) []sql.Row {
+		sch, iter, err := engine.Query(sqlCtx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(sqlCtx, sch, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	query("CREATE TABLE t (pk int primary key);")
+	query("CALL dolt_commit('-Am', 'first', '--author', 'billy bob <bigbillieb@fake.horse>');")
+	query("CALL dolt_branch('feature1');")
+	query("CALL dolt_tag('v1');")
+	parentHash := query("SELECT hashof('HEAD');")[0][0].(string)
+	query("INSERT INTO t VALUES (1);")
+	query("CALL dolt_commit('-am', 'second', '--author', 'billy bob <bigbillieb@fake.horse>');")
+
+	// The log of each revision database starts at the revision its name pins, rather than at the head of main
+	for _, revision := range []string{"feature1", "v1", parentHash} {
+		t.Run(revision, func(t *testing.T) {
+			dbName := "dolt/" + revision
+			rows := query(fmt.Sprintf("SELECT commit_hash, message FROM dolt_log('--database', '%s') LIMIT 1;", dbName))
+			assert.Equal(t, []sql.Row{{parentHash, "first"}}, rows)
+
+			// Privileges are checked against the base database, so that grants on it apply to its revision databases
+			revisionDb, err := dsess.DSessFromSess(sqlCtx.Session).Provider().Database(sqlCtx, dbName)
+			require.NoError(t, err)
+			checker := &recordingPrivilegeChecker{}
+			assert.True(t, (&LogTableFunction{database: revisionDb}).CheckPrivileges(sqlCtx, checker))
+			require.NotEmpty(t, checker.operations)
+			for _, op := range checker.operations {
+				assert.Equal(t, "dolt", op.Database)
+			}
+		})
+	}
+	assert.Equal(t, []sql.Row{{"second"}}, query("SELECT message FROM dolt_log() LIMIT 1;"))
+}
+
+// recordingPrivilegeChecker records the operations that are checked, and grants every privilege.
+type recordingPrivilegeChecker struct {
+	operations []sql.PrivilegedOperation
+}
+
+func (c *recordingPrivilegeChecker) UserHasPrivileges(ctx *sql.Context, operations ...sql.PrivilegedOperation) bool {
+	c.operations = append(c.operations, operations...)
+	return true
+}
+
+func TestLogTableFunctionRefsDeletedDuringDecoration(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "first"}})
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	branchRef := ref.NewBranchRef("feature")
+	tagRef := ref.NewTagRef("v1")
+	createRefs := func() {
+		require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, branchRef, head))
+		require.NoError(t, dEnv.DoltDB.NewTagAtCommit(ctx, tagRef, head, datas.NewTagMeta("name", "name@fake.horse", "")))
+	}
+	defer func() {
+		logTableFunctionHooks.afterResolve = nil
+		logTableFunctionHooks.afterRefSnapshot = nil
+	}()
+	query := "SELECT refs FROM dolt_log('--decorate', 'short') LIMIT 1;"
+
+	// A branch deleted after the revision was resolved is no longer decorated
+	createRefs()
+	logTableFunctionHooks.afterResolve = func(ctx *sql.Context) error {
+		return dEnv.DoltDB.DeleteBranch(ctx, branchRef)
+	}
+	assert.Equal(t, []sql.Row{{"HEAD -> main, tag: v1"}}, executeLogQuery(t, dEnv, false, query))
+	logTableFunctionHooks.afterResolve = nil
+	require.NoError(t, dEnv.DoltDB.DeleteTag(ctx, tagRef))
+
+	// A tag deleted after the refs were read is skipped, while the branches are decorated from the same snapshot
+	createRefs()
+	logTableFunctionHooks.afterRefSnapshot = func(ctx *sql.Context) error {
+		if err := dEnv.DoltDB.DeleteBranch(ctx, branchRef); err != nil {
+			return err
+		}
+		return dEnv.DoltDB.DeleteTag(ctx, tagRef)
+	}
+	assert.Equal(t, []sql.Row{{"HEAD -> feature, main"}}, executeLogQuery(t, dEnv, false, query))
+}
+
+func TestLogTableFunctionJSONFormat(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "first"}})
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature,with,commas"), head))
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.SetHead(ctx, ref.NewRemoteRef("origin", "main"), headHash))
+	require.NoError(t, dEnv.DoltDB.NewTagAtCommit(ctx, ref.NewTagRef("v1"), head, datas.NewTagMeta("name", "name@fake.horse", "")))
+	parents, err := head.ParentHashes(ctx)
+	require.NoError(t, err)
+	require.Len(t, parents, 1)
+
+	rows := executeLogQuery(t, dEnv, false, "SELECT parents, refs FROM dolt_log('--parents', '--decorate', 'short', '--format', 'json') LIMIT 1;")
+	require.Len(t, rows, 1)
+	assert.Equal(t, sql.MustJSON(fmt.Sprintf(`["%s"]`, parents[0].String())), rows[0][0])
+	assert.Equal(t, sql.MustJSON(`[
+		{"name": "feature,with,commas", "type": "branch", "is_head": false},
+		{"name": "main", "type": "branch", "is_head": true},
+		{"name": "origin/main", "type": "remote", "is_head": false},
+		{"name": "v1", "type": "tag", "is_head": false}
+	]`), rows[0][1])
+
+	rows = executeLogQuery(t, dEnv, false, "SELECT refs FROM dolt_log('--decorate', 'full', '--format', 'json') LIMIT 1;")
+	require.Len(t, rows, 1)
+	assert.Equal(t, sql.MustJSON(`[
+		{"name": "refs/heads/feature,with,commas", "type": "branch", "is_head": false},
+		{"name": "refs/heads/main", "type": "branch", "is_head": true},
+		{"name": "refs/remotes/origin/main", "type": "remote", "is_head": false},
+		{"name": "refs/tags/v1", "type": "tag", "is_head": false}
+	]`), rows[0][0])
+
+	// The text format is unchanged
+	rows = executeLogQuery(t, dEnv, false, "SELECT refs FROM dolt_log('--decorate', 'short') LIMIT 1;")
+	assert.Equal(t, []sql.Row{{"HEAD -> feature,with,commas, main, origin/main, tag: v1"}}, rows)
+}
+
+func TestLogTableFunctionMissingAncestors(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "name", Email: "name@fake.horse", Description: "first"},
+		{Name: "name", Email: "name@fake.horse", Description: "second"},
+		{Name: "name", Email: "name@fake.horse", Description: "third"},
+		{Name: "name", Email: "name@fake.horse", Description: "fourth"},
+	})
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	// commits holds the initial commit followed by the commits above
+	commits := []*doltdb.Commit{head}
+	for i := 0; i < 4; i++ {
+		parent, err := commits[0].GetParent(ctx, 0)
+		require.NoError(t, err)
+		commits = append([]*doltdb.Commit{parent}, commits...)
+	}
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("root"), commits[0]))
+	firstHash, err := commits[1].HashOf()
+	require.NoError(t, err)
+
+	// A commit can only be loaded along with its parents, so removing the first commit leaves the third as the oldest
+	// commit that can be loaded, as in a shallow clone
+	dEnv.DoltDB = doltdb.DoltDBFromCS(&chunks.MissingChunkStore{
+		ChunkStore: datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB)),
+		Missing:    hash.NewHashSet(firstHash),
+	})
+
+	rows := executeLogQuery(t, dEnv, false, "SELECT message FROM dolt_log();")
+	assert.Equal(t, []sql.Row{{"fourth"}, {"third"}}, rows)
+	rows = executeLogQuery(t, dEnv, false, "SELECT message, is_boundary FROM dolt_log('--boundary');")
+	assert.Equal(t, []sql.Row{{"fourth", false}, {"third", true}}, rows)
+	rows = executeLogQuery(t, dEnv, false, "SELECT message, is_boundary FROM dolt_log('root..main', '--boundary');")
+	assert.Equal(t, []sql.Row{{"fourth", false}, {"third", true}}, rows)
+	rows = executeLogQuery(t, dEnv, false, "SELECT message, is_boundary FROM dolt_log('--reverse', '--boundary');")
+	assert.Equal(t, []sql.Row{{"third", true}, {"fourth", false}}, rows)
+	rows = executeLogQuery(t, dEnv, false, "SELECT message, graph_order FROM dolt_log('--graph');")
+	assert.Equal(t, []sql.Row{{"fourth", int64(0)}, {"third", int64(1)}}, rows)
+	// The boundary is omitted when it does not match, without failing the query
+	rows = executeLogQuery(t, dEnv, false, "SELECT message FROM dolt_log('--merges');")
+	assert.Empty(t, rows)
+}
+
+func TestLogTableFunctionOctopusMerge(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "base"}})
+	base, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	root, err := base.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rootHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	newMeta := func(description string) *datas.CommitMeta {
+		meta, err := datas.NewCommitMeta("name", "name@fake.horse", description)
+		require.NoError(t, err)
+		return meta
+	}
+
+	// Merges made with SQL only have two parents, so the octopus merge of main with two side branches is written
+	// directly through DoltDB
+	side1, err := dEnv.DoltDB.CommitDanglingWithParentCommits(ctx, rootHash, []*doltdb.Commit{base}, newMeta("side1"))
+	require.NoError(t, err)
+	side2, err := dEnv.DoltDB.CommitDanglingWithParentCommits(ctx, rootHash, []*doltdb.Commit{base}, newMeta("side2"))
+	require.NoError(t, err)
+	octopus, err := dEnv.DoltDB.CommitWithParentCommits(ctx, rootHash, ref.NewBranchRef(env.DefaultInitBranch), []*doltdb.Commit{side1, side2}, newMeta("octopus"))
+	require.NoError(t, err)
+	require.Equal(t, 3, octopus.NumParents())
+
+	tests := []struct {
+		args     string
+		expected []sql.Row
+	}{
+		{"", []sql.Row{{"Initialize data repository"}, {"base"}, {"octopus"}, {"side1"}, {"side2"}}},
+		{"'--merges'", []sql.Row{{"octopus"}}},
+		{"'--no-merges'", []sql.Row{{"Initialize data repository"}, {"base"}, {"side1"}, {"side2"}}},
+		{"'--max-parents', '0'", []sql.Row{{"Initialize data repository"}}},
+		{"'--max-parents', '1'", []sql.Row{{"Initialize data repository"}, {"base"}, {"side1"}, {"side2"}}},
+		// A merge with three parents is excluded by a maximum of two
+		{"'--max-parents', '2'", []sql.Row{{"Initialize data repository"}, {"base"}, {"side1"}, {"side2"}}},
+		{"'--max-parents', '3'", []sql.Row{{"Initialize data repository"}, {"base"}, {"octopus"}, {"side1"}, {"side2"}}},
+		{"'--min-parents', '3', '--max-parents', '3'", []sql.Row{{"octopus"}}},
+		{"'--min-parents', '1', '--max-parents', '2'", []sql.Row{{"base"}, {"side1"}, {"side2"}}},
+		{"'--min-parents', '4'", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.args, func(t *testing.T) {
+			rows := executeLogQuery(t, dEnv, false, fmt.Sprintf("SELECT message FROM dolt_log(%s) ORDER BY message;", test.args))
+			assert.Equal(t, test.expected, rows)
+		})
+	}
+}
+
+func TestLogTableFunctionUnfetchedRemoteBranch(t *testing.T) {
+	ctx := context.Background()
+	remoteDir := t.TempDir()
+	remoteUrl := "file://" + filepath.ToSlash(remoteDir)
+	remoteDB, err := doltdb.LoadDoltDB(ctx, types.Format_Default, remoteUrl, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, remoteDB.WriteEmptyRepo(ctx, "feature", "remote", "remote@fake.horse"))
+
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "first"}})
+	require.NoError(t, dEnv.AddRemote(env.NewRemote("origin", remoteUrl, nil)))
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(t, dEnv, ctx, db, root)
+	require.NoError(t, err)
+	query := func(query string) ([]sql.Row, error) {
+		sch, iter, err := engine.Query(sqlCtx, query)
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(sqlCtx, sch, iter)
+	}
+
+	// The remote is configured, so its branches are reported as not fetched, wherever the revision is given
+	tests := []struct {
+		query  string
+		detail ArgumentErrorDetail
+	}{
+		{"SELECT * FROM dolt_log('origin/feature');", ArgumentErrorDetail{Index: 0, Code: ArgumentErrorUnfetchedRevision}},
+		{"SELECT * FROM dolt_log('--stat', 'origin/feature~1');", ArgumentErrorDetail{Index: 1, Code: ArgumentErrorUnfetchedRevision}},
+		{"SELECT * FROM dolt_log('origin/feature..main');", ArgumentErrorDetail{Index: 0, Code: ArgumentErrorUnfetchedRevision}},
+		{"SELECT * FROM dolt_log('main', '--not', 'refs/remotes/origin/feature');", ArgumentErrorDetail{Index: 1, Flag: cli.NotFlag, Code: ArgumentErrorUnfetchedRevision}},
+		{"SELECT * FROM dolt_log('--contains', 'origin/feature');", ArgumentErrorDetail{Index: -1, Flag: cli.ContainsParam, Code: ArgumentErrorUnfetchedRevision}},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := query(test.query)
+			require.Error(t, err)
+			detail, ok := GetArgumentErrorDetail(err)
+			require.True(t, ok, err.Error())
+			assert.Equal(t, test.detail, detail)
+			assert.Contains(t, err.Error(), "remote-tracking ref origin/feature has not been fetched: branch `feature` of remote `origin`")
+		})
+	}
+
+	// Revisions of remotes that are not configured fail to resolve as before
+	_, err = query("SELECT * FROM dolt_log('upstream/feature');")
+	require.Error(t, err)
+	_, ok := GetArgumentErrorDetail(err)
+	assert.False(t, ok)
+	assert.True(t, goerrors.Is(err, doltdb.ErrBranchNotFound))
+
+	_, err = query("SELECT * FROM dolt_log('--fetch', 'upstream', 'origin/feature');")
+	require.Error(t, err)
+	detail, ok := GetArgumentErrorDetail(err)
+	require.True(t, ok)
+	assert.Equal(t, ArgumentErrorDetail{Index: 0, Flag: cli.FetchParam, Code: ArgumentErrorInvalidValue}, detail)
+
+	// --fetch resolves the branch in one step, after which it's fetched for every query
+	rows, err := query("SELECT committer, message FROM dolt_log('--fetch', 'origin', 'origin/feature');")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"remote", "Initialize data repository"}}, rows)
+	rows, err = query("SELECT committer FROM dolt_log('origin/feature');")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"remote"}}, rows)
+}
+
+func TestLogTableFunctionDuplicateArguments(t *testing.T) {
+	ltf := &LogTableFunction{defaultDecoration: "auto"}
+	tests := []struct {
+		option   string
+		args     []string
+		expected func(args logArguments) bool
+		warning  string
+	}{
+		{cli.NumberFlag, []string{"-n", "1", "-n", "2"}, nil, "--number was given more than once, so the last value `2` is used"},
+		{cli.MinParentsFlag, []string{"--min-parents", "1", "--min-parents", "2"}, func(args logArguments) bool { return args.minParents == 2 }, "--min-parents was given more than once, so the last value `2` is used"},
+		{cli.MergesFlag, []string{"--merges", "--merges"}, func(args logArguments) bool { return args.minParents == 2 }, "--merges was given more than once"},
+		{cli.ParentsFlag, []string{"--parents", "--parents"}, func(args logArguments) bool { return args.showParents }, "--parents was given more than once"},
+		{cli.DecorateFlag, []string{"--decorate", "short", "--decorate", "full"}, func(args logArguments) bool { return args.decoration == "full" }, "--decorate was given more than once, so the last value `full` is used"},
+		{cli.OneLineFlag, []string{"--oneline", "--oneline"}, nil, "--oneline was given more than once"},
+		{cli.NotFlag, []string{"--not", "x", "--not", "y"}, func(args logArguments) bool { return len(args.notRevisions) == 2 }, ""},
+		{cli.StatFlag, []string{"--stat", "--stat"}, func(args logArguments) bool { return args.showStat }, "--stat was given more than once"},
+		{cli.ReverseFlag, []string{"--reverse", "--reverse"}, func(args logArguments) bool { return args.reverse }, "--reverse was given more than once"},
+		{cli.DatabaseParam, []string{"--database", "a", "--database", "b"}, func(args logArguments) bool { return args.database == "b" }, "--database was given more than once, so the last value `b` is used"},
+		{cli.GraphFlag, []string{"--graph", "--graph"}, func(args logArguments) bool { return args.showGraph }, "--graph was given more than once"},
+		{cli.StartOrderParam, []string{"--start-order", "1", "--start-order", "2"}, func(args logArguments) bool { return args.startOrder == 2 }, "--start-order was given more than once, so the last value `2` is used"},
+		{cli.EndOrderParam, []string{"--end-order", "1", "--end-order", "2"}, func(args logArguments) bool { return args.endOrder == 2 }, "--end-order was given more than once, so the last value `2` is used"},
+		{cli.FormatParam, []string{"--format", "json", "--format", "text"}, func(args logArguments) bool { return !args.jsonFormat }, "--format was given more than once, so the last value `text` is used"},
+		{cli.ContainsParam, []string{"--contains", "x", "--contains", "x"}, func(args logArguments) bool { return len(args.containsRefs) == 2 }, ""},
+		{cli.BoundaryFlag, []string{"--boundary", "--boundary"}, func(args logArguments) bool { return args.showBoundary }, "--boundary was given more than once"},
+		{cli.MergeBaseFlag, []string{"--merge-base", "--merge-base"}, func(args logArguments) bool { return args.showMergeBase }, "--merge-base was given more than once"},
+		{cli.TrailerParam, []string{"--trailer", "Signed-off-by", "--trailer", "Signed-off-by"}, func(args logArguments) bool { return len(args.trailerConditions) == 2 }, ""},
+		{cli.NoTrailerParam, []string{"--no-trailer", "Signed-off-by", "--no-trailer", "Signed-off-by"}, func(args logArguments) bool { return len(args.excludedTrailers) == 2 }, ""},
+		{cli.FirstParentFlag, []string{"--first-parent", "--first-parent"}, func(args logArguments) bool { return args.firstParent }, "--first-parent was given more than once"},
+		{cli.ShortHashFlag, []string{"--show-short-hash", "--show-short-hash"}, func(args logArguments) bool { return args.showShortHash }, "--show-short-hash was given more than once"},
+		{cli.AbbrevParam, []string{"--abbrev", "--abbrev=12"}, func(args logArguments) bool { return args.abbrevLength == 12 }, "--abbrev was given more than once, so the last value `12` is used"},
+		{cli.MaxParentsFlag, []string{"--max-parents", "0", "--max-parents", "2"}, func(args logArguments) bool { return args.maxParents == 2 }, "--max-parents was given more than once, so the last value `2` is used"},
+		{cli.NoMergesFlag, []string{"--no-merges", "--no-merges"}, func(args logArguments) bool { return args.maxParents == 1 }, "--no-merges was given more than once"},
+		{cli.FetchParam, []string{"--fetch", "a", "--fetch", "b"}, func(args logArguments) bool { return args.fetchRemote == "b" }, "--fetch was given more than once, so the last value `b` is used"},
+		{cli.AllFlag, []string{"--all", "--all"}, func(args logArguments) bool { return len(args.sourceRefs) == 3 }, "--all was given more than once"},
+		{cli.BranchesFlag, []string{"--branches", "--branches"}, func(args logArguments) bool { return len(args.sourceRefs) == 1 }, "--branches was given more than once"},
+		{cli.SourceLimitParam, []string{"--all", "--per-source-limit", "1", "--per-source-limit", "3"}, func(args logArguments) bool { return args.perSourceLimit == 3 }, "--per-source-limit was given more than once, so the last value `3` is used"},
+		{cli.WithParam, []string{"--with", "message_word_count", "--with", "MESSAGE_WORD_COUNT"}, func(args logArguments) bool { return len(args.columnProviders) == 1 }, "--with provider `message_word_count` was given more than once"},
+		{cli.CheckPolicyParam, []string{"--check-policy", "a", "--check-policy", "b"}, func(args logArguments) bool { return args.policyTable == "b" }, "--check-policy was given more than once, so the last value `b` is used"},
+		{cli.SinceParam, []string{"--since", "2023-01-01", "--since", "2023-02-01"}, func(args logArguments) bool { return args.since.Month() == time.February }, "--since was given more than once, so the last value `2023-02-01` is used"},
+		{cli.UntilParam, []string{"--until", "2023-01-01", "--until", "2023-02-01"}, func(args logArguments) bool { return args.until.Month() == time.February }, "--until was given more than once, so the last value `2023-02-01` is used"},
+		{cli.TablesParam, []string{"--tables", "a", "--tables", "b,c"}, func(args logArguments) bool { return len(args.tables) == 2 }, "--tables was given more than once, so the last value `b,c` is used"},
+	}
+
+	// Every option must have a policy for duplicates, which is verified here
+	tested := make(map[string]bool)
+	for _, test := range tests {
+		tested[test.option] = true
+	}
+	for _, opt := range cli.CreateLogTableFunctionArgParser().Supported {
+		assert.Contains(t, logDuplicatePolicies, opt.Name)
+		assert.True(t, tested[opt.Name], "duplicates of --%s are not tested", opt.Name)
+	}
+
+	for _, test := range tests {
+		t.Run(test.option, func(t *testing.T) {
+			args, err := ltf.parseArguments(test.args)
+			require.NoError(t, err)
+			if test.expected != nil {
+				assert.True(t, test.expected(args))
+			}
+			if test.warning == "" {
+				assert.Empty(t, args.warnings)
+			} else {
+				assert.Equal(t, []string{test.warning}, args.warnings)
+			}
+		})
+	}
+
+	t.Run("revisions", func(t *testing.T) {
+		args, err := ltf.parseArguments([]string{"main", "main..feature", "main", "main..feature"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"main", "main..feature"}, args.revisions)
+		assert.Equal(t, []string{"revision `main` was given more than once", "revision `main..feature` was given more than once"}, args.warnings)
+	})
+}
+
+func TestLogTableFunctionGlobalDefaultOptions(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{{Name: "name", Email: "name@fake.horse", Description: "first"}})
+	require.NoError(t, sql.SystemVariables.SetGlobal(dsess.LogShowParents, int8(1)))
+	require.NoError(t, sql.SystemVariables.SetGlobal(dsess.LogDecorate, "full"))
+	defer func() {
+		require.NoError(t, sql.SystemVariables.SetGlobal(dsess.LogShowParents, int8(0)))
+		require.NoError(t, sql.SystemVariables.SetGlobal(dsess.LogDecorate, "auto"))
+	}()
+
+	// New sessions take their defaults from the global values
+	rows := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log() LIMIT 1;")
+	require.Len(t, rows, 1)
+	require.Len(t, rows[0], len(logTableSchema)+2)
+	assert.Equal(t, "HEAD -> refs/heads/main", rows[0][len(logTableSchema)+1])
+	rows = executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log('--decorate', 'no') LIMIT 1;")
+	require.Len(t, rows, 1)
+	assert.Len(t, rows[0], len(logTableSchema)+1)
+}
+
+func TestLogChecksum(t *testing.T) {
+	defer func(now func() time.Time) {
+		datas.CommitNowFunc = now
+	}(datas.CommitNowFunc)
+	datas.CommitNowFunc = func() time.Time {
+		return time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+	}
+	metas := []datas.CommitMeta{
+		{Name: "name", Email: "name@fake.horse", Description: "first"},
+		{Name: "name", Email: "name@fake.horse", Description: "second"},
+	}
+	checksum := func(dEnv *env.DoltEnv, query string) string {
+		rows := executeLogQuery(t, dEnv, false, query)
+		require.Len(t, rows, 1)
+		require.Len(t, rows[0], 1)
+		return rows[0][0].(string)
+	}
+
+	// Identical histories in independent databases have the same checksum, even when their refs differ
+	dEnv1 := createLogEnvWithCommits(t, metas)
+	dEnv2 := createLogEnvWithCommits(t, metas)
+	head, err := dEnv2.HeadCommit(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, dEnv2.DoltDB.NewBranchAtCommit(context.Background(), ref.NewBranchRef("other"), head))
+	expected := checksum(dEnv1, "SELECT dolt_log_checksum();")
+	assert.Len(t, expected, 32)
+	assert.Equal(t, expected, checksum(dEnv2, "SELECT dolt_log_checksum();"))
+	assert.Equal(t, expected, checksum(dEnv2, "SELECT dolt_log_checksum('other');"))
+	// Options that only affect the output columns are ignored
+	assert.Equal(t, expected, checksum(dEnv1, "SELECT dolt_log_checksum('main', '--parents', '--decorate', 'full');"))
+
+	// A single additional commit changes the checksum
+	dEnv3 := createLogEnvWithCommits(t, append(metas, datas.CommitMeta{Name: "name", Email: "name@fake.horse", Description: "third"}))
+	assert.NotEqual(t, expected, checksum(dEnv3, "SELECT dolt_log_checksum();"))
+	assert.Equal(t, expected, checksum(dEnv3, "SELECT dolt_log_checksum('main~1');"))
+	assert.NotEqual(t, expected, checksum(dEnv1, "SELECT dolt_log_checksum('main~1');"))
+	assert.NotEqual(t, expected, checksum(dEnv1, "SELECT dolt_log_checksum('--reverse');"))
+}
+
+func TestLogTableFunctionArgumentErrorDetails(t *testing.T) {
+	ltf := &LogTableFunction{defaultDecoration: "auto"}
+	tests := []struct {
+		args     []string
+		expected ArgumentErrorDetail
+	}{
+		{[]string{"--unknown"}, ArgumentErrorDetail{Index: -1, Code: ArgumentErrorInvalidOption}},
+		{[]string{"main", "--format", "xml"}, ArgumentErrorDetail{Index: 1, Flag: cli.FormatParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--decorate", "short", "--decorate", "long"}, ArgumentErrorDetail{Index: 2, Flag: cli.DecorateFlag, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--start-order", "-1"}, ArgumentErrorDetail{Index: 0, Flag: cli.StartOrderParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--end-order", "-1"}, ArgumentErrorDetail{Index: 1, Flag: cli.EndOrderParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--parents", "main^"}, ArgumentErrorDetail{Index: 1, Code: ArgumentErrorMissingRevision}},
+		{[]string{"main..feature^", "other"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorInvalidRevision}},
+		{[]string{"main", "--stat", "feature^..other"}, ArgumentErrorDetail{Index: 2, Code: ArgumentErrorInvalidRevision}},
+		{[]string{"^main", "^feature"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorMissingRevision}},
+		{[]string{"--not", "main"}, ArgumentErrorDetail{Index: 0, Flag: cli.NotFlag, Code: ArgumentErrorMissingRevision}},
+		{[]string{"^main", "--not", "other"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorMissingRevision}},
+		{[]string{"main", "--not", "feature..other"}, ArgumentErrorDetail{Index: 1, Flag: cli.NotFlag, Code: ArgumentErrorInvalidRevision}},
+		{[]string{"main", "--not", "a", "--not", "feature^"}, ArgumentErrorDetail{Index: 3, Flag: cli.NotFlag, Code: ArgumentErrorInvalidRevision}},
+		{[]string{"main...feature", "other"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorConflictingRevisions}},
+		{[]string{"main", "feature...other"}, ArgumentErrorDetail{Index: 1, Code: ArgumentErrorConflictingRevisions}},
+		// Duplicate revisions are removed, so the index is that of the first occurrence
+		{[]string{"main...feature", "other", "main...feature"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorConflictingRevisions}},
+		{[]string{"main...feature", "--not", "other"}, ArgumentErrorDetail{Index: 0, Code: ArgumentErrorConflictingRevisions}},
+		{[]string{"main", "--merge-base"}, ArgumentErrorDetail{Index: 1, Flag: cli.MergeBaseFlag, Code: ArgumentErrorMissingRevision}},
+		{[]string{"main", "feature", "^other", "--merge-base"}, ArgumentErrorDetail{Index: 3, Flag: cli.MergeBaseFlag, Code: ArgumentErrorConflictingRevisions}},
+		{[]string{"main..feature", "--graph", "--merge-base"}, ArgumentErrorDetail{Index: 2, Flag: cli.MergeBaseFlag, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"--trailer", "Signed-off-by", "--trailer", "=Jane"}, ArgumentErrorDetail{Index: 2, Flag: cli.TrailerParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--no-trailer", "Signed-off-by=Jane"}, ArgumentErrorDetail{Index: 1, Flag: cli.NoTrailerParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--abbrev=3"}, ArgumentErrorDetail{Index: 1, Flag: cli.AbbrevParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--abbrev=long"}, ArgumentErrorDetail{Index: 0, Flag: cli.AbbrevParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--max-parents", "-1"}, ArgumentErrorDetail{Index: 1, Flag: cli.MaxParentsFlag, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--merges", "--no-merges"}, ArgumentErrorDetail{Index: 1, Flag: cli.NoMergesFlag, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"main", "--fetch", ""}, ArgumentErrorDetail{Index: 1, Flag: cli.FetchParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--branches"}, ArgumentErrorDetail{Index: 1, Flag: cli.BranchesFlag, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"--branches", "--all", "--not", "main"}, ArgumentErrorDetail{Index: 1, Flag: cli.AllFlag, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"main", "--per-source-limit", "2"}, ArgumentErrorDetail{Index: 1, Flag: cli.SourceLimitParam, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"--all", "--per-source-limit", "0"}, ArgumentErrorDetail{Index: 1, Flag: cli.SourceLimitParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--with", "unknown"}, ArgumentErrorDetail{Index: 1, Flag: cli.WithParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"main", "--check-policy", ""}, ArgumentErrorDetail{Index: 1, Flag: cli.CheckPolicyParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--branches", "--check-policy", "authors"}, ArgumentErrorDetail{Index: 1, Flag: cli.CheckPolicyParam, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"main", "--since", "yesterday"}, ArgumentErrorDetail{Index: 1, Flag: cli.SinceParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--until", "2023-13-01"}, ArgumentErrorDetail{Index: 0, Flag: cli.UntilParam, Code: ArgumentErrorInvalidValue}},
+		{[]string{"--since", "2023-02-01", "--until", "2023-01-01"}, ArgumentErrorDetail{Index: 2, Flag: cli.UntilParam, Code: ArgumentErrorConflictingOptions}},
+		{[]string{"main", "--tables", "orders,,customers"}, ArgumentErrorDetail{Index: 1, Flag: cli.TablesParam, Code: ArgumentErrorInvalidValue}},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			args, err := ltf.parseArguments(test.args)
+			if err == nil {
+				err = ltf.validateRevisions(args)
+			}
+			require.Error(t, err)
+			assert.True(t, sql.ErrInvalidArgumentDetails.Is(err))
+			detail, ok := GetArgumentErrorDetail(err)
+			require.True(t, ok)
+			assert.Equal(t, test.expected, detail)
+			// The message remains readable, with the detail as a suffix for clients that only receive the message
+			assert.True(t, strings.HasPrefix(err.Error(), "Invalid argument to dolt_log: "))
+			assert.True(t, strings.HasSuffix(err.Error(), fmt.Sprintf(": [arg_index=%d flag=%s code=%s]", test.expected.Index, test.expected.Flag, test.expected.Code)), err.Error())
+		})
+	}
+
+	t.Run("--database", func(t *testing.T) {
+		ltf := &LogTableFunction{argumentExprs: []sql.Expression{
+			expression.NewLiteral("main", sql.LongText),
+			expression.NewLiteral("--database", sql.LongText),
+			expression.NewGetField(0, sql.LongText, "db", false),
+		}}
+		err := ltf.resolveDatabaseArgument(logArguments{})
+		require.Error(t, err)
+		detail, ok := GetArgumentErrorDetail(err)
+		require.True(t, ok)
+		assert.Equal(t, ArgumentErrorDetail{Index: 1, Flag: cli.DatabaseParam, Code: ArgumentErrorNotConstant}, detail)
+	})
+
+	t.Run("escaped reason", func(t *testing.T) {
+		args, err := ltf.parseArguments([]string{"50%^"})
+		require.NoError(t, err)
+		err = ltf.validateRevisions(args)
+		assert.Equal(t, "Invalid argument to dolt_log: 50%^ - a revision without '^' must exist if every revision contains '^': [arg_index=0 flag= code=missing_revision]", err.Error())
+	})
+
+	_, ok := GetArgumentErrorDetail(sql.ErrInvalidArgumentDetails.New("dolt_log", "other"))
+	assert.False(t, ok)
+}
+
+func TestLogTableFunctionColumnProviders(t *testing.T) {
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "name", Email: "name@fake.horse", Description: "first commit"},
+		{Name: "name", Email: "name@fake.horse", Description: "fail on this commit"},
+	})
+
+	calls := 0
+	require.NoError(t, RegisterLogColumnProvider(LogColumnProvider{
+		Name: "message_length",
+		Type: sql.Int32,
+		Compute: func(ctx *sql.Context, cm *doltdb.Commit, h hash.Hash) (interface{}, error) {
+			calls++
+			meta, err := cm.GetCommitMeta(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(meta.Description, "fail") {
+				return nil, fmt.Errorf("refusing to measure")
+			}
+			return len(meta.Description), nil
+		},
+	}))
+	defer UnregisterLogColumnProvider("message_length")
+	assert.True(t, ErrLogColumnProviderExists.Is(RegisterLogColumnProvider(LogColumnProvider{Name: "Message_Length", Type: sql.Int32, Compute: messageWordCountColumn.Compute})))
+	assert.True(t, ErrLogColumnProviderInvalid.Is(RegisterLogColumnProvider(LogColumnProvider{Name: "", Type: sql.Int32, Compute: messageWordCountColumn.Compute})))
+	assert.True(t, ErrLogColumnProviderInvalid.Is(RegisterLogColumnProvider(LogColumnProvider{Name: "no_compute", Type: sql.Int32})))
+
+	// Providers are only consulted when they're requested, and their columns are read
+	rows := executeLogQuery(t, dEnv, false, "SELECT message FROM dolt_log();")
+	require.Len(t, rows, 3)
+	rows = executeLogQuery(t, dEnv, false, "SELECT message, message_word_count FROM dolt_log('--with', 'message_length', '--with', 'message_word_count') WHERE commit_order = 2;")
+	assert.Equal(t, []sql.Row{{"first commit", int64(2)}}, rows)
+	assert.Zero(t, calls)
+
+	rows = executeLogQuery(t, dEnv, false, "SELECT message_length FROM dolt_log('main~1', '--with', 'message_length');")
+	assert.Equal(t, []sql.Row{{int32(12)}, {int32(len("Initialize data repository"))}}, rows)
+	assert.Equal(t, 2, calls)
+
+	// Errors fail the row, naming the provider and the commit
+	_, err := executeLogQueryErr(t, dEnv, "SELECT * FROM dolt_log('--with', 'message_length');")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dolt_log column provider `message_length` failed on commit ")
+	assert.Contains(t, err.Error(), "refusing to measure")
+
+	// A provider's column may not clash with the columns of the log
+	require.NoError(t, RegisterLogColumnProvider(LogColumnProvider{Name: "committer", Type: sql.Text, Compute: messageWordCountColumn.Compute}))
+	defer UnregisterLogColumnProvider("committer")
+	_, err = executeLogQueryErr(t, dEnv, "SELECT * FROM dolt_log('--with', 'committer');")
+	require.Error(t, err)
+	detail, ok := GetArgumentErrorDetail(err)
+	require.True(t, ok)
+	assert.Equal(t, ArgumentErrorDetail{Index: 0, Flag: cli.WithParam, Code: ArgumentErrorConflictingOptions}, detail)
+}
+
+func TestLogTableFunctionOptionalColumnValues(t *testing.T) {
+	// The initial commit is the root commit, which is not decorated by any ref, and is followed by a commit with an empty
+	// message
+	dEnv := createLogEnvWithCommits(t, []datas.CommitMeta{
+		{Name: "name", Email: "name@fake.horse", Description: ""},
+	})
+	ctx := context.Background()
+	head, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	parentHash, err := head.ParentHashes(ctx)
+	require.NoError(t, err)
+	require.Len(t, parentHash, 1)
+	require.NoError(t, dEnv.DoltDB.NewTagAtCommit(ctx, ref.NewTagRef("v1"), head, &datas.TagMeta{Name: "name", Email: "name@fake.horse"}))
+
+	// Columns are NULL when they don't apply to a commit, and empty when they apply but have an empty value. Every
+	// optional column must have a case for each of the commits it's added for.
+	tests := []struct {
+		name   string
+		query  string
+		value  interface{}
+		legacy interface{}
+	}{
+		{"committer_date_tz without a recorded time zone", "SELECT committer_date_tz FROM dolt_log() WHERE commit_order = 2;", nil, nil},
+		{"subject of an empty message", "SELECT subject FROM dolt_log('--abbrev') WHERE commit_order = 2;", "", ""},
+		{"short_hash", "SELECT short_hash FROM dolt_log('--show-short-hash') WHERE commit_order = 2;", headHash.String()[:7], headHash.String()[:7]},
+		{"parents of the root commit", "SELECT parents FROM dolt_log('--parents') WHERE commit_order = 1;", nil, ""},
+		{"parents of a commit", "SELECT parents FROM dolt_log('--parents') WHERE commit_order = 2;", parentHash[0].String(), parentHash[0].String()},
+		{"parents of the root commit as JSON", "SELECT CAST(parents AS CHAR) FROM dolt_log('--parents', '--format', 'json') WHERE commit_order = 1;", nil, "[]"},
+		{"refs of an undecorated commit", "SELECT refs FROM dolt_log('--decorate', 'short') WHERE commit_order = 1;", nil, ""},
+		{"refs of a decorated commit", "SELECT refs FROM dolt_log('--decorate', 'short') WHERE commit_order = 2;", "HEAD -> main, tag: v1", "HEAD -> main, tag: v1"},
+		{"refs of an undecorated commit as JSON", "SELECT CAST(refs AS CHAR) FROM dolt_log('--decorate', 'full', '--format', 'json') WHERE commit_order = 1;", nil, "[]"},
+		{"sources of the root commit", "SELECT sources FROM dolt_log('--branches') WHERE commit_order = 1;", "main", "main"},
+		{"tables_changed of the root commit", "SELECT tables_changed FROM dolt_log('--stat') WHERE commit_order = 1;", int32(0), int32(0)},
+		{"parent_orders of the root commit", "SELECT CAST(parent_orders AS CHAR) FROM dolt_log('--graph') WHERE commit_order = 1;", nil, "[]"},
+		{"parent_orders of a commit", "SELECT CAST(parent_orders AS CHAR) FROM dolt_log('--graph') WHERE commit_order = 2;", "[1]", "[1]"},
+		{"is_boundary of the root commit", "SELECT is_boundary FROM dolt_log('--boundary') WHERE commit_order = 1;", false, false},
+		{"contained of the root commit", "SELECT contained FROM dolt_log('--contains', 'main') WHERE commit_order = 1;", true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows := executeLogQuery(t, dEnv, false, test.query)
+			assert.Equal(t, []sql.Row{{test.value}}, rows)
+			rows = executeLogQueryWithVars(t, dEnv, map[string]interface{}{dsess.LogLegacyEmptyColumns: int8(1)}, test.query)
+			assert.Equal(t, []sql.Row{{test.legacy}}, rows)
+		})
+	}
+
+	// Columns of options that aren't given are left out of the schema, rather than being NULL
+	rows := executeLogQuery(t, dEnv, false, "SELECT * FROM dolt_log('--decorate', 'no') WHERE commit_order = 1;")
+	require.Len(t, rows, 1)
+	assert.Len(t, rows[0], len(logTableSchema))
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// logTableFilter is the --tables filter, which matches the commits that changed any of the given tables. Tables are
+// content addressed, so a table is unchanged by a commit when its hash is the same as in the commit's first parent, and
+// the rows of the table never need to be read.
+type logTableFilter struct {
+	ddb    *doltdb.DoltDB
+	tables []string
+	// hashes holds the hash of each table in the root of each commit, as every commit is compared against its parent,
+	// which is then compared against its own parent when it's walked
+	hashes map[hash.Hash][]hash.Hash
+}
+
+func newLogTableFilter(ddb *doltdb.DoltDB, tables []string) *logTableFilter {
+	return &logTableFilter{
+		ddb:    ddb,
+		tables: tables,
+		hashes: make(map[hash.Hash][]hash.Hash),
+	}
+}
+
+// parseLogTables returns the table names of a --tables option, which are separated by commas, or false if any name
+// is empty.
+func parseLogTables(tablesStr string) ([]string, bool) {
+	var tables []string
+	for _, table := range strings.Split(tablesStr, ",") {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			return nil, false
+		}
+		tables = append(tables, table)
+	}
+	return tables, true
+}
+
+// tableHashes returns the hash of each of the filter's tables in the root of the given commit, which is empty for the
+// tables that don't exist. Names are matched case-insensitively, as a table may be renamed to a different case.
+func (f *logTableFilter) tableHashes(ctx *sql.Context, cm *doltdb.Commit) ([]hash.Hash, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if hashes, ok := f.hashes[h]; ok {
+		return hashes, nil
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]hash.Hash, len(f.tables))
+	for i, table := range f.tables {
+		name, ok, err := root.ResolveTableName(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		hashes[i], _, err = root.GetTableHash(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.hashes[h] = hashes
+	return hashes, nil
+}
+
+// matches returns whether the given commit changed any of the filter's tables, compared with its first parent. A
+// commit without parents matches when it contains any of the tables.
+func (f *logTableFilter) matches(ctx *sql.Context, cm *doltdb.Commit) (bool, error) {
+	hashes, err := f.tableHashes(ctx, cm)
+	if err != nil {
+		return false, err
+	}
+	parentHashes := make([]hash.Hash, len(f.tables))
+	if cm.NumParents() > 0 {
+		parent, err := f.ddb.ResolveParent(ctx, cm, 0)
+		if err != nil {
+			return false, err
+		}
+		parentHashes, err = f.tableHashes(ctx, parent)
+		if err != nil {
+			return false, err
+		}
+	}
+	for i := range hashes {
+		if hashes[i] != parentHashes[i] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,404 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.TableFunction = (*PatchTableFunction)(nil)
+
+// PatchSkippedDataWarningCode is the code of the warnings emitted by dolt_patch for tables whose data changes can't be
+// expressed as statements.
+const PatchSkippedDataWarningCode int = 1105 // Since this is our own custom warning we'll use 1105, the code for an unknown error
+
+const (
+	patchDiffTypeSchema = "schema"
+	patchDiffTypeData   = "data"
+)
+
+// PatchTableFunction is the dolt_patch table function, which returns the SQL statements that transform the tables of
+// one revision into the tables of another. Statements are grouped by table, in table name order, and the DDL
+// statements for each table come before the statements that change its data.
+type PatchTableFunction struct {
+	ctx *sql.Context
+
+	fromCommitExpr sql.Expression
+	toCommitExpr   sql.Expression
+	tableNameExpr  sql.Expression
+	database       sql.Database
+}
+
+var patchTableSchema = sql.Schema{
+	&sql.Column{Name: "statement_order", Type: sql.Uint64, Nullable: false},
+	&sql.Column{Name: "table_name", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "diff_type", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "statement", Type: sql.LongText, Nullable: false},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ptf *PatchTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &PatchTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ptf *PatchTableFunction) Database() sql.Database {
+	return ptf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ptf *PatchTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	ptf.database = database
+	return ptf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (ptf *PatchTableFunction) FunctionName() string {
+	return "dolt_patch"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ptf *PatchTableFunction) Resolved() bool {
+	if ptf.tableNameExpr != nil {
+		return ptf.fromCommitExpr.Resolved() && ptf.toCommitExpr.Resolved() && ptf.tableNameExpr.Resolved()
+	}
+	return ptf.fromCommitExpr.Resolved() && ptf.toCommitExpr.Resolved()
+}
+
+// String implements the Stringer interface
+func (ptf *PatchTableFunction) String() string {
+	if ptf.tableNameExpr != nil {
+		return fmt.Sprintf("DOLT_PATCH(%s, %s, %s)", ptf.fromCommitExpr.String(), ptf.toCommitExpr.String(), ptf.tableNameExpr.String())
+	}
+	return fmt.Sprintf("DOLT_PATCH(%s, %s)", ptf.fromCommitExpr.String(), ptf.toCommitExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (ptf *PatchTableFunction) Schema() sql.Schema {
+	return patchTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ptf *PatchTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ptf *PatchTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ptf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ptf *PatchTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	if ptf.tableNameExpr != nil {
+		_, _, tableName, err := ptf.evaluateArguments()
+		if err != nil {
+			return false
+		}
+		return opChecker.UserHasPrivileges(ctx,
+			sql.NewPrivilegedOperation(ptf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+	}
+
+	tblNames, err := ptf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(ptf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ptf *PatchTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{ptf.fromCommitExpr, ptf.toCommitExpr}
+	if ptf.tableNameExpr != nil {
+		exprs = append(exprs, ptf.tableNameExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ptf *PatchTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 2 || len(expression) > 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ptf.FunctionName(), "2 or 3", len(expression))
+	}
+
+	for _, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(ptf.FunctionName(), expr.String())
+		}
+		if !sql.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(ptf.FunctionName(), expr.String())
+		}
+	}
+
+	ptf.fromCommitExpr = expression[0]
+	ptf.toCommitExpr = expression[1]
+	ptf.tableNameExpr = nil
+	if len(expression) == 3 {
+		ptf.tableNameExpr = expression[2]
+	}
+
+	return ptf, nil
+}
+
+// evaluateArguments returns the from revision, the to revision and the table name, which is empty when no table was
+// given.
+func (ptf *PatchTableFunction) evaluateArguments() (string, string, string, error) {
+	var tableName string
+	if ptf.tableNameExpr != nil {
+		tableNameVal, err := ptf.tableNameExpr.Eval(ptf.ctx, nil)
+		if err != nil {
+			return "", "", "", err
+		}
+		tn, ok := tableNameVal.(string)
+		if !ok {
+			return "", "", "", ErrInvalidTableName.New(ptf.tableNameExpr.String())
+		}
+		tableName = tn
+	}
+
+	fromCommitVal, err := ptf.fromCommitExpr.Eval(ptf.ctx, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	fromCommitValStr, ok := fromCommitVal.(string)
+	if !ok {
+		return "", "", "", fmt.Errorf("received '%v' when expecting commit hash string", fromCommitVal)
+	}
+
+	toCommitVal, err := ptf.toCommitExpr.Eval(ptf.ctx, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	toCommitValStr, ok := toCommitVal.(string)
+	if !ok {
+		return "", "", "", fmt.Errorf("received '%v' when expecting commit hash string", toCommitVal)
+	}
+
+	return fromCommitValStr, toCommitValStr, tableName, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ptf *PatchTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	fromCommitVal, toCommitVal, tableName, err := ptf.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := ptf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ptf.database)
+	}
+
+	fromRoot, _, fromDate, err := loadDetailsForRef(ctx, fromCommitVal, sqledb)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, _, toDate, err := loadDetailsForRef(ctx, toCommitVal, sqledb)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if ptf.tableNameExpr != nil {
+		_, fromTableExists, err := fromRoot.ResolveTableName(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		_, toTableExists, err := toRoot.ResolveTableName(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if !fromTableExists && !toTableExists {
+			return nil, sql.ErrTableNotFound.New(tableName)
+		}
+
+		delta := findMatchingDelta(deltas, tableName)
+		deltas = nil
+		// no delta means the table wasn't changed, and so there are no statements for it
+		if delta.FromTable != nil || delta.ToTable != nil {
+			deltas = []diff.TableDelta{delta}
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].CurName() < deltas[j].CurName()
+	})
+
+	toSchemas, err := toRoot.GetAllSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pg := &patchGenerator{
+		ddb:      sqledb.GetDoltDB(),
+		fromName: fromCommitVal,
+		toName:   toCommitVal,
+		fromDate: fromDate,
+		toDate:   toDate,
+	}
+	for _, delta := range deltas {
+		err = pg.addTableStatements(ctx, delta, toSchemas)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.RowsToRowIter(pg.rows...), nil
+}
+
+// patchGenerator accumulates the rows of the dolt_patch table function, numbering the statements as they are added.
+type patchGenerator struct {
+	ddb              *doltdb.DoltDB
+	fromName, toName string
+	fromDate, toDate *types.Timestamp
+
+	rows []sql.Row
+}
+
+func (pg *patchGenerator) add(tableName, diffType, stmt string) {
+	pg.rows = append(pg.rows, sql.Row{uint64(len(pg.rows) + 1), tableName, diffType, stmt})
+}
+
+// addTableStatements adds the DDL statements for the table delta given, followed by its data statements. Data changes
+// are skipped, with a warning, when the primary key or the columns of the table changed, since the rows of the two
+// revisions can't be matched up by statements against the new schema.
+func (pg *patchGenerator) addTableStatements(ctx *sql.Context, td diff.TableDelta, toSchemas map[string]schema.Schema) error {
+	tableName := td.CurName()
+
+	ddlStatements, err := SqlSchemaDiff(ctx, td, toSchemas)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range ddlStatements {
+		pg.add(tableName, patchDiffTypeSchema, stmt)
+	}
+
+	if td.IsDrop() {
+		return nil
+	}
+
+	if !schema.ArePrimaryKeySetsDiffable(td.Format(), td.FromSch, td.ToSch) {
+		ctx.Warn(PatchSkippedDataWarningCode, "Primary key sets differ between revisions for table %s, skipping data diff", tableName)
+		return nil
+	}
+	if td.FromSch != nil && !schema.SchemasAreEqual(td.FromSch, td.ToSch) {
+		ctx.Warn(PatchSkippedDataWarningCode, "Incompatible schema change for table %s, skipping data diff", tableName)
+		return nil
+	}
+
+	return pg.addDataStatements(ctx, td)
+}
+
+// addDataStatements adds the INSERT, UPDATE and DELETE statements for the rows that changed in the table delta given,
+// whose from and to schemas must be equal when the table exists in both revisions.
+func (pg *patchGenerator) addDataStatements(ctx *sql.Context, td diff.TableDelta) error {
+	tableName := td.ToName
+
+	_, joiner, err := dtables.GetDiffTableSchemaAndJoiner(td.ToTable.Format(), td.FromSch, td.ToSch)
+	if err != nil {
+		return err
+	}
+	sqlSch, err := sqlutil.FromDoltSchema(tableName, td.ToSch)
+	if err != nil {
+		return err
+	}
+	keyless := schema.IsKeyless(td.ToSch)
+
+	dp := dtables.NewDiffPartition(td.ToTable, td.FromTable, pg.toName, pg.fromName, pg.toDate, pg.fromDate, td.ToSch, td.FromSch)
+	iter := NewDiffTableFunctionRowIterForSinglePartition(*dp, pg.ddb, joiner)
+	defer iter.Close(ctx)
+
+	// the rows of the diff hold the to columns, to_commit, to_commit_date, the from columns, from_commit,
+	// from_commit_date and diff_type
+	n := len(sqlSch.Schema)
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		toRow, fromRow := row[:n], row[n+2:n+2+n]
+		var stmt string
+		switch row[len(row)-1] {
+		case "added":
+			stmt, err = sqlfmt.SqlRowAsInsertStmt(toRow, tableName, td.ToSch)
+		case "removed":
+			// rows of keyless tables may be duplicated, so only one of them is deleted at a time
+			var limit uint64
+			if keyless {
+				limit = 1
+			}
+			stmt, err = sqlfmt.SqlRowAsDeleteStmt(fromRow, tableName, td.ToSch, limit)
+		case "modified":
+			updatedCols := set.NewEmptyStrSet()
+			for i, col := range sqlSch.Schema {
+				cmp, err := col.Type.Compare(toRow[i], fromRow[i])
+				if err != nil {
+					return err
+				}
+				if cmp != 0 {
+					updatedCols.Add(col.Name)
+				}
+			}
+			stmt, err = sqlfmt.SqlRowAsUpdateStmt(toRow, tableName, td.ToSch, updatedCols)
+		default:
+			return fmt.Errorf("unexpected diff type: %v", row[len(row)-1])
+		}
+		if err != nil {
+			return err
+		}
+
+		pg.add(tableName, patchDiffTypeData, stmt)
+	}
+}
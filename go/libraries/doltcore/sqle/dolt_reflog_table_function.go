@@ -0,0 +1,171 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+var _ sql.TableFunction = (*ReflogTableFunction)(nil)
+
+var reflogTableFunctionSchema = sql.Schema{
+	&sql.Column{Name: "ref", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "ref_timestamp", Type: sql.Timestamp, Nullable: false},
+	&sql.Column{Name: "commit_hash", Type: sql.Text, Nullable: true},
+	&sql.Column{Name: "commit_message", Type: sql.Text, Nullable: true},
+}
+
+// ReflogTableFunction is the dolt_reflog table function, which returns the recorded moves of a ref, most recent first,
+// so that commits that are no longer referenced can be found. Without an argument, it returns the moves of every ref,
+// in the same way as the dolt_reflog system table.
+type ReflogTableFunction struct {
+	ctx *sql.Context
+
+	refExpr sql.Expression
+
+	database sql.Database
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (rtf *ReflogTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &ReflogTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (rtf *ReflogTableFunction) Database() sql.Database {
+	return rtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (rtf *ReflogTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	rtf.database = database
+	return rtf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (rtf *ReflogTableFunction) FunctionName() string {
+	return "dolt_reflog"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (rtf *ReflogTableFunction) Resolved() bool {
+	if rtf.refExpr != nil {
+		return rtf.refExpr.Resolved()
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (rtf *ReflogTableFunction) String() string {
+	if rtf.refExpr != nil {
+		return fmt.Sprintf("DOLT_REFLOG(%s)", rtf.refExpr.String())
+	}
+	return "DOLT_REFLOG()"
+}
+
+// Schema implements the sql.Node interface.
+func (rtf *ReflogTableFunction) Schema() sql.Schema {
+	return reflogTableFunctionSchema
+}
+
+// Children implements the sql.Node interface.
+func (rtf *ReflogTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (rtf *ReflogTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return rtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (rtf *ReflogTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := rtf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(rtf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (rtf *ReflogTableFunction) Expressions() []sql.Expression {
+	if rtf.refExpr != nil {
+		return []sql.Expression{rtf.refExpr}
+	}
+	return nil
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (rtf *ReflogTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) > 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(rtf.FunctionName(), "0 or 1", len(expressions))
+	}
+
+	rtf.refExpr = nil
+	if len(expressions) == 1 {
+		// The ref is only evaluated in RowIter, so it may be any text expression
+		if expressions[0].Resolved() && !sql.IsText(expressions[0].Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(rtf.FunctionName(), expressions[0].String())
+		}
+		rtf.refExpr = expressions[0]
+	}
+
+	return rtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (rtf *ReflogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := rtf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", rtf.database)
+	}
+
+	var name string
+	if rtf.refExpr != nil {
+		val, err := rtf.refExpr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		name, ok = val.(string)
+		if !ok || name == "" {
+			return nil, sql.ErrInvalidArgumentDetails.New(rtf.FunctionName(), rtf.refExpr.String())
+		}
+	}
+
+	return dtables.NewReflogItr(ctx, sqledb.ddb, name)
+}
@@ -0,0 +1,226 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var _ sql.TableFunction = (*TagsTableFunction)(nil)
+
+// TagsTableFunction is the dolt_tags table function, which returns the metadata of each tag. An optional pattern
+// filters the tag names using LIKE semantics.
+type TagsTableFunction struct {
+	ctx *sql.Context
+
+	patternExpr sql.Expression
+
+	database sql.Database
+}
+
+var tagsTableFunctionSchema = sql.Schema{
+	&sql.Column{Name: "tag_name", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "commit_hash", Type: sql.Text, Nullable: true},
+	&sql.Column{Name: "tagger_name", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "tagger_email", Type: sql.Text, Nullable: false},
+	&sql.Column{Name: "tag_date", Type: sql.Datetime, Nullable: false},
+	&sql.Column{Name: "message", Type: sql.Text, Nullable: false},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ttf *TagsTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &TagsTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ttf *TagsTableFunction) Database() sql.Database {
+	return ttf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ttf *TagsTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	ttf.database = database
+	return ttf, nil
+}
+
+// FunctionName implements the sql.TableFunction interface
+func (ttf *TagsTableFunction) FunctionName() string {
+	return "dolt_tags"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ttf *TagsTableFunction) Resolved() bool {
+	if ttf.patternExpr != nil {
+		return ttf.patternExpr.Resolved()
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (ttf *TagsTableFunction) String() string {
+	if ttf.patternExpr != nil {
+		return fmt.Sprintf("DOLT_TAGS(%s)", ttf.patternExpr.String())
+	}
+	return "DOLT_TAGS()"
+}
+
+// Schema implements the sql.Node interface.
+func (ttf *TagsTableFunction) Schema() sql.Schema {
+	return tagsTableFunctionSchema
+}
+
+// Children implements the sql.Node interface.
+func (ttf *TagsTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ttf *TagsTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ttf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ttf *TagsTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := ttf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(ttf.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ttf *TagsTableFunction) Expressions() []sql.Expression {
+	if ttf.patternExpr != nil {
+		return []sql.Expression{ttf.patternExpr}
+	}
+	return nil
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ttf *TagsTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) > 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ttf.FunctionName(), "0 or 1", len(expressions))
+	}
+
+	ttf.patternExpr = nil
+	if len(expressions) == 1 {
+		// The pattern is only evaluated in RowIter, so it may be any text expression
+		if expressions[0].Resolved() && !sql.IsText(expressions[0].Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(ttf.FunctionName(), expressions[0].String())
+		}
+		ttf.patternExpr = expressions[0]
+	}
+
+	return ttf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ttf *TagsTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqledb, ok := ttf.database.(Database)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ttf.database)
+	}
+
+	var like sql.Expression
+	if ttf.patternExpr != nil {
+		pattern, err := ttf.patternExpr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if pattern == nil {
+			return nil, sql.ErrInvalidArgumentDetails.New(ttf.FunctionName(), ttf.patternExpr.String())
+		}
+		tagName := expression.NewGetField(0, sql.Text, "tag_name", false)
+		like = expression.NewLike(tagName, expression.NewLiteral(pattern, ttf.patternExpr.Type()), nil)
+	}
+
+	tags, err := sqledb.ddb.GetTagMetasWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tagsTableFunctionRowIter{tags: tags, like: like}, nil
+}
+
+//------------------------------------
+// tagsTableFunctionRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*tagsTableFunctionRowIter)(nil)
+
+// tagsTableFunctionRowIter is a sql.RowIter implementation which iterates over each tag, skipping those whose names do
+// not match the pattern.
+type tagsTableFunctionRowIter struct {
+	tags []doltdb.TagMetaWithHash
+	like sql.Expression
+	idx  int
+}
+
+// Next implements the sql.RowIter interface
+func (itr *tagsTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for itr.idx < len(itr.tags) {
+		tag := itr.tags[itr.idx]
+		itr.idx++
+
+		// The commit of a tag may be missing, such as in a shallow clone, in which case it has no commit hash
+		var commitHash interface{}
+		if tag.CommitFound {
+			commitHash = tag.Hash.String()
+		}
+		row := sql.NewRow(tag.Name, commitHash, tag.Meta.Name, tag.Meta.Email, tag.Meta.Time(), tag.Meta.Description)
+
+		if itr.like != nil {
+			matches, err := itr.like.Eval(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if matches != true {
+				continue
+			}
+		}
+		return row, nil
+	}
+	return nil, io.EOF
+}
+
+// Close implements the sql.RowIter interface
+func (itr *tagsTableFunctionRowIter) Close(_ *sql.Context) error {
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/cron"
+)
+
+// doltBackupSchedule sets or removes the schedule that sql-server syncs a backup on, e.g.
+// CALL dolt_backup_schedule('nightly', '0 2 * * *') or CALL dolt_backup_schedule('-d', 'nightly'). Schedules are
+// shown in the dolt_backups system table.
+func doltBackupSchedule(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltBackupSchedule(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltBackupSchedule(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateBackupScheduleArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+
+	var backupName, schedule string
+	if apr.Contains(cli.DeleteFlag) {
+		if apr.NArg() != 1 {
+			return 1, fmt.Errorf("usage: dolt_backup_schedule('-d', BACKUP_NAME)")
+		}
+		backupName = strings.TrimSpace(apr.Arg(0))
+	} else {
+		if apr.NArg() != 2 {
+			return 1, fmt.Errorf("usage: dolt_backup_schedule(BACKUP_NAME, SCHEDULE)")
+		}
+		backupName, schedule = strings.TrimSpace(apr.Arg(0)), strings.TrimSpace(apr.Arg(1))
+		if _, err = cron.Parse(schedule); err != nil {
+			return 1, err
+		}
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	fs, err := sess.Provider().FileSystemForDatabase(dbName)
+	if err != nil {
+		return 1, err
+	}
+
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return 1, err
+	}
+	if _, ok := repoState.Backups[backupName]; !ok {
+		return 1, fmt.Errorf("error: unknown backup: '%s'", backupName)
+	}
+
+	repoState.SetBackupSchedule(backupName, schedule)
+	err = repoState.Save(fs)
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltBranchControlExport returns a JSON document containing every entry of the branch control tables.
+func doltBranchControlExport(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_EXPORT", 0, len(args))
+	}
+	data, err := branch_control.StaticController.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(string(data)), nil
+}
+
+// doltBranchControlImport loads a JSON document, as returned by doltBranchControlExport, into the branch control
+// tables. The mode must be either "replace" or "merge".
+func doltBranchControlImport(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_IMPORT", 2, len(args))
+	}
+	mode := branch_control.ImportMode(strings.ToLower(args[1]))
+	if err := branch_control.StaticController.Import(ctx, []byte(args[0]), mode); err != nil {
+		return nil, err
+	}
+	if err := branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltBranchControlPrune removes every entry of the branch control tables whose branch expression matches the given
+// pattern, returning the number of removed entries from each table.
+func doltBranchControlPrune(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_PRUNE", 1, len(args))
+	}
+	accessCount, namespaceCount, err := branch_control.StaticController.Prune(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(accessCount), int64(namespaceCount)), nil
+}
+
+// doltBranchControlDedup collapses the entries of the branch control tables that are identical except for their
+// permissions, returning the number of removed entries from each table.
+func doltBranchControlDedup(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_DEDUP", 0, len(args))
+	}
+	accessCount, namespaceCount, err := branch_control.StaticController.Dedup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(accessCount), int64(namespaceCount)), nil
+}
+
+// doltBranchControlCompact rewrites the branch control file as a single snapshot of the branch control tables, rather
+// than waiting for the journal of changes to grow large enough to be compacted.
+func doltBranchControlCompact(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_COMPACT", 0, len(args))
+	}
+	if err := branch_control.CompactData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+func doltBranchControlReload(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_RELOAD", 0, len(args))
+	}
+	if err := branch_control.ReloadBase(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+func doltBranchControlStatus(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_STATUS", 0, len(args))
+	}
+	sources, err := branch_control.StaticController.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The escalation constraints only govern edits, which are always made to the overlay
+	constraints := strings.Join(branch_control.EscalationConstraints(), ",")
+	rows := make([]sql.Row, len(sources))
+	for i, source := range sources {
+		rows[i] = sql.Row{source.Name, source.Location, int64(source.AccessRows), int64(source.NamespaceRows), int64(source.DuplicateRows), ""}
+		if source.Name == "overlay" {
+			rows[i][5] = constraints
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltBranchControlDefaultBranches returns the default branch of every database, along with the default branch that it
+// replaced and the user that most recently changed it. The last three columns are empty for databases whose default
+// branch has not been changed while branch control was enabled.
+func doltBranchControlDefaultBranches(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_CONTROL_DEFAULT_BRANCHES", 0, len(args))
+	}
+	var dbNames []string
+	for _, db := range dsess.DSessFromSess(ctx.Session).Provider().AllDatabases(ctx) {
+		// Only databases with a default branch variable have a default branch, which excludes revision databases
+		if _, _, ok := sql.SystemVariables.GetGlobal(dsess.DefaultBranchKey(db.Name())); ok {
+			dbNames = append(dbNames, db.Name())
+		}
+	}
+	sort.Strings(dbNames)
+
+	rows := make([]sql.Row, len(dbNames))
+	for i, dbName := range dbNames {
+		change, ok, err := branch_control.StaticController.LatestDefaultBranchChange(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = sql.Row{dbName, dsess.DefaultBranch(ctx, dbName), "", "", ""}
+		if ok {
+			rows[i][2] = change.OldBranch
+			rows[i][3] = fmt.Sprintf("%s@%s", change.User, change.Host)
+			rows[i][4] = change.ChangedAt.Format(sql.TimestampDatetimeLayout)
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltBranchFreeze freezes every branch matching a branch expression, so that only the super user may modify them,
+// with an optional reason that is displayed by dolt_branch_freeze and in the errors of denied operations.
+func doltBranchFreeze(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_FREEZE", "1 or 2", len(args))
+	}
+	reason := ""
+	if len(args) == 2 {
+		reason = args[1]
+	}
+	if err := branch_control.FreezeBranch(ctx, args[0], reason); err != nil {
+		return nil, err
+	}
+	if err := branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltBranchUnfreeze removes the freeze of a branch expression.
+func doltBranchUnfreeze(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH_UNFREEZE", 1, len(args))
+	}
+	if err := branch_control.UnfreezeBranch(ctx, args[0]); err != nil {
+		return nil, err
+	}
+	if err := branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltCherryPick applies the changes introduced by the commits given to the current branch, committing each with its
+// original message and author. Commits may be given as ranges of the form A..B, which include the commits reachable from B that
+// aren't reachable from A. With --no-commit, the changes of all the commits are staged instead of committed.
+//
+// A cherry-pick that results in conflicts or constraint violations stops at the commit that caused them, leaving them
+// in the working set to be resolved with the dolt_conflicts and dolt_constraint_violations tables and committed, so
+// that the commits after it must be cherry-picked again afterwards. The cherry-pick is recorded in the working set's
+// merge state until then, and may be aborted with --abort instead. Returns the hash of the last commit created, and
+// whether the cherry-pick stopped on conflicts.
+func doltCherryPick(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	h, conflicts, err := doDoltCherryPick(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(h, int64(conflicts)), nil
+}
+
+func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return "", 0, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateCherryPickArgParser().Parse(args)
+	if err != nil {
+		return "", 0, err
+	}
+	if apr.Contains(cli.AbortParam) {
+		return "", 0, abortCherryPick(ctx, dbName)
+	}
+	if apr.NArg() == 0 {
+		return "", 0, fmt.Errorf("error: no commits given to cherry-pick")
+	}
+	if err = branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_Merge); err != nil {
+		return "", 0, err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return "", 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+	if err = checkCherryPickWorkingSet(ctx, dSess, dbName); err != nil {
+		return "", 0, err
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", 0, err
+	}
+	commits, err := resolveCherryPickCommits(ctx, ddb, headRef, apr.Args)
+	if err != nil {
+		return "", 0, err
+	}
+
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return "", 0, err
+	} else if !ok {
+		return "", 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	noCommit := apr.Contains(cli.NoCommitFlag)
+	lastHash := ""
+	for _, cm := range commits {
+		// committing ends the session's transaction, so another is started for each commit after the first
+		if ctx.GetTransaction() == nil {
+			tx, err := dSess.StartTransaction(ctx, dbName, sql.ReadWrite)
+			if err != nil {
+				return "", 0, err
+			}
+			ctx.SetTransaction(tx)
+		}
+
+		roots, ok := dSess.GetRoots(ctx, dbName)
+		if !ok {
+			return "", 0, fmt.Errorf("Could not load database %s", dbName)
+		}
+
+		root, _, err := merge.CherryPick(ctx, ddb, roots.Working, cm, dbState.EditOpts())
+		if err != nil {
+			return "", 0, err
+		}
+
+		hasConflicts, err := root.HasConflicts(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		hasViolations, err := root.HasConstraintViolations(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		if hasConflicts || hasViolations {
+			h, err := cm.HashOf()
+			if err != nil {
+				return "", 0, err
+			}
+			// the staged root is left as it was, so that only the changes of cherry-picked commits without
+			// conflicts are staged
+			ws, err := dSess.WorkingSet(ctx, dbName)
+			if err != nil {
+				return "", 0, err
+			}
+			if err = dSess.SetWorkingSet(ctx, dbName, ws.StartCherryPick(cm, h.String()).WithWorkingRoot(root)); err != nil {
+				return "", 0, err
+			}
+			ctx.Warn(dfunctions.DoltMergeWarningCode, "cherry-pick of commit %s stopped on conflicts or constraint violations; "+
+				"resolve them and commit, then cherry-pick any remaining commits", h.String())
+			return lastHash, 1, nil
+		}
+
+		roots.Working, roots.Staged = root, root
+		if err = dSess.SetRoots(ctx, dbName, roots); err != nil {
+			return "", 0, err
+		}
+		if noCommit {
+			continue
+		}
+
+		// a commit whose changes are already on the branch is skipped
+		headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+		if err != nil {
+			return "", 0, err
+		}
+		if changed, err := rootChangedFromCommit(ctx, root, headCommit); err != nil {
+			return "", 0, err
+		} else if !changed {
+			continue
+		}
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		author, ok := apr.GetValue(cli.AuthorParam)
+		if !ok {
+			author = fmt.Sprintf("%s <%s>", meta.Name, meta.Email)
+		}
+		commitArgs := []string{"-m", meta.Description, "--author", author}
+		lastHash, err = dfunctions.DoDoltCommit(ctx, commitArgs)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	return lastHash, 0, nil
+}
+
+// abortCherryPick aborts the cherry-pick that stopped on conflicts in the session's working set, restoring the working
+// set to its state before the cherry-pick of the commit that caused them.
+func abortCherryPick(ctx *sql.Context, dbName string) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if !ws.MergeActive() || !ws.MergeState().IsCherryPick() {
+		return fmt.Errorf("fatal: There is no cherry-pick to abort")
+	}
+	if err = dSess.SetWorkingSet(ctx, dbName, ws.AbortMerge()); err != nil {
+		return err
+	}
+	return dSess.CommitWorkingSet(ctx, dbName, dSess.GetTransaction())
+}
+
+// checkCherryPickWorkingSet returns an error if the session's working set has uncommitted changes or is in the middle
+// of a merge, as the changes of the cherry-picked commits would be mixed with them.
+func checkCherryPickWorkingSet(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) error {
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if ws.MergeActive() && ws.MergeState().IsCherryPick() {
+		return fmt.Errorf("error: a cherry-pick is already in progress.\n" +
+			"hint: resolve its conflicts and commit them, or abort it with dolt_cherry_pick('--abort').")
+	} else if ws.MergeActive() {
+		return doltdb.ErrMergeActive
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if changed, err := rootChangedFromCommit(ctx, ws.StagedRoot(), headCommit); err != nil {
+		return err
+	} else if changed {
+		return fmt.Errorf("Please commit your staged changes before using cherry-pick.")
+	}
+	if changed, err := rootChangedFromCommit(ctx, ws.WorkingRoot(), headCommit); err != nil {
+		return err
+	} else if changed {
+		return fmt.Errorf("error: your local changes would be overwritten by cherry-pick.\n" +
+			"hint: commit your changes (dolt_commit('-am', '<message>')) or reset them (dolt_reset('--hard')) to proceed.")
+	}
+	return nil
+}
+
+// rootChangedFromCommit returns whether the root given differs from the root of the commit given.
+func rootChangedFromCommit(ctx *sql.Context, root *doltdb.RootValue, cm *doltdb.Commit) (bool, error) {
+	cmRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return false, err
+	}
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return false, err
+	}
+	cmHash, err := cmRoot.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return !rootHash.Equal(cmHash), nil
+}
+
+// resolveCherryPickCommits resolves the commits named by the arguments given in the order they are cherry-picked in.
+// Ranges of the form A..B are expanded to the commits reachable from B that aren't reachable from A, oldest first.
+// Every commit is checked before any is cherry-picked, so that a merge commit in a range fails the whole cherry-pick.
+func resolveCherryPickCommits(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, args []string) ([]*doltdb.Commit, error) {
+	var commits []*doltdb.Commit
+	for _, arg := range args {
+		if len(arg) == 0 {
+			return nil, fmt.Errorf("error: cannot cherry-pick empty string")
+		}
+
+		if strings.Contains(arg, "...") {
+			return nil, fmt.Errorf("error: symmetric ranges are not supported by cherry-pick: %s", arg)
+		}
+		if excludedStr, includedStr, ok := strings.Cut(arg, ".."); ok {
+			excluded, err := resolveCherryPickCommit(ctx, ddb, headRef, excludedStr)
+			if err != nil {
+				return nil, err
+			}
+			included, err := resolveCherryPickCommit(ctx, ddb, headRef, includedStr)
+			if err != nil {
+				return nil, err
+			}
+			excludedHash, err := excluded.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			includedHash, err := included.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			rng, err := commitwalk.GetDotDotRevisions(ctx, ddb, includedHash, ddb, excludedHash, -1)
+			if err != nil {
+				return nil, err
+			}
+			for i := len(rng) - 1; i >= 0; i-- {
+				commits = append(commits, rng[i])
+			}
+			continue
+		}
+
+		cm, err := resolveCherryPickCommit(ctx, ddb, headRef, arg)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, cm)
+	}
+
+	for _, cm := range commits {
+		if len(cm.DatasParents()) > 1 {
+			return nil, merge.ErrCherryPickMergeCommit
+		}
+		if len(cm.DatasParents()) == 0 {
+			return nil, merge.ErrCherryPickNoParents
+		}
+	}
+	return commits, nil
+}
+
+func resolveCherryPickCommit(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, spec string) (*doltdb.Commit, error) {
+	if len(spec) == 0 {
+		spec = "HEAD"
+	}
+	cs, err := doltdb.NewCommitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return dsess.ResolveReadableCommit(ctx, ddb, cs, headRef)
+}
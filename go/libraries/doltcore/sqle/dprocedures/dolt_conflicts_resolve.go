@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/conflict"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
@@ -74,7 +75,9 @@ func getProllyRowMaps(ctx *sql.Context, vrw types.ValueReadWriter, ns tree.NodeS
 	return durable.ProllyMapFromIndex(idx), nil
 }
 
-func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string, sch schema.Schema) (*doltdb.Table, error) {
+// resolveProllyConflicts resolves the conflicts of the table given by taking their rows, or with the union strategy,
+// by taking their rows only where ours were deleted.
+func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string, sch schema.Schema, strategy string) (*doltdb.Table, error) {
 	var err error
 	artifactIdx, err := tbl.GetArtifacts(ctx)
 	if err != nil {
@@ -141,6 +144,9 @@ func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string,
 		if err != nil {
 			return nil, err
 		}
+		if strategy == cli.UnionStrategy && len(ourRow) > 0 {
+			continue
+		}
 
 		// update row data
 		if len(theirRow) == 0 {
@@ -197,7 +203,7 @@ func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string,
 	return newTbl, nil
 }
 
-func resolvePkConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema, conflicts types.Map) (*doltdb.Table, error) {
+func resolvePkConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema, conflicts types.Map, strategy string) (*doltdb.Table, error) {
 	// Create table editor
 	tblEditor, err := editor.NewTableEditor(ctx, tbl, sch, tblName, opts)
 	if err != nil {
@@ -210,6 +216,9 @@ func resolvePkConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table
 		if err != nil {
 			return true, err
 		}
+		if strategy == cli.UnionStrategy && !types.IsNull(cnf.Value) {
+			return false, nil
+		}
 
 		// row was removed
 		if types.IsNull(cnf.MergeValue) {
@@ -261,7 +270,7 @@ func resolvePkConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table
 	return tblEditor.Table(ctx)
 }
 
-func resolveKeylessConflicts(ctx *sql.Context, tbl *doltdb.Table, conflicts types.Map) (*doltdb.Table, error) {
+func resolveKeylessConflicts(ctx *sql.Context, tbl *doltdb.Table, conflicts types.Map, strategy string) (*doltdb.Table, error) {
 	rowData, err := tbl.GetNomsRowData(ctx)
 	if err != nil {
 		return nil, err
@@ -273,6 +282,9 @@ func resolveKeylessConflicts(ctx *sql.Context, tbl *doltdb.Table, conflicts type
 		if err != nil {
 			return true, err
 		}
+		if strategy == cli.UnionStrategy && !types.IsNull(cnf.Value) {
+			return false, nil
+		}
 
 		if types.IsNull(cnf.MergeValue) {
 			mapEditor.Remove(key)
@@ -294,7 +306,7 @@ func resolveKeylessConflicts(ctx *sql.Context, tbl *doltdb.Table, conflicts type
 	return tbl.UpdateNomsRows(ctx, rowData)
 }
 
-func resolveNomsConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema) (*doltdb.Table, error) {
+func resolveNomsConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema, strategy string) (*doltdb.Table, error) {
 	// Get conflicts
 	_, confIdx, err := tbl.GetConflicts(ctx)
 	if err != nil {
@@ -303,10 +315,10 @@ func resolveNomsConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Tab
 	conflicts := durable.NomsMapFromConflictIndex(confIdx)
 
 	if schema.IsKeyless(sch) {
-		return resolveKeylessConflicts(ctx, tbl, conflicts)
+		return resolveKeylessConflicts(ctx, tbl, conflicts, strategy)
 	}
 
-	return resolvePkConflicts(ctx, opts, tbl, tblName, sch, conflicts)
+	return resolvePkConflicts(ctx, opts, tbl, tblName, sch, conflicts, strategy)
 }
 
 func validateConstraintViolations(ctx *sql.Context, before, after *doltdb.RootValue, table string) error {
@@ -339,20 +351,23 @@ func clearTableAndUpdateRoot(ctx *sql.Context, root *doltdb.RootValue, tbl *dolt
 	return newRoot, nil
 }
 
-func ResolveConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *doltdb.RootValue, dbName string, ours bool, tblNames []string) error {
+// ResolveConflicts resolves the data conflicts of the tables given with the merge strategy given. The ours strategy
+// keeps our rows, and the theirs strategy takes their rows. The union strategy keeps the rows of either side, so their
+// rows are only taken where ours were deleted, and our rows are kept where both sides changed them.
+func ResolveConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *doltdb.RootValue, dbName string, strategy string, tblNames []string) error {
 	for _, tblName := range tblNames {
 		tbl, ok, err := root.GetTable(ctx, tblName)
 		if err != nil {
 			return err
 		}
 		if !ok {
-			return doltdb.ErrTableNotFound
+			return fmt.Errorf("%w: %s", doltdb.ErrTableNotFound, tblName)
 		}
 
 		if has, err := tbl.HasConflicts(ctx); err != nil {
 			return err
 		} else if !has {
-			return nil
+			continue
 		}
 
 		sch, err := tbl.GetSchema(ctx)
@@ -364,22 +379,22 @@ func ResolveConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *doltdb.R
 			return err
 		}
 
-		if ours && !schema.ColCollsAreEqual(sch.GetAllCols(), ourSch.GetAllCols()) {
+		if strategy != cli.TheirsStrategy && !schema.ColCollsAreEqual(sch.GetAllCols(), ourSch.GetAllCols()) {
 			return ErrConfSchIncompatible
-		} else if !ours && !schema.ColCollsAreEqual(sch.GetAllCols(), theirSch.GetAllCols()) {
+		} else if strategy != cli.OursStrategy && !schema.ColCollsAreEqual(sch.GetAllCols(), theirSch.GetAllCols()) {
 			return ErrConfSchIncompatible
 		}
 
-		if !ours {
+		if strategy != cli.OursStrategy {
 			if tbl.Format() == types.Format_DOLT {
-				tbl, err = resolveProllyConflicts(ctx, tbl, tblName, sch)
+				tbl, err = resolveProllyConflicts(ctx, tbl, tblName, sch, strategy)
 			} else {
 				state, _, err := dSess.LookupDbState(ctx, dbName)
 				if err != nil {
 					return err
 				}
 				opts := state.WriteSession.GetOptions()
-				tbl, err = resolveNomsConflicts(ctx, opts, tbl, tblName, sch)
+				tbl, err = resolveNomsConflicts(ctx, opts, tbl, tblName, sch, strategy)
 			}
 			if err != nil {
 				return err
@@ -401,9 +416,67 @@ func ResolveConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *doltdb.R
 	return dSess.SetRoot(ctx, dbName, root)
 }
 
+// resolveSchemaConflicts resolves the schema conflicts of any of the given tables whose schemas could not be merged.
+// The merge left these tables as they were in our root, so resolving with ours keeps them, while resolving with
+// theirs takes their tables from the merge commit, schema and data alike. Returns the updated working set and the rest
+// of the given tables, which may have data conflicts to resolve.
+func resolveSchemaConflicts(ctx *sql.Context, ws *doltdb.WorkingSet, ours bool, tblNames []string) (*doltdb.WorkingSet, []string, error) {
+	if !ws.MergeActive() || !ws.MergeState().HasSchemaConflicts() {
+		return ws, tblNames, nil
+	}
+
+	var theirRoot *doltdb.RootValue
+	if !ours {
+		var err error
+		theirRoot, err = ws.MergeState().Commit().GetRootValue(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	unmergable := set.NewStrSet(ws.MergeState().UnmergableTables())
+	root := ws.WorkingRoot()
+	var remaining []string
+	for _, tblName := range tblNames {
+		if !unmergable.Contains(tblName) {
+			remaining = append(remaining, tblName)
+			continue
+		}
+		if theirRoot == nil {
+			continue
+		}
+
+		theirTbl, ok, err := theirRoot.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			root, err = root.PutTable(ctx, tblName, theirTbl)
+		} else {
+			root, err = root.RemoveTables(ctx, false, false, tblName)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	resolved := set.NewStrSet(tblNames)
+	var unresolved []string
+	for _, tblName := range ws.MergeState().UnmergableTables() {
+		if !resolved.Contains(tblName) {
+			unresolved = append(unresolved, tblName)
+		}
+	}
+
+	ws = ws.WithWorkingRoot(root).WithMergeState(ws.MergeState().WithUnmergableTables(unresolved))
+	return ws, remaining, nil
+}
+
 func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 	dbName := ctx.GetCurrentDatabase()
-	fmt.Printf("database name: %s", dbName)
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
 
 	apr, err := cli.CreateConflictsResolveArgParser().Parse(args)
 	if err != nil {
@@ -411,6 +484,18 @@ func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	if rodb, ok := db.(sql.ReadOnlyDatabase); ok && rodb.IsReadOnly() {
+		return 1, fmt.Errorf("unable to resolve conflicts in read-only databases")
+	}
+	// Resolving conflicts completes a merge, so it's checked as one
+	if err = branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_Merge); err != nil {
+		return 1, err
+	}
+
 	roots, ok := dSess.GetRoots(ctx, dbName)
 	if !ok {
 		return 1, fmt.Errorf("Could not load database %s", dbName)
@@ -428,6 +513,11 @@ func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 		return 1, fmt.Errorf("specify at least one table to resolve conflicts")
 	}
 
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+
 	// get all tables in conflict
 	root := roots.Working
 	tbls := apr.Args
@@ -437,9 +527,24 @@ func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 		} else {
 			tbls = allTables
 		}
+		if ws.MergeActive() {
+			tbls = append(tbls, ws.MergeState().UnmergableTables()...)
+		}
+	}
+
+	ws, tbls, err = resolveSchemaConflicts(ctx, ws, ours, tbls)
+	if err != nil {
+		return 1, err
+	}
+	if err = dSess.SetWorkingSet(ctx, dbName, ws); err != nil {
+		return 1, err
 	}
 
-	err = ResolveConflicts(ctx, dSess, root, dbName, ours, tbls)
+	strategy := cli.TheirsStrategy
+	if ours {
+		strategy = cli.OursStrategy
+	}
+	err = ResolveConflicts(ctx, dSess, ws.WorkingRoot(), dbName, strategy, tbls)
 	if err != nil {
 		return 1, err
 	}
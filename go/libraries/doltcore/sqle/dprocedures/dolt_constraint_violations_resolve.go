@@ -0,0 +1,426 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// doltConstraintViolationsResolve is the stored procedure which resolves the constraint violations of tables in bulk,
+// such as those left by a forced merge. It returns the number of constraint violations that remain in the tables.
+func doltConstraintViolationsResolve(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := DoDoltConstraintViolationsResolve(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+// DoDoltConstraintViolationsResolve resolves the constraint violations of the tables given. With --delete, the rows
+// that violate constraints are deleted, and with --ignore they are kept. Either way, their violations are cleared.
+// With --reverify, the foreign keys of every row of the tables are verified again, and their foreign key violations
+// replaced with those found. Returns the number of constraint violations left in the tables.
+func DoDoltConstraintViolationsResolve(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateConstraintViolationsResolveArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+
+	del := apr.Contains(cli.DeleteFlag)
+	ignore := apr.Contains(cli.IgnoreFlag)
+	reverify := apr.Contains(cli.ReverifyFlag)
+	if (del && ignore) || (del && reverify) || (ignore && reverify) {
+		return 0, fmt.Errorf("specify only one of --delete, --ignore, or --reverify")
+	} else if !del && !ignore && !reverify {
+		return 0, fmt.Errorf("--delete, --ignore, or --reverify must be supplied")
+	}
+	if apr.NArg() == 0 && !reverify {
+		return 0, fmt.Errorf("specify at least one table to resolve constraint violations")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return 0, err
+	}
+	if rodb, ok := db.(sql.ReadOnlyDatabase); ok && rodb.IsReadOnly() {
+		return 0, fmt.Errorf("unable to resolve constraint violations in read-only databases")
+	}
+	// Like resolving conflicts, resolving constraint violations completes a merge, so it's checked as one
+	if err = branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_Merge); err != nil {
+		return 0, err
+	}
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return 0, err
+	}
+	root := ws.WorkingRoot()
+
+	tblNames, err := constraintViolationsTableNames(ctx, root, apr.Args, reverify)
+	if err != nil {
+		return 0, err
+	}
+
+	if reverify {
+		var theirRootIsh hash.Hash
+		if ws.MergeActive() {
+			theirRootIsh, err = ws.MergeState().Commit().HashOf()
+		} else {
+			var headCommit *doltdb.Commit
+			headCommit, err = dSess.GetHeadCommit(ctx, dbName)
+			if err == nil {
+				theirRootIsh, err = headCommit.HashOf()
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		root, err = reverifyForeignKeys(ctx, root, tblNames, theirRootIsh)
+	} else {
+		root, err = resolveConstraintViolations(ctx, dSess, dbName, root, tblNames, del)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err = dSess.SetRoot(ctx, dbName, root); err != nil {
+		return 0, err
+	}
+
+	var remaining uint64
+	for _, tblName := range tblNames {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		n, err := tbl.NumConstraintViolations(ctx)
+		if err != nil {
+			return 0, err
+		}
+		remaining += n
+	}
+	return int(remaining), nil
+}
+
+// constraintViolationsTableNames returns the names of the tables given, as they are named in |root|. The table "."
+// names every table with constraint violations, and when no table is given, every table is named for --reverify.
+func constraintViolationsTableNames(ctx *sql.Context, root *doltdb.RootValue, args []string, reverify bool) ([]string, error) {
+	if len(args) == 0 && reverify {
+		return root.GetTableNames(ctx)
+	}
+	if len(args) == 1 && args[0] == "." {
+		if reverify {
+			return root.GetTableNames(ctx)
+		}
+		return root.TablesWithConstraintViolations(ctx)
+	}
+
+	tblNames := make([]string, len(args))
+	for i, arg := range args {
+		_, tblName, ok, err := root.GetTableInsensitive(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, sql.ErrTableNotFound.New(arg)
+		}
+		tblNames[i] = tblName
+	}
+	return tblNames, nil
+}
+
+// resolveConstraintViolations clears the constraint violations of the tables given, first deleting the rows that
+// violate constraints when |del| is true. Deleting rows must not leave rows of other tables referring to them.
+func resolveConstraintViolations(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, root *doltdb.RootValue, tblNames []string, del bool) (*doltdb.RootValue, error) {
+	for _, tblName := range tblNames {
+		tbl, _, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := tbl.NumConstraintViolations(ctx); err != nil {
+			return nil, err
+		} else if n == 0 {
+			continue
+		}
+
+		if del {
+			sch, err := tbl.GetSchema(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if tbl.Format() == types.Format_DOLT {
+				tbl, err = deleteProllyViolatingRows(ctx, tbl, sch)
+			} else {
+				state, _, err := dSess.LookupDbState(ctx, dbName)
+				if err != nil {
+					return nil, err
+				}
+				tbl, err = deleteNomsViolatingRows(ctx, state.WriteSession.GetOptions(), tbl, tblName, sch)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tbl, err = tbl.ClearConstraintViolations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		newRoot, err := root.PutTable(ctx, tblName, tbl)
+		if err != nil {
+			return nil, err
+		}
+
+		if del {
+			tables, err := newRoot.GetTableNames(ctx)
+			if err != nil {
+				return nil, err
+			}
+			_, violators, err := merge.AddForeignKeyViolations(ctx, newRoot, root, set.NewStrSet(tables), hash.Of(nil))
+			if err != nil {
+				return nil, err
+			}
+			if violators.Size() > 0 {
+				return nil, fmt.Errorf("deleting the rows of table %s that violate constraints created foreign key violations", tblName)
+			}
+		}
+		root = newRoot
+	}
+	return root, nil
+}
+
+// deleteProllyViolatingRows deletes the rows of the table given that have constraint violations.
+func deleteProllyViolatingRows(ctx *sql.Context, tbl *doltdb.Table, sch schema.Schema) (*doltdb.Table, error) {
+	artIdx, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := durable.ProllyMapFromArtifactIndex(artIdx).IterAllCVs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIdx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mutMap := durable.ProllyMapFromIndex(rowIdx).Mutate()
+	idxSet, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mutIdxs, err := merge.GetMutableSecondaryIdxs(ctx, sch, idxSet)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		art, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		// a row may violate more than one constraint, in which case it's already been deleted
+		var value val.Tuple
+		err = mutMap.Get(ctx, art.Key, func(_, v val.Tuple) error {
+			value = v
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+
+		if err = mutMap.Delete(ctx, art.Key); err != nil {
+			return nil, err
+		}
+		for _, mutIdx := range mutIdxs {
+			if err = mutIdx.DeleteEntry(ctx, art.Key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	m, err := mutMap.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(m))
+	if err != nil {
+		return nil, err
+	}
+	for _, mutIdx := range mutIdxs {
+		m, err := mutIdx.Map(ctx)
+		if err != nil {
+			return nil, err
+		}
+		idxSet, err = idxSet.PutIndex(ctx, mutIdx.Name, durable.IndexFromProllyMap(m))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tbl.SetIndexSet(ctx, idxSet)
+}
+
+// deleteNomsViolatingRows deletes the rows of the table given that have constraint violations. The key of a
+// violation is its type, followed by the key of the violating row.
+func deleteNomsViolatingRows(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema) (*doltdb.Table, error) {
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("deleting the rows of keyless table %s that violate constraints is not supported in this format", tblName)
+	}
+
+	cvMap, err := tbl.GetConstraintViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rowData, err := tbl.GetNomsRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tblEditor, err := editor.NewTableEditor(ctx, tbl, sch, tblName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make(hash.HashSet)
+	err = cvMap.IterAll(ctx, func(key, _ types.Value) error {
+		vals, err := key.(types.Tuple).AsSlice()
+		if err != nil {
+			return err
+		}
+		rowKey, err := types.NewTuple(tbl.Format(), vals[2:]...)
+		if err != nil {
+			return err
+		}
+		h, err := rowKey.Hash(tbl.Format())
+		if err != nil {
+			return err
+		}
+		if deleted.Has(h) {
+			return nil
+		}
+		deleted.Insert(h)
+
+		rowVal, ok, err := rowData.MaybeGet(ctx, rowKey)
+		if err != nil || !ok {
+			return err
+		}
+		r, err := row.FromNoms(sch, rowKey, rowVal.(types.Tuple))
+		if err != nil {
+			return err
+		}
+		return tblEditor.DeleteRow(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tblEditor.Table(ctx)
+}
+
+// reverifyForeignKeys replaces the foreign key violations of the tables given with those found by verifying the
+// foreign keys of every one of their rows. Unique key and check constraint violations are kept.
+func reverifyForeignKeys(ctx *sql.Context, root *doltdb.RootValue, tblNames []string, theirRootIsh hash.Hash) (*doltdb.RootValue, error) {
+	for _, tblName := range tblNames {
+		tbl, _, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, err
+		}
+		tbl, err = clearForeignKeyViolations(ctx, tbl)
+		if err != nil {
+			return nil, err
+		}
+		root, err = root.PutTable(ctx, tblName, tbl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	emptyRoot, err := doltdb.EmptyRootValue(ctx, root.VRW(), root.NodeStore())
+	if err != nil {
+		return nil, err
+	}
+	root, _, err = merge.AddForeignKeyViolations(ctx, root, emptyRoot, set.NewStrSet(tblNames), theirRootIsh)
+	return root, err
+}
+
+// clearForeignKeyViolations deletes the foreign key violations of the table given.
+func clearForeignKeyViolations(ctx *sql.Context, tbl *doltdb.Table) (*doltdb.Table, error) {
+	if tbl.Format() == types.Format_DOLT {
+		artIdx, err := tbl.GetArtifacts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		artMap, err := durable.ProllyMapFromArtifactIndex(artIdx).ClearArtifactsOfType(ctx, prolly.ArtifactTypeForeignKeyViol)
+		if err != nil {
+			return nil, err
+		}
+		return tbl.SetArtifacts(ctx, durable.ArtifactIndexFromProllyMap(artMap))
+	}
+
+	cvMap, err := tbl.GetConstraintViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cvEditor := cvMap.Edit()
+	err = cvMap.IterAll(ctx, func(key, _ types.Value) error {
+		cvType, err := key.(types.Tuple).Get(1)
+		if err != nil {
+			return err
+		}
+		if cvType.Equals(types.Uint(merge.CvType_ForeignKey)) {
+			cvEditor.Remove(key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	cvMap, err = cvEditor.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tbl.SetConstraintViolations(ctx, cvMap)
+}
@@ -15,16 +15,73 @@
 package dprocedures
 
 import (
+	"sort"
+
 	"github.com/dolthub/go-mysql-server/sql"
 
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
 )
 
 // doltMerge is the stored procedure version of the functions `merge` and `dolt_merge`.
 func doltMerge(ctx *sql.Context, args ...string) (sql.RowIter, error) {
-	hasConflicts, ff, err := dfunctions.DoDoltMerge(ctx, args)
+	hasConflicts, ff, err := dfunctions.DoDoltMergeWithStrategies(ctx, args, resolveMergeConflicts)
 	if err != nil {
 		return nil, err
 	}
 	return rowToIter(int64(ff), int64(hasConflicts)), nil
 }
+
+// resolveMergeConflicts resolves the conflicts of the merge in the working set given with the strategy of each table,
+// as dolt_conflicts_resolve would. Tables without a strategy are left in conflict.
+func resolveMergeConflicts(ctx *sql.Context, dbName string, ws *doltdb.WorkingSet, strategies dfunctions.MergeStrategies) (*doltdb.WorkingSet, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	conflicted, err := ws.WorkingRoot().TablesInConflict(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tblNames := set.NewStrSet(conflicted)
+	if ws.MergeActive() {
+		tblNames.Add(ws.MergeState().UnmergableTables()...)
+	}
+
+	byStrategy := make(map[string][]string)
+	for _, tblName := range tblNames.AsSlice() {
+		if strategy := strategies.ForTable(tblName); strategy != "" {
+			byStrategy[strategy] = append(byStrategy[strategy], tblName)
+		}
+	}
+
+	for _, strategy := range cli.MergeStrategies {
+		tblNames := byStrategy[strategy]
+		if len(tblNames) == 0 {
+			continue
+		}
+		sort.Strings(tblNames)
+
+		ws, err = dSess.WorkingSet(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
+		// Schemas can't be combined, so the union strategy keeps our schema as the ours strategy does
+		var remaining []string
+		ws, remaining, err = resolveSchemaConflicts(ctx, ws, strategy != cli.TheirsStrategy, tblNames)
+		if err != nil {
+			return nil, err
+		}
+		if err = dSess.SetWorkingSet(ctx, dbName, ws); err != nil {
+			return nil, err
+		}
+
+		err = ResolveConflicts(ctx, dSess, ws.WorkingRoot(), dbName, strategy, remaining)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dSess.WorkingSet(ctx, dbName)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// doltProposeMove records a proposal to move the head of a branch to a revision, which an admin on the branch may
+// then approve or reject.
+func doltProposeMove(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_PROPOSE_MOVE", 2, len(args))
+	}
+	dbData, err := getMoveDbData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = requireBranch(ctx, dbData.Ddb, args[0]); err != nil {
+		return nil, err
+	}
+	cs, err := doltdb.NewCommitSpec(args[1])
+	if err != nil {
+		return nil, err
+	}
+	cm, err := dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, dbData.Rsr.CWBHeadRef())
+	if err != nil {
+		return nil, err
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if err = branch_control.ProposeMove(ctx, args[0], h.String()); err != nil {
+		return nil, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltApproveMove applies the pending move of a branch, which must be to the expected revision. The move must be a
+// fast-forward of the branch's head unless --force is given.
+func doltApproveMove(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreateApproveMoveArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_APPROVE_MOVE", 2, apr.NArg())
+	}
+	branch := apr.Arg(0)
+	dbData, err := getMoveDbData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = requireBranch(ctx, dbData.Ddb, branch); err != nil {
+		return nil, err
+	}
+	cs, err := doltdb.NewCommitSpec(apr.Arg(1))
+	if err != nil {
+		return nil, err
+	}
+	expected, err := dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, dbData.Rsr.CWBHeadRef())
+	if err != nil {
+		return nil, err
+	}
+	expectedHash, err := expected.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	err = branch_control.ApproveMove(ctx, branch, expectedHash.String(), func(move branch_control.PendingMove) error {
+		h, ok := hash.MaybeParse(move.ProposedHash)
+		if !ok {
+			return fmt.Errorf("invalid proposed hash `%s` for branch `%s`", move.ProposedHash, branch)
+		}
+		cm, err := dbData.Ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return err
+		}
+		return moveBranchHead(ctx, dbData, ref.NewBranchRef(branch), cm, apr.Contains(cli.ForceFlag))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// doltRejectMove removes the pending move of a branch without applying it.
+func doltRejectMove(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("DOLT_REJECT_MOVE", 1, len(args))
+	}
+	if err := branch_control.RejectMove(ctx, args[0]); err != nil {
+		return nil, err
+	}
+	if err := branch_control.SaveData(ctx); err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0)), nil
+}
+
+// getMoveDbData returns the data of the current database, which must not be read-only as its refs are modified.
+func getMoveDbData(ctx *sql.Context) (env.DbData, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return env.DbData{}, fmt.Errorf("Empty database name.")
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return env.DbData{}, err
+	}
+	if rodb, ok := db.(sql.ReadOnlyDatabase); ok && rodb.IsReadOnly() {
+		return env.DbData{}, fmt.Errorf("unable to move branches in read-only databases")
+	}
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return env.DbData{}, fmt.Errorf("Could not load database %s", dbName)
+	}
+	return dbData, nil
+}
+
+// requireBranch returns an error if the given branch does not exist.
+func requireBranch(ctx *sql.Context, ddb *doltdb.DoltDB, branch string) error {
+	ok, err := ddb.HasBranch(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("branch not found: %s", branch)
+	}
+	return nil
+}
+
+// moveBranchHead moves the head of the given branch to the given commit, replacing the branch's working set in the
+// same way as a hard reset. Without |force|, the head is only moved when the commit is a fast-forward of it.
+func moveBranchHead(ctx *sql.Context, dbData env.DbData, branchRef ref.DoltRef, cm *doltdb.Commit, force bool) error {
+	if force {
+		if err := dbData.Ddb.SetHeadToCommit(ctx, branchRef, cm); err != nil {
+			return err
+		}
+	} else if err := dbData.Ddb.FastForward(ctx, branchRef, cm); err == datas.ErrMergeNeeded {
+		return fmt.Errorf("the proposed move of branch `%s` is not a fast-forward, use --force to apply it anyway", branchRef.GetPath())
+	} else if err != nil {
+		return err
+	}
+
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	// The session holds the working set of its checked out branch, so that's updated rather than the stored one
+	if ref.Equals(branchRef, dbData.Rsr.CWBHeadRef()) {
+		dSess := dsess.DSessFromSess(ctx.Session)
+		dbName := ctx.GetCurrentDatabase()
+		ws, err := dSess.WorkingSet(ctx, dbName)
+		if err != nil {
+			return err
+		}
+		return dSess.SetWorkingSet(ctx, dbName, ws.WithWorkingRoot(root).WithStagedRoot(root).ClearMerge())
+	}
+
+	// The head has already been moved, so only the stored working set is replaced. A branch without a working set is
+	// given one when it's checked out.
+	wsRef, err := ref.WorkingSetRefForHead(branchRef)
+	if err != nil {
+		return err
+	}
+	ws, err := dbData.Ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	wsHash, err := ws.HashOf()
+	if err != nil {
+		return err
+	}
+	return dbData.Ddb.UpdateWorkingSet(ctx, wsRef, ws.WithWorkingRoot(root).WithStagedRoot(root).ClearMerge(), wsHash, doltdb.TodoWorkingSetMeta())
+}
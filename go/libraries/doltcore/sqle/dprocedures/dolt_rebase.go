@@ -0,0 +1,409 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// rebaseBranchPrefix is the prefix of the branches that interactive rebases are performed from. The interactive rebase
+// of branch b checks out branch dolt_rebase_b, which starts at the commit that b is rebased onto, and whose working set
+// holds the rebase plan in the dolt_rebase_plan table until the rebase is continued or aborted.
+const rebaseBranchPrefix = "dolt_rebase_"
+
+var ErrNoRebaseInProgress = errors.New("no interactive rebase is in progress; interactive rebases are continued and " +
+	"aborted from the branch that they check out, dolt_rebase_<branch>")
+
+// doltRebase replays the commits of the current branch that aren't reachable from the upstream commit given on top of
+// it, and moves the branch to the result. Merge commits aren't replayed, so that the rebased history is linear.
+//
+// With -i, the commits aren't replayed right away. Instead, a branch is checked out whose working set holds the plan of
+// the rebase in the dolt_rebase_plan table, with a row for each commit. The plan may be edited to pick, reword, squash
+// or drop each commit, and to reorder them, before the rebase is performed with dolt_rebase('--continue') or abandoned
+// with dolt_rebase('--abort'). A plan that results in conflicts changes nothing, so that it may be edited and continued
+// again.
+func doltRebase(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	msg, err := doDoltRebase(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(0), msg), nil
+}
+
+func doDoltRebase(ctx *sql.Context, args []string) (string, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return "", fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateRebaseArgParser().Parse(args)
+	if err != nil {
+		return "", err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if rodb, ok := db.(sql.ReadOnlyDatabase); ok && rodb.IsReadOnly() {
+		return "", fmt.Errorf("unable to rebase branches of read-only databases")
+	}
+
+	switch {
+	case apr.Contains(cli.AbortParam):
+		if apr.NArg() > 0 || apr.Contains(cli.ContinueFlag) || apr.Contains(cli.InteractiveFlag) {
+			return "", fmt.Errorf("error: --%s takes no other arguments", cli.AbortParam)
+		}
+		return abortRebase(ctx, dSess, dbName)
+	case apr.Contains(cli.ContinueFlag):
+		if apr.NArg() > 0 || apr.Contains(cli.InteractiveFlag) {
+			return "", fmt.Errorf("error: --%s takes no other arguments", cli.ContinueFlag)
+		}
+		return continueRebase(ctx, dSess, dbName)
+	case apr.NArg() != 1:
+		return "", fmt.Errorf("usage: dolt_rebase(['-i',] UPSTREAM), dolt_rebase('--continue'), or dolt_rebase('--abort')")
+	default:
+		return startRebase(ctx, dSess, dbName, apr.Arg(0), apr.Contains(cli.InteractiveFlag))
+	}
+}
+
+// startRebase rebases the current branch onto the commit |upstream|, or starts an interactive rebase of it.
+func startRebase(ctx *sql.Context, dSess *dsess.DoltSession, dbName, upstream string, interactive bool) (string, error) {
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	if interactive {
+		if err := checkRebaseDatabase(ctx, dSess, dbName); err != nil {
+			return "", err
+		}
+	}
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if ok, err := ws.WorkingRoot().HasTable(ctx, doltdb.RebasePlanTableName); err != nil {
+		return "", err
+	} else if ok {
+		return "", fmt.Errorf("error: an interactive rebase is in progress; " +
+			"continue it with dolt_rebase('--continue'), or abort it with dolt_rebase('--abort')")
+	}
+	if err = checkRebaseWorkingSet(ctx, dSess, dbName); err != nil {
+		return "", err
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	branch := headRef.GetPath()
+	if err = checkRebaseRefMove(ctx, branch); err != nil {
+		return "", err
+	}
+
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	cs, err := doltdb.NewCommitSpec(upstream)
+	if err != nil {
+		return "", err
+	}
+	onto, err := dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, headRef)
+	if err != nil {
+		return "", err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return "", err
+	}
+	ontoHash, err := onto.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := commitwalk.GetDotDotRevisions(ctx, dbData.Ddb, headHash, dbData.Ddb, ontoHash, -1)
+	if err != nil {
+		return "", err
+	}
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	plan, err := rebase.NewPlan(ctx, commits)
+	if err != nil {
+		return "", err
+	}
+
+	if !interactive {
+		dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+		if err != nil {
+			return "", err
+		} else if !ok {
+			return "", fmt.Errorf("Could not load database %s", dbName)
+		}
+		newHead, err := rebase.Execute(ctx, dbData.Ddb, onto, plan, dbState.EditOpts())
+		if err != nil {
+			return "", err
+		}
+		if err = moveRebasedBranch(ctx, dSess, dbName, headRef, newHead); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Successfully rebased and updated %s", headRef.String()), nil
+	}
+
+	rebaseBranch := rebaseBranchPrefix + branch
+	if ok, err := actions.IsBranch(ctx, dbData.Ddb, rebaseBranch); err != nil {
+		return "", err
+	} else if ok {
+		return "", fmt.Errorf("error: branch %s already exists; an interactive rebase of branch %s may already be in progress, "+
+			"which can be continued or aborted from that branch", rebaseBranch, branch)
+	}
+	if err = branch_control.CanCreateBranch(ctx, rebaseBranch); err != nil {
+		return "", err
+	}
+	if err = actions.CreateBranchWithStartPt(ctx, dbData, rebaseBranch, ontoHash.String(), false); err != nil {
+		return "", err
+	}
+	if err = branch_control.GrantCreatedBranch(ctx, rebaseBranch); err != nil {
+		return "", err
+	}
+	if err = switchRebaseBranch(ctx, dSess, dbName, rebaseBranch); err != nil {
+		return "", err
+	}
+
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	roots.Working, err = rebase.WritePlan(ctx, roots.Working, plan)
+	if err != nil {
+		return "", err
+	}
+	if err = dSess.SetRoots(ctx, dbName, roots); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("interactive rebase started on branch %s; adjust the rebase plan in the %s table, "+
+		"then continue rebasing by calling dolt_rebase('--continue')", rebaseBranch, doltdb.RebasePlanTableName), nil
+}
+
+// continueRebase performs the plan of the interactive rebase in progress, moves the rebased branch to the result, and
+// checks it out again.
+func continueRebase(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) (string, error) {
+	rebaseBranch, branch, err := currentRebase(ctx, dSess, dbName)
+	if err != nil {
+		return "", err
+	}
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	plan, ok, err := rebase.LoadPlan(ctx, ws.WorkingRoot())
+	if err != nil {
+		return "", err
+	} else if !ok {
+		return "", ErrNoRebaseInProgress
+	}
+	if err = checkRebaseRefMove(ctx, branch); err != nil {
+		return "", err
+	}
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return "", err
+	} else if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	onto, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	newHead, err := rebase.Execute(ctx, dbData.Ddb, onto, plan, dbState.EditOpts())
+	if err != nil {
+		return "", err
+	}
+
+	if err = switchRebaseBranch(ctx, dSess, dbName, branch); err != nil {
+		return "", err
+	}
+	if err = checkRebaseWorkingSet(ctx, dSess, dbName); err != nil {
+		return "", err
+	}
+	branchRef := ref.NewBranchRef(branch)
+	if err = moveRebasedBranch(ctx, dSess, dbName, branchRef, newHead); err != nil {
+		return "", err
+	}
+	if err = deleteRebaseBranch(ctx, dSess, dbName, rebaseBranch); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Successfully rebased and updated %s", branchRef.String()), nil
+}
+
+// abortRebase abandons the interactive rebase in progress, and checks out the branch that was being rebased again.
+func abortRebase(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) (string, error) {
+	rebaseBranch, branch, err := currentRebase(ctx, dSess, dbName)
+	if err != nil {
+		return "", err
+	}
+	if err = switchRebaseBranch(ctx, dSess, dbName, branch); err != nil {
+		return "", err
+	}
+	if err = deleteRebaseBranch(ctx, dSess, dbName, rebaseBranch); err != nil {
+		return "", err
+	}
+	return "interactive rebase aborted", nil
+}
+
+// currentRebase returns the branch checked out by the interactive rebase in progress, and the branch being rebased.
+func currentRebase(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) (string, string, error) {
+	if err := checkRebaseDatabase(ctx, dSess, dbName); err != nil {
+		return "", "", err
+	}
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", "", err
+	}
+	rebaseBranch := headRef.GetPath()
+	if !strings.HasPrefix(rebaseBranch, rebaseBranchPrefix) {
+		return "", "", ErrNoRebaseInProgress
+	}
+	branch := strings.TrimPrefix(rebaseBranch, rebaseBranchPrefix)
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	if ok, err := actions.IsBranch(ctx, dbData.Ddb, branch); err != nil {
+		return "", "", err
+	} else if !ok {
+		return "", "", fmt.Errorf("error: branch %s that was being rebased no longer exists", branch)
+	}
+	return rebaseBranch, branch, nil
+}
+
+// checkRebaseDatabase returns an error for revision databases, as interactive rebases check out branches, which
+// revision databases can't do.
+func checkRebaseDatabase(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) error {
+	_, revision, err := dSess.Provider().GetRevisionForRevisionDatabase(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if revision != "" {
+		return fmt.Errorf("interactive rebases are not supported in revision database %s; "+
+			"use dolt_checkout to check out the branch to rebase instead", dbName)
+	}
+	return nil
+}
+
+// checkRebaseWorkingSet returns an error if the session's working set has uncommitted changes or is in the middle of a
+// merge, as rebasing would discard them.
+func checkRebaseWorkingSet(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) error {
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if ws.MergeActive() {
+		return doltdb.ErrMergeActive
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	for _, root := range []*doltdb.RootValue{ws.WorkingRoot(), ws.StagedRoot()} {
+		if changed, err := rootChangedFromCommit(ctx, root, headCommit); err != nil {
+			return err
+		} else if changed {
+			headRef, err := ws.Ref().ToHeadRef()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("error: cannot rebase branch %s, as it has uncommitted changes; commit or discard them first",
+				headRef.GetPath())
+		}
+	}
+	return nil
+}
+
+// checkRebaseRefMove returns an error if the head of the branch given may not be moved by the current user, or if
+// moving it requires approval, which rebases don't support.
+func checkRebaseRefMove(ctx *sql.Context, branch string) error {
+	requiresApproval, err := branch_control.CheckRefMove(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if requiresApproval {
+		return fmt.Errorf("branch `%s` requires approval to move its head, so it can't be rebased", branch)
+	}
+	return nil
+}
+
+// moveRebasedBranch moves the head of the session's current branch to the rebased commit given, along with its working
+// set.
+func moveRebasedBranch(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, branchRef ref.DoltRef, newHead *doltdb.Commit) error {
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	if err := dbData.Ddb.SetHeadToCommit(ctx, branchRef, newHead); err != nil {
+		return err
+	}
+	root, err := newHead.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	return dSess.SetWorkingSet(ctx, dbName, ws.WithWorkingRoot(root).WithStagedRoot(root).ClearMerge())
+}
+
+func switchRebaseBranch(ctx *sql.Context, dSess *dsess.DoltSession, dbName, branch string) error {
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef(branch))
+	if err != nil {
+		return err
+	}
+	return dSess.SwitchWorkingSet(ctx, dbName, wsRef)
+}
+
+func deleteRebaseBranch(ctx *sql.Context, dSess *dsess.DoltSession, dbName, rebaseBranch string) error {
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	err := actions.DeleteBranchOnDB(ctx, dbData, nil, ref.NewBranchRef(rebaseBranch), actions.DeleteOptions{Force: true})
+	if err != nil {
+		return err
+	}
+	return branch_control.RevokeDeletedBranch(ctx, rebaseBranch)
+}
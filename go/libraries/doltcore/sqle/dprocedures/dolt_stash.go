@@ -0,0 +1,176 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+// doltStash puts aside the uncommitted changes of the session's working set, or restores them with the pop and apply
+// subcommands. Stashes are listed by the dolt_stashes system table.
+func doltStash(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltStash(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltStash(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateStashArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+
+	subcommand := cli.StashPushCmd
+	if apr.NArg() > 0 {
+		subcommand = apr.Arg(0)
+	}
+	if subcommand != cli.StashPushCmd && apr.Contains(cli.MessageArg) {
+		return 1, fmt.Errorf("the message option may only be given when pushing a stash")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	switch subcommand {
+	case cli.StashPushCmd:
+		if apr.NArg() > 1 {
+			return 1, fmt.Errorf("%s takes no stash", subcommand)
+		}
+		err = stashPush(ctx, dSess, dbName, apr)
+	case cli.StashPopCmd, cli.StashApplyCmd:
+		err = stashApply(ctx, dSess, dbName, apr, subcommand == cli.StashPopCmd)
+	case cli.StashDropCmd:
+		var stash doltdb.Stash
+		stash, err = actions.ResolveStash(ctx, dbData.Ddb, stashSpec(apr))
+		if err == nil {
+			err = dbData.Ddb.DeleteStash(ctx, stash.Ref)
+		}
+	case cli.StashClearCmd:
+		err = actions.ClearStashes(ctx, dbData.Ddb)
+	default:
+		return 1, fmt.Errorf("unknown stash subcommand `%s`; use the dolt_stashes system table to list stashes", subcommand)
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// stashPush stashes the changes of the session's working set, leaving it clean.
+func stashPush(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, apr *argparser.ArgParseResults) error {
+	if err := checkStashWorkingSet(ctx, dSess, dbName, branch_control.Operations_DirectDML); err != nil {
+		return err
+	}
+	dbData, _ := dSess.GetDbData(ctx, dbName)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+
+	msg, _ := apr.GetValue(cli.MessageArg)
+	props := actions.StashProps{Name: dSess.Username(), Email: dSess.Email(), Message: msg}
+	roots, err = actions.StashChanges(ctx, dbData.Ddb, roots, headCommit, dbData.Rsr.CWBHeadRef().GetPath(), props)
+	if err != nil {
+		return err
+	}
+	return dSess.SetRoots(ctx, dbName, roots)
+}
+
+// stashApply applies the changes of a stash to the session's working set, deleting the stash when |pop| is true.
+func stashApply(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, apr *argparser.ArgParseResults, pop bool) error {
+	if err := checkStashWorkingSet(ctx, dSess, dbName, branch_control.Operations_Merge); err != nil {
+		return err
+	}
+	dbData, _ := dSess.GetDbData(ctx, dbName)
+	stash, err := actions.ResolveStash(ctx, dbData.Ddb, stashSpec(apr))
+	if err != nil {
+		return err
+	}
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	roots.Working, err = merge.ApplyStash(ctx, dbData.Ddb, roots.Working, stash, dbState.EditOpts())
+	if err != nil {
+		return err
+	}
+	if err = dSess.SetRoots(ctx, dbName, roots); err != nil {
+		return err
+	}
+	if pop {
+		return dbData.Ddb.DeleteStash(ctx, stash.Ref)
+	}
+	return nil
+}
+
+// checkStashWorkingSet returns an error if the session's working set may not be stashed or have a stash applied to it,
+// as it belongs to a read-only database, it is in the middle of a merge, or the user may not write to the branch with
+// the given class of operation. Applying a stash is a merge, while pushing a stash directly modifies the working set.
+func checkStashWorkingSet(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, op branch_control.Operations) error {
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if rodb, ok := db.(sql.ReadOnlyDatabase); ok && rodb.IsReadOnly() {
+		return fmt.Errorf("unable to stash changes in read-only databases")
+	}
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if ws.MergeActive() {
+		return fmt.Errorf("unable to stash changes while a merge is in progress")
+	}
+	return branch_control.CheckAccess(ctx, branch_control.Permissions_Write, op)
+}
+
+// stashSpec returns the stash named by the arguments, or an empty spec for the most recent stash.
+func stashSpec(apr *argparser.ArgParseResults) string {
+	if apr.NArg() > 1 {
+		return apr.Arg(1)
+	}
+	return ""
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 
@@ -61,6 +62,15 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		if apr.Contains(cli.MessageArg) {
 			return 1, fmt.Errorf("delete and tag message options are incompatible")
 		}
+		if apr.Contains(cli.AuthorParam) {
+			return 1, fmt.Errorf("delete and author options are incompatible")
+		}
+		// Verify that we can delete all tags before continuing
+		for _, tagName := range apr.Args {
+			if err = branch_control.CanModifyTag(ctx, tagName); err != nil {
+				return 1, err
+			}
+		}
 		err = actions.DeleteTagsOnDB(ctx, dbData.Ddb, apr.Args...)
 		if err != nil {
 			return 1, err
@@ -73,8 +83,16 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		return 1, fmt.Errorf("create tag takes at most two args")
 	}
 
-	name := dSess.Username()
-	email := dSess.Email()
+	var name, email string
+	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
+		name, email, err = cli.ParseAuthor(authorStr)
+		if err != nil {
+			return 1, err
+		}
+	} else {
+		name = dSess.Username()
+		email = dSess.Email()
+	}
 	msg, _ := apr.GetValue(cli.MessageArg)
 
 	props := actions.TagProps{
@@ -88,6 +106,9 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 	if len(apr.Args) > 1 {
 		startPoint = apr.Arg(1)
 	}
+	if err = branch_control.CanModifyTag(ctx, tagName); err != nil {
+		return 1, err
+	}
 	headRef := dbData.Rsr.CWBHeadRef()
 	err = actions.CreateTagOnDB(ctx, dbData.Ddb, tagName, startPoint, props, headRef)
 	if err != nil {
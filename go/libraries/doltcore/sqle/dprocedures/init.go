@@ -18,20 +18,38 @@ import "github.com/dolthub/go-mysql-server/sql"
 
 var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_add", Schema: int64Schema("status"), Function: doltAdd},
+	{Name: "dolt_approve_move", Schema: int64Schema("status"), Function: doltApproveMove},
 	{Name: "dolt_backup", Schema: int64Schema("success"), Function: doltBackup},
+	{Name: "dolt_backup_schedule", Schema: int64Schema("status"), Function: doltBackupSchedule},
 	{Name: "dolt_branch", Schema: int64Schema("status"), Function: doltBranch},
+	{Name: "dolt_branch_control_compact", Schema: int64Schema("status"), Function: doltBranchControlCompact},
+	{Name: "dolt_branch_control_dedup", Schema: int64Schema("access_rows", "namespace_rows"), Function: doltBranchControlDedup},
+	{Name: "dolt_branch_control_default_branches", Schema: stringSchema("database", "default_branch", "previous_branch", "changed_by", "changed_at"), Function: doltBranchControlDefaultBranches},
+	{Name: "dolt_branch_control_export", Schema: stringSchema("data"), Function: doltBranchControlExport},
+	{Name: "dolt_branch_control_import", Schema: int64Schema("status"), Function: doltBranchControlImport},
+	{Name: "dolt_branch_control_prune", Schema: int64Schema("access_rows", "namespace_rows"), Function: doltBranchControlPrune},
+	{Name: "dolt_branch_control_reload", Schema: int64Schema("status"), Function: doltBranchControlReload},
+	{Name: "dolt_branch_control_status", Schema: append(stringSchema("source", "location"), append(int64Schema("access_rows", "namespace_rows", "duplicate_rows"), stringSchema("escalation_constraints")...)...), Function: doltBranchControlStatus},
+	{Name: "dolt_branch_freeze", Schema: int64Schema("status"), Function: doltBranchFreeze},
+	{Name: "dolt_branch_unfreeze", Schema: int64Schema("status"), Function: doltBranchUnfreeze},
 	{Name: "dolt_checkout", Schema: int64Schema("status"), Function: doltCheckout},
+	{Name: "dolt_cherry_pick", Schema: append(stringSchema("hash"), int64Schema("conflicts")...), Function: doltCherryPick},
 	{Name: "dolt_clean", Schema: int64Schema("status"), Function: doltClean},
 	{Name: "dolt_clone", Schema: int64Schema("status"), Function: doltClone},
 	{Name: "dolt_commit", Schema: stringSchema("hash"), Function: doltCommit},
 	{Name: "dolt_conflicts_resolve", Schema: int64Schema("status"), Function: doltConflictsResolve},
+	{Name: "dolt_constraint_violations_resolve", Schema: int64Schema("violations"), Function: doltConstraintViolationsResolve},
 	{Name: "dolt_fetch", Schema: int64Schema("success"), Function: doltFetch},
 	{Name: "dolt_merge", Schema: int64Schema("fast_forward", "conflicts"), Function: doltMerge},
+	{Name: "dolt_propose_move", Schema: int64Schema("status"), Function: doltProposeMove},
 	{Name: "dolt_pull", Schema: int64Schema("fast_forward", "conflicts"), Function: doltPull},
 	{Name: "dolt_push", Schema: int64Schema("success"), Function: doltPush},
+	{Name: "dolt_rebase", Schema: append(int64Schema("status"), stringSchema("message")...), Function: doltRebase},
+	{Name: "dolt_reject_move", Schema: int64Schema("status"), Function: doltRejectMove},
 	{Name: "dolt_remote", Schema: int64Schema("status"), Function: doltRemote},
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
+	{Name: "dolt_stash", Schema: int64Schema("status"), Function: doltStash},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
 
@@ -0,0 +1,88 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	BackupRunStatusRunning = "running"
+	BackupRunStatusSuccess = "success"
+	BackupRunStatusError   = "error"
+)
+
+// BackupRun describes the most recent sync of a backup made by this process, whether it was run by the backup
+// scheduler or by a call to dolt_backup('sync', ...).
+type BackupRun struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Status     string
+	Error      string
+}
+
+var backupRuns = struct {
+	mu   sync.Mutex
+	runs map[string]BackupRun
+}{runs: make(map[string]BackupRun)}
+
+// backupRunKey returns the key of the runs of a backup. Backups are configured per database rather than per revision,
+// so any revision qualifier of the database name is dropped.
+func backupRunKey(dbName, backupName string) string {
+	dbName = strings.SplitN(dbName, "/", 2)[0]
+	return strings.ToLower(dbName) + "\x00" + backupName
+}
+
+// StartBackupRun records the start of a sync of the backup named of the database named, returning an error if a sync
+// of that backup is already running.
+func StartBackupRun(dbName, backupName string) error {
+	backupRuns.mu.Lock()
+	defer backupRuns.mu.Unlock()
+
+	key := backupRunKey(dbName, backupName)
+	if run, ok := backupRuns.runs[key]; ok && run.Status == BackupRunStatusRunning {
+		return fmt.Errorf("backup '%s' is already being synced, since %s", backupName, run.StartedAt.Format(time.RFC3339))
+	}
+	backupRuns.runs[key] = BackupRun{StartedAt: time.Now(), Status: BackupRunStatusRunning}
+	return nil
+}
+
+// FinishBackupRun records the outcome of a sync started with StartBackupRun.
+func FinishBackupRun(dbName, backupName string, err error) {
+	backupRuns.mu.Lock()
+	defer backupRuns.mu.Unlock()
+
+	key := backupRunKey(dbName, backupName)
+	run := backupRuns.runs[key]
+	run.FinishedAt = time.Now()
+	run.Status = BackupRunStatusSuccess
+	if err != nil {
+		run.Status = BackupRunStatusError
+		run.Error = err.Error()
+	}
+	backupRuns.runs[key] = run
+}
+
+// GetBackupRun returns the most recent sync of the backup named of the database named, if there has been one.
+func GetBackupRun(dbName, backupName string) (BackupRun, bool) {
+	backupRuns.mu.Lock()
+	defer backupRuns.mu.Unlock()
+
+	run, ok := backupRuns.runs[backupRunKey(dbName, backupName)]
+	return run, ok
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ResolveReadableCommit is the same as DoltDB.Resolve, except that it returns an error when the commit is hidden from
+// the context by branch control. A commit spec that names a branch other than the current working branch |cwb|
+// requires that the branch can be read. Any other commit spec, such as a commit hash or a tag, requires that the
+// commit is readable according to CheckReadableCommit.
+func ResolveReadableCommit(ctx context.Context, ddb *doltdb.DoltDB, cs *doltdb.CommitSpec, cwb ref.DoltRef) (*doltdb.Commit, error) {
+	cm, resolvedRef, err := ddb.ResolveWithRef(ctx, cs, cwb)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resolvedRef != nil && cwb != nil && ref.Equals(resolvedRef, cwb):
+		return cm, nil
+	case resolvedRef != nil && resolvedRef.GetType() == ref.BranchRefType:
+		err = branch_control.CanReadBranch(ctx, resolvedRef.GetPath())
+	default:
+		err = CheckReadableCommit(ctx, ddb, cm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// ResolveReadableCommitRef is the same as DoltDB.ResolveCommitRef, except that it returns an error when the ref is a
+// branch that is hidden from the context by branch control.
+func ResolveReadableCommitRef(ctx context.Context, ddb *doltdb.DoltDB, r ref.DoltRef) (*doltdb.Commit, error) {
+	if r.GetType() == ref.BranchRefType {
+		if err := branch_control.CanReadBranch(ctx, r.GetPath()); err != nil {
+			return nil, err
+		}
+	}
+	return ddb.ResolveCommitRef(ctx, r)
+}
+
+// IsHiddenRef returns whether the given ref is a branch that is hidden from the context by branch control. Listings of
+// refs skip hidden branches rather than failing, so that they only show the branches that the context can read.
+func IsHiddenRef(ctx context.Context, r ref.DoltRef) bool {
+	return r.GetType() == ref.BranchRefType && branch_control.IsBranchHidden(ctx, r.GetPath())
+}
+
+// CommitItrForReadableBranches is the same as doltdb.CommitItrForAllBranches, except that the branches that are hidden
+// from the context are skipped, along with the commits that only they can reach.
+func CommitItrForReadableBranches(ctx context.Context, ddb *doltdb.DoltDB) (doltdb.CommitItr, error) {
+	branchRefs, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootCommits := make([]*doltdb.Commit, 0, len(branchRefs))
+	for _, branchRef := range branchRefs {
+		if IsHiddenRef(ctx, branchRef) {
+			continue
+		}
+		cm, err := ddb.ResolveCommitRef(ctx, branchRef)
+		if err != nil {
+			return nil, err
+		}
+		rootCommits = append(rootCommits, cm)
+	}
+	return doltdb.CommitItrForRoots(ddb, rootCommits...), nil
+}
+
+// CheckReadableCommit returns an error when the given commit can only be reached from branches that are hidden from
+// the context. Commits that no branch can reach, such as those that were reset away, are not hidden by any branch.
+func CheckReadableCommit(ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit) error {
+	if !branch_control.MayHideBranches(ctx) {
+		return nil
+	}
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	var readableHeads []hash.Hash
+	var hiddenBranches []doltdb.BranchWithHash
+	for _, branch := range branches {
+		if IsHiddenRef(ctx, branch.Ref) {
+			hiddenBranches = append(hiddenBranches, branch)
+		} else {
+			readableHeads = append(readableHeads, branch.Hash)
+		}
+	}
+	if len(hiddenBranches) == 0 {
+		return nil
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	height, err := cm.Height()
+	if err != nil {
+		return err
+	}
+	for _, head := range readableHeads {
+		if ok, err := isReachableFrom(ctx, ddb, head, h, height); err != nil || ok {
+			return err
+		}
+	}
+	for _, branch := range hiddenBranches {
+		ok, err := isReachableFrom(ctx, ddb, branch.Hash, h, height)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return branch_control.CanReadBranch(ctx, branch.Ref.GetPath())
+		}
+	}
+	return nil
+}
+
+// isReachableFrom returns whether the commit |h|, with the given |height|, is reachable from the commit |head|.
+func isReachableFrom(ctx context.Context, ddb *doltdb.DoltDB, head hash.Hash, h hash.Hash, height uint64) (bool, error) {
+	if head == h {
+		return true, nil
+	}
+	ancestors, err := commitwalk.NewAncestorSet(ctx, ddb, head)
+	if err != nil {
+		return false, err
+	}
+	return ancestors.Contains(ctx, h, height)
+}
@@ -48,6 +48,7 @@ const (
 
 var ErrWorkingSetChanges = goerrors.NewKind("Cannot switch working set, session state is dirty. " +
 	"Rollback or commit changes before changing working sets.")
+var ErrUnresolvedSchemaConflicts = errors.New("cannot commit a merge with unresolved schema conflicts; resolve them with dolt_conflicts_resolve, or abort the merge")
 var ErrSessionNotPeristable = errors.New("session is not persistable")
 var ErrCurrentBranchDeleted = errors.New("current branch has been force deleted. run 'USE <database>/<branch>' to checkout a different branch, or reconnect to the server")
 
@@ -297,6 +298,11 @@ func (d *DoltSession) newWorkingSetForHead(ctx *sql.Context, wsRef ref.WorkingSe
 // CommitTransaction commits the in-progress transaction for the database named. Depending on session settings, this
 // may write only a new working set, or may additionally create a new dolt commit for the current HEAD.
 func (d *DoltSession) CommitTransaction(ctx *sql.Context, dbName string, tx sql.Transaction) error {
+	// Branch control tables are not versioned, so their changes are committed regardless of the database
+	if err := branch_control.CommitTransaction(ctx); err != nil {
+		return err
+	}
+
 	if d.BatchMode() == Batched {
 		err := d.Flush(ctx, dbName)
 		if err != nil {
@@ -461,7 +467,13 @@ func (d *DoltSession) NewPendingCommit(ctx *sql.Context, dbName string, roots do
 
 	var mergeParentCommits []*doltdb.Commit
 	if sessionState.WorkingSet.MergeActive() {
-		mergeParentCommits = []*doltdb.Commit{sessionState.WorkingSet.MergeState().Commit()}
+		if sessionState.WorkingSet.MergeState().HasSchemaConflicts() {
+			return nil, ErrUnresolvedSchemaConflicts
+		}
+		// the commit of a cherry-pick is applied to the branch rather than merged into it
+		if !sessionState.WorkingSet.MergeState().IsCherryPick() {
+			mergeParentCommits = []*doltdb.Commit{sessionState.WorkingSet.MergeState().Commit()}
+		}
 	}
 
 	pendingCommit, err := actions.GetCommitStaged(ctx, roots, sessionState.WorkingSet.MergeActive(), mergeParentCommits, sessionState.dbData.Ddb, props)
@@ -474,6 +486,10 @@ func (d *DoltSession) NewPendingCommit(ctx *sql.Context, dbName string, roots do
 
 // RollbackTransaction rolls the given transaction back
 func (d *DoltSession) RollbackTransaction(ctx *sql.Context, dbName string, tx sql.Transaction) error {
+	if err := branch_control.RollbackTransaction(ctx); err != nil {
+		return err
+	}
+
 	if TransactionsDisabled(ctx) || dbName == "" {
 		return nil
 	}
@@ -641,7 +657,7 @@ func (d *DoltSession) ResolveRootForRef(ctx *sql.Context, dbName, hashStr string
 		return nil, nil, err
 	}
 
-	cm, err := dbData.Ddb.Resolve(ctx, cs, headRef)
+	cm, err := ResolveReadableCommit(ctx, dbData.Ddb, cs, headRef)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -778,24 +794,19 @@ func (d *DoltSession) SwitchWorkingSet(
 		return err
 	}
 
-	// TODO: just call SetWorkingSet?
-	sessionState.WorkingSet = ws
-
-	cs, err := doltdb.NewCommitSpec(ws.Ref().GetPath())
-	if err != nil {
-		return err
-	}
-
 	branchRef, err := ws.Ref().ToHeadRef()
 	if err != nil {
 		return err
 	}
 
-	cm, err := sessionState.dbData.Ddb.Resolve(ctx, cs, branchRef)
+	cm, err := ResolveReadableCommitRef(ctx, sessionState.dbData.Ddb, branchRef)
 	if err != nil {
 		return err
 	}
 
+	// TODO: just call SetWorkingSet?
+	sessionState.WorkingSet = ws
+
 	sessionState.headCommit = cm
 	sessionState.headRoot, err = cm.GetRootValue(ctx)
 	if err != nil {
@@ -39,6 +39,7 @@ const (
 
 var ErrRetryTransaction = errors.New("this transaction conflicts with a committed transaction from another client")
 var ErrUnresolvedConflictsCommit = errors.New("Merge conflict detected, transaction rolled back. Merge conflicts must be resolved using the dolt_conflicts tables before committing a transaction. To commit transactions with merge conflicts, set @@dolt_allow_commit_conflicts = 1")
+var ErrUnresolvedSchemaConflictsCommit = errors.New("Merge schema conflict detected, transaction rolled back. Schema conflicts must be resolved using dolt_conflicts_resolve before committing a transaction. To commit transactions with schema conflicts, set @@dolt_allow_commit_conflicts = 1")
 var ErrUnresolvedConstraintViolationsCommit = errors.New("Committing this transaction resulted in a working set with constraint violations, transaction rolled back. " +
 	"This constraint violation may be the result of a previous merge or the result of transaction sequencing. " +
 	"Constraint violations from a merge can be resolved using the dolt_constraint_violations table before committing the transaction. " +
@@ -378,6 +379,19 @@ func (tx *DoltTransaction) validateWorkingSetForCommit(ctx *sql.Context, working
 		}
 	}
 
+	// Schema conflicts are only ever created by a merge inside the transaction, so whether they may be committed is
+	// always a session setting
+	if workingSet.MergeActive() && workingSet.MergeState().HasSchemaConflicts() {
+		if !(allowCommitConflicts.(int8) == 1 || forceTransactionCommit.(int8) == 1) {
+			rollbackErr := tx.rollback(ctx)
+			if rollbackErr != nil {
+				return rollbackErr
+			}
+
+			return ErrUnresolvedSchemaConflictsCommit
+		}
+	}
+
 	if hasConstraintViolations {
 		// Constraint violations are acceptable in the working set if force
 		// transaction commit is enabled, regardless if an internal merge ( a
@@ -19,6 +19,8 @@ import (
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 )
 
 // Per-DB system variables
@@ -45,9 +47,14 @@ const (
 	ReplicateHeads                = "dolt_replicate_heads"
 	ReplicateAllHeads             = "dolt_replicate_all_heads"
 	AsyncReplication              = "dolt_async_replication"
+	ReplicateBranchControl        = "dolt_replicate_branch_control"
 	AwsCredsFile                  = "aws_credentials_file"
 	AwsCredsProfile               = "aws_credentials_profile"
 	AwsCredsRegion                = "aws_credentials_region"
+	LogRawCommitMetadata          = "dolt_log_raw_commit_metadata"
+	LogShowParents                = "dolt_log_show_parents"
+	LogDecorate                   = "dolt_log_decorate"
+	LogLegacyEmptyColumns         = "dolt_log_legacy_empty_columns"
 )
 
 const URLTemplateDatabasePlaceholder = "{database}"
@@ -146,6 +153,37 @@ func IsWorkingKey(key string) (bool, string) {
 	return false, ""
 }
 
+// IsDefaultBranchKey returns whether the given system variable is the default branch of a database, along with the name
+// of the database.
+func IsDefaultBranchKey(key string) (bool, string) {
+	if strings.HasSuffix(strings.ToLower(key), DefaultBranchKeySuffix) {
+		return true, key[:len(key)-len(DefaultBranchKeySuffix)]
+	}
+
+	return false, ""
+}
+
+// DefaultBranch returns the branch that new sessions of the given database begin on.
+func DefaultBranch(ctx *sql.Context, dbName string) string {
+	_, val, _ := sql.SystemVariables.GetGlobal(DefaultBranchKey(dbName))
+	return DefaultBranchForValue(ctx, dbName, val)
+}
+
+// DefaultBranchForValue returns the branch that new sessions of the given database would begin on if its default branch
+// variable had the given value. An empty value leaves new sessions on the branch checked out by the database's
+// repository.
+func DefaultBranchForValue(ctx *sql.Context, dbName string, val interface{}) string {
+	if branch, ok := val.(string); ok && len(branch) > 0 {
+		return strings.TrimPrefix(branch, ref.PrefixForType(ref.BranchRefType))
+	}
+	if sess, ok := ctx.Session.(*DoltSession); ok {
+		if dbData, ok := sess.GetDbData(ctx, dbName); ok {
+			return dbData.Rsr.CWBHeadRef().GetPath()
+		}
+	}
+	return ""
+}
+
 func IsReadOnlyVersionKey(key string) bool {
 	return strings.HasSuffix(key, HeadKeySuffix) ||
 		strings.HasSuffix(key, StagedKeySuffix) ||
@@ -0,0 +1,170 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/utils/cron"
+)
+
+var _ sql.Table = (*BackupsTable)(nil)
+
+// BackupsTable is a sql.Table implementation that implements a system table which shows the dolt backups, the
+// schedules that sql-server syncs them on, and the outcome of the last sync of each made by this server.
+type BackupsTable struct {
+	dbName string
+}
+
+// NewBackupsTable creates a BackupsTable
+func NewBackupsTable(_ *sql.Context, dbName string) sql.Table {
+	return &BackupsTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// BackupsTableName
+func (bt *BackupsTable) Name() string {
+	return doltdb.BackupsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// BackupsTableName
+func (bt *BackupsTable) String() string {
+	return doltdb.BackupsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the backups system table
+func (bt *BackupsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "name", Type: sql.Text, Source: doltdb.BackupsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "url", Type: sql.Text, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "params", Type: sql.JSON, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "schedule", Type: sql.Text, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "next_run_at", Type: sql.Datetime, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_run_at", Type: sql.Datetime, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_run_finished_at", Type: sql.Datetime, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_run_status", Type: sql.Text, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_run_error", Type: sql.Text, Source: doltdb.BackupsTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (bt *BackupsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (bt *BackupsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (bt *BackupsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	return NewBackupsItr(ctx, bt.dbName)
+}
+
+// BackupsItr is a sql.RowItr implementation which iterates over each backup as if it's a row in the table.
+type BackupsItr struct {
+	dbName    string
+	backups   []env.Remote
+	schedules map[string]string
+	idx       int
+}
+
+// NewBackupsItr creates a BackupsItr for the database named. Backups and their schedules are read from the repo
+// state file of the database, so that changes made outside this server, such as by `dolt backup add`, are shown.
+func NewBackupsItr(ctx *sql.Context, dbName string) (*BackupsItr, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+
+	var backupMap map[string]env.Remote
+	var schedules map[string]string
+	if fs, err := sess.Provider().FileSystemForDatabase(dbName); err == nil {
+		repoState, err := env.LoadRepoState(fs)
+		if err != nil {
+			return nil, err
+		}
+		backupMap, schedules = repoState.Backups, repoState.BackupSchedules
+	} else {
+		// revision databases and databases without a repo state file have only the backups loaded by the session
+		dbData, ok := sess.GetDbData(ctx, dbName)
+		if !ok {
+			return nil, sql.ErrDatabaseNotFound.New(dbName)
+		}
+		backupMap, err = dbData.Rsr.GetBackups()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	backups := make([]env.Remote, 0, len(backupMap))
+	for _, b := range backupMap {
+		backups = append(backups, b)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name < backups[j].Name
+	})
+
+	return &BackupsItr{dbName: dbName, backups: backups, schedules: schedules}, nil
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+// After retrieving the last row, Close will be automatically closed.
+func (itr *BackupsItr) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.backups) {
+		return nil, io.EOF
+	}
+	b := itr.backups[itr.idx]
+	itr.idx++
+
+	params, err := sql.JSON.Convert(b.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule, nextRunAt interface{}
+	if expr, ok := itr.schedules[b.Name]; ok {
+		schedule = expr
+		if s, err := cron.Parse(expr); err == nil {
+			if next := s.Next(ctx.QueryTime()); !next.IsZero() {
+				nextRunAt = next
+			}
+		}
+	}
+
+	var lastRunAt, lastRunFinishedAt, lastRunStatus, lastRunError interface{}
+	if run, ok := dsess.GetBackupRun(itr.dbName, b.Name); ok {
+		lastRunAt, lastRunStatus = run.StartedAt, run.Status
+		if !run.FinishedAt.IsZero() {
+			lastRunFinishedAt = run.FinishedAt
+		}
+		if run.Error != "" {
+			lastRunError = run.Error
+		}
+	}
+
+	return sql.NewRow(b.Name, b.Url, params, schedule, nextRunAt, lastRunAt, lastRunFinishedAt, lastRunStatus, lastRunError), nil
+}
+
+// Close closes the iterator.
+func (itr *BackupsItr) Close(*sql.Context) error {
+	return nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	RolesTableName = "dolt_branch_control_roles"
+)
+
+// rolesSchema is the schema for the "dolt_branch_control_roles" table.
+var rolesSchema = sql.Schema{
+	&sql.Column{
+		Name:       "role",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     RolesTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "user",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     RolesTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "host",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     RolesTableName,
+		PrimaryKey: true,
+	},
+}
+
+// BranchControlRolesTable provides a layer over the branch_control.Roles structure, exposing it as a system table. Each
+// row adds the users matching the user and host expressions to the role, and entries of the "dolt_branch_control"
+// table reference the role by using "@" followed by the role as their user expression. Unlike the other branch control
+// tables, modifications are made directly to the roles rather than to a copy held by the statement, so they are not
+// undone by a rollback. A statement that fails partway through instead undoes the modifications that it already made.
+type BranchControlRolesTable struct {
+	*branch_control.Roles
+	// statement is shared between every copy of the table, as the table is passed by value to the engine
+	statement *rolesStatement
+}
+
+// rolesStatement holds the functions that undo each modification made by the current statement, in the order that the
+// modifications were made. Each function requires the write lock to be held.
+type rolesStatement struct {
+	undos []func()
+}
+
+var _ sql.Table = BranchControlRolesTable{}
+var _ sql.InsertableTable = BranchControlRolesTable{}
+var _ sql.ReplaceableTable = BranchControlRolesTable{}
+var _ sql.UpdatableTable = BranchControlRolesTable{}
+var _ sql.DeletableTable = BranchControlRolesTable{}
+var _ sql.RowInserter = BranchControlRolesTable{}
+var _ sql.RowReplacer = BranchControlRolesTable{}
+var _ sql.RowUpdater = BranchControlRolesTable{}
+var _ sql.RowDeleter = BranchControlRolesTable{}
+
+// NewBranchControlRolesTable returns a new BranchControlRolesTable.
+func NewBranchControlRolesTable(roles *branch_control.Roles) BranchControlRolesTable {
+	return BranchControlRolesTable{Roles: roles, statement: &rolesStatement{}}
+}
+
+// Name implements the interface sql.Table.
+func (tbl BranchControlRolesTable) Name() string {
+	return RolesTableName
+}
+
+// String implements the interface sql.Table.
+func (tbl BranchControlRolesTable) String() string {
+	return RolesTableName
+}
+
+// Schema implements the interface sql.Table.
+func (tbl BranchControlRolesTable) Schema() sql.Schema {
+	return rolesSchema
+}
+
+// Collation implements the interface sql.Table.
+func (tbl BranchControlRolesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (tbl BranchControlRolesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table.
+func (tbl BranchControlRolesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	tbl.RWMutex.RLock()
+	defer tbl.RWMutex.RUnlock()
+
+	rows := make([]sql.Row, len(tbl.Values))
+	for i, member := range tbl.Values {
+		rows[i] = sql.Row{member.Role, member.User, member.Host}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// Inserter implements the interface sql.InsertableTable.
+func (tbl BranchControlRolesTable) Inserter(context *sql.Context) sql.RowInserter {
+	return tbl
+}
+
+// Replacer implements the interface sql.ReplaceableTable.
+func (tbl BranchControlRolesTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return tbl
+}
+
+// Updater implements the interface sql.UpdatableTable.
+func (tbl BranchControlRolesTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return tbl
+}
+
+// Deleter implements the interface sql.DeletableTable.
+func (tbl BranchControlRolesTable) Deleter(context *sql.Context) sql.RowDeleter {
+	return tbl
+}
+
+// StatementBegin implements the interface sql.TableEditor.
+func (tbl BranchControlRolesTable) StatementBegin(ctx *sql.Context) {
+	tbl.statement.undos = nil
+}
+
+// DiscardChanges implements the interface sql.TableEditor. The modifications made by the statement are undone in
+// reverse order, so that a failing statement does not leave some of its rows behind.
+func (tbl BranchControlRolesTable) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	for i := len(tbl.statement.undos) - 1; i >= 0; i-- {
+		tbl.statement.undos[i]()
+	}
+	tbl.statement.undos = nil
+	return nil
+}
+
+// StatementComplete implements the interface sql.TableEditor.
+func (tbl BranchControlRolesTable) StatementComplete(ctx *sql.Context) error {
+	tbl.statement.undos = nil
+	return nil
+}
+
+// Insert implements the interface sql.RowInserter.
+func (tbl BranchControlRolesTable) Insert(ctx *sql.Context, row sql.Row) error {
+	member, err := foldRoleMember(row)
+	if err != nil {
+		return err
+	}
+	// The permissions are checked before acquiring the lock, as matching the Access table reads the roles
+	if err = branch_control.CheckRoleEdit(ctx); err != nil {
+		return err
+	}
+	if err = branch_control.CheckRoleEscalation(ctx, member.Role); err != nil {
+		return err
+	}
+
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	return tbl.insert(ctx, member)
+}
+
+// Update implements the interface sql.RowUpdater.
+func (tbl BranchControlRolesTable) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	oldMember, err := foldRoleMember(old)
+	if err != nil {
+		return err
+	}
+	newMember, err := foldRoleMember(new)
+	if err != nil {
+		return err
+	}
+	if err = branch_control.CheckRoleEdit(ctx); err != nil {
+		return err
+	}
+	if err = branch_control.CheckRoleEscalation(ctx, newMember.Role); err != nil {
+		return err
+	}
+
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	// If we're not updating the same row, then we pre-emptively check for a row violation
+	if oldMember != newMember && tbl.GetIndex(newMember.Role, newMember.User, newMember.Host) != -1 {
+		return roleMemberUniqueKeyErr(newMember)
+	}
+	tbl.delete(oldMember)
+	return tbl.insert(ctx, newMember)
+}
+
+// Delete implements the interface sql.RowDeleter.
+func (tbl BranchControlRolesTable) Delete(ctx *sql.Context, row sql.Row) error {
+	member, err := foldRoleMember(row)
+	if err != nil {
+		return err
+	}
+	if err = branch_control.CheckRoleEdit(ctx); err != nil {
+		return err
+	}
+
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	tbl.delete(member)
+	return nil
+}
+
+// Close implements the interface sql.Closer.
+func (tbl BranchControlRolesTable) Close(context *sql.Context) error {
+	return branch_control.SaveData(context)
+}
+
+// insert adds the given member to the roles. Assumes that the expressions have already been folded. Requires the write
+// lock to be held.
+func (tbl BranchControlRolesTable) insert(ctx context.Context, member branch_control.RoleMember) error {
+	// If we already have this in the table, then we return a duplicate PK error
+	if tbl.GetIndex(member.Role, member.User, member.Host) != -1 {
+		return roleMemberUniqueKeyErr(member)
+	}
+	tbl.Roles.Insert(member)
+	tbl.statement.undos = append(tbl.statement.undos, func() {
+		tbl.Roles.Delete(member.Role, member.User, member.Host)
+	})
+	warnOnHostExpression(ctx, member.Host)
+	return nil
+}
+
+// delete removes the given member from the roles, if it exists. Assumes that the expressions have already been folded.
+// Requires the write lock to be held.
+func (tbl BranchControlRolesTable) delete(member branch_control.RoleMember) {
+	if tbl.Roles.Delete(member.Role, member.User, member.Host) {
+		tbl.statement.undos = append(tbl.statement.undos, func() {
+			tbl.Roles.Insert(member)
+		})
+	}
+}
+
+// foldRoleMember returns the member from the given row, folding the user and host expressions in the same way as the
+// "dolt_branch_control" table. The role is a name rather than an expression, so it is kept as is.
+func foldRoleMember(row sql.Row) (branch_control.RoleMember, error) {
+	role := row[0].(string)
+	// User is case-sensitive, while host is case-insensitive
+	user := branch_control.FoldExpression(row[1].(string))
+	host := strings.ToLower(branch_control.FoldExpression(row[2].(string)))
+
+	if len(role) == 0 {
+		return branch_control.RoleMember{}, branch_control.ErrEmptyRoleName.New()
+	}
+	if branch_control.IsRoleUser(user) {
+		return branch_control.RoleMember{}, branch_control.ErrNestedRole.New(role, strings.TrimPrefix(user, branch_control.RolePrefix))
+	}
+	// Verify that the lengths of each expression fit within an uint16
+	if len(branch_control.RoleUser(role)) > math.MaxUint16 || len(user) > math.MaxUint16 || len(host) > math.MaxUint16 {
+		return branch_control.RoleMember{}, branch_control.ErrExpressionsTooLong.New(role, user, host)
+	}
+	return branch_control.RoleMember{Role: role, User: user, Host: host}, nil
+}
+
+// roleMemberUniqueKeyErr returns the duplicate primary key error for the given member.
+func roleMemberUniqueKeyErr(member branch_control.RoleMember) error {
+	return sql.NewUniqueKeyErr(
+		fmt.Sprintf(`[%q, %q, %q]`, member.Role, member.User, member.Host),
+		true,
+		sql.Row{member.Role, member.User, member.Host})
+}
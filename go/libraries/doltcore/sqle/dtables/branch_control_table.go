@@ -19,12 +19,16 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/transform"
 	"github.com/dolthub/vitess/go/sqltypes"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
 )
 
 const (
@@ -33,7 +37,28 @@ const (
 
 // PermissionsStrings is a slice of strings representing the available branch_control.branch_control.Permissions. The order of the
 // strings should exactly match the order of the branch_control.Permissions according to their flag value.
-var PermissionsStrings = []string{"admin", "write"}
+var PermissionsStrings = []string{"admin", "write", "read"}
+
+// OperationsStrings is a slice of strings representing the available branch_control.Operations. The order of the
+// strings should exactly match the order of the branch_control.Operations according to their flag value.
+var OperationsStrings = []string{"all", "direct_dml", "merge", "ref_move", "tag"}
+
+// DaysStrings is a slice of strings representing the available branch_control.Days. The order of the strings should
+// exactly match the order of the branch_control.Days according to their flag value.
+var DaysStrings = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// RefsStrings is a slice of strings representing the available branch_control.Refs. The order of the strings should
+// exactly match the order of the branch_control.Refs according to their flag value.
+var RefsStrings = []string{"branches", "tags", "remotes"}
+
+// operationsType is the type of the "operations" column.
+var operationsType = sql.MustCreateSetType(OperationsStrings, sql.Collation_utf8mb4_0900_ai_ci)
+
+// daysType is the type of the "window_days" column.
+var daysType = sql.MustCreateSetType(DaysStrings, sql.Collation_utf8mb4_0900_ai_ci)
+
+// refsType is the type of the "refs" column.
+var refsType = sql.MustCreateSetType(RefsStrings, sql.Collation_utf8mb4_0900_ai_ci)
 
 // accessSchema is the schema for the "dolt_branch_control" table.
 var accessSchema = sql.Schema{
@@ -61,11 +86,96 @@ var accessSchema = sql.Schema{
 		Source:     AccessTableName,
 		PrimaryKey: false,
 	},
+	&sql.Column{
+		Name:       "operations",
+		Type:       operationsType,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral("all", sql.LongText), operationsType),
+	},
+	&sql.Column{
+		Name:       "priority",
+		Type:       sql.Int64,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral(int64(0), sql.Int64), sql.Int64),
+	},
+	&sql.Column{
+		Name:       "window_start",
+		Type:       sql.Time,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral("00:00:00", sql.LongText), sql.Time),
+	},
+	&sql.Column{
+		Name:       "window_end",
+		Type:       sql.Time,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral("24:00:00", sql.LongText), sql.Time),
+	},
+	&sql.Column{
+		Name:       "window_days",
+		Type:       daysType,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral(strings.Join(DaysStrings, ","), sql.LongText), daysType),
+	},
+	&sql.Column{
+		Name:       "requires_approval",
+		Type:       sql.Boolean,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral(false, sql.Boolean), sql.Boolean),
+	},
+	&sql.Column{
+		Name:       "expires_at",
+		Type:       sql.Datetime,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Nullable:   true,
+	},
+	&sql.Column{
+		Name:       "refs",
+		Type:       refsType,
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Default:    mustCreateLiteralDefault(expression.NewLiteral("branches", sql.LongText), refsType),
+	},
+	&sql.Column{
+		Name:       "table_pattern",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     AccessTableName,
+		PrimaryKey: false,
+		Nullable:   true,
+	},
+}
+
+// mustCreateLiteralDefault returns a column default for the given literal. Panics if the default is invalid.
+func mustCreateLiteralDefault(lit *expression.Literal, typ sql.Type) *sql.ColumnDefaultValue {
+	def, err := sql.NewColumnDefaultValue(lit, typ, true, false, false)
+	if err != nil {
+		panic(err)
+	}
+	return def
 }
 
+// accessExpressionColumns are the columns of the "dolt_branch_control" table that hold folded expressions.
+var accessExpressionColumns = set.NewStrSet([]string{"branch", "user", "host"})
+
 // BranchControlTable provides a layer over the branch_control.Access structure, exposing it as a system table.
 type BranchControlTable struct {
 	*branch_control.Access
+	// statement is shared between every copy of the table, as the table is passed by value to the engine
+	statement   *accessStatement
+	filters     []sql.Expression
+	projections []string
+}
+
+// accessStatement holds the copy of the Access table that is modified by the current statement. The copy is nil
+// when no statement is in progress, in which case modifications are made directly to the Access table.
+type accessStatement struct {
+	access *branch_control.Access
 }
 
 var _ sql.Table = BranchControlTable{}
@@ -77,10 +187,12 @@ var _ sql.RowInserter = BranchControlTable{}
 var _ sql.RowReplacer = BranchControlTable{}
 var _ sql.RowUpdater = BranchControlTable{}
 var _ sql.RowDeleter = BranchControlTable{}
+var _ sql.FilteredTable = BranchControlTable{}
+var _ sql.ProjectedTable = BranchControlTable{}
 
 // NewBranchControlTable returns a new BranchControlTable.
 func NewBranchControlTable(access *branch_control.Access) BranchControlTable {
-	return BranchControlTable{access}
+	return BranchControlTable{Access: access, statement: &accessStatement{}}
 }
 
 // Name implements the interface sql.Table.
@@ -113,16 +225,59 @@ func (tbl BranchControlTable) PartitionRows(context *sql.Context, partition sql.
 	tbl.RWMutex.RLock()
 	defer tbl.RWMutex.RUnlock()
 
-	rows := []sql.Row{{"%", tbl.SuperUser, tbl.SuperHost, uint64(branch_control.Permissions_Admin)}}
+	rows := []sql.Row{accessRow("%", tbl.SuperUser, tbl.SuperHost, uint64(branch_control.Permissions_Admin), uint64(branch_control.Operations_All), int64(0), branch_control.Window{}, false, 0, 0, "")}
 	for _, value := range tbl.Values {
-		rows = append(rows, sql.Row{
-			value.Branch,
-			value.User,
-			value.Host,
-			uint64(value.Permissions),
-		})
+		rows = append(rows, accessRowFromValue(value))
 	}
-	return sql.RowsToRowIter(rows...), nil
+	if len(tbl.filters) == 0 {
+		return sql.RowsToRowIter(rows...), nil
+	}
+
+	filteredRows := make([]sql.Row, 0, len(rows))
+	for _, row := range rows {
+		matches, err := sql.EvaluateCondition(context, expression.JoinAnd(tbl.filters...), row)
+		if err != nil {
+			return nil, err
+		}
+		if sql.IsTrue(matches) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return sql.RowsToRowIter(filteredRows...), nil
+}
+
+// Filters implements the interface sql.FilteredTable.
+func (tbl BranchControlTable) Filters() []sql.Expression {
+	return tbl.filters
+}
+
+// HandledFilters implements the interface sql.FilteredTable. Only filters on the expression columns are handled, as
+// those are the filters whose literals must be folded.
+func (tbl BranchControlTable) HandledFilters(filters []sql.Expression) []sql.Expression {
+	return FilterFilters(filters, ColumnPredicate(accessExpressionColumns))
+}
+
+// WithFilters implements the interface sql.FilteredTable. String literals compared against the expression columns are
+// folded in the same way as Insert, so that a filter written in any equivalent form matches the stored expression.
+func (tbl BranchControlTable) WithFilters(ctx *sql.Context, filters []sql.Expression) sql.Table {
+	handled := tbl.HandledFilters(filters)
+	tbl.filters = make([]sql.Expression, len(handled))
+	for i, filter := range handled {
+		tbl.filters[i] = foldExpressionFilter(filter)
+	}
+	return tbl
+}
+
+// Projections implements the interface sql.ProjectedTable.
+func (tbl BranchControlTable) Projections() []string {
+	return tbl.projections
+}
+
+// WithProjections implements the interface sql.ProjectedTable. As the table is held entirely in memory, every column is
+// still returned.
+func (tbl BranchControlTable) WithProjections(colNames []string) sql.Table {
+	tbl.projections = colNames
+	return tbl
 }
 
 // Inserter implements the interface sql.InsertableTable.
@@ -145,38 +300,70 @@ func (tbl BranchControlTable) Deleter(context *sql.Context) sql.RowDeleter {
 	return tbl
 }
 
-// StatementBegin implements the interface sql.TableEditor.
+// StatementBegin implements the interface sql.TableEditor. The statement modifies a copy of the Access table, so that
+// neither concurrent readers nor a failure partway through the statement may observe a partially applied statement.
 func (tbl BranchControlTable) StatementBegin(ctx *sql.Context) {
-	//TODO: will use the binlog to implement
+	tbl.RWMutex.RLock()
+	defer tbl.RWMutex.RUnlock()
+	tbl.statement.access = tbl.Access.Clone()
 }
 
 // DiscardChanges implements the interface sql.TableEditor.
 func (tbl BranchControlTable) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
-	//TODO: will use the binlog to implement
+	tbl.statement.access = nil
 	return nil
 }
 
 // StatementComplete implements the interface sql.TableEditor.
 func (tbl BranchControlTable) StatementComplete(ctx *sql.Context) error {
-	//TODO: will use the binlog to implement
+	if tbl.statement.access != nil {
+		tbl.Access.Apply(ctx, tbl.statement.access)
+		tbl.statement.access = nil
+	}
 	return nil
 }
 
 // Insert implements the interface sql.RowInserter.
 func (tbl BranchControlTable) Insert(ctx *sql.Context, row sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	access := tbl.editing()
+	access.RWMutex.Lock()
+	defer access.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	branch := strings.ToLower(branch_control.FoldExpression(row[0].(string)))
 	user := branch_control.FoldExpression(row[1].(string))
 	host := strings.ToLower(branch_control.FoldExpression(row[2].(string)))
 	perms := branch_control.Permissions(row[3].(uint64))
+	ops := branch_control.Operations(row[4].(uint64))
+	priority := row[5].(int64)
+	window, err := windowFromRow(row)
+	if err != nil {
+		return err
+	}
+	requiresApproval, err := sql.ConvertToBool(row[9])
+	if err != nil {
+		return err
+	}
+	expiresAt := expiresAtFromRow(row)
+	refs, err := refsFromRow(row)
+	if err != nil {
+		return err
+	}
+	tablePattern := tablePatternFromRow(row)
 
 	// Verify that the lengths of each expression fit within an uint16
 	if len(branch) > math.MaxUint16 || len(user) > math.MaxUint16 || len(host) > math.MaxUint16 {
 		return branch_control.ErrExpressionsTooLong.New(branch, user, host)
 	}
+	if len(tablePattern) > math.MaxUint16 {
+		return branch_control.ErrTablePatternTooLong.New(tablePattern)
+	}
+
+	// Admin over the escalation constraints may only be granted by the super user, regardless of the inserter's own
+	// permissions
+	if err := branch_control.CheckEscalation(ctx, branch, perms); err != nil {
+		return err
+	}
 
 	// A nil session means we're not in the SQL context, so we allow the insertion in such a case
 	if branchAwareSession := branch_control.GetBranchAwareSession(ctx); branchAwareSession != nil {
@@ -184,32 +371,40 @@ func (tbl BranchControlTable) Insert(ctx *sql.Context, row sql.Row) error {
 		insertHost := branchAwareSession.GetHost()
 		// As we've folded the branch expression, we can use it directly as though it were a normal branch name to
 		// determine if the user attempting the insertion has permission to perform the insertion.
-		_, modPerms := tbl.Match(branch, insertUser, insertHost)
+		_, modPerms := access.Match(branch, insertUser, insertHost)
 		if modPerms&branch_control.Permissions_Admin != branch_control.Permissions_Admin {
 			permStr, _ := accessSchema[3].Type.(sql.SetType).BitsToString(uint64(perms))
 			return branch_control.ErrInsertingRow.New(insertUser, insertHost, branch, user, host, permStr)
 		}
 	}
 
+	// Entries that affect a frozen branch may not be modified, as they could otherwise be used to circumvent the freeze
+	if err := branch_control.CheckRuleEdit(ctx, branch); err != nil {
+		return err
+	}
+
 	// We check if we're inserting a subset of an already-existing row. If we are, we deny the insertion as the existing
 	// row will already match against ALL possible values for this row.
-	_, modPerms := tbl.Match(branch, user, host)
+	_, modPerms := access.Match(branch, user, host)
 	if modPerms&branch_control.Permissions_Admin == branch_control.Permissions_Admin {
 		permBits := uint64(modPerms)
 		permStr, _ := accessSchema[3].Type.(sql.SetType).BitsToString(permBits)
 		return sql.NewUniqueKeyErr(
 			fmt.Sprintf(`[%q, %q, %q, %q]`, branch, user, host, permStr),
 			true,
-			sql.Row{branch, user, host, permBits})
+			accessRow(branch, user, host, permBits, uint64(branch_control.Operations_All), int64(0), branch_control.Window{}, false, 0, 0, ""))
 	}
 
-	return tbl.insert(ctx, branch, user, host, perms)
+	return tbl.insert(ctx, access, branch_control.AccessValue{Branch: branch, User: user, Host: host, Permissions: perms, Operations: ops,
+		Priority: priority, Window: window, RequiresApproval: requiresApproval, ExpiresAt: expiresAt, Refs: refs,
+		Table: tablePattern})
 }
 
 // Update implements the interface sql.RowUpdater.
 func (tbl BranchControlTable) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	access := tbl.editing()
+	access.RWMutex.Lock()
+	defer access.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	oldBranch := strings.ToLower(branch_control.FoldExpression(old[0].(string)))
@@ -219,65 +414,97 @@ func (tbl BranchControlTable) Update(ctx *sql.Context, old sql.Row, new sql.Row)
 	newUser := branch_control.FoldExpression(new[1].(string))
 	newHost := strings.ToLower(branch_control.FoldExpression(new[2].(string)))
 	newPerms := branch_control.Permissions(new[3].(uint64))
+	newOps := branch_control.Operations(new[4].(uint64))
+	newPriority := new[5].(int64)
+	newWindow, err := windowFromRow(new)
+	if err != nil {
+		return err
+	}
+	newRequiresApproval, err := sql.ConvertToBool(new[9])
+	if err != nil {
+		return err
+	}
+	newExpiresAt := expiresAtFromRow(new)
+	newRefs, err := refsFromRow(new)
+	if err != nil {
+		return err
+	}
+	newTablePattern := tablePatternFromRow(new)
 
 	// Verify that the lengths of each expression fit within an uint16
 	if len(newBranch) > math.MaxUint16 || len(newUser) > math.MaxUint16 || len(newHost) > math.MaxUint16 {
 		return branch_control.ErrExpressionsTooLong.New(newBranch, newUser, newHost)
 	}
+	if len(newTablePattern) > math.MaxUint16 {
+		return branch_control.ErrTablePatternTooLong.New(newTablePattern)
+	}
 
 	// If we're not updating the same row, then we pre-emptively check for a row violation
 	if oldBranch != newBranch || oldUser != newUser || oldHost != newHost {
-		if tblIndex := tbl.GetIndex(newBranch, newUser, newHost); tblIndex != -1 {
-			permBits := uint64(tbl.Values[tblIndex].Permissions)
+		if tblIndex := access.GetIndex(newBranch, newUser, newHost); tblIndex != -1 {
+			permBits := uint64(access.Values[tblIndex].Permissions)
 			permStr, _ := accessSchema[3].Type.(sql.SetType).BitsToString(permBits)
 			return sql.NewUniqueKeyErr(
 				fmt.Sprintf(`[%q, %q, %q, %q]`, newBranch, newUser, newHost, permStr),
 				true,
-				sql.Row{newBranch, newUser, newHost, permBits})
+				accessRowFromValue(access.Values[tblIndex]))
 		}
 	}
 
+	// The updated entry is checked against the escalation constraints in the same way as an inserted entry
+	if err := branch_control.CheckEscalation(ctx, newBranch, newPerms); err != nil {
+		return err
+	}
+
 	// A nil session means we're not in the SQL context, so we'd allow the update in such a case
 	if branchAwareSession := branch_control.GetBranchAwareSession(ctx); branchAwareSession != nil {
 		insertUser := branchAwareSession.GetUser()
 		insertHost := branchAwareSession.GetHost()
 		// As we've folded the branch expression, we can use it directly as though it were a normal branch name to
 		// determine if the user attempting the update has permission to perform the update on the old branch name.
-		_, modPerms := tbl.Match(oldBranch, insertUser, insertHost)
+		_, modPerms := access.Match(oldBranch, insertUser, insertHost)
 		if modPerms&branch_control.Permissions_Admin != branch_control.Permissions_Admin {
 			return branch_control.ErrUpdatingRow.New(insertUser, insertHost, oldBranch, oldUser, oldHost)
 		}
 		// Now we check if the user has permission use the new branch name
-		_, modPerms = tbl.Match(newBranch, insertUser, insertHost)
+		_, modPerms = access.Match(newBranch, insertUser, insertHost)
 		if modPerms&branch_control.Permissions_Admin != branch_control.Permissions_Admin {
 			return branch_control.ErrUpdatingToRow.New(insertUser, insertHost, oldBranch, oldUser, oldHost, newBranch)
 		}
 	}
 
+	// Neither the old nor the new entry may affect a frozen branch
+	if err := branch_control.CheckRuleEdit(ctx, oldBranch, newBranch); err != nil {
+		return err
+	}
+
 	// We check if we're updating to a subset of an already-existing row. If we are, we deny the update as the existing
 	// row will already match against ALL possible values for this updated row.
-	_, modPerms := tbl.Match(newBranch, newUser, newHost)
+	_, modPerms := access.Match(newBranch, newUser, newHost)
 	if modPerms&branch_control.Permissions_Admin == branch_control.Permissions_Admin {
 		permBits := uint64(modPerms)
 		permStr, _ := accessSchema[3].Type.(sql.SetType).BitsToString(permBits)
 		return sql.NewUniqueKeyErr(
 			fmt.Sprintf(`[%q, %q, %q, %q]`, newBranch, newUser, newHost, permStr),
 			true,
-			sql.Row{newBranch, newUser, newHost, permBits})
+			accessRow(newBranch, newUser, newHost, permBits, uint64(branch_control.Operations_All), int64(0), branch_control.Window{}, false, 0, 0, ""))
 	}
 
-	if tblIndex := tbl.GetIndex(oldBranch, oldUser, oldHost); tblIndex != -1 {
-		if err := tbl.delete(ctx, oldBranch, oldUser, oldHost); err != nil {
+	if tblIndex := access.GetIndex(oldBranch, oldUser, oldHost); tblIndex != -1 {
+		if err := tbl.delete(ctx, access, oldBranch, oldUser, oldHost); err != nil {
 			return err
 		}
 	}
-	return tbl.insert(ctx, newBranch, newUser, newHost, newPerms)
+	return tbl.insert(ctx, access, branch_control.AccessValue{Branch: newBranch, User: newUser, Host: newHost, Permissions: newPerms,
+		Operations: newOps, Priority: newPriority, Window: newWindow, RequiresApproval: newRequiresApproval, ExpiresAt: newExpiresAt,
+		Refs: newRefs, Table: newTablePattern})
 }
 
 // Delete implements the interface sql.RowDeleter.
 func (tbl BranchControlTable) Delete(ctx *sql.Context, row sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	access := tbl.editing()
+	access.RWMutex.Lock()
+	defer access.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	branch := strings.ToLower(branch_control.FoldExpression(row[0].(string)))
@@ -290,69 +517,209 @@ func (tbl BranchControlTable) Delete(ctx *sql.Context, row sql.Row) error {
 		insertHost := branchAwareSession.GetHost()
 		// As we've folded the branch expression, we can use it directly as though it were a normal branch name to
 		// determine if the user attempting the deletion has permission to perform the deletion.
-		_, modPerms := tbl.Match(branch, insertUser, insertHost)
+		_, modPerms := access.Match(branch, insertUser, insertHost)
 		if modPerms&branch_control.Permissions_Admin != branch_control.Permissions_Admin {
 			return branch_control.ErrDeletingRow.New(insertUser, insertHost, branch, user, host)
 		}
 	}
 
-	return tbl.delete(ctx, branch, user, host)
+	// Removing an entry may not be used to circumvent a freeze either
+	if err := branch_control.CheckRuleEdit(ctx, branch); err != nil {
+		return err
+	}
+
+	return tbl.delete(ctx, access, branch, user, host)
 }
 
-// Close implements the interface sql.Closer.
+// Close implements the interface sql.Closer. Changes made within an explicit transaction are saved once the
+// transaction commits.
 func (tbl BranchControlTable) Close(context *sql.Context) error {
+	if branch_control.InTransaction(context) {
+		return nil
+	}
 	return branch_control.SaveData(context)
 }
 
-// insert adds the given branch, user, and host expression strings to the table. Assumes that the expressions have
-// already been folded.
-func (tbl BranchControlTable) insert(ctx context.Context, branch string, user string, host string, perms branch_control.Permissions) error {
+// editing returns the Access table that modifications are made to, which is the statement's copy while a statement is
+// in progress.
+func (tbl BranchControlTable) editing() *branch_control.Access {
+	if tbl.statement != nil && tbl.statement.access != nil {
+		return tbl.statement.access
+	}
+	return tbl.Access
+}
+
+// insert adds the given entry to the table. Assumes that the expressions have already been folded, and that the window
+// has already been validated.
+func (tbl BranchControlTable) insert(ctx context.Context, access *branch_control.Access, value branch_control.AccessValue) error {
 	// If we already have this in the table, then we return a duplicate PK error
-	if tblIndex := tbl.GetIndex(branch, user, host); tblIndex != -1 {
-		permBits := uint64(tbl.Values[tblIndex].Permissions)
+	if tblIndex := access.GetIndex(value.Branch, value.User, value.Host); tblIndex != -1 {
+		permBits := uint64(access.Values[tblIndex].Permissions)
 		permStr, _ := accessSchema[3].Type.(sql.SetType).BitsToString(permBits)
 		return sql.NewUniqueKeyErr(
-			fmt.Sprintf(`[%q, %q, %q, %q]`, branch, user, host, permStr),
+			fmt.Sprintf(`[%q, %q, %q, %q]`, value.Branch, value.User, value.Host, permStr),
 			true,
-			sql.Row{branch, user, host, permBits})
-	}
-
-	// Add the expressions to their respective slices
-	branchExpr := branch_control.ParseExpression(branch, sql.Collation_utf8mb4_0900_ai_ci)
-	userExpr := branch_control.ParseExpression(user, sql.Collation_utf8mb4_0900_bin)
-	hostExpr := branch_control.ParseExpression(host, sql.Collation_utf8mb4_0900_ai_ci)
-	nextIdx := uint32(len(tbl.Values))
-	tbl.Branches = append(tbl.Branches, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: branchExpr})
-	tbl.Users = append(tbl.Users, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: userExpr})
-	tbl.Hosts = append(tbl.Hosts, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: hostExpr})
-	tbl.Values = append(tbl.Values, branch_control.AccessValue{
-		Branch:      branch,
-		User:        user,
-		Host:        host,
-		Permissions: perms,
-	})
+			accessRowFromValue(access.Values[tblIndex]))
+	}
+
+	access.Insert(value)
+	warnOnHostExpression(ctx, value.Host)
 	return nil
 }
 
+// warnOnHostExpression warns the session when the given folded host expression is unlikely to match the clients that it
+// was written for, as the server identifies clients by a different form of host.
+func warnOnHostExpression(ctx context.Context, host string) {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok {
+		return
+	}
+	if warning := branch_control.HostExpressionWarning(host); len(warning) > 0 {
+		sqlCtx.Warn(1105, "%s", warning)
+	}
+}
+
+// accessRow returns a row of the "dolt_branch_control" table from the given values.
+func accessRow(branch string, user string, host string, perms uint64, ops uint64, priority int64, window branch_control.Window, requiresApproval bool, expiresAt int64, refs branch_control.Refs, tablePattern string) sql.Row {
+	windowStart, windowEnd, windowDays := windowToRowValues(window)
+	var expiresAtValue interface{}
+	if expiresAt != 0 {
+		expiresAtValue = time.UnixMilli(expiresAt).UTC()
+	}
+	var tablePatternValue interface{}
+	if len(tablePattern) > 0 {
+		tablePatternValue = tablePattern
+	}
+	return sql.Row{branch, user, host, perms, ops, priority, windowStart, windowEnd, windowDays, requiresApproval, expiresAtValue,
+		uint64(refs.OrBranches()), tablePatternValue}
+}
+
+// accessRowFromValue returns a row of the "dolt_branch_control" table from the given value.
+func accessRowFromValue(value branch_control.AccessValue) sql.Row {
+	return accessRow(value.Branch, value.User, value.Host, uint64(value.Permissions), uint64(value.Operations), value.Priority, value.Window, value.RequiresApproval, value.ExpiresAt, value.Refs, value.Table)
+}
+
+// windowToRowValues returns the values of the window columns for the given window. The end of the day is displayed as
+// 24:00:00, and every day of the week is displayed when the window applies to all days.
+func windowToRowValues(window branch_control.Window) (sql.Timespan, sql.Timespan, uint64) {
+	start := sql.Time.MicrosecondsToTimespan(int64(window.Start) * 1000000)
+	end := sql.Time.MicrosecondsToTimespan(int64(window.EndOrMax()) * 1000000)
+	return start, end, uint64(window.DaysOrAll())
+}
+
+// windowFromRow returns the window represented by the window columns of the given row. Fractional seconds are truncated.
+func windowFromRow(row sql.Row) (branch_control.Window, error) {
+	start := row[6].(sql.Timespan).AsMicroseconds() / 1000000
+	end := row[7].(sql.Timespan).AsMicroseconds() / 1000000
+	days := row[8].(uint64)
+	if start < 0 || end < 0 {
+		return branch_control.Window{}, branch_control.ErrInvalidWindow.New(start, end)
+	}
+	// An empty set would otherwise be read as every day, so it is rejected rather than silently widening the window
+	if days == 0 {
+		return branch_control.Window{}, branch_control.ErrInvalidWindowDays.New(days)
+	}
+	return branch_control.NewWindow(uint32(start), uint32(end), branch_control.Days(days))
+}
+
+// expiresAtFromRow returns the expiration of the given row in milliseconds since the Unix epoch, where a NULL expiration
+// never expires.
+func expiresAtFromRow(row sql.Row) int64 {
+	if row[10] == nil {
+		return 0
+	}
+	return row[10].(time.Time).UnixMilli()
+}
+
+// tablePatternFromRow returns the folded table pattern of the given row. Table names are case-insensitive, so the
+// pattern is folded in the same way as the branch. A NULL or empty pattern applies the entry to every table.
+func tablePatternFromRow(row sql.Row) string {
+	if row[12] == nil {
+		return ""
+	}
+	return strings.ToLower(branch_control.FoldExpression(row[12].(string)))
+}
+
+// refsFromRow returns the refs of the given row, with only branches stored as the zero value.
+func refsFromRow(row sql.Row) (branch_control.Refs, error) {
+	refs := branch_control.Refs(row[11].(uint64))
+	// An empty set would otherwise be read as only branches, so it is rejected in the same way as the window days
+	if refs == 0 {
+		return 0, branch_control.ErrInvalidRefs.New(refs)
+	}
+	return branch_control.NewRefs(refs), nil
+}
+
 // delete removes the given branch, user, and host expression strings from the table. Assumes that the expressions have
 // already been folded.
-func (tbl BranchControlTable) delete(ctx context.Context, branch string, user string, host string) error {
-	// If we don't have this in the table, then we just return
-	tblIndex := tbl.GetIndex(branch, user, host)
-	if tblIndex == -1 {
-		return nil
+func (tbl BranchControlTable) delete(ctx context.Context, access *branch_control.Access, branch string, user string, host string) error {
+	access.Delete(branch, user, host)
+	return nil
+}
+
+// foldExpressionFilter returns the filter with every string literal that is compared against an expression column
+// folded as Insert would fold it. Branch and host are lowercased, while user remains case-sensitive.
+func foldExpressionFilter(filter sql.Expression) sql.Expression {
+	folded, _, err := transform.Expr(filter, func(e sql.Expression) (sql.Expression, transform.TreeIdentity, error) {
+		children := e.Children()
+		colName := ""
+		for _, child := range children {
+			if gf, ok := child.(*expression.GetField); ok {
+				colName = strings.ToLower(gf.Name())
+				break
+			}
+		}
+		if !accessExpressionColumns.Contains(colName) {
+			return e, transform.SameTree, nil
+		}
+
+		newChildren := make([]sql.Expression, len(children))
+		changed := false
+		for i, child := range children {
+			if tuple, ok := child.(expression.Tuple); ok {
+				newTuple := make(expression.Tuple, len(tuple))
+				for j, tupleChild := range tuple {
+					var foldedChild bool
+					newTuple[j], foldedChild = foldExpressionLiteral(colName, tupleChild)
+					changed = changed || foldedChild
+				}
+				newChildren[i] = newTuple
+			} else {
+				var foldedChild bool
+				newChildren[i], foldedChild = foldExpressionLiteral(colName, child)
+				changed = changed || foldedChild
+			}
+		}
+		if !changed {
+			return e, transform.SameTree, nil
+		}
+		newExpr, err := e.WithChildren(newChildren...)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		return newExpr, transform.NewTree, nil
+	})
+	if err != nil {
+		// Folding only replaces literals, so the original filter is still a valid (if stricter) filter
+		return filter
 	}
+	return folded
+}
 
-	endIndex := len(tbl.Values) - 1
-	// Remove the matching row from all slices by first swapping with the last element
-	tbl.Branches[tblIndex], tbl.Branches[endIndex] = tbl.Branches[endIndex], tbl.Branches[tblIndex]
-	tbl.Users[tblIndex], tbl.Users[endIndex] = tbl.Users[endIndex], tbl.Users[tblIndex]
-	tbl.Hosts[tblIndex], tbl.Hosts[endIndex] = tbl.Hosts[endIndex], tbl.Hosts[tblIndex]
-	tbl.Values[tblIndex], tbl.Values[endIndex] = tbl.Values[endIndex], tbl.Values[tblIndex]
-	// Then we remove the last element
-	tbl.Branches = tbl.Branches[:endIndex]
-	tbl.Users = tbl.Users[:endIndex]
-	tbl.Hosts = tbl.Hosts[:endIndex]
-	tbl.Values = tbl.Values[:endIndex]
-	return nil
+// foldExpressionLiteral folds the given expression if it is a string literal, returning it unchanged otherwise. Also
+// returns whether the expression was replaced.
+func foldExpressionLiteral(colName string, expr sql.Expression) (sql.Expression, bool) {
+	lit, ok := expr.(*expression.Literal)
+	if !ok {
+		return expr, false
+	}
+	str, ok := lit.Value().(string)
+	if !ok {
+		return expr, false
+	}
+	str = branch_control.FoldExpression(str)
+	if colName != "user" {
+		str = strings.ToLower(str)
+	}
+	return expression.NewLiteral(str, lit.Type()), true
 }
@@ -0,0 +1,107 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	FreezeTableName = "dolt_branch_freeze"
+)
+
+// freezeSchema is the schema for the "dolt_branch_freeze" table.
+var freezeSchema = sql.Schema{
+	&sql.Column{
+		Name:       "branch",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     FreezeTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "frozen_by",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     FreezeTableName,
+		PrimaryKey: false,
+	},
+	&sql.Column{
+		Name:       "created_at",
+		Type:       sql.Datetime,
+		Source:     FreezeTableName,
+		PrimaryKey: false,
+	},
+	&sql.Column{
+		Name:       "reason",
+		Type:       sql.LongText,
+		Source:     FreezeTableName,
+		PrimaryKey: false,
+	},
+}
+
+// FreezeTable provides a read-only view over the branch_control.Freezes, which are modified through the
+// DOLT_BRANCH_FREEZE and DOLT_BRANCH_UNFREEZE procedures.
+type FreezeTable struct {
+	*branch_control.Freezes
+}
+
+var _ sql.Table = FreezeTable{}
+
+// NewFreezeTable returns a new FreezeTable.
+func NewFreezeTable(freezes *branch_control.Freezes) FreezeTable {
+	return FreezeTable{Freezes: freezes}
+}
+
+// Name implements the interface sql.Table.
+func (tbl FreezeTable) Name() string {
+	return FreezeTableName
+}
+
+// String implements the interface sql.Table.
+func (tbl FreezeTable) String() string {
+	return FreezeTableName
+}
+
+// Schema implements the interface sql.Table.
+func (tbl FreezeTable) Schema() sql.Schema {
+	return freezeSchema
+}
+
+// Collation implements the interface sql.Table.
+func (tbl FreezeTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (tbl FreezeTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table.
+func (tbl FreezeTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	tbl.RWMutex.RLock()
+	defer tbl.RWMutex.RUnlock()
+
+	rows := make([]sql.Row, len(tbl.Values))
+	for i, freeze := range tbl.Values {
+		rows[i] = sql.Row{freeze.Branch, fmt.Sprintf("%s@%s", freeze.User, freeze.Host), freeze.CreatedAt, freeze.Reason}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
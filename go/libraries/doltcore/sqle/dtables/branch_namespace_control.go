@@ -51,12 +51,29 @@ var namespaceSchema = sql.Schema{
 		Source:     NamespaceTableName,
 		PrimaryKey: true,
 	},
+	// inherits_from is computed from the other entries, so any value that is written to it is ignored
+	&sql.Column{
+		Name:       "inherits_from",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     NamespaceTableName,
+		PrimaryKey: false,
+		Nullable:   true,
+	},
 }
 
 // BranchNamespaceControlTable provides a layer over the branch_control.Namespace structure, exposing it as a system
-// table.
+// table. Each row displays the namespace that its branch expression inherits from, which is the namespace whose entries
+// applied to the row's branches before the row's namespace was given entries of its own.
 type BranchNamespaceControlTable struct {
 	*branch_control.Namespace
+	// statement is shared between every copy of the table, as the table is passed by value to the engine
+	statement *namespaceStatement
+}
+
+// namespaceStatement holds the copy of the Namespace table that is modified by the current statement, following the
+// same rules as accessStatement.
+type namespaceStatement struct {
+	namespace *branch_control.Namespace
 }
 
 var _ sql.Table = BranchNamespaceControlTable{}
@@ -71,7 +88,7 @@ var _ sql.RowDeleter = BranchNamespaceControlTable{}
 
 // NewBranchNamespaceControlTable returns a new BranchNamespaceControlTable.
 func NewBranchNamespaceControlTable(namespace *branch_control.Namespace) BranchNamespaceControlTable {
-	return BranchNamespaceControlTable{namespace}
+	return BranchNamespaceControlTable{Namespace: namespace, statement: &namespaceStatement{}}
 }
 
 // Name implements the interface sql.Table.
@@ -106,10 +123,15 @@ func (tbl BranchNamespaceControlTable) PartitionRows(context *sql.Context, parti
 
 	var rows []sql.Row
 	for _, value := range tbl.Values {
+		var inheritsFrom interface{}
+		if parent, ok := tbl.InheritsFrom(value.Branch); ok {
+			inheritsFrom = parent
+		}
 		rows = append(rows, sql.Row{
 			value.Branch,
 			value.User,
 			value.Host,
+			inheritsFrom,
 		})
 	}
 	return sql.RowsToRowIter(rows...), nil
@@ -135,27 +157,34 @@ func (tbl BranchNamespaceControlTable) Deleter(context *sql.Context) sql.RowDele
 	return tbl
 }
 
-// StatementBegin implements the interface sql.TableEditor.
+// StatementBegin implements the interface sql.TableEditor. As with the Access table, the statement modifies a copy of
+// the Namespace table.
 func (tbl BranchNamespaceControlTable) StatementBegin(ctx *sql.Context) {
-	//TODO: will use the binlog to implement
+	tbl.RWMutex.RLock()
+	defer tbl.RWMutex.RUnlock()
+	tbl.statement.namespace = tbl.Namespace.Clone()
 }
 
 // DiscardChanges implements the interface sql.TableEditor.
 func (tbl BranchNamespaceControlTable) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
-	//TODO: will use the binlog to implement
+	tbl.statement.namespace = nil
 	return nil
 }
 
 // StatementComplete implements the interface sql.TableEditor.
 func (tbl BranchNamespaceControlTable) StatementComplete(ctx *sql.Context) error {
-	//TODO: will use the binlog to implement
+	if tbl.statement.namespace != nil {
+		tbl.Namespace.Apply(ctx, tbl.statement.namespace)
+		tbl.statement.namespace = nil
+	}
 	return nil
 }
 
 // Insert implements the interface sql.RowInserter.
 func (tbl BranchNamespaceControlTable) Insert(ctx *sql.Context, row sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	namespace := tbl.editing()
+	namespace.RWMutex.Lock()
+	defer namespace.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	branch := strings.ToLower(branch_control.FoldExpression(row[0].(string)))
@@ -183,13 +212,19 @@ func (tbl BranchNamespaceControlTable) Insert(ctx *sql.Context, row sql.Row) err
 		}
 	}
 
-	return tbl.insert(ctx, branch, user, host)
+	// Entries that affect a frozen branch may not be modified, just as those of the access table
+	if err := branch_control.CheckRuleEdit(ctx, branch); err != nil {
+		return err
+	}
+
+	return tbl.insert(ctx, namespace, branch, user, host)
 }
 
 // Update implements the interface sql.RowUpdater.
 func (tbl BranchNamespaceControlTable) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	namespace := tbl.editing()
+	namespace.RWMutex.Lock()
+	defer namespace.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	oldBranch := strings.ToLower(branch_control.FoldExpression(old[0].(string)))
@@ -206,7 +241,7 @@ func (tbl BranchNamespaceControlTable) Update(ctx *sql.Context, old sql.Row, new
 
 	// If we're not updating the same row, then we pre-emptively check for a row violation
 	if oldBranch != newBranch || oldUser != newUser || oldHost != newHost {
-		if tblIndex := tbl.GetIndex(newBranch, newUser, newHost); tblIndex != -1 {
+		if tblIndex := namespace.GetIndex(newBranch, newUser, newHost); tblIndex != -1 {
 			return sql.NewUniqueKeyErr(
 				fmt.Sprintf(`[%q, %q, %q]`, newBranch, newUser, newHost),
 				true,
@@ -235,18 +270,24 @@ func (tbl BranchNamespaceControlTable) Update(ctx *sql.Context, old sql.Row, new
 		}
 	}
 
-	if tblIndex := tbl.GetIndex(oldBranch, oldUser, oldHost); tblIndex != -1 {
-		if err := tbl.delete(ctx, oldBranch, oldUser, oldHost); err != nil {
+	// Neither the old nor the new entry may affect a frozen branch
+	if err := branch_control.CheckRuleEdit(ctx, oldBranch, newBranch); err != nil {
+		return err
+	}
+
+	if tblIndex := namespace.GetIndex(oldBranch, oldUser, oldHost); tblIndex != -1 {
+		if err := tbl.delete(ctx, namespace, oldBranch, oldUser, oldHost); err != nil {
 			return err
 		}
 	}
-	return tbl.insert(ctx, newBranch, newUser, newHost)
+	return tbl.insert(ctx, namespace, newBranch, newUser, newHost)
 }
 
 // Delete implements the interface sql.RowDeleter.
 func (tbl BranchNamespaceControlTable) Delete(ctx *sql.Context, row sql.Row) error {
-	tbl.RWMutex.Lock()
-	defer tbl.RWMutex.Unlock()
+	namespace := tbl.editing()
+	namespace.RWMutex.Lock()
+	defer namespace.RWMutex.Unlock()
 
 	// Branch and Host are case-insensitive, while user is case-sensitive
 	branch := strings.ToLower(branch_control.FoldExpression(row[0].(string)))
@@ -269,60 +310,51 @@ func (tbl BranchNamespaceControlTable) Delete(ctx *sql.Context, row sql.Row) err
 		}
 	}
 
-	return tbl.delete(ctx, branch, user, host)
+	// Entries that affect a frozen branch may not be removed
+	if err := branch_control.CheckRuleEdit(ctx, branch); err != nil {
+		return err
+	}
+
+	return tbl.delete(ctx, namespace, branch, user, host)
 }
 
-// Close implements the interface sql.Closer.
+// Close implements the interface sql.Closer. Changes made within an explicit transaction are saved once the
+// transaction commits.
 func (tbl BranchNamespaceControlTable) Close(context *sql.Context) error {
+	if branch_control.InTransaction(context) {
+		return nil
+	}
 	return branch_control.SaveData(context)
 }
 
+// editing returns the Namespace table that modifications are made to, which is the statement's copy while a statement
+// is in progress.
+func (tbl BranchNamespaceControlTable) editing() *branch_control.Namespace {
+	if tbl.statement != nil && tbl.statement.namespace != nil {
+		return tbl.statement.namespace
+	}
+	return tbl.Namespace
+}
+
 // insert adds the given branch, user, and host expression strings to the table. Assumes that the expressions have
 // already been folded.
-func (tbl BranchNamespaceControlTable) insert(ctx context.Context, branch string, user string, host string) error {
+func (tbl BranchNamespaceControlTable) insert(ctx context.Context, namespace *branch_control.Namespace, branch string, user string, host string) error {
 	// If we already have this in the table, then we return a duplicate PK error
-	if tblIndex := tbl.GetIndex(branch, user, host); tblIndex != -1 {
+	if tblIndex := namespace.GetIndex(branch, user, host); tblIndex != -1 {
 		return sql.NewUniqueKeyErr(
 			fmt.Sprintf(`[%q, %q, %q]`, branch, user, host),
 			true,
 			sql.Row{branch, user, host})
 	}
 
-	// Add the expressions to their respective slices
-	branchExpr := branch_control.ParseExpression(branch, sql.Collation_utf8mb4_0900_ai_ci)
-	userExpr := branch_control.ParseExpression(user, sql.Collation_utf8mb4_0900_bin)
-	hostExpr := branch_control.ParseExpression(host, sql.Collation_utf8mb4_0900_ai_ci)
-	nextIdx := uint32(len(tbl.Values))
-	tbl.Branches = append(tbl.Branches, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: branchExpr})
-	tbl.Users = append(tbl.Users, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: userExpr})
-	tbl.Hosts = append(tbl.Hosts, branch_control.MatchExpression{CollectionIndex: nextIdx, SortOrders: hostExpr})
-	tbl.Values = append(tbl.Values, branch_control.NamespaceValue{
-		Branch: branch,
-		User:   user,
-		Host:   host,
-	})
+	namespace.Insert(branch, user, host)
+	warnOnHostExpression(ctx, host)
 	return nil
 }
 
 // delete removes the given branch, user, and host expression strings from the table. Assumes that the expressions have
 // already been folded.
-func (tbl BranchNamespaceControlTable) delete(ctx context.Context, branch string, user string, host string) error {
-	// If we don't have this in the table, then we just return
-	tblIndex := tbl.GetIndex(branch, user, host)
-	if tblIndex == -1 {
-		return nil
-	}
-
-	endIndex := len(tbl.Values) - 1
-	// Remove the matching row from all slices by first swapping with the last element
-	tbl.Branches[tblIndex], tbl.Branches[endIndex] = tbl.Branches[endIndex], tbl.Branches[tblIndex]
-	tbl.Users[tblIndex], tbl.Users[endIndex] = tbl.Users[endIndex], tbl.Users[tblIndex]
-	tbl.Hosts[tblIndex], tbl.Hosts[endIndex] = tbl.Hosts[endIndex], tbl.Hosts[tblIndex]
-	tbl.Values[tblIndex], tbl.Values[endIndex] = tbl.Values[endIndex], tbl.Values[tblIndex]
-	// Then we remove the last element
-	tbl.Branches = tbl.Branches[:endIndex]
-	tbl.Users = tbl.Users[:endIndex]
-	tbl.Hosts = tbl.Hosts[:endIndex]
-	tbl.Values = tbl.Values[:endIndex]
+func (tbl BranchNamespaceControlTable) delete(ctx context.Context, namespace *branch_control.Namespace, branch string, user string, host string) error {
+	namespace.Delete(branch, user, host)
 	return nil
 }
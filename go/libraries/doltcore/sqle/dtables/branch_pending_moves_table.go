@@ -0,0 +1,107 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	PendingMovesTableName = "dolt_branch_pending_moves"
+)
+
+// pendingMovesSchema is the schema for the "dolt_branch_pending_moves" table.
+var pendingMovesSchema = sql.Schema{
+	&sql.Column{
+		Name:       "branch",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     PendingMovesTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "proposed_hash",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     PendingMovesTableName,
+		PrimaryKey: false,
+	},
+	&sql.Column{
+		Name:       "proposer",
+		Type:       sql.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     PendingMovesTableName,
+		PrimaryKey: false,
+	},
+	&sql.Column{
+		Name:       "created_at",
+		Type:       sql.Datetime,
+		Source:     PendingMovesTableName,
+		PrimaryKey: false,
+	},
+}
+
+// PendingMovesTable provides a read-only view over the branch_control.PendingMoves, which are modified through the
+// DOLT_PROPOSE_MOVE, DOLT_APPROVE_MOVE, and DOLT_REJECT_MOVE procedures.
+type PendingMovesTable struct {
+	*branch_control.PendingMoves
+}
+
+var _ sql.Table = PendingMovesTable{}
+
+// NewPendingMovesTable returns a new PendingMovesTable.
+func NewPendingMovesTable(pendingMoves *branch_control.PendingMoves) PendingMovesTable {
+	return PendingMovesTable{PendingMoves: pendingMoves}
+}
+
+// Name implements the interface sql.Table.
+func (tbl PendingMovesTable) Name() string {
+	return PendingMovesTableName
+}
+
+// String implements the interface sql.Table.
+func (tbl PendingMovesTable) String() string {
+	return PendingMovesTableName
+}
+
+// Schema implements the interface sql.Table.
+func (tbl PendingMovesTable) Schema() sql.Schema {
+	return pendingMovesSchema
+}
+
+// Collation implements the interface sql.Table.
+func (tbl PendingMovesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (tbl PendingMovesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table.
+func (tbl PendingMovesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	tbl.RWMutex.RLock()
+	defer tbl.RWMutex.RUnlock()
+
+	rows := make([]sql.Row, len(tbl.Values))
+	for i, move := range tbl.Values {
+		rows[i] = sql.Row{move.Branch, move.ProposedHash, fmt.Sprintf("%s@%s", move.User, move.Host), move.CreatedAt}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -19,25 +19,39 @@ import (
 	"io"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 )
 
+// ErrMoveCheckedOutBranch is returned when the hash of the session's checked out branch is updated through the
+// dolt_branches table, as the move would not be reflected in the session's working set.
+var ErrMoveCheckedOutBranch = errors.NewKind("cannot move the head of the checked out branch `%s`; use dolt_reset instead")
+
 var _ sql.Table = (*BranchesTable)(nil)
 var _ sql.UpdatableTable = (*BranchesTable)(nil)
 var _ sql.DeletableTable = (*BranchesTable)(nil)
 var _ sql.InsertableTable = (*BranchesTable)(nil)
 var _ sql.ReplaceableTable = (*BranchesTable)(nil)
 
-// BranchesTable is a sql.Table implementation that implements a system table which shows the dolt branches
+// BranchesTable is a sql.Table implementation that implements a system table which shows the dolt branches. Inserting
+// a row creates a branch at the row's hash, deleting a row deletes its branch, and updating a row renames its branch
+// and moves its head, in the same way as the dolt_branch stored procedure. Only the name and hash are written, as the
+// remaining columns are read from the branch's head.
 type BranchesTable struct {
-	ddb *doltdb.DoltDB
+	ddb    *doltdb.DoltDB
+	dbName string
 }
 
 // NewBranchesTable creates a BranchesTable
-func NewBranchesTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
-	return &BranchesTable{ddb}
+func NewBranchesTable(_ *sql.Context, dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &BranchesTable{ddb: ddb, dbName: dbName}
 }
 
 // Name is a sql.Table interface function which returns the name of the table which is defined by the constant
@@ -94,17 +108,21 @@ func NewBranchItr(sqlCtx *sql.Context, ddb *doltdb.DoltDB) (*BranchItr, error) {
 		return nil, err
 	}
 
-	branchNames := make([]string, len(branches))
-	commits := make([]*doltdb.Commit, len(branches))
-	for i, branch := range branches {
+	branchNames := make([]string, 0, len(branches))
+	commits := make([]*doltdb.Commit, 0, len(branches))
+	for _, branch := range branches {
+		// Branches that the user cannot read are hidden from them
+		if dsess.IsHiddenRef(sqlCtx, branch) {
+			continue
+		}
 		commit, err := ddb.ResolveCommitRef(sqlCtx, branch)
 
 		if err != nil {
 			return nil, err
 		}
 
-		branchNames[i] = branch.GetPath()
-		commits[i] = commit
+		branchNames = append(branchNames, branch.GetPath())
+		commits = append(commits, commit)
 	}
 
 	return &BranchItr{branchNames, commits, 0}, nil
@@ -178,21 +196,117 @@ type branchWriter struct {
 
 // Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
 // for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
-// is called.
+// is called. The hash may be any commit spec, such as another branch.
 func (bWr branchWriter) Insert(ctx *sql.Context, r sql.Row) error {
-	return fmt.Errorf("the dolt_branches table is read-only; use the dolt_branch stored procedure to edit remotes")
+	dbData, err := bWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	name, startPt := r[0].(string), r[1].(string)
+	if hasRef, err := dbData.Ddb.HasRef(ctx, ref.NewBranchRef(name)); err != nil {
+		return err
+	} else if hasRef {
+		return sql.NewUniqueKeyErr(fmt.Sprintf("[%s]", name), true, sql.Row{name})
+	}
+	if err = branch_control.CanCreateBranch(ctx, name); err != nil {
+		return err
+	}
+	if err = actions.CreateBranchWithStartPt(ctx, dbData, name, startPt, false); err != nil {
+		return err
+	}
+	return branch_control.GrantCreatedBranch(ctx, name)
 }
 
-// Update the given row. Provides both the old and new rows.
+// Update the given row. Provides both the old and new rows. A new name renames the branch, while a new hash moves the
+// branch's head, which is checked as a ref move in the same way as a hard reset.
 func (bWr branchWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
-	return fmt.Errorf("the dolt_branches table is read-only; use the dolt_branch stored procedure to edit remotes")
+	dbData, err := bWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	oldName, newName := old[0].(string), new[0].(string)
+	if oldName != newName {
+		if err = branch_control.CanDeleteBranch(ctx, oldName); err != nil {
+			return err
+		}
+		if err = branch_control.CanCreateBranch(ctx, newName); err != nil {
+			return err
+		}
+		if err = actions.RenameBranch(ctx, dbData, nil, oldName, newName, false); err != nil {
+			return err
+		}
+		if err = branch_control.RevokeDeletedBranch(ctx, oldName); err != nil {
+			return err
+		}
+		if err = branch_control.GrantCreatedBranch(ctx, newName); err != nil {
+			return err
+		}
+	}
+	if old[1] == new[1] {
+		return nil
+	}
+	return bWr.moveHead(ctx, dbData, newName, new[1].(string))
 }
 
 // Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
 // each row to process for the delete operation, which may involve many rows. After all rows have been processed,
-// Close is called.
+// Close is called. Deleting a row always deletes its branch, even when the branch has not been merged, although the
+// session's checked out branch may not be deleted.
 func (bWr branchWriter) Delete(ctx *sql.Context, r sql.Row) error {
-	return fmt.Errorf("the dolt_branches table is read-only; use the dolt_branch stored procedure to edit remotes")
+	dbData, err := bWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	name := r[0].(string)
+	if err = branch_control.CanDeleteBranch(ctx, name); err != nil {
+		return err
+	}
+	if err = actions.DeleteBranch(ctx, dbData, nil, name, actions.DeleteOptions{Force: true}); err != nil {
+		return err
+	}
+	return branch_control.RevokeDeletedBranch(ctx, name)
+}
+
+// moveHead moves the head of the given branch to the given commit spec. When any entry that grants the permission to
+// move the head requires approval, the move is proposed rather than applied.
+func (bWr branchWriter) moveHead(ctx *sql.Context, dbData env.DbData, name string, commitSpec string) error {
+	branchRef := ref.NewBranchRef(name)
+	if ref.Equals(dbData.Rsr.CWBHeadRef(), branchRef) {
+		return ErrMoveCheckedOutBranch.New(name)
+	}
+	requiresApproval, err := branch_control.CheckRefMove(ctx, name)
+	if err != nil {
+		return err
+	}
+	cs, err := doltdb.NewCommitSpec(commitSpec)
+	if err != nil {
+		return err
+	}
+	commit, err := dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, dbData.Rsr.CWBHeadRef())
+	if err != nil {
+		return err
+	}
+	if requiresApproval {
+		hash, err := commit.HashOf()
+		if err != nil {
+			return err
+		}
+		if err = branch_control.ProposeMove(ctx, name, hash.String()); err != nil {
+			return err
+		}
+		ctx.Warn(1105, "branch `%s` requires approval to move its head, so the move to `%s` was proposed instead", name, hash.String())
+		return branch_control.SaveData(ctx)
+	}
+	return dbData.Ddb.SetHeadToCommit(ctx, branchRef, commit)
+}
+
+// dbData returns the data of the table's database from the session.
+func (bWr branchWriter) dbData(ctx *sql.Context) (env.DbData, error) {
+	dbData, ok := dsess.DSessFromSess(ctx.Session).GetDbData(ctx, bWr.bt.dbName)
+	if !ok {
+		return env.DbData{}, fmt.Errorf("could not load database %s", bWr.bt.dbName)
+	}
+	return dbData, nil
 }
 
 // StatementBegin implements the interface sql.TableEditor. Currently a no-op.
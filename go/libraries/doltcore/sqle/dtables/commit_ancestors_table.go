@@ -18,6 +18,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 )
 
@@ -79,7 +80,7 @@ type CommitAncestorsRowItr struct {
 
 // NewCommitAncestorsRowItr creates a CommitAncestorsRowItr from the current environment.
 func NewCommitAncestorsRowItr(sqlCtx *sql.Context, ddb *doltdb.DoltDB) (*CommitAncestorsRowItr, error) {
-	itr, err := doltdb.CommitItrForAllBranches(sqlCtx, ddb)
+	itr, err := dsess.CommitItrForReadableBranches(sqlCtx, ddb)
 	if err != nil {
 		return nil, err
 	}
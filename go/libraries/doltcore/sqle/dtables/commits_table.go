@@ -18,6 +18,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 )
 
@@ -79,7 +80,7 @@ type CommitsRowItr struct {
 
 // NewCommitsRowItr creates a CommitsRowItr from the current environment.
 func NewCommitsRowItr(ctx *sql.Context, ddb *doltdb.DoltDB) (CommitsRowItr, error) {
-	itr, err := doltdb.CommitItrForAllBranches(ctx, ddb)
+	itr, err := dsess.CommitItrForReadableBranches(ctx, ddb)
 	if err != nil {
 		return CommitsRowItr{}, err
 	}
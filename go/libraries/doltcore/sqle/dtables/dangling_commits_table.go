@@ -0,0 +1,112 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*DanglingCommitsTable)(nil)
+
+// DanglingCommitsTable is a sql.Table implementation that implements a system table which shows the commits that
+// cannot be reached from any ref, most recent first. These commits are removed by garbage collection once they are no
+// longer in the reflog, so they can be recovered, such as with dolt_branch, until then.
+type DanglingCommitsTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewDanglingCommitsTable creates a DanglingCommitsTable
+func NewDanglingCommitsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
+	return &DanglingCommitsTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// DanglingCommitsTableName
+func (dt *DanglingCommitsTable) Name() string {
+	return doltdb.DanglingCommitsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// DanglingCommitsTableName
+func (dt *DanglingCommitsTable) String() string {
+	return doltdb.DanglingCommitsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dangling commits system table.
+func (dt *DanglingCommitsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "commit_hash", Type: sql.Text, Source: doltdb.DanglingCommitsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "committer", Type: sql.Text, Source: doltdb.DanglingCommitsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "email", Type: sql.Text, Source: doltdb.DanglingCommitsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "date", Type: sql.Datetime, Source: doltdb.DanglingCommitsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "age_seconds", Type: sql.Int64, Source: doltdb.DanglingCommitsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "message", Type: sql.Text, Source: doltdb.DanglingCommitsTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *DanglingCommitsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *DanglingCommitsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *DanglingCommitsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	return NewDanglingCommitsItr(ctx, dt.ddb)
+}
+
+// DanglingCommitsItr is a sql.RowItr implementation which iterates over each dangling commit as if it's a row in the
+// table.
+type DanglingCommitsItr struct {
+	commits []doltdb.DanglingCommit
+	idx     int
+}
+
+// NewDanglingCommitsItr creates a DanglingCommitsItr from the dangling commits of the given database.
+func NewDanglingCommitsItr(ctx *sql.Context, ddb *doltdb.DoltDB) (*DanglingCommitsItr, error) {
+	commits, err := ddb.DanglingCommits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DanglingCommitsItr{commits: commits}, nil
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row. The age of a commit is the number of
+// seconds since it was made.
+func (itr *DanglingCommitsItr) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.commits) {
+		return nil, io.EOF
+	}
+	dc := itr.commits[itr.idx]
+	itr.idx++
+
+	age := int64(ctx.QueryTime().Sub(dc.Meta.Time()).Seconds())
+	return sql.NewRow(dc.Hash.String(), dc.Meta.Name, dc.Meta.Email, dc.Meta.Time(), age, dc.Meta.Description), nil
+}
+
+// Close closes the iterator.
+func (itr *DanglingCommitsItr) Close(*sql.Context) error {
+	return nil
+}
@@ -124,7 +124,8 @@ func newMergeStatusItr(ctx context.Context, ws *doltdb.WorkingSet) (*MergeStatus
 		s3 := curr.String()
 		target = &s3
 
-		s4 := strings.Join(unmergedTblNames.AsSlice(), ", ")
+		unmergedTblNames.Add(state.UnmergableTables()...)
+		s4 := strings.Join(unmergedTblNames.AsSortedSlice(), ", ")
 		unmergedTables = &s4
 	}
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/google/uuid"
 	"gopkg.in/src-d/go-errors.v1"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/val"
@@ -114,6 +116,18 @@ func (sq SavedQuery) asRow(nbf *types.NomsBinFormat) (row.Row, error) {
 }
 
 var DoltQueryCatalogSchema = schema.MustSchemaFromCols(queryCatalogCols)
+
+// DoltQueryCatalogSqlSchema is the sql schema of the query catalog table, which it may be created with in SQL
+var DoltQueryCatalogSqlSchema sql.PrimaryKeySchema
+
+func init() {
+	var err error
+	DoltQueryCatalogSqlSchema, err = sqlutil.FromDoltSchema(doltdb.DoltQueryCatalogTableName, DoltQueryCatalogSchema)
+	if err != nil {
+		panic(err)
+	}
+}
+
 var catalogKd = DoltQueryCatalogSchema.GetKeyDescriptor()
 var catalogVd = DoltQueryCatalogSchema.GetValueDescriptor()
 
@@ -0,0 +1,141 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"errors"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+var _ sql.Table = (*ReflogTable)(nil)
+
+// ReflogSchema is the schema of the reflog system table, and of the dolt_reflog table function
+var ReflogSchema = sql.Schema{
+	&sql.Column{Name: "ref", Type: sql.Text, Source: doltdb.ReflogTableName, Nullable: false},
+	&sql.Column{Name: "ref_timestamp", Type: sql.Timestamp, Source: doltdb.ReflogTableName, Nullable: false},
+	&sql.Column{Name: "commit_hash", Type: sql.Text, Source: doltdb.ReflogTableName, Nullable: true},
+	&sql.Column{Name: "commit_message", Type: sql.Text, Source: doltdb.ReflogTableName, Nullable: true},
+}
+
+// ReflogTable is a sql.Table implementation that implements a system table which shows every recorded move of every
+// ref, most recent first
+type ReflogTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewReflogTable creates a ReflogTable
+func NewReflogTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
+	return &ReflogTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReflogTableName
+func (rt *ReflogTable) Name() string {
+	return doltdb.ReflogTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReflogTableName
+func (rt *ReflogTable) String() string {
+	return doltdb.ReflogTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the reflog system table.
+func (rt *ReflogTable) Schema() sql.Schema {
+	return ReflogSchema
+}
+
+// Collation implements the sql.Table interface.
+func (rt *ReflogTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (rt *ReflogTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (rt *ReflogTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	return NewReflogItr(ctx, rt.ddb, "")
+}
+
+// ReflogItr is a sql.RowItr implementation which iterates over each move of a ref as if it's a row in the table.
+type ReflogItr struct {
+	ddb     *doltdb.DoltDB
+	entries []doltdb.ReflogEntry
+	idx     int
+}
+
+// NewReflogItr creates a ReflogItr over the moves of the ref with the given name, or of every ref when the name is
+// empty. See DoltDB.Reflog for how names are matched. The moves of branches that are hidden by branch control are
+// skipped.
+func NewReflogItr(ctx *sql.Context, ddb *doltdb.DoltDB, name string) (*ReflogItr, error) {
+	entries, err := ddb.Reflog(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	readable := entries[:0]
+	for _, entry := range entries {
+		if r, err := ref.Parse(entry.Ref); err == nil && dsess.IsHiddenRef(ctx, r) {
+			continue
+		}
+		readable = append(readable, entry)
+	}
+
+	return &ReflogItr{ddb: ddb, entries: readable}, nil
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row. Deleted refs have no commit, and the commit
+// of a move may since have been garbage collected, in which case it has no message.
+func (itr *ReflogItr) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.entries) {
+		return nil, io.EOF
+	}
+	entry := itr.entries[itr.idx]
+	itr.idx++
+
+	var commitHash, message interface{}
+	if !entry.Hash.IsEmpty() {
+		commitHash = entry.Hash.String()
+		commit, err := itr.ddb.ReadCommit(ctx, entry.Hash)
+		if err != nil && !errors.Is(err, datas.ErrCommitNotFound) {
+			return nil, err
+		}
+		if commit != nil {
+			meta, err := commit.GetCommitMeta(ctx)
+			if err != nil {
+				return nil, err
+			}
+			message = meta.Description
+		}
+	}
+
+	return sql.NewRow(entry.Ref, entry.Timestamp, commitHash, message), nil
+}
+
+// Close closes the iterator.
+func (itr *ReflogItr) Close(*sql.Context) error {
+	return nil
+}
@@ -15,24 +15,37 @@
 package dtables
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 )
 
 var _ sql.Table = (*TagsTable)(nil)
+var _ sql.UpdatableTable = (*TagsTable)(nil)
+var _ sql.DeletableTable = (*TagsTable)(nil)
+var _ sql.InsertableTable = (*TagsTable)(nil)
+var _ sql.ReplaceableTable = (*TagsTable)(nil)
 
-// TagsTable is a sql.Table implementation that implements a system table which shows the dolt tags
+// TagsTable is a sql.Table implementation that implements a system table which shows the dolt tags. Inserting a row
+// creates an annotated tag at the row's hash, deleting a row deletes its tag, and updating a row replaces its tag, in
+// the same way as the dolt_tag stored procedure. The date of a written tag is always the time it was written.
 type TagsTable struct {
-	ddb *doltdb.DoltDB
+	ddb    *doltdb.DoltDB
+	dbName string
 }
 
 // NewTagsTable creates a TagsTable
-func NewTagsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
-	return &TagsTable{ddb: ddb}
+func NewTagsTable(_ *sql.Context, dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &TagsTable{ddb: ddb, dbName: dbName}
 }
 
 // Name is a sql.Table interface function which returns the name of the table which is defined by the constant
@@ -52,10 +65,10 @@ func (dt *TagsTable) Schema() sql.Schema {
 	return []*sql.Column{
 		{Name: "tag_name", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: true},
 		{Name: "tag_hash", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: true},
-		{Name: "tagger", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "email", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "date", Type: sql.Datetime, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "message", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
+		{Name: "tagger", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "email", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "date", Type: sql.Datetime, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "message", Type: sql.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
 	}
 }
 
@@ -109,3 +122,151 @@ func (itr *TagsItr) Next(ctx *sql.Context) (sql.Row, error) {
 func (itr *TagsItr) Close(*sql.Context) error {
 	return nil
 }
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (dt *TagsTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return tagWriter{dt}
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (dt *TagsTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return tagWriter{dt}
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (dt *TagsTable) Inserter(*sql.Context) sql.RowInserter {
+	return tagWriter{dt}
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (dt *TagsTable) Deleter(*sql.Context) sql.RowDeleter {
+	return tagWriter{dt}
+}
+
+var _ sql.RowReplacer = tagWriter{nil}
+var _ sql.RowUpdater = tagWriter{nil}
+var _ sql.RowInserter = tagWriter{nil}
+var _ sql.RowDeleter = tagWriter{nil}
+
+type tagWriter struct {
+	dt *TagsTable
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called. The hash may be any commit spec, and a NULL tagger or email defaults to the session's user.
+func (tWr tagWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	dbData, err := tWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	name := r[0].(string)
+	if hasRef, err := dbData.Ddb.HasRef(ctx, ref.NewTagRef(name)); err != nil {
+		return err
+	} else if hasRef {
+		return sql.NewUniqueKeyErr(fmt.Sprintf("[%s]", name), true, sql.Row{name})
+	}
+	if err = branch_control.CanModifyTag(ctx, name); err != nil {
+		return err
+	}
+	return actions.CreateTagOnDB(ctx, dbData.Ddb, name, r[1].(string), tWr.tagProps(ctx, r), dbData.Rsr.CWBHeadRef())
+}
+
+// Update the given row. Provides both the old and new rows. As tags cannot be modified, the old tag is deleted and a
+// new tag is created from the new row, once the new row's name and hash have been validated.
+func (tWr tagWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	dbData, err := tWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	oldName, newName := old[0].(string), new[0].(string)
+	if err = branch_control.CanModifyTag(ctx, oldName); err != nil {
+		return err
+	}
+	if oldName != newName {
+		if err = branch_control.CanModifyTag(ctx, newName); err != nil {
+			return err
+		}
+		if hasRef, err := dbData.Ddb.HasRef(ctx, ref.NewTagRef(newName)); err != nil {
+			return err
+		} else if hasRef {
+			return sql.NewUniqueKeyErr(fmt.Sprintf("[%s]", newName), true, sql.Row{newName})
+		}
+		if !ref.IsValidTagName(newName) {
+			return doltdb.ErrInvTagName
+		}
+	}
+	cs, err := doltdb.NewCommitSpec(new[1].(string))
+	if err != nil {
+		return err
+	}
+	if _, err = dsess.ResolveReadableCommit(ctx, dbData.Ddb, cs, dbData.Rsr.CWBHeadRef()); err != nil {
+		return err
+	}
+	if err = actions.DeleteTagsOnDB(ctx, dbData.Ddb, oldName); err != nil {
+		return err
+	}
+	return actions.CreateTagOnDB(ctx, dbData.Ddb, newName, new[1].(string), tWr.tagProps(ctx, new), dbData.Rsr.CWBHeadRef())
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (tWr tagWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	dbData, err := tWr.dbData(ctx)
+	if err != nil {
+		return err
+	}
+	name := r[0].(string)
+	if err = branch_control.CanModifyTag(ctx, name); err != nil {
+		return err
+	}
+	return actions.DeleteTagsOnDB(ctx, dbData.Ddb, name)
+}
+
+// tagProps returns the properties of the tag that the given row describes.
+func (tWr tagWriter) tagProps(ctx *sql.Context, r sql.Row) actions.TagProps {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	props := actions.TagProps{TaggerName: dSess.Username(), TaggerEmail: dSess.Email()}
+	if tagger, ok := r[2].(string); ok {
+		props.TaggerName = tagger
+	}
+	if email, ok := r[3].(string); ok {
+		props.TaggerEmail = email
+	}
+	if msg, ok := r[5].(string); ok {
+		props.Description = msg
+	}
+	return props
+}
+
+// dbData returns the data of the table's database from the session.
+func (tWr tagWriter) dbData(ctx *sql.Context) (env.DbData, error) {
+	dbData, ok := dsess.DSessFromSess(ctx.Session).GetDbData(ctx, tWr.dt.dbName)
+	if !ok {
+		return env.DbData{}, fmt.Errorf("could not load database %s", tWr.dt.dbName)
+	}
+	return dbData, nil
+}
+
+// StatementBegin implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) StatementBegin(ctx *sql.Context) {}
+
+// DiscardChanges implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	return nil
+}
+
+// StatementComplete implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) StatementComplete(ctx *sql.Context) error {
+	return nil
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (tWr tagWriter) Close(*sql.Context) error {
+	return nil
+}
@@ -556,7 +556,7 @@ func getCommitFromHash(ctx *sql.Context, ddb *doltdb.DoltDB, val string) *doltdb
 	if err != nil {
 		return nil
 	}
-	cm, err := ddb.Resolve(ctx, cmSpec, headRef)
+	cm, err := dsess.ResolveReadableCommit(ctx, ddb, cmSpec, headRef)
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,426 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+)
+
+// The number of columns preceding the to and from columns of a workspace table: id, staged and diff_type
+const workspaceMetaColCount = 3
+
+var _ sql.Table = (*WorkspaceTable)(nil)
+var _ sql.UpdatableTable = (*WorkspaceTable)(nil)
+var _ sql.DeletableTable = (*WorkspaceTable)(nil)
+
+// WorkspaceTable is a sql.Table implementation of the dolt_workspace_<table> system tables, which show the uncommitted
+// changes to the rows of a table. The staged changes, between HEAD and the staged root, are shown first, followed by
+// the unstaged changes between the staged and working roots. Updating the staged column of a row stages or unstages
+// its change, and deleting an unstaged row reverts its change in the working root.
+type WorkspaceTable struct {
+	name      string
+	dbName    string
+	ddb       *doltdb.DoltDB
+	targetSch schema.Schema
+	sqlSch    sql.Schema
+	joiner    *rowconv.Joiner
+
+	// the indexes of the to and from columns, followed by the diff_type column, in the rows of the diff
+	diffIdxs []int
+}
+
+// NewWorkspaceTable creates a WorkspaceTable for the table named, whose rows are shown with its schema in |root|.
+func NewWorkspaceTable(ctx *sql.Context, tblName, dbName string, ddb *doltdb.DoltDB, root *doltdb.RootValue) (sql.Table, error) {
+	wsTblName := doltdb.DoltWorkspaceTablePrefix + tblName
+
+	table, tblName, ok, err := root.GetTableInsensitive(ctx, tblName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(wsTblName)
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diffTableSch, j, err := GetDiffTableSchemaAndJoiner(ddb.Format(), sch, sch)
+	if err != nil {
+		return nil, err
+	}
+	diffSqlSch, err := sqlutil.FromDoltSchema(wsTblName, diffTableSch)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlSch := sql.Schema{
+		{Name: "id", Type: sql.Uint64, Source: wsTblName, PrimaryKey: true, Nullable: false},
+		{Name: "staged", Type: sql.Boolean, Source: wsTblName, PrimaryKey: false, Nullable: false},
+		{Name: diffTypeColName, Type: sql.Text, Source: wsTblName, PrimaryKey: false, Nullable: false},
+	}
+	var diffIdxs []int
+	for _, prefix := range []string{"to_", "from_"} {
+		for _, col := range sch.GetAllCols().GetColumns() {
+			idx := diffSqlSch.Schema.IndexOfColName(prefix + col.Name)
+			if idx < 0 {
+				return nil, fmt.Errorf("column %s%s not found in the diff of table %s", prefix, col.Name, tblName)
+			}
+			c := *diffSqlSch.Schema[idx]
+			c.Source, c.PrimaryKey, c.Nullable, c.Default, c.AutoIncrement = wsTblName, false, true, nil, false
+			sqlSch = append(sqlSch, &c)
+			diffIdxs = append(diffIdxs, idx)
+		}
+	}
+	diffIdxs = append(diffIdxs, diffSqlSch.Schema.IndexOfColName(diffTypeColName))
+
+	return &WorkspaceTable{
+		name:      tblName,
+		dbName:    dbName,
+		ddb:       ddb,
+		targetSch: sch,
+		sqlSch:    sqlSch,
+		joiner:    j,
+		diffIdxs:  diffIdxs,
+	}, nil
+}
+
+// Name is a sql.Table interface function which returns the name of the table
+func (wt *WorkspaceTable) Name() string {
+	return doltdb.DoltWorkspaceTablePrefix + wt.name
+}
+
+// String is a sql.Table interface function which returns the name of the table
+func (wt *WorkspaceTable) String() string {
+	return doltdb.DoltWorkspaceTablePrefix + wt.name
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the workspace table.
+func (wt *WorkspaceTable) Schema() sql.Schema {
+	return wt.sqlSch
+}
+
+// Collation implements the sql.Table interface.
+func (wt *WorkspaceTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (wt *WorkspaceTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition. The rows are numbered in
+// order, staged rows first.
+func (wt *WorkspaceTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	roots, ok := dsess.DSessFromSess(ctx.Session).GetRoots(ctx, wt.dbName)
+	if !ok {
+		return nil, fmt.Errorf("could not load database %s", wt.dbName)
+	}
+
+	staged, err := wt.diffRows(ctx, roots.Head, roots.Staged, true)
+	if err != nil {
+		return nil, err
+	}
+	unstaged, err := wt.diffRows(ctx, roots.Staged, roots.Working, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := append(staged, unstaged...)
+	for i := range rows {
+		rows[i][0] = uint64(i)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// diffRows returns the rows of the workspace table for the changes to the table between the two roots given, without
+// their ids.
+func (wt *WorkspaceTable) diffRows(ctx *sql.Context, fromRoot, toRoot *doltdb.RootValue, staged bool) ([]sql.Row, error) {
+	from, _, err := fromRoot.GetTable(ctx, wt.name)
+	if err != nil {
+		return nil, err
+	}
+	to, _, err := toRoot.GetTable(ctx, wt.name)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil && to == nil {
+		return nil, nil
+	}
+
+	dp := NewDiffPartition(to, from, "", "", nil, nil, wt.targetSch, wt.targetSch)
+	iter, err := dp.GetRowIter(ctx, wt.ddb, wt.joiner, sql.IndexLookup{})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	var rows []sql.Row
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		row := make(sql.Row, workspaceMetaColCount, workspaceMetaColCount+len(wt.diffIdxs)-1)
+		row[1], row[2] = staged, r[wt.diffIdxs[len(wt.diffIdxs)-1]]
+		for _, idx := range wt.diffIdxs[:len(wt.diffIdxs)-1] {
+			row = append(row, r[idx])
+		}
+		rows = append(rows, row)
+	}
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (wt *WorkspaceTable) Updater(*sql.Context) sql.RowUpdater {
+	return &workspaceWriter{wt: wt}
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (wt *WorkspaceTable) Deleter(*sql.Context) sql.RowDeleter {
+	return &workspaceWriter{wt: wt}
+}
+
+var _ sql.RowUpdater = (*workspaceWriter)(nil)
+var _ sql.RowDeleter = (*workspaceWriter)(nil)
+
+// workspaceWriter applies the changes of workspace table rows to the staged and working roots, through table writers
+// for the underlying table in each root, which are created on first use.
+type workspaceWriter struct {
+	wt      *WorkspaceTable
+	staged  writer.TableWriter
+	working writer.TableWriter
+}
+
+// Update the given row. Only the staged column may be changed. Staging a row applies its change to the staged root,
+// while unstaging a row reverts the staged root to the row's value at HEAD.
+func (wr *workspaceWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	for i, col := range wr.wt.sqlSch {
+		if i == 1 {
+			continue
+		}
+		if cmp, err := col.Type.Compare(old[i], new[i]); err != nil {
+			return err
+		} else if cmp != 0 {
+			return fmt.Errorf("only the staged column of %s may be updated", wr.wt.Name())
+		}
+	}
+	wasStaged, err := sql.ConvertToBool(old[1])
+	if err != nil {
+		return err
+	}
+	isStaged, err := sql.ConvertToBool(new[1])
+	if err != nil {
+		return err
+	}
+	if wasStaged == isStaged {
+		return nil
+	}
+
+	tw, err := wr.tableWriter(ctx, true)
+	if err != nil {
+		return err
+	}
+	to, from := wr.rowValues(old)
+	if isStaged {
+		return applyRowChange(ctx, tw, from, to)
+	}
+	return applyRowChange(ctx, tw, to, from)
+}
+
+// Delete deletes the given row, reverting its change in the working root to the row's staged value. Staged rows cannot
+// be deleted, and must be unstaged first.
+func (wr *workspaceWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if staged, err := sql.ConvertToBool(r[1]); err != nil {
+		return err
+	} else if staged {
+		return fmt.Errorf("cannot delete staged rows of %s; unstage them first by setting staged to false", wr.wt.Name())
+	}
+
+	tw, err := wr.tableWriter(ctx, false)
+	if err != nil {
+		return err
+	}
+	to, from := wr.rowValues(r)
+	return applyRowChange(ctx, tw, to, from)
+}
+
+// rowValues returns the to and from values of the row of the underlying table, which are nil when the row was removed
+// or added.
+func (wr *workspaceWriter) rowValues(r sql.Row) (to, from sql.Row) {
+	n := (len(r) - workspaceMetaColCount) / 2
+	to, from = r[workspaceMetaColCount:workspaceMetaColCount+n], r[workspaceMetaColCount+n:]
+	switch r[2] {
+	case diffTypeAdded:
+		from = nil
+	case diffTypeRemoved:
+		to = nil
+	}
+	return to, from
+}
+
+// applyRowChange changes a row of the table written from |before| to |after|, either of which may be nil for a row
+// that does not exist.
+func applyRowChange(ctx *sql.Context, tw writer.TableWriter, before, after sql.Row) error {
+	switch {
+	case before == nil:
+		return tw.Insert(ctx, after)
+	case after == nil:
+		return tw.Delete(ctx, before)
+	default:
+		return tw.Update(ctx, before, after)
+	}
+}
+
+// tableWriter returns the writer of the underlying table in the session's staged or working root.
+func (wr *workspaceWriter) tableWriter(ctx *sql.Context, staged bool) (writer.TableWriter, error) {
+	if staged && wr.staged != nil {
+		return wr.staged, nil
+	} else if !staged && wr.working != nil {
+		return wr.working, nil
+	}
+
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
+		return nil, err
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbName := wr.wt.dbName
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("could not load database %s", dbName)
+	}
+	root, setter := roots.Working, writer.SessionRootSetter(dSess.SetRoot)
+	if staged {
+		root, setter = roots.Staged, setStagedRoot(dSess)
+	}
+
+	tbl, ok, err := root.GetTable(ctx, wr.wt.name)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("table %s must be staged with dolt_add before its rows can be staged", wr.wt.name)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !schema.SchemasAreEqual(sch, wr.wt.targetSch) {
+		return nil, fmt.Errorf("the schema of table %s has unstaged changes; stage them with dolt_add before staging its rows", wr.wt.name)
+	}
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	ws = ws.WithWorkingRoot(root)
+	ait, err := globalstate.NewAutoIncrementTracker(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("could not load database %s", dbName)
+	}
+
+	writeSession := writer.NewWriteSession(tbl.Format(), ws, ait, dbState.EditOpts())
+	tw, err := writeSession.GetTableWriter(ctx, wr.wt.name, dbName, setter, false)
+	if err != nil {
+		return nil, err
+	}
+	tw.StatementBegin(ctx)
+
+	if staged {
+		wr.staged = tw
+	} else {
+		wr.working = tw
+	}
+	return tw, nil
+}
+
+// setStagedRoot returns a writer.SessionRootSetter that sets the session's staged root.
+func setStagedRoot(dSess *dsess.DoltSession) writer.SessionRootSetter {
+	return func(ctx *sql.Context, dbName string, root *doltdb.RootValue) error {
+		roots, ok := dSess.GetRoots(ctx, dbName)
+		if !ok {
+			return fmt.Errorf("could not load database %s", dbName)
+		}
+		roots.Staged = root
+		return dSess.SetRoots(ctx, dbName, roots)
+	}
+}
+
+// writers returns the table writers that have been created.
+func (wr *workspaceWriter) writers() []writer.TableWriter {
+	var writers []writer.TableWriter
+	for _, tw := range []writer.TableWriter{wr.staged, wr.working} {
+		if tw != nil {
+			writers = append(writers, tw)
+		}
+	}
+	return writers
+}
+
+// StatementBegin implements the interface sql.TableEditor. The table writers begin their statement when created.
+func (wr *workspaceWriter) StatementBegin(*sql.Context) {}
+
+// DiscardChanges implements the interface sql.TableEditor.
+func (wr *workspaceWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	for _, tw := range wr.writers() {
+		if err := tw.DiscardChanges(ctx, errorEncountered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StatementComplete implements the interface sql.TableEditor.
+func (wr *workspaceWriter) StatementComplete(ctx *sql.Context) error {
+	for _, tw := range wr.writers() {
+		if err := tw.StatementComplete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close finalizes the update or delete operation, writing the staged and working roots to the session.
+func (wr *workspaceWriter) Close(ctx *sql.Context) error {
+	for _, tw := range wr.writers() {
+		if err := tw.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
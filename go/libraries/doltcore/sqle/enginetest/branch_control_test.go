@@ -16,6 +16,7 @@ package enginetest
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/sql"
@@ -38,12 +39,13 @@ type BranchControlTest struct {
 
 // BranchControlTestAssertion is within a BranchControlTest to assert functionality.
 type BranchControlTestAssertion struct {
-	User           string
-	Host           string
-	Query          string
-	Expected       []sql.Row
-	ExpectedErr    *errors.Kind
-	ExpectedErrStr string
+	User            string
+	Host            string
+	Query           string
+	Expected        []sql.Row
+	ExpectedErr     *errors.Kind
+	ExpectedErrStr  string
+	ExpectedWarning int
 }
 
 // BranchControlBlockTest are tests for quickly verifying that a command is blocked before the appropriate entry is
@@ -57,6 +59,10 @@ type BranchControlBlockTest struct {
 	ExpectedErr *errors.Kind
 }
 
+// branchControlTestTime is the time that the clock is frozen to while running BranchControlTests, which is a Wednesday at
+// noon in UTC.
+var branchControlTestTime = time.Date(2022, time.October, 19, 12, 0, 0, 0, time.UTC)
+
 // TestUserSetUpScripts creates a user named "testuser@localhost", and grants them privileges on all databases and
 // tables. In addition, creates a committed table named "test" with a single value, along with a mirror branch named
 // "other".
@@ -304,7 +310,7 @@ var BranchControlTests = []BranchControlTest{
 				Host:  "localhost",
 				Query: "SELECT * FROM dolt_branch_control;",
 				Expected: []sql.Row{
-					{"%", "root", "localhost", uint64(1)},
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
 				},
 			},
 			{
@@ -320,7 +326,7 @@ var BranchControlTests = []BranchControlTest{
 				Host:  "localhost",
 				Query: "SELECT * FROM dolt_branch_control;",
 				Expected: []sql.Row{
-					{"%", "root", "localhost", uint64(1)},
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
 				},
 			},
 			{
@@ -336,7 +342,7 @@ var BranchControlTests = []BranchControlTest{
 				Host:  "localhost",
 				Query: "SELECT * FROM dolt_branch_control;",
 				Expected: []sql.Row{
-					{"%", "root", "localhost", uint64(1)},
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
 				},
 			},
 			{
@@ -364,7 +370,7 @@ var BranchControlTests = []BranchControlTest{
 			{ // Prefix "other" is now locked by root
 				User:  "root",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_namespace_control VALUES ('other%', 'root', 'localhost');",
+				Query: "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'root', 'localhost');",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -378,7 +384,7 @@ var BranchControlTests = []BranchControlTest{
 			{ // Allow testuser to use the "other" prefix
 				User:  "root",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_namespace_control VALUES ('other%', 'testuser', 'localhost');",
+				Query: "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'testuser', 'localhost');",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -392,7 +398,7 @@ var BranchControlTests = []BranchControlTest{
 			{ // Create a longer match, which takes precedence over shorter matches
 				User:  "root",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_namespace_control VALUES ('otherbranch%', 'root', 'localhost');",
+				Query: "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('otherbranch%', 'root', 'localhost');",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -412,7 +418,7 @@ var BranchControlTests = []BranchControlTest{
 			{
 				User:  "root",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_namespace_control VALUES ('otherbranch%', 'testuser', 'localhost');",
+				Query: "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('otherbranch%', 'testuser', 'localhost');",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -425,6 +431,126 @@ var BranchControlTests = []BranchControlTest{
 			},
 		},
 	},
+	{
+		Name: "Namespace entries block branches created by checkout",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'root', 'localhost');",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_CHECKOUT('-b', 'otherbranch');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_CHECKOUT('-b', 'otherbranch', 'main');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT count(*) FROM dolt_branches WHERE name = 'otherbranch';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('-b', 'newbranch');",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "Namespace entries apply to every branch creation path",
+		SetUpScript: []string{
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('team1/%', 'user1', 'localhost');",
+			"CREATE USER user1@localhost;",
+			"GRANT ALL ON *.* TO user1@localhost;",
+			"CREATE USER user2@localhost;",
+			"GRANT ALL ON *.* TO user2@localhost;",
+			"CALL DOLT_REMOTE('add', 'origin', 'mem://remote');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('team1/branch');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "user1",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('team1/branch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('-c', 'main', 'team1/copy');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "user1",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-c', 'main', 'team1/copy');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "CALL DOLT_CHECKOUT('-b', 'team1/checkout');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "user1",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('-b', 'team1/checkout');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "CALL DOLT_PUSH('origin', 'main:team1/pushed');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "user1",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PUSH('origin', 'main:team1/pushed');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branches (name, hash) VALUES ('team1/inserted', 'main');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "user1",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branches (name, hash) VALUES ('team1/inserted', 'main');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "user2",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_branches SET name = 'team1/renamed' WHERE name = 'team1/inserted';",
+				ExpectedErr: branch_control.ErrCannotDeleteBranch,
+			},
+			{ // Branches outside of the namespace are unaffected
+				User:     "user2",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('-b', 'team2/checkout');",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
 	{
 		Name: "Require admin to modify tables",
 		SetUpScript: []string{
@@ -433,7 +559,7 @@ var BranchControlTests = []BranchControlTest{
 			"CREATE USER b@localhost;",
 			"GRANT ALL ON *.* TO a@localhost;",
 			"GRANT ALL ON *.* TO b@localhost;",
-			"INSERT INTO dolt_branch_control VALUES ('other', 'a', 'localhost', 'write'), ('prefix%', 'a', 'localhost', 'admin')",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('other', 'a', 'localhost', 'write', 'all', 0), ('prefix%', 'a', 'localhost', 'admin', 'all', 0)",
 		},
 		Assertions: []BranchControlTestAssertion{
 			{
@@ -457,7 +583,7 @@ var BranchControlTests = []BranchControlTest{
 			{
 				User:  "a",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_control VALUES ('prefix1%', 'b', 'localhost', 'write');",
+				Query: "INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('prefix1%', 'b', 'localhost', 'write', 'all', 0);",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -499,13 +625,13 @@ var BranchControlTests = []BranchControlTest{
 			{
 				User:        "b",
 				Host:        "localhost",
-				Query:       "INSERT INTO dolt_branch_control VALUES ('prefix1%', 'b', 'localhost', 'admin');",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('prefix1%', 'b', 'localhost', 'admin', 'all', 0);",
 				ExpectedErr: branch_control.ErrInsertingRow,
 			},
 			{ // Since "a" has admin on "prefix%", they can also insert into the namespace table
 				User:  "a",
 				Host:  "localhost",
-				Query: "INSERT INTO dolt_branch_namespace_control VALUES ('prefix___', 'a', 'localhost');",
+				Query: "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('prefix___', 'a', 'localhost');",
 				Expected: []sql.Row{
 					{sql.NewOkResult(1)},
 				},
@@ -513,7 +639,7 @@ var BranchControlTests = []BranchControlTest{
 			{
 				User:        "b",
 				Host:        "localhost",
-				Query:       "INSERT INTO dolt_branch_namespace_control VALUES ('prefix', 'b', 'localhost');",
+				Query:       "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('prefix', 'b', 'localhost');",
 				ExpectedErr: branch_control.ErrInsertingRow,
 			},
 			{
@@ -544,58 +670,2075 @@ var BranchControlTests = []BranchControlTest{
 			},
 		},
 	},
+	{
+		Name: "Only the super user may grant admin over the escalation constraints",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // Being an admin on every branch isn't enough to make another admin on every branch
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'a', 'localhost', 'admin');",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%%', 'a', '%', 'write,admin');",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'a', 'localhost', 'write');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_branch_control SET permissions = 'admin' WHERE user = 'a';",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{ // Narrower grants of admin are unaffected
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('dev%', 'a', 'localhost', 'admin');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'b', 'localhost', 'admin');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_escalation_constraints = 'release%, main';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "CALL DOLT_BRANCH_CONTROL_STATUS();",
+				Expected: []sql.Row{
+					{"base", "", int64(0), int64(0), int64(0), ""},
+					{"overlay", "", int64(4), int64(0), int64(0), "release%,main"},
+				},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('release%', 'a', 'localhost', 'admin');",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('MAIN', 'a', 'localhost', 'admin');",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('release1', 'a', 'localhost', 'admin');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_escalation_constraints = '%';",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "Only the super user may grant admin over the escalation constraints through roles or imports",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', '@ops', '%', 'admin');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('dev%', '@devs', '%', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control_roles VALUES ('ops', 'a', 'localhost');",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control_roles VALUES ('devs', 'a', 'localhost');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_branch_control_roles SET role = 'ops' WHERE user = 'a';",
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       `CALL DOLT_BRANCH_CONTROL_IMPORT('{"access":[{"branch":"%","user":"b","host":"localhost","permissions":1}]}', 'merge');`,
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       `CALL DOLT_BRANCH_CONTROL_IMPORT('{"roles":[{"role":"ops","user":"b","host":"localhost"}]}', 'merge');`,
+				ExpectedErr: branch_control.ErrEscalatingAdmin,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control_roles VALUES ('ops', 'b', 'localhost');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control_roles ORDER BY role, user;",
+				Expected: []sql.Row{{"devs", "a", "localhost"}, {"ops", "b", "localhost"}},
+			},
+		},
+	},
 	{
 		Name: "Subset entries count as duplicates",
 		SetUpScript: []string{
 			"DELETE FROM dolt_branch_control WHERE user = '%';",
 			"CREATE USER testuser@localhost;",
 			"GRANT ALL ON *.* TO testuser@localhost;",
-			"INSERT INTO dolt_branch_control VALUES ('prefix%', 'testuser', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('prefix%', 'testuser', 'localhost', 'admin', 'all', 0);",
 		},
 		Assertions: []BranchControlTestAssertion{
 			{ // The pre-existing "prefix%" entry will cover ALL possible matches of "prefixsub%", so we treat it as a duplicate
 				User:        "testuser",
 				Host:        "localhost",
-				Query:       "INSERT INTO dolt_branch_control VALUES ('prefixsub%', 'testuser', 'localhost', 'admin');",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('prefixsub%', 'testuser', 'localhost', 'admin', 'all', 0);",
 				ExpectedErr: sql.ErrPrimaryKeyViolation,
 			},
 		},
 	},
-	//TODO: need to add this logic
-	/*{
-		Name: "Creating branch creates new entry",
+	{
+		Name: "Failing statements leave the tables unchanged",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('prefix%', 'testuser', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // The third row is a subset of the existing entry, so the first two rows must not be applied either
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('first', 'testuser', 'localhost', 'write'), ('second', 'testuser', 'localhost', 'write'), ('prefixsub%', 'testuser', 'localhost', 'admin');",
+				ExpectedErr: sql.ErrPrimaryKeyViolation,
+			},
+			{
+				Query: "SELECT branch, user, host FROM dolt_branch_control;",
+				Expected: []sql.Row{
+					{"%", "root", "localhost"},
+					{"prefix%", "testuser", "localhost"},
+				},
+			},
+			{
+				Query:       "UPDATE dolt_branch_control SET permissions = 'write', branch = 'other' WHERE user = 'testuser' OR user = 'root';",
+				ExpectedErr: sql.ErrPrimaryKeyViolation,
+			},
+			{
+				Query:       "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('first', 'testuser', 'localhost'), ('first', 'testuser', 'localhost');",
+				ExpectedErr: sql.ErrPrimaryKeyViolation,
+			},
+			{
+				Query:    "SELECT * FROM dolt_branch_namespace_control;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "INSERT INTO dolt_branch_control_roles VALUES ('devs', 'first', 'localhost'), ('devs', 'first', 'localhost');",
+				ExpectedErr: sql.ErrPrimaryKeyViolation,
+			},
+			{
+				Query:    "SELECT * FROM dolt_branch_control_roles;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "Transactions are committed and rolled back",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('kept', 'testuser', 'localhost', 'write');",
+			"START TRANSACTION;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('rolledback', 'testuser', 'localhost', 'write');",
+			"DELETE FROM dolt_branch_control WHERE branch = 'kept';",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('rolledback', 'testuser', 'localhost');",
+			"ROLLBACK;",
+			"START TRANSACTION;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('committed', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('committed', 'testuser', 'localhost');",
+			"COMMIT;",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				Query: "SELECT branch, user, host FROM dolt_branch_control ORDER BY branch;",
+				Expected: []sql.Row{
+					{"%", "root", "localhost"},
+					{"committed", "testuser", "localhost"},
+					{"kept", "testuser", "localhost"},
+				},
+			},
+			{
+				Query: "SELECT * FROM dolt_branch_namespace_control;",
+				Expected: []sql.Row{
+					{"committed", "testuser", "localhost", nil},
+				},
+			},
+		},
+	},
+	{
+		Name: "Host expressions for the wrong form of client host are warned about",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // The server resolves hostnames by default, so an IP address pattern does not match a client by its name
+				Query:           "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', '10.0.0.%', 'write');",
+				Expected:        []sql.Row{{sql.NewOkResult(1)}},
+				ExpectedWarning: 1105,
+			},
+			{
+				Query:           "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('main', 'testuser', '10.0.%');",
+				Expected:        []sql.Row{{sql.NewOkResult(1)}},
+				ExpectedWarning: 1105,
+			},
+			{
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', '%.example.com', 'write');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "Operation classes restrict entries",
 		SetUpScript: []string{
 			"DELETE FROM dolt_branch_control WHERE user = '%';",
 			"CREATE USER testuser@localhost;",
 			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"CALL DOLT_BRANCH('other');",
+			"CALL DOLT_CHECKOUT('other');",
+			"INSERT INTO test VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'other commit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('main', 'testuser', 'localhost', 'write', 'merge', 0);",
 		},
 		Assertions: []BranchControlTestAssertion{
 			{
-				User: "testuser",
-				Host: "localhost",
+				User:  "root",
+				Host:  "localhost",
 				Query: "SELECT * FROM dolt_branch_control WHERE user = 'testuser';",
-				Expected: []sql.Row{},
+				Expected: []sql.Row{
+					{"main", "testuser", "localhost", uint64(2), uint64(4), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+				},
 			},
 			{
-				User: "testuser",
-				Host: "localhost",
-				Query: "CALL DOLT_BRANCH('otherbranch');",
-				Expected: []sql.Row{{0}},
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 5 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
 			},
 			{
-				User: "testuser",
-				Host: "localhost",
-				Query: "SELECT * FROM dolt_branch_control WHERE user = 'testuser';",
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_MERGE('other');",
+				Expected: []sql.Row{{1, 0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_RESET('--hard', 'HEAD~1');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{ // Entries inserted without operations apply to every class
+				User:  "root",
+				Host:  "localhost",
+				Query: "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('other', 'testuser', 'localhost', 'write');",
 				Expected: []sql.Row{
-					{"otherbranch", "testuser", "localhost", uint64(1)},
+					{sql.NewOkResult(1)},
 				},
 			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control WHERE branch = 'other';",
+				Expected: []sql.Row{{"other", "testuser", "localhost", uint64(2), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil}},
+			},
 		},
-	},*/
-}
-
-func TestBranchControl(t *testing.T) {
+	},
+	{
+		Name: "Amending a commit requires permission to move the branch's head",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('main', 'testuser', 'localhost', 'write', 'direct_dml', 0);",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (1, 1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_COMMIT('-a', '--amend', '-m', 'amended setup commit');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"setup commit"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "UPDATE dolt_branch_control SET operations = 'direct_dml,ref_move' WHERE user = 'testuser';",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT LENGTH(DOLT_COMMIT('-a', '--amend', '-m', 'amended setup commit'));",
+				Expected: []sql.Row{{32}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT message FROM dolt_log LIMIT 2;",
+				Expected: []sql.Row{{"amended setup commit"}, {"checkpoint enginetest database mydb"}},
+			},
+		},
+	},
+	{
+		Name: "Ordered match mode uses the highest priority entry",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('%', 'testuser', 'localhost', 'write', 'all', 0);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('main', 'testuser', 'localhost', '', 'all', 10);",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "UPDATE test SET v1 = 2 WHERE pk = 1;",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_match_mode = 'ordered';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 3 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "UPDATE dolt_branch_control SET priority = -1 WHERE branch = 'main';",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "UPDATE test SET v1 = 3 WHERE pk = 1;",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_match_mode = 'union';",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "Audit mode allows denied operations on branches",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'root', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 2 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('otherbranch');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_enforcement = 'audit';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "UPDATE test SET v1 = 2 WHERE pk = 1;",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('otherbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // The branch control tables remain protected, so the audit cannot be used to grant permissions
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'admin');",
+				ExpectedErr: branch_control.ErrInsertingRow,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "DELETE FROM dolt_branch_namespace_control;",
+				ExpectedErr: branch_control.ErrDeletingRow,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_EXPORT();",
+				ExpectedErr: branch_control.ErrExportImportPermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_enforcement = 'enforce';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 3 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+		},
+	},
+	{
+		Name: "Export and import",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('other', 'testuser', 'localhost', 'admin', 'all', 0);",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'testuser', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_CONTROL_EXPORT();",
+				Expected: []sql.Row{{`{"access":[{"branch":"other","user":"testuser","host":"localhost","permissions":1,"operations":1,"priority":0}],"namespace":[{"branch":"other%","user":"testuser","host":"localhost"}]}`}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_EXPORT();",
+				ExpectedErr: branch_control.ErrExportImportPermissions,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       `CALL DOLT_BRANCH_CONTROL_IMPORT('{"access":[],"namespace":[]}', 'replace');`,
+				ExpectedErr: branch_control.ErrExportImportPermissions,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       `CALL DOLT_BRANCH_CONTROL_IMPORT('{"access":[],"namespace":[]}', 'overwrite');`,
+				ExpectedErr: branch_control.ErrInvalidImportMode,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    `CALL DOLT_BRANCH_CONTROL_IMPORT('{"access":[],"namespace":[]}', 'replace');`,
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control;",
+				Expected: []sql.Row{{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    `CALL DOLT_BRANCH_CONTROL_IMPORT('{"access":[{"branch":"other","user":"testuser","host":"localhost","permissions":1,"operations":1,"priority":0}],"namespace":[{"branch":"other%","user":"testuser","host":"localhost"}]}', 'merge');`,
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_branch_control;",
+				Expected: []sql.Row{
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+					{"other", "testuser", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+				},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_namespace_control;",
+				Expected: []sql.Row{{"other%", "testuser", "localhost", nil}},
+			},
+		},
+	},
+	{
+		Name: "Compacting requires admin on all branches",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('other', 'testuser', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_COMPACT();",
+				ExpectedErr: branch_control.ErrCompactPermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_CONTROL_COMPACT();",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_branch_control;",
+				Expected: []sql.Row{
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+					{"other", "testuser", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+				},
+			},
+		},
+	},
+	{
+		Name: "Status reports both sources and reloading requires a base",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('other', 'testuser', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('other%', 'testuser', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_STATUS();",
+				ExpectedErr: branch_control.ErrStatusPermissions,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_RELOAD();",
+				ExpectedErr: branch_control.ErrReloadPermissions,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "CALL DOLT_BRANCH_CONTROL_STATUS();",
+				Expected: []sql.Row{
+					{"base", "", int64(0), int64(0), int64(0), ""},
+					{"overlay", "", int64(1), int64(1), int64(0), "%"},
+				},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_RELOAD();",
+				ExpectedErr: branch_control.ErrNoBaseSource,
+			},
+		},
+	},
+	{
+		Name: "Filters and pruning match folded expressions",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('Feature%%', 'testuser', 'localhost', 'write', 'all', 0);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('feature1', 'Bob', 'localhost', 'write', 'all', 0);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('main', 'testuser', 'localhost', 'admin', 'all', 0);",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('FEATURE_%', 'testuser', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT branch, user FROM dolt_branch_control WHERE branch = 'FEATURE%%%';",
+				Expected: []sql.Row{{"feature%", "testuser"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT branch, user FROM dolt_branch_control WHERE branch IN ('MAIN', 'feature%%') ORDER BY branch;",
+				Expected: []sql.Row{{"feature%", "testuser"}, {"main", "testuser"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT branch, user FROM dolt_branch_control WHERE user = 'bob';",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "DELETE FROM dolt_branch_control WHERE branch LIKE 'FEATURE1';",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				// The super user is returned by the filter, but it is never deleted
+				User:     "root",
+				Host:     "localhost",
+				Query:    "DELETE FROM dolt_branch_control WHERE user = 'root';",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control WHERE branch = '%';",
+				Expected: []sql.Row{{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_PRUNE('feature%');",
+				ExpectedErr: branch_control.ErrPrunePermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_CONTROL_PRUNE('FEATURE%');",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_branch_control;",
+				Expected: []sql.Row{
+					{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+					{"main", "testuser", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+				},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_namespace_control;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_CONTROL_PRUNE('%');",
+				Expected: []sql.Row{{1, 0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control;",
+				Expected: []sql.Row{{"%", "root", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil}},
+			},
+		},
+	},
+	{
+		Name: "Deduplication requires a global admin",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_DEDUP();",
+				ExpectedErr: branch_control.ErrDedupPermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_CONTROL_DEDUP();",
+				Expected: []sql.Row{{0, 0}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "CALL DOLT_BRANCH_CONTROL_STATUS();",
+				Expected: []sql.Row{
+					{"base", "", int64(0), int64(0), int64(0), ""},
+					{"overlay", "", int64(1), int64(0), int64(0), "%"},
+				},
+			},
+		},
+	},
+	{
+		Name: "Changing the default branch requires admin on both branches",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER admin@localhost;",
+			"GRANT ALL ON *.* TO admin@localhost;",
+			"CALL DOLT_BRANCH('dev');",
+			"CALL DOLT_BRANCH('other');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'admin', 'localhost', 'admin'), ('dev', 'admin', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SET GLOBAL mydb_default_branch = 'dev';",
+				ExpectedErr: branch_control.ErrChangingDefault,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SET PERSIST mydb_default_branch = 'dev';",
+				ExpectedErr: branch_control.ErrChangingDefault,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT @@GLOBAL.mydb_default_branch;",
+				Expected: []sql.Row{{""}},
+			},
+			{ // The admin isn't an admin on the new branch
+				User:        "admin",
+				Host:        "localhost",
+				Query:       "SET GLOBAL mydb_default_branch = 'other';",
+				ExpectedErr: branch_control.ErrChangingDefault,
+			},
+			{
+				User:     "admin",
+				Host:     "localhost",
+				Query:    "SET GLOBAL mydb_default_branch = 'dev';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_CONTROL_DEFAULT_BRANCHES();",
+				ExpectedErr: branch_control.ErrStatusPermissions,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "CALL DOLT_BRANCH_CONTROL_DEFAULT_BRANCHES();",
+				Expected: []sql.Row{
+					{"mydb", "dev", "main", "admin@localhost", "2022-10-19 12:00:00"},
+				},
+			},
+			{ // The old default branch is checked as well
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SET GLOBAL mydb_default_branch = 'main';",
+				ExpectedErr: branch_control.ErrChangingDefault,
+			},
+			{
+				User:     "admin",
+				Host:     "localhost",
+				Query:    "SET GLOBAL mydb_default_branch = '';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "CALL DOLT_BRANCH_CONTROL_DEFAULT_BRANCHES();",
+				Expected: []sql.Row{
+					{"mydb", "main", "dev", "admin@localhost", "2022-10-19 12:00:00"},
+				},
+			},
+		},
+	},
+	{
+		Name: "Entries only apply within their window",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, window_start, window_end, window_days) VALUES ('main', 'testuser', 'localhost', 'write', '09:00:00', '17:00:00', 'monday,tuesday,wednesday,thursday,friday');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT window_start, window_end, window_days FROM dolt_branch_control WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.Timespan(32400000000), sql.Timespan(61200000000), uint64(62)},
+				},
+			},
+			{ // The clock is frozen to a Wednesday at noon UTC
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (1, 1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "UPDATE dolt_branch_control SET window_start = '13:00:00' WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (2, 2);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "UPDATE dolt_branch_control SET window_start = '00:00:00', window_days = 'saturday,sunday' WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (2, 2);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_branch_control SET window_start = '18:00:00' WHERE user = 'testuser';",
+				ExpectedErr: branch_control.ErrInvalidWindow,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions, window_days) VALUES ('other', 'testuser', 'localhost', 'write', '');",
+				ExpectedErr: branch_control.ErrInvalidWindowDays,
+			},
+		},
+	},
+	{
+		Name: "Expired entries no longer apply",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, expires_at) VALUES ('main', 'testuser', 'localhost', 'write', '2022-10-19 13:00:00');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT expires_at FROM dolt_branch_control WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{time.Date(2022, time.October, 19, 13, 0, 0, 0, time.UTC)},
+				},
+			},
+			{ // The clock is frozen to a Wednesday at noon UTC
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (1, 1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "UPDATE dolt_branch_control SET expires_at = '2022-10-19 11:00:00' WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (2, 2);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "UPDATE dolt_branch_control SET expires_at = NULL WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (2, 2);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "Tags require write permissions once entries apply to tags",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'write');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT refs FROM dolt_branch_control WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{uint64(1)},
+				},
+			},
+			{ // Tags are unrestricted until an entry applies to them
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('v0');",
+				Expected: []sql.Row{{int64(0)}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "INSERT INTO dolt_branch_control (branch, user, host, permissions, refs) VALUES ('v%', 'testuser', 'localhost', 'write', 'tags');",
+				Expected: []sql.Row{
+					{sql.NewOkResult(1)},
+				},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('v1');",
+				Expected: []sql.Row{{int64(0)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_TAG('release');",
+				ExpectedErr: branch_control.ErrCannotModifyTag,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('release');",
+				Expected: []sql.Row{{int64(0)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_TAG('-d', 'v1', 'release');",
+				ExpectedErr: branch_control.ErrCannotModifyTag,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT tag_name FROM dolt_tags ORDER BY tag_name;",
+				Expected: []sql.Row{{"release"}, {"v0"}, {"v1"}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('-d', 'v1');",
+				Expected: []sql.Row{{int64(0)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_tags (tag_name, tag_hash) VALUES ('release2', 'HEAD');",
+				ExpectedErr: branch_control.ErrCannotModifyTag,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_tags (tag_name, tag_hash) VALUES ('v2', 'HEAD');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_tags SET tag_name = 'release2' WHERE tag_name = 'v2';",
+				ExpectedErr: branch_control.ErrCannotModifyTag,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "DELETE FROM dolt_tags WHERE tag_name = 'release';",
+				ExpectedErr: branch_control.ErrCannotModifyTag,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions, refs) VALUES ('%', 'otheruser', 'localhost', 'write', '');",
+				ExpectedErr: branch_control.ErrInvalidRefs,
+			},
+		},
+	},
+	{
+		Name: "Table patterns restrict writes to matching tables",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"CREATE TABLE staging_test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, table_pattern) VALUES ('main', 'testuser', 'localhost', 'write', 'STAGING%');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT table_pattern FROM dolt_branch_control WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{"staging%"},
+				},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO staging_test VALUES (1, 1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:  "testuser",
+				Host:  "localhost",
+				Query: "UPDATE staging_test SET v1 = 2 WHERE pk = 1;",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (1, 1);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{ // Entries with a table pattern don't apply to schema changes
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "ALTER TABLE staging_test ADD COLUMN v2 BIGINT;",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "UPDATE dolt_branch_control SET table_pattern = NULL WHERE user = 'testuser';",
+				Expected: []sql.Row{
+					{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}},
+				},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (1, 1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "Moves of branches that require approval are proposed",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'first commit');",
+			"INSERT INTO test VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'second commit');",
+			"CALL DOLT_BRANCH('other');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, requires_approval) VALUES ('main', 'testuser', 'localhost', 'write', true);",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:            "testuser",
+				Host:            "localhost",
+				Query:           "CALL DOLT_RESET('--hard', 'HEAD~1');",
+				Expected:        []sql.Row{{0}},
+				ExpectedWarning: 1105,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT branch, proposer, created_at FROM dolt_branch_pending_moves;",
+				Expected: []sql.Row{{"main", "testuser@localhost", branchControlTestTime}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_APPROVE_MOVE('main', 'HEAD~1');",
+				ExpectedErr: branch_control.ErrApprovingMove,
+			},
+			{ // The approver names the commit that they expect the move to be to
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_APPROVE_MOVE('main', 'HEAD');",
+				ExpectedErr: branch_control.ErrUnexpectedPendingMove,
+			},
+			{ // Resetting to the parent is not a fast-forward
+				User:           "root",
+				Host:           "localhost",
+				Query:          "CALL DOLT_APPROVE_MOVE('main', 'HEAD~1');",
+				ExpectedErrStr: "the proposed move of branch `main` is not a fast-forward, use --force to apply it anyway",
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_APPROVE_MOVE('--force', 'main', 'HEAD~1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_pending_moves;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSE_MOVE('main', 'other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_REJECT_MOVE('main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_REJECT_MOVE('main');",
+				ExpectedErr: branch_control.ErrNoPendingMove,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSE_MOVE('main', 'other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_APPROVE_MOVE('main', 'other');",
+				ExpectedErr: branch_control.ErrApprovingOwnMove,
+			},
+			{ // Only the proposer may withdraw a proposal without being an admin
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_REJECT_MOVE('main');",
+				ExpectedErr: branch_control.ErrApprovingMove,
+			},
+			{ // A pending proposal isn't replaced by another until it's approved or rejected
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_PROPOSE_MOVE('main', 'HEAD');",
+				ExpectedErr: branch_control.ErrMovePending,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_REJECT_MOVE('main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSE_MOVE('main', 'other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_APPROVE_MOVE('main', 'other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "Approved moves of branches that aren't checked out move the head once",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"CALL DOLT_COMMIT('-Am', 'first commit');",
+			"CALL DOLT_BRANCH('feature');",
+			"INSERT INTO test VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-am', 'second commit');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions, requires_approval) VALUES ('feature', 'testuser', 'localhost', 'write', true);",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSE_MOVE('feature', 'main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_APPROVE_MOVE('feature', 'main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/feature`.test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{ // The branch was created and then moved
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_reflog('feature');",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "Branch permissions are reported for the current user",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER a@localhost;",
+			"GRANT ALL ON *.* TO a@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main%', 'a', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('ma%', 'a', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('main'), dolt_branch_permissions('other');",
+				Expected: []sql.Row{{"write", "write"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('main');",
+				Expected: []sql.Row{{"admin"}},
+			},
+			{ // Overlapping entries have their permissions combined
+				User:     "a",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('main'), dolt_branch_permissions('mat'), dolt_branch_permissions('other');",
+				Expected: []sql.Row{{"admin,write", "admin", ""}},
+			},
+			{ // Branches are folded and matched case-insensitively, just as the entries are
+				User:     "a",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('MAIN');",
+				Expected: []sql.Row{{"admin,write"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('main', 'a', 'localhost'), dolt_branch_permissions('other', 'testuser', 'LOCALHOST'), dolt_branch_permissions('other', 'b', 'localhost');",
+				Expected: []sql.Row{{"admin,write", "write", ""}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions(NULL);",
+				Expected: []sql.Row{{nil}},
+			},
+			{ // Only admins on every branch may view the permissions of other users
+				User:        "a",
+				Host:        "localhost",
+				Query:       "SELECT dolt_branch_permissions('main', 'testuser', 'localhost');",
+				ExpectedErr: branch_control.ErrAuditPermissions,
+			},
+		},
+	},
+	{
+		Name: "Nested namespaces inherit from their parent namespaces until overridden",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER a@localhost;",
+			"GRANT ALL ON *.* TO a@localhost;",
+			"CREATE USER b@localhost;",
+			"GRANT ALL ON *.* TO b@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'a', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'b', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('team1/%', 'a', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // Only the parent namespace exists, so it applies to the nested namespace
+				User:        "b",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('team1/alice/feature');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "a",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('team1/alice/feature');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('team1/alice/%', 'b', 'localhost');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_branch_namespace_control ORDER BY branch;",
+				Expected: []sql.Row{
+					{"team1/%", "a", "localhost", nil},
+					{"team1/alice/%", "b", "localhost", "team1/%"},
+				},
+			},
+			{ // The nested namespace overrides its parent, while the rest of the parent is unchanged
+				User:        "a",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('team1/alice/other');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+			{
+				User:     "b",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('team1/alice/other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "b",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('team1/bob');",
+				ExpectedErr: branch_control.ErrCannotCreateBranch,
+			},
+		},
+	},
+	{
+		Name: "Match expressions are expanded into the branches and users that they cover",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CALL DOLT_BRANCH('feature1');",
+			"CALL DOLT_BRANCH('feature_2');",
+			"CALL DOLT_BRANCH('other');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'alice', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_namespace_control (branch, user, host) VALUES ('feature%', 'alan', 'localhost');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control_matches('FEATURE%');",
+				Expected: []sql.Row{{"branch", "feature1"}, {"branch", "feature_2"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control_matches('al%');",
+				Expected: []sql.Row{{"user", "alan"}, {"user", "alice"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT type, name FROM dolt_branch_control_matches('%') WHERE type = 'branch' ORDER BY name;",
+				Expected: []sql.Row{{"branch", "feature1"}, {"branch", "feature_2"}, {"branch", "main"}, {"branch", "other"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control_matches('missing');",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "Branch control checks explain the permissions of a user",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER a@localhost;",
+			"GRANT ALL ON *.* TO a@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main%', 'a', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('ma%', 'a', 'localhost', 'read');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT JSON_UNQUOTE(JSON_EXTRACT(dolt_branch_control_check('a', 'localhost', 'MAIN'), '$.permissions')), JSON_EXTRACT(dolt_branch_control_check('a', 'localhost', 'main'), '$.entries[*].branch');",
+				Expected: []sql.Row{{"write,read", sql.MustJSON(`["main%", "ma%"]`)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT JSON_UNQUOTE(JSON_EXTRACT(dolt_branch_control_check('a', 'localhost', 'mat'), '$.entries[0].branch')), JSON_EXTRACT(dolt_branch_control_check('a', 'localhost', 'mat'), '$.frozen');",
+				Expected: []sql.Row{{"ma%", sql.MustJSON("false")}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT JSON_EXTRACT(dolt_branch_control_check('a', 'localhost', 'other'), '$.entries'), JSON_EXTRACT(dolt_branch_control_check('root', 'localhost', 'other'), '$.super_user');",
+				Expected: []sql.Row{{sql.MustJSON("[]"), sql.MustJSON("true")}},
+			},
+			{ // Only admins on every branch may check the permissions of other users
+				User:        "a",
+				Host:        "localhost",
+				Query:       "SELECT dolt_branch_control_check('a', 'localhost', 'main');",
+				ExpectedErr: branch_control.ErrAuditPermissions,
+			},
+		},
+	},
+	{
+		Name: "Commits are checked against the current permissions of their authors",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'alice', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'carol', '%', 'admin');",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"CREATE TABLE authors (email VARCHAR(100) PRIMARY KEY, user VARCHAR(100), host VARCHAR(100));",
+			"INSERT INTO authors VALUES ('alice@example.com', 'alice', 'localhost'), ('bob@example.com', 'bob', 'localhost'), ('carol@example.com', 'carol', 'localhost');",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('--author', 'Alice <alice@example.com>', '-m', 'alice commit');",
+			"INSERT INTO test VALUES (2, 2);",
+			"CALL DOLT_COMMIT('--author', 'Bob <bob@example.com>', '-am', 'bob commit');",
+			"INSERT INTO test VALUES (3, 3);",
+			"CALL DOLT_COMMIT('--author', 'Carol <carol@example.com>', '-am', 'carol commit');",
+			"INSERT INTO test VALUES (4, 4);",
+			"CALL DOLT_COMMIT('--author', 'Dave <dave@example.com>', '-am', 'dave commit');",
+			"CALL DOLT_BRANCH('dev');",
+			"CALL DOLT_TAG('v1');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // Committers that aren't mapped to an account are NULL
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT message, policy_violation FROM dolt_log('--check-policy', 'authors') WHERE email LIKE '%@example.com' ORDER BY commit_order;",
+				Expected: []sql.Row{{"alice commit", false}, {"bob commit", true}, {"carol commit", false}, {"dave commit", nil}},
+			},
+			{ // Commits are checked against the branch that's logged
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT message, policy_violation FROM dolt_log('dev', '--check-policy', 'AUTHORS') WHERE email LIKE '%@example.com' ORDER BY commit_order;",
+				Expected: []sql.Row{{"alice commit", true}, {"bob commit", true}, {"carol commit", false}, {"dave commit", nil}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'bob', 'localhost', 'write');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{ // Verdicts reflect the rules at the time of the query
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT message, policy_violation FROM dolt_log('main', '--check-policy', 'authors') WHERE email = 'bob@example.com';",
+				Expected: []sql.Row{{"bob commit", false}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_log('v1', '--check-policy', 'authors');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_log('--check-policy', 'missing');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{ // The permissions of other users may only be checked by admins on every branch
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM dolt_log('--check-policy', 'authors');",
+				ExpectedErr: branch_control.ErrAuditPermissions,
+			},
+			{ // The mapping is only read when the column is
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT message FROM dolt_log('--check-policy', 'authors') WHERE email = 'dave@example.com';",
+				Expected: []sql.Row{{"dave commit"}},
+			},
+		},
+	},
+	{
+		Name: "Frozen branches may only be modified by the super user",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER a@localhost;",
+			"GRANT ALL ON *.* TO a@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT);",
+			"INSERT INTO test VALUES (1, 1);",
+			"CALL DOLT_BRANCH('other');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('%', 'a', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_FREEZE('main', 'incident');",
+				ExpectedErr: branch_control.ErrFreezePermissions,
+			},
+			{
+				User:     "a",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_FREEZE('MAIN', 'incident');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_freeze;",
+				Expected: []sql.Row{{"main", "a@localhost", branchControlTestTime, "incident"}},
+			},
+			{ // The freeze overrides every entry, including those that make the user an admin
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 2 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrBranchFrozen,
+			},
+			{
+				User:        "a",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 2 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrBranchFrozen,
+			},
+			{
+				User:        "a",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('-d', 'main');",
+				ExpectedErr: branch_control.ErrBranchFrozen,
+			},
+			{ // Branches that are not frozen are unaffected
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-c', 'main', 'copy');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "a",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-d', 'other');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // Entries that affect the frozen branch may not be edited to circumvent the freeze
+				User:        "a",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'admin');",
+				ExpectedErr: branch_control.ErrEditingFrozenRow,
+			},
+			{
+				User:        "a",
+				Host:        "localhost",
+				Query:       "UPDATE dolt_branch_control SET host = '127.0.0.1' WHERE user = 'testuser';",
+				ExpectedErr: branch_control.ErrEditingFrozenRow,
+			},
+			{
+				User:        "a",
+				Host:        "localhost",
+				Query:       "DELETE FROM dolt_branch_control WHERE user = 'testuser';",
+				ExpectedErr: branch_control.ErrEditingFrozenRow,
+			},
+			{
+				User:     "a",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('other', 'testuser', 'localhost', 'admin');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{ // The super user is never affected by a freeze
+				User:     "root",
+				Host:     "localhost",
+				Query:    "UPDATE test SET v1 = 3 WHERE pk = 1;",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'admin');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "UPDATE test SET v1 = 4 WHERE pk = 1;",
+				ExpectedErr: branch_control.ErrBranchFrozen,
+			},
+			{
+				User:     "a",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH_UNFREEZE('main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "a",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH_UNFREEZE('main');",
+				ExpectedErr: branch_control.ErrNotFrozen,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_freeze;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "UPDATE test SET v1 = 4 WHERE pk = 1;",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+		},
+	},
+	{
+		Name: "Creating branch creates new entry",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('owned%', 'testuser', 'localhost', 'admin');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{ // Nothing is granted while the variable is off
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('offbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT branch FROM dolt_branch_control WHERE user = 'testuser';",
+				Expected: []sql.Row{{"owned%"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_auto_grant = 'on';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('otherbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('-b', 'feature_1');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // An existing entry already makes the creator an admin
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('owned1');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // The super user is always an admin
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('rootbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{ // Wildcards in the branch name are escaped, so that the entry only matches the created branch
+				User:  "testuser",
+				Host:  "localhost",
+				Query: "SELECT * FROM dolt_branch_control WHERE user = 'testuser' ORDER BY branch;",
+				Expected: []sql.Row{
+					{"feature\\_1", "testuser", "localhost", uint64(3), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+					{"otherbranch", "testuser", "localhost", uint64(3), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+					{"owned%", "testuser", "localhost", uint64(1), uint64(1), int64(0), sql.Timespan(0), sql.Timespan(86400000000), uint64(127), false, nil, uint64(1), nil},
+				},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('otherbranch'), dolt_branch_permissions('feature_1'), dolt_branch_permissions('featureX1');",
+				Expected: []sql.Row{{"admin,write", "admin,write", ""}},
+			},
+			{ // Grants remain once their branch is deleted unless cleanup is selected
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-d', 'otherbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT count(*) FROM dolt_branch_control WHERE branch = 'otherbranch';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_auto_grant = 'cleanup';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-d', 'feature_1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT branch FROM dolt_branch_control WHERE user = 'testuser' ORDER BY branch;",
+				Expected: []sql.Row{{"otherbranch"}, {"owned%"}},
+			},
+			{ // Renaming moves the grant to the new name
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-c', 'main', 'copied');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-m', 'copied', 'renamed');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT branch FROM dolt_branch_control WHERE user = 'testuser' ORDER BY branch;",
+				Expected: []sql.Row{{"otherbranch"}, {"owned%"}, {"renamed"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET GLOBAL dolt_branch_control_auto_grant = 'off';",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "Roles grant their permissions to every member",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER otheruser@localhost;",
+			"GRANT ALL ON *.* TO otheruser@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', '@devs', '%', 'write');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (1);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control_roles VALUES ('devs', 'testuser', '%');",
+				ExpectedErr: branch_control.ErrRolePermissions,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_branch_control_roles VALUES ('devs', 'testuser', '%');",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (1);",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "otheruser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (2);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_branch_control_roles VALUES ('admins', '@devs', '%');",
+				ExpectedErr: branch_control.ErrNestedRole,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_control_roles;",
+				Expected: []sql.Row{{"devs", "testuser", "%"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "DELETE FROM dolt_branch_control_roles WHERE role = 'devs';",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (3);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+		},
+	},
+	{
+		Name: "Branches with read entries require a permission on the branch",
+		SetUpScript: append(TestUserSetUpScripts,
+			"CALL DOLT_BRANCH('secret');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('other', 'testuser', 'localhost', 'read');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('secret', 'admin', 'localhost', 'read');",
+		),
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/other`.test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/main`.test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM `mydb/secret`.test;",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM test AS OF 'secret';",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_CHECKOUT('secret');",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT COUNT(*) FROM dolt_log('secret');",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT COUNT(*) FROM dolt_diff('main', 'secret', 'test');",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"main"}, {"other"}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT dolt_branch_permissions('other'), dolt_branch_permissions('main'), dolt_branch_permissions('secret');",
+				Expected: []sql.Row{{"read", "write", ""}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/secret`.test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"main"}, {"other"}, {"secret"}},
+			},
+		},
+	},
+	{
+		Name: "Commits that only hidden branches can reach are hidden",
+		SetUpScript: append(TestUserSetUpScripts,
+			"CALL DOLT_BRANCH('secret');",
+			"CALL DOLT_CHECKOUT('secret');",
+			"INSERT INTO test VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'secret commit');",
+			"CALL DOLT_TAG('secret_tag', 'secret');",
+			"CALL DOLT_CHECKOUT('main');",
+			"CREATE TABLE secret_hash (h VARCHAR(64) PRIMARY KEY);",
+			"INSERT INTO secret_hash VALUES (HASHOF('secret'));",
+			"CALL DOLT_COMMIT('-Am', 'record the secret hash');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('secret', 'admin', 'localhost', 'read');",
+		),
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_commits WHERE message = 'secret commit';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_commit_ancestors WHERE commit_hash IN (SELECT h FROM secret_hash);",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_reflog WHERE ref = 'refs/heads/secret';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT DOLT_MERGE_BASE((SELECT h FROM secret_hash), 'main');",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM test AS OF 'secret_tag';",
+				ExpectedErr: branch_control.ErrCannotReadBranch,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_commits WHERE message = 'secret commit';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) > 0 FROM dolt_reflog WHERE ref = 'refs/heads/secret';",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test AS OF 'secret_tag' ORDER BY pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "Branches without read entries may be read by anyone",
+		SetUpScript: append(TestUserSetUpScripts,
+			"INSERT INTO dolt_branch_control (branch, user, host, permissions) VALUES ('main', 'testuser', 'localhost', 'write');",
+		),
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/other`.test;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test AS OF 'other';",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_diff('main', 'other', 'test');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"main"}, {"other"}},
+			},
+		},
+	},
+}
+
+func TestBranchControl(t *testing.T) {
 	t.Skip("Branch control isn't globally enabled yet, so tests would fail")
+	defer branch_control.SetTimeSource(branch_control.SetTimeSource(func() time.Time {
+		return branchControlTestTime
+	}))
 	for _, test := range BranchControlTests {
 		harness := newDoltHarness(t)
 		t.Run(test.Name, func(t *testing.T) {
@@ -637,6 +2780,10 @@ func TestBranchControl(t *testing.T) {
 					t.Run(assertion.Query, func(t *testing.T) {
 						enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, assertion.ExpectedErrStr)
 					})
+				} else if assertion.ExpectedWarning != 0 {
+					t.Run(assertion.Query, func(t *testing.T) {
+						enginetest.AssertWarningAndTestQuery(t, engine, ctx, harness, assertion.Query, assertion.Expected, nil, assertion.ExpectedWarning, 1, "", false)
+					})
 				} else {
 					t.Run(assertion.Query, func(t *testing.T) {
 						enginetest.TestQueryWithContext(t, ctx, engine, harness, assertion.Query, assertion.Expected, nil, nil)
@@ -674,7 +2821,7 @@ func TestBranchControlBlocks(t *testing.T) {
 				Address: "localhost",
 			})
 			enginetest.AssertErrWithCtx(t, engine, harness, userCtx, test.Query, test.ExpectedErr)
-			addUserQuery := "INSERT INTO dolt_branch_control VALUES ('main', 'testuser', 'localhost', 'write');"
+			addUserQuery := "INSERT INTO dolt_branch_control (branch, user, host, permissions, operations, priority) VALUES ('main', 'testuser', 'localhost', 'write', 'all', 0);"
 			addUserQueryResults := []sql.Row{{sql.NewOkResult(1)}}
 			enginetest.TestQueryWithContext(t, rootCtx, engine, harness, addUserQuery, addUserQueryResults, nil, nil)
 			sch, iter, err := engine.Query(userCtx, test.Query)
@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/information_schema"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/stretchr/testify/require"
@@ -189,6 +190,12 @@ func (d *DoltHarness) NewEngine(t *testing.T) (*gms.Engine, error) {
 		if err != nil {
 			return nil, err
 		}
+		// The engine is created with the default analyzer, which doesn't have the rules that Dolt adds to its engines
+		builder := sqle.AddAnalyzerRules(analyzer.NewBuilder(pro))
+		if d.Parallelism() > 1 {
+			builder = builder.WithParallelism(d.Parallelism())
+		}
+		e.Analyzer.Batches = builder.Build().Batches
 		d.engine = e
 
 		var res []sql.Row
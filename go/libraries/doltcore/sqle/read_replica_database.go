@@ -157,7 +157,7 @@ func (rrd ReadReplicaDatabase) PullFromRemote(ctx *sql.Context) error {
 	default:
 		return fmt.Errorf("%w: dolt_replicate_heads not set", ErrInvalidReplicateHeadsSetting)
 	}
-	return nil
+	return pullBranchControl(ctx, rrd)
 }
 
 type pullBehavior bool
@@ -239,7 +239,10 @@ func pullBranches(ctx *sql.Context, rrd ReadReplicaDatabase, branches []string,
 				}
 
 				ws, err := rrd.ddb.ResolveWorkingSet(ctx, wsRef)
-				if err != nil {
+				if err == doltdb.ErrWorkingSetNotFound {
+					// The branch was fetched without ever being checked out on this replica
+					ws = doltdb.EmptyWorkingSet(wsRef)
+				} else if err != nil {
 					return err
 				}
 
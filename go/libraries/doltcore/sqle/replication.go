@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
@@ -129,6 +130,13 @@ func ApplyReplicationConfig(ctx context.Context, bThreads *sql.BackgroundThreads
 			return nil, err
 		}
 		dEnv.DoltDB.SetCommitHooks(ctx, postCommitHooks)
+		if len(postCommitHooks) > 0 && shouldReplicateBranchControl() {
+			// The branch control tables are replicated through a ref of every replicated database
+			ddb := dEnv.DoltDB
+			branch_control.AddReplicationTarget(ctx, func(data []byte) error {
+				return replicateBranchControl(context.Background(), ddb, data)
+			})
+		}
 
 		if _, remote, ok := sql.SystemVariables.GetGlobal(dsess.ReadReplicaRemote); ok && remote != "" {
 			remoteName, ok := remote.(string)
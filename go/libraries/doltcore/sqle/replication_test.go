@@ -20,6 +20,7 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/buffer"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -96,3 +97,63 @@ func TestReplicationBranches(t *testing.T) {
 		assert.Equal(t, diffNames, tt.expToDelete)
 	}
 }
+
+func TestReplicateBranchControl(t *testing.T) {
+	ctx := context.Background()
+	dEnv := CreateEnvWithSeedData(t)
+	ddb := dEnv.DoltDB
+
+	_, _, ok, err := readBranchControl(ctx, ddb)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, replicateBranchControl(ctx, ddb, []byte("first")))
+	data, firstHash, ok, err := readBranchControl(ctx, ddb)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []byte("first"), data)
+
+	// An unchanged snapshot is not committed again
+	require.NoError(t, replicateBranchControl(ctx, ddb, []byte("first")))
+	_, h, _, err := readBranchControl(ctx, ddb)
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, h)
+
+	require.NoError(t, replicateBranchControl(ctx, ddb, []byte("second")))
+	data, h, _, err = readBranchControl(ctx, ddb)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+	assert.NotEqual(t, firstHash, h)
+	// The ref is internal, so it does not appear as a branch
+	branches, err := ddb.GetBranches(ctx)
+	require.NoError(t, err)
+	for _, branch := range branches {
+		assert.NotEqual(t, branchControlRef.GetPath(), branch.GetPath())
+	}
+}
+
+func TestPullBranchControl(t *testing.T) {
+	ctx := context.Background()
+	first := ReadReplicaDatabase{Database: Database{name: "first"}, srcDB: CreateEnvWithSeedData(t).DoltDB}
+	second := ReadReplicaDatabase{Database: Database{name: "second"}, srcDB: CreateEnvWithSeedData(t).DoltDB}
+	require.NoError(t, replicateBranchControl(ctx, first.srcDB, []byte("first")))
+	require.NoError(t, replicateBranchControl(ctx, second.srcDB, []byte("second")))
+	_, firstHash, _, err := readBranchControl(ctx, first.srcDB)
+	require.NoError(t, err)
+	_, secondHash, _, err := readBranchControl(ctx, second.srcDB)
+	require.NoError(t, err)
+
+	// Nothing is loaded unless replication of the branch control tables is enabled
+	require.NoError(t, pullBranchControl(ctx, first))
+	assert.NotContains(t, appliedBranchControl.hashes, "first")
+
+	require.NoError(t, sql.SystemVariables.SetGlobal(dsess.ReplicateBranchControl, int8(1)))
+	defer func() {
+		require.NoError(t, sql.SystemVariables.SetGlobal(dsess.ReplicateBranchControl, int8(0)))
+	}()
+	require.NoError(t, pullBranchControl(ctx, first))
+	require.NoError(t, pullBranchControl(ctx, second))
+	// Each database tracks the snapshot that was loaded from it
+	assert.Equal(t, firstHash, appliedBranchControl.hashes["first"])
+	assert.Equal(t, secondHash, appliedBranchControl.hashes["second"])
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*SchemaConflictsTable)(nil)
+
+// SchemaConflictsTable is a sql.Table implementation of the dolt_schema_conflicts system table, which shows the tables
+// whose schemas could not be merged by the merge in progress. Each table is shown with the CREATE TABLE statements of
+// its base, our and their schemas, and a JSON description of each of its conflicts. This is in the sqle package, rather
+// than dtables, as formatting the schemas relies on the sqle package.
+type SchemaConflictsTable struct {
+	dbName string
+	ddb    *doltdb.DoltDB
+}
+
+// NewSchemaConflictsTable creates a SchemaConflictsTable
+func NewSchemaConflictsTable(_ *sql.Context, dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &SchemaConflictsTable{dbName: dbName, ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// SchemaConflictsTableName
+func (sct *SchemaConflictsTable) Name() string {
+	return doltdb.SchemaConflictsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// SchemaConflictsTableName
+func (sct *SchemaConflictsTable) String() string {
+	return doltdb.SchemaConflictsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the schema conflicts system table.
+func (sct *SchemaConflictsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table_name", Type: sql.Text, Source: doltdb.SchemaConflictsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "base_schema", Type: sql.Text, Source: doltdb.SchemaConflictsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "our_schema", Type: sql.Text, Source: doltdb.SchemaConflictsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "their_schema", Type: sql.Text, Source: doltdb.SchemaConflictsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "description", Type: sql.JSON, Source: doltdb.SchemaConflictsTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (sct *SchemaConflictsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (sct *SchemaConflictsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (sct *SchemaConflictsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	ws, err := sess.WorkingSet(ctx, sct.dbName)
+	if err != nil {
+		return nil, err
+	}
+	if !ws.MergeActive() || !ws.MergeState().HasSchemaConflicts() {
+		return sql.RowsToRowIter(), nil
+	}
+
+	headCommit, err := sess.GetHeadCommit(ctx, sct.dbName)
+	if err != nil {
+		return nil, err
+	}
+	mergeCommit := ws.MergeState().Commit()
+	ancCommit, err := doltdb.GetCommitAncestor(ctx, headCommit, mergeCommit)
+	if err != nil {
+		return nil, err
+	}
+	ancRoot, err := ancCommit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	theirRoot, err := mergeCommit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ourRoot := ws.MergeState().PreMergeWorkingRoot()
+
+	var rows []sql.Row
+	for _, tblName := range ws.MergeState().UnmergableTables() {
+		row, err := schemaConflictRow(ctx, tblName, ourRoot, theirRoot, ancRoot)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// schemaConflictRow returns the row of the schema conflicts table for the table named, computing its conflicts by
+// merging its schemas again.
+func schemaConflictRow(ctx *sql.Context, tblName string, ourRoot, theirRoot, ancRoot *doltdb.RootValue) (sql.Row, error) {
+	ourSch, err := schemaOrNil(ctx, ourRoot, tblName)
+	if err != nil {
+		return nil, err
+	}
+	theirSch, err := schemaOrNil(ctx, theirRoot, tblName)
+	if err != nil {
+		return nil, err
+	}
+	ancSch, err := schemaOrNil(ctx, ancRoot, tblName)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []interface{}
+	if ourSch != nil && theirSch != nil {
+		// A table added on both branches is merged with our schema as its ancestor
+		mergeAncSch := ancSch
+		if mergeAncSch == nil {
+			mergeAncSch = ourSch
+		}
+		_, sc, err := merge.SchemaMerge(ctx, ourRoot.VRW().Format(), ourSch, theirSch, mergeAncSch, tblName)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range sc.ColConflicts {
+			conflicts = append(conflicts, schemaConflictDescription("column", c.Ours.Name, c.Theirs.Name, c.String()))
+		}
+		for _, c := range sc.IdxConflicts {
+			conflicts = append(conflicts, schemaConflictDescription("index", nameOf(c.Ours), nameOf(c.Theirs), c.String()))
+		}
+		for _, c := range sc.ChkConflicts {
+			conflicts = append(conflicts, schemaConflictDescription("check", nameOf(c.Ours), nameOf(c.Theirs), c.String()))
+		}
+	}
+	description, err := sql.JSON.Convert(conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	row := sql.Row{tblName}
+	for _, sch := range []schema.Schema{ancSch, ourSch, theirSch} {
+		ddl, err := createTableStmtOrNil(ctx, tblName, sch)
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, ddl)
+	}
+	return append(row, description), nil
+}
+
+func schemaConflictDescription(typ, ours, theirs, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        typ,
+		"ours":        ours,
+		"theirs":      theirs,
+		"description": description,
+	}
+}
+
+// nameOf returns the name of the given index or check, which is empty when there is none.
+func nameOf(named interface{ Name() string }) string {
+	if named == nil {
+		return ""
+	}
+	return named.Name()
+}
+
+func schemaOrNil(ctx *sql.Context, root *doltdb.RootValue, tblName string) (schema.Schema, error) {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return tbl.GetSchema(ctx)
+}
+
+func createTableStmtOrNil(ctx *sql.Context, tblName string, sch schema.Schema) (interface{}, error) {
+	if sch == nil {
+		return nil, nil
+	}
+	sqlCtx, engine, _ := PrepareCreateTableStmt(ctx, NewSingleTableDatabase(tblName, sch, nil, nil))
+	return GetCreateTableStmt(sqlCtx, engine, tblName)
+}
@@ -17,6 +17,7 @@ package sqle
 import (
 	"github.com/dolthub/go-mysql-server/sql"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -87,6 +88,14 @@ func AddDoltSystemVariables() {
 			Type:              sql.NewSystemBoolType(dsess.ReplicateAllHeads),
 			Default:           int8(0),
 		},
+		{
+			Name:              dsess.ReplicateBranchControl,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemBoolType(dsess.ReplicateBranchControl),
+			Default:           int8(0),
+		},
 		{
 			Name:              dsess.AsyncReplication,
 			Scope:             sql.SystemVariableScope_Global,
@@ -159,6 +168,71 @@ func AddDoltSystemVariables() {
 			Type:              sql.NewSystemStringType(dsess.AwsCredsRegion),
 			Default:           nil,
 		},
+		{ // If true, dolt_log returns commit metadata as raw bytes rather than sanitized text.
+			Name:              dsess.LogRawCommitMetadata,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemBoolType(dsess.LogRawCommitMetadata),
+			Default:           int8(0),
+		},
+		{ // If true, dolt_log includes the parents column as though --parents was given.
+			Name:              dsess.LogShowParents,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemBoolType(dsess.LogShowParents),
+			Default:           int8(0),
+		},
+		{ // The --decorate option that dolt_log uses when none is given.
+			Name:              dsess.LogDecorate,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemEnumType(dsess.LogDecorate, "auto", "short", "full", "no"),
+			Default:           "auto",
+		},
+		{ // If true, dolt_log returns empty values rather than NULL for columns that don't apply to a commit. Deprecated, and
+			// will be removed in the next release.
+			Name:              dsess.LogLegacyEmptyColumns,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemBoolType(dsess.LogLegacyEmptyColumns),
+			Default:           int8(0),
+		},
+		{ // Determines how the permissions of multiple matching dolt_branch_control entries are combined.
+			Name:              branch_control.MatchModeVariable,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemEnumType(branch_control.MatchModeVariable, branch_control.MatchModes...),
+			Default:           string(branch_control.MatchMode_Union),
+		},
+		{ // Determines whether operations denied by branch control fail, or are only logged while being allowed to proceed.
+			Name:              branch_control.EnforcementVariable,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemEnumType(branch_control.EnforcementVariable, branch_control.EnforcementModes...),
+			Default:           string(branch_control.EnforcementMode_Enforce),
+		},
+		{ // Determines whether the creators of branches are granted write and admin on them through dolt_branch_control.
+			Name:              branch_control.AutoGrantVariable,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemEnumType(branch_control.AutoGrantVariable, branch_control.AutoGrantModes...),
+			Default:           string(branch_control.AutoGrantMode_Off),
+		},
+		{ // The branch expressions, separated by commas, over which only the super user may grant admin through dolt_branch_control.
+			Name:              branch_control.EscalationVariable,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              sql.NewSystemStringType(branch_control.EscalationVariable),
+			Default:           branch_control.DefaultEscalationConstraints,
+		},
 	})
 }
 
@@ -41,6 +41,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/libraries/doltcore/statistics"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor/creation"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -82,10 +83,21 @@ type DoltTableStatistics struct {
 	rowCount     uint64
 	createdAt    time.Time
 	histogramMap sql.HistogramMap
+	// analyzed holds the statistics of the last analysis of the table, if there was one
+	analyzed *statistics.TableStatistics
 }
 
 var _ sql.TableStatistics = &DoltTableStatistics{}
 
+func newAnalyzedTableStatistics(ts *statistics.TableStatistics) *DoltTableStatistics {
+	return &DoltTableStatistics{
+		rowCount:     ts.RowCount,
+		createdAt:    ts.CreatedAt,
+		histogramMap: ts.Histograms,
+		analyzed:     ts,
+	}
+}
+
 func (ds *DoltTableStatistics) CreatedAt() time.Time {
 	return ds.createdAt
 }
@@ -452,32 +464,36 @@ func (t *DoltTable) DataLength(ctx *sql.Context) (uint64, error) {
 }
 
 // AnalyzeTable implements the sql.StatisticsTable interface.
-// This method will save the stats into the Database state found in the Session.
+// This method will save the stats into the Database state found in the Session, and persist them in the
+// dolt_statistics table of the working set so that they are versioned along with the table's data.
 func (t *DoltTable) AnalyzeTable(ctx *sql.Context) error {
 	table, err := t.DoltTable(ctx)
 	if err != nil {
 		return err
 	}
 
-	m, err := table.GetRowData(ctx)
+	ts, err := statistics.Compute(ctx, t.tableName, table)
 	if err != nil {
 		return err
 	}
+	t.doltStats = newAnalyzedTableStatistics(ts)
 
-	mc, err := m.Count()
-	if err != nil {
-		return err
-	}
-	t.doltStats = &DoltTableStatistics{
-		rowCount:  mc,
-		createdAt: time.Now(),
-	}
-
-	histMap, err := sql.NewHistogramMapFromTable(ctx, t)
-	if err != nil {
-		return err
+	// Statistics of historical tables and read only databases are kept in the session only
+	rodb, ok := t.db.(sql.ReadOnlyDatabase)
+	if t.lockedToRoot == nil && !(ok && rodb.IsReadOnly()) {
+		root, err := t.getRoot(ctx)
+		if err != nil {
+			return err
+		}
+		root, err = statistics.Put(ctx, root, ts)
+		if err != nil {
+			return err
+		}
+		err = dsess.DSessFromSess(ctx.Session).SetRoot(ctx, t.db.Name(), root)
+		if err != nil {
+			return err
+		}
 	}
-	t.doltStats.histogramMap = histMap
 
 	dSess := ctx.Session.(*dsess.DoltSession)
 	dbState, ok, err := dSess.LookupDbState(ctx, ctx.GetCurrentDatabase())
@@ -491,34 +507,63 @@ func (t *DoltTable) AnalyzeTable(ctx *sql.Context) error {
 	return nil
 }
 
-// Statistics implements the sql.StatisticsTable interface.
+// Statistics implements the sql.StatisticsTable interface. The statistics of the last analysis of the table are used
+// as long as the table's data has not changed since, whether the analysis was done in this session or persisted in
+// the dolt_statistics table.
 func (t *DoltTable) Statistics(ctx *sql.Context) (sql.TableStatistics, error) {
 	if t.doltStats != nil {
 		return t.doltStats, nil
 	}
 
+	table, err := t.DoltTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load stats from the session
-	dSess, ok := ctx.Session.(*dsess.DoltSession)
-	if !ok {
-		return nil, nil
+	if dSess, ok := ctx.Session.(*dsess.DoltSession); ok {
+		dbState, ok, err := dSess.LookupDbState(ctx, ctx.GetCurrentDatabase())
+		if ok && err == nil {
+			if stats, ok := dbState.TblStats[t.tableName].(*DoltTableStatistics); ok {
+				current, err := stats.analyzed.IsCurrent(ctx, table)
+				if err != nil {
+					return nil, err
+				}
+				if current {
+					t.doltStats = stats
+					return t.doltStats, nil
+				}
+			}
+		}
 	}
-	dbState, ok, err := dSess.LookupDbState(ctx, ctx.GetCurrentDatabase())
-	if !ok || err != nil {
-		return nil, nil
+
+	// Load stats persisted in the table's root
+	root, err := t.workingRoot(ctx)
+	if err != nil {
+		return nil, err
 	}
-	stats, ok := dbState.TblStats[t.tableName]
-	if !ok {
-		numRows, err := t.numRows(ctx)
+	ts, ok, err := statistics.Load(ctx, root, t.tableName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		current, err := ts.IsCurrent(ctx, table)
 		if err != nil {
 			return nil, err
 		}
-		return &DoltTableStatistics{
-			rowCount: numRows,
-		}, nil
+		if current {
+			t.doltStats = newAnalyzedTableStatistics(ts)
+			return t.doltStats, nil
+		}
 	}
-	t.doltStats = stats.(*DoltTableStatistics)
 
-	return t.doltStats, nil
+	numRows, err := t.numRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DoltTableStatistics{
+		rowCount: numRows,
+	}, nil
 }
 
 func (t *DoltTable) PrimaryKeySchema() sql.PrimaryKeySchema {
@@ -600,7 +645,7 @@ func (t *WritableDoltTable) WithProjections(colNames []string) sql.Table {
 
 // Inserter implements sql.InsertableTable
 func (t *WritableDoltTable) Inserter(ctx *sql.Context) sql.RowInserter {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
@@ -642,7 +687,7 @@ func (t *WritableDoltTable) getTableEditor(ctx *sql.Context) (ed writer.TableWri
 
 // Deleter implements sql.DeletableTable
 func (t *WritableDoltTable) Deleter(ctx *sql.Context) sql.RowDeleter {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
@@ -654,7 +699,7 @@ func (t *WritableDoltTable) Deleter(ctx *sql.Context) sql.RowDeleter {
 
 // Replacer implements sql.ReplaceableTable
 func (t *WritableDoltTable) Replacer(ctx *sql.Context) sql.RowReplacer {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
@@ -666,7 +711,7 @@ func (t *WritableDoltTable) Replacer(ctx *sql.Context) sql.RowReplacer {
 
 // Truncate implements sql.TruncateableTable
 func (t *WritableDoltTable) Truncate(ctx *sql.Context) (int, error) {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return 0, err
 	}
 	table, err := t.DoltTable.DoltTable(ctx)
@@ -756,7 +801,7 @@ func (t *WritableDoltTable) truncate(
 
 // Updater implements sql.UpdatableTable
 func (t *WritableDoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
@@ -768,7 +813,7 @@ func (t *WritableDoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
 
 // AutoIncrementSetter implements sql.AutoIncrementTable
 func (t *WritableDoltTable) AutoIncrementSetter(ctx *sql.Context) sql.AutoIncrementSetter {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckTableAccess(ctx, t.Name(), branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
 	te, err := t.getTableEditor(ctx)
@@ -1136,7 +1181,7 @@ func (t *AlterableDoltTable) WithProjections(colNames []string) sql.Table {
 
 // AddColumn implements sql.AlterableTable
 func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, order *sql.ColumnOrder) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := t.getRoot(ctx)
@@ -1272,7 +1317,7 @@ func (t *AlterableDoltTable) RewriteInserter(
 	oldColumn *sql.Column,
 	newColumn *sql.Column,
 ) (sql.RowInserter, error) {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return nil, err
 	}
 	err := validateSchemaChange(t.Name(), oldSchema, newSchema, oldColumn, newColumn)
@@ -1606,7 +1651,7 @@ func (t *AlterableDoltTable) dropColumnData(ctx *sql.Context, updatedTable *dolt
 // ModifyColumn implements sql.AlterableTable. ModifyColumn operations are only used for operations that change only
 // the schema of a table, not the data. For those operations, |RewriteInserter| is used.
 func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, column *sql.Column, order *sql.ColumnOrder) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	ws, err := t.db.GetWorkingSet(ctx)
@@ -1757,7 +1802,7 @@ func (t *AlterableDoltTable) CreateIndex(
 	indexColumns []sql.IndexColumn,
 	comment string,
 ) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	if constraint != sql.IndexConstraint_None && constraint != sql.IndexConstraint_Unique {
@@ -1829,7 +1874,7 @@ func (t *AlterableDoltTable) CreateIndex(
 
 // DropIndex implements sql.IndexAlterableTable
 func (t *AlterableDoltTable) DropIndex(ctx *sql.Context, indexName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	// We disallow removing internal dolt_ tables from SQL directly
@@ -1858,7 +1903,7 @@ func (t *AlterableDoltTable) DropIndex(ctx *sql.Context, indexName string) error
 
 // RenameIndex implements sql.IndexAlterableTable
 func (t *AlterableDoltTable) RenameIndex(ctx *sql.Context, fromIndexName string, toIndexName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	// RenameIndex will error if there is a name collision or an index does not exist
@@ -1899,7 +1944,7 @@ func (t *AlterableDoltTable) RenameIndex(ctx *sql.Context, fromIndexName string,
 
 // AddForeignKey implements sql.ForeignKeyTable
 func (t *AlterableDoltTable) AddForeignKey(ctx *sql.Context, sqlFk sql.ForeignKeyConstraint) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	if sqlFk.Name != "" && !doltdb.IsValidForeignKeyName(sqlFk.Name) {
@@ -2089,7 +2134,7 @@ func (t *AlterableDoltTable) AddForeignKey(ctx *sql.Context, sqlFk sql.ForeignKe
 
 // DropForeignKey implements sql.ForeignKeyTable
 func (t *AlterableDoltTable) DropForeignKey(ctx *sql.Context, fkName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := t.getRoot(ctx)
@@ -2117,7 +2162,7 @@ func (t *AlterableDoltTable) DropForeignKey(ctx *sql.Context, fkName string) err
 
 // UpdateForeignKey implements sql.ForeignKeyTable
 func (t *AlterableDoltTable) UpdateForeignKey(ctx *sql.Context, fkName string, sqlFk sql.ForeignKeyConstraint) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := t.getRoot(ctx)
@@ -2463,7 +2508,7 @@ func (t *AlterableDoltTable) updateFromRoot(ctx *sql.Context, root *doltdb.RootV
 }
 
 func (t *AlterableDoltTable) CreateCheck(ctx *sql.Context, check *sql.CheckDefinition) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := t.getRoot(ctx)
@@ -2519,7 +2564,7 @@ func (t *AlterableDoltTable) CreateCheck(ctx *sql.Context, check *sql.CheckDefin
 }
 
 func (t *AlterableDoltTable) DropCheck(ctx *sql.Context, chName string) error {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write, branch_control.Operations_DirectDML); err != nil {
 		return err
 	}
 	root, err := t.getRoot(ctx)
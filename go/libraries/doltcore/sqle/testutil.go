@@ -25,6 +25,7 @@ import (
 
 	sqle "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
 	"github.com/stretchr/testify/require"
 
@@ -134,7 +135,7 @@ func NewTestEngine(t *testing.T, dEnv *env.DoltEnv, ctx context.Context, db Data
 	b := env.GetDefaultInitBranch(dEnv.Config)
 	pro, err := NewDoltDatabaseProviderWithDatabase(b, dEnv.FS, db, dEnv.FS)
 	require.NoError(t, err)
-	engine := sqle.NewDefault(pro)
+	engine := sqle.New(AddAnalyzerRules(analyzer.NewBuilder(pro)).Build(), nil)
 	sqlCtx := NewTestSQLCtxWithProvider(ctx, pro)
 
 	err = dsess.DSessFromSess(sqlCtx.Session).AddDB(sqlCtx, getDbState(t, db, dEnv))
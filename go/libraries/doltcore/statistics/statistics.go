@@ -0,0 +1,493 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// maxBuckets is the maximum number of buckets in the histogram of a column.
+const maxBuckets = 32
+
+// Schema is the schema of the dolt_statistics table. It holds a row for every column of every analyzed table.
+var Schema = schema.MustSchemaFromCols(schema.NewColCollection(
+	schema.NewColumn(doltdb.StatisticsTableNameCol, schema.DoltStatisticsTableNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsColumnNameCol, schema.DoltStatisticsColumnNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsDataHashCol, schema.DoltStatisticsDataHashTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsRowCountCol, schema.DoltStatisticsRowCountTag, types.UintKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsDistinctCountCol, schema.DoltStatisticsDistinctCountTag, types.UintKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsNullCountCol, schema.DoltStatisticsNullCountTag, types.UintKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn(doltdb.StatisticsMeanCol, schema.DoltStatisticsMeanTag, types.FloatKind, false),
+	schema.NewColumn(doltdb.StatisticsMinCol, schema.DoltStatisticsMinTag, types.FloatKind, false),
+	schema.NewColumn(doltdb.StatisticsMaxCol, schema.DoltStatisticsMaxTag, types.FloatKind, false),
+	schema.NewColumn(doltdb.StatisticsBucketsCol, schema.DoltStatisticsBucketsTag, types.StringKind, false),
+	schema.NewColumn(doltdb.StatisticsCreatedAtCol, schema.DoltStatisticsCreatedAtTag, types.TimestampKind, false),
+))
+
+// TableStatistics are the statistics of the rows of a table at the time it was analyzed.
+type TableStatistics struct {
+	TableName string
+	// DataHash is the hash of the table's row data that the statistics were computed from.
+	DataHash  hash.Hash
+	RowCount  uint64
+	CreatedAt time.Time
+	// Histograms holds the statistics of each column, by column name. Only numeric columns have a mean, minimum,
+	// maximum, and histogram buckets.
+	Histograms sql.HistogramMap
+}
+
+// IsCurrent returns whether the statistics describe the current rows and columns of |tbl|.
+func (ts *TableStatistics) IsCurrent(ctx context.Context, tbl *doltdb.Table) (bool, error) {
+	h, err := dataHash(ctx, tbl)
+	if err != nil {
+		return false, err
+	}
+	if h != ts.DataHash {
+		return false, nil
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return false, err
+	}
+	cols := sch.GetAllCols()
+	if cols.Size() != len(ts.Histograms) {
+		return false, nil
+	}
+	for _, col := range cols.GetColumns() {
+		if _, ok := ts.Histograms[col.Name]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Compute reads every row of |tbl| and returns its statistics.
+func Compute(ctx context.Context, tableName string, tbl *doltdb.Table) (*TableStatistics, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h, err := rows.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := sch.GetAllCols().GetColumns()
+	accs := make([]*columnAccumulator, len(cols))
+	for i, col := range cols {
+		accs[i] = newColumnAccumulator(sql.IsNumber(col.TypeInfo.ToSqlType()) || sql.IsDecimal(col.TypeInfo.ToSqlType()))
+	}
+
+	iter, err := table.NewTableIterator(ctx, sch, rows, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	var rowCount uint64
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		rowCount++
+		for i := range accs {
+			accs[i].add(r[i])
+		}
+	}
+
+	histograms := make(sql.HistogramMap, len(cols))
+	for i, col := range cols {
+		histograms[col.Name] = accs[i].histogram()
+	}
+
+	return &TableStatistics{
+		TableName:  tableName,
+		DataHash:   h,
+		RowCount:   rowCount,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		Histograms: histograms,
+	}, nil
+}
+
+// Load returns the statistics of the table named |tableName| persisted in |root|, if there are any.
+func Load(ctx context.Context, root *doltdb.RootValue, tableName string) (*TableStatistics, bool, error) {
+	all, err := loadAll(ctx, root)
+	if err != nil {
+		return nil, false, err
+	}
+	ts, ok := all[tableName]
+	return ts, ok, nil
+}
+
+// Put persists |ts| in the dolt_statistics table of |root|, replacing any earlier statistics of the same table, and
+// returns the updated root.
+func Put(ctx context.Context, root *doltdb.RootValue, ts *TableStatistics) (*doltdb.RootValue, error) {
+	all, err := loadAll(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	all[ts.TableName] = ts
+	return writeAll(ctx, root, all)
+}
+
+// Refresh recomputes the persisted statistics of every table of |root| whose rows or columns have changed since it
+// was analyzed, removes the statistics of dropped tables, and returns the updated root. Tables that have never been
+// analyzed are left alone.
+func Refresh(ctx context.Context, root *doltdb.RootValue) (*doltdb.RootValue, error) {
+	all, err := loadAll(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for name, ts := range all {
+		tbl, ok, err := root.GetTable(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			delete(all, name)
+			changed = true
+			continue
+		}
+
+		current, err := ts.IsCurrent(ctx, tbl)
+		if err != nil {
+			return nil, err
+		}
+		if current {
+			continue
+		}
+
+		all[name], err = Compute(ctx, name, tbl)
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return root, nil
+	}
+	return writeAll(ctx, root, all)
+}
+
+func dataHash(ctx context.Context, tbl *doltdb.Table) (hash.Hash, error) {
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return rows.HashOf()
+}
+
+// columnAccumulator gathers the statistics of a single column as its values are read.
+type columnAccumulator struct {
+	numeric   bool
+	nullCount uint64
+	count     uint64
+	sum       float64
+	min       float64
+	max       float64
+	freqs     map[float64]uint64
+	distinct  map[string]struct{}
+}
+
+func newColumnAccumulator(numeric bool) *columnAccumulator {
+	acc := &columnAccumulator{numeric: numeric, min: math.MaxFloat64, max: -math.MaxFloat64}
+	if numeric {
+		acc.freqs = make(map[float64]uint64)
+	} else {
+		acc.distinct = make(map[string]struct{})
+	}
+	return acc
+}
+
+func (acc *columnAccumulator) add(v interface{}) {
+	if v == nil {
+		acc.nullCount++
+		return
+	}
+	acc.count++
+
+	if !acc.numeric {
+		acc.distinct[fmt.Sprintf("%v", v)] = struct{}{}
+		return
+	}
+
+	f, err := sql.Float64.Convert(v)
+	if err != nil {
+		return
+	}
+	fv := f.(float64)
+	acc.freqs[fv]++
+	acc.sum += fv
+	acc.min = math.Min(acc.min, fv)
+	acc.max = math.Max(acc.max, fv)
+}
+
+// histogram returns the gathered statistics. The values of numeric columns are divided into at most maxBuckets
+// buckets holding roughly the same number of values.
+func (acc *columnAccumulator) histogram() *sql.Histogram {
+	hist := &sql.Histogram{
+		Count:     acc.count,
+		NullCount: acc.nullCount,
+	}
+	if !acc.numeric {
+		hist.DistinctCount = uint64(len(acc.distinct))
+		return hist
+	}
+
+	hist.DistinctCount = uint64(len(acc.freqs))
+	if acc.count == 0 {
+		return hist
+	}
+	hist.Mean = acc.sum / float64(acc.count)
+	hist.Min = acc.min
+	hist.Max = acc.max
+
+	values := make([]float64, 0, len(acc.freqs))
+	for v := range acc.freqs {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+
+	depth := (acc.count + maxBuckets - 1) / maxBuckets
+	var bucket *sql.HistogramBucket
+	var inBucket uint64
+	for _, v := range values {
+		if bucket == nil {
+			bucket = &sql.HistogramBucket{LowerBound: v}
+		}
+		bucket.UpperBound = v
+		inBucket += acc.freqs[v]
+		if inBucket >= depth {
+			bucket.Frequency = float64(inBucket) / float64(acc.count)
+			hist.Buckets = append(hist.Buckets, bucket)
+			bucket, inBucket = nil, 0
+		}
+	}
+	if bucket != nil {
+		bucket.Frequency = float64(inBucket) / float64(acc.count)
+		hist.Buckets = append(hist.Buckets, bucket)
+	}
+	return hist
+}
+
+// jsonBucket is the representation of a histogram bucket in the buckets column of dolt_statistics.
+type jsonBucket struct {
+	LowerBound float64 `json:"lower_bound"`
+	UpperBound float64 `json:"upper_bound"`
+	Frequency  float64 `json:"frequency"`
+}
+
+// loadAll returns the statistics persisted in |root| by table name.
+func loadAll(ctx context.Context, root *doltdb.RootValue) (map[string]*TableStatistics, error) {
+	all := make(map[string]*TableStatistics)
+	tbl, ok, err := root.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil || !ok {
+		return all, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := table.NewTableIterator(ctx, sch, rows, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		tableName := r[0].(string)
+		ts, ok := all[tableName]
+		if !ok {
+			h, ok := hash.MaybeParse(r[2].(string))
+			if !ok {
+				return nil, fmt.Errorf("invalid data hash in %s for table %s: %s", doltdb.StatisticsTableName, tableName, r[2])
+			}
+			ts = &TableStatistics{
+				TableName:  tableName,
+				DataHash:   h,
+				RowCount:   r[3].(uint64),
+				Histograms: make(sql.HistogramMap),
+			}
+			if createdAt, ok := r[10].(time.Time); ok {
+				ts.CreatedAt = createdAt
+			}
+			all[tableName] = ts
+		}
+
+		hist := &sql.Histogram{
+			DistinctCount: r[4].(uint64),
+			NullCount:     r[5].(uint64),
+			Count:         ts.RowCount - r[5].(uint64),
+		}
+		if r[6] != nil {
+			hist.Mean, hist.Min, hist.Max = r[6].(float64), r[7].(float64), r[8].(float64)
+		}
+		if s, ok := r[9].(string); ok && s != "" {
+			var buckets []jsonBucket
+			if err := json.Unmarshal([]byte(s), &buckets); err != nil {
+				return nil, err
+			}
+			for _, b := range buckets {
+				hist.Buckets = append(hist.Buckets, &sql.HistogramBucket{LowerBound: b.LowerBound, UpperBound: b.UpperBound, Frequency: b.Frequency})
+			}
+		}
+		ts.Histograms[r[1].(string)] = hist
+	}
+
+	return all, nil
+}
+
+// writeAll replaces the rows of the dolt_statistics table of |root| with |all|, creating the table if necessary.
+func writeAll(ctx context.Context, root *doltdb.RootValue, all map[string]*TableStatistics) (*doltdb.RootValue, error) {
+	var rows []sql.Row
+	for _, ts := range all {
+		for colName, hist := range ts.Histograms {
+			r := sql.Row{ts.TableName, colName, ts.DataHash.String(), ts.RowCount, hist.DistinctCount, hist.NullCount, nil, nil, nil, nil, ts.CreatedAt}
+			if hist.Count > 0 && hist.Buckets != nil {
+				buckets := make([]jsonBucket, len(hist.Buckets))
+				for i, b := range hist.Buckets {
+					buckets[i] = jsonBucket{LowerBound: b.LowerBound, UpperBound: b.UpperBound, Frequency: b.Frequency}
+				}
+				js, err := json.Marshal(buckets)
+				if err != nil {
+					return nil, err
+				}
+				r[6], r[7], r[8], r[9] = hist.Mean, hist.Min, hist.Max, string(js)
+			}
+			rows = append(rows, r)
+		}
+	}
+
+	var idx durable.Index
+	var err error
+	if types.IsFormat_DOLT(root.VRW().Format()) {
+		idx, err = prollyIndexFromRows(ctx, root, rows)
+	} else {
+		idx, err = nomsIndexFromRows(ctx, root, rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, ok, err := root.GetTable(ctx, doltdb.StatisticsTableName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		tbl, err = tbl.UpdateRows(ctx, idx)
+	} else {
+		tbl, err = doltdb.NewTable(ctx, root.VRW(), root.NodeStore(), Schema, idx, nil, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return root.PutTable(ctx, doltdb.StatisticsTableName, tbl)
+}
+
+func prollyIndexFromRows(ctx context.Context, root *doltdb.RootValue, rows []sql.Row) (durable.Index, error) {
+	idx, err := durable.NewEmptyIndex(ctx, root.VRW(), root.NodeStore(), Schema)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(idx)
+	mut := m.Mutate()
+
+	numPks := Schema.GetPKCols().Size()
+	kb := val.NewTupleBuilder(Schema.GetKeyDescriptor())
+	vb := val.NewTupleBuilder(Schema.GetValueDescriptor())
+	for _, r := range rows {
+		for i := 0; i < numPks; i++ {
+			if err := index.PutField(ctx, m.NodeStore(), kb, i, r[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := numPks; i < len(r); i++ {
+			if err := index.PutField(ctx, m.NodeStore(), vb, i-numPks, r[i]); err != nil {
+				return nil, err
+			}
+		}
+		if err := mut.Put(ctx, kb.Build(m.Pool()), vb.Build(m.Pool())); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err = mut.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return durable.IndexFromProllyMap(m), nil
+}
+
+func nomsIndexFromRows(ctx context.Context, root *doltdb.RootValue, rows []sql.Row) (durable.Index, error) {
+	m, err := types.NewMap(ctx, root.VRW())
+	if err != nil {
+		return nil, err
+	}
+	me := m.Edit()
+	for _, r := range rows {
+		dRow, err := sqlutil.SqlRowToDoltRow(ctx, root.VRW(), r, Schema)
+		if err != nil {
+			return nil, err
+		}
+		me.Set(dRow.NomsMapKey(Schema), dRow.NomsMapValue(Schema))
+	}
+
+	m, err = me.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return durable.IndexFromNomsMap(m, root.VRW(), root.NodeStore()), nil
+}
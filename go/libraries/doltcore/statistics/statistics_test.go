@@ -0,0 +1,64 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	acc := newColumnAccumulator(true)
+	for i := 0; i < 1000; i++ {
+		acc.add(int64(i % 100))
+	}
+	acc.add(nil)
+
+	hist := acc.histogram()
+	assert.Equal(t, uint64(1000), hist.Count)
+	assert.Equal(t, uint64(1), hist.NullCount)
+	assert.Equal(t, uint64(100), hist.DistinctCount)
+	assert.Equal(t, 49.5, hist.Mean)
+	assert.Equal(t, 0.0, hist.Min)
+	assert.Equal(t, 99.0, hist.Max)
+
+	require.LessOrEqual(t, len(hist.Buckets), maxBuckets)
+	assert.Equal(t, 0.0, hist.Buckets[0].LowerBound)
+	assert.Equal(t, 99.0, hist.Buckets[len(hist.Buckets)-1].UpperBound)
+	var total float64
+	for i, b := range hist.Buckets {
+		assert.LessOrEqual(t, b.LowerBound, b.UpperBound)
+		if i > 0 {
+			assert.Less(t, hist.Buckets[i-1].UpperBound, b.LowerBound)
+		}
+		total += b.Frequency
+	}
+	assert.InDelta(t, 1.0, total, 1e-9)
+}
+
+func TestNonNumericColumnStatistics(t *testing.T) {
+	acc := newColumnAccumulator(false)
+	for _, v := range []interface{}{"a", "b", "a", nil, "c"} {
+		acc.add(v)
+	}
+
+	hist := acc.histogram()
+	assert.Equal(t, uint64(4), hist.Count)
+	assert.Equal(t, uint64(1), hist.NullCount)
+	assert.Equal(t, uint64(3), hist.DistinctCount)
+	assert.Empty(t, hist.Buckets)
+}
@@ -25,15 +25,17 @@ import (
 var forceOpt = &Option{"force", "f", "", OptionalFlag, "force desc", nil}
 var messageOpt = &Option{"message", "m", "msg", OptionalValue, "msg desc", nil}
 var fileTypeOpt = &Option{"file-type", "", "", OptionalValue, "file type", nil}
+var includeOpt = &Option{"include", "i", "path", OptionalValueList, "include desc", nil}
 
 func TestParsing(t *testing.T) {
 	tests := []struct {
-		name         string
-		options      []*Option
-		args         []string
-		expectedOpts map[string]string
-		expectedArgs []string
-		expectedErr  string
+		name          string
+		options       []*Option
+		args          []string
+		expectedOpts  map[string]string
+		expectedArgs  []string
+		expectedLists map[string][]string
+		expectedErr   string
 	}{
 		{
 			name:         "empty",
@@ -184,6 +186,22 @@ func TestParsing(t *testing.T) {
 			args:        []string{"-f", "-f"},
 			expectedErr: "error: multiple values provided for `force'",
 		},
+		{
+			name:          "repeated list arg",
+			options:       []*Option{forceOpt, includeOpt},
+			args:          []string{"-i", "a", "--include=b", "-f", "-ic", "d"},
+			expectedOpts:  map[string]string{"include": "c", "force": ""},
+			expectedArgs:  []string{"d"},
+			expectedLists: map[string][]string{"include": {"a", "b", "c"}},
+		},
+		{
+			name:          "single list arg",
+			options:       []*Option{includeOpt},
+			args:          []string{"--include", "a"},
+			expectedOpts:  map[string]string{"include": "a"},
+			expectedArgs:  []string{},
+			expectedLists: map[string][]string{"include": {"a"}},
+		},
 	}
 
 	for _, test := range tests {
@@ -194,7 +212,7 @@ func TestParsing(t *testing.T) {
 				parser.SupportOption(opt)
 			}
 
-			exp := &ArgParseResults{test.expectedOpts, test.expectedArgs, parser}
+			exp := &ArgParseResults{options: test.expectedOpts, Args: test.expectedArgs, parser: parser, valueLists: test.expectedLists}
 
 			res, err := parser.Parse(test.args)
 			if test.expectedErr != "" {
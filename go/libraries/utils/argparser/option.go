@@ -25,6 +25,11 @@ const (
 	OptionalFlag OptionType = iota
 	OptionalValue
 	OptionalEmptyValue
+	// OptionalValueList is a value that may be given more than once, collecting every value
+	OptionalValueList
+	// OptionalAttachedValue is a value that may only be attached to the option, as in `--name=value`, so that the option
+	// may also be given without a value and never consumes the argument that follows it
+	OptionalAttachedValue
 )
 
 type ValidationFunc func(string) error
@@ -111,6 +111,16 @@ func (ap *ArgParser) SupportsOptionalString(name, abbrev, valDesc, desc string)
 	return ap
 }
 
+// SupportsAttachedString adds support for a new string argument whose value may only be attached to the option, as in
+// `--name=value`. The option may be given without a value, in which case its value is empty. See SupportOpt for details
+// on params.
+func (ap *ArgParser) SupportsAttachedString(name, abbrev, valDesc, desc string) *ArgParser {
+	opt := &Option{name, abbrev, valDesc, OptionalAttachedValue, desc, nil}
+	ap.SupportOption(opt)
+
+	return ap
+}
+
 // SupportsValidatedString adds support for a new string argument with the description given and defined validation function.
 func (ap *ArgParser) SupportsValidatedString(name, abbrev, valDesc, desc string, validator ValidationFunc) *ArgParser {
 	opt := &Option{name, abbrev, valDesc, OptionalValue, desc, validator}
@@ -119,6 +129,15 @@ func (ap *ArgParser) SupportsValidatedString(name, abbrev, valDesc, desc string,
 	return ap
 }
 
+// SupportsStringList adds support for a new string argument that may be given more than once, with the description
+// given. Every value is returned by ArgParseResults.GetValueList. See SupportOpt for details on params.
+func (ap *ArgParser) SupportsStringList(name, abbrev, valDesc, desc string) *ArgParser {
+	opt := &Option{name, abbrev, valDesc, OptionalValueList, desc, nil}
+	ap.SupportOption(opt)
+
+	return ap
+}
+
 // SupportsUint adds support for a new uint argument with the description given. See SupportOpt for details on params.
 func (ap *ArgParser) SupportsUint(name, abbrev, valDesc, desc string) *ArgParser {
 	opt := &Option{name, abbrev, valDesc, OptionalValue, desc, isUintStr}
@@ -157,10 +176,16 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 	for kontinue {
 		kontinue = false
 
-		// stop if we see a value option
+		// stop if we see a value option, unless a longer flag matches, such as --no-merges when -n takes a value
+		longestFlag := 0
+		for _, on := range candidateFlagNames {
+			if lo := len(on); lo > longestFlag && len(rest) >= lo && rest[:lo] == on {
+				longestFlag = lo
+			}
+		}
 		for _, vo := range ap.sortedValueOptions() {
 			lv := len(vo)
-			isValOpt := len(rest) >= lv && rest[:lv] == vo
+			isValOpt := lv >= longestFlag && len(rest) >= lv && rest[:lv] == vo
 			if isValOpt {
 				return matches, rest
 			}
@@ -193,7 +218,7 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 func (ap *ArgParser) sortedValueOptions() []string {
 	vos := make([]string, 0, len(ap.Supported))
 	for s, opt := range ap.NameOrAbbrevToOpt {
-		if (opt.OptType == OptionalValue || opt.OptType == OptionalEmptyValue) && s != "" {
+		if (opt.OptType == OptionalValue || opt.OptType == OptionalEmptyValue || opt.OptType == OptionalValueList || opt.OptType == OptionalAttachedValue) && s != "" {
 			vos = append(vos, s)
 		}
 	}
@@ -222,8 +247,29 @@ func (ap *ArgParser) matchValueOption(arg string) (match *Option, value *string)
 // methods. Any unrecognized arguments or incorrect types will result in an appropriate error being returned. If the
 // universal --help or -h flag is found, an ErrHelp error is returned.
 func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
+	return ap.parse(args, false)
+}
+
+// ParseAllowingDuplicates parses the args in the same manner as Parse, except that any option may be given more than
+// once rather than only those added with SupportsStringList. The results hold the last value that was given for each
+// option, and ArgParseResults.Occurrences returns the number of times that each option was given, so that callers are
+// able to decide how duplicates are handled. The results also record where each argument was given, which is returned
+// by ArgParseResults.ArgIndex and ArgParseResults.OptionIndex.
+func (ap *ArgParser) ParseAllowingDuplicates(args []string) (*ArgParseResults, error) {
+	return ap.parse(args, true)
+}
+
+func (ap *ArgParser) parse(args []string, allowDuplicates bool) (*ArgParseResults, error) {
 	list := make([]string, 0, 16)
 	results := make(map[string]string)
+	var occurrences map[string]int
+	var argIndexes []int
+	var optionIndexes map[string]int
+	if allowDuplicates {
+		occurrences = make(map[string]int)
+		optionIndexes = make(map[string]int)
+	}
+	var valueLists map[string][]string
 
 	i := 0
 	for ; i < len(args); i++ {
@@ -231,6 +277,9 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 
 		if len(arg) == 0 || arg[0] != '-' || arg == "--" { // empty strings should get passed through like other naked words
 			list = append(list, arg)
+			if allowDuplicates {
+				argIndexes = append(argIndexes, i)
+			}
 			continue
 		}
 
@@ -243,11 +292,15 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 		modalOpts, rest := ap.matchModalOptions(arg)
 
 		for _, opt := range modalOpts {
-			if _, exists := results[opt.Name]; exists {
+			if _, exists := results[opt.Name]; exists && !allowDuplicates {
 				return nil, errors.New("error: multiple values provided for `" + opt.Name + "'")
 			}
 
 			results[opt.Name] = ""
+			if allowDuplicates {
+				occurrences[opt.Name]++
+				optionIndexes[opt.Name] = i
+			}
 		}
 
 		opt, value := ap.matchValueOption(rest)
@@ -261,18 +314,25 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 				// value was attached to modal flag
 				// eg: dolt branch -fdmy_branch
 				list = append(list, rest)
+				if allowDuplicates {
+					argIndexes = append(argIndexes, i)
+				}
 				continue
 			}
 
 			return nil, UnknownArgumentParam{name: arg}
 		}
 
-		if _, exists := results[opt.Name]; exists {
+		if _, exists := results[opt.Name]; exists && opt.OptType != OptionalValueList && !allowDuplicates {
 			//already provided
 			return nil, errors.New("error: multiple values provided for `" + opt.Name + "'")
 		}
 
-		if value == nil {
+		optionIndex := i
+		if value == nil && opt.OptType == OptionalAttachedValue {
+			valueStr := ""
+			value = &valueStr
+		} else if value == nil {
 			i++
 			valueStr := ""
 			if i >= len(args) {
@@ -295,11 +355,21 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 		}
 
 		results[opt.Name] = *value
+		if allowDuplicates {
+			occurrences[opt.Name]++
+			optionIndexes[opt.Name] = optionIndex
+		}
+		if opt.OptType == OptionalValueList {
+			if valueLists == nil {
+				valueLists = make(map[string][]string)
+			}
+			valueLists[opt.Name] = append(valueLists[opt.Name], *value)
+		}
 	}
 
 	if i < len(args) {
 		copy(list, args[i:])
 	}
 
-	return &ArgParseResults{results, list, ap}, nil
+	return &ArgParseResults{options: results, Args: list, parser: ap, valueLists: valueLists, occurrences: occurrences, argIndexes: argIndexes, optionIndexes: optionIndexes}, nil
 }
@@ -78,6 +78,21 @@ func TestArgParser(t *testing.T) {
 			map[string]string{"param": "value"},
 			[]string{"arg1"},
 		},
+		{
+			// A flag is matched when it's longer than a value option that is a prefix of it
+			NewArgParser().SupportsInt("number", "n", "", "").SupportsFlag("no-merges", "", ""),
+			[]string{"--no-merges", "-n", "1"},
+			nil,
+			map[string]string{"no-merges": "", "number": "1"},
+			[]string{},
+		},
+		{
+			NewArgParser().SupportsInt("number", "n", "", "").SupportsFlag("no-merges", "", ""),
+			[]string{"-n1"},
+			nil,
+			map[string]string{"number": "1"},
+			[]string{},
+		},
 	}
 
 	for _, test := range tests {
@@ -90,3 +105,66 @@ func TestArgParser(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAllowingDuplicates(t *testing.T) {
+	newParser := func() *ArgParser {
+		return NewArgParser().
+			SupportsFlag("flag", "f", "").
+			SupportsString("param", "p", "", "").
+			SupportsStringList("list", "", "", "")
+	}
+	args := []string{"--flag", "-f", "--param", "first", "arg1", "-p", "second", "--list", "a", "--list", "a"}
+
+	_, err := newParser().Parse(args)
+	require.Error(t, err)
+
+	apr, err := newParser().ParseAllowingDuplicates(args)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arg1"}, apr.Args)
+	assert.True(t, apr.Contains("flag"))
+	assert.Equal(t, 2, apr.Occurrences("flag"))
+	assert.Equal(t, "second", apr.MustGetValue("param"))
+	assert.Equal(t, 2, apr.Occurrences("param"))
+	assert.Equal(t, []string{"a", "a"}, apr.GetValueList("list"))
+	assert.Equal(t, 2, apr.Occurrences("list"))
+	assert.Equal(t, 0, apr.Occurrences("missing"))
+	assert.Equal(t, 4, apr.ArgIndex(0))
+	assert.Equal(t, -1, apr.ArgIndex(1))
+	assert.Equal(t, 1, apr.OptionIndex("flag"))
+	assert.Equal(t, 5, apr.OptionIndex("param"))
+	assert.Equal(t, 9, apr.OptionIndex("list"))
+	assert.Equal(t, -1, apr.OptionIndex("missing"))
+
+	// Results from Parse report the same occurrences for options that may only be given once
+	apr, err = newParser().Parse([]string{"-f", "--param", "value", "--list", "a", "--list", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, apr.Occurrences("flag"))
+	assert.Equal(t, 1, apr.Occurrences("param"))
+	assert.Equal(t, 2, apr.Occurrences("list"))
+	assert.Equal(t, 0, apr.Occurrences("missing"))
+}
+
+func TestAttachedString(t *testing.T) {
+	newParser := func() *ArgParser {
+		return NewArgParser().
+			SupportsFlag("flag", "f", "").
+			SupportsAttachedString("attached", "", "", "")
+	}
+
+	// A value is only taken when it's attached to the option
+	apr, err := newParser().Parse([]string{"--attached=12", "arg1"})
+	require.NoError(t, err)
+	assert.Equal(t, "12", apr.MustGetValue("attached"))
+	assert.Equal(t, []string{"arg1"}, apr.Args)
+
+	apr, err = newParser().Parse([]string{"--attached", "arg1", "--flag"})
+	require.NoError(t, err)
+	assert.True(t, apr.Contains("attached"))
+	assert.Equal(t, "", apr.MustGetValue("attached"))
+	assert.Equal(t, []string{"arg1"}, apr.Args)
+	assert.True(t, apr.Contains("flag"))
+
+	apr, err = newParser().Parse([]string{"--attached"})
+	require.NoError(t, err)
+	assert.Equal(t, "", apr.MustGetValue("attached"))
+}
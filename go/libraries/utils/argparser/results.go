@@ -25,6 +25,14 @@ type ArgParseResults struct {
 	options map[string]string
 	Args    []string
 	parser  *ArgParser
+	// valueLists holds every value of the options that may be given more than once
+	valueLists map[string][]string
+	// occurrences holds the number of times that each option was given, and is only set by ParseAllowingDuplicates
+	occurrences map[string]int
+	// argIndexes holds the index within the parsed args of each positional arg, and optionIndexes holds the index of
+	// the last occurrence of each option. Both are only set by ParseAllowingDuplicates.
+	argIndexes    []int
+	optionIndexes map[string]int
 }
 
 func (res *ArgParseResults) Equals(other *ArgParseResults) bool {
@@ -44,6 +52,18 @@ func (res *ArgParseResults) Equals(other *ArgParseResults) bool {
 		}
 	}
 
+	for k, vals := range res.valueLists {
+		otherVals := other.valueLists[k]
+		if len(vals) != len(otherVals) {
+			return false
+		}
+		for i, v := range vals {
+			if otherVals[i] != v {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
@@ -96,6 +116,44 @@ func (res *ArgParseResults) GetValue(name string) (string, bool) {
 	return val, ok
 }
 
+// GetValueList returns every value given for an option added with SupportsStringList, in the order they were given.
+func (res *ArgParseResults) GetValueList(name string) []string {
+	return res.valueLists[name]
+}
+
+// ArgIndex returns the index within the parsed args of the positional arg at the given index of Args, or -1 if the args
+// were not parsed with ParseAllowingDuplicates.
+func (res *ArgParseResults) ArgIndex(i int) int {
+	if i < 0 || i >= len(res.argIndexes) {
+		return -1
+	}
+	return res.argIndexes[i]
+}
+
+// OptionIndex returns the index within the parsed args of the last occurrence of the option, or -1 if the option was
+// not given or the args were not parsed with ParseAllowingDuplicates.
+func (res *ArgParseResults) OptionIndex(name string) int {
+	if idx, ok := res.optionIndexes[name]; ok {
+		return idx
+	}
+	return -1
+}
+
+// Occurrences returns the number of times that the option was given, which is only greater than one for options added
+// with SupportsStringList, or when the args were parsed with ParseAllowingDuplicates.
+func (res *ArgParseResults) Occurrences(name string) int {
+	if res.occurrences != nil {
+		return res.occurrences[name]
+	}
+	if vals, ok := res.valueLists[name]; ok {
+		return len(vals)
+	}
+	if _, ok := res.options[name]; ok {
+		return 1
+	}
+	return 0
+}
+
 func (res *ArgParseResults) GetValues(names ...string) map[string]string {
 	vals := make(map[string]string)
 
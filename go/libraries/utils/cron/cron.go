@@ -0,0 +1,176 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression of the five standard fields: minute, hour, day of month, month, and day of
+// week. Each field is a comma separated list of values, ranges such as 1-5, or *, each optionally followed by a step
+// such as */15. Days of the week run from 0 to 7, where both 0 and 7 are Sunday. As in cron, when both the day of the
+// month and the day of the week are restricted, a time matches if either of them does.
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// anyDay and anyWeekday record whether the day fields are unrestricted
+	anyDay     bool
+	anyWeekday bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// maxSearch bounds the search for the next matching time of a schedule, so that schedules that can never match,
+// such as the 30th of February, end.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Parse parses the cron expression given.
+func Parse(expr string) (Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return Schedule{}, fmt.Errorf("invalid cron expression '%s': expected %d fields but found %d", expr, len(fields), len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		var err error
+		bits[i], err = parseField(part, fields[i])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+		}
+	}
+
+	// Sunday may be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return Schedule{
+		minutes:    bits[0],
+		hours:      bits[1],
+		days:       bits[2],
+		months:     bits[3],
+		weekdays:   bits[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}, nil
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			rng = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: '%s'", f.name, item)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			var err error
+			if i := strings.IndexByte(rng, '-'); i >= 0 {
+				lo, err = parseValue(rng[:i], f)
+				if err == nil {
+					hi, err = parseValue(rng[i+1:], f)
+				}
+			} else {
+				lo, err = parseValue(rng, f)
+				hi = lo
+				if step > 1 {
+					hi = f.max
+				}
+			}
+			if err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in %s field: '%s'", f.name, item)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, f field) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value in %s field: '%s'; values must be between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Matches returns whether the minute of |t| is one that the schedule runs at.
+func (s Schedule) Matches(t time.Time) bool {
+	return s.has(s.minutes, t.Minute()) && s.has(s.hours, t.Hour()) && s.has(s.months, int(t.Month())) && s.matchesDay(t)
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	day, weekday := s.has(s.days, t.Day()), s.has(s.weekdays, int(t.Weekday()))
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func (s Schedule) has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// Next returns the first time after |t| that the schedule runs at, in the location of |t|, or the zero time if the
+// schedule never runs.
+func (s Schedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(maxSearch)
+	for next.Before(end) {
+		switch {
+		case !s.has(s.months, int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !s.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !s.has(s.hours, next.Hour()):
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case !s.has(s.minutes, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
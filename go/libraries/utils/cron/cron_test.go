@@ -0,0 +1,84 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a Friday
+	start := time.Date(2022, time.September, 16, 13, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2022, time.September, 16, 13, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2022, time.September, 16, 13, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2022, time.September, 17, 2, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * 1-5", time.Date(2022, time.September, 16, 13, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2022, time.September, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2022, time.September, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 1", time.Date(2022, time.September, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2022, time.September, 16, 13, 25, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			s, err := Parse(test.expr)
+			require.NoError(t, err)
+			next := s.Next(start)
+			assert.Equal(t, test.expected, next)
+			if !next.IsZero() {
+				assert.True(t, s.Matches(next))
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	s, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+	assert.True(t, s.Matches(time.Date(2022, time.September, 16, 2, 0, 45, 0, time.UTC)))
+	assert.False(t, s.Matches(time.Date(2022, time.September, 16, 2, 1, 0, 0, time.UTC)))
+	assert.False(t, s.Matches(time.Date(2022, time.September, 16, 3, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Snapshot is the state of a histogram at the time that it was read.
+type Snapshot struct {
+	Count uint64
+	// Sum is the sum of every observation, in seconds
+	Sum float64
+	// Buckets holds the cumulative count of the observations at or below each upper bound
+	Buckets map[float64]uint64
+}
+
+// Histogram counts durations in fixed buckets without locking or allocating, so that it may be updated on hot paths.
+// Histograms are read through snapshots, which the server exports along with its other metrics.
+type Histogram struct {
+	bounds []float64
+	// counts holds the number of observations in each bucket, where the last bucket holds those above every bound
+	counts []uint64
+	sumNs  uint64
+}
+
+// New returns a histogram with the given upper bounds, in seconds, which must be sorted in increasing order.
+func New(bounds []float64) *Histogram {
+	return &Histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+// Observe adds the given duration to the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	seconds := d.Seconds()
+	i := 0
+	for i < len(h.bounds) && seconds > h.bounds[i] {
+		i++
+	}
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddUint64(&h.sumNs, uint64(d.Nanoseconds()))
+}
+
+// Snapshot returns the current state of the histogram. Concurrent observations may be partially included.
+func (h *Histogram) Snapshot() Snapshot {
+	snapshot := Snapshot{Buckets: make(map[float64]uint64, len(h.bounds))}
+	for i := range h.counts {
+		snapshot.Count += atomic.LoadUint64(&h.counts[i])
+		if i < len(h.bounds) {
+			snapshot.Buckets[h.bounds[i]] = snapshot.Count
+		}
+	}
+	snapshot.Sum = time.Duration(atomic.LoadUint64(&h.sumNs)).Seconds()
+	return snapshot
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := New([]float64{0.001, 0.01})
+	h.Observe(500 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(5 * time.Millisecond)
+	h.Observe(time.Second)
+
+	snapshot := h.Snapshot()
+	assert.Equal(t, uint64(4), snapshot.Count)
+	assert.InDelta(t, 1.0065, snapshot.Sum, 0.0000001)
+	// Buckets are cumulative, and observations above every bound are only in the count
+	assert.Equal(t, map[float64]uint64{0.001: 2, 0.01: 3}, snapshot.Buckets)
+}
+
+func TestObserveDoesNotAllocate(t *testing.T) {
+	h := New([]float64{0.001, 0.01})
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Observe(time.Millisecond)
+	})
+	assert.Zero(t, allocs)
+}
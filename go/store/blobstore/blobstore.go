@@ -18,14 +18,31 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sort"
+	"strings"
+	"time"
 )
 
+// DefaultListPageSize is the number of blobs that are returned by each call to Blobstore.List when no page size is given
+const DefaultListPageSize = 1000
+
 // Blobstore is an interface for storing and retrieving blobs of data by key
 type Blobstore interface {
 	Exists(ctx context.Context, key string) (bool, error)
 	Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error)
 	Put(ctx context.Context, key string, reader io.Reader) (string, error)
 	CheckAndPut(ctx context.Context, expectedVersion, key string, reader io.Reader) (string, error)
+	// List returns a page of the blobs whose keys begin with |prefix|, in ascending order of their keys, along with the
+	// token of the next page, which is empty once every blob has been returned. An empty |pageToken| returns the first
+	// page. At most |pageSize| blobs are returned, or DefaultListPageSize when |pageSize| is not positive.
+	List(ctx context.Context, prefix string, pageToken string, pageSize int) ([]BlobInfo, string, error)
+}
+
+// BlobInfo describes a blob that was returned by Blobstore.List.
+type BlobInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
 }
 
 // GetBytes is a utility method calls bs.Get and handles reading the data from the returned
@@ -52,3 +69,40 @@ func PutBytes(ctx context.Context, bs Blobstore, key string, data []byte) (strin
 	reader := bytes.NewReader(data)
 	return bs.Put(ctx, key, reader)
 }
+
+// ListAll is a utility method that calls bs.List until every blob whose key begins with |prefix| has been returned.
+func ListAll(ctx context.Context, bs Blobstore, prefix string) ([]BlobInfo, error) {
+	var blobs []BlobInfo
+	pageToken := ""
+	for {
+		page, nextPageToken, err := bs.List(ctx, prefix, pageToken, DefaultListPageSize)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, page...)
+		if nextPageToken == "" {
+			return blobs, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// pageOfSortedBlobs returns a page of the given blobs for implementations that list every blob at once. The blobs must
+// be sorted by their keys, which allows the token of each page to be the last key of the previous page.
+func pageOfSortedBlobs(blobs []BlobInfo, prefix string, pageToken string, pageSize int) ([]BlobInfo, string) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	start := sort.Search(len(blobs), func(i int) bool {
+		return blobs[i].Key > pageToken && blobs[i].Key >= prefix
+	})
+
+	var page []BlobInfo
+	for i := start; i < len(blobs) && strings.HasPrefix(blobs[i].Key, prefix); i++ {
+		if len(page) == pageSize {
+			return page, page[len(page)-1].Key
+		}
+		page = append(page, blobs[i])
+	}
+	return page, ""
+}
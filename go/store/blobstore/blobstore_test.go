@@ -377,3 +377,74 @@ func TestPanicOnNegativeRangeLength(t *testing.T) {
 
 	NewBlobRange(0, -1)
 }
+
+func testList(t *testing.T, bs Blobstore) {
+	ctx := context.Background()
+	var expected []string
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("list_%d", i)
+		if _, err := PutBytes(ctx, bs, key, randBytes(32+i)); err != nil {
+			t.Fatalf("Put failed %v.", err)
+		}
+		expected = append(expected, key)
+	}
+	if _, err := PutBytes(ctx, bs, "other", randBytes(32)); err != nil {
+		t.Fatalf("Put failed %v.", err)
+	}
+
+	var keys []string
+	pageToken := ""
+	for pages := 0; ; pages++ {
+		if pages > len(expected) {
+			t.Fatalf("List returned more pages than there are blobs.")
+		}
+		page, nextPageToken, err := bs.List(ctx, "list_", pageToken, 2)
+		if err != nil {
+			t.Fatalf("List failed %v.", err)
+		}
+		if len(page) > 2 {
+			t.Errorf("List returned %d blobs, which exceeds the page size.", len(page))
+		}
+		for _, blob := range page {
+			if blob.Size != int64(32+len(keys)) {
+				t.Errorf("Size mismatch for %s. Expected: %d Actual: %d.", blob.Key, 32+len(keys), blob.Size)
+			}
+			if blob.LastModified.IsZero() {
+				t.Errorf("LastModified missing for %s.", blob.Key)
+			}
+			keys = append(keys, blob.Key)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Keys mismatch. Expected: %v Actual: %v.", expected, keys)
+	}
+
+	all, err := ListAll(ctx, bs, "")
+	if err != nil {
+		t.Fatalf("ListAll failed %v.", err)
+	}
+	if len(all) != len(expected)+1 {
+		t.Errorf("ListAll returned %d blobs. Expected: %d.", len(all), len(expected)+1)
+	}
+
+	missing, err := ListAll(ctx, bs, "missing")
+	if err != nil {
+		t.Fatalf("ListAll failed %v.", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("ListAll returned blobs for a prefix that no key has.")
+	}
+}
+
+func TestList(t *testing.T) {
+	for _, bsTest := range newBlobStoreTests() {
+		t.Run(bsTest.bsType, func(t *testing.T) {
+			testList(t, bsTest.bs)
+		})
+	}
+}
@@ -19,9 +19,11 @@ import (
 	"io"
 	"path"
 	"strconv"
+	"strings"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 )
 
 const (
@@ -155,3 +157,32 @@ func (bs *GCSBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key st
 
 	return ver, err
 }
+
+// List returns a page of the blobs whose keys begin with the given prefix, along with the token of the next page.
+func (bs *GCSBlobstore) List(ctx context.Context, prefix string, pageToken string, pageSize int) ([]BlobInfo, string, error) {
+	// the prefix of the blobstore is a directory, so its separator is part of every object name within it
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	dirPrefix := ""
+	if len(bs.prefix) > 0 {
+		dirPrefix = strings.TrimSuffix(bs.prefix, "/") + "/"
+	}
+
+	it := bs.bucket.Objects(ctx, &storage.Query{Prefix: dirPrefix + prefix})
+	var attrs []*storage.ObjectAttrs
+	nextPageToken, err := iterator.NewPager(it, pageSize, pageToken).NextPage(&attrs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	blobs := make([]BlobInfo, len(attrs))
+	for i, attr := range attrs {
+		blobs[i] = BlobInfo{
+			Key:          strings.TrimPrefix(attr.Name, dirPrefix),
+			Size:         attr.Size,
+			LastModified: attr.Updated,
+		}
+	}
+	return blobs, nextPageToken, nil
+}
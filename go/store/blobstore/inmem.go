@@ -18,7 +18,9 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -38,11 +40,12 @@ type InMemoryBlobstore struct {
 	mutex    sync.Mutex
 	blobs    map[string][]byte
 	versions map[string]string
+	modified map[string]time.Time
 }
 
 // NewInMemoryBlobstore creates an instance of an InMemoryBlobstore
 func NewInMemoryBlobstore() *InMemoryBlobstore {
-	return &InMemoryBlobstore{blobs: make(map[string][]byte), versions: make(map[string]string)}
+	return &InMemoryBlobstore{blobs: make(map[string][]byte), versions: make(map[string]string), modified: make(map[string]time.Time)}
 }
 
 // Get retrieves an io.reader for the portion of a blob specified by br along with
@@ -88,6 +91,7 @@ func (bs *InMemoryBlobstore) Put(ctx context.Context, key string, reader io.Read
 
 	bs.blobs[key] = data
 	bs.versions[key] = ver
+	bs.modified[key] = time.Now()
 
 	return ver, nil
 }
@@ -114,6 +118,7 @@ func (bs *InMemoryBlobstore) CheckAndPut(ctx context.Context, expectedVersion, k
 
 	bs.blobs[key] = data
 	bs.versions[key] = newVer
+	bs.modified[key] = time.Now()
 
 	return newVer, nil
 }
@@ -126,3 +131,19 @@ func (bs *InMemoryBlobstore) Exists(ctx context.Context, key string) (bool, erro
 
 	return ok, nil
 }
+
+// List returns a page of the blobs whose keys begin with the given prefix, along with the token of the next page.
+func (bs *InMemoryBlobstore) List(ctx context.Context, prefix string, pageToken string, pageSize int) ([]BlobInfo, string, error) {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	blobs := make([]BlobInfo, 0, len(bs.blobs))
+	for key, data := range bs.blobs {
+		blobs = append(blobs, BlobInfo{Key: key, Size: int64(len(data)), LastModified: bs.modified[key]})
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Key < blobs[j].Key
+	})
+	page, nextPageToken := pageOfSortedBlobs(blobs, prefix, pageToken, pageSize)
+	return page, nextPageToken, nil
+}
@@ -21,6 +21,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/dolthub/fslock"
 	"github.com/google/uuid"
@@ -232,3 +234,37 @@ func (bs *LocalBlobstore) Exists(ctx context.Context, key string) (bool, error)
 
 	return err == nil, err
 }
+
+// List returns a page of the blobs whose keys begin with the given prefix, along with the token of the next page.
+// The reported size of each blob excludes the version that is stored at the start of its file.
+func (bs *LocalBlobstore) List(ctx context.Context, prefix string, pageToken string, pageSize int) ([]BlobInfo, string, error) {
+	var blobs []BlobInfo
+	err := filepath.Walk(bs.RootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, bsExt) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bs.RootDir, path)
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, BlobInfo{
+			Key:          filepath.ToSlash(strings.TrimSuffix(rel, bsExt)),
+			Size:         info.Size() - lfsVerSize,
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Key < blobs[j].Key
+	})
+	page, nextPageToken := pageOfSortedBlobs(blobs, prefix, pageToken, pageSize)
+	return page, nextPageToken, nil
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
@@ -116,6 +117,39 @@ func (ob *OSSBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key st
 	return ob.getVersion(meta), nil
 }
 
+// List returns a page of the blobs whose keys begin with the given prefix, along with the token of the next page.
+func (ob *OSSBlobstore) List(_ context.Context, prefix string, pageToken string, pageSize int) ([]BlobInfo, string, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	dirPrefix := ""
+	if len(ob.prefix) > 0 {
+		dirPrefix = strings.TrimSuffix(ob.prefix, "/") + "/"
+	}
+
+	options := []oss.Option{oss.Prefix(dirPrefix + prefix), oss.MaxKeys(pageSize)}
+	if len(pageToken) > 0 {
+		options = append(options, oss.ContinuationToken(pageToken))
+	}
+	res, err := ob.bucket.ListObjectsV2(options...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	blobs := make([]BlobInfo, len(res.Objects))
+	for i, obj := range res.Objects {
+		blobs[i] = BlobInfo{
+			Key:          strings.TrimPrefix(obj.Key, dirPrefix),
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+		}
+	}
+	if !res.IsTruncated {
+		return blobs, "", nil
+	}
+	return blobs, res.NextContinuationToken, nil
+}
+
 func (ob *OSSBlobstore) absKey(key string) string {
 	return path.Join(ob.prefix, key)
 }
@@ -111,6 +111,16 @@ type ChunkStoreGarbageCollector interface {
 	MarkAndSweepChunks(ctx context.Context, last hash.Hash, keepChunks <-chan []hash.Hash, dest ChunkStore) error
 }
 
+// IterableChunkStore is a ChunkStore whose chunks can be enumerated, such as to find the chunks that are no longer
+// reachable from its root.
+type IterableChunkStore interface {
+	ChunkStore
+
+	// IterateAllAddresses calls |cb| with the address of every chunk in the store, in no particular order. A chunk that
+	// is stored more than once may be visited more than once. Iteration stops at the first error returned by |cb|.
+	IterateAllAddresses(ctx context.Context, cb func(h hash.Hash) error) error
+}
+
 type PrefixChunkStore interface {
 	ChunkStore
 
@@ -134,6 +134,7 @@ type MemoryStoreView struct {
 
 var _ ChunkStore = &MemoryStoreView{}
 var _ ChunkStoreGarbageCollector = &MemoryStoreView{}
+var _ IterableChunkStore = &MemoryStoreView{}
 
 func (ms *MemoryStoreView) Get(ctx context.Context, h hash.Hash) (Chunk, error) {
 	ms.mu.RLock()
@@ -203,6 +204,30 @@ func (ms *MemoryStoreView) Len() int {
 	return len(ms.pending) + ms.storage.Len()
 }
 
+// IterateAllAddresses implements IterableChunkStore.
+func (ms *MemoryStoreView) IterateAllAddresses(ctx context.Context, cb func(h hash.Hash) error) error {
+	ms.mu.RLock()
+	addrs := make(hash.HashSet, len(ms.pending))
+	for h := range ms.pending {
+		addrs.Insert(h)
+	}
+	storage := ms.storage
+	ms.mu.RUnlock()
+
+	storage.mu.RLock()
+	for h := range storage.data {
+		addrs.Insert(h)
+	}
+	storage.mu.RUnlock()
+
+	for h := range addrs {
+		if err := cb(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ms *MemoryStoreView) Rebase(ctx context.Context) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
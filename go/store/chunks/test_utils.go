@@ -110,6 +110,50 @@ func (s *TestStoreView) Writes() int {
 	return int(writes)
 }
 
+// MissingChunkStore is a ChunkStore that omits the Missing chunks, as though they had never been written to the
+// underlying store. It stands in for stores with incomplete histories, such as those populated by a shallow clone.
+type MissingChunkStore struct {
+	ChunkStore
+	Missing hash.HashSet
+}
+
+func (s *MissingChunkStore) Get(ctx context.Context, h hash.Hash) (Chunk, error) {
+	if s.Missing.Has(h) {
+		return EmptyChunk, nil
+	}
+	return s.ChunkStore.Get(ctx, h)
+}
+
+func (s *MissingChunkStore) GetMany(ctx context.Context, hashes hash.HashSet, found func(context.Context, *Chunk)) error {
+	present := hash.NewHashSet()
+	for h := range hashes {
+		if !s.Missing.Has(h) {
+			present.Insert(h)
+		}
+	}
+	return s.ChunkStore.GetMany(ctx, present, found)
+}
+
+func (s *MissingChunkStore) Has(ctx context.Context, h hash.Hash) (bool, error) {
+	if s.Missing.Has(h) {
+		return false, nil
+	}
+	return s.ChunkStore.Has(ctx, h)
+}
+
+func (s *MissingChunkStore) HasMany(ctx context.Context, hashes hash.HashSet) (hash.HashSet, error) {
+	absent, err := s.ChunkStore.HasMany(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	for h := range hashes {
+		if s.Missing.Has(h) {
+			absent.Insert(h)
+		}
+	}
+	return absent, nil
+}
+
 type TestStoreFactory struct {
 	stores map[string]*TestStorage
 }
@@ -166,6 +166,10 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	nameoff := builder.CreateString(opts.Meta.Name)
 	emailoff := builder.CreateString(opts.Meta.Email)
 	descoff := builder.CreateString(opts.Meta.Description)
+	var tzoff flatbuffers.UOffsetT
+	if opts.Meta.UserTimezone != "" {
+		tzoff = builder.CreateString(opts.Meta.UserTimezone)
+	}
 	serial.CommitStart(builder)
 	serial.CommitAddRoot(builder, vaddroff)
 	serial.CommitAddHeight(builder, maxheight+1)
@@ -176,6 +180,9 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	serial.CommitAddDescription(builder, descoff)
 	serial.CommitAddTimestampMillis(builder, opts.Meta.Timestamp)
 	serial.CommitAddUserTimestampMillis(builder, opts.Meta.UserTimestamp)
+	if tzoff != 0 {
+		serial.CommitAddUserTimezone(builder, tzoff)
+	}
 
 	bytes := serial.FinishMessage(builder, serial.CommitEnd(builder), []byte(serial.CommitFileID))
 	return bytes, maxheight + 1
@@ -305,13 +312,17 @@ func commitFromValue(nbf *types.NomsBinFormat, v types.Value) (*Commit, error) {
 	return commitPtr(nbf, v, nil)
 }
 
+// ErrCommitNotFound is returned when loading a commit whose chunk is not present in the store, such as an ancestor that
+// was omitted from a shallow clone.
+var ErrCommitNotFound = errors.New("target commit not found")
+
 func LoadCommitRef(ctx context.Context, vr types.ValueReader, r types.Ref) (*Commit, error) {
 	v, err := vr.ReadValue(ctx, r.TargetHash())
 	if err != nil {
 		return nil, err
 	}
 	if v == nil {
-		return nil, errors.New("target commit not found")
+		return nil, ErrCommitNotFound
 	}
 	return commitPtr(vr.Format(), v, &r)
 }
@@ -322,7 +333,7 @@ func LoadCommitAddr(ctx context.Context, vr types.ValueReader, addr hash.Hash) (
 		return nil, err
 	}
 	if v == nil {
-		return nil, errors.New("target commit not found")
+		return nil, ErrCommitNotFound
 	}
 	return commitFromValue(vr.Format(), v)
 }
@@ -450,7 +461,7 @@ func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value)
 		res := make([]*Commit, len(vals))
 		for i, v := range vals {
 			if v == nil {
-				return nil, fmt.Errorf("GetCommitParents: Did not find parent Commit in ValueReader: %s", addrs[i].String())
+				return nil, fmt.Errorf("GetCommitParents: Did not find parent Commit in ValueReader: %s: %w", addrs[i].String(), ErrCommitNotFound)
 			}
 			var csm serial.Commit
 			err := serial.InitCommitRoot(&csm, []byte(v.(types.SerialMessage)), serial.MessagePrefixSz)
@@ -507,7 +518,7 @@ func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value)
 	res := make([]*Commit, len(refs))
 	for i, val := range vals {
 		if val == nil {
-			return nil, fmt.Errorf("GetCommitParents: Did not find parent Commit in ValueReader: %s", hashes[i].String())
+			return nil, fmt.Errorf("GetCommitParents: Did not find parent Commit in ValueReader: %s: %w", hashes[i].String(), ErrCommitNotFound)
 		}
 		res[i] = &Commit{
 			val:    val,
@@ -521,6 +532,18 @@ func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value)
 // GetCommitMeta extracts the CommitMeta field from a commit. Returns |nil,
 // nil| if there is no metadata for the commit.
 func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
+	return getCommitMeta(ctx, cv, true)
+}
+
+// GetCommitMetaWithoutDescription extracts the CommitMeta field from a commit
+// in the same way as GetCommitMeta, except that the Description is left empty.
+// Descriptions may be very large, so this avoids copying them for callers that
+// do not use them.
+func GetCommitMetaWithoutDescription(ctx context.Context, cv types.Value) (*CommitMeta, error) {
+	return getCommitMeta(ctx, cv, false)
+}
+
+func getCommitMeta(ctx context.Context, cv types.Value, withDescription bool) (*CommitMeta, error) {
 	if sm, ok := cv.(types.SerialMessage); ok {
 		data := []byte(sm)
 		if serial.GetFileID(data) != serial.CommitFileID {
@@ -534,9 +557,12 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		ret := &CommitMeta{}
 		ret.Name = string(cmsg.Name())
 		ret.Email = string(cmsg.Email())
-		ret.Description = string(cmsg.Description())
+		if withDescription {
+			ret.Description = string(cmsg.Description())
+		}
 		ret.Timestamp = cmsg.TimestampMillis()
 		ret.UserTimestamp = cmsg.UserTimestampMillis()
+		ret.UserTimezone = string(cmsg.UserTimezone())
 		return ret, nil
 	}
 	c, ok := cv.(types.Struct)
@@ -554,7 +580,11 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		return nil, nil
 	}
 	if metaSt, ok := metaVal.(types.Struct); ok {
-		return CommitMetaFromNomsSt(metaSt)
+		meta, err := CommitMetaFromNomsSt(metaSt)
+		if err == nil && !withDescription {
+			meta.Description = ""
+		}
+		return meta, err
 	} else {
 		return nil, errors.New("GetCommitMeta: Commit had metadata field but it was not a Struct.")
 	}
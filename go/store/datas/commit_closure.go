@@ -32,30 +32,13 @@ func newParentsClosureIterator(ctx context.Context, c *Commit, vr types.ValueRea
 	sv := c.NomsValue()
 
 	if _, ok := sv.(types.SerialMessage); ok {
-		var msg serial.Commit
-		err := serial.InitCommitRoot(&msg, sv.(types.SerialMessage), serial.MessagePrefixSz)
+		cc, ok, err := getFbCommitParentClosure(ctx, c, vr, ns)
 		if err != nil {
 			return nil, err
 		}
-		addr := hash.New(msg.ParentClosureBytes())
-		if addr.IsEmpty() {
+		if !ok {
 			return nil, nil
 		}
-		v, err := vr.ReadValue(ctx, addr)
-		if err != nil {
-			return nil, err
-		}
-		if types.IsNull(v) {
-			return nil, fmt.Errorf("internal error or data loss: dangling commit parent closure for addr %s or commit %s", addr.String(), c.Addr().String())
-		}
-		node, err := tree.NodeFromBytes(v.(types.SerialMessage))
-		if err != nil {
-			return nil, err
-		}
-		cc, err := prolly.NewCommitClosure(node, ns)
-		if err != nil {
-			return nil, err
-		}
 		ci, err := cc.IterAllReverse(ctx)
 		if err != nil {
 			return nil, err
@@ -112,6 +95,69 @@ func newParentsClosureIterator(ctx context.Context, c *Commit, vr types.ValueRea
 	return &parentsClosureIterator{mi, nil, initialCurr}, nil
 }
 
+// getFbCommitParentClosure returns the parent closure of a flatbuffers commit. Returns false if the commit has no
+// parents, as parentless commits do not store a closure.
+func getFbCommitParentClosure(ctx context.Context, c *Commit, vr types.ValueReader, ns tree.NodeStore) (prolly.CommitClosure, bool, error) {
+	var msg serial.Commit
+	err := serial.InitCommitRoot(&msg, c.NomsValue().(types.SerialMessage), serial.MessagePrefixSz)
+	if err != nil {
+		return prolly.CommitClosure{}, false, err
+	}
+	addr := hash.New(msg.ParentClosureBytes())
+	if addr.IsEmpty() {
+		return prolly.CommitClosure{}, false, nil
+	}
+	v, err := vr.ReadValue(ctx, addr)
+	if err != nil {
+		return prolly.CommitClosure{}, false, err
+	}
+	if types.IsNull(v) {
+		return prolly.CommitClosure{}, false, fmt.Errorf("internal error or data loss: dangling commit parent closure for addr %s or commit %s", addr.String(), c.Addr().String())
+	}
+	node, err := tree.NodeFromBytes(v.(types.SerialMessage))
+	if err != nil {
+		return prolly.CommitClosure{}, false, err
+	}
+	cc, err := prolly.NewCommitClosure(node, ns)
+	if err != nil {
+		return prolly.CommitClosure{}, false, err
+	}
+	return cc, true, nil
+}
+
+// GetAncestorsAtHeight returns the addresses of the ancestors of |c| with the given height, which are read from the
+// commit's parent closure rather than by walking the commit graph. Returns false if the commit's format does not
+// support looking up ancestors by height.
+func GetAncestorsAtHeight(ctx context.Context, c *Commit, height uint64, vr types.ValueReader, ns tree.NodeStore) ([]hash.Hash, bool, error) {
+	if _, ok := c.NomsValue().(types.SerialMessage); !ok {
+		return nil, false, nil
+	}
+	if height >= c.Height() {
+		return nil, true, nil
+	}
+	cc, ok, err := getFbCommitParentClosure(ctx, c, vr, ns)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, true, nil
+	}
+	iter, err := cc.IterHeight(ctx, height)
+	if err != nil {
+		return nil, false, err
+	}
+	var addrs []hash.Hash
+	for {
+		k, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			return addrs, true, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+		addrs = append(addrs, k.Addr())
+	}
+}
+
 func commitToMapKeyTuple(f *types.NomsBinFormat, c *Commit) (types.Tuple, error) {
 	h := c.Addr()
 	ib := make([]byte, len(hash.Hash{}))
@@ -29,6 +29,7 @@ const (
 	commitMetaDescKey      = "desc"
 	commitMetaTimestampKey = "timestamp"
 	commitMetaUserTSKey    = "user_timestamp"
+	commitMetaUserTZKey    = "user_timezone"
 	commitMetaVersionKey   = "metaversion"
 
 	commitMetaStName  = "metadata"
@@ -49,6 +50,9 @@ type CommitMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// UserTimezone is the UTC offset of the committer's time zone, such as "+05:30", and is empty for commits that were
+	// written before the offset was recorded
+	UserTimezone string
 }
 
 // NewCommitMeta creates a CommitMeta instance from a name, email, and description and uses the current time for the
@@ -78,7 +82,7 @@ func NewCommitMetaWithUserTS(name, email, desc string, userTS time.Time) (*Commi
 	ms := uint64(CommitNowFunc().UnixMilli())
 	userMS := userTS.UnixMilli()
 
-	return &CommitMeta{n, e, ms, d, userMS}, nil
+	return &CommitMeta{n, e, ms, d, userMS, FormatTimezoneOffset(userTS)}, nil
 }
 
 func getRequiredFromSt(st types.Struct, k string) (types.Value, error) {
@@ -124,12 +128,20 @@ func CommitMetaFromNomsSt(st types.Struct) (*CommitMeta, error) {
 		userTS = types.Int(int64(uint64(ts.(types.Uint))))
 	}
 
+	var userTZ string
+	if tz, ok, err := st.MaybeGet(commitMetaUserTZKey); err != nil {
+		return nil, err
+	} else if ok {
+		userTZ = string(tz.(types.String))
+	}
+
 	return &CommitMeta{
 		string(n.(types.String)),
 		string(e.(types.String)),
 		uint64(ts.(types.Uint)),
 		string(d.(types.String)),
 		int64(userTS.(types.Int)),
+		userTZ,
 	}, nil
 }
 
@@ -142,6 +154,9 @@ func (cm *CommitMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, erro
 		commitMetaVersionKey:   types.String(commitMetaVersion),
 		commitMetaUserTSKey:    types.Int(cm.UserTimestamp),
 	}
+	if cm.UserTimezone != "" {
+		metadata[commitMetaUserTZKey] = types.String(cm.UserTimezone)
+	}
 
 	return types.NewStruct(nbf, commitMetaStName, metadata)
 }
@@ -151,6 +166,12 @@ func (cm *CommitMeta) Time() time.Time {
 	return time.UnixMilli(cm.UserTimestamp)
 }
 
+// FormatTimezoneOffset returns the UTC offset of the given time's location in the form stored by CommitMeta, such as
+// "+05:30" or "-08:00".
+func FormatTimezoneOffset(t time.Time) string {
+	return t.Format("-07:00")
+}
+
 // FormatTS takes the internal timestamp and turns it into a human readable string in the time.RubyDate format
 // which looks like: "Mon Jan 02 15:04:05 -0700 2006"
 func (cm *CommitMeta) FormatTS() string {
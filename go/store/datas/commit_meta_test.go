@@ -17,8 +17,10 @@ package datas
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -37,3 +39,27 @@ func TestCommitMetaToAndFromNomsStruct(t *testing.T) {
 
 	t.Log(cm.String())
 }
+
+func TestCommitMetaUserTimezone(t *testing.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	cm, err := NewCommitMetaWithUserTS("Bill Billerson", "bigbillieb@fake.horse", "This is a test commit", time.Date(2022, 10, 1, 12, 0, 0, 0, loc))
+	require.NoError(t, err)
+	assert.Equal(t, "+05:30", cm.UserTimezone)
+
+	cmSt, err := cm.toNomsStruct(types.Format_Default)
+	require.NoError(t, err)
+	result, err := CommitMetaFromNomsSt(cmSt)
+	require.NoError(t, err)
+	assert.Equal(t, cm, result)
+
+	// metadata written before the time zone was recorded has none
+	cm.UserTimezone = ""
+	cmSt, err = cm.toNomsStruct(types.Format_Default)
+	require.NoError(t, err)
+	_, ok, err := cmSt.MaybeGet(commitMetaUserTZKey)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	result, err = CommitMetaFromNomsSt(cmSt)
+	require.NoError(t, err)
+	assert.Equal(t, "", result.UserTimezone)
+}
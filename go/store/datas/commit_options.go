@@ -33,4 +33,13 @@ type CommitOptions struct {
 	Parents []hash.Hash
 
 	Meta *CommitMeta
+
+	// Amend, if set, creates a commit that replaces the existing dataset
+	// head rather than following it. The existing head must be
+	// ExpectedHead, and is not added as a parent of the new commit.
+	Amend bool
+
+	// ExpectedHead is the head that an amended commit replaces. The
+	// commit fails with ErrMergeNeeded if the dataset's head has moved.
+	ExpectedHead hash.Hash
 }
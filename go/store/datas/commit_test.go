@@ -511,6 +511,38 @@ func TestCommitParentsClosure(t *testing.T) {
 	})
 }
 
+func TestGetAncestorsAtHeight(t *testing.T) {
+	storage := &chunks.TestStorage{}
+	db := NewDatabase(storage.NewViewWithDefaultFormat()).(*database)
+	ctx := context.Background()
+
+	a, b, c := "ds-a", "ds-b", "ds-c"
+	a1, a1a := addCommit(t, db, a, "a1")
+	a2, a2a := addCommit(t, db, a, "a2", a1)
+	b1, b1a := addCommit(t, db, b, "b1", a1)
+	c1, _ := addCommit(t, db, c, "c1", a2, b1)
+
+	assertAncestorsAtHeight := func(v types.Value, height uint64, expected ...hash.Hash) {
+		cm, err := commitPtr(db.Format(), v, nil)
+		require.NoError(t, err)
+		addrs, ok, err := GetAncestorsAtHeight(ctx, cm, height, db, db.ns)
+		require.NoError(t, err)
+		if !types.IsFormat_DOLT(db.Format()) {
+			assert.False(t, ok)
+			return
+		}
+		require.True(t, ok)
+		assert.ElementsMatch(t, expected, addrs)
+	}
+
+	assertAncestorsAtHeight(a1, 1)
+	assertAncestorsAtHeight(a2, 1, a1a)
+	assertAncestorsAtHeight(a2, 2)
+	assertAncestorsAtHeight(c1, 2, a2a, b1a)
+	assertAncestorsAtHeight(c1, 1, a1a)
+	assertAncestorsAtHeight(c1, 3)
+}
+
 func TestFindCommonAncestor(t *testing.T) {
 	assert := assert.New(t)
 
@@ -621,8 +621,9 @@ func (db *database) CommitWithWorkingSet(
 	}
 
 	// Prepend the current head hash to the list of parents if one was provided. This is only necessary if parents were
-	// provided because we fill it in automatically in buildNewCommit otherwise.
-	if len(opts.Parents) > 0 {
+	// provided because we fill it in automatically in buildNewCommit otherwise. Amended commits replace the current head,
+	// so it is never one of their parents.
+	if len(opts.Parents) > 0 && !opts.Amend {
 		headHash, ok := commitDS.MaybeHeadAddr()
 		if ok {
 			if !hasParentHash(opts, headHash) {
@@ -871,7 +872,13 @@ func (db *database) validateRefAsCommit(ctx context.Context, r types.Ref) (types
 }
 
 func buildNewCommit(ctx context.Context, ds Dataset, v types.Value, opts CommitOptions) (*Commit, error) {
-	if len(opts.Parents) == 0 {
+	if opts.Amend {
+		// the head being replaced must still be the commit that was amended
+		headAddr, ok := ds.MaybeHeadAddr()
+		if !ok || headAddr != opts.ExpectedHead {
+			return nil, ErrMergeNeeded
+		}
+	} else if len(opts.Parents) == 0 {
 		headAddr, ok := ds.MaybeHeadAddr()
 		if ok {
 			opts.Parents = []hash.Hash{headAddr}
@@ -573,6 +573,41 @@ func (suite *DatabaseSuite) TestFastForward() {
 	suite.True(mustHeadValue(ds).Equals(c))
 }
 
+func (suite *DatabaseSuite) TestAmend() {
+	datasetID := "ds1"
+
+	// |a| <- |b|
+	ds, err := suite.db.GetDataset(context.Background(), datasetID)
+	suite.NoError(err)
+	a := types.String("a")
+	ds, err = CommitValue(context.Background(), suite.db, ds, a)
+	suite.NoError(err)
+	aCommitAddr := mustHeadAddr(ds)
+
+	b := types.String("b")
+	ds, err = CommitValue(context.Background(), suite.db, ds, b)
+	suite.NoError(err)
+	bCommitAddr := mustHeadAddr(ds)
+
+	// Amending |b| with |c| replaces it, giving |a| <- |c|
+	c := types.String("c")
+	ds, err = suite.db.Commit(context.Background(), ds, c, CommitOptions{Parents: []hash.Hash{aCommitAddr}, Amend: true, ExpectedHead: bCommitAddr})
+	suite.Require().NoError(err)
+	suite.True(mustHeadValue(ds).Equals(c))
+	parents, err := GetCommitParents(context.Background(), suite.db, mustHead(ds))
+	suite.Require().NoError(err)
+	suite.Require().Len(parents, 1)
+	suite.Equal(aCommitAddr, parents[0].Addr())
+
+	// Amending a commit that is no longer the head would drop commits, so it fails, even when the parents are the same
+	d := types.String("d")
+	_, err = suite.db.Commit(context.Background(), ds, d, CommitOptions{Parents: []hash.Hash{aCommitAddr}, Amend: true, ExpectedHead: bCommitAddr})
+	suite.Equal(ErrMergeNeeded, err)
+	_, err = suite.db.Commit(context.Background(), ds, d, CommitOptions{Parents: []hash.Hash{aCommitAddr}, Amend: true})
+	suite.Equal(ErrMergeNeeded, err)
+	suite.True(mustHeadValue(ds).Equals(c))
+}
+
 func (suite *DatabaseSuite) TestDatabaseHeightOfRefs() {
 	r1, err := suite.db.WriteValue(context.Background(), types.String("hello"))
 	suite.NoError(err)
@@ -650,8 +685,15 @@ func (suite *DatabaseSuite) TestMetaOption() {
 	ds, err := suite.db.GetDataset(ctx, "ds1")
 	suite.NoError(err)
 
-	ds, err = suite.db.Commit(ctx, ds, types.String("a"), CommitOptions{Meta: &CommitMeta{Name: "arv"}})
+	ds, err = suite.db.Commit(ctx, ds, types.String("a"), CommitOptions{Meta: &CommitMeta{Name: "arv", Description: "a description", UserTimezone: "-08:00"}})
 	suite.NoError(err)
 	meta, err := GetCommitMeta(ctx, mustHead(ds))
 	suite.Equal("arv", meta.Name)
+	suite.Equal("a description", meta.Description)
+	suite.Equal("-08:00", meta.UserTimezone)
+
+	withoutDescription, err := GetCommitMetaWithoutDescription(ctx, mustHead(ds))
+	suite.NoError(err)
+	meta.Description = ""
+	suite.Equal(meta, withoutDescription)
 }
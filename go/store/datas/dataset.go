@@ -164,6 +164,8 @@ type MergeState struct {
 	preMergeWorkingAddr *hash.Hash
 	fromCommitAddr      *hash.Hash
 	fromCommitSpec      string
+	unmergableTables    []string
+	isCherryPick        bool
 
 	nomsMergeStateRef *types.Ref
 	nomsMergeState    *types.Struct
@@ -255,6 +257,60 @@ func (ms *MergeState) FromCommitSpec(ctx context.Context, vr types.ValueReader)
 	return string(commitSpecStr.(types.String)), nil
 }
 
+// UnmergableTables returns the tables whose schemas could not be merged, which is empty for merge states written
+// before they were recorded.
+func (ms *MergeState) UnmergableTables(ctx context.Context, vr types.ValueReader) ([]string, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.unmergableTables, nil
+	}
+
+	if ms.nomsMergeState == nil {
+		err := ms.loadIfNeeded(ctx, vr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tablesV, ok, err := ms.nomsMergeState.MaybeGet(mergeStateUnmergableTables)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var tables []string
+	err = tablesV.(types.List).IterAll(ctx, func(v types.Value, _ uint64) error {
+		tables = append(tables, string(v.(types.String)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// IsCherryPick returns whether the merge state is of a cherry-pick that stopped on conflicts, which is false for merge
+// states written before cherry-picks were recorded.
+func (ms *MergeState) IsCherryPick(ctx context.Context, vr types.ValueReader) (bool, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.isCherryPick, nil
+	}
+
+	if ms.nomsMergeState == nil {
+		err := ms.loadIfNeeded(ctx, vr)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	v, ok, err := ms.nomsMergeState.MaybeGet(mergeStateIsCherryPickField)
+	if err != nil || !ok {
+		return false, err
+	}
+	return bool(v.(types.Bool)), nil
+}
+
 type dsHead interface {
 	TypeName() string
 	Addr() hash.Hash
@@ -369,6 +425,10 @@ func (h serialWorkingSetHead) HeadWorkingSet() (*WorkingSetHead, error) {
 		}
 		*ret.MergeState.preMergeWorkingAddr = hash.New(mergeState.PreWorkingRootAddrBytes())
 		*ret.MergeState.fromCommitAddr = hash.New(mergeState.FromCommitAddrBytes())
+		for i := 0; i < mergeState.UnmergableTablesLength(); i++ {
+			ret.MergeState.unmergableTables = append(ret.MergeState.unmergableTables, string(mergeState.UnmergableTables(i)))
+		}
+		ret.MergeState.isCherryPick = mergeState.IsCherryPick()
 	}
 	return &ret, nil
 }
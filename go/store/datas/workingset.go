@@ -37,6 +37,8 @@ const (
 	mergeStateCommitSpecField      = "commitSpec"
 	mergeStateCommitField          = "commit"
 	mergeStateWorkingPreMergeField = "workingPreMerge"
+	mergeStateUnmergableTables     = "unmergableTables"
+	mergeStateIsCherryPickField    = "isCherryPick"
 )
 
 const (
@@ -162,10 +164,28 @@ func workingset_flatbuffer(working hash.Hash, staged *hash.Hash, mergeState *Mer
 		prerootaddroff := builder.CreateByteVector((*mergeState.preMergeWorkingAddr)[:])
 		fromaddroff := builder.CreateByteVector((*mergeState.fromCommitAddr)[:])
 		fromspecoff := builder.CreateString(mergeState.fromCommitSpec)
+		var unmergableoff flatbuffers.UOffsetT
+		if len(mergeState.unmergableTables) > 0 {
+			offs := make([]flatbuffers.UOffsetT, len(mergeState.unmergableTables))
+			for i, tbl := range mergeState.unmergableTables {
+				offs[i] = builder.CreateString(tbl)
+			}
+			serial.MergeStateStartUnmergableTablesVector(builder, len(offs))
+			for i := len(offs) - 1; i >= 0; i-- {
+				builder.PrependUOffsetT(offs[i])
+			}
+			unmergableoff = builder.EndVector(len(offs))
+		}
 		serial.MergeStateStart(builder)
 		serial.MergeStateAddPreWorkingRootAddr(builder, prerootaddroff)
 		serial.MergeStateAddFromCommitAddr(builder, fromaddroff)
 		serial.MergeStateAddFromCommitSpecStr(builder, fromspecoff)
+		if unmergableoff != 0 {
+			serial.MergeStateAddUnmergableTables(builder, unmergableoff)
+		}
+		if mergeState.isCherryPick {
+			serial.MergeStateAddIsCherryPick(builder, true)
+		}
 		mergeStateOff = serial.MergeStateEnd(builder)
 	}
 
@@ -193,18 +213,49 @@ func workingset_flatbuffer(working hash.Hash, staged *hash.Hash, mergeState *Mer
 	return serial.FinishMessage(builder, serial.WorkingSetEnd(builder), []byte(serial.WorkingSetFileID))
 }
 
-func NewMergeState(ctx context.Context, vrw types.ValueReadWriter, preMergeWorking types.Ref, commit *Commit, commitSpecStr string) (*MergeState, error) {
+// NewMergeState returns a new MergeState for a merge of |commit|. |unmergableTables| are the tables whose schemas
+// could not be merged, which may be empty. |isCherryPick| is set for cherry-picks of |commit| that stopped on conflicts.
+func NewMergeState(ctx context.Context, vrw types.ValueReadWriter, preMergeWorking types.Ref, commit *Commit, commitSpecStr string, unmergableTables []string, isCherryPick bool) (*MergeState, error) {
 	if vrw.Format().UsesFlatbuffers() {
 		ms := &MergeState{
 			preMergeWorkingAddr: new(hash.Hash),
 			fromCommitAddr:      new(hash.Hash),
 			fromCommitSpec:      commitSpecStr,
+			unmergableTables:    unmergableTables,
+			isCherryPick:        isCherryPick,
 		}
 		*ms.preMergeWorkingAddr = preMergeWorking.TargetHash()
 		*ms.fromCommitAddr = commit.Addr()
 		return ms, nil
 	} else {
-		v, err := mergeStateTemplate.NewStruct(preMergeWorking.Format(), []types.Value{commit.NomsValue(), types.String(commitSpecStr), preMergeWorking})
+		var v types.Struct
+		var err error
+		if len(unmergableTables) == 0 && !isCherryPick {
+			v, err = mergeStateTemplate.NewStruct(preMergeWorking.Format(), []types.Value{commit.NomsValue(), types.String(commitSpecStr), preMergeWorking})
+		} else {
+			// Merge states without unmergable tables or a cherry-pick are written without those fields, so that they
+			// are unchanged from before the fields existed
+			data := types.StructData{
+				mergeStateCommitField:          commit.NomsValue(),
+				mergeStateCommitSpecField:      types.String(commitSpecStr),
+				mergeStateWorkingPreMergeField: preMergeWorking,
+			}
+			if len(unmergableTables) > 0 {
+				tables := make([]types.Value, len(unmergableTables))
+				for i, tbl := range unmergableTables {
+					tables[i] = types.String(tbl)
+				}
+				l, err := types.NewList(ctx, vrw, tables...)
+				if err != nil {
+					return nil, err
+				}
+				data[mergeStateUnmergableTables] = l
+			}
+			if isCherryPick {
+				data[mergeStateIsCherryPickField] = types.Bool(true)
+			}
+			v, err = types.NewStruct(preMergeWorking.Format(), mergeStateName, data)
+		}
 		if err != nil {
 			return nil, err
 		}
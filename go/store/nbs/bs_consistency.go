@@ -0,0 +1,120 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+// MissingTableFile is a table file that is referenced by the manifest of a blobstore, but that either does not exist
+// or is too small to hold the chunks that the manifest records for it.
+type MissingTableFile struct {
+	Name       string
+	ChunkCount uint32
+	// Exists is true when the object exists, but is smaller than MinSize
+	Exists  bool
+	Size    int64
+	MinSize int64
+}
+
+// BlobstoreConsistencyReport is the result of comparing the manifest of a blobstore with the objects that it stores.
+type BlobstoreConsistencyReport struct {
+	// Missing are the referenced table files that are absent or implausibly small
+	Missing []MissingTableFile
+	// Orphaned are the stored objects that are neither the manifest nor referenced by it
+	Orphaned []blobstore.BlobInfo
+	// AccountedBytes is the total size of the manifest, its checksum, and the referenced table files that exist
+	AccountedBytes int64
+	// OrphanedBytes is the total size of the orphaned objects
+	OrphanedBytes int64
+}
+
+// Blobstore returns the blobstore that the table files of this store are persisted to, or false if this store is not
+// backed by a blobstore.
+func (nbs *NomsBlockStore) Blobstore() (blobstore.Blobstore, bool) {
+	if bsp, ok := nbs.p.(*blobstorePersister); ok {
+		return bsp.bs, true
+	}
+	return nil, false
+}
+
+// CheckBlobstoreConsistency reads the current manifest of |bs| and compares the table files that it references, in
+// both its specs and its appendix, with the objects that |bs| stores. Nothing is modified, so this is safe to run
+// against a blobstore that is in use, although objects that are written concurrently may be reported as orphans. The
+// manifest is verified against its checksum, if one was stored.
+func CheckBlobstoreConsistency(ctx context.Context, bs blobstore.Blobstore) (BlobstoreConsistencyReport, error) {
+	_, contents, err := manifestVersionAndContents(ctx, bs, true)
+	if err != nil {
+		return BlobstoreConsistencyReport{}, err
+	}
+
+	blobs, err := blobstore.ListAll(ctx, bs, "")
+	if err != nil {
+		return BlobstoreConsistencyReport{}, err
+	}
+	blobsByKey := make(map[string]blobstore.BlobInfo, len(blobs))
+	for _, blob := range blobs {
+		blobsByKey[blob.Key] = blob
+	}
+
+	var report BlobstoreConsistencyReport
+	referenced := map[string]struct{}{manifestFile: {}, manifestChecksumFile: {}}
+	for key := range referenced {
+		if blob, ok := blobsByKey[key]; ok {
+			report.AccountedBytes += blob.Size
+		}
+	}
+
+	specs := append(append([]tableSpec{}, contents.specs...), contents.appendix...)
+	for _, spec := range specs {
+		name := spec.name.String()
+		if _, ok := referenced[name]; ok {
+			continue
+		}
+		referenced[name] = struct{}{}
+
+		minSize := minTableFileSize(spec.chunkCount)
+		blob, ok := blobsByKey[name]
+		if !ok || blob.Size < minSize {
+			report.Missing = append(report.Missing, MissingTableFile{
+				Name:       name,
+				ChunkCount: spec.chunkCount,
+				Exists:     ok,
+				Size:       blob.Size,
+				MinSize:    minSize,
+			})
+		}
+		if ok {
+			report.AccountedBytes += blob.Size
+		}
+	}
+
+	for _, blob := range blobs {
+		if _, ok := referenced[blob.Key]; !ok {
+			report.Orphaned = append(report.Orphaned, blob)
+			report.OrphanedBytes += blob.Size
+		}
+	}
+
+	return report, nil
+}
+
+// minTableFileSize returns the smallest size of a table file with |chunkCount| chunks, in which every chunk record
+// holds its checksum and at least a single byte of data.
+func minTableFileSize(chunkCount uint32) int64 {
+	return int64(indexSize(chunkCount) + footerSize + uint64(chunkCount)*(checksumSize+1))
+}
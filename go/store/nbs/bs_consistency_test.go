@@ -0,0 +1,122 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestCheckBlobstoreConsistency(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	bsm := blobstoreManifest{"manifest", bs, false}
+
+	present := tableSpec{computeAddr([]byte("present")), 3}
+	truncated := tableSpec{computeAddr([]byte("truncated")), 2}
+	absent := tableSpec{computeAddr([]byte("absent")), 1}
+	appendix := tableSpec{computeAddr([]byte("appendix")), 1}
+	contents := manifestContents{
+		nbfVers:  constants.NomsVersion,
+		lock:     computeAddr([]byte("locker")),
+		root:     hash.Of([]byte("root")),
+		specs:    []tableSpec{appendix, present, truncated, absent},
+		appendix: []tableSpec{appendix},
+	}
+	_, err := bsm.Update(ctx, addr{}, contents, &Stats{}, nil)
+	require.NoError(t, err)
+
+	putTableFile := func(name addr, size int64) {
+		_, err := blobstore.PutBytes(ctx, bs, name.String(), make([]byte, size))
+		require.NoError(t, err)
+	}
+	putTableFile(present.name, minTableFileSize(present.chunkCount))
+	putTableFile(truncated.name, minTableFileSize(truncated.chunkCount)-1)
+	putTableFile(appendix.name, minTableFileSize(appendix.chunkCount)+10)
+	orphan := computeAddr([]byte("orphan"))
+	putTableFile(orphan, 100)
+
+	report, err := CheckBlobstoreConsistency(ctx, bs)
+	require.NoError(t, err)
+
+	assert.Equal(t, []MissingTableFile{
+		{
+			Name:       truncated.name.String(),
+			ChunkCount: truncated.chunkCount,
+			Exists:     true,
+			Size:       minTableFileSize(truncated.chunkCount) - 1,
+			MinSize:    minTableFileSize(truncated.chunkCount),
+		},
+		{
+			Name:       absent.name.String(),
+			ChunkCount: absent.chunkCount,
+			MinSize:    minTableFileSize(absent.chunkCount),
+		},
+	}, report.Missing)
+
+	require.Len(t, report.Orphaned, 1)
+	assert.Equal(t, orphan.String(), report.Orphaned[0].Key)
+	assert.Equal(t, int64(100), report.Orphaned[0].Size)
+	assert.False(t, report.Orphaned[0].LastModified.IsZero())
+	assert.Equal(t, int64(100), report.OrphanedBytes)
+
+	manifest, _, err := blobstore.GetBytes(ctx, bs, manifestFile, blobstore.AllRange)
+	require.NoError(t, err)
+	expectedBytes := int64(len(manifest)) +
+		minTableFileSize(present.chunkCount) +
+		minTableFileSize(truncated.chunkCount) - 1 +
+		minTableFileSize(appendix.chunkCount) + 10
+	assert.Equal(t, expectedBytes, report.AccountedBytes)
+}
+
+func TestCheckBlobstoreConsistencyWithoutManifest(t *testing.T) {
+	_, err := CheckBlobstoreConsistency(context.Background(), blobstore.NewInMemoryBlobstore())
+	assert.True(t, blobstore.IsNotFoundError(err))
+}
+
+func TestNomsBlockStoreBlobstore(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	store, err := NewBSStore(ctx, constants.FormatDefaultString, bs, 1<<20, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer store.Close()
+
+	c := chunks.NewChunk([]byte("abc"))
+	require.NoError(t, store.Put(ctx, c))
+	root, err := store.Root(ctx)
+	require.NoError(t, err)
+	ok, err := store.Commit(ctx, c.Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	storeBS, ok := store.Blobstore()
+	require.True(t, ok)
+	assert.Equal(t, bs, storeBS)
+
+	// every object written by the store is referenced by its manifest
+	report, err := CheckBlobstoreConsistency(ctx, storeBS)
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Orphaned)
+	assert.Positive(t, report.AccountedBytes)
+}
@@ -17,24 +17,62 @@ package nbs
 import (
 	"bytes"
 	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
 )
 
 const (
 	manifestFile = "manifest"
+	// manifestChecksumFile holds the length and checksum of the manifest, along with the version of the manifest that
+	// they were computed for
+	manifestChecksumFile = "manifest.checksum"
 )
 
+// ErrManifestChecksumMismatch is returned when a manifest read from a blobstore does not match the checksum that was
+// stored when it was written.
+var ErrManifestChecksumMismatch = errors.New("manifest does not match its checksum")
+
+// ManifestReadError is returned when the manifest of a blobstore was fetched, but could not be read or parsed. It
+// identifies the object that was fetched so the corruption can be tracked down in the underlying storage.
+type ManifestReadError struct {
+	Key     string
+	Version string
+	// Length is the number of bytes that were read before the error
+	Length int
+	Err    error
+}
+
+func (e *ManifestReadError) Error() string {
+	return fmt.Sprintf("error reading manifest from blobstore key %s at version %s after %d bytes: %s", e.Key, e.Version, e.Length, e.Err.Error())
+}
+
+func (e *ManifestReadError) Unwrap() error {
+	return e.Err
+}
+
 type blobstoreManifest struct {
 	name string
 	bs   blobstore.Blobstore
+	// checksums causes a checksum of the manifest to be written by each update, and verified by each read
+	checksums bool
 }
 
 func (bsm blobstoreManifest) Name() string {
 	return bsm.name
 }
 
-func manifestVersionAndContents(ctx context.Context, bs blobstore.Blobstore) (string, manifestContents, error) {
+// manifestVersionAndContents fetches and parses the manifest in |bs|. When |verifyChecksum| is true and a checksum was
+// stored for the version that was fetched, the manifest must match it. Manifests written without a checksum, or whose
+// checksum was not updated along with them, are not verified.
+func manifestVersionAndContents(ctx context.Context, bs blobstore.Blobstore, verifyChecksum bool) (string, manifestContents, error) {
 	reader, ver, err := bs.Get(ctx, manifestFile, blobstore.AllRange)
 
 	if err != nil {
@@ -42,15 +80,66 @@ func manifestVersionAndContents(ctx context.Context, bs blobstore.Blobstore) (st
 	}
 
 	defer reader.Close()
-	contents, err := parseManifest(reader)
+	data, err := io.ReadAll(reader)
 
 	if err != nil {
-		return "", manifestContents{}, err
+		return "", manifestContents{}, &ManifestReadError{Key: manifestFile, Version: ver, Length: len(data), Err: err}
+	}
+
+	if verifyChecksum {
+		err = verifyManifestChecksum(ctx, bs, ver, data)
+
+		if err != nil {
+			return "", manifestContents{}, &ManifestReadError{Key: manifestFile, Version: ver, Length: len(data), Err: err}
+		}
+	}
+
+	contents, err := parseManifest(bytes.NewReader(data))
+
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = ErrCorruptManifest
+		}
+		return "", manifestContents{}, &ManifestReadError{Key: manifestFile, Version: ver, Length: len(data), Err: err}
 	}
 
 	return ver, contents, nil
 }
 
+// manifestChecksum returns the contents of the checksum object for the manifest |data| written at version |ver|.
+func manifestChecksum(ver string, data []byte) string {
+	sum := sha512.Sum512(data)
+	return strconv.Itoa(len(data)) + ":" + hex.EncodeToString(sum[:]) + ":" + ver
+}
+
+// verifyManifestChecksum checks the manifest |data| read at version |ver| against the checksum stored in |bs|.
+func verifyManifestChecksum(ctx context.Context, bs blobstore.Blobstore, ver string, data []byte) error {
+	stored, _, err := blobstore.GetBytes(ctx, bs, manifestChecksumFile, blobstore.AllRange)
+
+	if blobstore.IsNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	fields := strings.SplitN(string(stored), ":", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("%w: invalid checksum object %s", ErrManifestChecksumMismatch, manifestChecksumFile)
+	}
+
+	// The checksum is written after the manifest, so it may belong to another version after a crash or a race between
+	// writers. There is nothing to verify against in that case.
+	if fields[2] != ver {
+		return nil
+	}
+
+	if expected := manifestChecksum(ver, data); string(stored) != expected {
+		return fmt.Errorf("%w: expected %s bytes with checksum %s", ErrManifestChecksumMismatch, fields[0], fields[1])
+	}
+
+	return nil
+}
+
 // ParseIfExists looks for a manifest in the specified blobstore.  If one exists
 // will return true and the contents, else false and nil
 func (bsm blobstoreManifest) ParseIfExists(ctx context.Context, stats *Stats, readHook func() error) (bool, manifestContents, error) {
@@ -58,7 +147,7 @@ func (bsm blobstoreManifest) ParseIfExists(ctx context.Context, stats *Stats, re
 		panic("Read hooks not supported")
 	}
 
-	_, contents, err := manifestVersionAndContents(ctx, bsm.bs)
+	_, contents, err := manifestVersionAndContents(ctx, bsm.bs, bsm.checksums)
 
 	if err != nil {
 		if blobstore.IsNotFoundError(err) {
@@ -74,33 +163,93 @@ func (bsm blobstoreManifest) ParseIfExists(ctx context.Context, stats *Stats, re
 
 // Update updates the contents of the manifest in the blobstore
 func (bsm blobstoreManifest) Update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (manifestContents, error) {
-	if writeHook != nil {
-		panic("Write hooks not supported")
+	checker := func(upstream, contents manifestContents) error {
+		if contents.gcGen != upstream.gcGen {
+			return chunks.ErrGCGenerationExpired
+		}
+		return nil
+	}
+
+	return updateBSWithChecker(ctx, bsm.bs, bsm.checksums, checker, lastLock, newContents, writeHook)
+}
+
+// UpdateGCGen updates the contents of the manifest in the blobstore with a new garbage collection generation. The
+// root must not change, and any table files that are absent from |newContents| are no longer referenced.
+func (bsm blobstoreManifest) UpdateGCGen(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (manifestContents, error) {
+	checker := func(upstream, contents manifestContents) error {
+		if contents.gcGen == upstream.gcGen {
+			return errors.New("UpdateGCGen() must update the garbage collection generation")
+		}
+
+		if contents.root != upstream.root {
+			return errors.New("UpdateGCGen() cannot update the root")
+		}
+		return nil
 	}
 
-	ver, contents, err := manifestVersionAndContents(ctx, bsm.bs)
+	return updateBSWithChecker(ctx, bsm.bs, bsm.checksums, checker, lastLock, newContents, writeHook)
+}
+
+// updateBSWithChecker writes |newContents| to the manifest in |bs| if |lastLock| matches the lock of the current
+// manifest, and |validate| accepts the change. The write uses CheckAndPut against the version that was read, so a
+// concurrent writer causes the current manifest to be read again and returned instead. If writeHook is non-nil, it is
+// invoked between reading the current manifest and writing the new one, which allows for testing of race conditions.
+// When |checksums| is true, the current manifest must match its checksum before anything is written, and a checksum
+// of the new manifest is stored after it is written.
+func updateBSWithChecker(ctx context.Context, bs blobstore.Blobstore, checksums bool, validate manifestChecker, lastLock addr, newContents manifestContents, writeHook func() error) (manifestContents, error) {
+	ver, contents, err := manifestVersionAndContents(ctx, bs, checksums)
 
 	if err != nil && !blobstore.IsNotFoundError(err) {
 		return manifestContents{}, err
 	}
 
-	if contents.lock == lastLock {
-		buffer := bytes.NewBuffer(make([]byte, 64*1024)[:0])
-		err := writeManifest(buffer, newContents)
+	if writeHook != nil {
+		err = writeHook()
 
 		if err != nil {
 			return manifestContents{}, err
 		}
+	}
 
-		_, err = bsm.bs.CheckAndPut(ctx, ver, manifestFile, buffer)
+	if contents.lock != lastLock {
+		return contents, nil
+	}
 
-		if err != nil {
-			if !blobstore.IsCheckAndPutError(err) {
+	// this is where we assert that gcGen is correct
+	err = validate(contents, newContents)
+
+	if err != nil {
+		return manifestContents{}, err
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 64*1024)[:0])
+	err = writeManifest(buffer, newContents)
+
+	if err != nil {
+		return manifestContents{}, err
+	}
+
+	data := buffer.Bytes()
+	newVer, err := bs.CheckAndPut(ctx, ver, manifestFile, bytes.NewReader(data))
+
+	if err == nil {
+		if checksums {
+			_, err = bs.Put(ctx, manifestChecksumFile, strings.NewReader(manifestChecksum(newVer, data)))
+
+			if err != nil {
 				return manifestContents{}, err
 			}
-		} else {
-			return newContents, nil
 		}
+		return newContents, nil
+	} else if !blobstore.IsCheckAndPutError(err) {
+		return manifestContents{}, err
+	}
+
+	// Another writer updated the manifest after we read it, so the manifest that was read is stale
+	_, contents, err = manifestVersionAndContents(ctx, bs, checksums)
+
+	if err != nil {
+		return manifestContents{}, err
 	}
 
 	return contents, nil
@@ -0,0 +1,309 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBlobstoreManifestUpdateGCGen(t *testing.T) {
+	ctx := context.Background()
+	bsm := blobstoreManifest{"manifest", blobstore.NewInMemoryBlobstore(), false}
+	stats := &Stats{}
+
+	root := hash.Of([]byte("root"))
+	contents := manifestContents{
+		nbfVers: constants.NomsVersion,
+		lock:    computeAddr([]byte("locker")),
+		root:    root,
+		specs:   []tableSpec{{computeAddr([]byte("a")), 3}, {computeAddr([]byte("b")), 2}, {computeAddr([]byte("c")), 1}},
+	}
+	upstream, err := bsm.Update(ctx, addr{}, contents, stats, nil)
+	require.NoError(t, err)
+	require.Equal(t, contents.lock, upstream.lock)
+
+	// The compacted table replaces every table from the old generation
+	gcLock := computeAddr([]byte("gc locker"))
+	gcContents := manifestContents{
+		nbfVers: constants.NomsVersion,
+		lock:    gcLock,
+		root:    root,
+		gcGen:   gcLock,
+		specs:   []tableSpec{{computeAddr([]byte("compacted")), 6}},
+	}
+
+	_, err = bsm.UpdateGCGen(ctx, contents.lock, manifestContents{nbfVers: constants.NomsVersion, lock: gcLock, root: root}, stats, nil)
+	assert.Error(t, err)
+	_, err = bsm.UpdateGCGen(ctx, contents.lock, manifestContents{nbfVers: constants.NomsVersion, lock: gcLock, root: hash.Of([]byte("other")), gcGen: gcLock}, stats, nil)
+	assert.Error(t, err)
+
+	// A stale lock returns the current contents without writing
+	upstream, err = bsm.UpdateGCGen(ctx, computeAddr([]byte("stale")), gcContents, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, contents.lock, upstream.lock)
+	assert.Equal(t, contents.specs, upstream.specs)
+
+	upstream, err = bsm.UpdateGCGen(ctx, contents.lock, gcContents, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, gcContents, upstream)
+	exists, upstream, err := bsm.ParseIfExists(ctx, stats, nil)
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Equal(t, gcLock, upstream.gcGen)
+	assert.Equal(t, gcContents.specs, upstream.specs)
+
+	// Writers from the previous generation may no longer update the manifest
+	_, err = bsm.Update(ctx, gcLock, contents, stats, nil)
+	assert.ErrorIs(t, err, chunks.ErrGCGenerationExpired)
+}
+
+func TestBlobstoreManifestUpdateConflict(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	bsm := blobstoreManifest{"manifest", bs, false}
+	stats := &Stats{}
+
+	root := hash.Of([]byte("root"))
+	contents := manifestContents{nbfVers: constants.NomsVersion, lock: computeAddr([]byte("locker")), root: root}
+	_, err := bsm.Update(ctx, addr{}, contents, stats, nil)
+	require.NoError(t, err)
+
+	// Another process writes the manifest after it has been read, so the write must not clobber it
+	jerkLock := computeAddr([]byte("jerk"))
+	tableName := computeAddr([]byte("table1"))
+	gcLock := computeAddr([]byte("gc locker"))
+	upstream, err := bsm.UpdateGCGen(ctx, contents.lock, manifestContents{nbfVers: constants.NomsVersion, lock: gcLock, root: root, gcGen: gcLock}, stats, func() error {
+		m := strings.Join([]string{StorageVersion, constants.NomsVersion, jerkLock.String(), root.String(), addr{}.String(), tableName.String(), "1"}, ":")
+		_, err := bs.Put(ctx, manifestFile, bytes.NewBufferString(m))
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, jerkLock, upstream.lock)
+	assert.Equal(t, []tableSpec{{tableName, 1}}, upstream.specs)
+}
+
+func TestBlobstoreStoreSwapTables(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	q := NewUnlimitedMemQuotaProvider()
+	st, err := NewBSStore(ctx, types.Format_Default.VersionString(), bs, defaultMemTableSize, q)
+	require.NoError(t, err)
+	// Each commit persists its chunk to a new table file
+	var chunkHashes []hash.Hash
+	last := hash.Hash{}
+	for i := uint32(0); i < 3; i++ {
+		c := makeChunk(i)
+		require.NoError(t, st.Put(ctx, c))
+		ok, err := st.Commit(ctx, c.Hash(), last)
+		require.NoError(t, err)
+		require.True(t, ok)
+		chunkHashes = append(chunkHashes, c.Hash())
+		last = c.Hash()
+	}
+	_, sources, _, err := st.Sources(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 3)
+
+	// Simulate a garbage collection that keeps a single table file from the previous generation
+	kept := st.upstream.specs[:1]
+	require.NoError(t, st.swapTables(ctx, kept))
+	require.NoError(t, st.Close())
+
+	st, err = NewBSStore(ctx, types.Format_Default.VersionString(), bs, defaultMemTableSize, q)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, st.Close())
+	}()
+	assert.Equal(t, st.upstream.lock, st.upstream.gcGen)
+	_, sources, _, err = st.Sources(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, kept[0].name.String(), sources[0].FileID())
+	// Only the chunk from the kept table file remains
+	present := 0
+	for _, h := range chunkHashes {
+		ok, err := st.Has(ctx, h)
+		require.NoError(t, err)
+		if ok {
+			present++
+		}
+	}
+	assert.Equal(t, 1, present)
+}
+
+// corruptingBlobstore truncates the bodies of manifests that are fetched from it, and counts the writes of manifests.
+type corruptingBlobstore struct {
+	blobstore.Blobstore
+	truncateTo   int
+	manifestPuts int
+}
+
+func (bs *corruptingBlobstore) Get(ctx context.Context, key string, br blobstore.BlobRange) (io.ReadCloser, string, error) {
+	rd, ver, err := bs.Blobstore.Get(ctx, key, br)
+	if err != nil || key != manifestFile || bs.truncateTo < 0 {
+		return rd, ver, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rd, int64(bs.truncateTo)), rd}, ver, nil
+}
+
+func (bs *corruptingBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, reader io.Reader) (string, error) {
+	if key == manifestFile {
+		bs.manifestPuts++
+	}
+	return bs.Blobstore.CheckAndPut(ctx, expectedVersion, key, reader)
+}
+
+func TestBlobstoreManifestReadErrors(t *testing.T) {
+	ctx := context.Background()
+	bs := &corruptingBlobstore{Blobstore: blobstore.NewInMemoryBlobstore(), truncateTo: -1}
+	bsm := blobstoreManifest{"manifest", bs, false}
+	stats := &Stats{}
+
+	contents := manifestContents{
+		nbfVers: constants.NomsVersion,
+		lock:    computeAddr([]byte("locker")),
+		root:    hash.Of([]byte("root")),
+		specs:   []tableSpec{{computeAddr([]byte("a")), 3}},
+	}
+	_, err := bsm.Update(ctx, addr{}, contents, stats, nil)
+	require.NoError(t, err)
+	_, ver, err := bs.Blobstore.Get(ctx, manifestFile, blobstore.AllRange)
+	require.NoError(t, err)
+
+	for _, truncateTo := range []int{0, 3, 40} {
+		bs.truncateTo = truncateTo
+		_, _, err = bsm.ParseIfExists(ctx, stats, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrCorruptManifest)
+		var readErr *ManifestReadError
+		require.True(t, errors.As(err, &readErr))
+		assert.Equal(t, ManifestReadError{Key: manifestFile, Version: ver, Length: truncateTo, Err: ErrCorruptManifest}, *readErr)
+		assert.Contains(t, err.Error(), ver)
+	}
+
+	// Update fails without writing when the current manifest cannot be parsed
+	bs.truncateTo = 40
+	bs.manifestPuts = 0
+	_, err = bsm.Update(ctx, contents.lock, manifestContents{nbfVers: constants.NomsVersion, lock: computeAddr([]byte("next")), root: contents.root}, stats, nil)
+	assert.ErrorIs(t, err, ErrCorruptManifest)
+	assert.Equal(t, 0, bs.manifestPuts)
+}
+
+func TestBlobstoreManifestChecksums(t *testing.T) {
+	ctx := context.Background()
+	bs := &corruptingBlobstore{Blobstore: blobstore.NewInMemoryBlobstore(), truncateTo: -1}
+	bsm := blobstoreManifest{"manifest", bs, true}
+	stats := &Stats{}
+
+	root := hash.Of([]byte("root"))
+	tableName := computeAddr([]byte("a"))
+	contents := manifestContents{
+		nbfVers: constants.NomsVersion,
+		lock:    computeAddr([]byte("locker")),
+		root:    root,
+		specs:   []tableSpec{{tableName, 31}},
+	}
+	_, err := bsm.Update(ctx, addr{}, contents, stats, nil)
+	require.NoError(t, err)
+	exists, upstream, err := bsm.ParseIfExists(ctx, stats, nil)
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Equal(t, contents.lock, upstream.lock)
+
+	// Dropping the trailing digit of the chunk count leaves a manifest that parses, but does not match its checksum
+	m, ver, err := blobstore.GetBytes(ctx, bs.Blobstore, manifestFile, blobstore.AllRange)
+	require.NoError(t, err)
+	bs.truncateTo = len(m) - 1
+	_, _, err = bsm.ParseIfExists(ctx, stats, nil)
+	assert.ErrorIs(t, err, ErrManifestChecksumMismatch)
+	var readErr *ManifestReadError
+	require.True(t, errors.As(err, &readErr))
+	assert.Equal(t, ver, readErr.Version)
+	assert.Equal(t, len(m)-1, readErr.Length)
+
+	// The truncated manifest is only accepted when checksums aren't verified
+	_, upstream, err = blobstoreManifest{"manifest", bs, false}.ParseIfExists(ctx, stats, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, contents.specs, upstream.specs)
+
+	bs.manifestPuts = 0
+	next := manifestContents{nbfVers: constants.NomsVersion, lock: computeAddr([]byte("next")), root: root}
+	_, err = bsm.Update(ctx, contents.lock, next, stats, nil)
+	assert.ErrorIs(t, err, ErrManifestChecksumMismatch)
+	assert.Equal(t, 0, bs.manifestPuts)
+
+	// A manifest written by another process replaces the checksum with one that doesn't match its version, so it can't
+	// be verified and is accepted
+	bs.truncateTo = -1
+	m = []byte(strings.Join([]string{StorageVersion, constants.NomsVersion, next.lock.String(), root.String(), addr{}.String(), tableName.String(), "1"}, ":"))
+	_, err = bs.Put(ctx, manifestFile, bytes.NewReader(m))
+	require.NoError(t, err)
+	_, upstream, err = bsm.ParseIfExists(ctx, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, next.lock, upstream.lock)
+
+	// A checksum that is stored for the current version must match it
+	_, ver, err = bs.Blobstore.Get(ctx, manifestFile, blobstore.AllRange)
+	require.NoError(t, err)
+	_, err = blobstore.PutBytes(ctx, bs, manifestChecksumFile, []byte(manifestChecksum(ver, []byte("other manifest"))))
+	require.NoError(t, err)
+	_, _, err = bsm.ParseIfExists(ctx, stats, nil)
+	assert.ErrorIs(t, err, ErrManifestChecksumMismatch)
+}
+
+func TestBlobstoreStoreWithManifestChecksums(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+	st, err := NewBSStoreWithManifestChecksums(ctx, types.Format_Default.VersionString(), bs, defaultMemTableSize, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, st.Close())
+	}()
+
+	c := makeChunk(0)
+	require.NoError(t, st.Put(ctx, c))
+	ok, err := st.Commit(ctx, c.Hash(), hash.Hash{})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	m, ver, err := blobstore.GetBytes(ctx, bs, manifestFile, blobstore.AllRange)
+	require.NoError(t, err)
+	checksum, _, err := blobstore.GetBytes(ctx, bs, manifestChecksumFile, blobstore.AllRange)
+	require.NoError(t, err)
+	assert.Equal(t, manifestChecksum(ver, m), string(checksum))
+
+	// The checksum is not an orphan
+	report, err := CheckBlobstoreConsistency(ctx, bs)
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Orphaned)
+}
@@ -27,6 +27,7 @@ import (
 
 var _ chunks.ChunkStore = (*GenerationalNBS)(nil)
 var _ chunks.GenerationalCS = (*GenerationalNBS)(nil)
+var _ chunks.IterableChunkStore = (*GenerationalNBS)(nil)
 var _ TableFileStore = (*GenerationalNBS)(nil)
 
 type GenerationalNBS struct {
@@ -134,6 +135,15 @@ func (gcs *GenerationalNBS) Has(ctx context.Context, h hash.Hash) (bool, error)
 	return gcs.newGen.Has(ctx, h)
 }
 
+// IterateAllAddresses implements chunks.IterableChunkStore. Chunks that have
+// been copied to the old gen may be visited in both generations.
+func (gcs *GenerationalNBS) IterateAllAddresses(ctx context.Context, cb func(h hash.Hash) error) error {
+	if err := gcs.oldGen.IterateAllAddresses(ctx, cb); err != nil {
+		return err
+	}
+	return gcs.newGen.IterateAllAddresses(ctx, cb)
+}
+
 // Returns a new HashSet containing any members of |hashes| that are
 // absent from the store.
 func (gcs *GenerationalNBS) HasMany(ctx context.Context, hashes hash.HashSet) (absent hash.HashSet, err error) {
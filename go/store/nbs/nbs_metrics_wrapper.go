@@ -76,6 +76,11 @@ func (nbsMW *NBSMetricWrapper) MarkAndSweepChunks(ctx context.Context, last hash
 }
 
 // PruneTableFiles deletes old table files that are no longer referenced in the manifest.
+// IterateAllAddresses implements chunks.IterableChunkStore.
+func (nbsMW *NBSMetricWrapper) IterateAllAddresses(ctx context.Context, cb func(h hash.Hash) error) error {
+	return nbsMW.nbs.IterateAllAddresses(ctx, cb)
+}
+
 func (nbsMW *NBSMetricWrapper) PruneTableFiles(ctx context.Context) error {
 	return nbsMW.nbs.PruneTableFiles(ctx)
 }
@@ -110,6 +110,7 @@ type NomsBlockStore struct {
 
 var _ TableFileStore = &NomsBlockStore{}
 var _ chunks.ChunkStoreGarbageCollector = &NomsBlockStore{}
+var _ chunks.IterableChunkStore = &NomsBlockStore{}
 
 type Range struct {
 	Offset uint64
@@ -518,7 +519,18 @@ func NewGCSStore(ctx context.Context, nbfVerStr string, bucketName, path string,
 func NewBSStore(ctx context.Context, nbfVerStr string, bs blobstore.Blobstore, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 
-	mm := makeManifestManager(blobstoreManifest{"manifest", bs})
+	mm := makeManifestManager(blobstoreManifest{"manifest", bs, false})
+
+	p := &blobstorePersister{bs, s3BlockSize, q}
+	return newNomsBlockStore(ctx, nbfVerStr, mm, p, q, inlineConjoiner{defaultMaxTables}, memTableSize)
+}
+
+// NewBSStoreWithManifestChecksums returns an nbs implementation backed by a Blobstore, which stores a checksum with
+// each manifest that it writes, and fails reads of a manifest that does not match its checksum.
+func NewBSStoreWithManifestChecksums(ctx context.Context, nbfVerStr string, bs blobstore.Blobstore, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	cacheOnce.Do(makeGlobalCaches)
+
+	mm := makeManifestManager(blobstoreManifest{"manifest", bs, true})
 
 	p := &blobstorePersister{bs, s3BlockSize, q}
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, q, inlineConjoiner{defaultMaxTables}, memTableSize)
@@ -828,6 +840,34 @@ func (nbs *NomsBlockStore) Count() (uint32, error) {
 	return count + tablesCount, nil
 }
 
+// IterateAllAddresses implements chunks.IterableChunkStore. Chunks that have been put but not yet persisted are
+// included.
+func (nbs *NomsBlockStore) IterateAllAddresses(ctx context.Context, cb func(h hash.Hash) error) error {
+	var pending []addr
+	tables := func() tableSet {
+		nbs.mu.RLock()
+		defer nbs.mu.RUnlock()
+		if nbs.mt != nil {
+			for a := range nbs.mt.chunks {
+				pending = append(pending, a)
+			}
+		}
+		return nbs.tables
+	}()
+
+	for _, a := range pending {
+		if err := cb(hash.Hash(a)); err != nil {
+			return err
+		}
+	}
+	return tables.iterateAllAddresses(func(a addr) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return cb(hash.Hash(a))
+	})
+}
+
 func (nbs *NomsBlockStore) Has(ctx context.Context, h hash.Hash) (bool, error) {
 	t1 := time.Now()
 	defer func() {
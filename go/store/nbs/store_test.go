@@ -270,6 +270,43 @@ func makeChunkSet(N, size int) (s map[hash.Hash]chunks.Chunk) {
 	return
 }
 
+func TestNBSIterateAllAddresses(t *testing.T) {
+	ctx := context.Background()
+	st, _, _ := makeTestLocalStore(t, 8)
+	defer func() {
+		require.NoError(t, st.Close())
+	}()
+
+	persisted := makeChunkSet(64, 64)
+	for _, c := range persisted {
+		require.NoError(t, st.Put(ctx, c))
+	}
+	r, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, r, r)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	pending := makeChunkSet(16, 64)
+	for _, c := range pending {
+		require.NoError(t, st.Put(ctx, c))
+	}
+
+	seen := make(hash.HashSet)
+	err = st.IterateAllAddresses(ctx, func(h hash.Hash) error {
+		seen.Insert(h)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, len(persisted)+len(pending), seen.Size())
+	for h := range persisted {
+		assert.True(t, seen.Has(h))
+	}
+	for h := range pending {
+		assert.True(t, seen.Has(h))
+	}
+}
+
 func TestNBSCopyGC(t *testing.T) {
 	ctx := context.Background()
 	st, _, _ := makeTestLocalStore(t, 8)
@@ -297,6 +297,33 @@ func (ts tableSet) uncompressedLen() (uint64, error) {
 	return novelCount + upCount, nil
 }
 
+// iterateAllAddresses calls |cb| with the address of every chunk in the tables of the set.
+func (ts tableSet) iterateAllAddresses(cb func(a addr) error) error {
+	f := func(css chunkSources) error {
+		for _, cs := range css {
+			index, err := cs.index()
+			if err != nil {
+				return err
+			}
+			for i := uint32(0); i < index.ChunkCount(); i++ {
+				var a addr
+				if _, err = index.IndexEntry(i, &a); err != nil {
+					return err
+				}
+				if err = cb(a); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := f(ts.novel); err != nil {
+		return err
+	}
+	return f(ts.upstream)
+}
+
 func (ts tableSet) physicalLen() (uint64, error) {
 	f := func(css chunkSources) (data uint64, err error) {
 		for _, haver := range css {
@@ -96,6 +96,14 @@ func (c CommitClosure) IterAllReverse(ctx context.Context) (CommitClosureIter, e
 	return c.closure.IterAllReverse(ctx)
 }
 
+// IterHeight returns an iterator over the commits in the closure with the given height.
+func (c CommitClosure) IterHeight(ctx context.Context, height uint64) (CommitClosureIter, error) {
+	pool := c.closure.NodeStore.Pool()
+	start := NewCommitClosureKey(pool, height, hash.Hash{})
+	stop := NewCommitClosureKey(pool, height+1, hash.Hash{})
+	return c.closure.IterKeyRange(ctx, start, stop)
+}
+
 func DecodeCommitClosureKey(key []byte) (height uint64, addr hash.Hash) {
 	height = binary.LittleEndian.Uint64(key)
 	addr = hash.New(key[8:])
@@ -105,6 +105,36 @@ func TestCommitClosure(t *testing.T) {
 		assert.Equal(t, 2, ccc)
 	})
 
+	t.Run("IterHeight", func(t *testing.T) {
+		cc, err := NewEmptyCommitClosure(ns)
+		require.NoError(t, err)
+		e := cc.Editor()
+		for i := 0; i < 4096; i++ {
+			err := e.Add(ctx, NewCommitClosureKey(ns.Pool(), uint64(i/2), hash.Parse(fmt.Sprintf("%0.32d", i))))
+			require.NoError(t, err)
+		}
+		cc, err = e.Flush(ctx)
+		require.NoError(t, err)
+
+		for _, height := range []uint64{0, 1000, 2047} {
+			i, err := cc.IterHeight(ctx, height)
+			require.NoError(t, err)
+			for j := 0; j < 2; j++ {
+				k, _, err := i.Next(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, height, k.Height())
+				assert.Equal(t, hash.Parse(fmt.Sprintf("%0.32d", int(height)*2+j)), k.Addr())
+			}
+			_, _, err = i.Next(ctx)
+			assert.True(t, errors.Is(err, io.EOF))
+		}
+
+		i, err := cc.IterHeight(ctx, 2048)
+		require.NoError(t, err)
+		_, _, err = i.Next(ctx)
+		assert.True(t, errors.Is(err, io.EOF))
+	})
+
 	t.Run("Diff", func(t *testing.T) {
 		ccl, err := NewEmptyCommitClosure(ns)
 		require.NoError(t, err)
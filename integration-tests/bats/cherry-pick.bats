@@ -408,3 +408,58 @@ teardown() {
     [ "$status" -eq "1" ]
     [[ "$output" =~ "table schema does not match in current HEAD and cherry-pick commit" ]] || false
 }
+
+@test "cherry-pick: --no-commit stages the changes without committing" {
+    dolt checkout main
+    run dolt cherry-pick -n branch1
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "3,c" ]] || false
+
+    run dolt status
+    [[ "$output" =~ "Changes to be committed" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "Created table" ]] || false
+}
+
+@test "cherry-pick: dolt_cherry_pick with a range of commits" {
+    dolt checkout main
+    run dolt sql -q "CALL dolt_cherry_pick('main..branch1')"
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,a" ]] || false
+    [[ "$output" =~ "2,b" ]] || false
+    [[ "$output" =~ "3,c" ]] || false
+
+    run dolt sql -q "SELECT message FROM dolt_log LIMIT 4" -r csv
+    [ "$status" -eq "0" ]
+    [[ "${lines[1]}" =~ "Inserted 3" ]] || false
+    [[ "${lines[2]}" =~ "Inserted 2" ]] || false
+    [[ "${lines[3]}" =~ "Inserted 1" ]] || false
+    [[ "${lines[4]}" =~ "Created table" ]] || false
+}
+
+@test "cherry-pick: dolt_cherry_pick leaves conflicts to be resolved" {
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (3, 'x')"
+    dolt commit -am "Inserted 3 on main"
+
+    run dolt sql -q "SET @@dolt_allow_commit_conflicts = 1; CALL dolt_cherry_pick('main..branch1')"
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT our_v, their_v FROM dolt_conflicts_test" -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "x,c" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "Inserted 2" ]] || false
+
+    dolt conflicts resolve --theirs test
+    dolt commit -am "Inserted 3"
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "3,c" ]] || false
+}
@@ -0,0 +1,62 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test(pk BIGINT PRIMARY KEY)"
+    dolt add -A
+    dolt commit -m "Created table"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "fsck: dangling commits of deleted branches" {
+    dolt checkout -b feature
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "lost work"
+    dolt checkout main
+    dolt branch -D feature
+
+    run dolt fsck --dangling
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "dangling commit" ]] || false
+    [[ "$output" =~ "lost work" ]] || false
+    [[ "$output" =~ "ago)" ]] || false
+    [[ ! "$output" =~ "Created table" ]] || false
+
+    run dolt sql -q "SELECT message FROM dolt_dangling_commits" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "lost work" ]] || false
+
+    hash=$(dolt sql -q "SELECT commit_hash FROM dolt_dangling_commits" -r=csv | tail -n 1)
+    dolt branch recovered "$hash"
+    run dolt fsck --dangling
+    [ "$status" -eq "0" ]
+    [ "$output" = "" ]
+}
+
+@test "fsck: dangling commits in the reflog are kept by gc" {
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "reset away"
+    dolt reset --hard HEAD~1
+
+    run dolt fsck --dangling
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "reset away" ]] || false
+
+    dolt gc
+    run dolt fsck --dangling
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "reset away" ]] || false
+    [[ ! "$output" =~ "reflog" ]] || false
+}
+
+@test "fsck: requires --dangling" {
+    run dolt fsck
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "usage" ]] || false
+}
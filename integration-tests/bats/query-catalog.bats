@@ -176,3 +176,47 @@ EOF
     [ "$status" -eq 0 ]
     [[ "$output" =~ "$EXPECTED" ]] || false
 }
+
+@test "query-catalog: saved queries of other branches" {
+    dolt sql -q "select pk from one_pk order by pk" -s name1
+    dolt add .
+    dolt commit -m "saved query"
+    dolt checkout -b other
+    dolt sql -q "select count(*) from one_pk" -s name1
+    dolt commit -am "changed saved query"
+    dolt checkout main
+
+    run dolt sql -r csv -x name1 --branch other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "count(*)" ]] || false
+    [[ "$output" =~ "4" ]] || false
+
+    run dolt sql --list-saved -r csv --branch other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "select count(*) from one_pk" ]] || false
+
+    run dolt sql --list-saved -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "select pk from one_pk order by pk" ]] || false
+
+    run dolt sql -r csv -x name1 --branch missing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown branch 'missing'" ]] || false
+
+    run dolt sql -q "select 1" --branch other
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--branch is only used with" ]] || false
+}
+
+@test "query-catalog: dolt_exec_saved" {
+    dolt sql -q "select pk from one_pk order by pk" -s name1
+
+    run dolt sql -r csv -q "call dolt_exec_saved('name1')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "pk" ]] || false
+    [[ "$output" =~ "3" ]] || false
+
+    run dolt sql -q "call dolt_exec_saved('missing')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Query 'missing' not found" ]] || false
+}
@@ -367,3 +367,73 @@ SQL
     run dolt log -n 1
     [[ "$output" =~ "Author: john doe <johndoe@gmail.com>" ]] || false
 }
+
+@test "revert: HEAD & HEAD~1 makes a commit for each revert" {
+    dolt revert HEAD HEAD~1
+    run dolt log --oneline -n 3
+    [ "$status" -eq "0" ]
+    [[ "${lines[0]}" =~ 'Revert "Inserted 2"' ]] || false
+    [[ "${lines[1]}" =~ 'Revert "Inserted 3"' ]] || false
+    [[ "${lines[2]}" =~ "Inserted 3" ]] || false
+}
+
+@test "revert: --squash reverts in a single commit" {
+    dolt revert --squash HEAD HEAD~1
+    run dolt log --oneline -n 2
+    [ "$status" -eq "0" ]
+    [[ "${lines[0]}" =~ 'Revert "Inserted 3" and "Inserted 2"' ]] || false
+    [[ "${lines[1]}" =~ "Inserted 3" ]] || false
+}
+
+@test "revert: range" {
+    dolt revert HEAD~2..HEAD
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "1,1" ]] || false
+    [[ "${#lines[@]}" = "2" ]] || false
+
+    run dolt log --oneline -n 2
+    [ "$status" -eq "0" ]
+    [[ "${lines[0]}" =~ 'Revert "Inserted 2"' ]] || false
+    [[ "${lines[1]}" =~ 'Revert "Inserted 3"' ]] || false
+}
+
+@test "revert: merge commit" {
+    dolt checkout -b other HEAD~1
+    dolt sql -q "INSERT INTO test VALUES (10, 10)"
+    dolt commit -am "Inserted 10"
+    dolt checkout main
+    dolt merge --no-ff -m "Merged other" other
+
+    run dolt revert HEAD
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "-m" ]] || false
+
+    dolt revert -m 1 HEAD
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ ! "$output" =~ "10,10" ]] || false
+    [[ "${#lines[@]}" = "4" ]] || false
+}
+
+@test "revert: Stored Procedure merge commit and range" {
+    dolt checkout -b other HEAD~1
+    dolt sql -q "INSERT INTO test VALUES (10, 10)"
+    dolt commit -am "Inserted 10"
+    dolt checkout main
+    dolt merge --no-ff -m "Merged other" other
+
+    run dolt sql -q "CALL DOLT_REVERT('HEAD')"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "-m" ]] || false
+
+    dolt sql -q "CALL DOLT_REVERT('-m', '1', 'HEAD')"
+    dolt sql -q "CALL DOLT_REVERT('--squash', 'HEAD~4..HEAD~2')"
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "1,1" ]] || false
+    [[ "${#lines[@]}" = "2" ]] || false
+
+    run dolt log --oneline -n 1
+    [[ "${lines[0]}" =~ 'Revert "Inserted 3" and "Inserted 2"' ]] || false
+}
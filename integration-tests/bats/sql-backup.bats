@@ -111,3 +111,52 @@ teardown() {
     run dolt sql -q "CALL dolt_backup('sync-url', 'https://dolthub.com/dolthub/backup')"
     [ "$status" -ne 0 ]
 }
+
+@test "sql-backup: dolt_backups shows backups and their schedules" {
+    mkdir the_backup
+    dolt backup add hostedapidb-0 file://./the_backup
+
+    run dolt sql -q "SELECT name, schedule, last_run_status FROM dolt_backups" -r=csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "hostedapidb-0,," ]] || false
+
+    dolt sql -q "CALL dolt_backup_schedule('hostedapidb-0', '0 2 * * *')"
+    run dolt sql -q "SELECT name, schedule, next_run_at IS NOT NULL FROM dolt_backups" -r=csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "hostedapidb-0,0 2 * * *,true" ]] || false
+
+    run dolt sql -q "CALL dolt_backup('sync', 'hostedapidb-0'); SELECT name, last_run_status, last_run_error FROM dolt_backups" -r=csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "hostedapidb-0,success," ]] || false
+
+    dolt sql -q "CALL dolt_backup_schedule('-d', 'hostedapidb-0')"
+    run dolt sql -q "SELECT name, schedule FROM dolt_backups" -r=csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "hostedapidb-0," ]] || false
+    [[ ! "$output" =~ "0 2 * * *" ]] || false
+}
+
+@test "sql-backup: dolt_backup_schedule fails for invalid schedules and unknown backups" {
+    mkdir the_backup
+    dolt backup add hostedapidb-0 file://./the_backup
+
+    run dolt sql -q "CALL dolt_backup_schedule('hostedapidb-0', '0 2 * *')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "invalid cron expression" ]] || false
+
+    run dolt sql -q "CALL dolt_backup_schedule('hostedapidb-1', '0 2 * * *')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown backup: 'hostedapidb-1'" ]] || false
+}
+
+@test "sql-backup: removing a backup removes its schedule" {
+    mkdir the_backup
+    dolt backup add hostedapidb-0 file://./the_backup
+    dolt sql -q "CALL dolt_backup_schedule('hostedapidb-0', '0 2 * * *')"
+    dolt backup remove hostedapidb-0
+
+    dolt backup add hostedapidb-0 file://./the_backup
+    run dolt sql -q "SELECT name, schedule FROM dolt_backups" -r=csv
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "0 2 * * *" ]] || false
+}
@@ -453,6 +453,64 @@ SQL
     [[ "$output" =~ 'error: no value for option `message' ]] || false
 }
 
+@test "sql-commit: CALL DOLT_COMMIT --amend replaces the last commit" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'add test')"
+    dolt sql -q "INSERT INTO test VALUES (3)"
+
+    run dolt sql -q "CALL DOLT_COMMIT('-a', '--amend', '-m', 'create and fill test')"
+    [ $status -eq 0 ]
+
+    run dolt log --oneline
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" =~ "create and fill test" ]] || false
+    [[ "${lines[1]}" =~ "Initialize data repository" ]] || false
+    [ "${#lines[@]}" -eq 2 ]
+
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt sql -q "SELECT COUNT(*) FROM test AS OF 'HEAD'" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "4" ]
+}
+
+@test "sql-commit: CALL DOLT_COMMIT --amend keeps the message and author when none are given" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'add test', '--author', 'John Doe <john@doe.com>')"
+
+    run dolt sql -q "CALL DOLT_COMMIT('--amend')"
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "add test" ]] || false
+    [[ "$output" =~ "John Doe <john@doe.com>" ]] || false
+}
+
+@test "sql-commit: CALL DOLT_COMMIT --amend can't amend the initial commit" {
+    dolt sql -q "CALL DOLT_RESET('--hard')"
+
+    run dolt sql -q "CALL DOLT_COMMIT('--amend', '-m', 'amended')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "the initial commit of a database can't be amended" ]] || false
+}
+
+@test "sql-commit: CALL DOLT_COMMIT --allow-empty commits without changes" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'add test')"
+
+    run dolt sql -q "CALL DOLT_COMMIT('-m', 'marker')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "nothing to commit" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'marker')"
+    [ $status -eq 0 ]
+
+    run dolt log --oneline
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" =~ "marker" ]] || false
+    [[ "${lines[1]}" =~ "add test" ]] || false
+}
+
 get_head_commit() {
     dolt log -n 1 | grep -m 1 commit | cut -c 13-44
 }
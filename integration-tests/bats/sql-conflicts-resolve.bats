@@ -154,4 +154,40 @@ teardown() {
     run dolt sql -q "select * from t"
     [ $status -eq 0 ]
     [[ $output =~ "main" ]] || false
-}
\ No newline at end of file
+}
+@test "sql-conflicts-resolve: schema conflicts from a merge are recorded and resolved" {
+    dolt sql -q "create table t (i int primary key, c int)"
+    dolt add .
+    dolt commit -am "init commit"
+    dolt checkout -b other
+    dolt sql -q "alter table t add constraint c0 check (c < 10)"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt sql -q "alter table t add constraint c1 check (c > 0)"
+    dolt commit -am "main commit"
+
+    dolt sql -q "set @@dolt_allow_commit_conflicts = 1; call dolt_merge('other')"
+
+    run dolt sql -q "select table_name, description from dolt_schema_conflicts" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "our check 'c1' and their check 'c0' both reference the same column(s)" ]] || false
+
+    run dolt sql -q "call dolt_commit('-am', 'merge other')"
+    [ $status -eq 1 ]
+    [[ $output =~ "unresolved schema conflicts" ]] || false
+
+    run dolt sql -q "call dolt_conflicts_resolve('--theirs', 't')"
+    [ $status -eq 0 ]
+    run dolt sql -q "select count(*) from dolt_schema_conflicts" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "0" ]] || false
+    run dolt sql -q "show create table t"
+    [ $status -eq 0 ]
+    [[ $output =~ "c0" ]] || false
+    [[ ! $output =~ "c1" ]] || false
+
+    dolt sql -q "call dolt_commit('-am', 'merge other')"
+    run dolt sql -q "select is_merging from dolt_merge_status" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "false" ]] || false
+}
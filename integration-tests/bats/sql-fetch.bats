@@ -483,3 +483,26 @@ teardown() {
     [ "$status" -eq 1 ]
     [[ "$output" =~ "invalid fetch spec: ''" ]] || false
 }
+
+@test "sql-fetch: dolt_log reports remote-tracking branches that have not been fetched" {
+    cd repo1
+    dolt push origin feature
+
+    cd ../repo2
+    run dolt sql -q "SELECT message FROM dolt_log('origin/feature')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "remote-tracking ref origin/feature has not been fetched" ]] || false
+    [[ "$output" =~ "code=unfetched_revision" ]] || false
+
+    run dolt sql -q "SELECT message FROM dolt_log('upstream/feature')"
+    [ "$status" -eq 1 ]
+    [[ ! "$output" =~ "unfetched_revision" ]] || false
+
+    run dolt sql -q "SELECT message FROM dolt_log('--fetch', 'origin', 'origin/feature') LIMIT 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Second commit" ]] || false
+
+    run dolt sql -q "SELECT message FROM dolt_log('origin/feature') LIMIT 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Second commit" ]] || false
+}
@@ -1752,6 +1752,32 @@ SQL
     [[ "$output" =~ "$regex" ]] || false
 }
 
+@test "sql-merge: CALL DOLT_MERGE with a merge strategy resolves conflicts" {
+    dolt sql <<SQL
+CREATE TABLE t (pk int primary key, c int);
+INSERT INTO t VALUES (1, 1);
+CALL DOLT_COMMIT('-Am', 'create t');
+CALL DOLT_BRANCH('feature-branch');
+UPDATE t SET c = 10;
+CALL DOLT_COMMIT('-am', 'main change');
+CALL DOLT_CHECKOUT('feature-branch');
+UPDATE t SET c = 20;
+CALL DOLT_COMMIT('-am', 'feature change');
+SQL
+
+    run dolt sql -q "CALL DOLT_MERGE('feature-branch', '--strategy=theirs');" -r=csv
+    log_status_eq 0
+    [[ "$output" =~ "0,0" ]] || false
+
+    run dolt sql -q "SELECT c FROM t" -r=csv
+    log_status_eq 0
+    [[ "$output" =~ "20" ]] || false
+
+    run dolt status
+    log_status_eq 0
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
 get_head_commit() {
     dolt log -n 1 | grep -m 1 commit | cut -c 13-44
 }
@@ -0,0 +1,92 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, c int)"
+    dolt add .
+    dolt commit -am "create table"
+    dolt branch feature
+    dolt sql -q "INSERT INTO t VALUES (1, 1)"
+    dolt commit -am "main: add 1"
+    dolt checkout feature
+    dolt sql -q "INSERT INTO t VALUES (10, 10)"
+    dolt commit -am "add 10"
+    dolt sql -q "INSERT INTO t VALUES (11, 11)"
+    dolt commit -am "add 11"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "sql-rebase: dolt_rebase replays commits onto another branch" {
+    run dolt sql -q "CALL dolt_rebase('main')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/feature" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "add 11" ]] || false
+    [[ "${lines[1]}" =~ "add 10" ]] || false
+    [[ "${lines[2]}" =~ "main: add 1" ]] || false
+
+    run dolt sql -q "SELECT pk FROM t ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "10" ]
+    [ "${lines[3]}" = "11" ]
+    [ "${#lines[@]}" -eq 4 ]
+}
+
+@test "sql-rebase: interactive dolt_rebase follows the rebase plan" {
+    dolt sql <<SQL
+CALL dolt_rebase('-i', 'main');
+UPDATE dolt_rebase_plan SET action = 'drop' WHERE commit_message = 'add 10';
+UPDATE dolt_rebase_plan SET action = 'reword', commit_message = 'add eleven' WHERE commit_message = 'add 11';
+CALL dolt_rebase('--continue');
+SQL
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "dolt_rebase_feature" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "add eleven" ]] || false
+    [[ "${lines[1]}" =~ "main: add 1" ]] || false
+
+    run dolt sql -q "SELECT pk FROM t ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "11" ]
+    [ "${#lines[@]}" -eq 3 ]
+}
+
+@test "sql-rebase: dolt_rebase --abort leaves the branch unchanged" {
+    run dolt sql -r csv <<SQL
+CALL dolt_rebase('-i', 'main');
+SELECT action, commit_message FROM dolt_rebase_plan ORDER BY rebase_order;
+CALL dolt_rebase('--abort');
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "pick,add 10" ]] || false
+    [[ "$output" =~ "pick,add 11" ]] || false
+    [[ "$output" =~ "interactive rebase aborted" ]] || false
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "dolt_rebase_feature" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "add 11" ]] || false
+    [[ "${lines[1]}" =~ "add 10" ]] || false
+    [[ "${lines[2]}" =~ "create table" ]] || false
+
+    run dolt sql -q "CALL dolt_rebase('--continue')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no interactive rebase is in progress" ]] || false
+}
@@ -557,3 +557,65 @@ get_head_commit() {
     dolt log -n 1 | grep -m 1 commit | cut -c 13-44
 }
 
+
+@test "sql-reset: CALL DOLT_RESET --hard to an ancestor can be undone with the reflog" {
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "Add 1"
+    dolt tag v1
+    dolt sql -q "INSERT INTO test VALUES (2)"
+    dolt commit -am "Add 2"
+    prev=$(get_head_commit)
+
+    run dolt sql -q "CALL DOLT_RESET('--hard', 'HEAD~2')"
+    [ $status -eq 0 ]
+    run dolt log -n 1
+    [[ "$output" =~ "Add a table" ]] || false
+
+    run dolt reflog main
+    [ $status -eq 0 ]
+    [[ "$output" =~ "$prev" ]] || false
+
+    dolt sql -q "CALL DOLT_RESET('--hard', '$prev')"
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+
+    dolt sql -q "CALL DOLT_RESET('--hard', 'v1')"
+    run dolt log -n 1
+    [[ "$output" =~ "Add 1" ]] || false
+}
+
+@test "sql-reset: CALL DOLT_RESET to a commit keeps the working set" {
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "Add 1"
+
+    run dolt sql -q "CALL DOLT_RESET('HEAD~1')"
+    [ $status -eq 0 ]
+    run dolt log -n 1
+    [[ "$output" =~ "Add a table" ]] || false
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [ "${lines[1]}" = "1" ]
+    run dolt status
+    [[ "$output" =~ "modified:" ]] || false
+}
+
+@test "sql-reset: CALL DOLT_RESET --hard records the head of a clone in the reflog" {
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "Add 1"
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    mkdir clones && cd clones
+    dolt clone file://../remotedir repo
+    cd repo
+    prev=$(get_head_commit)
+
+    dolt sql -q "CALL DOLT_RESET('--hard', 'HEAD~1')"
+    run dolt reflog main
+    [ $status -eq 0 ]
+    [[ "$output" =~ "$prev" ]] || false
+    [[ "$output" =~ "Add 1" ]] || false
+}
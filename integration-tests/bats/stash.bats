@@ -0,0 +1,94 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test(pk BIGINT PRIMARY KEY, v1 BIGINT)"
+    dolt sql -q "INSERT INTO test VALUES (1, 1)"
+    dolt add -A
+    dolt commit -m "Created table"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "stash: push and pop changes" {
+    dolt sql -q "INSERT INTO test VALUES (2, 2)"
+    run dolt stash
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "Saved working directory and index state WIP on main:" ]] || false
+
+    run dolt status
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt stash list
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "stash@{0}: WIP on main:" ]] || false
+    [[ "$output" =~ "Created table" ]] || false
+
+    run dolt stash pop
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "Dropped stash@{0}" ]] || false
+
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "2,2" ]] || false
+
+    run dolt stash list
+    [ "$status" -eq "0" ]
+    [ "$output" = "" ]
+}
+
+@test "stash: no local changes" {
+    run dolt stash
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "No local changes to save" ]] || false
+
+    run dolt stash pop
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "no stash entries found" ]] || false
+}
+
+@test "stash: apply a stash on another branch" {
+    dolt branch other
+    dolt sql -q "INSERT INTO test VALUES (2, 2)"
+    dolt stash -m "parked changes"
+    dolt checkout other
+    dolt sql -q "INSERT INTO test VALUES (3, 3)"
+    dolt commit -am "Inserted 3"
+
+    run dolt stash apply stash@{0}
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "2,2" ]] || false
+    [[ "$output" =~ "3,3" ]] || false
+
+    run dolt sql -q "SELECT stash_id, branch, message FROM dolt_stashes" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "stash@{0},main,On main: parked changes" ]] || false
+
+    dolt stash drop
+    run dolt stash list
+    [ "$status" -eq "0" ]
+    [ "$output" = "" ]
+}
+
+@test "stash: sql procedure and cli share stashes" {
+    dolt sql -q "INSERT INTO test VALUES (2, 2)"
+    dolt sql -q "CALL dolt_stash('push', '-m', 'from sql')"
+
+    run dolt stash list
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "stash@{0}: On main: from sql" ]] || false
+
+    dolt stash pop
+    run dolt sql -q "SELECT * FROM test" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "2,2" ]] || false
+}
@@ -0,0 +1,56 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test(pk BIGINT PRIMARY KEY, c BIGINT)"
+    dolt sql -q "INSERT INTO test VALUES (1, 1), (2, 2)"
+    dolt add -A
+    dolt commit -m "Created table"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "statistics: analyze table persists statistics" {
+    run dolt sql -q "ANALYZE TABLE test"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "OK" ]] || false
+
+    run dolt sql -q "SELECT column_name, row_count, max FROM dolt_statistics ORDER BY column_name" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "c,2,2" ]] || false
+    [[ "$output" =~ "pk,2,2" ]] || false
+
+    run dolt status
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "dolt_statistics" ]] || false
+
+    run dolt sql -q "SELECT table_name, column_name, count FROM information_schema.column_statistics" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "test,c,2" ]] || false
+}
+
+@test "statistics: statistics are refreshed on commit" {
+    dolt sql -q "ANALYZE TABLE test"
+    dolt add -A
+    dolt commit -m "Analyzed table"
+
+    dolt sql -q "INSERT INTO test VALUES (3, 3)"
+    dolt commit -am "Inserted row"
+
+    run dolt status
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt sql -q "SELECT row_count, max FROM dolt_statistics WHERE column_name = 'c'" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "3,3" ]] || false
+
+    run dolt sql -q "SELECT row_count FROM dolt_statistics AS OF 'HEAD~1' WHERE column_name = 'c'" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "2" ]] || false
+}
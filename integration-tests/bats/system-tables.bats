@@ -548,18 +548,26 @@ SQL
     [[ "$output" =~ "1" ]] || false
 }
 
-@test "system-tables: dolt_branches is read-only" {
-    run dolt sql -q "DELETE FROM dolt_branches"
-    [ "$status" -ne 0 ]
-    [[ "$output" =~ "read-only" ]] || false
+@test "system-tables: dolt_branches is writable" {
+    dolt sql -q "INSERT INTO dolt_branches (name,hash) VALUES ('branch1', 'main');"
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "branch1" ]] || false
 
-    run dolt sql -q "INSERT INTO dolt_branches (name,hash) VALUES ('branch1', 'main');"
-    [ "$status" -ne 0 ]
-    [[ "$output" =~ "read-only" ]] || false
+    dolt sql -q "UPDATE dolt_branches SET name = 'branch2' WHERE name = 'branch1'"
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "branch2" ]] || false
+    [[ ! "$output" =~ "branch1" ]] || false
 
-    run dolt sql -q "UPDATE dolt_branches SET name = 'branch1' WHERE name = 'main'"
+    dolt sql -q "DELETE FROM dolt_branches WHERE name = 'branch2'"
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "branch2" ]] || false
+
+    # The checked out branch may not be deleted
+    run dolt sql -q "DELETE FROM dolt_branches WHERE name = 'main'"
     [ "$status" -ne 0 ]
-    [[ "$output" =~ "read-only" ]] || false
 }
 
 @test "system-tables: dolt diff includes changes from initial commit" {
@@ -596,3 +604,30 @@ SQL
     [[ "$output" =~ "tag v2 from branch1" ]] || false
     [[ "$output" =~ "tag v3 from branch1" ]] || false
 }
+
+@test "system-tables: stage individual rows with dolt_workspace tables" {
+    dolt sql -q "CREATE TABLE test(pk int primary key, val int)"
+    dolt sql -q "INSERT INTO test VALUES (1,1), (2,2)"
+    dolt add .
+    dolt commit -m "cm1"
+
+    dolt sql -q "UPDATE test SET val = 10 WHERE pk = 1"
+    dolt sql -q "INSERT INTO test VALUES (3,3)"
+
+    run dolt sql -q "SELECT staged, diff_type, to_pk FROM dolt_workspace_test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "false,modified,1" ]] || false
+    [[ "$output" =~ "false,added,3" ]] || false
+
+    dolt sql -q "UPDATE dolt_workspace_test SET staged = true WHERE to_pk = 3"
+    dolt sql -q "DELETE FROM dolt_workspace_test WHERE to_pk = 1"
+
+    run dolt sql -q "SELECT staged, diff_type, to_pk FROM dolt_workspace_test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "true,added,3" ]] || false
+    [[ ! "$output" =~ "modified" ]] || false
+
+    run dolt sql -q "SELECT * FROM test WHERE pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+}